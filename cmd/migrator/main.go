@@ -2,8 +2,10 @@ package main
 
 import (
 	"errors"
-	"flag"
 	"fmt"
+	"os"
+
+	"grpc-service-ref/internal/cli"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
@@ -13,10 +15,25 @@ import (
 func main() {
 	var storagePath, migrationsPath, migrationsTable string
 
-	flag.StringVar(&storagePath, "storage-path", "", "path to storage")
-	flag.StringVar(&migrationsPath, "migrations-path", "", "path to migrations")
-	flag.StringVar(&migrationsTable, "migrations-table", "migrations", "name of migrations table")
-	flag.Parse()
+	flags := cli.New("migrator")
+	flags.FlagSet().StringVar(&storagePath, "storage-path", "", "path to storage, defaults to storage_path of config")
+	flags.FlagSet().StringVar(&migrationsPath, "migrations-path", "", "path to migrations, defaults to migrations_path of config")
+	flags.FlagSet().StringVar(&migrationsTable, "migrations-table", "migrations", "name of migrations table")
+	flags.Parse(os.Args[1:])
+
+	if flags.ConfigPath() != "" {
+		cfg, err := flags.LoadConfig()
+		if err != nil {
+			panic(err)
+		}
+
+		if storagePath == "" {
+			storagePath = cfg.StoragePath
+		}
+		if migrationsPath == "" {
+			migrationsPath = cfg.MigrationsPath
+		}
+	}
 
 	if storagePath == "" {
 		panic("storage-path is required")
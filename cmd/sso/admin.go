@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"grpc-service-ref/internal/cli"
+	"grpc-service-ref/internal/config"
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/fieldcrypt"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/secrets"
+	"grpc-service-ref/internal/services/audit"
+	"grpc-service-ref/internal/storage/sqlite"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const adminUsage = `Usage: sso admin <command> [flags]
+
+Commands:
+  create-user  create verified user, e.g. create-user --email admin@example.com --admin
+  create-app   create app, e.g. create-app --name web
+
+Run "sso admin <command> -help" for flags of the command.`
+
+// runAdmin runs "sso admin" commands, which bootstrap a fresh deployment working with storage directly.
+func runAdmin(args []string) error {
+	if len(args) == 0 {
+		return errors.New(adminUsage)
+	}
+
+	switch args[0] {
+	case "create-user":
+		return createUser(args[1:])
+	case "create-app":
+		return createApp(args[1:])
+	default:
+		return fmt.Errorf("unknown admin command %q\n\n%s", args[0], adminUsage)
+	}
+}
+
+func createUser(args []string) error {
+	var (
+		email    string
+		password string
+		isAdmin  bool
+	)
+
+	flags := cli.New("sso admin create-user")
+	flags.FlagSet().StringVar(&email, "email", "", "email of the user, required")
+	flags.FlagSet().StringVar(&password, "password", "", "password of the user, generated and printed if empty")
+	flags.FlagSet().BoolVar(&isAdmin, "admin", false, "grant admin role")
+	flags.Parse(args)
+
+	if email == "" {
+		return errors.New("--email is required")
+	}
+
+	generated := password == ""
+	if generated {
+		var err error
+		if password, err = random.Secret(random.Crypto); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+
+	cfg, err := flags.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	storage, auditLog, err := openAdminStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storage.Stop()
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(password), cfg.Password.Cost)
+	if err != nil {
+		return err
+	}
+
+	id, err := saveVerifiedUser(ctx, storage, auditLog, email, passHash, isAdmin)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user created: id=%d email=%s admin=%t\n", id, email, isAdmin)
+	if generated {
+		fmt.Printf("password: %s\n", password)
+	}
+
+	return nil
+}
+
+func createApp(args []string) error {
+	var name, secret string
+
+	flags := cli.New("sso admin create-app")
+	flags.FlagSet().StringVar(&name, "name", "", "name of the app, required")
+	flags.FlagSet().StringVar(&secret, "secret", "", "secret of the app, generated if empty")
+	flags.Parse(args)
+
+	if name == "" {
+		return errors.New("--name is required")
+	}
+
+	if secret == "" {
+		var err error
+		if secret, err = random.Secret(random.Crypto); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+
+	cfg, err := flags.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	storage, auditLog, err := openAdminStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storage.Stop()
+
+	id, err := storage.SaveApp(ctx, name, secret)
+	if err != nil {
+		return err
+	}
+
+	auditLog.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionAppChanged,
+		Subject: name,
+		AppID:   id,
+		Payload: map[string]string{"source": "cli", "change": "created"},
+	})
+
+	fmt.Printf("app created: id=%d name=%s\nsecret: %s\n", id, name, secret)
+
+	return nil
+}
+
+// saveVerifiedUser saves user created by a command, granting admin role if isAdmin.
+func saveVerifiedUser(
+	ctx context.Context,
+	storage *sqlite.Storage,
+	auditLog *audit.Audit,
+	email string,
+	passHash []byte,
+	isAdmin bool,
+) (int64, error) {
+	id, err := storage.SaveUser(ctx, email, passHash)
+	if err != nil {
+		return 0, err
+	}
+
+	// Bootstrapped users can't receive verification email before the deployment is set up.
+	if _, err := storage.VerifyUser(ctx, email); err != nil {
+		return 0, err
+	}
+
+	auditLog.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionRegistered,
+		ActorID: id,
+		Subject: email,
+		Payload: map[string]string{"source": "cli"},
+	})
+
+	if isAdmin {
+		if err := storage.SetAdmin(ctx, id, true); err != nil {
+			return 0, err
+		}
+
+		auditLog.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionRoleChanged,
+			Subject: email,
+			Payload: map[string]string{"source": "cli", "role": "admin"},
+		})
+	}
+
+	return id, nil
+}
+
+// openAdminStorage opens storage of the config, resolving storage_path if it references a secret.
+// The config is not validated, since admin commands need storage only.
+func openAdminStorage(ctx context.Context, cfg *config.Config) (*sqlite.Storage, *audit.Audit, error) {
+	// Output of commands goes to stdout, so only problems are logged.
+	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	resolver := mustSetupSecrets(ctx, cfg.Secrets, mustSetupVault(ctx, log, cfg.Vault))
+	if err := secrets.ResolveConfig(ctx, cfg, resolver); err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.StoragePath == "" {
+		return nil, nil, errors.New("storage_path is required")
+	}
+
+	// Users created by commands must be readable by the server, so they are encrypted the same way.
+	var cipher sqlite.FieldCipher
+	if cfg.Encryption.Enabled {
+		var err error
+		if cipher, err = fieldcrypt.New(cfg.Encryption.Key); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	storage, err := sqlite.New(cfg.StoragePath, cipher)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return storage, audit.New(log, storage, storage), nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// loadtestOptions are flags of "sso loadtest".
+type loadtestOptions struct {
+	addr          string
+	appID         int
+	rps           int
+	duration      time.Duration
+	timeout       time.Duration
+	maxInFlight   int
+	registerRatio float64
+	email         string
+	password      string
+}
+
+// runLoadtest runs "sso loadtest": drives Login and Register of a running instance with target RPS
+// and reports latency percentiles by RPC.
+func runLoadtest(args []string) error {
+	var opts loadtestOptions
+
+	fs := flag.NewFlagSet("sso loadtest", flag.ExitOnError)
+	fs.StringVar(&opts.addr, "addr", "localhost:44044", "gRPC address of the instance")
+	fs.IntVar(&opts.appID, "app-id", 1, "app to log in to")
+	fs.IntVar(&opts.rps, "rps", 50, "target requests per second")
+	fs.DurationVar(&opts.duration, "duration", 30*time.Second, "duration of the test")
+	fs.DurationVar(&opts.timeout, "timeout", 5*time.Second, "timeout of a request")
+	fs.IntVar(&opts.maxInFlight, "max-in-flight", 500, "requests over this number in flight are dropped and counted")
+	fs.Float64Var(&opts.registerRatio, "register-ratio", 0.1, "share of requests which are Register, others are Login")
+	fs.StringVar(&opts.email, "email", "", "email of the user to log in, a new user is registered if empty")
+	fs.StringVar(&opts.password, "password", "", "password of the user to log in")
+	// ExitOnError flag set never returns an error.
+	_ = fs.Parse(args)
+
+	if opts.rps < 1 || opts.duration <= 0 || opts.maxInFlight < 1 {
+		return errors.New("--rps, --duration and --max-in-flight must be positive")
+	}
+	if opts.registerRatio < 0 || opts.registerRatio > 1 {
+		return errors.New("--register-ratio must be between 0 and 1")
+	}
+
+	cc, err := grpc.Dial(opts.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	client := ssov1.NewAuthClient(cc)
+
+	if opts.email == "" {
+		if opts.email, opts.password, err = registerLoadtestUser(client, opts.timeout); err != nil {
+			return fmt.Errorf("failed to register user to log in: %w", err)
+		}
+	}
+
+	fmt.Printf("running %d rps for %s against %s\n", opts.rps, opts.duration, opts.addr)
+
+	results := newLoadtestResults()
+	started := time.Now()
+	runLoad(client, opts, results)
+
+	results.Print(os.Stdout, time.Since(started))
+
+	return nil
+}
+
+// runLoad sends requests at the target rate until duration passes and waits for in-flight ones.
+func runLoad(client ssov1.AuthClient, opts loadtestOptions, results *loadtestResults) {
+	ticker := time.NewTicker(time.Second / time.Duration(opts.rps))
+	defer ticker.Stop()
+
+	deadline := time.After(opts.duration)
+	inFlight := make(chan struct{}, opts.maxInFlight)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-deadline:
+			return
+		case <-ticker.C:
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			results.Dropped()
+
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+			defer cancel()
+
+			if mathrand.Float64() < opts.registerRatio {
+				start := time.Now()
+				_, err := client.Register(ctx, &ssov1.RegisterRequest{Email: randomLoadtestEmail(), Password: randomLoadtestPassword()})
+				results.Record("Register", time.Since(start), err)
+
+				return
+			}
+
+			start := time.Now()
+			_, err := client.Login(ctx, &ssov1.LoginRequest{Email: opts.email, Password: opts.password, AppId: int32(opts.appID)})
+			results.Record("Login", time.Since(start), err)
+		}()
+	}
+}
+
+func registerLoadtestUser(client ssov1.AuthClient, timeout time.Duration) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	email, password := randomLoadtestEmail(), randomLoadtestPassword()
+	if _, err := client.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password}); err != nil {
+		return "", "", err
+	}
+
+	return email, password, nil
+}
+
+func randomLoadtestEmail() string {
+	return "loadtest-" + randomHex(8) + "@example.com"
+}
+
+func randomLoadtestPassword() string {
+	return randomHex(12)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	// crypto/rand doesn't fail on supported platforms.
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// loadtestResults collects latencies and errors by RPC.
+type loadtestResults struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]map[codes.Code]int
+	dropped   int
+}
+
+func newLoadtestResults() *loadtestResults {
+	return &loadtestResults{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]map[codes.Code]int),
+	}
+}
+
+func (r *loadtestResults) Record(rpc string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.latencies[rpc] = append(r.latencies[rpc], latency)
+
+	if err != nil {
+		if r.errors[rpc] == nil {
+			r.errors[rpc] = make(map[codes.Code]int)
+		}
+		r.errors[rpc][status.Code(err)]++
+	}
+}
+
+func (r *loadtestResults) Dropped() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dropped++
+}
+
+func (r *loadtestResults) Print(out io.Writer, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rpcs := make([]string, 0, len(r.latencies))
+	for rpc := range r.latencies {
+		rpcs = append(rpcs, rpc)
+	}
+	sort.Strings(rpcs)
+
+	fmt.Fprintf(out, "%-10s %8s %8s %10s %10s %10s %10s %10s\n", "rpc", "requests", "rps", "p50", "p90", "p99", "max", "errors")
+
+	for _, rpc := range rpcs {
+		latencies := r.latencies[rpc]
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		errCount := 0
+		for _, n := range r.errors[rpc] {
+			errCount += n
+		}
+
+		fmt.Fprintf(out, "%-10s %8d %8.1f %10s %10s %10s %10s %10d\n",
+			rpc,
+			len(latencies),
+			float64(len(latencies))/elapsed.Seconds(),
+			percentile(latencies, 50),
+			percentile(latencies, 90),
+			percentile(latencies, 99),
+			latencies[len(latencies)-1],
+			errCount,
+		)
+
+		for code, n := range r.errors[rpc] {
+			fmt.Fprintf(out, "  %s: %d\n", code, n)
+		}
+	}
+
+	if r.dropped > 0 {
+		fmt.Fprintf(out, "dropped: %d requests over --max-in-flight, the instance can't keep up with target rps\n", r.dropped)
+	}
+}
+
+// percentile returns p-th percentile of sorted latencies by nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1].Round(time.Microsecond)
+}
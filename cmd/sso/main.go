@@ -1,71 +1,311 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"grpc-service-ref/internal/app"
+	"grpc-service-ref/internal/cli"
 	"grpc-service-ref/internal/config"
+	"grpc-service-ref/internal/domain/models"
 	"grpc-service-ref/internal/lib/logger/handlers/slogpretty"
+	"grpc-service-ref/internal/lib/logger/handlers/slogredact"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/lib/version"
+	"grpc-service-ref/internal/secrets"
 )
 
 const (
 	envLocal = "local"
-	envDev   = "dev"
 	envProd  = "prod"
 )
 
 func main() {
-	cfg := config.MustLoad()
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		if err := runAdmin(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 
-	log := setupLogger(cfg.Env)
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := runSeed(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "loadtest" {
+		if err := runLoadtest(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	flags := cli.New("sso")
+	flags.Parse(os.Args[1:])
+
+	cfg := flags.MustLoadConfig()
+
+	logLevel := new(slog.LevelVar)
+	if err := setLogLevel(logLevel, cfg); err != nil {
+		panic(err)
+	}
+
+	log := setupLogger(cfg, logLevel)
+
+	build := version.Get()
+	log.Info("starting sso",
+		slog.String("version", build.Version),
+		slog.String("commit", build.Commit),
+		slog.String("build_date", build.BuildDate),
+		slog.String("go_version", build.GoVersion),
+		slog.String("env", cfg.Env),
+	)
+
+	// ctx is done on SIGTERM or SIGINT, background workers and the app stop then.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	vault := mustSetupVault(ctx, log, cfg.Vault)
+	secretResolver := mustSetupSecrets(ctx, cfg.Secrets, vault)
+	if err := secrets.ResolveConfig(ctx, cfg, secretResolver); err != nil {
+		panic(err)
+	}
+
+	var appSecrets map[string]string
+	if vault != nil && cfg.Vault.AppSecretsPath != "" {
+		var err error
+		appSecrets, err = vault.Secrets(ctx, cfg.Vault.AppSecretsPath)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	application := app.New(log, logLevel, cfg, verificationCodeFormats(cfg.Verification), appSecrets)
+
+	if vault != nil {
+		go vault.Run(ctx)
+	}
 
-	application := app.New(log, cfg.GRPC.Port, cfg.StoragePath, cfg.TokenTTL, cfg.EmailService.Name, cfg.EmailService.Email, cfg.EmailService.Password, cfg.Verification.Len, cfg.Verification.LastHours)
+	// Config reload
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
 
 	go func() {
-		application.GRPCServer.MustRun()
+		current := cfg
+		for range reload {
+			current = reloadConfig(ctx, log, flags, current, application, logLevel, secretResolver)
+		}
 	}()
 
-	// Graceful shutdown
+	// SIGUSR1 toggles debug logging, e.g. to investigate an incident without restart.
+
+	toggleDebug := make(chan os.Signal, 1)
+	signal.Notify(toggleDebug, syscall.SIGUSR1)
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		previous := logLevel.Level()
+		for range toggleDebug {
+			if logLevel.Level() != slog.LevelDebug {
+				previous = logLevel.Level()
+				logLevel.Set(slog.LevelDebug)
+			} else {
+				logLevel.Set(previous)
+			}
+
+			log.Warn("log level changed by SIGUSR1", slog.String("level", logLevel.Level().String()))
+		}
+	}()
 
-	<-stop
+	application.Run(ctx)
 
-	application.GRPCServer.Stop()
 	log.Info("Gracefully stopped")
 }
 
-func setupLogger(env string) *slog.Logger {
-	var log *slog.Logger
+func verificationCodeFormats(cfg config.VerificationConfig) verification.CodeFormats {
+	formats := verification.CodeFormats{
+		Default: verification.CodeFormat{Len: cfg.Len, Charset: cfg.Charset, TTL: cfg.TTL},
+		ByType:  make(map[models.VerificationType]verification.CodeFormat, len(cfg.Types)),
+	}
+
+	for vType, c := range cfg.Types {
+		format := formats.Default
+		if c.Len != 0 {
+			format.Len = c.Len
+		}
+		if c.Charset != "" {
+			format.Charset = c.Charset
+		}
+		if c.TTL != 0 {
+			format.TTL = c.TTL
+		}
+
+		formats.ByType[models.VerificationType(vType)] = format
+	}
+
+	return formats
+}
+
+// reloadConfig applies reloadable values of the config file to the running app
+// and returns config in effect. Boot-only changes are only reported.
+func reloadConfig(
+	ctx context.Context,
+	log *slog.Logger,
+	flags *cli.CLI,
+	current *config.Config,
+	application *app.App,
+	logLevel *slog.LevelVar,
+	secretResolver *secrets.Resolver,
+) *config.Config {
+	const op = "main.reloadConfig"
+
+	log = log.With(slog.String("op", op))
+
+	loaded, err := flags.LoadConfig()
+	if err != nil {
+		log.Error("failed to reload config, keeping current one", sl.Err(err))
+
+		return current
+	}
+
+	if err := secrets.ResolveConfig(ctx, loaded, secretResolver); err != nil {
+		log.Error("failed to reload config, keeping current one", sl.Err(err))
+
+		return current
+	}
+
+	if err := loaded.Validate(); err != nil {
+		log.Error("reloaded config is invalid, keeping current one", sl.Err(err))
+
+		return current
+	}
+
+	cfg := current.WithReloadable(loaded)
+
+	if err := setLogLevel(logLevel, cfg); err != nil {
+		log.Error("failed to reload config, keeping current one", sl.Err(err))
+
+		return current
+	}
+
+	application.Reload(verificationCodeFormats(cfg.Verification), cfg.RateLimit, cfg.IPFilter, cfg.Maintenance)
+
+	if changed := config.BootOnlyChanges(current, loaded); len(changed) > 0 {
+		log.Warn("config changes require restart", slog.Any("fields", changed))
+	}
+
+	log.Info("config reloaded")
+
+	return cfg
+}
+
+// mustSetupVault logs in to Vault, returns nil if Vault is not configured.
+func mustSetupVault(ctx context.Context, log *slog.Logger, cfg config.VaultConfig) *secrets.Vault {
+	if cfg.Address == "" {
+		return nil
+	}
+
+	vault, err := secrets.New(ctx, log, cfg.Address, cfg.Mount, cfg.AuthMethod, cfg.Token, cfg.RoleID, cfg.SecretID, cfg.Timeout)
+	if err != nil {
+		panic(err)
+	}
+
+	return vault
+}
+
+// mustSetupSecrets registers secret managers config may reference, vault may be nil.
+func mustSetupSecrets(ctx context.Context, cfg config.SecretsConfig, vault *secrets.Vault) *secrets.Resolver {
+	resolver := secrets.NewResolver()
+
+	if vault != nil {
+		resolver.Register(secrets.SchemeVault, vault)
+	}
 
-	switch env {
-	case envLocal:
-		log = setupPrettySlog()
-	case envDev:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
-	case envProd:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
+	aws, err := secrets.NewAWSSecretsManager(ctx, cfg.AWSRegion)
+	if err != nil {
+		panic(err)
 	}
+	resolver.Register(secrets.SchemeAWS, aws)
+	resolver.Register(secrets.SchemeGCP, secrets.NewGCPSecretManager(cfg.Timeout))
 
-	return log
+	return resolver
 }
 
-func setupPrettySlog() *slog.Logger {
+// setLogLevel sets log level from config, falling back to the default level of the env.
+func setLogLevel(level *slog.LevelVar, cfg *config.Config) error {
+	if cfg.LogLevel == "" {
+		if cfg.Env == envProd {
+			level.Set(slog.LevelInfo)
+		} else {
+			level.Set(slog.LevelDebug)
+		}
+
+		return nil
+	}
+
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", cfg.LogLevel, err)
+	}
+	level.Set(l)
+
+	return nil
+}
+
+// setupLogger returns logger of the configured format, by default pretty for local env and JSON otherwise.
+func setupLogger(cfg *config.Config, level slog.Leveler) *slog.Logger {
+	format := cfg.LogFormat
+	if format == "" {
+		format = config.LogFormatJSON
+		if cfg.Env == envLocal {
+			format = config.LogFormatPretty
+		}
+	}
+
+	var handler slog.Handler
+	switch format {
+	case config.LogFormatPretty:
+		handler = setupPrettySlog(level)
+	case config.LogFormatText:
+		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	default:
+		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	}
+
+	pii := cfg.LogPII
+	if pii == "" {
+		pii = config.LogPIIPlain
+		if cfg.Env == envProd {
+			pii = config.LogPIIHash
+		}
+	}
+
+	if pii != config.LogPIIPlain {
+		handler = slogredact.New(handler, slogredact.Mode(pii))
+	}
+
+	return slog.New(handler)
+}
+
+func setupPrettySlog(level slog.Leveler) slog.Handler {
 	opts := slogpretty.PrettyHandlerOptions{
 		SlogOpts: &slog.HandlerOptions{
-			Level: slog.LevelDebug,
+			Level: level,
 		},
 	}
 
-	handler := opts.NewPrettyHandler(os.Stdout)
-
-	return slog.New(handler)
+	return opts.NewPrettyHandler(os.Stdout)
 }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"grpc-service-ref/internal/cli"
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/storage"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Seeded data is fixed, so frontends can be configured once and the command can be rerun.
+const (
+	seedAppName       = "demo"
+	seedAdminEmail    = "admin@example.com"
+	seedUnverified    = "unverified@example.com"
+	seedUserEmailForm = "user%d@example.com"
+)
+
+// runSeed runs "sso seed": fills storage of a local deployment with demo data.
+// Existing users and app are kept, so it's safe to run again, e.g. after adding users.
+func runSeed(args []string) error {
+	var (
+		users     int
+		password  string
+		appSecret string
+	)
+
+	flags := cli.New("sso seed")
+	flags.FlagSet().IntVar(&users, "users", 5, "number of verified test users")
+	flags.FlagSet().StringVar(&password, "password", "password", "password of all seeded users")
+	flags.FlagSet().StringVar(&appSecret, "app-secret", "demo-secret", "secret of the demo app")
+	flags.Parse(args)
+
+	if users < 0 {
+		return errors.New("--users must not be negative")
+	}
+
+	ctx := context.Background()
+
+	cfg, err := flags.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Env == envProd {
+		return errors.New("seed creates users with known passwords, it's not allowed in prod env")
+	}
+
+	store, auditLog, err := openAdminStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Stop()
+
+	id, err := store.SaveApp(ctx, seedAppName, appSecret)
+	switch {
+	case errors.Is(err, storage.ErrAppExists):
+		fmt.Printf("app exists: name=%s\n", seedAppName)
+	case err != nil:
+		return err
+	default:
+		auditLog.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionAppChanged,
+			Subject: seedAppName,
+			AppID:   id,
+			Payload: map[string]string{"source": "cli", "change": "created"},
+		})
+		fmt.Printf("app created: id=%d name=%s secret=%s\n", id, seedAppName, appSecret)
+	}
+
+	// Seeded passwords are known anyway, the minimal cost keeps seeding fast.
+	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		return err
+	}
+
+	seed := func(email string, verified bool, isAdmin bool) error {
+		var id int64
+		var err error
+		if verified {
+			id, err = saveVerifiedUser(ctx, store, auditLog, email, passHash, isAdmin)
+		} else {
+			id, err = store.SaveUser(ctx, email, passHash)
+		}
+
+		switch {
+		case errors.Is(err, storage.ErrUserExists):
+			fmt.Printf("user exists: email=%s\n", email)
+		case err != nil:
+			return fmt.Errorf("failed to seed %s: %w", email, err)
+		default:
+			fmt.Printf("user created: id=%d email=%s verified=%t admin=%t\n", id, email, verified, isAdmin)
+		}
+
+		return nil
+	}
+
+	if err := seed(seedAdminEmail, true, true); err != nil {
+		return err
+	}
+
+	for i := 1; i <= users; i++ {
+		if err := seed(fmt.Sprintf(seedUserEmailForm, i), true, false); err != nil {
+			return err
+		}
+	}
+
+	// Unverified user lets the verification flow be tried without registering.
+	if err := seed(seedUnverified, false, false); err != nil {
+		return err
+	}
+
+	fmt.Printf("password of all users: %s\n", password)
+
+	return nil
+}
@@ -1,42 +1,765 @@
 package app
 
 import (
+	"context"
+	"crypto/tls"
 	"log/slog"
+	"net/http"
+	"os"
+	"sync"
 	"time"
 
 	grpcapp "grpc-service-ref/internal/app/grpc"
+	httpapp "grpc-service-ref/internal/app/http"
+	schedulerapp "grpc-service-ref/internal/app/scheduler"
+	"grpc-service-ref/internal/config"
+	"grpc-service-ref/internal/events"
+	admingrpc "grpc-service-ref/internal/grpc/admin"
+	authgrpc "grpc-service-ref/internal/grpc/auth"
+	bounceshttp "grpc-service-ref/internal/http/bounces"
+	forwardauthhttp "grpc-service-ref/internal/http/forwardauth"
+	headershttp "grpc-service-ref/internal/http/headers"
+	jwkshttp "grpc-service-ref/internal/http/jwks"
+	opshttp "grpc-service-ref/internal/http/ops"
+	sessionhttp "grpc-service-ref/internal/http/session"
+	tokenreviewhttp "grpc-service-ref/internal/http/tokenreview"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/dkim"
+	"grpc-service-ref/internal/lib/fieldcrypt"
+	"grpc-service-ref/internal/lib/geoip"
+	"grpc-service-ref/internal/lib/ipfilter"
+	"grpc-service-ref/internal/lib/joblock"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+	"grpc-service-ref/internal/lib/passhash"
+	"grpc-service-ref/internal/lib/passstrength"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/lib/ratelimit"
+	"grpc-service-ref/internal/lib/tlsconfig"
+	"grpc-service-ref/internal/lib/tracing"
+	verificationlib "grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/secrets"
+	"grpc-service-ref/internal/services/admin"
+	"grpc-service-ref/internal/services/audit"
 	"grpc-service-ref/internal/services/auth"
+	"grpc-service-ref/internal/services/captcha"
+	"grpc-service-ref/internal/services/cleanup"
+	"grpc-service-ref/internal/services/deletion"
+	"grpc-service-ref/internal/services/devicelogin"
+	"grpc-service-ref/internal/services/directory"
+	"grpc-service-ref/internal/services/directory/httpdirectory"
+	"grpc-service-ref/internal/services/mail"
+	"grpc-service-ref/internal/services/mail/console"
 	"grpc-service-ref/internal/services/mail/gmail"
+	"grpc-service-ref/internal/services/maintenance"
+	"grpc-service-ref/internal/services/notification"
+	"grpc-service-ref/internal/services/organization"
+	"grpc-service-ref/internal/services/reminder"
+	"grpc-service-ref/internal/services/risk"
+	"grpc-service-ref/internal/services/serviceaccount"
+	"grpc-service-ref/internal/services/signin"
+	smsconsole "grpc-service-ref/internal/services/sms/console"
+	"grpc-service-ref/internal/services/sms/twilio"
+	"grpc-service-ref/internal/services/stats"
 	"grpc-service-ref/internal/services/verification"
+	"grpc-service-ref/internal/services/webhook"
+	"grpc-service-ref/internal/storage/cache"
 	"grpc-service-ref/internal/storage/sqlite"
+
+	"github.com/redis/go-redis/v9"
 )
 
+const envLocal = "local"
+
 type App struct {
+	log        *slog.Logger
 	GRPCServer *grpcapp.App
+	// AdminServer serves AdminService on a separate port, it's nil if disabled.
+	AdminServer *grpcapp.App
+	HTTPServer  *httpapp.App
+	// OpsServer serves probes, it's stopped after other servers are drained.
+	OpsServer *httpapp.App
+	Scheduler *schedulerapp.App
+	probes    *opshttp.Probes
+	storage   *sqlite.Storage
+	webhooks  *webhook.Dispatcher
+	// mailPool sends emails of all services, it's stopped once requests are drained.
+	mailPool *mail.Pool
+	// auditBuffer saves audit events in background, it's nil if they are saved synchronously.
+	auditBuffer *audit.Buffer
+	// redis keeps rate limits and publishes cache invalidations, it's nil if neither uses Redis.
+	redis redis.UniversalClient
+	// invalidations receives cache invalidations of other replicas, it's nil if caches are invalidated locally.
+	invalidations *cache.RedisInvalidations
+	// workers are background jobs using storage, they are waited for before storage is closed.
+	workers sync.WaitGroup
+	// shutdownTimeout is how long in-flight requests are drained on shutdown.
+	shutdownTimeout time.Duration
+	// stopTracing flushes pending spans, it's nil if tracing is disabled.
+	stopTracing func(context.Context) error
+
+	// reloadable parts, see Reload.
+	verificationCodes    *verificationlib.ReloadableCodeFormats
+	verificationPerEmail *ratelimit.Limiter
+	verificationPerIP    *ratelimit.Limiter
+	verificationPerPhone *ratelimit.Limiter
+	ipFilter             *ipfilter.Filter
+	maintenance          *maintenance.Maintenance
 }
 
+// New wires services of the config and creates servers of the app, they are started by Run.
+// verificationCodes are code formats of cfg.Verification, appSecrets override secrets of apps by name, e.g. taken from Vault.
 func New(
 	log *slog.Logger,
-	grpcPort int,
-	storagePath string,
-	tokenTTL time.Duration,
-	senderName string,
-	senderEmail string,
-	senderPassword string,
-	verificationCodeLen int,
-	verificationExpiresAt int,
+	logLevel *slog.LevelVar,
+	cfg *config.Config,
+	verificationCodes verificationlib.CodeFormats,
+	appSecrets map[string]string,
 ) *App {
-	storage, err := sqlite.New(storagePath)
+	var stopTracing func(context.Context) error
+	if cfg.Tracing.Enabled {
+		var err error
+		stopTracing, err = tracing.Setup(
+			context.Background(),
+			cfg.Tracing.ServiceName,
+			cfg.Tracing.Endpoint,
+			cfg.Tracing.Insecure,
+			cfg.Tracing.SampleRatio,
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	storage, err := sqlite.New(cfg.StoragePath, mustSetupFieldCipher(cfg.Encryption))
 	if err != nil {
 		panic(err)
 	}
+	storage.SetPool(cfg.SQLitePool.MaxOpenConns, cfg.SQLitePool.MaxIdleConns, cfg.SQLitePool.ConnMaxLifetime)
+	metrics.RegisterDBStats("sqlite", storage.Stats)
+
+	if cfg.Encryption.Enabled {
+		n, err := storage.EncryptUsers(context.Background())
+		if err != nil {
+			panic(err)
+		}
+
+		log.Info("users encrypted", slog.Int64("count", n))
+	}
+
+	passwordCost := mustPasswordCost(log, cfg.Password)
+	passwordPolicy := passstrength.Policy{MinScore: cfg.Password.MinStrength}
+
+	var appProvider cache.AppProvider = storage
+	if appSecrets != nil {
+		appProvider = secrets.NewApps(storage, appSecrets)
+	}
+	// redisClient is shared by rate limits and cache invalidations, it's nil if neither uses Redis.
+	var redisClient redis.UniversalClient
+	if cfg.RateLimit.Store == config.RateLimitStoreRedis || cfg.Cache.Invalidations == config.CacheInvalidationsRedis {
+		redisClient = mustConnectRedis(cfg.Redis)
+	}
+
+	// invalidations is nil if caches are invalidated locally.
+	var invalidations *cache.RedisInvalidations
+	var cacheInvalidations cache.Invalidations
+	if cfg.Cache.Invalidations == config.CacheInvalidationsRedis {
+		invalidations = cache.NewRedisInvalidations(log, redisClient)
+		cacheInvalidations = invalidations
+	}
+
+	apps := cache.NewApps(appProvider, cfg.Cache.AppsTTL, cacheInvalidations)
+	// users must be used for all writes of users, so cached ones are invalidated.
+	users := cache.NewUsers(storage, cfg.Cache.UsersTTL, cfg.Cache.AdminsTTL, cacheInvalidations)
+
+	replica, err := joblock.Owner()
+	if err != nil {
+		panic(err)
+	}
+	jobLocks := joblock.New(storage, replica)
+
+	// auditBuffer is nil if audit events are saved synchronously.
+	var auditBuffer *audit.Buffer
+	var auditSaver audit.EventSaver = storage
+	if cfg.Audit.Async {
+		auditBuffer = audit.NewBuffer(log, storage, cfg.Audit.BufferSize, cfg.Audit.BatchSize, cfg.Audit.FlushInterval, cfg.Audit.Overflow)
+		auditSaver = auditBuffer
+	}
+
+	auditService := audit.New(log, auditSaver, storage)
+	webhooks := webhook.New(
+		log, storage, storage, storage, jobLocks,
+		cfg.Webhooks.MaxAttempts,
+		cfg.Webhooks.InitialBackoff,
+		cfg.Webhooks.MaxBackoff,
+		cfg.Webhooks.Timeout,
+		cfg.Webhooks.PollInterval,
+	)
+
+	// Audit and webhooks react to events of auth and verification services published to the bus.
+	bus := events.New(log)
+	auditService.Subscribe(bus)
+	webhooks.Subscribe(bus)
+
+	var mailSender mail.Sender
+	if cfg.Env == envLocal {
+		mailSender = console.New(log, os.Stdout)
+	} else {
+		var dkimSigner *dkim.Signer
+		if cfg.EmailService.DKIM.PrivateKeyPath != "" {
+			dkimSigner, err = dkim.New(cfg.EmailService.DKIM.Domain, cfg.EmailService.DKIM.Selector, cfg.EmailService.DKIM.PrivateKeyPath)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		mailSender = gmail.New(log, cfg.EmailService.Name, cfg.EmailService.Email, cfg.EmailService.Password, dkimSigner)
+	}
+
+	mailPool := mail.NewPool(log, mailSender, cfg.EmailService.Workers, cfg.EmailService.BatchSize)
+	mailService := mail.New(log, mailPool, storage, storage, storage, storage, storage, storage)
+	phoneVerification := verification.NewPhone(log, storage, storage, storage, storage, users, storage, clock.Real{}, cfg.Verification.MaxAttempts)
+	verification := verification.New(log, storage, storage, storage, storage, users, storage, bus, storage, clock.Real{}, cfg.Verification.MaxAttempts)
+
+	// smsSender is nil if SMS delivery is not configured or phone verification is disabled.
+	var smsSender authgrpc.SMSSender
+	switch {
+	case !cfg.Features.PhoneVerification:
+	case cfg.Env == envLocal:
+		smsSender = smsconsole.New(log, os.Stdout)
+	case cfg.SMSService.AccountSID != "":
+		smsSender = twilio.New(log, cfg.SMSService.AccountSID, cfg.SMSService.AuthToken, cfg.SMSService.From, cfg.SMSService.Timeout)
+	}
+
+	var captchaVerifier authgrpc.Captcha
+	if cfg.Captcha.Enabled {
+		captchaVerifier, err = captcha.New(log, cfg.Captcha.Provider, cfg.Captcha.Secret, cfg.Captcha.MinScore, cfg.Captcha.Timeout)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var limiterStore ratelimit.Store = ratelimit.NewMemoryStore()
+	var failureStore ratelimit.FailureStore = storage
+	if cfg.RateLimit.Store == config.RateLimitStoreRedis {
+		redisStore := ratelimit.NewRedisStore(redisClient)
+		limiterStore, failureStore = redisStore, redisStore
+	}
+	verificationPerEmail := ratelimit.New(limiterStore, "verification_email", cfg.RateLimit.VerificationPerEmail.Limit, cfg.RateLimit.VerificationPerEmail.Window)
+	verificationPerIP := ratelimit.New(limiterStore, "verification_ip", cfg.RateLimit.VerificationPerIP.Limit, cfg.RateLimit.VerificationPerIP.Window)
+	verificationPerPhone := ratelimit.New(limiterStore, "verification_phone", cfg.RateLimit.VerificationPerPhone.Limit, cfg.RateLimit.VerificationPerPhone.Window)
+	rateLimits := authgrpc.RateLimits{
+		VerificationPerEmail: verificationPerEmail,
+		VerificationPerIP:    verificationPerIP,
+		VerificationPerPhone: verificationPerPhone,
+	}
+	if cfg.Login.Throttle.Enabled {
+		rateLimits.LoginPerAccount = ratelimit.NewThrottle(log, failureStore, "login_account", throttlePolicy(cfg.Login.Throttle.Account), cfg.Login.Throttle.Window)
+		rateLimits.LoginPerIP = ratelimit.NewThrottle(log, failureStore, "login_ip", throttlePolicy(cfg.Login.Throttle.IP), cfg.Login.Throttle.Window)
+	}
+	if cfg.Login.Stuffing.Enabled {
+		rateLimits.LoginStuffing = ratelimit.NewStuffingMonitor(log, limiterStore, bus, ratelimit.StuffingPolicy{
+			Window:          cfg.Login.Stuffing.Window,
+			GlobalThreshold: cfg.Login.Stuffing.GlobalThreshold,
+			IPThreshold:     cfg.Login.Stuffing.IPThreshold,
+			Cooldown:        cfg.Login.Stuffing.Cooldown,
+			Captcha:         captchaVerifier != nil,
+		})
+	}
+
+	geoDB := mustOpenGeoIP(cfg.GeoIP)
+
+	globalIPRules, methodIPRules := mustParseIPRules(cfg.IPFilter)
+	ipFilter := ipfilter.New(storage, cfg.IPFilter.AppRulesTTL, globalIPRules, methodIPRules)
+
+	reloadableCodes := verificationlib.NewReloadableCodeFormats(verificationCodes)
+
+	notifier := notification.New(log, mailService)
+
+	// signIn is nil if new device detection is disabled.
+	var signIn auth.SignInChecker
+	if cfg.Login.NewDevice.Notify || cfg.Login.NewDevice.RequireConfirmation || cfg.Login.Risk.Enabled {
+		// riskEvaluator is nil if risk evaluation is disabled.
+		var riskEvaluator signin.RiskEvaluator
+		if cfg.Login.Risk.Enabled {
+			riskEvaluator = risk.NewHeuristic(geoDB, cfg.Login.Risk.MaxTravelSpeed)
+		}
+		riskPolicy := risk.Policy{ChallengeScore: cfg.Login.Risk.ChallengeScore, BlockScore: cfg.Login.Risk.BlockScore}
+
+		signIn = signin.New(log, storage, verification, mailService, notifier, auditService, reloadableCodes, clock.Real{}, random.Crypto, cfg.Login.NewDevice.Notify, cfg.Login.NewDevice.RequireConfirmation, riskEvaluator, riskPolicy)
+	}
+
+	issuance := jwt.Issuance{Issuer: cfg.TokenClaims.Issuer, Audience: cfg.TokenClaims.Audience, Key: mustLoadSigningKey(cfg.TokenSigning)}
+
+	authService := auth.New(log, auth.Deps{
+		UserSaver:     users,
+		UserProvider:  users,
+		AppProvider:   apps,
+		PendingSaver:  storage,
+		Auditor:       auditService,
+		Events:        bus,
+		Notifier:      notifier,
+		SignIn:        signIn,
+		Sessions:      storage,
+		RefreshTokens: storage,
+		Orgs:          storage,
+		Deletions:     storage,
+		Preferences:   storage,
+		Bans:          storage,
+		Directory:     newDirectory(log, users, cfg.Directories),
+		Clock:         clock.Real{},
+		Random:        random.Crypto,
+	}, auth.Config{
+		TokenTTL:               cfg.TokenTTL,
+		ElevatedTokenTTL:       cfg.ElevatedTokenTTL,
+		SSOSessionTTL:          cfg.SSOSessionTTL,
+		RefreshTokenTTL:        cfg.RefreshTokenTTL,
+		PasswordCost:           passwordCost,
+		PasswordPolicy:         passwordPolicy,
+		RevokeSessions:         cfg.Password.RevokeSessions,
+		RequireVerified:        cfg.Login.RequireVerified,
+		PendingRegistrationTTL: pendingRegistrationTTL(cfg.Registration),
+		Issuance:               issuance,
+	})
 
-	authService := auth.New(log, storage, storage, storage, tokenTTL)
-	mailService := gmail.New(log, senderName, senderEmail, senderPassword)
-	verification := verification.New(log, storage, storage, storage, storage)
-	grpcApp := grpcapp.New(log, authService, mailService, verification, grpcPort, verificationCodeLen, verificationExpiresAt)
+	organizations := organization.New(log, storage, notifier, auditService, clock.Real{}, random.Crypto, cfg.Organizations.InvitationTTL)
+
+	serviceAccounts := serviceaccount.New(log, storage, apps, storage, storage, auditService, clock.Real{}, random.Crypto, cfg.ServiceAccounts.TokenTTL, cfg.ServiceAccounts.AssertionAudience, issuance)
+
+	deletions := deletion.New(log, storage, users, storage, verification, mailService, notifier, webhooks, auditService, reloadableCodes, clock.Real{}, random.Crypto, cfg.AccountDeletion.GracePeriod)
+
+	reminders := reminder.New(log, storage, users, storage, storage, verification, mailService, auditService, reloadableCodes, verificationPerEmail, clock.Real{}, random.Crypto, cfg.VerificationReminder.After, cfg.VerificationReminder.Interval, cfg.VerificationReminder.MaxReminders)
+
+	deviceLogins := devicelogin.New(log, storage, apps, authService, auditService, clock.Real{}, random.Crypto, cfg.DeviceLogin.TTL, cfg.DeviceLogin.PollInterval, cfg.DeviceLogin.VerificationURI)
+
+	maintenanceMode := maintenance.New(log, storage, storage, auditService, clock.Real{}, cfg.Maintenance.WindowsTTL, cfg.Maintenance.RetryAfter, maintenanceSettings(cfg.Maintenance))
+
+	antiEnumeration := authgrpc.AntiEnumeration{Enabled: cfg.AntiEnumeration.Enabled, MinDuration: cfg.AntiEnumeration.MinResponseTime}
+	requestSampling := grpcapp.RequestSampling{Auditor: auditService, Percent: cfg.Audit.RequestSamplePercent}
+	authorization := grpcapp.Authorization{Auth: authService, Policies: cfg.GRPC.Authorization, ClientCerts: cfg.Admin.TLS.ClientCAFile != ""}
+
+	grpcApp := grpcapp.New(log, grpcapp.Deps{
+		Auth: authgrpc.Deps{
+			Auth:              authService,
+			EmailService:      mailService,
+			EmailTracker:      mailService,
+			Verification:      verification,
+			PhoneVerification: phoneVerification,
+			SMSSender:         smsSender,
+			VerificationCodes: reloadableCodes,
+			Captcha:           captchaVerifier,
+			RateLimits:        rateLimits,
+			AntiEnumeration:   antiEnumeration,
+			AuditLog:          auditService,
+			Webhooks:          webhooks,
+			Organizations:     organizations,
+			ServiceAccounts:   serviceAccounts,
+			AccountDeletion:   deletions,
+			PasswordPolicy:    passwordPolicy,
+			DeviceLogins:      deviceLogins,
+			Clock:             clock.Real{},
+			Random:            random.Crypto,
+		},
+		RefreshTokens: authService,
+		IPFilter:      ipFilter,
+		GeoIP:         geoDB,
+		Maintenance:   maintenanceMode,
+		Sampling:      requestSampling,
+		Authorization: authorization,
+	}, grpcapp.Config{
+		Port:                cfg.GRPC.Port,
+		Interceptors:        cfg.GRPC.Interceptors,
+		RefreshTokenBinding: cfg.RefreshTokenBinding,
+	})
+
+	var adminApp *grpcapp.App
+	if cfg.Admin.Port != 0 {
+		adminService := admin.New(log, storage, storage, storage, storage, apps, users, auditService, clock.Real{}, random.Crypto)
+		statsService := stats.New(log, storage, clock.Real{}, cfg.Cache.StatsTTL)
+		adminApp = grpcapp.NewAdmin(log, grpcapp.AdminDeps{
+			Admin: admingrpc.Deps{
+				Admin:           adminService,
+				Suppressions:    mailService,
+				AuditLog:        auditService,
+				ServiceAccounts: serviceAccounts,
+				Stats:           statsService,
+				Verifications:   reminders,
+				Maintenance:     maintenanceMode,
+				EmailPreviews:   notifier,
+			},
+			IPFilter:      ipFilter,
+			GeoIP:         geoDB,
+			Sampling:      requestSampling,
+			Authorization: authorization,
+		}, grpcapp.AdminConfig{
+			Host:         cfg.Admin.Host,
+			Port:         cfg.Admin.Port,
+			TLS:          mustLoadTLS(cfg.Admin.TLS),
+			Interceptors: cfg.GRPC.Interceptors,
+		})
+	}
+
+	mux := http.NewServeMux()
+	if err := bounceshttp.Register(mux, log, mailService, cfg.EmailService.Webhooks.SNSTopicARN, cfg.EmailService.Webhooks.SendGridPublicKey); err != nil {
+		panic(err)
+	}
+
+	if cfg.HTTP.ForwardAuth.Enabled {
+		forwardauthhttp.Register(mux, log, authService, cfg.HTTP.ForwardAuth.CookieName, cfg.HTTP.Sessions.Enabled)
+	}
+	if cfg.HTTP.Sessions.Enabled {
+		sessionhttp.Register(mux, log, authService, random.Crypto, sessionhttp.Cookies{
+			Name:     cfg.HTTP.ForwardAuth.CookieName,
+			Domain:   cfg.HTTP.Sessions.Domain,
+			SameSite: sameSite(cfg.HTTP.Sessions.SameSite),
+			Insecure: cfg.HTTP.Sessions.Insecure,
+		})
+	}
+	if cfg.HTTP.TokenReview.Enabled {
+		tokenreviewhttp.Register(mux, log, authService, cfg.HTTP.TokenReview.AppID)
+	}
+	if issuance.Key != nil {
+		jwkshttp.Register(mux, log, issuance.Key)
+	}
+
+	httpApp := httpapp.New(log, cfg.HTTP.Port, headershttp.Wrap(log, mux, storage, cfg.Cache.AppsTTL))
+
+	checks := map[string]opshttp.Pinger{"storage": storage}
+	if pinger, ok := mailSender.(opshttp.Pinger); ok {
+		checks["mail"] = pinger
+	}
+	if redisClient != nil {
+		checks["redis"] = redisPinger{redisClient}
+	}
+
+	opsMux := http.NewServeMux()
+	probes := opshttp.Register(opsMux, log, checks, logLevel)
+	if cfg.Ops.Debug {
+		opshttp.RegisterDebug(opsMux, log)
+	}
+	opsApp := httpapp.New(log, cfg.Ops.Port, opsMux)
+
+	cleanupService := cleanup.New(log, storage, storage, storage, storage, storage, cfg.Login.Throttle.Window)
+	scheduler := schedulerapp.New(log, jobLocks,
+		schedulerapp.Job{Name: "cleanup_verifications", Interval: cfg.Scheduler.CleanupInterval, Run: cleanupService.Verifications},
+		schedulerapp.Job{Name: "cleanup_pending_registrations", Interval: cfg.Scheduler.CleanupInterval, Run: cleanupService.PendingRegistrations},
+		schedulerapp.Job{Name: "cleanup_login_failures", Interval: cfg.Scheduler.CleanupInterval, Run: cleanupService.LoginFailures},
+		schedulerapp.Job{Name: "cleanup_sessions", Interval: cfg.Scheduler.CleanupInterval, Run: cleanupService.Sessions},
+		schedulerapp.Job{Name: "cleanup_device_logins", Interval: cfg.Scheduler.CleanupInterval, Run: cleanupService.DeviceLogins},
+		schedulerapp.Job{Name: "purge_deleted_accounts", Interval: cfg.Scheduler.CleanupInterval, Run: deletions.Purge},
+		schedulerapp.Job{Name: "remind_unverified_users", Interval: cfg.Scheduler.CleanupInterval, Run: reminders.Remind},
+	)
 
 	return &App{
-		GRPCServer: grpcApp,
+		log:                  log,
+		GRPCServer:           grpcApp,
+		AdminServer:          adminApp,
+		HTTPServer:           httpApp,
+		OpsServer:            opsApp,
+		Scheduler:            scheduler,
+		probes:               probes,
+		storage:              storage,
+		webhooks:             webhooks,
+		mailPool:             mailPool,
+		auditBuffer:          auditBuffer,
+		redis:                redisClient,
+		invalidations:        invalidations,
+		shutdownTimeout:      cfg.ShutdownTimeout,
+		stopTracing:          stopTracing,
+		verificationCodes:    reloadableCodes,
+		verificationPerEmail: verificationPerEmail,
+		verificationPerIP:    verificationPerIP,
+		verificationPerPhone: verificationPerPhone,
+		ipFilter:             ipFilter,
+		maintenance:          maintenanceMode,
+	}
+}
+
+// Run starts servers and blocks until ctx is done, then stops the app gracefully.
+func (a *App) Run(ctx context.Context) {
+	go a.GRPCServer.MustRun()
+	if a.AdminServer != nil {
+		go a.AdminServer.MustRun()
+	}
+	go a.HTTPServer.MustRun()
+	go a.OpsServer.MustRun()
+	a.mailPool.Start()
+	if a.auditBuffer != nil {
+		a.auditBuffer.Start()
+	}
+
+	a.workers.Add(2)
+	go func() {
+		defer a.workers.Done()
+		a.webhooks.Run(ctx)
+	}()
+	go func() {
+		defer a.workers.Done()
+		a.Scheduler.Run(ctx)
+	}()
+	if a.invalidations != nil {
+		go a.invalidations.Run(ctx)
+	}
+
+	<-ctx.Done()
+
+	a.Stop()
+}
+
+// Stop drains in-flight requests within shutdown timeout and closes storage.
+// Storage is closed last, so drained requests can still use it.
+func (a *App) Stop() {
+	const op = "app.Stop"
+
+	log := a.log.With(slog.String("op", op))
+
+	a.probes.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		a.GRPCServer.Stop(ctx)
+	}()
+	if a.AdminServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.AdminServer.Stop(ctx)
+		}()
+	}
+	go func() {
+		defer wg.Done()
+		a.HTTPServer.Stop(ctx)
+	}()
+	wg.Wait()
+
+	// Requests are drained, so no more emails are queued.
+	a.mailPool.Stop()
+
+	a.OpsServer.Stop(ctx)
+
+	a.workers.Wait()
+
+	// Buffered audit events are saved before storage is closed.
+	if a.auditBuffer != nil {
+		a.auditBuffer.Stop()
 	}
+
+	if err := a.storage.Stop(); err != nil {
+		log.Error("failed to close storage", sl.Err(err))
+	}
+
+	if a.redis != nil {
+		if err := a.redis.Close(); err != nil {
+			log.Error("failed to close redis", sl.Err(err))
+		}
+	}
+
+	if a.stopTracing != nil {
+		if err := a.stopTracing(ctx); err != nil {
+			log.Error("failed to flush traces", sl.Err(err))
+		}
+	}
+}
+
+// mustLoadTLS returns TLS config of a server, nil if TLS is disabled.
+func mustLoadTLS(cfg config.TLSConfig) *tls.Config {
+	if cfg.CertFile == "" {
+		return nil
+	}
+
+	tlsConfig, err := tlsconfig.Server(cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile)
+	if err != nil {
+		panic(err)
+	}
+
+	return tlsConfig
+}
+
+// sameSite returns SameSite attribute of the config value, it's validated.
+func sameSite(value string) http.SameSite {
+	switch value {
+	case config.SameSiteStrict:
+		return http.SameSiteStrictMode
+	case config.SameSiteNone:
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// mustLoadSigningKey returns key of the config, nil if tokens are signed by secrets of apps.
+func mustLoadSigningKey(cfg config.TokenSigningConfig) *jwt.SigningKey {
+	if cfg.KeyFile == "" {
+		return nil
+	}
+
+	key, err := jwt.LoadSigningKey(cfg.KeyFile)
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+// mustConnectRedis returns client of Redis shared by replicas.
+func mustConnectRedis(cfg config.RedisConfig) redis.UniversalClient {
+	opts := &redis.Options{
+		Addr:         cfg.Addr,
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	// Misconfigured Redis fails startup, not the first request using it.
+	if err := client.Ping(ctx).Err(); err != nil {
+		panic(err)
+	}
+
+	return client
+}
+
+// redisPinger checks readiness of Redis.
+type redisPinger struct {
+	client redis.UniversalClient
+}
+
+func (p redisPinger) Ping(ctx context.Context) error {
+	return p.client.Ping(ctx).Err()
+}
+
+// mustOpenGeoIP returns GeoIP database, nil if GeoIP is disabled.
+func mustOpenGeoIP(cfg config.GeoIPConfig) *geoip.DB {
+	if cfg.DatabasePath == "" {
+		return nil
+	}
+
+	db, err := geoip.Open(cfg.DatabasePath)
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+// newDirectory returns router of users to external user directories, nil if there are none.
+func newDirectory(log *slog.Logger, users directory.UserStore, cfgs []config.DirectoryConfig) auth.Directory {
+	if len(cfgs) == 0 {
+		return nil
+	}
+
+	directories := make([]directory.Directory, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		directories = append(directories, directory.Directory{
+			Name:    cfg.Name,
+			Client:  httpdirectory.New(cfg.URL, cfg.Token, cfg.Timeout),
+			AppIDs:  cfg.Apps,
+			Domains: cfg.Domains,
+		})
+	}
+
+	return directory.New(log, users, directories)
+}
+
+// mustSetupFieldCipher returns cipher of emails and phones of users, nil if encryption is disabled.
+func mustSetupFieldCipher(cfg config.EncryptionConfig) sqlite.FieldCipher {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	cipher, err := fieldcrypt.New(cfg.Key)
+	if err != nil {
+		panic(err)
+	}
+
+	return cipher
+}
+
+// mustPasswordCost returns configured bcrypt cost or calibrates it if calibration is enabled.
+func mustPasswordCost(log *slog.Logger, cfg config.PasswordConfig) int {
+	if cfg.CalibrateTarget <= 0 {
+		return cfg.Cost
+	}
+
+	cost, took, err := passhash.Calibrate(cfg.Cost, cfg.CalibrateTarget)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Info("password cost calibrated", slog.Int("cost", cost), slog.Duration("hash_time", took))
+
+	return cost
+}
+
+func throttlePolicy(cfg config.ThrottlePolicyConfig) ratelimit.Policy {
+	return ratelimit.Policy{
+		DelayAfter:      cfg.DelayAfter,
+		BaseDelay:       cfg.BaseDelay,
+		MaxDelay:        cfg.MaxDelay,
+		CaptchaAfter:    cfg.CaptchaAfter,
+		LockoutAfter:    cfg.LockoutAfter,
+		LockoutDuration: cfg.LockoutDuration,
+	}
+}
+
+// mustParseIPRules parses global and method rules of IP filter, panics if config has invalid CIDR ranges.
+func mustParseIPRules(cfg config.IPFilterConfig) (ipfilter.Rules, map[string]ipfilter.Rules) {
+	global, err := ipfilter.ParseRules(cfg.Allow, cfg.Deny)
+	if err != nil {
+		panic(err)
+	}
+
+	methods := make(map[string]ipfilter.Rules, len(cfg.Methods))
+	for method, rules := range cfg.Methods {
+		if methods[method], err = ipfilter.ParseRules(rules.Allow, rules.Deny); err != nil {
+			panic(err)
+		}
+	}
+
+	return global, methods
+}
+
+// Reload applies reloadable config values to the running app.
+func (a *App) Reload(
+	verificationCodes verificationlib.CodeFormats,
+	rateLimitCfg config.RateLimitConfig,
+	ipFilterCfg config.IPFilterConfig,
+	maintenanceCfg config.MaintenanceConfig,
+) {
+	a.verificationCodes.Set(verificationCodes)
+	a.verificationPerEmail.SetLimit(rateLimitCfg.VerificationPerEmail.Limit, rateLimitCfg.VerificationPerEmail.Window)
+	a.verificationPerIP.SetLimit(rateLimitCfg.VerificationPerIP.Limit, rateLimitCfg.VerificationPerIP.Window)
+	a.verificationPerPhone.SetLimit(rateLimitCfg.VerificationPerPhone.Limit, rateLimitCfg.VerificationPerPhone.Window)
+	// Config is validated before reload, so rules are valid.
+	a.ipFilter.SetRules(mustParseIPRules(ipFilterCfg))
+	a.maintenance.SetSettings(maintenanceSettings(maintenanceCfg))
+}
+
+// pendingRegistrationTTL returns TTL of pending registrations, 0 disables pending-registration mode.
+func pendingRegistrationTTL(cfg config.RegistrationConfig) time.Duration {
+	if cfg.Mode != config.RegistrationModePending {
+		return 0
+	}
+
+	return cfg.PendingTTL
+}
+
+func maintenanceSettings(cfg config.MaintenanceConfig) maintenance.Settings {
+	return maintenance.Settings{Enabled: cfg.Enabled, Message: cfg.Message}
 }
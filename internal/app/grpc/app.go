@@ -2,62 +2,181 @@ package grpcapp
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
+	"math/rand"
 	"net"
+	"strconv"
+	"strings"
+	"time"
 
+	"grpc-service-ref/internal/domain/models"
+	admingrpc "grpc-service-ref/internal/grpc/admin"
 	authgrpc "grpc-service-ref/internal/grpc/auth"
+	authv2grpc "grpc-service-ref/internal/grpc/authv2"
+	"grpc-service-ref/internal/lib/geoip"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/services/maintenance"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
-	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+// IPFilter allows or denies client IPs
+type IPFilter interface {
+	Allowed(ctx context.Context, ip string, method string, appID int) (bool, error)
+}
+
+// GeoIP resolves locations of client IPs.
+type GeoIP interface {
+	Lookup(ip string) (models.Location, bool)
+}
+
+// Maintenance reports whether apps are under maintenance.
+type Maintenance interface {
+	Check(ctx context.Context, appID int) (maintenance.Notice, bool, error)
+}
+
+// RequestAuditor records sampled RPCs to the audit log.
+type RequestAuditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// RequestSampling records metadata of Percent of RPCs to the audit log for forensic analysis,
+// zero Percent disables it. Requests and responses themselves are never recorded.
+type RequestSampling struct {
+	Auditor RequestAuditor
+	Percent float64
+}
+
+// maxSampledUserAgent is the longest user agent recorded by sampling, longer ones are truncated.
+const maxSampledUserAgent = 256
+
+// maintenanceExempt are prefixes of methods served during maintenance, so load balancers don't take
+// the server out of rotation and its API can be inspected.
+var maintenanceExempt = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
 type App struct {
 	log        *slog.Logger
 	gRPCServer *grpc.Server
-	port       int
+	// host is the address the server listens on, empty means all interfaces.
+	host string
+	port int
+}
+
+// Deps are services of the public server and of its interceptors.
+type Deps struct {
+	// Auth are services of API v1, API v2 adapts it.
+	Auth authgrpc.Deps
+	// RefreshTokens issues refresh tokens of API v2.
+	RefreshTokens authv2grpc.Auth
+	IPFilter      IPFilter
+	GeoIP         GeoIP
+	Maintenance   Maintenance
+	Sampling      RequestSampling
+	Authorization Authorization
+}
+
+// Config configures the public server.
+type Config struct {
+	Port int
+	// Interceptors are names of interceptors in order of the chain, see config.GRPCConfig.
+	Interceptors []string
+	// RefreshTokenBinding is what refresh tokens of API v2 are bound to, see config.Config.RefreshTokenBinding.
+	RefreshTokenBinding string
 }
 
 // New creates new gRPC server app.
-func New(
-	log *slog.Logger,
-	authService authgrpc.Auth,
-	mailService authgrpc.EmailSender,
-	verificationService authgrpc.Verification,
-	port int,
-	verificationCodeLen int,
-	verificationExpires int,
-) *App {
-	loggingOpts := []logging.Option{
-		logging.WithLogOnEvents(
-			//logging.StartCall, logging.FinishCall,
-			logging.PayloadReceived, logging.PayloadSent,
-		),
-		// Add any other option (check functions starting with logging.With).
+func New(log *slog.Logger, deps Deps, cfg Config) *App {
+	gRPCServer := grpc.NewServer(serverOptions(log, cfg.Interceptors, deps.IPFilter, deps.GeoIP, deps.Maintenance, deps.Sampling, deps.Authorization)...)
+
+	v1 := authgrpc.Register(gRPCServer, deps.Auth)
+	authv2grpc.Register(gRPCServer, v1, deps.RefreshTokens, deps.Auth.VerificationCodes, clientFingerprint(cfg.RefreshTokenBinding, deps.Auth.Clock), deps.Auth.Clock)
+
+	return &App{
+		log:        log,
+		gRPCServer: gRPCServer,
+		port:       cfg.Port,
 	}
+}
+
+// AdminDeps are services of the admin server and of its interceptors.
+type AdminDeps struct {
+	Admin         admingrpc.Deps
+	IPFilter      IPFilter
+	GeoIP         GeoIP
+	Sampling      RequestSampling
+	Authorization Authorization
+}
 
-	recoveryOpts := []recovery.Option{
-		recovery.WithRecoveryHandler(func(p interface{}) (err error) {
-			log.Error("Recovered from panic", slog.Any("panic", p))
+// AdminConfig configures the admin server.
+type AdminConfig struct {
+	// Host is the address the server listens on, see config.AdminConfig.Host.
+	Host string
+	Port int
+	// TLS is nil if TLS is disabled.
+	TLS *tls.Config
+	// Interceptors are names of interceptors in order of the chain, see config.GRPCConfig.
+	Interceptors []string
+}
 
-			return status.Errorf(codes.Internal, "internal error")
-		}),
+// NewAdmin creates gRPC server app of AdminService, it has its own listener, so admin RPCs aren't exposed
+// on the public port. It's served during maintenance.
+func NewAdmin(log *slog.Logger, deps AdminDeps, cfg AdminConfig) *App {
+	opts := serverOptions(log, cfg.Interceptors, deps.IPFilter, deps.GeoIP, nil, deps.Sampling, deps.Authorization)
+	if cfg.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(cfg.TLS)))
 	}
 
-	gRPCServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
-		recovery.UnaryServerInterceptor(recoveryOpts...),
-		logging.UnaryServerInterceptor(InterceptorLogger(log), loggingOpts...),
-	))
+	gRPCServer := grpc.NewServer(opts...)
 
-	authgrpc.Register(gRPCServer, authService, mailService, verificationService, verificationCodeLen, verificationExpires)
+	admingrpc.Register(gRPCServer, deps.Admin)
 
 	return &App{
 		log:        log,
 		gRPCServer: gRPCServer,
-		port:       port,
+		host:       cfg.Host,
+		port:       cfg.Port,
+	}
+}
+
+// serverOptions returns options of gRPC servers: tracing and the interceptor chain of the names.
+// Nil maintenanceMode means the server is never under maintenance.
+func serverOptions(
+	log *slog.Logger,
+	interceptors []string,
+	ipFilter IPFilter,
+	geo GeoIP,
+	maintenanceMode Maintenance,
+	sampling RequestSampling,
+	authorization Authorization,
+) []grpc.ServerOption {
+	chain := mustChain(interceptors, interceptorDeps{
+		log:           log,
+		ipFilter:      ipFilter,
+		geo:           geo,
+		maintenance:   maintenanceMode,
+		sampling:      sampling,
+		authorization: authorization,
+	})
+
+	return []grpc.ServerOption{
+		// Starts a span per RPC, it's a no-op until tracing is set up.
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(chain...),
 	}
 }
 
@@ -69,6 +188,184 @@ func InterceptorLogger(l *slog.Logger) logging.Logger {
 	})
 }
 
+// metricsInterceptor counts RPCs and measures their duration.
+// It goes first in the chain, so recovered panics are counted as Internal.
+func metricsInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	metrics.RPCDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	metrics.RPCRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+
+	return resp, err
+}
+
+// ipFilterInterceptor rejects RPCs of client IPs the filter denies.
+// Rules of the app apply to requests with app_id.
+func ipFilterInterceptor(filter IPFilter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		allowed, err := filter.Allowed(ctx, peer.IP(ctx), info.FullMethod, requestAppID(req))
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to check client address")
+		}
+
+		if !allowed {
+			return nil, status.Error(codes.PermissionDenied, "client address is not allowed")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// maintenanceInterceptor rejects RPCs with Unavailable while the app of the request, or all apps, are under
+// maintenance. The status has RetryInfo with time left until the window ends, and ErrorInfo with MAINTENANCE reason.
+// Failing check doesn't reject RPCs, so storage outage doesn't put the server under maintenance.
+func maintenanceInterceptor(log *slog.Logger, m Maintenance) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		for _, prefix := range maintenanceExempt {
+			if strings.HasPrefix(info.FullMethod, prefix) {
+				return handler(ctx, req)
+			}
+		}
+
+		notice, active, err := m.Check(ctx, requestAppID(req))
+		if err != nil {
+			log.Error("failed to check maintenance", slog.String("method", info.FullMethod), sl.Err(err))
+
+			return handler(ctx, req)
+		}
+
+		if !active {
+			return handler(ctx, req)
+		}
+
+		msg := "service is under maintenance"
+		if notice.Message != "" {
+			msg += ": " + notice.Message
+		}
+
+		st, err := status.New(codes.Unavailable, msg).WithDetails(
+			&errdetails.RetryInfo{RetryDelay: durationpb.New(notice.RetryAfter)},
+			&errdetails.ErrorInfo{Reason: "MAINTENANCE", Domain: "sso"},
+		)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, msg)
+		}
+
+		return nil, st.Err()
+	}
+}
+
+// geoIPInterceptor adds location of the client IP to the context, see geoip.FromContext.
+func geoIPInterceptor(geo GeoIP) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if loc, ok := geo.Lookup(peer.IP(ctx)); ok {
+			ctx = geoip.NewContext(ctx, loc)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// samplingInterceptor records method, caller, latency and status code of sampled RPCs to the audit log.
+// It goes after the IP filter and maintenance, so rejected RPCs aren't sampled, and after GeoIP,
+// so sampled RPCs are located. Health checks and reflection aren't sampled.
+func samplingInterceptor(sampling RequestSampling) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		for _, prefix := range maintenanceExempt {
+			if strings.HasPrefix(info.FullMethod, prefix) {
+				return handler(ctx, req)
+			}
+		}
+
+		if rand.Float64()*100 >= sampling.Percent {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		payload := map[string]string{
+			"method":     info.FullMethod,
+			"code":       status.Code(err).String(),
+			"latency_ms": strconv.FormatInt(time.Since(start).Milliseconds(), 10),
+		}
+		if userAgent := firstMetadata(ctx, "user-agent"); userAgent != "" {
+			payload["user_agent"] = truncate(userAgent, maxSampledUserAgent)
+		}
+		if name := peer.ClientCertName(ctx); name != "" {
+			payload["client_cert"] = name
+		}
+
+		sampling.Auditor.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionRequestSampled,
+			Subject: info.FullMethod,
+			AppID:   requestAppID(req),
+			Payload: payload,
+		})
+
+		return resp, err
+	}
+}
+
+// firstMetadata returns the first value of the incoming metadata key, empty string if there is none.
+func firstMetadata(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// truncate cuts s to at most n bytes, keeping it valid UTF-8.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return strings.ToValidUTF8(s[:n], "")
+}
+
+// requestAppID returns app_id of the request, 0 if it has none.
+func requestAppID(req any) int {
+	switch r := req.(type) {
+	case interface{ GetAppId() int32 }:
+		return int(r.GetAppId())
+	case interface{ GetAppId() int64 }:
+		return int(r.GetAppId())
+	default:
+		return 0
+	}
+}
+
 // MustRun runs gRPC server and panics if any error occurs.
 func (a *App) MustRun() {
 	if err := a.Run(); err != nil {
@@ -80,7 +377,7 @@ func (a *App) MustRun() {
 func (a *App) Run() error {
 	const op = "grpcapp.Run"
 
-	l, err := net.Listen("tcp", fmt.Sprintf(":%d", a.port))
+	l, err := net.Listen("tcp", net.JoinHostPort(a.host, strconv.Itoa(a.port)))
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -94,12 +391,27 @@ func (a *App) Run() error {
 	return nil
 }
 
-// Stop stops gRPC server.
-func (a *App) Stop() {
+// Stop stops gRPC server, waiting for in-flight RPCs until ctx is done.
+// Connections left after that are closed.
+func (a *App) Stop(ctx context.Context) {
 	const op = "grpcapp.Stop"
 
-	a.log.With(slog.String("op", op)).
-		Info("stopping gRPC server", slog.Int("port", a.port))
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("stopping gRPC server", slog.Int("port", a.port))
+
+	stopped := make(chan struct{})
+	go func() {
+		a.gRPCServer.GracefulStop()
+		close(stopped)
+	}()
 
-	a.gRPCServer.GracefulStop()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Warn("drain timeout exceeded, closing remaining connections")
+
+		a.gRPCServer.Stop()
+		<-stopped
+	}
 }
@@ -0,0 +1,163 @@
+package grpcapp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"strings"
+
+	"grpc-service-ref/internal/config"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/services/auth"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Authenticator authenticates callers of RPCs by their access tokens.
+type Authenticator interface {
+	AuthenticateToken(ctx context.Context, token string) (jwt.Claims, error)
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+// Authorization enforces policies of RPCs, nil Auth disables it. Policies override defaultPolicies.
+type Authorization struct {
+	Auth     Authenticator
+	Policies map[string]string
+	// ClientCerts is set if AdminService requires client certificates, its RPCs default to mtls then,
+	// otherwise to role:admin.
+	ClientCerts bool
+}
+
+// adminService is prefix of AdminService methods.
+const adminService = "/auth.Admin/"
+
+// defaultPolicies are policies of RPCs defined in code, keyed like config.GRPCConfig.Authorization.
+// RPCs without policy are anonymous, e.g. Login, or authenticate callers by other means, e.g. x-app-secret.
+// AdminService is never anonymous, see Authorization.ClientCerts.
+var defaultPolicies = map[string]string{
+	ssov1.Auth_IsAdmin_FullMethodName:                       config.PolicyAuthenticated,
+	ssov1.Auth_ChangePassword_FullMethodName:                config.PolicyAuthenticated,
+	ssov1.Auth_Elevate_FullMethodName:                       config.PolicyAuthenticated,
+	ssov1.Auth_Authorize_FullMethodName:                     config.PolicyAuthenticated,
+	ssov1.Auth_Logout_FullMethodName:                        config.PolicyAuthenticated,
+	ssov1.Auth_WhoAmI_FullMethodName:                        config.PolicyAuthenticated,
+	ssov1.Auth_GetSecurityEvents_FullMethodName:             config.PolicyAuthenticated,
+	ssov1.Auth_CreateOrganization_FullMethodName:            config.PolicyAuthenticated,
+	ssov1.Auth_InviteToOrganization_FullMethodName:          config.PolicyAuthenticated,
+	ssov1.Auth_AcceptOrganizationInvitation_FullMethodName:  config.PolicyAuthenticated,
+	ssov1.Auth_ListOrganizations_FullMethodName:             config.PolicyAuthenticated,
+	ssov1.Auth_RequestAccountDeletion_FullMethodName:        config.PolicyAuthenticated,
+	ssov1.Auth_ConfirmAccountDeletion_FullMethodName:        config.PolicyAuthenticated,
+	ssov1.Auth_ApproveDeviceLogin_FullMethodName:            config.PolicyAuthenticated,
+	ssov1.Auth_UpdateNotificationPreferences_FullMethodName: config.PolicyAuthenticated,
+}
+
+// policyFor returns policy of the method: of the method itself, otherwise of the longest service prefix
+// matching it, otherwise anonymous.
+func policyFor(policies map[string]string, method string) string {
+	if policy, ok := policies[method]; ok {
+		return policy
+	}
+
+	policy, matched := config.PolicyAnonymous, ""
+	for key, p := range policies {
+		if strings.HasSuffix(key, "/") && strings.HasPrefix(method, key) && len(key) > len(matched) {
+			policy, matched = p, key
+		}
+	}
+
+	return policy
+}
+
+// authorizationInterceptor rejects RPCs whose callers don't satisfy policy of the method. Claims of callers
+// authenticated by it are put to the context, see jwt.FromContext, so handlers don't authenticate them again
+// and one-time tokens aren't consumed twice. Health checks and reflection are anonymous.
+func authorizationInterceptor(log *slog.Logger, authorization Authorization) grpc.UnaryServerInterceptor {
+	policies := maps.Clone(defaultPolicies)
+	policies[adminService] = config.PolicyAdmin
+	if authorization.ClientCerts {
+		policies[adminService] = config.PolicyMTLS
+	}
+	maps.Copy(policies, authorization.Policies)
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		for _, prefix := range maintenanceExempt {
+			if strings.HasPrefix(info.FullMethod, prefix) {
+				return handler(ctx, req)
+			}
+		}
+
+		switch policyFor(policies, info.FullMethod) {
+		case config.PolicyAuthenticated:
+			claims, err := authenticate(ctx, authorization.Auth)
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = jwt.NewContext(ctx, claims)
+		case config.PolicyAdmin:
+			claims, err := authenticate(ctx, authorization.Auth)
+			if err != nil {
+				return nil, err
+			}
+
+			// Service accounts have no roles.
+			isAdmin := false
+			if claims.UserID != 0 {
+				isAdmin, err = authorization.Auth.IsAdmin(ctx, claims.UserID)
+				if err != nil {
+					log.Error("failed to check admin", slog.String("method", info.FullMethod), sl.Err(err))
+
+					return nil, status.Error(codes.Internal, "failed to authorize")
+				}
+			}
+
+			if !isAdmin {
+				return nil, status.Error(codes.PermissionDenied, "admin role is required")
+			}
+
+			ctx = jwt.NewContext(ctx, claims)
+		case config.PolicyMTLS:
+			if peer.ClientCertName(ctx) == "" {
+				return nil, status.Error(codes.Unauthenticated, "client certificate is required")
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// authenticate returns claims of the bearer token in authorization metadata.
+func authenticate(ctx context.Context, a Authenticator) (jwt.Claims, error) {
+	token, ok := strings.CutPrefix(firstMetadata(ctx, "authorization"), "Bearer ")
+	if !ok || token == "" {
+		return jwt.Claims{}, status.Error(codes.Unauthenticated, "access token is required")
+	}
+
+	claims, err := a.AuthenticateToken(ctx, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrTokenRevoked):
+			return jwt.Claims{}, status.Error(codes.Unauthenticated, "access token is revoked")
+		case errors.Is(err, auth.ErrTokenConsumed):
+			return jwt.Claims{}, status.Error(codes.Unauthenticated, "one-time access token is already used")
+		case errors.Is(err, auth.ErrInvalidToken):
+			return jwt.Claims{}, status.Error(codes.Unauthenticated, "invalid access token")
+		default:
+			return jwt.Claims{}, status.Error(codes.Internal, "failed to authenticate")
+		}
+	}
+
+	return claims, nil
+}
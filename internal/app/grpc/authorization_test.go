@@ -0,0 +1,218 @@
+package grpcapp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log/slog"
+	"testing"
+
+	"grpc-service-ref/internal/config"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/services/auth"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// staticAuth is Authenticator with tokens and admins known upfront.
+type staticAuth struct {
+	tokens     map[string]jwt.Claims
+	tokenErrs  map[string]error
+	admins     map[int64]bool
+	isAdminErr error
+}
+
+func (a staticAuth) AuthenticateToken(_ context.Context, token string) (jwt.Claims, error) {
+	if err, ok := a.tokenErrs[token]; ok {
+		return jwt.Claims{}, err
+	}
+
+	claims, ok := a.tokens[token]
+	if !ok {
+		return jwt.Claims{}, auth.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (a staticAuth) IsAdmin(_ context.Context, userID int64) (bool, error) {
+	return a.admins[userID], a.isAdminErr
+}
+
+func TestPolicyFor(t *testing.T) {
+	policies := map[string]string{
+		"/auth.Auth/Login": config.PolicyAuthenticated,
+		"/auth.Admin/":     config.PolicyMTLS,
+		"/auth.Admin/Get":  config.PolicyAnonymous,
+		"/auth.Auth/":      config.PolicyAuthenticated,
+		// Keys without trailing slash aren't service prefixes.
+		"/other.":            config.PolicyAdmin,
+		"/auth.Admin/Users/": config.PolicyAdmin,
+	}
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"/auth.Auth/Login", config.PolicyAuthenticated},
+		{"/auth.Admin/GetUser", config.PolicyMTLS},
+		{"/auth.Admin/Get", config.PolicyAnonymous},
+		{"/auth.Auth/Register", config.PolicyAuthenticated},
+		{"/auth.Admin/Users/List", config.PolicyAdmin},
+		{"/other.Service/Method", config.PolicyAnonymous},
+		{"/grpc.health.v1.Health/Check", config.PolicyAnonymous},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			assert.Equal(t, tt.want, policyFor(policies, tt.method))
+		})
+	}
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func withClientCert(ctx context.Context, name string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: name}}
+
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+}
+
+func TestAuthorizationInterceptor(t *testing.T) {
+	const (
+		authenticatedMethod = "/test.Service/Authenticated"
+		adminMethod         = "/test.Service/Admin"
+		mtlsMethod          = "/test.Service/MTLS"
+	)
+
+	user := jwt.Claims{UserID: 1, Email: "user@example.com"}
+	admin := jwt.Claims{UserID: 2, Email: "admin@example.com"}
+	serviceAccount := jwt.Claims{ServiceAccountID: 3}
+
+	authorization := Authorization{
+		Auth: staticAuth{
+			tokens: map[string]jwt.Claims{"user": user, "admin": admin, "service-account": serviceAccount},
+			tokenErrs: map[string]error{
+				"revoked":  auth.ErrTokenRevoked,
+				"consumed": auth.ErrTokenConsumed,
+				"failing":  assert.AnError,
+			},
+			admins: map[int64]bool{admin.UserID: true},
+		},
+		Policies: map[string]string{
+			authenticatedMethod: config.PolicyAuthenticated,
+			adminMethod:         config.PolicyAdmin,
+			mtlsMethod:          config.PolicyMTLS,
+			// Overrides default policy.
+			ssov1.Auth_WhoAmI_FullMethodName: config.PolicyAnonymous,
+			// Policies don't apply to health checks.
+			"/grpc.health.v1.Health/": config.PolicyAdmin,
+		},
+	}
+
+	interceptor := authorizationInterceptor(slog.New(slog.NewTextHandler(io.Discard, nil)), authorization)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		method     string
+		ctx        context.Context
+		wantCode   codes.Code
+		wantClaims *jwt.Claims
+	}{
+		{name: "anonymous", method: "/test.Service/Anonymous", ctx: ctx},
+		{name: "overridden default policy", method: ssov1.Auth_WhoAmI_FullMethodName, ctx: ctx},
+		{name: "default policy", method: ssov1.Auth_Logout_FullMethodName, ctx: ctx, wantCode: codes.Unauthenticated},
+		{name: "health check", method: "/grpc.health.v1.Health/Check", ctx: ctx},
+		{name: "authenticated", method: authenticatedMethod, ctx: withToken(ctx, "user"), wantClaims: &user},
+		{name: "service account", method: authenticatedMethod, ctx: withToken(ctx, "service-account"), wantClaims: &serviceAccount},
+		{name: "no token", method: authenticatedMethod, ctx: ctx, wantCode: codes.Unauthenticated},
+		{name: "not bearer", method: authenticatedMethod, ctx: metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "user")), wantCode: codes.Unauthenticated},
+		{name: "invalid token", method: authenticatedMethod, ctx: withToken(ctx, "unknown"), wantCode: codes.Unauthenticated},
+		{name: "revoked token", method: authenticatedMethod, ctx: withToken(ctx, "revoked"), wantCode: codes.Unauthenticated},
+		{name: "consumed token", method: authenticatedMethod, ctx: withToken(ctx, "consumed"), wantCode: codes.Unauthenticated},
+		{name: "authentication failure", method: authenticatedMethod, ctx: withToken(ctx, "failing"), wantCode: codes.Internal},
+		{name: "admin", method: adminMethod, ctx: withToken(ctx, "admin"), wantClaims: &admin},
+		{name: "not admin", method: adminMethod, ctx: withToken(ctx, "user"), wantCode: codes.PermissionDenied},
+		{name: "service account isn't admin", method: adminMethod, ctx: withToken(ctx, "service-account"), wantCode: codes.PermissionDenied},
+		{name: "admin without token", method: adminMethod, ctx: ctx, wantCode: codes.Unauthenticated},
+		{name: "client certificate", method: mtlsMethod, ctx: withClientCert(ctx, "operator")},
+		{name: "no client certificate", method: mtlsMethod, ctx: withToken(ctx, "admin"), wantCode: codes.Unauthenticated},
+		{name: "AdminService", method: ssov1.Admin_GetUser_FullMethodName, ctx: withToken(ctx, "admin"), wantClaims: &admin},
+		{name: "AdminService without admin role", method: ssov1.Admin_GetUser_FullMethodName, ctx: withToken(ctx, "user"), wantCode: codes.PermissionDenied},
+		{name: "anonymous AdminService", method: ssov1.Admin_GetUser_FullMethodName, ctx: ctx, wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+
+				claims, ok := jwt.FromContext(ctx)
+				if tt.wantClaims == nil {
+					assert.False(t, ok)
+				} else {
+					assert.True(t, ok)
+					assert.Equal(t, *tt.wantClaims, claims)
+				}
+
+				return "ok", nil
+			}
+
+			resp, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, called)
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, "ok", resp)
+			}
+		})
+	}
+}
+
+func TestAuthorizationInterceptorAdminFailure(t *testing.T) {
+	authorization := Authorization{
+		Auth: staticAuth{
+			tokens:     map[string]jwt.Claims{"admin": {UserID: 2}},
+			isAdminErr: assert.AnError,
+		},
+		Policies: map[string]string{"/test.Service/Admin": config.PolicyAdmin},
+	}
+
+	interceptor := authorizationInterceptor(slog.New(slog.NewTextHandler(io.Discard, nil)), authorization)
+
+	_, err := interceptor(withToken(context.Background(), "admin"), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Admin"},
+		func(context.Context, any) (any, error) {
+			require.Fail(t, "handler must not be called")
+
+			return nil, nil
+		})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestAuthorizationInterceptorClientCerts(t *testing.T) {
+	authorization := Authorization{Auth: staticAuth{}, ClientCerts: true}
+	interceptor := authorizationInterceptor(slog.New(slog.NewTextHandler(io.Discard, nil)), authorization)
+	info := &grpc.UnaryServerInfo{FullMethod: ssov1.Admin_GetUser_FullMethodName}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	_, err := interceptor(withClientCert(context.Background(), "operator"), nil, info, handler)
+	require.NoError(t, err)
+
+	_, err = interceptor(context.Background(), nil, info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "AdminService requires client certificates")
+}
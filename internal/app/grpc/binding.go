@@ -0,0 +1,63 @@
+package grpcapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"grpc-service-ref/internal/config"
+	authv2grpc "grpc-service-ref/internal/grpc/authv2"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/dpop"
+	"grpc-service-ref/internal/lib/peer"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// clientFingerprint returns fingerprint of clients for the binding of refresh tokens, see
+// config.Config.RefreshTokenBinding, nil if tokens aren't bound. Fingerprints are prefixed with their source,
+// so tokens bound by one binding don't match clients after it's changed to another one.
+func clientFingerprint(binding string, clock clock.Clock) authv2grpc.Fingerprint {
+	switch binding {
+	case config.BindingUserAgent:
+		return func(ctx context.Context) (string, error) {
+			userAgent := firstMetadata(ctx, "user-agent")
+			if userAgent == "" {
+				return "", nil
+			}
+
+			sum := sha256.Sum256([]byte(userAgent))
+
+			return "ua:" + hex.EncodeToString(sum[:]), nil
+		}
+	case config.BindingClientCert:
+		return func(ctx context.Context) (string, error) {
+			fingerprint := peer.ClientCertFingerprint(ctx)
+			if fingerprint == "" {
+				return "", nil
+			}
+
+			return "cert:" + fingerprint, nil
+		}
+	case config.BindingDPoP:
+		return func(ctx context.Context) (string, error) {
+			proof := firstMetadata(ctx, "dpop")
+			if proof == "" {
+				return "", nil
+			}
+
+			method, _ := grpc.Method(ctx)
+
+			thumbprint, err := dpop.Verify(proof, method, clock.Now())
+			if err != nil {
+				return "", status.Error(codes.InvalidArgument, "invalid dpop proof")
+			}
+
+			return "jkt:" + thumbprint, nil
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package grpcapp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/config"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/dpop"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// methodStream is the transport stream of the RPC, grpc.Method reads the method from it.
+type methodStream struct {
+	grpc.ServerTransportStream
+	method string
+}
+
+func (s methodStream) Method() string {
+	return s.method
+}
+
+func withMetadata(ctx context.Context, kv ...string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(kv...))
+}
+
+// newProof returns DPoP proof for the method signed by a new key.
+func newProof(t *testing.T, method string) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"htm": dpop.Method, "htu": method, "iat": testNow.Unix()})
+	token.Header["typ"] = "dpop+jwt"
+	token.Header["jwk"] = map[string]any{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+
+	proof, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return proof
+}
+
+func TestClientFingerprint(t *testing.T) {
+	ctx := context.Background()
+	c := clock.NewFake(testNow)
+
+	assert.Nil(t, clientFingerprint("", c), "tokens aren't bound")
+
+	t.Run("user agent", func(t *testing.T) {
+		fingerprint := clientFingerprint(config.BindingUserAgent, c)
+		sum := sha256.Sum256([]byte("test-client/1.0"))
+
+		got, err := fingerprint(withMetadata(ctx, "user-agent", "test-client/1.0"))
+		require.NoError(t, err)
+		assert.Equal(t, "ua:"+hex.EncodeToString(sum[:]), got)
+
+		got, err = fingerprint(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("client certificate", func(t *testing.T) {
+		fingerprint := clientFingerprint(config.BindingClientCert, c)
+
+		got, err := fingerprint(withClientCert(ctx, "client"))
+		require.NoError(t, err)
+		assert.Regexp(t, "^cert:[0-9a-f]{64}$", got)
+
+		got, err = fingerprint(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("DPoP", func(t *testing.T) {
+		const method = "/auth.AuthV2/Refresh"
+
+		fingerprint := clientFingerprint(config.BindingDPoP, c)
+		rpcCtx := grpc.NewContextWithServerTransportStream(ctx, methodStream{method: method})
+
+		got, err := fingerprint(withMetadata(rpcCtx, "dpop", newProof(t, method)))
+		require.NoError(t, err)
+		assert.Regexp(t, "^jkt:[A-Za-z0-9_-]{43}$", got)
+
+		got, err = fingerprint(rpcCtx)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+
+		_, err = fingerprint(withMetadata(rpcCtx, "dpop", newProof(t, "/auth.AuthV2/Login")))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "proof of another RPC")
+
+		c.Advance(dpop.MaxAge + time.Second)
+		_, err = fingerprint(withMetadata(rpcCtx, "dpop", newProof(t, method)))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "stale proof")
+	})
+}
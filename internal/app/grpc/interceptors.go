@@ -0,0 +1,94 @@
+package grpcapp
+
+import (
+	"fmt"
+	"log/slog"
+
+	"grpc-service-ref/internal/config"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// interceptorDeps are dependencies interceptors are built with.
+type interceptorDeps struct {
+	log           *slog.Logger
+	ipFilter      IPFilter
+	geo           GeoIP
+	maintenance   Maintenance
+	sampling      RequestSampling
+	authorization Authorization
+}
+
+// interceptors builds interceptors by name. Nil interceptor is off for the server,
+// e.g. maintenance of the admin server.
+var interceptors = map[string]func(deps interceptorDeps) grpc.UnaryServerInterceptor{
+	config.InterceptorMetrics: func(interceptorDeps) grpc.UnaryServerInterceptor {
+		return metricsInterceptor
+	},
+	config.InterceptorRecovery: func(deps interceptorDeps) grpc.UnaryServerInterceptor {
+		return recovery.UnaryServerInterceptor(recovery.WithRecoveryHandler(func(p interface{}) (err error) {
+			deps.log.Error("Recovered from panic", slog.Any("panic", p))
+
+			return status.Errorf(codes.Internal, "internal error")
+		}))
+	},
+	config.InterceptorIPFilter: func(deps interceptorDeps) grpc.UnaryServerInterceptor {
+		return ipFilterInterceptor(deps.ipFilter)
+	},
+	config.InterceptorMaintenance: func(deps interceptorDeps) grpc.UnaryServerInterceptor {
+		if deps.maintenance == nil {
+			return nil
+		}
+
+		return maintenanceInterceptor(deps.log, deps.maintenance)
+	},
+	config.InterceptorGeoIP: func(deps interceptorDeps) grpc.UnaryServerInterceptor {
+		return geoIPInterceptor(deps.geo)
+	},
+	config.InterceptorAuditSampling: func(deps interceptorDeps) grpc.UnaryServerInterceptor {
+		if deps.sampling.Percent <= 0 {
+			return nil
+		}
+
+		return samplingInterceptor(deps.sampling)
+	},
+	config.InterceptorAuthorization: func(deps interceptorDeps) grpc.UnaryServerInterceptor {
+		if deps.authorization.Auth == nil {
+			return nil
+		}
+
+		return authorizationInterceptor(deps.log, deps.authorization)
+	},
+	config.InterceptorLogging: func(deps interceptorDeps) grpc.UnaryServerInterceptor {
+		return logging.UnaryServerInterceptor(InterceptorLogger(deps.log), logging.WithLogOnEvents(
+			//logging.StartCall, logging.FinishCall,
+			logging.PayloadReceived, logging.PayloadSent,
+		))
+	},
+}
+
+// mustChain returns interceptors of the names in order of the chain, empty names mean config.DefaultInterceptors.
+// Names are validated with config, so unknown name panics.
+func mustChain(names []string, deps interceptorDeps) []grpc.UnaryServerInterceptor {
+	if len(names) == 0 {
+		names = config.DefaultInterceptors
+	}
+
+	chain := make([]grpc.UnaryServerInterceptor, 0, len(names))
+	for _, name := range names {
+		build, ok := interceptors[name]
+		if !ok {
+			panic(fmt.Sprintf("unknown gRPC interceptor %q", name))
+		}
+
+		if interceptor := build(deps); interceptor != nil {
+			chain = append(chain, interceptor)
+		}
+	}
+
+	return chain
+}
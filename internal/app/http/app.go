@@ -0,0 +1,72 @@
+package httpapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"grpc-service-ref/internal/lib/logger/sl"
+)
+
+const readHeaderTimeout = 5 * time.Second
+
+type App struct {
+	log        *slog.Logger
+	httpServer *http.Server
+	port       int
+}
+
+// New creates new HTTP server app.
+func New(
+	log *slog.Logger,
+	port int,
+	handler http.Handler,
+) *App {
+	return &App{
+		log: log,
+		httpServer: &http.Server{
+			Addr:              fmt.Sprintf(":%d", port),
+			Handler:           handler,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+		port: port,
+	}
+}
+
+// MustRun runs HTTP server and panics if any error occurs.
+func (a *App) MustRun() {
+	if err := a.Run(); err != nil {
+		panic(err)
+	}
+}
+
+// Run runs HTTP server.
+func (a *App) Run() error {
+	const op = "httpapp.Run"
+
+	a.log.Info("http server started", slog.String("addr", a.httpServer.Addr))
+
+	if err := a.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Stop stops HTTP server, waiting for in-flight requests until ctx is done.
+func (a *App) Stop(ctx context.Context) {
+	const op = "httpapp.Stop"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("stopping HTTP server", slog.Int("port", a.port))
+
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		log.Error("failed to stop HTTP server gracefully", sl.Err(err))
+
+		_ = a.httpServer.Close()
+	}
+}
@@ -0,0 +1,139 @@
+package schedulerapp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+)
+
+// jitterFraction is a max share of the interval added to it at random,
+// so jobs of different replicas don't run simultaneously.
+const jitterFraction = 0.1
+
+// Job is a periodic task. A failed run is not retried until the next interval,
+// so Run should be idempotent.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Locks leases jobs, so replicas don't run the same job simultaneously.
+type Locks interface {
+	// Acquire reports whether the replica holds lease of the job for ttl.
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+}
+
+type App struct {
+	log   *slog.Logger
+	locks Locks
+	jobs  []Job
+}
+
+// New creates new scheduler app running the jobs. A job runs on the replica holding its lease for the interval,
+// so it runs once per interval however many replicas there are. Nil locks run jobs on every replica.
+func New(log *slog.Logger, locks Locks, jobs ...Job) *App {
+	return &App{
+		log:   log,
+		locks: locks,
+		jobs:  jobs,
+	}
+}
+
+// Run runs every job each interval until ctx is done, then waits for running jobs to finish.
+func (a *App) Run(ctx context.Context) {
+	const op = "schedulerapp.Run"
+
+	a.log.Info("scheduler started", slog.String("op", op), slog.Int("jobs", len(a.jobs)))
+
+	var wg sync.WaitGroup
+	for _, job := range a.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			a.schedule(ctx, job)
+		}(job)
+	}
+
+	wg.Wait()
+}
+
+func (a *App) schedule(ctx context.Context, job Job) {
+	for {
+		timer := time.NewTimer(withJitter(job.Interval))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+
+		// Run in progress is completed on shutdown, it's stopped between runs only.
+		a.run(context.WithoutCancel(ctx), job)
+	}
+}
+
+// run runs the job once, recording its result. Panic of the job is recovered and counted as failure.
+func (a *App) run(ctx context.Context, job Job) {
+	const op = "schedulerapp.run"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("job", job.Name),
+	)
+
+	if a.locks != nil {
+		acquired, err := a.locks.Acquire(ctx, job.Name, job.Interval)
+		if err != nil {
+			// Run is skipped, so storage outage doesn't make every replica run the job.
+			log.Error("failed to acquire job lock", sl.Err(err))
+			metrics.JobRuns.WithLabelValues(job.Name, metrics.JobFailed).Inc()
+
+			return
+		}
+
+		if !acquired {
+			log.Debug("job is run by another replica")
+			metrics.JobRuns.WithLabelValues(job.Name, metrics.JobSkipped).Inc()
+
+			return
+		}
+	}
+
+	start := time.Now()
+
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+		}()
+
+		return job.Run(ctx)
+	}()
+
+	metrics.JobDuration.WithLabelValues(job.Name).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		log.Error("job failed", sl.Err(err))
+		metrics.JobRuns.WithLabelValues(job.Name, metrics.JobFailed).Inc()
+
+		return
+	}
+
+	log.Debug("job succeeded", slog.Duration("duration", time.Since(start)))
+	metrics.JobRuns.WithLabelValues(job.Name, metrics.JobSucceeded).Inc()
+	metrics.JobLastSuccess.WithLabelValues(job.Name).SetToCurrentTime()
+}
+
+func withJitter(interval time.Duration) time.Duration {
+	return interval + time.Duration(rand.Int63n(int64(float64(interval)*jitterFraction)+1))
+}
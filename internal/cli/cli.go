@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"grpc-service-ref/internal/config"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// CLI registers flags shared by all commands: --config and --set.
+// Commands register their own flags on FlagSet before Parse,
+// flags are parsed exactly once.
+type CLI struct {
+	fs         *flag.FlagSet
+	configPath string
+	overrides  overrides
+}
+
+func New(name string) *CLI {
+	c := &CLI{
+		fs:        flag.NewFlagSet(name, flag.ExitOnError),
+		overrides: make(overrides),
+	}
+
+	c.fs.StringVar(&c.configPath, "config", "", "path to config file, optional if config is set by env variables")
+	c.fs.Var(c.overrides, "set", "override config value, e.g. --set grpc.port=44045, may be repeated")
+	c.fs.Usage = cleanenv.FUsage(c.fs.Output(), &config.Config{}, nil, c.defaultUsage)
+
+	return c
+}
+
+// FlagSet returns flag set to register command specific flags on.
+func (c *CLI) FlagSet() *flag.FlagSet {
+	return c.fs
+}
+
+// Parse parses command line arguments.
+func (c *CLI) Parse(args []string) {
+	// ExitOnError flag set never returns an error.
+	_ = c.fs.Parse(args)
+
+	if c.configPath == "" {
+		c.configPath = os.Getenv("CONFIG_PATH")
+	}
+}
+
+// ConfigPath returns config path from --config flag or CONFIG_PATH env, empty if config is set by env only.
+func (c *CLI) ConfigPath() string {
+	return c.configPath
+}
+
+// Overrides returns config values set by --set flags keyed by yaml path.
+func (c *CLI) Overrides() map[string]string {
+	return c.overrides
+}
+
+// MustLoadConfig loads and validates config, panics listing all problems found.
+func (c *CLI) MustLoadConfig() *config.Config {
+	cfg, err := c.LoadConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		panic("invalid config:\n" + err.Error())
+	}
+
+	return cfg
+}
+
+// LoadConfig loads config without validation, used to reload config and by commands needing part of it.
+func (c *CLI) LoadConfig() (*config.Config, error) {
+	return config.Load(c.configPath, c.overrides)
+}
+
+func (c *CLI) defaultUsage() {
+	fmt.Fprintf(c.fs.Output(), "Usage of %s:\n", c.fs.Name())
+	c.fs.PrintDefaults()
+}
+
+// overrides is a repeatable key=value flag.
+type overrides map[string]string
+
+func (o overrides) String() string {
+	pairs := make([]string, 0, len(o))
+	for k, v := range o {
+		pairs = append(pairs, k+"="+v)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (o overrides) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("override must be key=value, got %q", s)
+	}
+
+	o[key] = value
+
+	return nil
+}
@@ -1,75 +1,798 @@
 package config
 
 import (
-	"flag"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"gopkg.in/yaml.v3"
 )
 
+// Config of the service.
+// Only fields listed in reload.go are applied on SIGHUP, others require restart.
 type Config struct {
-	Env            string             `yaml:"env" env-default:"local"`
-	StoragePath    string             `yaml:"storage_path" env-required:"true"`
-	GRPC           GRPCConfig         `yaml:"grpc"`
-	EmailService   EmailSenderConfig  `yaml:"emailSender"`
-	Verification   VerificationConfig `yaml:"verification"`
-	MigrationsPath string             `yaml:"migrations_path"`
-	TokenTTL       time.Duration      `yaml:"token_ttl" env-default:"1h"`
+	Env string `yaml:"env" env:"SSO_ENV" env-default:"local"`
+	// LogLevel is debug, info, warn or error, empty means default level of the env.
+	LogLevel string `yaml:"log_level" env:"SSO_LOG_LEVEL"`
+	// LogFormat is json, text or pretty, empty means pretty for local env and json otherwise.
+	LogFormat string `yaml:"log_format" env:"SSO_LOG_FORMAT"`
+	// LogPII is how emails, IPs and other personal data are logged: plain, hash or redact.
+	// Empty means hash in prod env and plain otherwise, plain is not allowed in prod.
+	// Secrets like codes and request payloads are never logged unless it's plain.
+	LogPII               string                     `yaml:"log_pii" env:"SSO_LOG_PII"`
+	StoragePath          string                     `yaml:"storage_path" env:"SSO_STORAGE_PATH" env-required:"true"`
+	SQLitePool           DBPoolConfig               `yaml:"sqlite_pool" env-prefix:"SSO_SQLITE_POOL_"`
+	GRPC                 GRPCConfig                 `yaml:"grpc"`
+	HTTP                 HTTPConfig                 `yaml:"http"`
+	Ops                  OpsConfig                  `yaml:"ops"`
+	Admin                AdminConfig                `yaml:"admin"`
+	EmailService         EmailSenderConfig          `yaml:"emailSender"`
+	SMSService           SMSSenderConfig            `yaml:"smsSender"`
+	Verification         VerificationConfig         `yaml:"verification"`
+	Registration         RegistrationConfig         `yaml:"registration"`
+	Login                LoginConfig                `yaml:"login"`
+	Captcha              CaptchaConfig              `yaml:"captcha"`
+	RateLimit            RateLimitConfig            `yaml:"rate_limit"`
+	AntiEnumeration      AntiEnumerationConfig      `yaml:"anti_enumeration"`
+	IPFilter             IPFilterConfig             `yaml:"ip_filter"`
+	Cache                CacheConfig                `yaml:"cache"`
+	Redis                RedisConfig                `yaml:"redis"`
+	Audit                AuditConfig                `yaml:"audit"`
+	Password             PasswordConfig             `yaml:"password"`
+	Encryption           EncryptionConfig           `yaml:"encryption"`
+	Vault                VaultConfig                `yaml:"vault"`
+	Secrets              SecretsConfig              `yaml:"secrets"`
+	Features             FeaturesConfig             `yaml:"features"`
+	Tracing              TracingConfig              `yaml:"tracing"`
+	Webhooks             WebhooksConfig             `yaml:"webhooks"`
+	Organizations        OrganizationsConfig        `yaml:"organizations"`
+	ServiceAccounts      ServiceAccountsConfig      `yaml:"service_accounts"`
+	AccountDeletion      AccountDeletionConfig      `yaml:"account_deletion"`
+	DeviceLogin          DeviceLoginConfig          `yaml:"device_login"`
+	VerificationReminder VerificationReminderConfig `yaml:"verification_reminder"`
+	GeoIP                GeoIPConfig                `yaml:"geoip"`
+	Maintenance          MaintenanceConfig          `yaml:"maintenance"`
+	Directories          DirectoryConfigs           `yaml:"directories" env:"SSO_DIRECTORIES"`
+	Scheduler            SchedulerConfig            `yaml:"scheduler"`
+	MigrationsPath       string                     `yaml:"migrations_path" env:"SSO_MIGRATIONS_PATH"`
+	TokenTTL             time.Duration              `yaml:"token_ttl" env:"SSO_TOKEN_TTL" env-default:"1h"`
+	TokenClaims          TokenClaimsConfig          `yaml:"token_claims"`
+	TokenSigning         TokenSigningConfig         `yaml:"token_signing"`
+	// ElevatedTokenTTL is lifetime of one-time tokens issued for sensitive actions.
+	ElevatedTokenTTL time.Duration `yaml:"elevated_token_ttl" env:"SSO_ELEVATED_TOKEN_TTL" env-default:"5m"`
+	// SSOSessionTTL is how long after login the user gets tokens for other apps by Authorize without credentials,
+	// 0 disables SSO sessions.
+	SSOSessionTTL time.Duration `yaml:"sso_session_ttl" env:"SSO_SSO_SESSION_TTL" env-default:"12h"`
+	// RefreshTokenTTL is lifetime of refresh tokens issued by Login of the v2 API, 0 disables refresh tokens.
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env:"SSO_REFRESH_TOKEN_TTL" env-default:"720h"`
+	// RefreshTokenBinding binds refresh tokens to the client they are issued to: user_agent, client_cert or dpop.
+	// Empty doesn't bind them, tokens issued while binding was off stay unbound.
+	RefreshTokenBinding string `yaml:"refresh_token_binding" env:"SSO_REFRESH_TOKEN_BINDING"`
+	// ShutdownTimeout is how long in-flight requests are drained on SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SSO_SHUTDOWN_TIMEOUT" env-default:"30s"`
+}
+
+// TokenClaimsConfig configures iss and aud claims of all tokens, apps may override them.
+// Introspection rejects tokens without the configured claims, so tokens of another deployment,
+// e.g. staging, aren't accepted. Empty claims are neither issued nor checked.
+type TokenClaimsConfig struct {
+	Issuer   string `yaml:"issuer" env:"SSO_TOKEN_CLAIMS_ISSUER"`
+	Audience string `yaml:"audience" env:"SSO_TOKEN_CLAIMS_AUDIENCE"`
+}
+
+// TokenSigningConfig configures the key tokens are signed with. With KeyFile set tokens of all apps are signed
+// by ES256 and the public key is served as JWKS by the HTTP server, otherwise by HS256 with secrets of apps.
+type TokenSigningConfig struct {
+	// KeyFile is PEM encoded P-256 private key.
+	KeyFile string `yaml:"key_file" env:"SSO_TOKEN_SIGNING_KEY_FILE"`
+}
+
+// DBPoolConfig configures connection pool of a storage backend, zero MaxOpenConns and ConnMaxLifetime mean no limit.
+type DBPoolConfig struct {
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"MAX_IDLE_CONNS" env-default:"2"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"CONN_MAX_LIFETIME"`
 }
 
 type GRPCConfig struct {
-	Port    int           `yaml:"port"`
-	Timeout time.Duration `yaml:"timeout"`
+	Port    int           `yaml:"port" env:"SSO_GRPC_PORT"`
+	Timeout time.Duration `yaml:"timeout" env:"SSO_GRPC_TIMEOUT"`
+	// Interceptors are names of interceptors of the public and admin servers in order of the chain,
+	// interceptors left out are off, except authorization which is required. Empty means DefaultInterceptors.
+	Interceptors []string `yaml:"interceptors" env:"SSO_GRPC_INTERCEPTORS"`
+	// Authorization are policies of RPCs keyed by full method name, e.g. /auth.Auth/IsAdmin,
+	// or by service prefix ending with "/", e.g. /auth.Admin/. They override policies defined in code.
+	// In env it's set as YAML or JSON, e.g. {/auth.Admin/: mtls}.
+	Authorization AuthorizationPolicies `yaml:"authorization" env:"SSO_GRPC_AUTHORIZATION"`
+}
+
+// AuthorizationPolicies are policies keyed by method.
+type AuthorizationPolicies map[string]string
+
+// SetValue parses policies set by env variable.
+func (p *AuthorizationPolicies) SetValue(s string) error {
+	return yaml.Unmarshal([]byte(s), p)
 }
 
+// Authorization policies of RPCs.
+const (
+	// PolicyAnonymous lets anyone call the RPC, its handler may still authenticate the caller.
+	PolicyAnonymous = "anonymous"
+	// PolicyAuthenticated requires access token of a user or a service account.
+	PolicyAuthenticated = "authenticated"
+	// PolicyAdmin requires access token of an admin user.
+	PolicyAdmin = "role:admin"
+	// PolicyMTLS requires verified client certificate.
+	PolicyMTLS = "mtls"
+)
+
+// Policies are the known authorization policies.
+var Policies = []string{PolicyAnonymous, PolicyAuthenticated, PolicyAdmin, PolicyMTLS}
+
+// Bindings of refresh tokens to clients.
+const (
+	// BindingUserAgent binds refresh tokens to user-agent of the client, it only stops thieves unaware of it.
+	BindingUserAgent = "user_agent"
+	// BindingClientCert binds refresh tokens to verified client certificate.
+	BindingClientCert = "client_cert"
+	// BindingDPoP binds refresh tokens to the key of DPoP proofs sent by the client in dpop metadata.
+	BindingDPoP = "dpop"
+)
+
+// Bindings are the known bindings of refresh tokens, empty means none.
+var Bindings = []string{"", BindingUserAgent, BindingClientCert, BindingDPoP}
+
+// Names of gRPC interceptors.
+const (
+	// InterceptorMetrics counts RPCs and measures their duration. Before recovery, it counts panics as Internal.
+	InterceptorMetrics = "metrics"
+	// InterceptorRecovery turns panics of interceptors after it and of handlers into Internal errors.
+	InterceptorRecovery = "recovery"
+	// InterceptorIPFilter rejects RPCs of denied client IPs.
+	InterceptorIPFilter = "ip_filter"
+	// InterceptorMaintenance rejects RPCs during maintenance, it's off on the admin server.
+	InterceptorMaintenance = "maintenance"
+	// InterceptorGeoIP resolves location of the client IP, interceptors and handlers after it see the location.
+	InterceptorGeoIP = "geoip"
+	// InterceptorAuditSampling records sampled RPCs to the audit log, it's off unless audit.request_sample_percent is set.
+	InterceptorAuditSampling = "audit_sampling"
+	// InterceptorAuthorization enforces authorization policies of RPCs, handlers after it see claims of the caller.
+	// After sampling, so rejected attempts are sampled too.
+	InterceptorAuthorization = "authorization"
+	// InterceptorLogging logs requests and responses.
+	InterceptorLogging = "logging"
+)
+
+// DefaultInterceptors is the interceptor chain unless GRPCConfig.Interceptors is set.
+var DefaultInterceptors = []string{
+	InterceptorMetrics,
+	InterceptorRecovery,
+	InterceptorIPFilter,
+	InterceptorMaintenance,
+	InterceptorGeoIP,
+	InterceptorAuditSampling,
+	InterceptorAuthorization,
+	InterceptorLogging,
+}
+
+type HTTPConfig struct {
+	Port        int               `yaml:"port" env:"SSO_HTTP_PORT"`
+	ForwardAuth ForwardAuthConfig `yaml:"forward_auth"`
+	TokenReview TokenReviewConfig `yaml:"token_review"`
+	Sessions    SessionsConfig    `yaml:"sessions"`
+}
+
+// ForwardAuthConfig configures endpoint for nginx auth_request and Traefik ForwardAuth,
+// which lets reverse proxy admit requests to upstream apps by access tokens issued by SSO.
+type ForwardAuthConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_HTTP_FORWARD_AUTH_ENABLED"`
+	// CookieName is the cookie access token is read from if there is no bearer token.
+	CookieName string `yaml:"cookie_name" env:"SSO_HTTP_FORWARD_AUTH_COOKIE_NAME" env-default:"sso_token"`
+}
+
+// TokenReviewConfig configures Kubernetes authentication webhook, which lets clusters authenticate users
+// by access tokens issued for the app.
+type TokenReviewConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_HTTP_TOKEN_REVIEW_ENABLED"`
+	// AppID is the app representing clusters, tokens of other apps are rejected.
+	AppID int `yaml:"app_id" env:"SSO_HTTP_TOKEN_REVIEW_APP_ID"`
+}
+
+// SessionsConfig configures cookie sessions of browser frontends: /auth/session exchanges the access token
+// issued by Login for session cookie ForwardAuth.CookieName, which /auth/verify accepts with CSRF protection.
+type SessionsConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_HTTP_SESSIONS_ENABLED"`
+	// SameSite of the cookies: lax, strict or none.
+	SameSite string `yaml:"same_site" env:"SSO_HTTP_SESSIONS_SAME_SITE" env-default:"lax"`
+	// Domain of the cookies, empty means the host of the HTTP server only.
+	Domain string `yaml:"domain" env:"SSO_HTTP_SESSIONS_DOMAIN"`
+	// Insecure drops Secure attribute of the cookies, for development over plain HTTP in local env only.
+	Insecure bool `yaml:"insecure" env:"SSO_HTTP_SESSIONS_INSECURE"`
+}
+
+// Values of SessionsConfig.SameSite.
+const (
+	SameSiteLax    = "lax"
+	SameSiteStrict = "strict"
+	SameSiteNone   = "none"
+)
+
+// OpsConfig configures HTTP server of liveness, readiness and version endpoints.
+type OpsConfig struct {
+	Port int `yaml:"port" env:"SSO_OPS_PORT" env-default:"8083"`
+	// Debug enables pprof and expvar endpoints under /debug/, they are served to localhost only.
+	Debug bool `yaml:"debug" env:"SSO_OPS_DEBUG"`
+}
+
+// AdminConfig configures gRPC server of AdminService: users, apps, suppressions and audit log management.
+// It's disabled if Port is 0, it should be reachable from operators' network only.
+type AdminConfig struct {
+	// Host is the address the server listens on, loopback by default. Set it to 0.0.0.0 or an interface
+	// address to reach the server from operators' network, outside local env TLS.ClientCAFile is required then.
+	Host string    `yaml:"host" env:"SSO_ADMIN_HOST" env-default:"127.0.0.1"`
+	Port int       `yaml:"port" env:"SSO_ADMIN_PORT"`
+	TLS  TLSConfig `yaml:"tls" env-prefix:"SSO_ADMIN_TLS_"`
+}
+
+// TLSConfig enables TLS of a server if CertFile and KeyFile are set.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file" env:"CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"KEY_FILE"`
+	// ClientCAFile enables mTLS: clients must present certificates signed by its CAs.
+	ClientCAFile string `yaml:"client_ca_file" env:"CLIENT_CA_FILE"`
+}
+
+// AuditConfig configures how audit events are saved.
+type AuditConfig struct {
+	// Async saves events in background by batches, so audited actions don't wait for inserts.
+	Async         bool          `yaml:"async" env:"SSO_AUDIT_ASYNC" env-default:"true"`
+	BufferSize    int           `yaml:"buffer_size" env:"SSO_AUDIT_BUFFER_SIZE" env-default:"1024"`
+	BatchSize     int           `yaml:"batch_size" env:"SSO_AUDIT_BATCH_SIZE" env-default:"100"`
+	FlushInterval time.Duration `yaml:"flush_interval" env:"SSO_AUDIT_FLUSH_INTERVAL" env-default:"1s"`
+	// Overflow is what happens to events while the buffer is full: block waits for space
+	// until the request deadline, drop loses them. Lost events are counted by metrics either way.
+	Overflow string `yaml:"overflow" env:"SSO_AUDIT_OVERFLOW" env-default:"block"`
+	// RequestSamplePercent is the percentage of RPCs, from 0 to 100, whose method, caller, latency
+	// and status code are recorded as request_sampled events. Zero disables sampling.
+	RequestSamplePercent float64 `yaml:"request_sample_percent" env:"SSO_AUDIT_REQUEST_SAMPLE_PERCENT"`
+}
+
+// PasswordConfig configures hashing and strength policy of passwords. Changed cost and min strength apply
+// to new passwords only, existing passwords keep their cost until they are changed.
+type PasswordConfig struct {
+	// Cost is bcrypt cost of password hashes, the minimal one if calibration is enabled.
+	Cost int `yaml:"cost" env:"SSO_PASSWORD_COST" env-default:"10"`
+	// CalibrateTarget enables calibration on startup: the highest cost not below Cost whose hashing
+	// takes at most this long on the host is used. Zero disables calibration.
+	CalibrateTarget time.Duration `yaml:"calibrate_target" env:"SSO_PASSWORD_CALIBRATE_TARGET"`
+	// MinStrength is the lowest accepted strength score of new passwords, from 0 (any password)
+	// to 4 (very unguessable), see CheckPasswordStrength.
+	MinStrength int `yaml:"min_strength" env:"SSO_PASSWORD_MIN_STRENGTH" env-default:"2"`
+	// RevokeSessions signs the user out everywhere on password reset, and on other devices on password change.
+	RevokeSessions bool `yaml:"revoke_sessions" env:"SSO_PASSWORD_REVOKE_SESSIONS" env-default:"true"`
+}
+
+// CacheConfig configures in-process caches of storage reads, zero TTL disables a cache.
+// Caches are invalidated on writes made by this instance, other replicas see changes after TTL
+// unless invalidations are published to them.
+type CacheConfig struct {
+	// AppsTTL is how long apps with their secrets are cached, and their origins allowed by the HTTP server.
+	AppsTTL time.Duration `yaml:"apps_ttl" env:"SSO_CACHE_APPS_TTL" env-default:"1m"`
+	// UsersTTL is how long users are cached by email for login.
+	UsersTTL time.Duration `yaml:"users_ttl" env:"SSO_CACHE_USERS_TTL"`
+	// AdminsTTL is how long admin roles are cached by user ID, it bounds how long revoked role is seen by replicas.
+	AdminsTTL time.Duration `yaml:"admins_ttl" env:"SSO_CACHE_ADMINS_TTL" env-default:"10s"`
+	// StatsTTL is how long login and registration stats of AdminService are cached.
+	StatsTTL time.Duration `yaml:"stats_ttl" env:"SSO_CACHE_STATS_TTL" env-default:"5m"`
+	// Invalidations is local, or redis to publish invalidations of apps, users and admin roles
+	// to other replicas by Redis.
+	Invalidations string `yaml:"invalidations" env:"SSO_CACHE_INVALIDATIONS" env-default:"local"`
+}
+
+// Values of CacheConfig.Invalidations.
+const (
+	CacheInvalidationsLocal = "local"
+	CacheInvalidationsRedis = "redis"
+)
+
 type EmailSenderConfig struct {
-	Name     string `yaml:"name"`
-	Email    string `yaml:"email"`
-	Password string `yaml:"password"`
+	Name     string              `yaml:"name" env:"SSO_EMAIL_NAME"`
+	Email    string              `yaml:"email" env:"SSO_EMAIL_EMAIL"`
+	Password string              `yaml:"password" env:"SSO_EMAIL_PASSWORD"`
+	Webhooks EmailWebhooksConfig `yaml:"webhooks"`
+	DKIM     DKIMConfig          `yaml:"dkim"`
+	// Workers is number of emails sent concurrently, others wait in queue.
+	Workers int `yaml:"workers" env:"SSO_EMAIL_WORKERS" env-default:"4"`
+	// BatchSize is how many queued emails a worker sends over one provider connection.
+	BatchSize int `yaml:"batch_size" env:"SSO_EMAIL_BATCH_SIZE" env-default:"10"`
+}
+
+// FeaturesConfig enables optional subsystems, so they can be rolled out gradually.
+type FeaturesConfig struct {
+	// PhoneVerification enables CreatePhoneVerification and VerifyPhone RPCs.
+	PhoneVerification bool `yaml:"phone_verification" env:"SSO_FEATURES_PHONE_VERIFICATION" env-default:"true"`
+	MFA               bool `yaml:"mfa" env:"SSO_FEATURES_MFA"`
+	MagicLinks        bool `yaml:"magic_links" env:"SSO_FEATURES_MAGIC_LINKS"`
+	SocialLogin       bool `yaml:"social_login" env:"SSO_FEATURES_SOCIAL_LOGIN"`
+	RESTGateway       bool `yaml:"rest_gateway" env:"SSO_FEATURES_REST_GATEWAY"`
+}
+
+// WebhooksConfig configures delivery of events to webhooks of apps.
+// Failed deliveries are retried with exponential backoff, after MaxAttempts they are dead until replayed.
+type WebhooksConfig struct {
+	MaxAttempts    int           `yaml:"max_attempts" env:"SSO_WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"SSO_WEBHOOKS_INITIAL_BACKOFF" env-default:"30s"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"SSO_WEBHOOKS_MAX_BACKOFF" env-default:"1h"`
+	// Timeout of a single delivery request.
+	Timeout time.Duration `yaml:"timeout" env:"SSO_WEBHOOKS_TIMEOUT" env-default:"10s"`
+	// PollInterval is how often due deliveries are looked up.
+	PollInterval time.Duration `yaml:"poll_interval" env:"SSO_WEBHOOKS_POLL_INTERVAL" env-default:"5s"`
+}
+
+// OrganizationsConfig configures organizations users are invited to by email.
+type OrganizationsConfig struct {
+	// InvitationTTL is how long the code of an invitation is accepted.
+	InvitationTTL time.Duration `yaml:"invitation_ttl" env:"SSO_ORGANIZATIONS_INVITATION_TTL" env-default:"168h"`
+}
+
+// ServiceAccountsConfig configures tokens of service accounts.
+type ServiceAccountsConfig struct {
+	TokenTTL time.Duration `yaml:"token_ttl" env:"SSO_SERVICE_ACCOUNTS_TOKEN_TTL" env-default:"1h"`
+	// AssertionAudience is aud claim JWT assertions of service accounts must have.
+	AssertionAudience string `yaml:"assertion_audience" env:"SSO_SERVICE_ACCOUNTS_ASSERTION_AUDIENCE" env-default:"sso"`
+}
+
+// AccountDeletionConfig configures deletion of accounts requested by their users.
+type AccountDeletionConfig struct {
+	// GracePeriod is how long after confirmation the account is deleted, signing in meanwhile cancels deletion.
+	GracePeriod time.Duration `yaml:"grace_period" env:"SSO_ACCOUNT_DELETION_GRACE_PERIOD" env-default:"720h"`
+}
+
+// DeviceLoginConfig configures login of devices, e.g. TVs and kiosks, approved from another signed in device.
+type DeviceLoginConfig struct {
+	// TTL is how long a login waits for approval.
+	TTL time.Duration `yaml:"ttl" env:"SSO_DEVICE_LOGIN_TTL" env-default:"10m"`
+	// PollInterval is how often devices are told to poll for their tokens.
+	PollInterval time.Duration `yaml:"poll_interval" env:"SSO_DEVICE_LOGIN_POLL_INTERVAL" env-default:"5s"`
+	// VerificationURI is the page of an app approving logins, devices encode it with the pairing code as QR code.
+	// Empty means devices show the pairing code only.
+	VerificationURI string `yaml:"verification_uri" env:"SSO_DEVICE_LOGIN_VERIFICATION_URI"`
+}
+
+// VerificationReminderConfig configures emails reminding unverified users to verify their email,
+// sent by a scheduled job with a fresh registration verification code.
+type VerificationReminderConfig struct {
+	// After is how long after registration users get the first reminder.
+	After time.Duration `yaml:"after" env:"SSO_VERIFICATION_REMINDER_AFTER" env-default:"72h"`
+	// Interval is the least time between reminders to the same user.
+	Interval time.Duration `yaml:"interval" env:"SSO_VERIFICATION_REMINDER_INTERVAL" env-default:"168h"`
+	// MaxReminders is how many reminders a user gets at most, 0 disables reminders.
+	MaxReminders int `yaml:"max_reminders" env:"SSO_VERIFICATION_REMINDER_MAX_REMINDERS" env-default:"2"`
+}
+
+// GeoIPConfig configures resolving locations of client IPs, which sessions, known devices and audit events record.
+type GeoIPConfig struct {
+	// DatabasePath is path to MaxMind DB file, e.g. GeoLite2-City.mmdb, empty disables GeoIP.
+	DatabasePath string `yaml:"database_path" env:"SSO_GEOIP_DATABASE_PATH"`
+}
+
+// MaintenanceConfig configures maintenance windows, the public gRPC server rejects RPCs with Unavailable
+// during them. Windows are turned on by Enabled or at runtime by SetMaintenance of AdminService.
+type MaintenanceConfig struct {
+	// Enabled puts all apps under maintenance until it's turned off.
+	Enabled bool `yaml:"enabled" env:"SSO_MAINTENANCE_ENABLED"`
+	// Message is shown to clients during maintenance turned on by Enabled.
+	Message string `yaml:"message" env:"SSO_MAINTENANCE_MESSAGE"`
+	// RetryAfter is the retry delay told to clients if the window has no end.
+	RetryAfter time.Duration `yaml:"retry_after" env:"SSO_MAINTENANCE_RETRY_AFTER" env-default:"1m"`
+	// WindowsTTL is how long windows turned on at runtime are cached, replicas pick them up within it.
+	WindowsTTL time.Duration `yaml:"windows_ttl" env:"SSO_MAINTENANCE_WINDOWS_TTL" env-default:"10s"`
+}
+
+// DirectoryConfigs are external user directories. In env they are set as YAML or JSON,
+// e.g. [{name: corp, url: https://directory.example.com/authenticate, apps: [2]}].
+type DirectoryConfigs []DirectoryConfig
+
+// SetValue parses directories set by env variable.
+func (c *DirectoryConfigs) SetValue(s string) error {
+	return yaml.Unmarshal([]byte(s), c)
+}
+
+// DirectoryConfig configures an external user directory, which authenticates users of the apps
+// and users with emails in the domains instead of local passwords. Apps take precedence over domains.
+type DirectoryConfig struct {
+	// Name identifies the directory, users it authenticated are bound to it by name, so it must not change.
+	Name string `yaml:"name"`
+	// URL is endpoint credentials are posted to, see httpdirectory.Client.
+	URL string `yaml:"url"`
+	// Token is sent as a bearer token, empty means requests are not authenticated.
+	Token   string        `yaml:"token"`
+	Timeout time.Duration `yaml:"timeout"`
+	Apps    []int         `yaml:"apps"`
+	Domains []string      `yaml:"domains"`
+}
+
+// SchedulerConfig configures periodic background jobs.
+type SchedulerConfig struct {
+	// CleanupInterval is how often expired verifications and pending registrations are deleted.
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"SSO_SCHEDULER_CLEANUP_INTERVAL" env-default:"1h"`
+}
+
+// TracingConfig configures exporting of OpenTelemetry traces by OTLP over gRPC.
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_TRACING_ENABLED"`
+	// Endpoint is host:port of OTLP collector.
+	Endpoint string `yaml:"endpoint" env:"SSO_TRACING_ENDPOINT,OTEL_EXPORTER_OTLP_ENDPOINT" env-default:"localhost:4317"`
+	// Insecure disables TLS of connection to the collector.
+	Insecure bool `yaml:"insecure" env:"SSO_TRACING_INSECURE"`
+	// SampleRatio is a share of traces recorded, from 0 to 1.
+	// Traces started by callers are sampled as callers decided.
+	SampleRatio float64 `yaml:"sample_ratio" env:"SSO_TRACING_SAMPLE_RATIO" env-default:"1"`
+	ServiceName string  `yaml:"service_name" env:"SSO_TRACING_SERVICE_NAME,OTEL_SERVICE_NAME" env-default:"sso"`
+}
+
+// SMSSenderConfig configures Twilio SMS delivery.
+// Phone verification is unavailable outside of local env if AccountSID is empty.
+type SMSSenderConfig struct {
+	AccountSID string        `yaml:"account_sid" env:"SSO_SMS_ACCOUNT_SID"`
+	AuthToken  string        `yaml:"auth_token" env:"SSO_SMS_AUTH_TOKEN"`
+	From       string        `yaml:"from" env:"SSO_SMS_FROM"`
+	Timeout    time.Duration `yaml:"timeout" env:"SSO_SMS_TIMEOUT" env-default:"10s"`
+}
+
+// SecretsConfig configures secret managers.
+// Secret fields (storage_path, emailSender.password, smsSender.auth_token, captcha.secret, encryption.key,
+// redis.password) may reference secrets by URI resolved at load time:
+//   - vault://<path>#<key>, e.g. vault://sso/email#password
+//   - aws-sm://<secret-id>[#<json-key>], e.g. aws-sm://sso/email-password
+//   - gcp-sm://<project>/<secret>[/<version>][#<json-key>]
+type SecretsConfig struct {
+	// AWSRegion of AWS Secrets Manager, empty means default region of the environment.
+	AWSRegion string `yaml:"aws_region" env:"SSO_SECRETS_AWS_REGION"`
+	// Timeout of GCP Secret Manager requests.
+	Timeout time.Duration `yaml:"timeout" env:"SSO_SECRETS_TIMEOUT" env-default:"10s"`
+}
+
+// VaultConfig configures sourcing secrets from HashiCorp Vault, it's disabled if Address is empty.
+type VaultConfig struct {
+	Address string `yaml:"address" env:"SSO_VAULT_ADDRESS,VAULT_ADDR"`
+	// AuthMethod is token or approle.
+	AuthMethod string `yaml:"auth_method" env:"SSO_VAULT_AUTH_METHOD" env-default:"token"`
+	Token      string `yaml:"token" env:"SSO_VAULT_TOKEN,VAULT_TOKEN"`
+	RoleID     string `yaml:"role_id" env:"SSO_VAULT_ROLE_ID"`
+	SecretID   string `yaml:"secret_id" env:"SSO_VAULT_SECRET_ID,VAULT_SECRET_ID"`
+	// Mount is a path KV v2 secrets engine is mounted at.
+	Mount string `yaml:"mount" env:"SSO_VAULT_MOUNT" env-default:"secret"`
+	// AppSecretsPath is a secret with app secrets keyed by app ID, empty means app secrets are taken from db.
+	AppSecretsPath string        `yaml:"app_secrets_path" env:"SSO_VAULT_APP_SECRETS_PATH"`
+	Timeout        time.Duration `yaml:"timeout" env:"SSO_VAULT_TIMEOUT" env-default:"10s"`
+}
+
+// DKIMConfig configures DKIM signing of outgoing emails.
+// Signing is disabled if PrivateKeyPath is empty.
+type DKIMConfig struct {
+	Domain         string `yaml:"domain" env:"SSO_EMAIL_DKIM_DOMAIN"`
+	Selector       string `yaml:"selector" env:"SSO_EMAIL_DKIM_SELECTOR"`
+	PrivateKeyPath string `yaml:"private_key_path" env:"SSO_EMAIL_DKIM_PRIVATE_KEY_PATH"`
+}
+
+// EmailWebhooksConfig configures bounce and complaint webhooks of email providers.
+type EmailWebhooksConfig struct {
+	// SNSTopicARN restricts SES notifications to the given SNS topic.
+	SNSTopicARN string `yaml:"sns_topic_arn" env:"SSO_EMAIL_WEBHOOKS_SNS_TOPIC_ARN"`
+	// SendGridPublicKey is base64 encoded verification key of SendGrid signed event webhook.
+	SendGridPublicKey string `yaml:"sendgrid_public_key" env:"SSO_EMAIL_WEBHOOKS_SENDGRID_PUBLIC_KEY"`
+}
+
+const (
+	LogFormatJSON   = "json"
+	LogFormatText   = "text"
+	LogFormatPretty = "pretty"
+)
+
+// Values of LogPII.
+const (
+	LogPIIPlain  = "plain"
+	LogPIIHash   = "hash"
+	LogPIIRedact = "redact"
+)
+
+const (
+	RegistrationModeImmediate = "immediate"
+	RegistrationModePending   = "pending"
+)
+
+type RegistrationConfig struct {
+	// Mode is "immediate" (user is created on Register) or "pending" (user is created once email is verified).
+	Mode string `yaml:"mode" env:"SSO_REGISTRATION_MODE" env-default:"immediate"`
+	// PendingTTL is how long pending registration blocks registering the same email again.
+	PendingTTL time.Duration `yaml:"pending_ttl" env:"SSO_REGISTRATION_PENDING_TTL" env-default:"24h"`
+}
+
+type LoginConfig struct {
+	// RequireVerified rejects login of users with unverified email for all apps.
+	// If false, it can still be required per app.
+	RequireVerified bool `yaml:"require_verified" env:"SSO_LOGIN_REQUIRE_VERIFIED"`
+	// Throttle slows down and locks out brute-force attempts after failed logins.
+	Throttle LoginThrottleConfig `yaml:"throttle"`
+	// NewDevice configures detection of logins from devices or countries new for the user.
+	NewDevice NewDeviceConfig `yaml:"new_device"`
+	// Risk configures risk evaluation of logins.
+	Risk RiskConfig `yaml:"risk"`
+	// Stuffing detects credential stuffing by failed login rates of all clients and of client IPs.
+	Stuffing StuffingConfig `yaml:"stuffing"`
+}
+
+// StuffingConfig configures detection of credential stuffing: attackers trying leaked credentials once per
+// account stay below per-account throttles, so failed logins are counted globally and per client IP per
+// Window. Once a rate exceeds its threshold, logins are challenged by captcha for Cooldown, or,
+// if captcha is disabled, logins of the IP are rejected and global detection halves IPThreshold. 0 thresholds
+// disable the scope.
+type StuffingConfig struct {
+	Enabled         bool          `yaml:"enabled" env:"SSO_LOGIN_STUFFING_ENABLED"`
+	Window          time.Duration `yaml:"window" env:"SSO_LOGIN_STUFFING_WINDOW" env-default:"1m"`
+	GlobalThreshold int           `yaml:"global_threshold" env:"SSO_LOGIN_STUFFING_GLOBAL_THRESHOLD" env-default:"500"`
+	IPThreshold     int           `yaml:"ip_threshold" env:"SSO_LOGIN_STUFFING_IP_THRESHOLD" env-default:"50"`
+	Cooldown        time.Duration `yaml:"cooldown" env:"SSO_LOGIN_STUFFING_COOLDOWN" env-default:"15m"`
+}
+
+// NewDeviceConfig configures what happens on login from a new device or country, detection is off if both are false.
+// Device is identified by x-device-id metadata or user agent, country by x-client-country metadata set by the fronting proxy.
+type NewDeviceConfig struct {
+	// Notify emails "new sign-in" notification to the user.
+	Notify bool `yaml:"notify" env:"SSO_LOGIN_NEW_DEVICE_NOTIFY" env-default:"true"`
+	// RequireConfirmation rejects login until it's repeated with code emailed to the user in x-sign-in-code metadata.
+	RequireConfirmation bool `yaml:"require_confirmation" env:"SSO_LOGIN_NEW_DEVICE_REQUIRE_CONFIRMATION"`
+}
+
+// RiskConfig configures scoring of logins by signals of account takeover: new devices, new countries
+// and impossible travel since the previous login. Scores are 0 to 100, risky logins are confirmed
+// by code emailed to the user like logins from new devices, or blocked.
+type RiskConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_LOGIN_RISK_ENABLED"`
+	// ChallengeScore requires confirmation of logins scored at least that, 0 disables it.
+	ChallengeScore int `yaml:"challenge_score" env:"SSO_LOGIN_RISK_CHALLENGE_SCORE" env-default:"50"`
+	// BlockScore rejects logins scored at least that and notifies the user, 0 disables it.
+	BlockScore int `yaml:"block_score" env:"SSO_LOGIN_RISK_BLOCK_SCORE" env-default:"90"`
+	// MaxTravelSpeed in km/h, travel between locations of logins faster than that is impossible.
+	MaxTravelSpeed float64 `yaml:"max_travel_speed" env:"SSO_LOGIN_RISK_MAX_TRAVEL_SPEED" env-default:"1000"`
+}
+
+// LoginThrottleConfig escalates throttling of failed logins per account and per client IP.
+// Account failures are reset on successful login, IP ones expire with Window.
+type LoginThrottleConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_LOGIN_THROTTLE_ENABLED" env-default:"true"`
+	// Window is how long failures are remembered since the last one.
+	Window  time.Duration        `yaml:"window" env:"SSO_LOGIN_THROTTLE_WINDOW" env-default:"1h"`
+	Account ThrottlePolicyConfig `yaml:"account" env-prefix:"SSO_LOGIN_THROTTLE_ACCOUNT_"`
+	IP      ThrottlePolicyConfig `yaml:"ip" env-prefix:"SSO_LOGIN_THROTTLE_IP_"`
+}
+
+// ThrottlePolicyConfig configures escalation steps by number of failures, 0 disables the step.
+// CaptchaAfter is ignored if captcha is disabled.
+type ThrottlePolicyConfig struct {
+	// DelayAfter failures, attempts are rejected until delay passes since the last failure.
+	// Delay starts with BaseDelay and doubles per failure up to MaxDelay.
+	DelayAfter      int           `yaml:"delay_after" env:"DELAY_AFTER" env-default:"3"`
+	BaseDelay       time.Duration `yaml:"base_delay" env:"BASE_DELAY" env-default:"1s"`
+	MaxDelay        time.Duration `yaml:"max_delay" env:"MAX_DELAY" env-default:"1m"`
+	CaptchaAfter    int           `yaml:"captcha_after" env:"CAPTCHA_AFTER" env-default:"5"`
+	LockoutAfter    int           `yaml:"lockout_after" env:"LOCKOUT_AFTER" env-default:"10"`
+	LockoutDuration time.Duration `yaml:"lockout_duration" env:"LOCKOUT_DURATION" env-default:"15m"`
+}
+
+// CaptchaConfig configures captcha check of Register, CreateVerification and RequestPasswordReset.
+type CaptchaConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_CAPTCHA_ENABLED"`
+	// Provider is recaptcha, hcaptcha or turnstile.
+	Provider string `yaml:"provider" env:"SSO_CAPTCHA_PROVIDER"`
+	Secret   string `yaml:"secret" env:"SSO_CAPTCHA_SECRET"`
+	// MinScore is a minimal score of reCAPTCHA v3 tokens.
+	MinScore float64       `yaml:"min_score" env:"SSO_CAPTCHA_MIN_SCORE" env-default:"0.5"`
+	Timeout  time.Duration `yaml:"timeout" env:"SSO_CAPTCHA_TIMEOUT" env-default:"5s"`
+}
+
+// AntiEnumerationConfig makes Register, CreateVerification and RequestPasswordReset respond alike whether
+// the email is registered or not: Register succeeds for existing emails, emailing their owners instead,
+// and returns no user ID, CreateVerification succeeds for unknown emails.
+type AntiEnumerationConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_ANTI_ENUMERATION_ENABLED"`
+	// MinResponseTime pads responses of these RPCs, so their timing doesn't tell registered emails either.
+	MinResponseTime time.Duration `yaml:"min_response_time" env:"SSO_ANTI_ENUMERATION_MIN_RESPONSE_TIME" env-default:"1s"`
+}
+
+// EncryptionConfig configures field-level encryption of emails and phones of users in storage.
+// Once enabled, it can't be disabled, since encrypted values are unreadable without the key.
+type EncryptionConfig struct {
+	Enabled bool `yaml:"enabled" env:"SSO_ENCRYPTION_ENABLED"`
+	// Key is base64-encoded 32-byte master key, usually a secret URI.
+	Key string `yaml:"key" env:"SSO_ENCRYPTION_KEY"`
+}
+
+// RateLimitConfig configures throttling of abusable RPCs.
+type RateLimitConfig struct {
+	// VerificationPerEmail limits CreateVerification and RequestPasswordReset calls per target email.
+	VerificationPerEmail LimitConfig `yaml:"verification_per_email" env-prefix:"SSO_RATE_LIMIT_VERIFICATION_PER_EMAIL_"`
+	// VerificationPerIP limits CreateVerification and RequestPasswordReset calls per client IP.
+	VerificationPerIP LimitConfig `yaml:"verification_per_ip" env-prefix:"SSO_RATE_LIMIT_VERIFICATION_PER_IP_"`
+	// VerificationPerPhone limits CreatePhoneVerification calls per target phone.
+	VerificationPerPhone LimitConfig `yaml:"verification_per_phone" env-prefix:"SSO_RATE_LIMIT_VERIFICATION_PER_PHONE_"`
+	// Store keeps hits of limits and failures of login throttling: memory keeps hits in the process and
+	// failures in storage, redis keeps both in Redis, so they are shared by replicas.
+	Store string `yaml:"store" env:"SSO_RATE_LIMIT_STORE" env-default:"memory"`
+}
+
+// Values of RateLimitConfig.Store.
+const (
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreRedis  = "redis"
+)
+
+// RedisConfig configures connection to Redis shared by replicas, it's used by rate limits and cache invalidations.
+type RedisConfig struct {
+	Addr     string `yaml:"addr" env:"SSO_REDIS_ADDR"`
+	Username string `yaml:"username" env:"SSO_REDIS_USERNAME"`
+	Password string `yaml:"password" env:"SSO_REDIS_PASSWORD"`
+	DB       int    `yaml:"db" env:"SSO_REDIS_DB"`
+	// Timeout limits dialing and every command, so unreachable Redis doesn't hang requests.
+	Timeout time.Duration `yaml:"timeout" env:"SSO_REDIS_TIMEOUT" env-default:"1s"`
+	TLS     bool          `yaml:"tls" env:"SSO_REDIS_TLS"`
+}
+
+// UsesRedis reports whether rate limits or cache invalidations are kept in Redis.
+func (c *Config) UsesRedis() bool {
+	return c.RateLimit.Store == RateLimitStoreRedis || c.Cache.Invalidations == CacheInvalidationsRedis
+}
+
+// LimitConfig allows at most Limit requests per Window, 0 disables the limit.
+type LimitConfig struct {
+	Limit  int           `yaml:"limit" env:"LIMIT"`
+	Window time.Duration `yaml:"window" env:"WINDOW" env-default:"1h"`
+}
+
+// IPFilterConfig allows or denies client IPs by CIDR ranges or single IPs, denied clients get PermissionDenied.
+// Apps can restrict clients of RPCs for them further by rules in the app_ip_rules table.
+type IPFilterConfig struct {
+	// Allow and Deny apply to all RPCs, empty Allow allows all IPs which are not denied.
+	Allow []string `yaml:"allow" env:"SSO_IP_FILTER_ALLOW"`
+	Deny  []string `yaml:"deny" env:"SSO_IP_FILTER_DENY"`
+	// Methods are rules of RPCs keyed by full method name, e.g. /auth.Auth/Login,
+	// or by service prefix ending with "/", e.g. /auth.Admin/.
+	// In env it's set as YAML or JSON, e.g. {/auth.Admin/: {allow: [10.0.0.0/8]}}.
+	Methods IPRuleConfigs `yaml:"methods" env:"SSO_IP_FILTER_METHODS"`
+	// AppRulesTTL is how long rules of apps are cached.
+	AppRulesTTL time.Duration `yaml:"app_rules_ttl" env:"SSO_IP_FILTER_APP_RULES_TTL" env-default:"1m"`
+}
+
+// IPRuleConfigs are IP rules keyed by method.
+type IPRuleConfigs map[string]IPRuleConfig
+
+// SetValue parses rules set by env variable.
+func (c *IPRuleConfigs) SetValue(s string) error {
+	return yaml.Unmarshal([]byte(s), c)
+}
+
+type IPRuleConfig struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
 }
 
 type VerificationConfig struct {
-	Len       int `yaml:"len"`
-	LastHours int `yaml:"hours"`
+	Len int `yaml:"len" env:"SSO_VERIFICATION_LEN" env-default:"6"`
+	// Charset of verification codes: letters, digits or alphanumeric.
+	Charset string `yaml:"charset" env:"SSO_VERIFICATION_CHARSET" env-default:"letters"`
+	// TTL is how long verification codes are valid, e.g. "15m".
+	TTL time.Duration `yaml:"ttl" env:"SSO_VERIFICATION_TTL" env-default:"3h"`
+	// MaxAttempts is a number of wrong codes after which verification is invalidated, 0 means unlimited.
+	MaxAttempts int `yaml:"max_attempts" env:"SSO_VERIFICATION_MAX_ATTEMPTS" env-default:"5"`
+	// Types overrides code format per verification type
+	// (registration, password_reset, email_change, sign_in, account_deletion, phone).
+	// In env it's set as YAML or JSON, e.g. {password_reset: {len: 8, ttl: 15m}}.
+	Types CodeConfigs `yaml:"types" env:"SSO_VERIFICATION_TYPES"`
 }
 
-func MustLoad() *Config {
-	configPath := fetchConfigPath()
-	if configPath == "" {
-		panic("config path is empty")
-	}
+// CodeConfigs are code formats keyed by verification type.
+type CodeConfigs map[string]CodeConfig
+
+// SetValue parses code formats set by env variable.
+func (c *CodeConfigs) SetValue(s string) error {
+	return yaml.Unmarshal([]byte(s), c)
+}
 
-	return MustLoadPath(configPath)
+// CodeConfig is a code format of a verification type, empty fields fall back to VerificationConfig ones.
+type CodeConfig struct {
+	Len     int           `yaml:"len"`
+	Charset string        `yaml:"charset"`
+	TTL     time.Duration `yaml:"ttl"`
 }
 
+// MustLoadPath loads and validates config, panics listing all problems found.
 func MustLoadPath(configPath string) *Config {
-	// check if file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		panic("config file does not exist: " + configPath)
+	cfg, err := Load(configPath, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		panic("invalid config:\n" + err.Error())
 	}
 
+	return cfg
+}
+
+// Load reads config from the file, used to reload config without restart.
+//
+// Values are taken, from lowest to highest priority, from:
+//   - base config file
+//   - overlay file of the env next to it, e.g. config.prod.yaml for config.yaml, if it exists
+//   - env variables
+//   - overrides, see ApplyOverrides
+//
+// If configPath is empty, config is read from env variables only.
+func Load(configPath string, overrides map[string]string) (*Config, error) {
 	var cfg Config
 
-	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
-		panic("cannot read config: " + err.Error())
+	if configPath == "" {
+		if err := cleanenv.ReadEnv(&cfg); err != nil {
+			return nil, fmt.Errorf("cannot read config from env: %w", err)
+		}
+	} else {
+		// check if file exists
+		if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			return nil, fmt.Errorf("config file does not exist: %s", configPath)
+		}
+
+		if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
+			return nil, fmt.Errorf("cannot read config: %w", err)
+		}
+
+		if err := applyOverlay(&cfg, OverlayPath(configPath, cfg.Env)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.ApplyOverrides(overrides); err != nil {
+		return nil, err
 	}
 
-	return &cfg
+	return &cfg, nil
 }
 
-// fetchConfigPath fetches config path from command line flag or environment variable.
-// Priority: flag > env > default.
-// Default value is empty string.
-func fetchConfigPath() string {
-	var res string
+// OverlayPath returns path of the env overlay of the config file, e.g. config/config.prod.yaml.
+func OverlayPath(configPath string, env string) string {
+	ext := filepath.Ext(configPath)
+
+	return strings.TrimSuffix(configPath, ext) + "." + env + ext
+}
 
-	flag.StringVar(&res, "config", "", "path to config file")
-	flag.Parse()
+// applyOverlay merges overlay file into config, missing overlay is not an error.
+// Env variables are read again, since they take priority over both files.
+func applyOverlay(cfg *Config, overlayPath string) error {
+	raw, err := os.ReadFile(overlayPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
 
-	if res == "" {
-		res = os.Getenv("CONFIG_PATH")
+		return fmt.Errorf("cannot read config overlay: %w", err)
 	}
 
-	return res
+	env := cfg.Env
+
+	if err := yaml.Unmarshal(raw, cfg); err != nil {
+		return fmt.Errorf("cannot read config overlay %s: %w", overlayPath, err)
+	}
+
+	if err := cleanenv.ReadEnv(cfg); err != nil {
+		return fmt.Errorf("cannot read config from env: %w", err)
+	}
+
+	// Overlay is selected by env, so it can't change it.
+	cfg.Env = env
+
+	return nil
 }
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyOverrides sets config values by yaml paths, e.g. "grpc.port" or "verification.types.phone.len".
+// Values are parsed as yaml scalars, so "15m" is a valid duration and "true" a valid bool.
+func (c *Config) ApplyOverrides(overrides map[string]string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	// Overrides are applied to the yaml tree of the whole config,
+	// so overriding a field of a map entry keeps its other fields.
+	var root yaml.Node
+	if err := root.Encode(c); err != nil {
+		return fmt.Errorf("cannot override config: %w", err)
+	}
+
+	for key, value := range overrides {
+		setNode(&root, strings.Split(key, "."), value)
+	}
+
+	var cfg Config
+	raw, err := yaml.Marshal(&root)
+	if err != nil {
+		return fmt.Errorf("cannot override config: %w", err)
+	}
+
+	dec := yaml.NewDecoder(strings.NewReader(string(raw)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return fmt.Errorf("cannot override config: %w", err)
+	}
+
+	*c = cfg
+
+	return nil
+}
+
+// setNode sets scalar value at the path, creating missing mapping nodes.
+func setNode(n *yaml.Node, path []string, value string) {
+	if len(path) == 0 {
+		*n = yaml.Node{Kind: yaml.ScalarNode, Value: value}
+
+		return
+	}
+
+	if n.Kind != yaml.MappingNode {
+		*n = yaml.Node{Kind: yaml.MappingNode}
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == path[0] {
+			setNode(n.Content[i+1], path[1:], value)
+
+			return
+		}
+	}
+
+	child := &yaml.Node{}
+	n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[0]}, child)
+	setNode(child, path[1:], value)
+}
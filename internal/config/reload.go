@@ -0,0 +1,99 @@
+package config
+
+import "reflect"
+
+// Reloadable fields are applied to the running service on SIGHUP:
+//   - log_level
+//   - rate_limit: verification_per_email, verification_per_ip and verification_per_phone
+//   - ip_filter: allow, deny and methods
+//   - verification code formats: len, charset, ttl and types
+//   - maintenance: enabled and message
+//
+// All other fields are boot-only, their changes are reported by BootOnlyChanges
+// and take effect after restart.
+
+// WithReloadable returns copy of the config with reloadable values taken from new one.
+func (c *Config) WithReloadable(new *Config) *Config {
+	cfg := *c
+
+	cfg.LogLevel = new.LogLevel
+	cfg.RateLimit.VerificationPerEmail = new.RateLimit.VerificationPerEmail
+	cfg.RateLimit.VerificationPerIP = new.RateLimit.VerificationPerIP
+	cfg.RateLimit.VerificationPerPhone = new.RateLimit.VerificationPerPhone
+	cfg.IPFilter.Allow = new.IPFilter.Allow
+	cfg.IPFilter.Deny = new.IPFilter.Deny
+	cfg.IPFilter.Methods = new.IPFilter.Methods
+	cfg.Verification.Len = new.Verification.Len
+	cfg.Verification.Charset = new.Verification.Charset
+	cfg.Verification.TTL = new.Verification.TTL
+	cfg.Verification.Types = new.Verification.Types
+	cfg.Maintenance.Enabled = new.Maintenance.Enabled
+	cfg.Maintenance.Message = new.Maintenance.Message
+
+	return &cfg
+}
+
+// BootOnlyChanges returns yaml names of boot-only fields which differ in old and new config.
+func BootOnlyChanges(old *Config, new *Config) []string {
+	fields := []struct {
+		name     string
+		old, new any
+	}{
+		{"env", old.Env, new.Env},
+		{"log_format", old.LogFormat, new.LogFormat},
+		{"log_pii", old.LogPII, new.LogPII},
+		{"storage_path", old.StoragePath, new.StoragePath},
+		{"sqlite_pool", old.SQLitePool, new.SQLitePool},
+		{"grpc", old.GRPC, new.GRPC},
+		{"http", old.HTTP, new.HTTP},
+		{"ops", old.Ops, new.Ops},
+		{"admin", old.Admin, new.Admin},
+		{"emailSender", old.EmailService, new.EmailService},
+		{"smsSender", old.SMSService, new.SMSService},
+		{"verification.max_attempts", old.Verification.MaxAttempts, new.Verification.MaxAttempts},
+		{"registration", old.Registration, new.Registration},
+		{"login", old.Login, new.Login},
+		{"captcha", old.Captcha, new.Captcha},
+		{"rate_limit.store", old.RateLimit.Store, new.RateLimit.Store},
+		{"anti_enumeration", old.AntiEnumeration, new.AntiEnumeration},
+		{"ip_filter.app_rules_ttl", old.IPFilter.AppRulesTTL, new.IPFilter.AppRulesTTL},
+		{"cache", old.Cache, new.Cache},
+		{"redis", old.Redis, new.Redis},
+		{"audit", old.Audit, new.Audit},
+		{"password", old.Password, new.Password},
+		{"encryption", old.Encryption, new.Encryption},
+		{"migrations_path", old.MigrationsPath, new.MigrationsPath},
+		{"token_ttl", old.TokenTTL, new.TokenTTL},
+		{"token_claims", old.TokenClaims, new.TokenClaims},
+		{"token_signing", old.TokenSigning, new.TokenSigning},
+		{"elevated_token_ttl", old.ElevatedTokenTTL, new.ElevatedTokenTTL},
+		{"sso_session_ttl", old.SSOSessionTTL, new.SSOSessionTTL},
+		{"refresh_token_ttl", old.RefreshTokenTTL, new.RefreshTokenTTL},
+		{"refresh_token_binding", old.RefreshTokenBinding, new.RefreshTokenBinding},
+		{"shutdown_timeout", old.ShutdownTimeout, new.ShutdownTimeout},
+		{"vault", old.Vault, new.Vault},
+		{"secrets", old.Secrets, new.Secrets},
+		{"features", old.Features, new.Features},
+		{"tracing", old.Tracing, new.Tracing},
+		{"webhooks", old.Webhooks, new.Webhooks},
+		{"organizations", old.Organizations, new.Organizations},
+		{"service_accounts", old.ServiceAccounts, new.ServiceAccounts},
+		{"account_deletion", old.AccountDeletion, new.AccountDeletion},
+		{"device_login", old.DeviceLogin, new.DeviceLogin},
+		{"verification_reminder", old.VerificationReminder, new.VerificationReminder},
+		{"geoip", old.GeoIP, new.GeoIP},
+		{"maintenance.retry_after", old.Maintenance.RetryAfter, new.Maintenance.RetryAfter},
+		{"maintenance.windows_ttl", old.Maintenance.WindowsTTL, new.Maintenance.WindowsTTL},
+		{"directories", old.Directories, new.Directories},
+		{"scheduler", old.Scheduler, new.Scheduler},
+	}
+
+	var changed []string
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.old, f.new) {
+			changed = append(changed, f.name)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,625 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/ipfilter"
+	"grpc-service-ref/internal/lib/passstrength"
+	"grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/services/audit"
+	"grpc-service-ref/internal/services/captcha"
+	"grpc-service-ref/internal/services/risk"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	envLocal = "local"
+	envDev   = "dev"
+	envProd  = "prod"
+)
+
+// Verification code length bounds, codes are stored in Varchar(10) column.
+const (
+	minCodeLen = 4
+	maxCodeLen = 10
+)
+
+// Validate checks config and returns all problems found joined in one error.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	switch c.Env {
+	case envLocal, envDev, envProd:
+	default:
+		v.addf("env: must be local, dev or prod, got %q", c.Env)
+	}
+
+	if c.LogLevel != "" {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(c.LogLevel)); err != nil {
+			v.addf("log_level: must be debug, info, warn or error, got %q", c.LogLevel)
+		}
+	}
+
+	switch c.LogFormat {
+	case "", LogFormatJSON, LogFormatText, LogFormatPretty:
+	default:
+		v.addf("log_format: must be json, text or pretty, got %q", c.LogFormat)
+	}
+
+	switch c.LogPII {
+	case "", LogPIIHash, LogPIIRedact:
+	case LogPIIPlain:
+		if c.Env == envProd {
+			v.addf("log_pii: must be hash or redact in prod env")
+		}
+	default:
+		v.addf("log_pii: must be plain, hash or redact, got %q", c.LogPII)
+	}
+
+	// Only sqlite storage is supported, its path is a file path, not a DSN.
+	if strings.Contains(c.StoragePath, "://") && !isSecretRef(c.StoragePath) {
+		v.addf("storage_path: must be a path of sqlite db file, got DSN %q", c.StoragePath)
+	}
+
+	v.port("grpc.port", c.GRPC.Port)
+	c.validateInterceptors(v)
+	c.validateAuthorization(v)
+	v.port("http.port", c.HTTP.Port)
+	if c.HTTP.ForwardAuth.Enabled {
+		v.required("http.forward_auth.cookie_name", c.HTTP.ForwardAuth.CookieName)
+	}
+	if c.HTTP.Sessions.Enabled {
+		if !c.HTTP.ForwardAuth.Enabled {
+			v.addf("http.sessions.enabled: requires http.forward_auth.enabled, /auth/verify checks the cookies")
+		}
+
+		switch c.HTTP.Sessions.SameSite {
+		case SameSiteLax, SameSiteStrict:
+		case SameSiteNone:
+			if c.HTTP.Sessions.Insecure {
+				v.addf("http.sessions.same_site: none requires secure cookies, insecure must be false")
+			}
+		default:
+			v.addf("http.sessions.same_site: must be lax, strict or none, got %q", c.HTTP.Sessions.SameSite)
+		}
+
+		if c.HTTP.Sessions.Insecure && c.Env != envLocal {
+			v.addf("http.sessions.insecure: allowed in local env only")
+		}
+	}
+	if c.HTTP.TokenReview.Enabled && c.HTTP.TokenReview.AppID <= 0 {
+		v.addf("http.token_review.app_id: must be positive")
+	}
+	v.port("ops.port", c.Ops.Port)
+	if c.GRPC.Port == c.HTTP.Port {
+		v.addf("http.port: must differ from grpc.port %d", c.GRPC.Port)
+	}
+	if c.Ops.Port == c.GRPC.Port || c.Ops.Port == c.HTTP.Port {
+		v.addf("ops.port: must differ from grpc.port and http.port")
+	}
+
+	if c.Admin.Port != 0 {
+		v.port("admin.port", c.Admin.Port)
+		if c.Admin.Port == c.GRPC.Port || c.Admin.Port == c.HTTP.Port || c.Admin.Port == c.Ops.Port {
+			v.addf("admin.port: must differ from grpc.port, http.port and ops.port")
+		}
+
+		v.tls("admin.tls", c.Admin.TLS)
+
+		// AdminService manages apps, secrets and roles, client certificates are its authentication.
+		if c.Env != envLocal && c.Admin.TLS.ClientCAFile == "" {
+			v.addf("admin.tls.client_ca_file: required outside local env, admin RPCs would be open to anyone reaching admin.port")
+		}
+	}
+
+	if c.TokenTTL <= 0 {
+		v.addf("token_ttl: must be positive")
+	}
+
+	if strings.ContainsFunc(c.TokenClaims.Issuer, unicode.IsSpace) {
+		v.addf("token_claims.issuer: must not contain whitespace")
+	}
+
+	if strings.ContainsFunc(c.TokenClaims.Audience, unicode.IsSpace) {
+		v.addf("token_claims.audience: must not contain whitespace")
+	}
+
+	if c.ElevatedTokenTTL <= 0 {
+		v.addf("elevated_token_ttl: must be positive")
+	}
+
+	if c.SSOSessionTTL < 0 {
+		v.addf("sso_session_ttl: must not be negative")
+	}
+
+	if c.RefreshTokenTTL < 0 {
+		v.addf("refresh_token_ttl: must not be negative")
+	}
+
+	if !slices.Contains(Bindings, c.RefreshTokenBinding) {
+		v.addf("refresh_token_binding: unknown binding %q, must be one of user_agent, client_cert, dpop", c.RefreshTokenBinding)
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		v.addf("shutdown_timeout: must be positive")
+	}
+
+	if c.Env != envLocal {
+		v.required("emailSender.email", c.EmailService.Email)
+		v.required("emailSender.password", c.EmailService.Password)
+	}
+
+	if c.EmailService.Workers < 1 || c.EmailService.BatchSize < 1 {
+		v.addf("emailSender: workers and batch_size must be positive")
+	}
+
+	if c.EmailService.DKIM.PrivateKeyPath != "" {
+		v.required("emailSender.dkim.domain", c.EmailService.DKIM.Domain)
+		v.required("emailSender.dkim.selector", c.EmailService.DKIM.Selector)
+	}
+
+	if c.SMSService.AccountSID != "" {
+		v.required("smsSender.auth_token", c.SMSService.AuthToken)
+		v.required("smsSender.from", c.SMSService.From)
+	}
+
+	c.validateVerification(v)
+
+	switch c.Registration.Mode {
+	case RegistrationModeImmediate:
+	case RegistrationModePending:
+		if c.Registration.PendingTTL <= 0 {
+			v.addf("registration.pending_ttl: must be positive in pending mode")
+		}
+	default:
+		v.addf("registration.mode: must be %s or %s, got %q", RegistrationModeImmediate, RegistrationModePending, c.Registration.Mode)
+	}
+
+	if c.Captcha.Enabled {
+		switch c.Captcha.Provider {
+		case captcha.ProviderReCaptcha, captcha.ProviderHCaptcha, captcha.ProviderTurnstile:
+		default:
+			v.addf("captcha.provider: must be recaptcha, hcaptcha or turnstile, got %q", c.Captcha.Provider)
+		}
+
+		v.required("captcha.secret", c.Captcha.Secret)
+
+		if c.Captcha.MinScore < 0 || c.Captcha.MinScore > 1 {
+			v.addf("captcha.min_score: must be between 0 and 1")
+		}
+	}
+
+	if c.Encryption.Enabled {
+		v.required("encryption.key", c.Encryption.Key)
+	}
+
+	if c.Login.Throttle.Enabled {
+		c.validateThrottle(v)
+	}
+
+	if r := c.Login.Risk; r.Enabled {
+		if r.ChallengeScore < 0 || r.ChallengeScore > risk.MaxScore || r.BlockScore < 0 || r.BlockScore > risk.MaxScore {
+			v.addf("login.risk: challenge_score and block_score must be 0..%d", risk.MaxScore)
+		}
+		if r.MaxTravelSpeed <= 0 {
+			v.addf("login.risk.max_travel_speed: must be positive")
+		}
+	}
+
+	if st := c.Login.Stuffing; st.Enabled {
+		if st.Window <= 0 || st.Cooldown <= 0 {
+			v.addf("login.stuffing: window and cooldown must be positive")
+		}
+		if st.GlobalThreshold < 0 || st.IPThreshold < 0 {
+			v.addf("login.stuffing: thresholds must not be negative")
+		}
+	}
+
+	v.limit("rate_limit.verification_per_email", c.RateLimit.VerificationPerEmail)
+	v.limit("rate_limit.verification_per_ip", c.RateLimit.VerificationPerIP)
+	v.limit("rate_limit.verification_per_phone", c.RateLimit.VerificationPerPhone)
+
+	switch c.RateLimit.Store {
+	case RateLimitStoreMemory, RateLimitStoreRedis:
+	default:
+		v.addf("rate_limit.store: must be %s or %s, got %q", RateLimitStoreMemory, RateLimitStoreRedis, c.RateLimit.Store)
+	}
+
+	switch c.Cache.Invalidations {
+	case CacheInvalidationsLocal, CacheInvalidationsRedis:
+	default:
+		v.addf("cache.invalidations: must be %s or %s, got %q", CacheInvalidationsLocal, CacheInvalidationsRedis, c.Cache.Invalidations)
+	}
+
+	if c.UsesRedis() {
+		v.required("redis.addr", c.Redis.Addr)
+		if c.Redis.Timeout <= 0 {
+			v.addf("redis.timeout: must be positive")
+		}
+	}
+
+	if c.AntiEnumeration.MinResponseTime < 0 {
+		v.addf("anti_enumeration.min_response_time: must not be negative")
+	}
+
+	c.validateIPFilter(v)
+
+	if c.Audit.RequestSamplePercent < 0 || c.Audit.RequestSamplePercent > 100 {
+		v.addf("audit.request_sample_percent: must be from 0 to 100, got %v", c.Audit.RequestSamplePercent)
+	}
+
+	if c.Audit.Async {
+		if c.Audit.BufferSize < 1 || c.Audit.BatchSize < 1 || c.Audit.FlushInterval <= 0 {
+			v.addf("audit: buffer_size, batch_size and flush_interval must be positive")
+		}
+
+		switch c.Audit.Overflow {
+		case audit.OverflowBlock, audit.OverflowDrop:
+		default:
+			v.addf("audit.overflow: must be %s or %s, got %q", audit.OverflowBlock, audit.OverflowDrop, c.Audit.Overflow)
+		}
+	}
+
+	if c.SQLitePool.MaxOpenConns < 0 || c.SQLitePool.MaxIdleConns < 0 || c.SQLitePool.ConnMaxLifetime < 0 {
+		v.addf("sqlite_pool: max_open_conns, max_idle_conns and conn_max_lifetime must not be negative")
+	}
+
+	if c.Password.Cost < bcrypt.MinCost || c.Password.Cost > bcrypt.MaxCost {
+		v.addf("password.cost: must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, c.Password.Cost)
+	}
+	if c.Password.CalibrateTarget < 0 {
+		v.addf("password.calibrate_target: must not be negative")
+	}
+	if c.Password.MinStrength < 0 || c.Password.MinStrength > passstrength.MaxScore {
+		v.addf("password.min_strength: must be between 0 and %d, got %d", passstrength.MaxScore, c.Password.MinStrength)
+	}
+
+	if c.Cache.AppsTTL < 0 || c.Cache.UsersTTL < 0 || c.Cache.AdminsTTL < 0 || c.Cache.StatsTTL < 0 {
+		v.addf("cache: apps_ttl, users_ttl, admins_ttl and stats_ttl must not be negative")
+	}
+
+	if c.Vault.Address != "" {
+		switch c.Vault.AuthMethod {
+		case "token":
+			v.required("vault.token", c.Vault.Token)
+		case "approle":
+			v.required("vault.role_id", c.Vault.RoleID)
+			v.required("vault.secret_id", c.Vault.SecretID)
+		default:
+			v.addf("vault.auth_method: must be token or approle, got %q", c.Vault.AuthMethod)
+		}
+	}
+
+	if c.Webhooks.MaxAttempts < 1 {
+		v.addf("webhooks.max_attempts: must be positive")
+	}
+	if c.Webhooks.InitialBackoff <= 0 || c.Webhooks.MaxBackoff < c.Webhooks.InitialBackoff {
+		v.addf("webhooks: initial_backoff must be positive and not greater than max_backoff")
+	}
+	if c.Webhooks.Timeout <= 0 || c.Webhooks.PollInterval <= 0 {
+		v.addf("webhooks: timeout and poll_interval must be positive")
+	}
+
+	if c.Organizations.InvitationTTL <= 0 {
+		v.addf("organizations.invitation_ttl: must be positive")
+	}
+	if c.ServiceAccounts.TokenTTL <= 0 {
+		v.addf("service_accounts.token_ttl: must be positive")
+	}
+	v.required("service_accounts.assertion_audience", c.ServiceAccounts.AssertionAudience)
+	if c.AccountDeletion.GracePeriod <= 0 {
+		v.addf("account_deletion.grace_period: must be positive")
+	}
+	if c.DeviceLogin.TTL <= 0 || c.DeviceLogin.PollInterval <= 0 {
+		v.addf("device_login: ttl and poll_interval must be positive")
+	}
+	if uri := c.DeviceLogin.VerificationURI; uri != "" {
+		if u, err := url.Parse(uri); err != nil || u.Scheme == "" || u.Host == "" {
+			v.addf("device_login.verification_uri: must be absolute URL, got %q", uri)
+		}
+	}
+
+	if c.VerificationReminder.MaxReminders < 0 {
+		v.addf("verification_reminder.max_reminders: must not be negative")
+	}
+	if c.VerificationReminder.MaxReminders > 0 && (c.VerificationReminder.After <= 0 || c.VerificationReminder.Interval <= 0) {
+		v.addf("verification_reminder: after and interval must be positive")
+	}
+
+	if c.Scheduler.CleanupInterval <= 0 {
+		v.addf("scheduler.cleanup_interval: must be positive")
+	}
+
+	c.validateDirectories(v)
+
+	if c.Maintenance.RetryAfter <= 0 {
+		v.addf("maintenance.retry_after: must be positive")
+	}
+	if c.Maintenance.WindowsTTL < 0 {
+		v.addf("maintenance.windows_ttl: must not be negative")
+	}
+
+	if c.Tracing.Enabled {
+		v.required("tracing.endpoint", c.Tracing.Endpoint)
+		v.required("tracing.service_name", c.Tracing.ServiceName)
+
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			v.addf("tracing.sample_ratio: must be between 0 and 1")
+		}
+	}
+
+	// Subsystems behind these flags are not implemented yet.
+	v.notImplemented("features.mfa", c.Features.MFA)
+	v.notImplemented("features.magic_links", c.Features.MagicLinks)
+	v.notImplemented("features.social_login", c.Features.SocialLogin)
+	v.notImplemented("features.rest_gateway", c.Features.RESTGateway)
+
+	return errors.Join(v.errs...)
+}
+
+func (c *Config) validateVerification(v *validator) {
+	v.codeFormat("verification", c.Verification.Len, c.Verification.Charset, c.Verification.TTL > 0)
+
+	if c.Verification.MaxAttempts < 0 {
+		v.addf("verification.max_attempts: must not be negative")
+	}
+
+	for vType, code := range c.Verification.Types {
+		name := "verification.types." + vType
+
+		switch models.VerificationType(vType) {
+		case models.VerificationTypeRegistration,
+			models.VerificationTypePasswordReset,
+			models.VerificationTypeEmailChange,
+			models.VerificationTypeSignIn,
+			models.VerificationTypeAccountDeletion,
+			models.VerificationTypePhone:
+		default:
+			v.addf("%s: unknown verification type", name)
+
+			continue
+		}
+
+		// Empty fields fall back to verification ones.
+		codeLen, charset := c.Verification.Len, c.Verification.Charset
+		if code.Len != 0 {
+			codeLen = code.Len
+		}
+		if code.Charset != "" {
+			charset = code.Charset
+		}
+
+		v.codeFormat(name, codeLen, charset, code.TTL >= 0)
+	}
+}
+
+func (c *Config) validateThrottle(v *validator) {
+	t := c.Login.Throttle
+
+	if t.Window <= 0 {
+		v.addf("login.throttle.window: must be positive")
+	}
+
+	policies := []struct {
+		name   string
+		policy ThrottlePolicyConfig
+	}{
+		{"login.throttle.account", t.Account},
+		{"login.throttle.ip", t.IP},
+	}
+
+	for _, pc := range policies {
+		name, p := pc.name, pc.policy
+
+		if p.DelayAfter < 0 || p.CaptchaAfter < 0 || p.LockoutAfter < 0 {
+			v.addf("%s: thresholds must not be negative", name)
+		}
+		if p.DelayAfter > 0 && (p.BaseDelay <= 0 || p.MaxDelay < p.BaseDelay) {
+			v.addf("%s: base_delay must be positive and not greater than max_delay", name)
+		}
+		if p.LockoutAfter > 0 && p.LockoutDuration <= 0 {
+			v.addf("%s.lockout_duration: must be positive", name)
+		}
+		// Failures forgotten before lockout ends would let the next failure start over.
+		if p.LockoutAfter > 0 && p.LockoutDuration > t.Window {
+			v.addf("%s.lockout_duration: must not be greater than login.throttle.window", name)
+		}
+	}
+}
+
+func (c *Config) validateDirectories(v *validator) {
+	names := make(map[string]bool)
+	apps := make(map[int]bool)
+	domains := make(map[string]bool)
+
+	for i, d := range c.Directories {
+		field := fmt.Sprintf("directories[%d]", i)
+
+		if d.Name == "" {
+			v.addf("%s.name: is required", field)
+		} else if names[d.Name] {
+			v.addf("%s.name: duplicate name %q", field, d.Name)
+		}
+		names[d.Name] = true
+
+		if u, err := url.Parse(d.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.addf("%s.url: must be absolute http or https URL, got %q", field, d.URL)
+		}
+		if d.Timeout <= 0 {
+			v.addf("%s.timeout: must be positive", field)
+		}
+		if len(d.Apps) == 0 && len(d.Domains) == 0 {
+			v.addf("%s: apps or domains are required", field)
+		}
+
+		for _, app := range d.Apps {
+			if apps[app] {
+				v.addf("%s.apps: app %d belongs to another directory", field, app)
+			}
+			apps[app] = true
+		}
+		for _, domain := range d.Domains {
+			domain = strings.ToLower(domain)
+			if domains[domain] {
+				v.addf("%s.domains: domain %q belongs to another directory", field, domain)
+			}
+			domains[domain] = true
+		}
+	}
+}
+
+func (c *Config) validateInterceptors(v *validator) {
+	seen := make(map[string]bool, len(c.GRPC.Interceptors))
+	for _, name := range c.GRPC.Interceptors {
+		if !slices.Contains(DefaultInterceptors, name) {
+			v.addf("grpc.interceptors: unknown interceptor %q, must be one of %s", name, strings.Join(DefaultInterceptors, ", "))
+
+			continue
+		}
+
+		if seen[name] {
+			v.addf("grpc.interceptors: %q is listed twice", name)
+		}
+		seen[name] = true
+	}
+
+	// RPCs policies are enforced by the authorization interceptor only, handlers rely on it.
+	if len(c.GRPC.Interceptors) > 0 && !seen[InterceptorAuthorization] {
+		v.addf("grpc.interceptors: %q is required", InterceptorAuthorization)
+	}
+}
+
+func (c *Config) validateAuthorization(v *validator) {
+	methods := make([]string, 0, len(c.GRPC.Authorization))
+	for method := range c.GRPC.Authorization {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		if !strings.HasPrefix(method, "/") {
+			v.addf("grpc.authorization: key must be full method name or service prefix starting with /, got %q", method)
+		}
+
+		if policy := c.GRPC.Authorization[method]; !slices.Contains(Policies, policy) {
+			v.addf("grpc.authorization.%s: unknown policy %q, must be one of %s", method, policy, strings.Join(Policies, ", "))
+		}
+	}
+}
+
+func (c *Config) validateIPFilter(v *validator) {
+	v.cidrs("ip_filter.allow", c.IPFilter.Allow)
+	v.cidrs("ip_filter.deny", c.IPFilter.Deny)
+
+	methods := make([]string, 0, len(c.IPFilter.Methods))
+	for method := range c.IPFilter.Methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		rules := c.IPFilter.Methods[method]
+
+		if !strings.HasPrefix(method, "/") {
+			v.addf("ip_filter.methods: key must be full method name or service prefix starting with /, got %q", method)
+		}
+
+		v.cidrs("ip_filter.methods."+method+".allow", rules.Allow)
+		v.cidrs("ip_filter.methods."+method+".deny", rules.Deny)
+	}
+
+	if c.IPFilter.AppRulesTTL <= 0 {
+		v.addf("ip_filter.app_rules_ttl: must be positive")
+	}
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) required(name string, value string) {
+	if value == "" {
+		v.addf("%s: is required", name)
+	}
+}
+
+func (v *validator) notImplemented(name string, enabled bool) {
+	if enabled {
+		v.addf("%s: is not implemented yet, must be false", name)
+	}
+}
+
+func (v *validator) port(name string, port int) {
+	if port < 1 || port > 65535 {
+		v.addf("%s: must be between 1 and 65535, got %d", name, port)
+	}
+}
+
+func (v *validator) tls(name string, t TLSConfig) {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		v.addf("%s: cert_file and key_file must be set together", name)
+	}
+
+	if t.ClientCAFile != "" && t.CertFile == "" {
+		v.addf("%s.client_ca_file: requires cert_file and key_file", name)
+	}
+}
+
+func (v *validator) limit(name string, l LimitConfig) {
+	if l.Limit < 0 {
+		v.addf("%s.limit: must not be negative", name)
+	}
+
+	if l.Limit > 0 && l.Window <= 0 {
+		v.addf("%s.window: must be positive", name)
+	}
+}
+
+func (v *validator) cidrs(name string, cidrs []string) {
+	for _, cidr := range cidrs {
+		if _, err := ipfilter.ParsePrefix(strings.TrimSpace(cidr)); err != nil {
+			v.addf("%s: invalid CIDR range %q", name, cidr)
+		}
+	}
+}
+
+func (v *validator) codeFormat(name string, codeLen int, charset string, ttlValid bool) {
+	if codeLen < minCodeLen || codeLen > maxCodeLen {
+		v.addf("%s.len: must be between %d and %d, got %d", name, minCodeLen, maxCodeLen, codeLen)
+	}
+
+	switch charset {
+	case verification.CharsetLetters, verification.CharsetDigits, verification.CharsetAlphanumeric:
+	default:
+		v.addf("%s.charset: must be letters, digits or alphanumeric, got %q", name, charset)
+	}
+
+	if !ttlValid {
+		v.addf("%s.ttl: must be positive", name)
+	}
+}
+
+// isSecretRef reports whether value references a secret manager, see SecretsConfig.
+func isSecretRef(value string) bool {
+	for _, scheme := range []string{"vault://", "aws-sm://", "gcp-sm://"} {
+		if strings.HasPrefix(value, scheme) {
+			return true
+		}
+	}
+
+	return false
+}
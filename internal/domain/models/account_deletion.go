@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// AccountDeletion is a deletion of the user's account requested by the user, the account and its data
+// are deleted at ScheduledAt unless the user signs in before.
+type AccountDeletion struct {
+	UserID      int64
+	RequestedAt time.Time
+	ScheduledAt time.Time
+}
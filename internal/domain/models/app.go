@@ -4,4 +4,14 @@ type App struct {
 	ID     int
 	Name   string
 	Secret string
+	// RequireVerified rejects login of users with unverified email.
+	RequireVerified bool
+	// AllowedOrigins are origins of frontends of the app the public HTTP server allows by CORS,
+	// e.g. https://app.example.com.
+	AllowedOrigins []string
+	// ContentSecurityPolicy is sent in responses to the app's frontends, empty means the default policy.
+	ContentSecurityPolicy string
+	// Issuer and Audience override iss and aud claims of the app's tokens, empty means the configured ones.
+	Issuer   string
+	Audience string
 }
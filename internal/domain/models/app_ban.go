@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// AppBan keeps the user from signing in to the app, other apps remain usable.
+type AppBan struct {
+	UserID    int64
+	AppID     int
+	Reason    string
+	CreatedAt time.Time
+	// ExpiresAt is when the ban is lifted by itself, zero means it lasts until it's lifted by an admin.
+	ExpiresAt time.Time
+}
+
+// Active reports whether the ban is in effect at the time.
+func (b AppBan) Active(at time.Time) bool {
+	return b.ExpiresAt.IsZero() || at.Before(b.ExpiresAt)
+}
@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditActionLoginSucceeded  AuditAction = "login_succeeded"
+	AuditActionLoginFailed     AuditAction = "login_failed"
+	AuditActionRegistered      AuditAction = "user_registered"
+	AuditActionEmailVerified   AuditAction = "email_verified"
+	AuditActionPasswordReset   AuditAction = "password_reset"
+	AuditActionPasswordChanged AuditAction = "password_changed"
+	AuditActionRoleChanged     AuditAction = "role_changed"
+	AuditActionAppChanged      AuditAction = "app_changed"
+	AuditActionNewSignIn       AuditAction = "new_sign_in"
+	AuditActionTokenElevated   AuditAction = "token_elevated"
+	AuditActionLoggedOut       AuditAction = "logged_out"
+	AuditActionSessionsRevoked AuditAction = "sessions_revoked"
+	// AuditActionRefreshTokenReused records exchange of an already exchanged refresh token,
+	// its SSO session is revoked as the token may be stolen.
+	AuditActionRefreshTokenReused AuditAction = "refresh_token_reused"
+	// AuditActionRefreshTokenMismatch records exchange of a refresh token by a client other than the one it's bound to.
+	AuditActionRefreshTokenMismatch AuditAction = "refresh_token_mismatch"
+
+	AuditActionOrganizationCreated AuditAction = "organization_created"
+	AuditActionOrganizationInvited AuditAction = "organization_invited"
+	AuditActionOrganizationJoined  AuditAction = "organization_joined"
+
+	AuditActionServiceAccountChanged     AuditAction = "service_account_changed"
+	AuditActionServiceAccountTokenIssued AuditAction = "service_account_token_issued"
+
+	AuditActionAccountDeletionRequested AuditAction = "account_deletion_requested"
+	AuditActionAccountDeletionCanceled  AuditAction = "account_deletion_canceled"
+	AuditActionAccountDeleted           AuditAction = "account_deleted"
+
+	AuditActionDeviceLoginApproved AuditAction = "device_login_approved"
+
+	AuditActionSignInChallenged AuditAction = "sign_in_challenged"
+	AuditActionSignInBlocked    AuditAction = "sign_in_blocked"
+
+	AuditActionVerificationResent  AuditAction = "verification_resent"
+	AuditActionVerificationChanged AuditAction = "verification_changed"
+
+	AuditActionMaintenanceChanged AuditAction = "maintenance_changed"
+
+	AuditActionUserBanned   AuditAction = "user_banned"
+	AuditActionUserUnbanned AuditAction = "user_unbanned"
+
+	AuditActionNotificationPreferencesChanged AuditAction = "notification_preferences_changed"
+
+	// AuditActionCredentialStuffingDetected records logins put under defense by failed login rates,
+	// Subject is the client IP unless the whole service is defended.
+	AuditActionCredentialStuffingDetected AuditAction = "credential_stuffing_detected"
+
+	// AuditActionRequestSampled records metadata of an RPC picked by request sampling.
+	AuditActionRequestSampled AuditAction = "request_sampled"
+)
+
+// AuditEvent is a record of a security-relevant action, audit events are never updated or deleted.
+type AuditEvent struct {
+	ID     int64
+	Action AuditAction
+	// ActorID is a user who performed the action, 0 if unknown, e.g. on login with unknown email.
+	ActorID int64
+	// Subject is what the action was performed on, e.g. email of the user.
+	Subject string
+	// AppID is 0 if the action is not related to an app.
+	AppID int
+	IP    string
+	// Country and City are resolved from IP by GeoIP, empty if unknown.
+	Country string
+	City    string
+	// Payload holds details of the action, e.g. reason of failed login.
+	Payload   map[string]string
+	CreatedAt time.Time
+}
+
+// AuditFilter selects audit events, zero fields match any event.
+type AuditFilter struct {
+	Action AuditAction
+	// Actions matches events with any of the actions.
+	Actions []AuditAction
+	ActorID int64
+	Subject string
+	AppID   int
+	// Country is ISO 3166-1 alpha-2 code.
+	Country string
+	Since   time.Time
+	Until   time.Time
+	// BeforeID matches events older than the event with the ID, it's set to page through events.
+	BeforeID int64
+}
@@ -0,0 +1,11 @@
+package models
+
+// DeliveryEvent is a delivery notification reported by email provider (bounce, complaint, ...).
+type DeliveryEvent struct {
+	MessageID string
+	Recipient string
+	Status    EmailStatus
+	// Permanent is true for hard bounces. Soft bounces don't suppress the address.
+	Permanent bool
+	Reason    string
+}
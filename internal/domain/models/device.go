@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Device is a client device user signs in from, as reported by the client and fronting proxy.
+type Device struct {
+	// ID is an identifier the client keeps per device, empty if the client doesn't send one.
+	ID        string
+	UserAgent string
+	IP        string
+	// Country is ISO 3166-1 alpha-2 code resolved by the fronting proxy or GeoIP, empty if unknown.
+	Country string
+	// City is resolved by GeoIP, empty if unknown.
+	City string
+}
+
+// KnownDevice is a device the user signed in from before.
+type KnownDevice struct {
+	UserID int64
+	// Key identifies the device among devices of the user.
+	Key         string
+	UserAgent   string
+	IP          string
+	Country     string
+	City        string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DeviceLogin is login of a device, e.g. TV or kiosk, approved from another device the user is signed in on.
+// The device shows PairingCode, e.g. as QR code, and polls by the token whose SHA-256 hash is ID
+// until the login is approved.
+type DeviceLogin struct {
+	ID          string
+	PairingCode string
+	AppID       int
+	// Device is the device which logs in, it's shown to the user approving the login.
+	Device Device
+	// UserID is the user who approved the login, 0 while it's pending.
+	UserID     int64
+	ApprovedAt time.Time
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// Approved tells whether the user approved the login.
+func (l DeviceLogin) Approved() bool {
+	return !l.ApprovedAt.IsZero()
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type EmailStatus string
+
+const (
+	EmailStatusSent       EmailStatus = "sent"
+	EmailStatusFailed     EmailStatus = "failed"
+	EmailStatusDelivered  EmailStatus = "delivered"
+	EmailStatusBounced    EmailStatus = "bounced"
+	EmailStatusComplained EmailStatus = "complained"
+)
+
+// Email is a delivery record of a single message to a single recipient.
+type Email struct {
+	ID        int64
+	MessageID string
+	Recipient string
+	// UserID is 0 if recipient is not registered.
+	UserID    int64
+	Subject   string
+	Status    EmailStatus
+	Reason    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
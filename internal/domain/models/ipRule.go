@@ -0,0 +1,16 @@
+package models
+
+type IPRuleAction string
+
+const (
+	IPRuleAllow IPRuleAction = "allow"
+	IPRuleDeny  IPRuleAction = "deny"
+)
+
+// IPRule allows or denies CIDR range of client IPs calling RPCs for the app.
+type IPRule struct {
+	ID     int64
+	AppID  int
+	CIDR   string
+	Action IPRuleAction
+}
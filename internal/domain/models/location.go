@@ -0,0 +1,12 @@
+package models
+
+// Location is geolocation of an IP address, resolved by a GeoIP database.
+type Location struct {
+	// Country is ISO 3166-1 alpha-2 code, empty if unknown.
+	Country string
+	City    string
+	// Latitude and Longitude are approximate coordinates in degrees, valid only if HasCoordinates is set.
+	Latitude       float64
+	Longitude      float64
+	HasCoordinates bool
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// LoginFailures counts failed logins by a throttled key, e.g. account or client IP.
+type LoginFailures struct {
+	Key           string
+	Count         int
+	LastFailureAt time.Time
+}
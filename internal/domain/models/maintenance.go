@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Maintenance is a window the server rejects RPCs of the app with Unavailable, AppID 0 means all apps.
+type Maintenance struct {
+	AppID int
+	// Message is shown to clients, e.g. what is going on and when it's over.
+	Message   string
+	StartedAt time.Time
+	// EndsAt is when the window is over by itself, zero means it lasts until it's turned off.
+	EndsAt time.Time
+}
@@ -0,0 +1,37 @@
+package models
+
+// EmailCategory is a category of non-essential emails users may opt out of. Emails without category,
+// e.g. verification codes, are essential and sent regardless of preferences.
+type EmailCategory string
+
+const (
+	EmailCategoryReminders EmailCategory = "reminders"
+	EmailCategorySecurity  EmailCategory = "security"
+	EmailCategoryProduct   EmailCategory = "product"
+)
+
+// NotificationPreferences are categories of non-essential emails the user receives.
+type NotificationPreferences struct {
+	Reminders bool
+	Security  bool
+	Product   bool
+}
+
+// DefaultNotificationPreferences are preferences of users who haven't changed them, every category is received.
+func DefaultNotificationPreferences() NotificationPreferences {
+	return NotificationPreferences{Reminders: true, Security: true, Product: true}
+}
+
+// Allows reports whether emails of the category are received, unknown categories are.
+func (p NotificationPreferences) Allows(category EmailCategory) bool {
+	switch category {
+	case EmailCategoryReminders:
+		return p.Reminders
+	case EmailCategorySecurity:
+		return p.Security
+	case EmailCategoryProduct:
+		return p.Product
+	default:
+		return true
+	}
+}
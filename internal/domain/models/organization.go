@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// OrgRole is a role of a member in an organization.
+type OrgRole string
+
+const (
+	// OrgRoleOwner manages the organization, including other owners.
+	OrgRoleOwner OrgRole = "owner"
+	// OrgRoleAdmin invites admins and members.
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleMember OrgRole = "member"
+)
+
+// Valid reports whether the role is known.
+func (r OrgRole) Valid() bool {
+	switch r {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleMember:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanInvite reports whether a member with the role may invite others with the invitee role.
+func (r OrgRole) CanInvite(invitee OrgRole) bool {
+	switch r {
+	case OrgRoleOwner:
+		return true
+	case OrgRoleAdmin:
+		return invitee != OrgRoleOwner
+	default:
+		return false
+	}
+}
+
+// Organization is a company or team users belong to, e.g. a customer of B2B app.
+type Organization struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+// OrganizationMember is membership of a user in an organization.
+type OrganizationMember struct {
+	OrgID int64
+	// OrgName is set when memberships of a user are listed.
+	OrgName  string
+	UserID   int64
+	Role     OrgRole
+	JoinedAt time.Time
+}
+
+// OrganizationInvitation is an invitation of an email to join the organization,
+// the code is emailed and must be presented by a user signed in with the email.
+type OrganizationInvitation struct {
+	ID        int64
+	OrgID     int64
+	Email     string
+	Role      OrgRole
+	Code      string
+	InvitedBy int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// AcceptedAt is zero until the invitation is accepted.
+	AcceptedAt time.Time
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// PhoneVerificationData is a pending verification of the phone number of the user.
+type PhoneVerificationData struct {
+	Email     string
+	Phone     string
+	Code      string
+	ExpiresAt time.Time
+	Attempts  int
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ServiceAccount is a non-human account of an app, e.g. for automation, it authenticates by its keys
+// instead of a password and gets tokens limited to its scopes.
+type ServiceAccount struct {
+	ID    int64
+	AppID int
+	// OrgID is organization the account acts for, 0 if it's not bound to one.
+	OrgID  int64
+	Name   string
+	Scopes []string
+	// DisabledAt is zero if the account is enabled.
+	DisabledAt time.Time
+	CreatedAt  time.Time
+}
+
+type ServiceAccountKeyKind string
+
+const (
+	// ServiceAccountKeyAPI is an API key, only SHA-256 hash of its secret is stored.
+	ServiceAccountKeyAPI ServiceAccountKeyKind = "api_key"
+	// ServiceAccountKeyPublic is a public key verifying JWT assertions signed by the account's private key.
+	ServiceAccountKeyPublic ServiceAccountKeyKind = "public_key"
+)
+
+// ServiceAccountKey is a credential of a service account.
+type ServiceAccountKey struct {
+	// ID is public part of API key or kid header of assertions signed by the key.
+	ID               string
+	ServiceAccountID int64
+	Kind             ServiceAccountKeyKind
+	// SecretHash is set for API keys.
+	SecretHash []byte
+	// PublicKey is PEM encoded public key, it's set for public keys.
+	PublicKey string
+	CreatedAt time.Time
+	// RevokedAt is zero if the key is not revoked.
+	RevokedAt time.Time
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Session is a record of issued access token, identified by jti claim of the token.
+type Session struct {
+	// ID is jti claim of the token.
+	ID string
+	// SSOSessionID is ID of the session issued by Login, which tokens issued by Authorize for other apps
+	// share without credentials. It's ID of the session itself for sessions issued by Login.
+	SSOSessionID string
+	// UserID is 0 for tokens of service accounts.
+	UserID int64
+	// ServiceAccountID is 0 for tokens of users.
+	ServiceAccountID int64
+	AppID            int
+	// Elevated token is one-time, it's consumed by the first successful validation.
+	Elevated bool
+	// IP, Country and City are of the client the token was issued to, empty if unknown.
+	IP        string
+	Country   string
+	City      string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// RevokedAt is zero if the session is not revoked.
+	RevokedAt time.Time
+	// ConsumedAt is zero if the elevated token is not used yet.
+	ConsumedAt time.Time
+}
+
+// SessionFilter selects sessions of a user.
+type SessionFilter struct {
+	UserID int64
+	// ActiveAt matches sessions which are not revoked, consumed or expired at the time, zero matches all sessions.
+	ActiveAt time.Time
+	// Before matches sessions issued before the session, only ID and IssuedAt are used.
+	// It's set to page through sessions, zero matches all sessions.
+	Before Session
+}
+
+// RefreshToken is a record of issued refresh token, the token itself is not stored.
+type RefreshToken struct {
+	// ID is SHA-256 of the token.
+	ID string
+	// SessionID is the SSO session the token is issued within, tokens are rejected once it's revoked.
+	SessionID string
+	UserID    int64
+	AppID     int
+	// Fingerprint identifies the client the token is bound to, e.g. hash of its certificate,
+	// only that client may exchange it. Empty if the token is not bound.
+	Fingerprint string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+	// UsedAt is zero until the token is exchanged, tokens are rotated by every exchange.
+	UsedAt time.Time
+}
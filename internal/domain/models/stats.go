@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Stats summarizes registrations and logins over a period, aggregated from the audit log,
+// and verifications, aggregated from verification stats.
+type Stats struct {
+	Since time.Time
+	Until time.Time
+	// Registrations counts registrations, including pending ones not verified yet.
+	Registrations int
+	// VerifiedRegistrations counts registrations of the period verified by now.
+	VerifiedRegistrations int
+	// ActiveUsers counts distinct users who logged in.
+	ActiveUsers     int
+	LoginsSucceeded int
+	LoginsFailed    int
+	// Verifications are funnels of verifications by type and app.
+	Verifications []VerificationFunnel
+}
+
+// VerificationRate returns share of registrations verified by now, 0 if there were none.
+func (s Stats) VerificationRate() float64 {
+	if s.Registrations == 0 {
+		return 0
+	}
+
+	return float64(s.VerifiedRegistrations) / float64(s.Registrations)
+}
+
+// VerificationEvent is a step of verification counted in verification stats.
+type VerificationEvent string
+
+const (
+	VerificationEventCreated   VerificationEvent = "created"
+	VerificationEventDelivered VerificationEvent = "delivered"
+	VerificationEventVerified  VerificationEvent = "verified"
+	VerificationEventExpired   VerificationEvent = "expired"
+	// VerificationEventFailed is a wrong code, each attempt is counted.
+	VerificationEventFailed VerificationEvent = "failed"
+)
+
+// VerificationFunnel counts verification events of the type and the app over a period.
+type VerificationFunnel struct {
+	Type VerificationType
+	// AppID is 0 for verifications not related to an app, e.g. registration.
+	AppID     int
+	Created   int
+	Delivered int
+	Verified  int
+	Expired   int
+	Failed    int
+}
+
+// ConversionRate returns share of created verifications which were verified, 0 if none were created.
+func (f VerificationFunnel) ConversionRate() float64 {
+	if f.Created == 0 {
+		return 0
+	}
+
+	return float64(f.Verified) / float64(f.Created)
+}
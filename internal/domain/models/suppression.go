@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type SuppressionSource string
+
+const (
+	SuppressionSourceBounce    SuppressionSource = "bounce"
+	SuppressionSourceComplaint SuppressionSource = "complaint"
+	SuppressionSourceManual    SuppressionSource = "manual"
+)
+
+// Suppression is an address the service must not send emails to.
+type Suppression struct {
+	Email     string
+	Source    SuppressionSource
+	Reason    string
+	CreatedAt time.Time
+}
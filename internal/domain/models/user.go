@@ -5,4 +5,9 @@ type User struct {
 	Email    string
 	PassHash []byte
 	Verified bool
+	// Phone is empty until user verifies a phone number.
+	Phone         string
+	PhoneVerified bool
+	// Directory is name of external user directory which authenticates the user, empty for local users.
+	Directory string
 }
@@ -4,8 +4,37 @@ import (
 	"time"
 )
 
+// VerificationType is a purpose the verification code was issued for.
+// Code issued for one purpose can't be used for another.
+type VerificationType string
+
+const (
+	VerificationTypeRegistration  VerificationType = "registration"
+	VerificationTypePasswordReset VerificationType = "password_reset"
+	VerificationTypeEmailChange   VerificationType = "email_change"
+	// VerificationTypeSignIn confirms login from a new device.
+	VerificationTypeSignIn VerificationType = "sign_in"
+	// VerificationTypeAccountDeletion confirms deletion of the account requested by the user.
+	VerificationTypeAccountDeletion VerificationType = "account_deletion"
+	// VerificationTypePhone is used for code format of phone verifications only,
+	// they are stored separately from email ones.
+	VerificationTypePhone VerificationType = "phone"
+)
+
 type VerificationData struct {
 	Email     string
+	Type      VerificationType
 	Code      string
 	ExpiresAt time.Time
+	// Attempts is a number of failed verification attempts.
+	Attempts int
+}
+
+// VerificationStatus describes pending verification for UIs, code is never exposed.
+type VerificationStatus struct {
+	Pending   bool
+	ExpiresAt time.Time
+	// RemainingAttempts is a number of wrong codes left before verification is invalidated,
+	// -1 if attempts are unlimited.
+	RemainingAttempts int
 }
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+type WebhookEventType string
+
+const (
+	WebhookEventUserRegistered    WebhookEventType = "user.registered"
+	WebhookEventUserVerified      WebhookEventType = "user.verified"
+	WebhookEventUserPasswordReset WebhookEventType = "user.password_reset"
+	// WebhookEventUserLoggedOut is back-channel logout, apps terminate their sessions of the user listed in it.
+	WebhookEventUserLoggedOut WebhookEventType = "user.logged_out"
+	// WebhookEventUserDeleted is sent to all apps once the user's account is deleted, apps delete their data of the user.
+	WebhookEventUserDeleted WebhookEventType = "user.deleted"
+)
+
+// WebhookEvent is an event delivered to webhooks subscribed to its type.
+type WebhookEvent struct {
+	Type WebhookEventType
+	// AppID restricts delivery to webhooks of the app, 0 means webhooks of all apps.
+	AppID int
+	Data  map[string]any
+}
+
+// Webhook is an endpoint of the app events are POSTed to.
+type Webhook struct {
+	ID    int64
+	AppID int
+	URL   string
+	// Secret signs payloads, so the app can check they are sent by the service.
+	Secret string
+	// Events the webhook is subscribed to, empty means all events.
+	Events []WebhookEventType
+}
+
+// Subscribed reports whether the webhook is subscribed to events of the type.
+func (w Webhook) Subscribed(eventType WebhookEventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead is a delivery which failed all attempts, it's retried only if replayed.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is a delivery of a single event to a single webhook.
+type WebhookDelivery struct {
+	ID            int64
+	Webhook       Webhook
+	Event         WebhookEventType
+	Payload       []byte
+	Status        WebhookDeliveryStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"grpc-service-ref/internal/lib/logger/sl"
+)
+
+// Event is a domain event, subscribers receive it by its concrete type, e.g. UserRegistered.
+type Event interface {
+	// Name identifies the event in logs.
+	Name() string
+}
+
+type handler func(ctx context.Context, event Event)
+
+// Bus delivers events published by services to subscribers within the process, so reactions to them,
+// e.g. audit and webhooks, aren't wired into the flows publishing them.
+//
+// Delivery is synchronous, in order of subscription: handlers see the context of the publisher,
+// with its client IP and deadline, and are done once Publish returns. Handlers must not fail
+// the published action, their errors are theirs to log.
+type Bus struct {
+	log *slog.Logger
+
+	mu       sync.RWMutex
+	handlers []handler
+}
+
+func New(log *slog.Logger) *Bus {
+	return &Bus{log: log}
+}
+
+// Subscribe makes handle receive events of type E published to the bus.
+// Subscriptions are made on startup, before events are published.
+func Subscribe[E Event](b *Bus, handle func(ctx context.Context, event E)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, func(ctx context.Context, event Event) {
+		if e, ok := event.(E); ok {
+			handle(ctx, e)
+		}
+	})
+}
+
+// Publish delivers the event to its subscribers. A panicking subscriber is logged and skipped,
+// so it neither fails the action the event is about nor keeps the event from other subscribers.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		b.deliver(ctx, h, event)
+	}
+}
+
+func (b *Bus) deliver(ctx context.Context, h handler, event Event) {
+	const op = "events.Bus.Publish"
+
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("event subscriber panicked",
+				slog.String("op", op),
+				slog.String("event", event.Name()),
+				sl.Err(fmt.Errorf("%v", r)),
+			)
+		}
+	}()
+
+	h(ctx, event)
+}
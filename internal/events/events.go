@@ -0,0 +1,68 @@
+package events
+
+import (
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+)
+
+// RegistrationPending is published once registration is saved until the email is verified.
+type RegistrationPending struct {
+	Email string
+}
+
+// UserRegistered is published once the user is created. Activated is set for users created
+// from pending registrations by email verification, their registration was published as pending before.
+type UserRegistered struct {
+	UserID    int64
+	Email     string
+	Activated bool
+}
+
+// EmailVerified is published once email of the registered user is verified.
+type EmailVerified struct {
+	UserID int64
+	Email  string
+}
+
+// PasswordReset is published once password of the user is reset by code.
+type PasswordReset struct {
+	UserID int64
+	Email  string
+	// SessionsRevoked is how many tokens of the user were revoked by the reset.
+	SessionsRevoked int
+}
+
+// PasswordChanged is published once the user changed password knowing the current one.
+type PasswordChanged struct {
+	UserID          int64
+	Email           string
+	SessionsRevoked int
+}
+
+// LoggedOut is published once the user logged out, Sessions are sessions of the SSO session revoked by it.
+type LoggedOut struct {
+	UserID       int64
+	Email        string
+	AppID        int
+	SSOSessionID string
+	Sessions     []models.Session
+}
+
+// CredentialStuffingDetected is published once failed logins of all clients, or of a single client IP,
+// exceed their threshold and logins are defended until Until. IP is empty for the global scope.
+type CredentialStuffingDetected struct {
+	Scope    string
+	IP       string
+	Failures int
+	Window   time.Duration
+	Until    time.Time
+}
+
+func (RegistrationPending) Name() string        { return "registration_pending" }
+func (UserRegistered) Name() string             { return "user_registered" }
+func (EmailVerified) Name() string              { return "email_verified" }
+func (PasswordReset) Name() string              { return "password_reset" }
+func (PasswordChanged) Name() string            { return "password_changed" }
+func (LoggedOut) Name() string                  { return "logged_out" }
+func (CredentialStuffingDetected) Name() string { return "credential_stuffing_detected" }
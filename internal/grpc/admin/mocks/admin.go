@@ -0,0 +1,894 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Admin is an autogenerated mock type for the Admin type
+type Admin struct {
+	mock.Mock
+}
+
+type Admin_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Admin) EXPECT() *Admin_Expecter {
+	return &Admin_Expecter{mock: &_m.Mock}
+}
+
+// App provides a mock function with given fields: ctx, appID
+func (_m *Admin) App(ctx context.Context, appID int) (models.App, error) {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for App")
+	}
+
+	var r0 models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.App, error)); ok {
+		return rf(ctx, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.App); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		r0 = ret.Get(0).(models.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_App_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'App'
+type Admin_App_Call struct {
+	*mock.Call
+}
+
+// App is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *Admin_Expecter) App(ctx interface{}, appID interface{}) *Admin_App_Call {
+	return &Admin_App_Call{Call: _e.mock.On("App", ctx, appID)}
+}
+
+func (_c *Admin_App_Call) Run(run func(ctx context.Context, appID int)) *Admin_App_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Admin_App_Call) Return(_a0 models.App, _a1 error) *Admin_App_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_App_Call) RunAndReturn(run func(context.Context, int) (models.App, error)) *Admin_App_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Apps provides a mock function with given fields: ctx, afterID, limit
+func (_m *Admin) Apps(ctx context.Context, afterID int, limit int) ([]models.App, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Apps")
+	}
+
+	var r0 []models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.App, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.App); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.App)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_Apps_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Apps'
+type Admin_Apps_Call struct {
+	*mock.Call
+}
+
+// Apps is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int
+//   - limit int
+func (_e *Admin_Expecter) Apps(ctx interface{}, afterID interface{}, limit interface{}) *Admin_Apps_Call {
+	return &Admin_Apps_Call{Call: _e.mock.On("Apps", ctx, afterID, limit)}
+}
+
+func (_c *Admin_Apps_Call) Run(run func(ctx context.Context, afterID int, limit int)) *Admin_Apps_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Admin_Apps_Call) Return(_a0 []models.App, _a1 error) *Admin_Apps_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_Apps_Call) RunAndReturn(run func(context.Context, int, int) ([]models.App, error)) *Admin_Apps_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BanUser provides a mock function with given fields: ctx, userID, appID, reason, expiresAt
+func (_m *Admin) BanUser(ctx context.Context, userID int64, appID int, reason string, expiresAt time.Time) (models.AppBan, error) {
+	ret := _m.Called(ctx, userID, appID, reason, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BanUser")
+	}
+
+	var r0 models.AppBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, string, time.Time) (models.AppBan, error)); ok {
+		return rf(ctx, userID, appID, reason, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, string, time.Time) models.AppBan); ok {
+		r0 = rf(ctx, userID, appID, reason, expiresAt)
+	} else {
+		r0 = ret.Get(0).(models.AppBan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, appID, reason, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_BanUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BanUser'
+type Admin_BanUser_Call struct {
+	*mock.Call
+}
+
+// BanUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - appID int
+//   - reason string
+//   - expiresAt time.Time
+func (_e *Admin_Expecter) BanUser(ctx interface{}, userID interface{}, appID interface{}, reason interface{}, expiresAt interface{}) *Admin_BanUser_Call {
+	return &Admin_BanUser_Call{Call: _e.mock.On("BanUser", ctx, userID, appID, reason, expiresAt)}
+}
+
+func (_c *Admin_BanUser_Call) Run(run func(ctx context.Context, userID int64, appID int, reason string, expiresAt time.Time)) *Admin_BanUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int), args[3].(string), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Admin_BanUser_Call) Return(_a0 models.AppBan, _a1 error) *Admin_BanUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_BanUser_Call) RunAndReturn(run func(context.Context, int64, int, string, time.Time) (models.AppBan, error)) *Admin_BanUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Bans provides a mock function with given fields: ctx, userID
+func (_m *Admin) Bans(ctx context.Context, userID int64) ([]models.AppBan, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bans")
+	}
+
+	var r0 []models.AppBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.AppBan, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.AppBan); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AppBan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_Bans_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Bans'
+type Admin_Bans_Call struct {
+	*mock.Call
+}
+
+// Bans is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *Admin_Expecter) Bans(ctx interface{}, userID interface{}) *Admin_Bans_Call {
+	return &Admin_Bans_Call{Call: _e.mock.On("Bans", ctx, userID)}
+}
+
+func (_c *Admin_Bans_Call) Run(run func(ctx context.Context, userID int64)) *Admin_Bans_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Admin_Bans_Call) Return(_a0 []models.AppBan, _a1 error) *Admin_Bans_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_Bans_Call) RunAndReturn(run func(context.Context, int64) ([]models.AppBan, error)) *Admin_Bans_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateApp provides a mock function with given fields: ctx, name
+func (_m *Admin) CreateApp(ctx context.Context, name string) (models.App, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateApp")
+	}
+
+	var r0 models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.App, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.App); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(models.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_CreateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateApp'
+type Admin_CreateApp_Call struct {
+	*mock.Call
+}
+
+// CreateApp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *Admin_Expecter) CreateApp(ctx interface{}, name interface{}) *Admin_CreateApp_Call {
+	return &Admin_CreateApp_Call{Call: _e.mock.On("CreateApp", ctx, name)}
+}
+
+func (_c *Admin_CreateApp_Call) Run(run func(ctx context.Context, name string)) *Admin_CreateApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Admin_CreateApp_Call) Return(_a0 models.App, _a1 error) *Admin_CreateApp_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_CreateApp_Call) RunAndReturn(run func(context.Context, string) (models.App, error)) *Admin_CreateApp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSessions provides a mock function with given fields: ctx, userID, appID
+func (_m *Admin) RevokeSessions(ctx context.Context, userID int64, appID int) (int, error) {
+	ret := _m.Called(ctx, userID, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) (int, error)); ok {
+		return rf(ctx, userID, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) int); ok {
+		r0 = rf(ctx, userID, appID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, userID, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_RevokeSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSessions'
+type Admin_RevokeSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - appID int
+func (_e *Admin_Expecter) RevokeSessions(ctx interface{}, userID interface{}, appID interface{}) *Admin_RevokeSessions_Call {
+	return &Admin_RevokeSessions_Call{Call: _e.mock.On("RevokeSessions", ctx, userID, appID)}
+}
+
+func (_c *Admin_RevokeSessions_Call) Run(run func(ctx context.Context, userID int64, appID int)) *Admin_RevokeSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Admin_RevokeSessions_Call) Return(revoked int, err error) *Admin_RevokeSessions_Call {
+	_c.Call.Return(revoked, err)
+	return _c
+}
+
+func (_c *Admin_RevokeSessions_Call) RunAndReturn(run func(context.Context, int64, int) (int, error)) *Admin_RevokeSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RotateAppSecret provides a mock function with given fields: ctx, appID
+func (_m *Admin) RotateAppSecret(ctx context.Context, appID int) (models.App, error) {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateAppSecret")
+	}
+
+	var r0 models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.App, error)); ok {
+		return rf(ctx, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.App); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		r0 = ret.Get(0).(models.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_RotateAppSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateAppSecret'
+type Admin_RotateAppSecret_Call struct {
+	*mock.Call
+}
+
+// RotateAppSecret is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *Admin_Expecter) RotateAppSecret(ctx interface{}, appID interface{}) *Admin_RotateAppSecret_Call {
+	return &Admin_RotateAppSecret_Call{Call: _e.mock.On("RotateAppSecret", ctx, appID)}
+}
+
+func (_c *Admin_RotateAppSecret_Call) Run(run func(ctx context.Context, appID int)) *Admin_RotateAppSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Admin_RotateAppSecret_Call) Return(_a0 models.App, _a1 error) *Admin_RotateAppSecret_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_RotateAppSecret_Call) RunAndReturn(run func(context.Context, int) (models.App, error)) *Admin_RotateAppSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Sessions provides a mock function with given fields: ctx, filter, limit
+func (_m *Admin) Sessions(ctx context.Context, filter models.SessionFilter, limit int) ([]models.Session, error) {
+	ret := _m.Called(ctx, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sessions")
+	}
+
+	var r0 []models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.SessionFilter, int) ([]models.Session, error)); ok {
+		return rf(ctx, filter, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.SessionFilter, int) []models.Session); ok {
+		r0 = rf(ctx, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.SessionFilter, int) error); ok {
+		r1 = rf(ctx, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_Sessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Sessions'
+type Admin_Sessions_Call struct {
+	*mock.Call
+}
+
+// Sessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.SessionFilter
+//   - limit int
+func (_e *Admin_Expecter) Sessions(ctx interface{}, filter interface{}, limit interface{}) *Admin_Sessions_Call {
+	return &Admin_Sessions_Call{Call: _e.mock.On("Sessions", ctx, filter, limit)}
+}
+
+func (_c *Admin_Sessions_Call) Run(run func(ctx context.Context, filter models.SessionFilter, limit int)) *Admin_Sessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.SessionFilter), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Admin_Sessions_Call) Return(_a0 []models.Session, _a1 error) *Admin_Sessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_Sessions_Call) RunAndReturn(run func(context.Context, models.SessionFilter, int) ([]models.Session, error)) *Admin_Sessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAdmin provides a mock function with given fields: ctx, userID, isAdmin
+func (_m *Admin) SetAdmin(ctx context.Context, userID int64, isAdmin bool) error {
+	ret := _m.Called(ctx, userID, isAdmin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAdmin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) error); ok {
+		r0 = rf(ctx, userID, isAdmin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Admin_SetAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAdmin'
+type Admin_SetAdmin_Call struct {
+	*mock.Call
+}
+
+// SetAdmin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - isAdmin bool
+func (_e *Admin_Expecter) SetAdmin(ctx interface{}, userID interface{}, isAdmin interface{}) *Admin_SetAdmin_Call {
+	return &Admin_SetAdmin_Call{Call: _e.mock.On("SetAdmin", ctx, userID, isAdmin)}
+}
+
+func (_c *Admin_SetAdmin_Call) Run(run func(ctx context.Context, userID int64, isAdmin bool)) *Admin_SetAdmin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *Admin_SetAdmin_Call) Return(_a0 error) *Admin_SetAdmin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Admin_SetAdmin_Call) RunAndReturn(run func(context.Context, int64, bool) error) *Admin_SetAdmin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAppTokenClaims provides a mock function with given fields: ctx, appID, issuer, audience
+func (_m *Admin) SetAppTokenClaims(ctx context.Context, appID int, issuer string, audience string) (models.App, error) {
+	ret := _m.Called(ctx, appID, issuer, audience)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAppTokenClaims")
+	}
+
+	var r0 models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (models.App, error)); ok {
+		return rf(ctx, appID, issuer, audience)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) models.App); ok {
+		r0 = rf(ctx, appID, issuer, audience)
+	} else {
+		r0 = ret.Get(0).(models.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, appID, issuer, audience)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_SetAppTokenClaims_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAppTokenClaims'
+type Admin_SetAppTokenClaims_Call struct {
+	*mock.Call
+}
+
+// SetAppTokenClaims is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - issuer string
+//   - audience string
+func (_e *Admin_Expecter) SetAppTokenClaims(ctx interface{}, appID interface{}, issuer interface{}, audience interface{}) *Admin_SetAppTokenClaims_Call {
+	return &Admin_SetAppTokenClaims_Call{Call: _e.mock.On("SetAppTokenClaims", ctx, appID, issuer, audience)}
+}
+
+func (_c *Admin_SetAppTokenClaims_Call) Run(run func(ctx context.Context, appID int, issuer string, audience string)) *Admin_SetAppTokenClaims_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Admin_SetAppTokenClaims_Call) Return(_a0 models.App, _a1 error) *Admin_SetAppTokenClaims_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_SetAppTokenClaims_Call) RunAndReturn(run func(context.Context, int, string, string) (models.App, error)) *Admin_SetAppTokenClaims_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAppWebSettings provides a mock function with given fields: ctx, appID, origins, csp
+func (_m *Admin) SetAppWebSettings(ctx context.Context, appID int, origins []string, csp string) (models.App, error) {
+	ret := _m.Called(ctx, appID, origins, csp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAppWebSettings")
+	}
+
+	var r0 models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string, string) (models.App, error)); ok {
+		return rf(ctx, appID, origins, csp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string, string) models.App); ok {
+		r0 = rf(ctx, appID, origins, csp)
+	} else {
+		r0 = ret.Get(0).(models.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, []string, string) error); ok {
+		r1 = rf(ctx, appID, origins, csp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_SetAppWebSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAppWebSettings'
+type Admin_SetAppWebSettings_Call struct {
+	*mock.Call
+}
+
+// SetAppWebSettings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - origins []string
+//   - csp string
+func (_e *Admin_Expecter) SetAppWebSettings(ctx interface{}, appID interface{}, origins interface{}, csp interface{}) *Admin_SetAppWebSettings_Call {
+	return &Admin_SetAppWebSettings_Call{Call: _e.mock.On("SetAppWebSettings", ctx, appID, origins, csp)}
+}
+
+func (_c *Admin_SetAppWebSettings_Call) Run(run func(ctx context.Context, appID int, origins []string, csp string)) *Admin_SetAppWebSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].([]string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Admin_SetAppWebSettings_Call) Return(_a0 models.App, _a1 error) *Admin_SetAppWebSettings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_SetAppWebSettings_Call) RunAndReturn(run func(context.Context, int, []string, string) (models.App, error)) *Admin_SetAppWebSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserVerified provides a mock function with given fields: ctx, userID, verified, reason
+func (_m *Admin) SetUserVerified(ctx context.Context, userID int64, verified bool, reason string) error {
+	ret := _m.Called(ctx, userID, verified, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool, string) error); ok {
+		r0 = rf(ctx, userID, verified, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Admin_SetUserVerified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserVerified'
+type Admin_SetUserVerified_Call struct {
+	*mock.Call
+}
+
+// SetUserVerified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - verified bool
+//   - reason string
+func (_e *Admin_Expecter) SetUserVerified(ctx interface{}, userID interface{}, verified interface{}, reason interface{}) *Admin_SetUserVerified_Call {
+	return &Admin_SetUserVerified_Call{Call: _e.mock.On("SetUserVerified", ctx, userID, verified, reason)}
+}
+
+func (_c *Admin_SetUserVerified_Call) Run(run func(ctx context.Context, userID int64, verified bool, reason string)) *Admin_SetUserVerified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(bool), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Admin_SetUserVerified_Call) Return(_a0 error) *Admin_SetUserVerified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Admin_SetUserVerified_Call) RunAndReturn(run func(context.Context, int64, bool, string) error) *Admin_SetUserVerified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnbanUser provides a mock function with given fields: ctx, userID, appID
+func (_m *Admin) UnbanUser(ctx context.Context, userID int64, appID int) error {
+	ret := _m.Called(ctx, userID, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnbanUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) error); ok {
+		r0 = rf(ctx, userID, appID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Admin_UnbanUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnbanUser'
+type Admin_UnbanUser_Call struct {
+	*mock.Call
+}
+
+// UnbanUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - appID int
+func (_e *Admin_Expecter) UnbanUser(ctx interface{}, userID interface{}, appID interface{}) *Admin_UnbanUser_Call {
+	return &Admin_UnbanUser_Call{Call: _e.mock.On("UnbanUser", ctx, userID, appID)}
+}
+
+func (_c *Admin_UnbanUser_Call) Run(run func(ctx context.Context, userID int64, appID int)) *Admin_UnbanUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Admin_UnbanUser_Call) Return(_a0 error) *Admin_UnbanUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Admin_UnbanUser_Call) RunAndReturn(run func(context.Context, int64, int) error) *Admin_UnbanUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// User provides a mock function with given fields: ctx, email
+func (_m *Admin) User(ctx context.Context, email string) (models.User, bool, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for User")
+	}
+
+	var r0 models.User
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.User, bool, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, email)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Admin_User_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'User'
+type Admin_User_Call struct {
+	*mock.Call
+}
+
+// User is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *Admin_Expecter) User(ctx interface{}, email interface{}) *Admin_User_Call {
+	return &Admin_User_Call{Call: _e.mock.On("User", ctx, email)}
+}
+
+func (_c *Admin_User_Call) Run(run func(ctx context.Context, email string)) *Admin_User_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Admin_User_Call) Return(user models.User, isAdmin bool, err error) *Admin_User_Call {
+	_c.Call.Return(user, isAdmin, err)
+	return _c
+}
+
+func (_c *Admin_User_Call) RunAndReturn(run func(context.Context, string) (models.User, bool, error)) *Admin_User_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Users provides a mock function with given fields: ctx, afterID, limit
+func (_m *Admin) Users(ctx context.Context, afterID int64, limit int) ([]models.User, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Users")
+	}
+
+	var r0 []models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]models.User, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []models.User); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Admin_Users_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Users'
+type Admin_Users_Call struct {
+	*mock.Call
+}
+
+// Users is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *Admin_Expecter) Users(ctx interface{}, afterID interface{}, limit interface{}) *Admin_Users_Call {
+	return &Admin_Users_Call{Call: _e.mock.On("Users", ctx, afterID, limit)}
+}
+
+func (_c *Admin_Users_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *Admin_Users_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Admin_Users_Call) Return(_a0 []models.User, _a1 error) *Admin_Users_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Admin_Users_Call) RunAndReturn(run func(context.Context, int64, int) ([]models.User, error)) *Admin_Users_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAdmin creates a new instance of Admin. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdmin(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Admin {
+	mock := &Admin{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
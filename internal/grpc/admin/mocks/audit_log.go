@@ -0,0 +1,97 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditLog is an autogenerated mock type for the AuditLog type
+type AuditLog struct {
+	mock.Mock
+}
+
+type AuditLog_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditLog) EXPECT() *AuditLog_Expecter {
+	return &AuditLog_Expecter{mock: &_m.Mock}
+}
+
+// Events provides a mock function with given fields: ctx, filter, limit
+func (_m *AuditLog) Events(ctx context.Context, filter models.AuditFilter, limit int) ([]models.AuditEvent, error) {
+	ret := _m.Called(ctx, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Events")
+	}
+
+	var r0 []models.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AuditFilter, int) ([]models.AuditEvent, error)); ok {
+		return rf(ctx, filter, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.AuditFilter, int) []models.AuditEvent); ok {
+		r0 = rf(ctx, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.AuditFilter, int) error); ok {
+		r1 = rf(ctx, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditLog_Events_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Events'
+type AuditLog_Events_Call struct {
+	*mock.Call
+}
+
+// Events is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.AuditFilter
+//   - limit int
+func (_e *AuditLog_Expecter) Events(ctx interface{}, filter interface{}, limit interface{}) *AuditLog_Events_Call {
+	return &AuditLog_Events_Call{Call: _e.mock.On("Events", ctx, filter, limit)}
+}
+
+func (_c *AuditLog_Events_Call) Run(run func(ctx context.Context, filter models.AuditFilter, limit int)) *AuditLog_Events_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AuditFilter), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *AuditLog_Events_Call) Return(_a0 []models.AuditEvent, _a1 error) *AuditLog_Events_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditLog_Events_Call) RunAndReturn(run func(context.Context, models.AuditFilter, int) ([]models.AuditEvent, error)) *AuditLog_Events_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditLog creates a new instance of AuditLog. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditLog(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditLog {
+	mock := &AuditLog{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,101 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailPreviews is an autogenerated mock type for the EmailPreviews type
+type EmailPreviews struct {
+	mock.Mock
+}
+
+type EmailPreviews_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EmailPreviews) EXPECT() *EmailPreviews_Expecter {
+	return &EmailPreviews_Expecter{mock: &_m.Mock}
+}
+
+// Preview provides a mock function with given fields: ctx, emailType, sample
+func (_m *EmailPreviews) Preview(ctx context.Context, emailType string, sample map[string]string) (string, string, error) {
+	ret := _m.Called(ctx, emailType, sample)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Preview")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string) (string, string, error)); ok {
+		return rf(ctx, emailType, sample)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string) string); ok {
+		r0 = rf(ctx, emailType, sample)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]string) string); ok {
+		r1 = rf(ctx, emailType, sample)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, map[string]string) error); ok {
+		r2 = rf(ctx, emailType, sample)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// EmailPreviews_Preview_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Preview'
+type EmailPreviews_Preview_Call struct {
+	*mock.Call
+}
+
+// Preview is a helper method to define mock.On call
+//   - ctx context.Context
+//   - emailType string
+//   - sample map[string]string
+func (_e *EmailPreviews_Expecter) Preview(ctx interface{}, emailType interface{}, sample interface{}) *EmailPreviews_Preview_Call {
+	return &EmailPreviews_Preview_Call{Call: _e.mock.On("Preview", ctx, emailType, sample)}
+}
+
+func (_c *EmailPreviews_Preview_Call) Run(run func(ctx context.Context, emailType string, sample map[string]string)) *EmailPreviews_Preview_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(map[string]string))
+	})
+	return _c
+}
+
+func (_c *EmailPreviews_Preview_Call) Return(subject string, body string, err error) *EmailPreviews_Preview_Call {
+	_c.Call.Return(subject, body, err)
+	return _c
+}
+
+func (_c *EmailPreviews_Preview_Call) RunAndReturn(run func(context.Context, string, map[string]string) (string, string, error)) *EmailPreviews_Preview_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEmailPreviews creates a new instance of EmailPreviews. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailPreviews(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailPreviews {
+	mock := &EmailPreviews{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
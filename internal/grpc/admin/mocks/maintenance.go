@@ -0,0 +1,203 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Maintenance is an autogenerated mock type for the Maintenance type
+type Maintenance struct {
+	mock.Mock
+}
+
+type Maintenance_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Maintenance) EXPECT() *Maintenance_Expecter {
+	return &Maintenance_Expecter{mock: &_m.Mock}
+}
+
+// Disable provides a mock function with given fields: ctx, appID
+func (_m *Maintenance) Disable(ctx context.Context, appID int) error {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Maintenance_Disable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Disable'
+type Maintenance_Disable_Call struct {
+	*mock.Call
+}
+
+// Disable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *Maintenance_Expecter) Disable(ctx interface{}, appID interface{}) *Maintenance_Disable_Call {
+	return &Maintenance_Disable_Call{Call: _e.mock.On("Disable", ctx, appID)}
+}
+
+func (_c *Maintenance_Disable_Call) Run(run func(ctx context.Context, appID int)) *Maintenance_Disable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Maintenance_Disable_Call) Return(_a0 error) *Maintenance_Disable_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Maintenance_Disable_Call) RunAndReturn(run func(context.Context, int) error) *Maintenance_Disable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Enable provides a mock function with given fields: ctx, appID, message, endsAt
+func (_m *Maintenance) Enable(ctx context.Context, appID int, message string, endsAt time.Time) (models.Maintenance, error) {
+	ret := _m.Called(ctx, appID, message, endsAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enable")
+	}
+
+	var r0 models.Maintenance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) (models.Maintenance, error)); ok {
+		return rf(ctx, appID, message, endsAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) models.Maintenance); ok {
+		r0 = rf(ctx, appID, message, endsAt)
+	} else {
+		r0 = ret.Get(0).(models.Maintenance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, time.Time) error); ok {
+		r1 = rf(ctx, appID, message, endsAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Maintenance_Enable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enable'
+type Maintenance_Enable_Call struct {
+	*mock.Call
+}
+
+// Enable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - message string
+//   - endsAt time.Time
+func (_e *Maintenance_Expecter) Enable(ctx interface{}, appID interface{}, message interface{}, endsAt interface{}) *Maintenance_Enable_Call {
+	return &Maintenance_Enable_Call{Call: _e.mock.On("Enable", ctx, appID, message, endsAt)}
+}
+
+func (_c *Maintenance_Enable_Call) Run(run func(ctx context.Context, appID int, message string, endsAt time.Time)) *Maintenance_Enable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Maintenance_Enable_Call) Return(_a0 models.Maintenance, _a1 error) *Maintenance_Enable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Maintenance_Enable_Call) RunAndReturn(run func(context.Context, int, string, time.Time) (models.Maintenance, error)) *Maintenance_Enable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Windows provides a mock function with given fields: ctx
+func (_m *Maintenance) Windows(ctx context.Context) ([]models.Maintenance, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Windows")
+	}
+
+	var r0 []models.Maintenance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Maintenance, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Maintenance); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Maintenance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Maintenance_Windows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Windows'
+type Maintenance_Windows_Call struct {
+	*mock.Call
+}
+
+// Windows is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Maintenance_Expecter) Windows(ctx interface{}) *Maintenance_Windows_Call {
+	return &Maintenance_Windows_Call{Call: _e.mock.On("Windows", ctx)}
+}
+
+func (_c *Maintenance_Windows_Call) Run(run func(ctx context.Context)) *Maintenance_Windows_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Maintenance_Windows_Call) Return(_a0 []models.Maintenance, _a1 error) *Maintenance_Windows_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Maintenance_Windows_Call) RunAndReturn(run func(context.Context) ([]models.Maintenance, error)) *Maintenance_Windows_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMaintenance creates a new instance of Maintenance. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMaintenance(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Maintenance {
+	mock := &Maintenance{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,372 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ServiceAccounts is an autogenerated mock type for the ServiceAccounts type
+type ServiceAccounts struct {
+	mock.Mock
+}
+
+type ServiceAccounts_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ServiceAccounts) EXPECT() *ServiceAccounts_Expecter {
+	return &ServiceAccounts_Expecter{mock: &_m.Mock}
+}
+
+// AddPublicKey provides a mock function with given fields: ctx, id, publicKey
+func (_m *ServiceAccounts) AddPublicKey(ctx context.Context, id int64, publicKey string) (models.ServiceAccountKey, error) {
+	ret := _m.Called(ctx, id, publicKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddPublicKey")
+	}
+
+	var r0 models.ServiceAccountKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (models.ServiceAccountKey, error)); ok {
+		return rf(ctx, id, publicKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) models.ServiceAccountKey); ok {
+		r0 = rf(ctx, id, publicKey)
+	} else {
+		r0 = ret.Get(0).(models.ServiceAccountKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, id, publicKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ServiceAccounts_AddPublicKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddPublicKey'
+type ServiceAccounts_AddPublicKey_Call struct {
+	*mock.Call
+}
+
+// AddPublicKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - publicKey string
+func (_e *ServiceAccounts_Expecter) AddPublicKey(ctx interface{}, id interface{}, publicKey interface{}) *ServiceAccounts_AddPublicKey_Call {
+	return &ServiceAccounts_AddPublicKey_Call{Call: _e.mock.On("AddPublicKey", ctx, id, publicKey)}
+}
+
+func (_c *ServiceAccounts_AddPublicKey_Call) Run(run func(ctx context.Context, id int64, publicKey string)) *ServiceAccounts_AddPublicKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *ServiceAccounts_AddPublicKey_Call) Return(_a0 models.ServiceAccountKey, _a1 error) *ServiceAccounts_AddPublicKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ServiceAccounts_AddPublicKey_Call) RunAndReturn(run func(context.Context, int64, string) (models.ServiceAccountKey, error)) *ServiceAccounts_AddPublicKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, appID, orgID, name, scopes
+func (_m *ServiceAccounts) Create(ctx context.Context, appID int, orgID int64, name string, scopes []string) (models.ServiceAccount, error) {
+	ret := _m.Called(ctx, appID, orgID, name, scopes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 models.ServiceAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, string, []string) (models.ServiceAccount, error)); ok {
+		return rf(ctx, appID, orgID, name, scopes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, string, []string) models.ServiceAccount); ok {
+		r0 = rf(ctx, appID, orgID, name, scopes)
+	} else {
+		r0 = ret.Get(0).(models.ServiceAccount)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int64, string, []string) error); ok {
+		r1 = rf(ctx, appID, orgID, name, scopes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ServiceAccounts_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type ServiceAccounts_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - orgID int64
+//   - name string
+//   - scopes []string
+func (_e *ServiceAccounts_Expecter) Create(ctx interface{}, appID interface{}, orgID interface{}, name interface{}, scopes interface{}) *ServiceAccounts_Create_Call {
+	return &ServiceAccounts_Create_Call{Call: _e.mock.On("Create", ctx, appID, orgID, name, scopes)}
+}
+
+func (_c *ServiceAccounts_Create_Call) Run(run func(ctx context.Context, appID int, orgID int64, name string, scopes []string)) *ServiceAccounts_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int64), args[3].(string), args[4].([]string))
+	})
+	return _c
+}
+
+func (_c *ServiceAccounts_Create_Call) Return(_a0 models.ServiceAccount, _a1 error) *ServiceAccounts_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ServiceAccounts_Create_Call) RunAndReturn(run func(context.Context, int, int64, string, []string) (models.ServiceAccount, error)) *ServiceAccounts_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, id
+func (_m *ServiceAccounts) CreateAPIKey(ctx context.Context, id int64) (models.ServiceAccountKey, string, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 models.ServiceAccountKey
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.ServiceAccountKey, string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.ServiceAccountKey); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.ServiceAccountKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) string); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ServiceAccounts_CreateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIKey'
+type ServiceAccounts_CreateAPIKey_Call struct {
+	*mock.Call
+}
+
+// CreateAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *ServiceAccounts_Expecter) CreateAPIKey(ctx interface{}, id interface{}) *ServiceAccounts_CreateAPIKey_Call {
+	return &ServiceAccounts_CreateAPIKey_Call{Call: _e.mock.On("CreateAPIKey", ctx, id)}
+}
+
+func (_c *ServiceAccounts_CreateAPIKey_Call) Run(run func(ctx context.Context, id int64)) *ServiceAccounts_CreateAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *ServiceAccounts_CreateAPIKey_Call) Return(key models.ServiceAccountKey, apiKey string, err error) *ServiceAccounts_CreateAPIKey_Call {
+	_c.Call.Return(key, apiKey, err)
+	return _c
+}
+
+func (_c *ServiceAccounts_CreateAPIKey_Call) RunAndReturn(run func(context.Context, int64) (models.ServiceAccountKey, string, error)) *ServiceAccounts_CreateAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Disable provides a mock function with given fields: ctx, id
+func (_m *ServiceAccounts) Disable(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ServiceAccounts_Disable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Disable'
+type ServiceAccounts_Disable_Call struct {
+	*mock.Call
+}
+
+// Disable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *ServiceAccounts_Expecter) Disable(ctx interface{}, id interface{}) *ServiceAccounts_Disable_Call {
+	return &ServiceAccounts_Disable_Call{Call: _e.mock.On("Disable", ctx, id)}
+}
+
+func (_c *ServiceAccounts_Disable_Call) Run(run func(ctx context.Context, id int64)) *ServiceAccounts_Disable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *ServiceAccounts_Disable_Call) Return(_a0 error) *ServiceAccounts_Disable_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ServiceAccounts_Disable_Call) RunAndReturn(run func(context.Context, int64) error) *ServiceAccounts_Disable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeKey provides a mock function with given fields: ctx, keyID
+func (_m *ServiceAccounts) RevokeKey(ctx context.Context, keyID string) error {
+	ret := _m.Called(ctx, keyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, keyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ServiceAccounts_RevokeKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeKey'
+type ServiceAccounts_RevokeKey_Call struct {
+	*mock.Call
+}
+
+// RevokeKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyID string
+func (_e *ServiceAccounts_Expecter) RevokeKey(ctx interface{}, keyID interface{}) *ServiceAccounts_RevokeKey_Call {
+	return &ServiceAccounts_RevokeKey_Call{Call: _e.mock.On("RevokeKey", ctx, keyID)}
+}
+
+func (_c *ServiceAccounts_RevokeKey_Call) Run(run func(ctx context.Context, keyID string)) *ServiceAccounts_RevokeKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ServiceAccounts_RevokeKey_Call) Return(_a0 error) *ServiceAccounts_RevokeKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ServiceAccounts_RevokeKey_Call) RunAndReturn(run func(context.Context, string) error) *ServiceAccounts_RevokeKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ServiceAccounts provides a mock function with given fields: ctx, appID
+func (_m *ServiceAccounts) ServiceAccounts(ctx context.Context, appID int) ([]models.ServiceAccount, error) {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ServiceAccounts")
+	}
+
+	var r0 []models.ServiceAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.ServiceAccount, error)); ok {
+		return rf(ctx, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ServiceAccount); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ServiceAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ServiceAccounts_ServiceAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ServiceAccounts'
+type ServiceAccounts_ServiceAccounts_Call struct {
+	*mock.Call
+}
+
+// ServiceAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *ServiceAccounts_Expecter) ServiceAccounts(ctx interface{}, appID interface{}) *ServiceAccounts_ServiceAccounts_Call {
+	return &ServiceAccounts_ServiceAccounts_Call{Call: _e.mock.On("ServiceAccounts", ctx, appID)}
+}
+
+func (_c *ServiceAccounts_ServiceAccounts_Call) Run(run func(ctx context.Context, appID int)) *ServiceAccounts_ServiceAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *ServiceAccounts_ServiceAccounts_Call) Return(_a0 []models.ServiceAccount, _a1 error) *ServiceAccounts_ServiceAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ServiceAccounts_ServiceAccounts_Call) RunAndReturn(run func(context.Context, int) ([]models.ServiceAccount, error)) *ServiceAccounts_ServiceAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewServiceAccounts creates a new instance of ServiceAccounts. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewServiceAccounts(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ServiceAccounts {
+	mock := &ServiceAccounts{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
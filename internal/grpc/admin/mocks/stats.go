@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Stats is an autogenerated mock type for the Stats type
+type Stats struct {
+	mock.Mock
+}
+
+type Stats_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Stats) EXPECT() *Stats_Expecter {
+	return &Stats_Expecter{mock: &_m.Mock}
+}
+
+// Last provides a mock function with given fields: ctx, period
+func (_m *Stats) Last(ctx context.Context, period time.Duration) (models.Stats, error) {
+	ret := _m.Called(ctx, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Last")
+	}
+
+	var r0 models.Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (models.Stats, error)); ok {
+		return rf(ctx, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) models.Stats); ok {
+		r0 = rf(ctx, period)
+	} else {
+		r0 = ret.Get(0).(models.Stats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stats_Last_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Last'
+type Stats_Last_Call struct {
+	*mock.Call
+}
+
+// Last is a helper method to define mock.On call
+//   - ctx context.Context
+//   - period time.Duration
+func (_e *Stats_Expecter) Last(ctx interface{}, period interface{}) *Stats_Last_Call {
+	return &Stats_Last_Call{Call: _e.mock.On("Last", ctx, period)}
+}
+
+func (_c *Stats_Last_Call) Run(run func(ctx context.Context, period time.Duration)) *Stats_Last_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Stats_Last_Call) Return(_a0 models.Stats, _a1 error) *Stats_Last_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Stats_Last_Call) RunAndReturn(run func(context.Context, time.Duration) (models.Stats, error)) *Stats_Last_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStats creates a new instance of Stats. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStats(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Stats {
+	mock := &Stats{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,192 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Suppressions is an autogenerated mock type for the Suppressions type
+type Suppressions struct {
+	mock.Mock
+}
+
+type Suppressions_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Suppressions) EXPECT() *Suppressions_Expecter {
+	return &Suppressions_Expecter{mock: &_m.Mock}
+}
+
+// Suppress provides a mock function with given fields: ctx, email, reason
+func (_m *Suppressions) Suppress(ctx context.Context, email string, reason string) error {
+	ret := _m.Called(ctx, email, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Suppress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, email, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Suppressions_Suppress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Suppress'
+type Suppressions_Suppress_Call struct {
+	*mock.Call
+}
+
+// Suppress is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - reason string
+func (_e *Suppressions_Expecter) Suppress(ctx interface{}, email interface{}, reason interface{}) *Suppressions_Suppress_Call {
+	return &Suppressions_Suppress_Call{Call: _e.mock.On("Suppress", ctx, email, reason)}
+}
+
+func (_c *Suppressions_Suppress_Call) Run(run func(ctx context.Context, email string, reason string)) *Suppressions_Suppress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Suppressions_Suppress_Call) Return(_a0 error) *Suppressions_Suppress_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Suppressions_Suppress_Call) RunAndReturn(run func(context.Context, string, string) error) *Suppressions_Suppress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Suppressions provides a mock function with given fields: ctx, limit, offset
+func (_m *Suppressions) Suppressions(ctx context.Context, limit int, offset int) ([]models.Suppression, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Suppressions")
+	}
+
+	var r0 []models.Suppression
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.Suppression, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.Suppression); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Suppression)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Suppressions_Suppressions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Suppressions'
+type Suppressions_Suppressions_Call struct {
+	*mock.Call
+}
+
+// Suppressions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - offset int
+func (_e *Suppressions_Expecter) Suppressions(ctx interface{}, limit interface{}, offset interface{}) *Suppressions_Suppressions_Call {
+	return &Suppressions_Suppressions_Call{Call: _e.mock.On("Suppressions", ctx, limit, offset)}
+}
+
+func (_c *Suppressions_Suppressions_Call) Run(run func(ctx context.Context, limit int, offset int)) *Suppressions_Suppressions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Suppressions_Suppressions_Call) Return(_a0 []models.Suppression, _a1 error) *Suppressions_Suppressions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Suppressions_Suppressions_Call) RunAndReturn(run func(context.Context, int, int) ([]models.Suppression, error)) *Suppressions_Suppressions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unsuppress provides a mock function with given fields: ctx, email
+func (_m *Suppressions) Unsuppress(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unsuppress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Suppressions_Unsuppress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unsuppress'
+type Suppressions_Unsuppress_Call struct {
+	*mock.Call
+}
+
+// Unsuppress is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *Suppressions_Expecter) Unsuppress(ctx interface{}, email interface{}) *Suppressions_Unsuppress_Call {
+	return &Suppressions_Unsuppress_Call{Call: _e.mock.On("Unsuppress", ctx, email)}
+}
+
+func (_c *Suppressions_Unsuppress_Call) Run(run func(ctx context.Context, email string)) *Suppressions_Unsuppress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Suppressions_Unsuppress_Call) Return(_a0 error) *Suppressions_Unsuppress_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Suppressions_Unsuppress_Call) RunAndReturn(run func(context.Context, string) error) *Suppressions_Unsuppress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSuppressions creates a new instance of Suppressions. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSuppressions(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Suppressions {
+	mock := &Suppressions{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Verifications is an autogenerated mock type for the Verifications type
+type Verifications struct {
+	mock.Mock
+}
+
+type Verifications_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Verifications) EXPECT() *Verifications_Expecter {
+	return &Verifications_Expecter{mock: &_m.Mock}
+}
+
+// Resend provides a mock function with given fields: ctx, userID, force
+func (_m *Verifications) Resend(ctx context.Context, userID int64, force bool) error {
+	ret := _m.Called(ctx, userID, force)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resend")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) error); ok {
+		r0 = rf(ctx, userID, force)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Verifications_Resend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Resend'
+type Verifications_Resend_Call struct {
+	*mock.Call
+}
+
+// Resend is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - force bool
+func (_e *Verifications_Expecter) Resend(ctx interface{}, userID interface{}, force interface{}) *Verifications_Resend_Call {
+	return &Verifications_Resend_Call{Call: _e.mock.On("Resend", ctx, userID, force)}
+}
+
+func (_c *Verifications_Resend_Call) Run(run func(ctx context.Context, userID int64, force bool)) *Verifications_Resend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *Verifications_Resend_Call) Return(_a0 error) *Verifications_Resend_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Verifications_Resend_Call) RunAndReturn(run func(context.Context, int64, bool) error) *Verifications_Resend_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewVerifications creates a new instance of Verifications. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVerifications(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Verifications {
+	mock := &Verifications{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
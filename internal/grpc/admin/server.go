@@ -0,0 +1,1112 @@
+package admingrpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/cursor"
+	"grpc-service-ref/internal/services/admin"
+	"grpc-service-ref/internal/services/maintenance"
+	"grpc-service-ref/internal/services/notification"
+	"grpc-service-ref/internal/services/reminder"
+	"grpc-service-ref/internal/services/serviceaccount"
+	"grpc-service-ref/internal/storage"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Users and apps management
+type Admin interface {
+	User(ctx context.Context, email string) (user models.User, isAdmin bool, err error)
+	Users(ctx context.Context, afterID int64, limit int) ([]models.User, error)
+	Sessions(ctx context.Context, filter models.SessionFilter, limit int) ([]models.Session, error)
+	RevokeSessions(ctx context.Context, userID int64, appID int) (revoked int, err error)
+	SetAdmin(ctx context.Context, userID int64, isAdmin bool) error
+	SetUserVerified(ctx context.Context, userID int64, verified bool, reason string) error
+	BanUser(ctx context.Context, userID int64, appID int, reason string, expiresAt time.Time) (models.AppBan, error)
+	UnbanUser(ctx context.Context, userID int64, appID int) error
+	Bans(ctx context.Context, userID int64) ([]models.AppBan, error)
+	App(ctx context.Context, appID int) (models.App, error)
+	Apps(ctx context.Context, afterID int, limit int) ([]models.App, error)
+	CreateApp(ctx context.Context, name string) (models.App, error)
+	RotateAppSecret(ctx context.Context, appID int) (models.App, error)
+	SetAppWebSettings(ctx context.Context, appID int, origins []string, csp string) (models.App, error)
+	SetAppTokenClaims(ctx context.Context, appID int, issuer string, audience string) (models.App, error)
+}
+
+// Email suppression list management
+type Suppressions interface {
+	Suppress(ctx context.Context, email string, reason string) error
+	Unsuppress(ctx context.Context, email string) error
+	Suppressions(ctx context.Context, limit int, offset int) ([]models.Suppression, error)
+}
+
+// Service accounts management
+type ServiceAccounts interface {
+	Create(ctx context.Context, appID int, orgID int64, name string, scopes []string) (models.ServiceAccount, error)
+	ServiceAccounts(ctx context.Context, appID int) ([]models.ServiceAccount, error)
+	Disable(ctx context.Context, id int64) error
+	CreateAPIKey(ctx context.Context, id int64) (key models.ServiceAccountKey, apiKey string, err error)
+	AddPublicKey(ctx context.Context, id int64, publicKey string) (models.ServiceAccountKey, error)
+	RevokeKey(ctx context.Context, keyID string) error
+}
+
+// Audit log queries
+type AuditLog interface {
+	Events(ctx context.Context, filter models.AuditFilter, limit int) ([]models.AuditEvent, error)
+}
+
+// Login and registration statistics
+type Stats interface {
+	Last(ctx context.Context, period time.Duration) (models.Stats, error)
+}
+
+// Verification emails resent by support staff
+type Verifications interface {
+	Resend(ctx context.Context, userID int64, force bool) error
+}
+
+// Emails rendered for operators without sending
+type EmailPreviews interface {
+	Preview(ctx context.Context, emailType string, sample map[string]string) (subject string, body string, err error)
+}
+
+// Maintenance windows of apps
+type Maintenance interface {
+	Enable(ctx context.Context, appID int, message string, endsAt time.Time) (models.Maintenance, error)
+	Disable(ctx context.Context, appID int) error
+	Windows(ctx context.Context) ([]models.Maintenance, error)
+}
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// Lists paged by page tokens, see cursor package.
+const (
+	listUsers    = "users"
+	listSessions = "sessions"
+	listApps     = "apps"
+	listAuditLog = "audit_log"
+)
+
+// sessionKey is sort key of sessions in page tokens.
+type sessionKey struct {
+	IssuedAt time.Time `json:"t"`
+	ID       string    `json:"id"`
+}
+
+// serverAPI implements AdminService. It's served on the admin port only, callers are authorized by
+// the server's interceptor, see grpcapp.Authorization, so RPCs don't check credentials themselves.
+type serverAPI struct {
+	ssov1.UnimplementedAdminServer
+	admin           Admin
+	suppressions    Suppressions
+	auditLog        AuditLog
+	serviceAccounts ServiceAccounts
+	stats           Stats
+	verifications   Verifications
+	maintenance     Maintenance
+	emailPreviews   EmailPreviews
+}
+
+// Deps are services of AdminService.
+type Deps struct {
+	Admin           Admin
+	Suppressions    Suppressions
+	AuditLog        AuditLog
+	ServiceAccounts ServiceAccounts
+	Stats           Stats
+	Verifications   Verifications
+	Maintenance     Maintenance
+	EmailPreviews   EmailPreviews
+}
+
+// Register registers sso.Admin on the server.
+func Register(gRPCServer *grpc.Server, deps Deps) {
+	ssov1.RegisterAdminServer(gRPCServer, &serverAPI{
+		admin:           deps.Admin,
+		suppressions:    deps.Suppressions,
+		auditLog:        deps.AuditLog,
+		serviceAccounts: deps.ServiceAccounts,
+		stats:           deps.Stats,
+		verifications:   deps.Verifications,
+		maintenance:     deps.Maintenance,
+		emailPreviews:   deps.EmailPreviews,
+	})
+}
+
+// GetUser returns user by email.
+func (s *serverAPI) GetUser(
+	ctx context.Context,
+	in *ssov1.GetUserRequest,
+) (*ssov1.GetUserResponse, error) {
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	user, isAdmin, err := s.admin.User(ctx, in.GetEmail())
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to get user")
+	}
+
+	return &ssov1.GetUserResponse{User: &ssov1.User{
+		Id:            user.ID,
+		Email:         user.Email,
+		Verified:      user.Verified,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified,
+		IsAdmin:       isAdmin,
+	}}, nil
+}
+
+// ListUsers returns page of users ordered by ID.
+func (s *serverAPI) ListUsers(
+	ctx context.Context,
+	in *ssov1.ListUsersRequest,
+) (*ssov1.ListUsersResponse, error) {
+	limit, err := pageLimit(in.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	var afterID int64
+	if in.GetPageToken() != "" {
+		if err := cursor.Decode(listUsers, in.GetPageToken(), &afterID); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	users, err := s.admin.Users(ctx, afterID, limit+1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+
+	users, next, err := cursor.Page(listUsers, users, limit, func(u models.User) any { return u.ID })
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+
+	res := make([]*ssov1.User, 0, len(users))
+	for _, user := range users {
+		res = append(res, &ssov1.User{
+			Id:            user.ID,
+			Email:         user.Email,
+			Verified:      user.Verified,
+			Phone:         user.Phone,
+			PhoneVerified: user.PhoneVerified,
+		})
+	}
+
+	return &ssov1.ListUsersResponse{Users: res, NextPageToken: next}, nil
+}
+
+// ListSessions returns page of sessions of the user, newest first.
+func (s *serverAPI) ListSessions(
+	ctx context.Context,
+	in *ssov1.ListSessionsRequest,
+) (*ssov1.ListSessionsResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	limit, err := pageLimit(in.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := models.SessionFilter{UserID: in.GetUserId()}
+	if in.GetActiveOnly() {
+		filter.ActiveAt = time.Now()
+	}
+	if in.GetPageToken() != "" {
+		var key sessionKey
+		if err := cursor.Decode(listSessions, in.GetPageToken(), &key); err != nil || key.ID == "" {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+
+		filter.Before = models.Session{ID: key.ID, IssuedAt: key.IssuedAt}
+	}
+
+	sessions, err := s.admin.Sessions(ctx, filter, limit+1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list sessions")
+	}
+
+	sessions, next, err := cursor.Page(listSessions, sessions, limit, func(s models.Session) any {
+		return sessionKey{IssuedAt: s.IssuedAt, ID: s.ID}
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list sessions")
+	}
+
+	res := make([]*ssov1.Session, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, &ssov1.Session{
+			Id:         session.ID,
+			UserId:     session.UserID,
+			AppId:      int32(session.AppID),
+			Elevated:   session.Elevated,
+			Ip:         session.IP,
+			Country:    session.Country,
+			City:       session.City,
+			IssuedAt:   timestamppb.New(session.IssuedAt),
+			ExpiresAt:  timestamppb.New(session.ExpiresAt),
+			RevokedAt:  optionalTimestamp(session.RevokedAt),
+			ConsumedAt: optionalTimestamp(session.ConsumedAt),
+		})
+	}
+
+	return &ssov1.ListSessionsResponse{Sessions: res, NextPageToken: next}, nil
+}
+
+// RevokeAllSessions revokes tokens of the user in all apps, e.g. after a password reset or account compromise.
+func (s *serverAPI) RevokeAllSessions(
+	ctx context.Context,
+	in *ssov1.RevokeAllSessionsRequest,
+) (*ssov1.RevokeAllSessionsResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	revoked, err := s.admin.RevokeSessions(ctx, in.GetUserId(), 0)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to revoke sessions")
+	}
+
+	return &ssov1.RevokeAllSessionsResponse{Revoked: int32(revoked)}, nil
+}
+
+// RevokeSessionsForApp revokes tokens of the user in the app, tokens of other apps stay valid.
+func (s *serverAPI) RevokeSessionsForApp(
+	ctx context.Context,
+	in *ssov1.RevokeSessionsForAppRequest,
+) (*ssov1.RevokeSessionsForAppResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	revoked, err := s.admin.RevokeSessions(ctx, in.GetUserId(), int(in.GetAppId()))
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, storage.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to revoke sessions")
+	}
+
+	return &ssov1.RevokeSessionsForAppResponse{Revoked: int32(revoked)}, nil
+}
+
+// SetAdmin grants or revokes admin role of the user.
+func (s *serverAPI) SetAdmin(
+	ctx context.Context,
+	in *ssov1.SetAdminRequest,
+) (*ssov1.SetAdminResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.admin.SetAdmin(ctx, in.GetUserId(), in.GetIsAdmin()); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to set admin role")
+	}
+
+	return &ssov1.SetAdminResponse{Success: true}, nil
+}
+
+// SetUserVerified marks email of the user verified or not, e.g. when verification email can't be delivered.
+// Reason is required, it's recorded in the audit log.
+func (s *serverAPI) SetUserVerified(
+	ctx context.Context,
+	in *ssov1.SetUserVerifiedRequest,
+) (*ssov1.SetUserVerifiedResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if strings.TrimSpace(in.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	if err := s.admin.SetUserVerified(ctx, in.GetUserId(), in.GetVerified(), strings.TrimSpace(in.GetReason())); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to set user verified")
+	}
+
+	return &ssov1.SetUserVerifiedResponse{}, nil
+}
+
+// BanUser bans the user from the app until expires_at, or until UnbanUser if it's not set. Login and other RPCs
+// issuing tokens for the app return PermissionDenied to the user, tokens the user has for the app are revoked.
+// Other apps remain usable. Reason is required, it's recorded in the audit log.
+func (s *serverAPI) BanUser(
+	ctx context.Context,
+	in *ssov1.BanUserRequest,
+) (*ssov1.BanUserResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if strings.TrimSpace(in.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	var expiresAt time.Time
+	if in.GetExpiresAt() != nil {
+		expiresAt = in.GetExpiresAt().AsTime()
+	}
+
+	ban, err := s.admin.BanUser(ctx, in.GetUserId(), int(in.GetAppId()), strings.TrimSpace(in.GetReason()), expiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidBanExpiry):
+			return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+		case errors.Is(err, storage.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, storage.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to ban user")
+	}
+
+	return &ssov1.BanUserResponse{Ban: appBanPb(ban)}, nil
+}
+
+// UnbanUser lifts ban of the user from the app.
+func (s *serverAPI) UnbanUser(
+	ctx context.Context,
+	in *ssov1.UnbanUserRequest,
+) (*ssov1.UnbanUserResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.UnbanUser(ctx, in.GetUserId(), int(in.GetAppId())); err != nil {
+		if errors.Is(err, storage.ErrAppBanNotFound) {
+			return nil, status.Error(codes.NotFound, "ban not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to unban user")
+	}
+
+	return &ssov1.UnbanUserResponse{}, nil
+}
+
+// ListUserBans returns bans of the user which are in effect, expired bans are not listed.
+func (s *serverAPI) ListUserBans(
+	ctx context.Context,
+	in *ssov1.ListUserBansRequest,
+) (*ssov1.ListUserBansResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	bans, err := s.admin.Bans(ctx, in.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list bans")
+	}
+
+	res := make([]*ssov1.AppBan, 0, len(bans))
+	for _, ban := range bans {
+		res = append(res, appBanPb(ban))
+	}
+
+	return &ssov1.ListUserBansResponse{Bans: res}, nil
+}
+
+func appBanPb(ban models.AppBan) *ssov1.AppBan {
+	return &ssov1.AppBan{
+		UserId:    ban.UserID,
+		AppId:     int32(ban.AppID),
+		Reason:    ban.Reason,
+		CreatedAt: timestamppb.New(ban.CreatedAt),
+		ExpiresAt: optionalTimestamp(ban.ExpiresAt),
+	}
+}
+
+// ResendVerification emails fresh registration verification code to the unverified user.
+// It's rate limited like CreateVerification of the user, force skips the limit.
+func (s *serverAPI) ResendVerification(
+	ctx context.Context,
+	in *ssov1.ResendVerificationRequest,
+) (*ssov1.ResendVerificationResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.verifications.Resend(ctx, in.GetUserId(), in.GetForce()); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, reminder.ErrAlreadyVerified):
+			return nil, status.Error(codes.FailedPrecondition, "user email is already verified")
+		case errors.Is(err, reminder.ErrSuppressed):
+			return nil, status.Error(codes.FailedPrecondition, "user email is suppressed, remove it from suppressions first")
+		case errors.Is(err, reminder.ErrCooldown):
+			return nil, status.Error(codes.ResourceExhausted, "verification was sent recently, use force to send anyway")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to resend verification")
+	}
+
+	return &ssov1.ResendVerificationResponse{}, nil
+}
+
+// GetApp returns app by ID, its secret is not returned.
+func (s *serverAPI) GetApp(
+	ctx context.Context,
+	in *ssov1.GetAppRequest,
+) (*ssov1.GetAppResponse, error) {
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	app, err := s.admin.App(ctx, int(in.GetAppId()))
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to get app")
+	}
+
+	return &ssov1.GetAppResponse{App: appPb(app)}, nil
+}
+
+// ListApps returns page of apps ordered by ID, their secrets are not returned.
+func (s *serverAPI) ListApps(
+	ctx context.Context,
+	in *ssov1.ListAppsRequest,
+) (*ssov1.ListAppsResponse, error) {
+	limit, err := pageLimit(in.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	var afterID int
+	if in.GetPageToken() != "" {
+		if err := cursor.Decode(listApps, in.GetPageToken(), &afterID); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	apps, err := s.admin.Apps(ctx, afterID, limit+1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list apps")
+	}
+
+	apps, next, err := cursor.Page(listApps, apps, limit, func(a models.App) any { return a.ID })
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list apps")
+	}
+
+	res := make([]*ssov1.App, 0, len(apps))
+	for _, app := range apps {
+		res = append(res, appPb(app))
+	}
+
+	return &ssov1.ListAppsResponse{Apps: res, NextPageToken: next}, nil
+}
+
+// CreateApp creates app and returns its generated secret, which can't be retrieved later.
+func (s *serverAPI) CreateApp(
+	ctx context.Context,
+	in *ssov1.CreateAppRequest,
+) (*ssov1.CreateAppResponse, error) {
+	if in.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	app, err := s.admin.CreateApp(ctx, in.GetName())
+	if err != nil {
+		if errors.Is(err, storage.ErrAppExists) {
+			return nil, status.Error(codes.AlreadyExists, "app already exists")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to create app")
+	}
+
+	return &ssov1.CreateAppResponse{AppId: int32(app.ID), Secret: app.Secret}, nil
+}
+
+// RotateAppSecret replaces secret of the app and returns the new one, tokens of the app issued before are invalidated.
+func (s *serverAPI) RotateAppSecret(
+	ctx context.Context,
+	in *ssov1.RotateAppSecretRequest,
+) (*ssov1.RotateAppSecretResponse, error) {
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	app, err := s.admin.RotateAppSecret(ctx, int(in.GetAppId()))
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to rotate app secret")
+	}
+
+	return &ssov1.RotateAppSecretResponse{Secret: app.Secret}, nil
+}
+
+// UpdateAppWebSettings replaces origins of the app's frontends the public HTTP server allows by CORS
+// and content security policy of responses to them. Changes apply within cache.apps_ttl.
+func (s *serverAPI) UpdateAppWebSettings(
+	ctx context.Context,
+	in *ssov1.UpdateAppWebSettingsRequest,
+) (*ssov1.UpdateAppWebSettingsResponse, error) {
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	app, err := s.admin.SetAppWebSettings(ctx, int(in.GetAppId()), in.GetAllowedOrigins(), in.GetContentSecurityPolicy())
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidOrigin):
+			return nil, status.Error(codes.InvalidArgument, "invalid origin, expected scheme and host, e.g. https://app.example.com")
+		case errors.Is(err, admin.ErrInvalidCSP):
+			return nil, status.Error(codes.InvalidArgument, "invalid content_security_policy")
+		case errors.Is(err, storage.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to update app web settings")
+	}
+
+	return &ssov1.UpdateAppWebSettingsResponse{App: appPb(app)}, nil
+}
+
+// UpdateAppTokenClaims replaces iss and aud claims of the app's tokens, empty ones fall back to the configured
+// claims. Changes apply within cache.apps_ttl.
+func (s *serverAPI) UpdateAppTokenClaims(
+	ctx context.Context,
+	in *ssov1.UpdateAppTokenClaimsRequest,
+) (*ssov1.UpdateAppTokenClaimsResponse, error) {
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	app, err := s.admin.SetAppTokenClaims(ctx, int(in.GetAppId()), in.GetIssuer(), in.GetAudience())
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidTokenClaim):
+			return nil, status.Error(codes.InvalidArgument, "issuer and audience must not contain whitespace")
+		case errors.Is(err, storage.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to update app token claims")
+	}
+
+	return &ssov1.UpdateAppTokenClaimsResponse{App: appPb(app)}, nil
+}
+
+// appPb converts app to its message, without the secret.
+func appPb(app models.App) *ssov1.App {
+	return &ssov1.App{
+		Id:                    int32(app.ID),
+		Name:                  app.Name,
+		RequireVerified:       app.RequireVerified,
+		AllowedOrigins:        app.AllowedOrigins,
+		ContentSecurityPolicy: app.ContentSecurityPolicy,
+		Issuer:                app.Issuer,
+		Audience:              app.Audience,
+	}
+}
+
+// SetMaintenance turns maintenance window of the app, or of all apps if app_id is 0, on or off.
+// During the window the public server rejects RPCs with Unavailable, AdminService is served as usual.
+// Replicas pick the change up within maintenance.windows_ttl.
+func (s *serverAPI) SetMaintenance(
+	ctx context.Context,
+	in *ssov1.SetMaintenanceRequest,
+) (*ssov1.SetMaintenanceResponse, error) {
+	appID := int(in.GetAppId())
+
+	if !in.GetEnabled() {
+		if err := s.maintenance.Disable(ctx, appID); err != nil {
+			if errors.Is(err, storage.ErrAppNotFound) {
+				return nil, status.Error(codes.NotFound, "app not found")
+			}
+
+			return nil, status.Error(codes.Internal, "failed to disable maintenance")
+		}
+
+		return &ssov1.SetMaintenanceResponse{}, nil
+	}
+
+	var endsAt time.Time
+	if in.GetEndsAt() != nil {
+		endsAt = in.GetEndsAt().AsTime()
+	}
+
+	window, err := s.maintenance.Enable(ctx, appID, in.GetMessage(), endsAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, maintenance.ErrInvalidMessage):
+			return nil, status.Error(codes.InvalidArgument, "message must be at most 500 characters without control characters")
+		case errors.Is(err, maintenance.ErrInvalidEndsAt):
+			return nil, status.Error(codes.InvalidArgument, "ends_at must be in the future")
+		case errors.Is(err, storage.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to enable maintenance")
+	}
+
+	return &ssov1.SetMaintenanceResponse{Maintenance: maintenancePb(window)}, nil
+}
+
+// ListMaintenance returns maintenance windows turned on by SetMaintenance which are not over.
+// Maintenance turned on by config is not listed.
+func (s *serverAPI) ListMaintenance(
+	ctx context.Context,
+	in *ssov1.ListMaintenanceRequest,
+) (*ssov1.ListMaintenanceResponse, error) {
+	windows, err := s.maintenance.Windows(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list maintenance")
+	}
+
+	res := make([]*ssov1.Maintenance, 0, len(windows))
+	for _, w := range windows {
+		res = append(res, maintenancePb(w))
+	}
+
+	return &ssov1.ListMaintenanceResponse{Maintenance: res}, nil
+}
+
+func maintenancePb(m models.Maintenance) *ssov1.Maintenance {
+	return &ssov1.Maintenance{
+		AppId:     int32(m.AppID),
+		Message:   m.Message,
+		StartedAt: timestamppb.New(m.StartedAt),
+		EndsAt:    optionalTimestamp(m.EndsAt),
+	}
+}
+
+// CreateServiceAccount creates service account of the app, optionally acting for an organization.
+func (s *serverAPI) CreateServiceAccount(
+	ctx context.Context,
+	in *ssov1.CreateServiceAccountRequest,
+) (*ssov1.CreateServiceAccountResponse, error) {
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	account, err := s.serviceAccounts.Create(ctx, int(in.GetAppId()), in.GetOrgId(), in.GetName(), in.GetScopes())
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceaccount.ErrInvalidName):
+			return nil, status.Error(codes.InvalidArgument, "invalid name")
+		case errors.Is(err, serviceaccount.ErrInvalidScope):
+			return nil, status.Error(codes.InvalidArgument, "invalid scope")
+		case errors.Is(err, storage.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		case errors.Is(err, storage.ErrOrganizationNotFound):
+			return nil, status.Error(codes.NotFound, "organization not found")
+		case errors.Is(err, storage.ErrServiceAccountExists):
+			return nil, status.Error(codes.AlreadyExists, "service account already exists")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to create service account")
+	}
+
+	return &ssov1.CreateServiceAccountResponse{ServiceAccount: serviceAccountPb(account)}, nil
+}
+
+// ListServiceAccounts returns service accounts of the app.
+func (s *serverAPI) ListServiceAccounts(
+	ctx context.Context,
+	in *ssov1.ListServiceAccountsRequest,
+) (*ssov1.ListServiceAccountsResponse, error) {
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	accounts, err := s.serviceAccounts.ServiceAccounts(ctx, int(in.GetAppId()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list service accounts")
+	}
+
+	res := make([]*ssov1.ServiceAccount, 0, len(accounts))
+	for _, account := range accounts {
+		res = append(res, serviceAccountPb(account))
+	}
+
+	return &ssov1.ListServiceAccountsResponse{ServiceAccounts: res}, nil
+}
+
+// DisableServiceAccount disables service account and revokes its tokens.
+func (s *serverAPI) DisableServiceAccount(
+	ctx context.Context,
+	in *ssov1.DisableServiceAccountRequest,
+) (*ssov1.DisableServiceAccountResponse, error) {
+	if in.GetServiceAccountId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+
+	if err := s.serviceAccounts.Disable(ctx, in.GetServiceAccountId()); err != nil {
+		if errors.Is(err, storage.ErrServiceAccountNotFound) {
+			return nil, status.Error(codes.NotFound, "service account not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to disable service account")
+	}
+
+	return &ssov1.DisableServiceAccountResponse{}, nil
+}
+
+// CreateServiceAccountKey creates API key of the service account, or adds its public key verifying
+// JWT assertions if public_key is set. API key is returned only once.
+func (s *serverAPI) CreateServiceAccountKey(
+	ctx context.Context,
+	in *ssov1.CreateServiceAccountKeyRequest,
+) (*ssov1.CreateServiceAccountKeyResponse, error) {
+	if in.GetServiceAccountId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+
+	var (
+		key    models.ServiceAccountKey
+		apiKey string
+		err    error
+	)
+	if in.GetPublicKey() != "" {
+		key, err = s.serviceAccounts.AddPublicKey(ctx, in.GetServiceAccountId(), in.GetPublicKey())
+	} else {
+		key, apiKey, err = s.serviceAccounts.CreateAPIKey(ctx, in.GetServiceAccountId())
+	}
+	if err != nil {
+		if errors.Is(err, serviceaccount.ErrInvalidPublicKey) {
+			return nil, status.Error(codes.InvalidArgument, "invalid public key")
+		}
+		if errors.Is(err, storage.ErrServiceAccountNotFound) {
+			return nil, status.Error(codes.NotFound, "service account not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to create service account key")
+	}
+
+	return &ssov1.CreateServiceAccountKeyResponse{KeyId: key.ID, ApiKey: apiKey}, nil
+}
+
+// RevokeServiceAccountKey revokes key of a service account, tokens issued for it expire as usual.
+func (s *serverAPI) RevokeServiceAccountKey(
+	ctx context.Context,
+	in *ssov1.RevokeServiceAccountKeyRequest,
+) (*ssov1.RevokeServiceAccountKeyResponse, error) {
+	if in.GetKeyId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key_id is required")
+	}
+
+	if err := s.serviceAccounts.RevokeKey(ctx, in.GetKeyId()); err != nil {
+		if errors.Is(err, storage.ErrServiceAccountKeyNotFound) {
+			return nil, status.Error(codes.NotFound, "service account key not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to revoke service account key")
+	}
+
+	return &ssov1.RevokeServiceAccountKeyResponse{}, nil
+}
+
+func serviceAccountPb(account models.ServiceAccount) *ssov1.ServiceAccount {
+	pb := &ssov1.ServiceAccount{
+		Id:        account.ID,
+		AppId:     int32(account.AppID),
+		OrgId:     account.OrgID,
+		Name:      account.Name,
+		Scopes:    account.Scopes,
+		CreatedAt: timestamppb.New(account.CreatedAt),
+	}
+	if !account.DisabledAt.IsZero() {
+		pb.DisabledAt = timestamppb.New(account.DisabledAt)
+	}
+
+	return pb
+}
+
+// AddSuppression stops emails to the address, e.g. on the owner's request.
+func (s *serverAPI) AddSuppression(
+	ctx context.Context,
+	in *ssov1.AddSuppressionRequest,
+) (*ssov1.AddSuppressionResponse, error) {
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.suppressions.Suppress(ctx, in.GetEmail(), in.GetReason()); err != nil {
+		return nil, status.Error(codes.Internal, "failed to add suppression")
+	}
+
+	return &ssov1.AddSuppressionResponse{Success: true}, nil
+}
+
+// RemoveSuppression allows emails to the address again.
+func (s *serverAPI) RemoveSuppression(
+	ctx context.Context,
+	in *ssov1.RemoveSuppressionRequest,
+) (*ssov1.RemoveSuppressionResponse, error) {
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.suppressions.Unsuppress(ctx, in.GetEmail()); err != nil {
+		if errors.Is(err, storage.ErrSuppressionNotFound) {
+			return nil, status.Error(codes.NotFound, "suppression not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to remove suppression")
+	}
+
+	return &ssov1.RemoveSuppressionResponse{Success: true}, nil
+}
+
+// ListSuppressions returns page of suppressed addresses.
+func (s *serverAPI) ListSuppressions(
+	ctx context.Context,
+	in *ssov1.ListSuppressionsRequest,
+) (*ssov1.ListSuppressionsResponse, error) {
+	if in.GetOffset() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+
+	limit, err := pageLimit(in.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	suppressions, err := s.suppressions.Suppressions(ctx, limit, int(in.GetOffset()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list suppressions")
+	}
+
+	res := make([]*ssov1.Suppression, 0, len(suppressions))
+	for _, sp := range suppressions {
+		res = append(res, &ssov1.Suppression{
+			Email:     sp.Email,
+			Source:    string(sp.Source),
+			Reason:    sp.Reason,
+			CreatedAt: timestamppb.New(sp.CreatedAt),
+		})
+	}
+
+	return &ssov1.ListSuppressionsResponse{Suppressions: res}, nil
+}
+
+// QueryAuditLog returns page of audit events, newest first.
+func (s *serverAPI) QueryAuditLog(
+	ctx context.Context,
+	in *ssov1.QueryAuditLogRequest,
+) (*ssov1.QueryAuditLogResponse, error) {
+	limit, err := pageLimit(in.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := models.AuditFilter{
+		Action:  models.AuditAction(in.GetAction()),
+		ActorID: in.GetActorId(),
+		Subject: in.GetSubject(),
+		AppID:   int(in.GetFilterAppId()),
+		Country: strings.ToUpper(in.GetCountry()),
+	}
+	if in.GetSince() != nil {
+		filter.Since = in.GetSince().AsTime()
+	}
+	if in.GetUntil() != nil {
+		filter.Until = in.GetUntil().AsTime()
+	}
+	if in.GetPageToken() != "" {
+		if err := cursor.Decode(listAuditLog, in.GetPageToken(), &filter.BeforeID); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	events, err := s.auditLog.Events(ctx, filter, limit+1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to query audit log")
+	}
+
+	events, next, err := cursor.Page(listAuditLog, events, limit, func(e models.AuditEvent) any { return e.ID })
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to query audit log")
+	}
+
+	res := make([]*ssov1.AuditEvent, 0, len(events))
+	for _, e := range events {
+		res = append(res, &ssov1.AuditEvent{
+			Id:        e.ID,
+			Action:    string(e.Action),
+			ActorId:   e.ActorID,
+			Subject:   e.Subject,
+			AppId:     int32(e.AppID),
+			Ip:        e.IP,
+			Country:   e.Country,
+			City:      e.City,
+			Payload:   e.Payload,
+			CreatedAt: timestamppb.New(e.CreatedAt),
+		})
+	}
+
+	return &ssov1.QueryAuditLogResponse{Events: res, NextPageToken: next}, nil
+}
+
+// Periods of GetStats.
+const (
+	statsDay  = 24 * time.Hour
+	statsWeek = 7 * statsDay
+)
+
+// PreviewEmail returns subject and body of the email of the type rendered with sample data, nothing is sent.
+// Emails are the same for all apps for now, app_id is only checked to exist, so previews of per-app emails
+// keep working once apps customize them.
+func (s *serverAPI) PreviewEmail(
+	ctx context.Context,
+	in *ssov1.PreviewEmailRequest,
+) (*ssov1.PreviewEmailResponse, error) {
+	if in.GetType() == "" {
+		return nil, status.Error(codes.InvalidArgument, "type is required")
+	}
+
+	if in.GetAppId() != 0 {
+		if _, err := s.admin.App(ctx, int(in.GetAppId())); err != nil {
+			if errors.Is(err, storage.ErrAppNotFound) {
+				return nil, status.Error(codes.NotFound, "app not found")
+			}
+
+			return nil, status.Error(codes.Internal, "failed to get app")
+		}
+	}
+
+	subject, body, err := s.emailPreviews.Preview(ctx, in.GetType(), in.GetSample())
+	if err != nil {
+		if errors.Is(err, notification.ErrUnknownEmail) {
+			return nil, status.Error(codes.InvalidArgument, "unknown email type")
+		}
+		if errors.Is(err, notification.ErrInvalidSample) {
+			return nil, status.Error(codes.InvalidArgument, "invalid sample data")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to render email")
+	}
+
+	return &ssov1.PreviewEmailResponse{Subject: subject, Body: body}, nil
+}
+
+// GetStats returns registrations, active users, logins, verification rate of registrations and funnels
+// of verifications over the last day and week. Stats are cached, so they lag behind by up to cache.stats_ttl.
+func (s *serverAPI) GetStats(
+	ctx context.Context,
+	in *ssov1.GetStatsRequest,
+) (*ssov1.GetStatsResponse, error) {
+	daily, err := s.stats.Last(ctx, statsDay)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get stats")
+	}
+
+	weekly, err := s.stats.Last(ctx, statsWeek)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get stats")
+	}
+
+	return &ssov1.GetStatsResponse{
+		Daily:  statsPb(daily),
+		Weekly: statsPb(weekly),
+	}, nil
+}
+
+func statsPb(stats models.Stats) *ssov1.Stats {
+	return &ssov1.Stats{
+		Since:                 timestamppb.New(stats.Since),
+		Until:                 timestamppb.New(stats.Until),
+		Registrations:         int64(stats.Registrations),
+		VerifiedRegistrations: int64(stats.VerifiedRegistrations),
+		VerificationRate:      stats.VerificationRate(),
+		ActiveUsers:           int64(stats.ActiveUsers),
+		LoginsSucceeded:       int64(stats.LoginsSucceeded),
+		LoginsFailed:          int64(stats.LoginsFailed),
+		Verifications:         verificationFunnelsPb(stats.Verifications),
+	}
+}
+
+func verificationFunnelsPb(funnels []models.VerificationFunnel) []*ssov1.VerificationFunnel {
+	res := make([]*ssov1.VerificationFunnel, 0, len(funnels))
+	for _, f := range funnels {
+		res = append(res, &ssov1.VerificationFunnel{
+			Type:           string(f.Type),
+			AppId:          int32(f.AppID),
+			Created:        int64(f.Created),
+			Delivered:      int64(f.Delivered),
+			Verified:       int64(f.Verified),
+			Expired:        int64(f.Expired),
+			Failed:         int64(f.Failed),
+			ConversionRate: f.ConversionRate(),
+		})
+	}
+
+	return res
+}
+
+// pageLimit returns size of the requested page, default if it's not set and at most maxPageSize.
+func pageLimit(limit int32) (int, error) {
+	if limit < 0 {
+		return 0, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	if limit == 0 {
+		return defaultPageSize, nil
+	}
+
+	return min(int(limit), maxPageSize), nil
+}
+
+// optionalTimestamp returns nil for zero time, which means the time is not set.
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
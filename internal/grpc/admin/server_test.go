@@ -0,0 +1,265 @@
+package admingrpc
+
+import (
+	"context"
+	"testing"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/grpc/admin/mocks"
+	"grpc-service-ref/internal/lib/cursor"
+	"grpc-service-ref/internal/services/reminder"
+	"grpc-service-ref/internal/storage"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRegister(t *testing.T) {
+	srv := grpc.NewServer()
+	Register(srv, Deps{
+		Admin:           mocks.NewAdmin(t),
+		Suppressions:    mocks.NewSuppressions(t),
+		AuditLog:        mocks.NewAuditLog(t),
+		ServiceAccounts: mocks.NewServiceAccounts(t),
+		Stats:           mocks.NewStats(t),
+		Verifications:   mocks.NewVerifications(t),
+		Maintenance:     mocks.NewMaintenance(t),
+		EmailPreviews:   mocks.NewEmailPreviews(t),
+	})
+
+	info := srv.GetServiceInfo()
+	assert.Len(t, info, 1, "only admin service is registered on the admin server")
+	assert.Contains(t, info, "auth.Admin")
+}
+
+func TestGetUser(t *testing.T) {
+	ctx := context.Background()
+	user := models.User{ID: 42, Email: "user@example.com", Verified: true}
+
+	tests := []struct {
+		name     string
+		email    string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "found", email: user.Email, wantCode: codes.OK},
+		{name: "empty email", email: "", wantCode: codes.InvalidArgument},
+		{name: "not found", email: user.Email, err: storage.ErrUserNotFound, wantCode: codes.NotFound},
+		{name: "storage failure", email: user.Email, err: assert.AnError, wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := mocks.NewAdmin(t)
+			if tt.email != "" {
+				admin.EXPECT().User(ctx, tt.email).Return(user, true, tt.err).Once()
+			}
+
+			s := &serverAPI{admin: admin}
+			resp, err := s.GetUser(ctx, &ssov1.GetUserRequest{Email: tt.email})
+			require.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode != codes.OK {
+				return
+			}
+
+			assert.Equal(t, user.ID, resp.GetUser().GetId())
+			assert.True(t, resp.GetUser().GetVerified())
+			assert.True(t, resp.GetUser().GetIsAdmin())
+		})
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	ctx := context.Background()
+	admin := mocks.NewAdmin(t)
+	s := &serverAPI{admin: admin}
+
+	admin.EXPECT().Users(ctx, int64(0), 3).Return([]models.User{{ID: 1}, {ID: 2}, {ID: 3}}, nil).Once()
+
+	resp, err := s.ListUsers(ctx, &ssov1.ListUsersRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, resp.GetUsers(), 2)
+	assert.Equal(t, int64(2), resp.GetUsers()[1].GetId())
+	require.NotEmpty(t, resp.GetNextPageToken())
+
+	var afterID int64
+	require.NoError(t, cursor.Decode(listUsers, resp.GetNextPageToken(), &afterID))
+	assert.Equal(t, int64(2), afterID)
+
+	admin.EXPECT().Users(ctx, int64(2), 3).Return([]models.User{{ID: 3}}, nil).Once()
+
+	resp, err = s.ListUsers(ctx, &ssov1.ListUsersRequest{Limit: 2, PageToken: resp.GetNextPageToken()})
+	require.NoError(t, err)
+	require.Len(t, resp.GetUsers(), 1)
+	assert.Empty(t, resp.GetNextPageToken(), "last page has no next page")
+}
+
+func TestListUsersInvalid(t *testing.T) {
+	ctx := context.Background()
+	s := &serverAPI{admin: mocks.NewAdmin(t)}
+
+	sessionsToken, err := cursor.Encode(listSessions, int64(1))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		in   *ssov1.ListUsersRequest
+	}{
+		{"negative limit", &ssov1.ListUsersRequest{Limit: -1}},
+		{"malformed page token", &ssov1.ListUsersRequest{PageToken: "not a token"}},
+		{"page token of another list", &ssov1.ListUsersRequest{PageToken: sessionsToken}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ListUsers(ctx, tt.in)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	tests := []struct {
+		limit    int32
+		want     int
+		wantCode codes.Code
+	}{
+		{limit: 0, want: defaultPageSize},
+		{limit: 10, want: 10},
+		{limit: maxPageSize + 1, want: maxPageSize},
+		{limit: -1, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		got, err := pageLimit(tt.limit)
+		assert.Equal(t, tt.wantCode, status.Code(err))
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		in       *ssov1.ResendVerificationRequest
+		err      error
+		wantCode codes.Code
+	}{
+		{"resent", &ssov1.ResendVerificationRequest{UserId: 42, Force: true}, nil, codes.OK},
+		{"user not found", &ssov1.ResendVerificationRequest{UserId: 42, Force: true}, storage.ErrUserNotFound, codes.NotFound},
+		{"already verified", &ssov1.ResendVerificationRequest{UserId: 42, Force: true}, reminder.ErrAlreadyVerified, codes.FailedPrecondition},
+		{"suppressed", &ssov1.ResendVerificationRequest{UserId: 42, Force: true}, reminder.ErrSuppressed, codes.FailedPrecondition},
+		{"cooldown", &ssov1.ResendVerificationRequest{UserId: 42}, reminder.ErrCooldown, codes.ResourceExhausted},
+		{"failure", &ssov1.ResendVerificationRequest{UserId: 42, Force: true}, assert.AnError, codes.Internal},
+		{"no user", &ssov1.ResendVerificationRequest{}, nil, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifications := mocks.NewVerifications(t)
+			if tt.wantCode != codes.InvalidArgument {
+				verifications.EXPECT().Resend(ctx, tt.in.GetUserId(), tt.in.GetForce()).Return(tt.err).Once()
+			}
+
+			s := &serverAPI{verifications: verifications}
+			_, err := s.ResendVerification(ctx, tt.in)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestSetUserVerified(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		in       *ssov1.SetUserVerifiedRequest
+		err      error
+		wantCode codes.Code
+	}{
+		{"reason is trimmed", &ssov1.SetUserVerifiedRequest{UserId: 42, Verified: true, Reason: " mail bounced "}, nil, codes.OK},
+		{"user not found", &ssov1.SetUserVerifiedRequest{UserId: 42, Verified: true, Reason: "mail bounced"}, storage.ErrUserNotFound, codes.NotFound},
+		{"no user", &ssov1.SetUserVerifiedRequest{Verified: true, Reason: "mail bounced"}, nil, codes.InvalidArgument},
+		{"blank reason", &ssov1.SetUserVerifiedRequest{UserId: 42, Verified: true, Reason: "  "}, nil, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := mocks.NewAdmin(t)
+			if tt.wantCode != codes.InvalidArgument {
+				admin.EXPECT().SetUserVerified(ctx, tt.in.GetUserId(), tt.in.GetVerified(), "mail bounced").Return(tt.err).Once()
+			}
+
+			s := &serverAPI{admin: admin}
+			_, err := s.SetUserVerified(ctx, tt.in)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		userID      int64
+		revoked     int
+		err         error
+		wantRevoked int32
+		wantCode    codes.Code
+	}{
+		{name: "revoked", userID: 42, revoked: 3, wantRevoked: 3, wantCode: codes.OK},
+		{name: "user not found", userID: 43, err: storage.ErrUserNotFound, wantCode: codes.NotFound},
+		{name: "no user", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := mocks.NewAdmin(t)
+			if tt.wantCode != codes.InvalidArgument {
+				admin.EXPECT().RevokeSessions(ctx, tt.userID, 0).Return(tt.revoked, tt.err).Once()
+			}
+
+			s := &serverAPI{admin: admin}
+			resp, err := s.RevokeAllSessions(ctx, &ssov1.RevokeAllSessionsRequest{UserId: tt.userID})
+			require.Equal(t, tt.wantCode, status.Code(err))
+			assert.EqualValues(t, tt.wantRevoked, resp.GetRevoked())
+		})
+	}
+}
+
+func TestRevokeSessionsForApp(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		in       *ssov1.RevokeSessionsForAppRequest
+		err      error
+		wantCode codes.Code
+	}{
+		{"revoked", &ssov1.RevokeSessionsForAppRequest{UserId: 42, AppId: 2}, nil, codes.OK},
+		{"user not found", &ssov1.RevokeSessionsForAppRequest{UserId: 42, AppId: 2}, storage.ErrUserNotFound, codes.NotFound},
+		{"app not found", &ssov1.RevokeSessionsForAppRequest{UserId: 42, AppId: 2}, storage.ErrAppNotFound, codes.NotFound},
+		{"failure", &ssov1.RevokeSessionsForAppRequest{UserId: 42, AppId: 2}, assert.AnError, codes.Internal},
+		{"no user", &ssov1.RevokeSessionsForAppRequest{AppId: 2}, nil, codes.InvalidArgument},
+		{"no app", &ssov1.RevokeSessionsForAppRequest{UserId: 42}, nil, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := mocks.NewAdmin(t)
+			if tt.wantCode != codes.InvalidArgument {
+				admin.EXPECT().RevokeSessions(ctx, tt.in.GetUserId(), int(tt.in.GetAppId())).Return(1, tt.err).Once()
+			}
+
+			s := &serverAPI{admin: admin}
+			_, err := s.RevokeSessionsForApp(ctx, tt.in)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
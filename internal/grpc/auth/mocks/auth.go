@@ -0,0 +1,756 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	jwt "grpc-service-ref/internal/lib/jwt"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+
+	servicesauth "grpc-service-ref/internal/services/auth"
+)
+
+// Auth is an autogenerated mock type for the Auth type
+type Auth struct {
+	mock.Mock
+}
+
+type Auth_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Auth) EXPECT() *Auth_Expecter {
+	return &Auth_Expecter{mock: &_m.Mock}
+}
+
+// AuthenticateApp provides a mock function with given fields: ctx, appID, secret
+func (_m *Auth) AuthenticateApp(ctx context.Context, appID int, secret string) error {
+	ret := _m.Called(ctx, appID, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthenticateApp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, appID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Auth_AuthenticateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthenticateApp'
+type Auth_AuthenticateApp_Call struct {
+	*mock.Call
+}
+
+// AuthenticateApp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - secret string
+func (_e *Auth_Expecter) AuthenticateApp(ctx interface{}, appID interface{}, secret interface{}) *Auth_AuthenticateApp_Call {
+	return &Auth_AuthenticateApp_Call{Call: _e.mock.On("AuthenticateApp", ctx, appID, secret)}
+}
+
+func (_c *Auth_AuthenticateApp_Call) Run(run func(ctx context.Context, appID int, secret string)) *Auth_AuthenticateApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_AuthenticateApp_Call) Return(_a0 error) *Auth_AuthenticateApp_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Auth_AuthenticateApp_Call) RunAndReturn(run func(context.Context, int, string) error) *Auth_AuthenticateApp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AuthenticateToken provides a mock function with given fields: ctx, token
+func (_m *Auth) AuthenticateToken(ctx context.Context, token string) (jwt.Claims, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthenticateToken")
+	}
+
+	var r0 jwt.Claims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (jwt.Claims, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) jwt.Claims); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(jwt.Claims)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_AuthenticateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthenticateToken'
+type Auth_AuthenticateToken_Call struct {
+	*mock.Call
+}
+
+// AuthenticateToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *Auth_Expecter) AuthenticateToken(ctx interface{}, token interface{}) *Auth_AuthenticateToken_Call {
+	return &Auth_AuthenticateToken_Call{Call: _e.mock.On("AuthenticateToken", ctx, token)}
+}
+
+func (_c *Auth_AuthenticateToken_Call) Run(run func(ctx context.Context, token string)) *Auth_AuthenticateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_AuthenticateToken_Call) Return(_a0 jwt.Claims, _a1 error) *Auth_AuthenticateToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Auth_AuthenticateToken_Call) RunAndReturn(run func(context.Context, string) (jwt.Claims, error)) *Auth_AuthenticateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AuthenticateUser provides a mock function with given fields: ctx, token
+func (_m *Auth) AuthenticateUser(ctx context.Context, token string) (jwt.Claims, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthenticateUser")
+	}
+
+	var r0 jwt.Claims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (jwt.Claims, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) jwt.Claims); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(jwt.Claims)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_AuthenticateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthenticateUser'
+type Auth_AuthenticateUser_Call struct {
+	*mock.Call
+}
+
+// AuthenticateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *Auth_Expecter) AuthenticateUser(ctx interface{}, token interface{}) *Auth_AuthenticateUser_Call {
+	return &Auth_AuthenticateUser_Call{Call: _e.mock.On("AuthenticateUser", ctx, token)}
+}
+
+func (_c *Auth_AuthenticateUser_Call) Run(run func(ctx context.Context, token string)) *Auth_AuthenticateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_AuthenticateUser_Call) Return(_a0 jwt.Claims, _a1 error) *Auth_AuthenticateUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Auth_AuthenticateUser_Call) RunAndReturn(run func(context.Context, string) (jwt.Claims, error)) *Auth_AuthenticateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Authorize provides a mock function with given fields: ctx, claims, appID, prompt
+func (_m *Auth) Authorize(ctx context.Context, claims jwt.Claims, appID int, prompt string) (string, error) {
+	ret := _m.Called(ctx, claims, appID, prompt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, jwt.Claims, int, string) (string, error)); ok {
+		return rf(ctx, claims, appID, prompt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, jwt.Claims, int, string) string); ok {
+		r0 = rf(ctx, claims, appID, prompt)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, jwt.Claims, int, string) error); ok {
+		r1 = rf(ctx, claims, appID, prompt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_Authorize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Authorize'
+type Auth_Authorize_Call struct {
+	*mock.Call
+}
+
+// Authorize is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims jwt.Claims
+//   - appID int
+//   - prompt string
+func (_e *Auth_Expecter) Authorize(ctx interface{}, claims interface{}, appID interface{}, prompt interface{}) *Auth_Authorize_Call {
+	return &Auth_Authorize_Call{Call: _e.mock.On("Authorize", ctx, claims, appID, prompt)}
+}
+
+func (_c *Auth_Authorize_Call) Run(run func(ctx context.Context, claims jwt.Claims, appID int, prompt string)) *Auth_Authorize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(jwt.Claims), args[2].(int), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_Authorize_Call) Return(token string, err error) *Auth_Authorize_Call {
+	_c.Call.Return(token, err)
+	return _c
+}
+
+func (_c *Auth_Authorize_Call) RunAndReturn(run func(context.Context, jwt.Claims, int, string) (string, error)) *Auth_Authorize_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ChangePassword provides a mock function with given fields: ctx, email, currentPassword, newPassword, sessionID
+func (_m *Auth) ChangePassword(ctx context.Context, email string, currentPassword string, newPassword string, sessionID string) error {
+	ret := _m.Called(ctx, email, currentPassword, newPassword, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, email, currentPassword, newPassword, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Auth_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type Auth_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - currentPassword string
+//   - newPassword string
+//   - sessionID string
+func (_e *Auth_Expecter) ChangePassword(ctx interface{}, email interface{}, currentPassword interface{}, newPassword interface{}, sessionID interface{}) *Auth_ChangePassword_Call {
+	return &Auth_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, email, currentPassword, newPassword, sessionID)}
+}
+
+func (_c *Auth_ChangePassword_Call) Run(run func(ctx context.Context, email string, currentPassword string, newPassword string, sessionID string)) *Auth_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_ChangePassword_Call) Return(_a0 error) *Auth_ChangePassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Auth_ChangePassword_Call) RunAndReturn(run func(context.Context, string, string, string, string) error) *Auth_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Elevate provides a mock function with given fields: ctx, claims, password
+func (_m *Auth) Elevate(ctx context.Context, claims jwt.Claims, password string) (string, error) {
+	ret := _m.Called(ctx, claims, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Elevate")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, jwt.Claims, string) (string, error)); ok {
+		return rf(ctx, claims, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, jwt.Claims, string) string); ok {
+		r0 = rf(ctx, claims, password)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, jwt.Claims, string) error); ok {
+		r1 = rf(ctx, claims, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_Elevate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Elevate'
+type Auth_Elevate_Call struct {
+	*mock.Call
+}
+
+// Elevate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims jwt.Claims
+//   - password string
+func (_e *Auth_Expecter) Elevate(ctx interface{}, claims interface{}, password interface{}) *Auth_Elevate_Call {
+	return &Auth_Elevate_Call{Call: _e.mock.On("Elevate", ctx, claims, password)}
+}
+
+func (_c *Auth_Elevate_Call) Run(run func(ctx context.Context, claims jwt.Claims, password string)) *Auth_Elevate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(jwt.Claims), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_Elevate_Call) Return(token string, err error) *Auth_Elevate_Call {
+	_c.Call.Return(token, err)
+	return _c
+}
+
+func (_c *Auth_Elevate_Call) RunAndReturn(run func(context.Context, jwt.Claims, string) (string, error)) *Auth_Elevate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsAdmin provides a mock function with given fields: ctx, userID
+func (_m *Auth) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAdmin")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (bool, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_IsAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsAdmin'
+type Auth_IsAdmin_Call struct {
+	*mock.Call
+}
+
+// IsAdmin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *Auth_Expecter) IsAdmin(ctx interface{}, userID interface{}) *Auth_IsAdmin_Call {
+	return &Auth_IsAdmin_Call{Call: _e.mock.On("IsAdmin", ctx, userID)}
+}
+
+func (_c *Auth_IsAdmin_Call) Run(run func(ctx context.Context, userID int64)) *Auth_IsAdmin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Auth_IsAdmin_Call) Return(_a0 bool, _a1 error) *Auth_IsAdmin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Auth_IsAdmin_Call) RunAndReturn(run func(context.Context, int64) (bool, error)) *Auth_IsAdmin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function with given fields: ctx, email, password, appID, device, signInCode
+func (_m *Auth) Login(ctx context.Context, email string, password string, appID int, device models.Device, signInCode string) (string, error) {
+	ret := _m.Called(ctx, email, password, appID, device, signInCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.Device, string) (string, error)); ok {
+		return rf(ctx, email, password, appID, device, signInCode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.Device, string) string); ok {
+		r0 = rf(ctx, email, password, appID, device, signInCode)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, models.Device, string) error); ok {
+		r1 = rf(ctx, email, password, appID, device, signInCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
+type Auth_Login_Call struct {
+	*mock.Call
+}
+
+// Login is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - password string
+//   - appID int
+//   - device models.Device
+//   - signInCode string
+func (_e *Auth_Expecter) Login(ctx interface{}, email interface{}, password interface{}, appID interface{}, device interface{}, signInCode interface{}) *Auth_Login_Call {
+	return &Auth_Login_Call{Call: _e.mock.On("Login", ctx, email, password, appID, device, signInCode)}
+}
+
+func (_c *Auth_Login_Call) Run(run func(ctx context.Context, email string, password string, appID int, device models.Device, signInCode string)) *Auth_Login_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int), args[4].(models.Device), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_Login_Call) Return(token string, err error) *Auth_Login_Call {
+	_c.Call.Return(token, err)
+	return _c
+}
+
+func (_c *Auth_Login_Call) RunAndReturn(run func(context.Context, string, string, int, models.Device, string) (string, error)) *Auth_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function with given fields: ctx, claims
+func (_m *Auth) Logout(ctx context.Context, claims jwt.Claims) error {
+	ret := _m.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, jwt.Claims) error); ok {
+		r0 = rf(ctx, claims)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Auth_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type Auth_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims jwt.Claims
+func (_e *Auth_Expecter) Logout(ctx interface{}, claims interface{}) *Auth_Logout_Call {
+	return &Auth_Logout_Call{Call: _e.mock.On("Logout", ctx, claims)}
+}
+
+func (_c *Auth_Logout_Call) Run(run func(ctx context.Context, claims jwt.Claims)) *Auth_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(jwt.Claims))
+	})
+	return _c
+}
+
+func (_c *Auth_Logout_Call) Return(_a0 error) *Auth_Logout_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Auth_Logout_Call) RunAndReturn(run func(context.Context, jwt.Claims) error) *Auth_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegisterNewUser provides a mock function with given fields: ctx, email, password
+func (_m *Auth) RegisterNewUser(ctx context.Context, email string, password string) (int64, error) {
+	ret := _m.Called(ctx, email, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterNewUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int64, error)); ok {
+		return rf(ctx, email, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, email, password)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_RegisterNewUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterNewUser'
+type Auth_RegisterNewUser_Call struct {
+	*mock.Call
+}
+
+// RegisterNewUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - password string
+func (_e *Auth_Expecter) RegisterNewUser(ctx interface{}, email interface{}, password interface{}) *Auth_RegisterNewUser_Call {
+	return &Auth_RegisterNewUser_Call{Call: _e.mock.On("RegisterNewUser", ctx, email, password)}
+}
+
+func (_c *Auth_RegisterNewUser_Call) Run(run func(ctx context.Context, email string, password string)) *Auth_RegisterNewUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_RegisterNewUser_Call) Return(userID int64, err error) *Auth_RegisterNewUser_Call {
+	_c.Call.Return(userID, err)
+	return _c
+}
+
+func (_c *Auth_RegisterNewUser_Call) RunAndReturn(run func(context.Context, string, string) (int64, error)) *Auth_RegisterNewUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateNotificationPreferences provides a mock function with given fields: ctx, userID, prefs
+func (_m *Auth) UpdateNotificationPreferences(ctx context.Context, userID int64, prefs models.NotificationPreferences) error {
+	ret := _m.Called(ctx, userID, prefs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNotificationPreferences")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.NotificationPreferences) error); ok {
+		r0 = rf(ctx, userID, prefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Auth_UpdateNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNotificationPreferences'
+type Auth_UpdateNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// UpdateNotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - prefs models.NotificationPreferences
+func (_e *Auth_Expecter) UpdateNotificationPreferences(ctx interface{}, userID interface{}, prefs interface{}) *Auth_UpdateNotificationPreferences_Call {
+	return &Auth_UpdateNotificationPreferences_Call{Call: _e.mock.On("UpdateNotificationPreferences", ctx, userID, prefs)}
+}
+
+func (_c *Auth_UpdateNotificationPreferences_Call) Run(run func(ctx context.Context, userID int64, prefs models.NotificationPreferences)) *Auth_UpdateNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(models.NotificationPreferences))
+	})
+	return _c
+}
+
+func (_c *Auth_UpdateNotificationPreferences_Call) Return(_a0 error) *Auth_UpdateNotificationPreferences_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Auth_UpdateNotificationPreferences_Call) RunAndReturn(run func(context.Context, int64, models.NotificationPreferences) error) *Auth_UpdateNotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUser provides a mock function with given fields: ctx, email, password
+func (_m *Auth) UpdateUser(ctx context.Context, email string, password string) (int64, error) {
+	ret := _m.Called(ctx, email, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int64, error)); ok {
+		return rf(ctx, email, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, email, password)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
+type Auth_UpdateUser_Call struct {
+	*mock.Call
+}
+
+// UpdateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - password string
+func (_e *Auth_Expecter) UpdateUser(ctx interface{}, email interface{}, password interface{}) *Auth_UpdateUser_Call {
+	return &Auth_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, email, password)}
+}
+
+func (_c *Auth_UpdateUser_Call) Run(run func(ctx context.Context, email string, password string)) *Auth_UpdateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Auth_UpdateUser_Call) Return(userID int64, err error) *Auth_UpdateUser_Call {
+	_c.Call.Return(userID, err)
+	return _c
+}
+
+func (_c *Auth_UpdateUser_Call) RunAndReturn(run func(context.Context, string, string) (int64, error)) *Auth_UpdateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WhoAmI provides a mock function with given fields: ctx, claims
+func (_m *Auth) WhoAmI(ctx context.Context, claims jwt.Claims) (servicesauth.Profile, error) {
+	ret := _m.Called(ctx, claims)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WhoAmI")
+	}
+
+	var r0 servicesauth.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, jwt.Claims) (servicesauth.Profile, error)); ok {
+		return rf(ctx, claims)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, jwt.Claims) servicesauth.Profile); ok {
+		r0 = rf(ctx, claims)
+	} else {
+		r0 = ret.Get(0).(servicesauth.Profile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, jwt.Claims) error); ok {
+		r1 = rf(ctx, claims)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Auth_WhoAmI_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WhoAmI'
+type Auth_WhoAmI_Call struct {
+	*mock.Call
+}
+
+// WhoAmI is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claims jwt.Claims
+func (_e *Auth_Expecter) WhoAmI(ctx interface{}, claims interface{}) *Auth_WhoAmI_Call {
+	return &Auth_WhoAmI_Call{Call: _e.mock.On("WhoAmI", ctx, claims)}
+}
+
+func (_c *Auth_WhoAmI_Call) Run(run func(ctx context.Context, claims jwt.Claims)) *Auth_WhoAmI_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(jwt.Claims))
+	})
+	return _c
+}
+
+func (_c *Auth_WhoAmI_Call) Return(_a0 servicesauth.Profile, _a1 error) *Auth_WhoAmI_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Auth_WhoAmI_Call) RunAndReturn(run func(context.Context, jwt.Claims) (servicesauth.Profile, error)) *Auth_WhoAmI_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuth creates a new instance of Auth. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuth(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Auth {
+	mock := &Auth{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
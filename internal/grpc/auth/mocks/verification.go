@@ -0,0 +1,299 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Verification is an autogenerated mock type for the Verification type
+type Verification struct {
+	mock.Mock
+}
+
+type Verification_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Verification) EXPECT() *Verification_Expecter {
+	return &Verification_Expecter{mock: &_m.Mock}
+}
+
+// DeleteVerification provides a mock function with given fields: ctx, email, vType
+func (_m *Verification) DeleteVerification(ctx context.Context, email string, vType models.VerificationType) error {
+	ret := _m.Called(ctx, email, vType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType) error); ok {
+		r0 = rf(ctx, email, vType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Verification_DeleteVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteVerification'
+type Verification_DeleteVerification_Call struct {
+	*mock.Call
+}
+
+// DeleteVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+func (_e *Verification_Expecter) DeleteVerification(ctx interface{}, email interface{}, vType interface{}) *Verification_DeleteVerification_Call {
+	return &Verification_DeleteVerification_Call{Call: _e.mock.On("DeleteVerification", ctx, email, vType)}
+}
+
+func (_c *Verification_DeleteVerification_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType)) *Verification_DeleteVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType))
+	})
+	return _c
+}
+
+func (_c *Verification_DeleteVerification_Call) Return(_a0 error) *Verification_DeleteVerification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Verification_DeleteVerification_Call) RunAndReturn(run func(context.Context, string, models.VerificationType) error) *Verification_DeleteVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delivered provides a mock function with given fields: ctx, vType
+func (_m *Verification) Delivered(ctx context.Context, vType models.VerificationType) {
+	_m.Called(ctx, vType)
+}
+
+// Verification_Delivered_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delivered'
+type Verification_Delivered_Call struct {
+	*mock.Call
+}
+
+// Delivered is a helper method to define mock.On call
+//   - ctx context.Context
+//   - vType models.VerificationType
+func (_e *Verification_Expecter) Delivered(ctx interface{}, vType interface{}) *Verification_Delivered_Call {
+	return &Verification_Delivered_Call{Call: _e.mock.On("Delivered", ctx, vType)}
+}
+
+func (_c *Verification_Delivered_Call) Run(run func(ctx context.Context, vType models.VerificationType)) *Verification_Delivered_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.VerificationType))
+	})
+	return _c
+}
+
+func (_c *Verification_Delivered_Call) Return() *Verification_Delivered_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Verification_Delivered_Call) RunAndReturn(run func(context.Context, models.VerificationType)) *Verification_Delivered_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Status provides a mock function with given fields: ctx, email, vType
+func (_m *Verification) Status(ctx context.Context, email string, vType models.VerificationType) (models.VerificationStatus, error) {
+	ret := _m.Called(ctx, email, vType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Status")
+	}
+
+	var r0 models.VerificationStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType) (models.VerificationStatus, error)); ok {
+		return rf(ctx, email, vType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType) models.VerificationStatus); ok {
+		r0 = rf(ctx, email, vType)
+	} else {
+		r0 = ret.Get(0).(models.VerificationStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.VerificationType) error); ok {
+		r1 = rf(ctx, email, vType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verification_Status_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Status'
+type Verification_Status_Call struct {
+	*mock.Call
+}
+
+// Status is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+func (_e *Verification_Expecter) Status(ctx interface{}, email interface{}, vType interface{}) *Verification_Status_Call {
+	return &Verification_Status_Call{Call: _e.mock.On("Status", ctx, email, vType)}
+}
+
+func (_c *Verification_Status_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType)) *Verification_Status_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType))
+	})
+	return _c
+}
+
+func (_c *Verification_Status_Call) Return(_a0 models.VerificationStatus, _a1 error) *Verification_Status_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Verification_Status_Call) RunAndReturn(run func(context.Context, string, models.VerificationType) (models.VerificationStatus, error)) *Verification_Status_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreVerification provides a mock function with given fields: ctx, email, vType, code, expiresAt
+func (_m *Verification) StoreVerification(ctx context.Context, email string, vType models.VerificationType, code string, expiresAt time.Time) (models.VerificationData, error) {
+	ret := _m.Called(ctx, email, vType, code, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreVerification")
+	}
+
+	var r0 models.VerificationData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, time.Time) (models.VerificationData, error)); ok {
+		return rf(ctx, email, vType, code, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, time.Time) models.VerificationData); ok {
+		r0 = rf(ctx, email, vType, code, expiresAt)
+	} else {
+		r0 = ret.Get(0).(models.VerificationData)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.VerificationType, string, time.Time) error); ok {
+		r1 = rf(ctx, email, vType, code, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verification_StoreVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreVerification'
+type Verification_StoreVerification_Call struct {
+	*mock.Call
+}
+
+// StoreVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+//   - code string
+//   - expiresAt time.Time
+func (_e *Verification_Expecter) StoreVerification(ctx interface{}, email interface{}, vType interface{}, code interface{}, expiresAt interface{}) *Verification_StoreVerification_Call {
+	return &Verification_StoreVerification_Call{Call: _e.mock.On("StoreVerification", ctx, email, vType, code, expiresAt)}
+}
+
+func (_c *Verification_StoreVerification_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType, code string, expiresAt time.Time)) *Verification_StoreVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType), args[3].(string), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Verification_StoreVerification_Call) Return(verificationData models.VerificationData, err error) *Verification_StoreVerification_Call {
+	_c.Call.Return(verificationData, err)
+	return _c
+}
+
+func (_c *Verification_StoreVerification_Call) RunAndReturn(run func(context.Context, string, models.VerificationType, string, time.Time) (models.VerificationData, error)) *Verification_StoreVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Verify provides a mock function with given fields: ctx, email, vType, code, deleteVerificationAfterAtempt
+func (_m *Verification) Verify(ctx context.Context, email string, vType models.VerificationType, code string, deleteVerificationAfterAtempt bool) (string, error) {
+	ret := _m.Called(ctx, email, vType, code, deleteVerificationAfterAtempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, bool) (string, error)); ok {
+		return rf(ctx, email, vType, code, deleteVerificationAfterAtempt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, bool) string); ok {
+		r0 = rf(ctx, email, vType, code, deleteVerificationAfterAtempt)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.VerificationType, string, bool) error); ok {
+		r1 = rf(ctx, email, vType, code, deleteVerificationAfterAtempt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verification_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type Verification_Verify_Call struct {
+	*mock.Call
+}
+
+// Verify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+//   - code string
+//   - deleteVerificationAfterAtempt bool
+func (_e *Verification_Expecter) Verify(ctx interface{}, email interface{}, vType interface{}, code interface{}, deleteVerificationAfterAtempt interface{}) *Verification_Verify_Call {
+	return &Verification_Verify_Call{Call: _e.mock.On("Verify", ctx, email, vType, code, deleteVerificationAfterAtempt)}
+}
+
+func (_c *Verification_Verify_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType, code string, deleteVerificationAfterAtempt bool)) *Verification_Verify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType), args[3].(string), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *Verification_Verify_Call) Return(result string, err error) *Verification_Verify_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *Verification_Verify_Call) RunAndReturn(run func(context.Context, string, models.VerificationType, string, bool) (string, error)) *Verification_Verify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewVerification creates a new instance of Verification. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVerification(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Verification {
+	mock := &Verification{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
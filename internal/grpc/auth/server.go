@@ -3,18 +3,39 @@ package authgrpc
 import (
 	"context"
 	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/geoip"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/passstrength"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/lib/ratelimit"
 	"grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/lib/version"
 	"grpc-service-ref/internal/services/auth"
+	"grpc-service-ref/internal/services/captcha"
+	"grpc-service-ref/internal/services/deletion"
+	"grpc-service-ref/internal/services/devicelogin"
+	"grpc-service-ref/internal/services/mail"
+	"grpc-service-ref/internal/services/organization"
+	"grpc-service-ref/internal/services/serviceaccount"
+	"grpc-service-ref/internal/services/signin"
 	verificationService "grpc-service-ref/internal/services/verification"
 	"grpc-service-ref/internal/storage"
 
 	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Authentication service
@@ -24,6 +45,8 @@ type Auth interface {
 		email string,
 		password string,
 		appID int,
+		device models.Device,
+		signInCode string,
 	) (token string, err error)
 	RegisterNewUser(
 		ctx context.Context,
@@ -36,17 +59,166 @@ type Auth interface {
 		email string,
 		password string,
 	) (userID int64, err error)
+	ChangePassword(
+		ctx context.Context,
+		email string,
+		currentPassword string,
+		newPassword string,
+		sessionID string,
+	) error
+	AuthenticateApp(ctx context.Context, appID int, secret string) error
+	AuthenticateUser(ctx context.Context, token string) (jwt.Claims, error)
+	AuthenticateToken(ctx context.Context, token string) (jwt.Claims, error)
+	Elevate(ctx context.Context, claims jwt.Claims, password string) (token string, err error)
+	Authorize(ctx context.Context, claims jwt.Claims, appID int, prompt string) (token string, err error)
+	Logout(ctx context.Context, claims jwt.Claims) error
+	WhoAmI(ctx context.Context, claims jwt.Claims) (auth.Profile, error)
+	UpdateNotificationPreferences(ctx context.Context, userID int64, prefs models.NotificationPreferences) error
 }
 
 type EmailSender interface {
 	SendEmail(
+		ctx context.Context,
 		subject string,
 		to []string,
 		content string,
 		cc []string,
 		bcc []string,
 		atachFiles []string,
+	) (messageID string, err error)
+}
+
+// Email delivery tracking
+type EmailTracker interface {
+	EmailStatus(ctx context.Context, messageID string) ([]models.Email, error)
+}
+
+// Security events of users
+type AuditLog interface {
+	SecurityEvents(ctx context.Context, userID int64, limit int) ([]models.AuditEvent, error)
+}
+
+// Webhook deliveries management
+type Webhooks interface {
+	Replay(ctx context.Context, appID int, deliveryID int64) error
+}
+
+// Organizations of users
+type Organizations interface {
+	Create(ctx context.Context, userID int64, email string, name string) (orgID int64, err error)
+	Invite(ctx context.Context, userID int64, email string, orgID int64, inviteeEmail string, role models.OrgRole) error
+	Accept(ctx context.Context, userID int64, email string, orgID int64, code string) (models.OrganizationMember, error)
+	Memberships(ctx context.Context, userID int64) ([]models.OrganizationMember, error)
+}
+
+// Tokens of service accounts
+type ServiceAccountTokens interface {
+	IssueTokenByAPIKey(ctx context.Context, apiKey string, scopes []string) (serviceaccount.Token, error)
+	IssueTokenByAssertion(ctx context.Context, assertion string, scopes []string) (serviceaccount.Token, error)
+}
+
+// Deletion of accounts requested by their users
+type AccountDeletion interface {
+	Request(ctx context.Context, userID int64, email string) error
+	Confirm(ctx context.Context, userID int64, email string, code string) (time.Time, error)
+}
+
+// Password strength policy
+type PasswordPolicy interface {
+	Check(password string, userInputs ...string) (passstrength.Result, error)
+}
+
+// Logins of devices approved from another signed in device
+type DeviceLogins interface {
+	Start(ctx context.Context, appID int, device models.Device) (devicelogin.Started, error)
+	Approve(ctx context.Context, userID int64, email string, pairingCode string) (models.DeviceLogin, error)
+	Poll(ctx context.Context, pollToken string) (string, error)
+}
+
+// Captcha verifier
+type Captcha interface {
+	Verify(ctx context.Context, token string, remoteIP string) error
+}
+
+// SMS sender
+type SMSSender interface {
+	SendSMS(ctx context.Context, to string, text string) error
+}
+
+// Phone verification service
+type PhoneVerification interface {
+	StorePhoneVerification(
+		ctx context.Context,
+		email string,
+		phone string,
+		code string,
+		expiresAt time.Time,
 	) error
+	VerifyPhone(
+		ctx context.Context,
+		email string,
+		phone string,
+		code string,
+	) (userID int64, err error)
+	Delivered(ctx context.Context)
+}
+
+// Verification code formats, may change while the server is running
+type VerificationCodes interface {
+	For(vType models.VerificationType) verification.CodeFormat
+}
+
+// Limiter throttles requests by key
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// Throttle escalates throttling of a key as its failures accumulate
+type Throttle interface {
+	Check(ctx context.Context, key string) (ratelimit.Decision, error)
+	Fail(ctx context.Context, key string)
+	Reset(ctx context.Context, key string)
+}
+
+// RateLimits are limiters of RPCs, nil limiter disables throttling.
+type RateLimits struct {
+	VerificationPerEmail Limiter
+	VerificationPerIP    Limiter
+	VerificationPerPhone Limiter
+	// LoginPerAccount and LoginPerIP throttle failed logins.
+	LoginPerAccount Throttle
+	LoginPerIP      Throttle
+	// LoginStuffing detects credential stuffing by failed login rates, keyed by client IP.
+	LoginStuffing Throttle
+}
+
+// AntiEnumeration makes Register, CreateVerification and RequestPasswordReset respond alike whether the email
+// is registered or not, so they can't be used to find out registered emails.
+type AntiEnumeration struct {
+	Enabled bool
+	// MinDuration pads responses, so their timing doesn't tell whether work for a registered email was done.
+	MinDuration time.Duration
+}
+
+// pad waits until MinDuration passes since start, or the call is canceled.
+// Real time is used rather than the clock, since it's latency clients observe.
+func (e AntiEnumeration) pad(ctx context.Context, start time.Time) {
+	if !e.Enabled {
+		return
+	}
+
+	wait := e.MinDuration - time.Since(start)
+	if wait <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
 }
 
 // Verification service
@@ -54,32 +226,157 @@ type Verification interface {
 	StoreVerification(
 		ctx context.Context,
 		email string,
+		vType models.VerificationType,
 		code string,
 		expiresAt time.Time,
 	) (verificationData models.VerificationData, err error)
 	Verify(
 		ctx context.Context,
 		email string,
+		vType models.VerificationType,
 		code string,
 		deleteVerificationAfterAtempt bool,
 	) (result string, err error)
 	DeleteVerification(
 		ctx context.Context,
 		email string,
+		vType models.VerificationType,
 	) error
+	Status(
+		ctx context.Context,
+		email string,
+		vType models.VerificationType,
+	) (models.VerificationStatus, error)
+	Delivered(ctx context.Context, vType models.VerificationType)
 }
 
 type serverAPI struct {
-	verificationCodeLen           int
-	verificationExpiresAfterHours int
+	verificationCodes VerificationCodes
 	ssov1.UnimplementedAuthServer
-	auth         Auth
-	verification Verification
-	emailService EmailSender
+	auth              Auth
+	verification      Verification
+	phoneVerification PhoneVerification
+	emailService      EmailSender
+	// smsSender is nil if SMS delivery is not configured or phone verification is disabled.
+	smsSender    SMSSender
+	emailTracker EmailTracker
+	// captcha is nil if captcha is disabled.
+	captcha         Captcha
+	rateLimits      RateLimits
+	antiEnumeration AntiEnumeration
+	auditLog        AuditLog
+	webhooks        Webhooks
+	organizations   Organizations
+	serviceAccounts ServiceAccountTokens
+	accountDeletion AccountDeletion
+	passwordPolicy  PasswordPolicy
+	deviceLogins    DeviceLogins
+	clock           clock.Clock
+	random          random.Randomizer
+}
+
+const (
+	defaultSecurityEvents = 20
+	maxSecurityEvents     = 100
+)
+
+// Machine-readable error reasons sent in google.rpc.ErrorInfo details.
+const (
+	errorDomain            = "sso"
+	reasonEmailNotVerified = "EMAIL_NOT_VERIFIED"
+	reasonCaptchaRequired  = "CAPTCHA_REQUIRED"
+	reasonCaptchaInvalid   = "CAPTCHA_INVALID"
+	reasonRateLimited      = "RATE_LIMITED"
+	reasonLoginLocked      = "LOGIN_LOCKED"
+
+	reasonSignInNotConfirmed = "SIGN_IN_NOT_CONFIRMED"
+	reasonSignInCodeInvalid  = "SIGN_IN_CODE_INVALID"
+	reasonSignInBlocked      = "SIGN_IN_BLOCKED"
+	reasonUserBanned         = "USER_BANNED"
+
+	reasonLoginRequired = "LOGIN_REQUIRED"
+
+	reasonPasswordTooWeak = "PASSWORD_TOO_WEAK"
+
+	reasonAuthorizationPending = "AUTHORIZATION_PENDING"
+)
+
+// captchaTokenHeader is a metadata key clients pass solved captcha token in.
+const captchaTokenHeader = "x-captcha-token"
+
+// Triggers of captcha, details of CAPTCHA_REQUIRED and CAPTCHA_INVALID errors tell clients which one applies.
+const (
+	// captchaTriggerAlways is captcha required by every call of the RPC.
+	captchaTriggerAlways = "always"
+	// captchaTriggerFailedLogins is captcha required by login throttles once the account or client IP
+	// failed captcha_after times, so clients show it only then.
+	captchaTriggerFailedLogins = "failed_logins"
+)
+
+// appSecretHeader is a metadata key trusted apps pass their secret in.
+const appSecretHeader = "x-app-secret"
+
+// Metadata keys describing the client device on login.
+const (
+	// signInCodeHeader is a metadata key clients pass code confirming sign-in from new device in.
+	signInCodeHeader = "x-sign-in-code"
+	// deviceIDHeader is an identifier the client keeps per device.
+	deviceIDHeader = "x-device-id"
+	// countryHeader is a country of the client IP, it's trusted only if the fronting proxy overwrites it.
+	countryHeader = "x-client-country"
+)
+
+// Deps are services the server works with.
+type Deps struct {
+	Auth              Auth
+	EmailService      EmailSender
+	EmailTracker      EmailTracker
+	Verification      Verification
+	PhoneVerification PhoneVerification
+	// SMSSender is nil if SMS delivery is not configured or phone verification is disabled.
+	SMSSender         SMSSender
+	VerificationCodes VerificationCodes
+	// Captcha is nil if captcha is disabled.
+	Captcha         Captcha
+	RateLimits      RateLimits
+	AntiEnumeration AntiEnumeration
+	AuditLog        AuditLog
+	Webhooks        Webhooks
+	Organizations   Organizations
+	ServiceAccounts ServiceAccountTokens
+	AccountDeletion AccountDeletion
+	PasswordPolicy  PasswordPolicy
+	DeviceLogins    DeviceLogins
+	Clock           clock.Clock
+	Random          random.Randomizer
 }
 
-func Register(gRPCServer *grpc.Server, auth Auth, emailService EmailSender, verification Verification, verificationCodeLen int, verificationExpiresAt int) {
-	ssov1.RegisterAuthServer(gRPCServer, &serverAPI{auth: auth, emailService: emailService, verification: verification, verificationCodeLen: verificationCodeLen, verificationExpiresAfterHours: verificationExpiresAt})
+// Register registers sso.Auth on the server and returns its server, which v2 adapts.
+func Register(gRPCServer *grpc.Server, deps Deps) ssov1.AuthServer {
+	server := &serverAPI{
+		auth:              deps.Auth,
+		emailService:      deps.EmailService,
+		emailTracker:      deps.EmailTracker,
+		verification:      deps.Verification,
+		phoneVerification: deps.PhoneVerification,
+		smsSender:         deps.SMSSender,
+		verificationCodes: deps.VerificationCodes,
+		captcha:           deps.Captcha,
+		rateLimits:        deps.RateLimits,
+		antiEnumeration:   deps.AntiEnumeration,
+		auditLog:          deps.AuditLog,
+		webhooks:          deps.Webhooks,
+		organizations:     deps.Organizations,
+		serviceAccounts:   deps.ServiceAccounts,
+		accountDeletion:   deps.AccountDeletion,
+		passwordPolicy:    deps.PasswordPolicy,
+		deviceLogins:      deps.DeviceLogins,
+		clock:             deps.Clock,
+		random:            deps.Random,
+	}
+	ssov1.RegisterAuthServer(gRPCServer, server)
+
+	return server
 }
 
 func (s *serverAPI) Login(
@@ -98,15 +395,45 @@ func (s *serverAPI) Login(
 		return nil, status.Error(codes.InvalidArgument, "app_id is required")
 	}
 
-	token, err := s.auth.Login(ctx, in.GetEmail(), in.GetPassword(), int(in.GetAppId()))
+	account, ip := strings.ToLower(in.GetEmail()), peer.IP(ctx)
+
+	if err := s.checkLoginThrottles(ctx, account, ip); err != nil {
+		return nil, err
+	}
+
+	token, err := s.auth.Login(ctx, in.GetEmail(), in.GetPassword(), int(in.GetAppId()), clientDevice(ctx), metadataValue(ctx, signInCodeHeader))
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
+			s.failLogin(ctx, account, ip)
+
 			return nil, status.Error(codes.InvalidArgument, "invalid email or password")
 		}
+		if errors.Is(err, auth.ErrUserNotVerified) {
+			return nil, notVerifiedError(in.GetEmail())
+		}
+		if errors.Is(err, signin.ErrConfirmationRequired) {
+			return nil, errorWithReason(codes.FailedPrecondition, "sign-in from new device must be confirmed by code sent to email", reasonSignInNotConfirmed, map[string]string{
+				"code_header": signInCodeHeader,
+			})
+		}
+		if errors.Is(err, signin.ErrInvalidCode) {
+			return nil, errorWithReason(codes.InvalidArgument, "sign-in code is invalid", reasonSignInCodeInvalid, nil)
+		}
+		if errors.Is(err, signin.ErrBlocked) {
+			return nil, errorWithReason(codes.PermissionDenied, "sign-in is blocked as suspicious", reasonSignInBlocked, nil)
+		}
+		if errors.Is(err, auth.ErrUserBanned) {
+			return nil, bannedError()
+		}
 
 		return nil, status.Error(codes.Internal, "failed to login")
 	}
 
+	// IP failures are not reset, otherwise any valid account would let the client brute-force others.
+	if s.rateLimits.LoginPerAccount != nil {
+		s.rateLimits.LoginPerAccount.Reset(ctx, account)
+	}
+
 	return &ssov1.LoginResponse{Token: token}, nil
 }
 
@@ -122,31 +449,59 @@ func (s *serverAPI) Register(
 		return nil, status.Error(codes.InvalidArgument, "password is required")
 	}
 
+	if err := s.verifyCaptcha(ctx, captchaTriggerAlways); err != nil {
+		return nil, err
+	}
+
+	defer s.antiEnumeration.pad(ctx, time.Now())
+
 	// save user
 	uid, err := s.auth.RegisterNewUser(ctx, in.GetEmail(), in.GetPassword())
 	if err != nil {
 		if errors.Is(err, storage.ErrUserExists) {
+			if s.antiEnumeration.Enabled {
+				// The owner is emailed instead, like a new user gets the verification code.
+				_, _ = s.emailService.SendEmail(ctx, "Your account already exists", []string{in.GetEmail()}, accountExistsEmail, []string{}, []string{}, []string{})
+
+				return &ssov1.RegisterResponse{}, nil
+			}
+
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
 		}
+		if errors.Is(err, passstrength.ErrTooWeak) {
+			return nil, s.weakPasswordError(in.GetPassword(), in.GetEmail())
+		}
+		if errors.Is(err, auth.ErrExternalUser) {
+			return nil, status.Error(codes.FailedPrecondition, "users with this email are managed by external user directory")
+		}
 
 		return nil, status.Error(codes.Internal, "failed to register user")
 	}
-	verificationCode := verification.GenerateRandomString(s.verificationCodeLen)
-	// save verification data
-	result, err := s.verification.StoreVerification(ctx, in.GetEmail(), verificationCode, time.Now().UTC().Add(time.Hour*time.Duration(s.verificationExpiresAfterHours)))
+	codeFormat := s.verificationCodes.For(models.VerificationTypeRegistration)
+	verificationCode, err := codeFormat.Generate(s.random)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to register user")
 	}
+	// save verification data
+	if _, err := s.verification.StoreVerification(ctx, in.GetEmail(), models.VerificationTypeRegistration, verificationCode, s.clock.Now().UTC().Add(codeFormat.TTL)); err != nil {
+		return nil, status.Error(codes.Internal, "failed to register user")
+	}
 
 	// send verification email
-	if err := s.emailService.SendEmail("Verify your new account", []string{in.GetEmail()}, verificationCode, []string{}, []string{}, []string{}); err != nil {
-		return nil, status.Error(codes.Internal, "failed to send email")
+	if err := s.sendVerificationCode(ctx, in.GetEmail(), models.VerificationTypeRegistration, verificationCode); err != nil {
+		return nil, err
+	}
+
+	if s.antiEnumeration.Enabled {
+		// ID of the user would tell new accounts from existing ones.
+		uid = 0
 	}
-	_ = result
 
 	return &ssov1.RegisterResponse{UserId: uid}, nil
 }
 
+// IsAdmin tells whether the user has admin role. Users may only check themselves, admins and service accounts
+// of relying services may check anyone.
 func (s *serverAPI) IsAdmin(
 	ctx context.Context,
 	in *ssov1.IsAdminRequest,
@@ -155,6 +510,24 @@ func (s *serverAPI) IsAdmin(
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
 
+	claims, ok := jwt.FromContext(ctx)
+	if !ok || claims.ServiceAccountID == 0 {
+		var err error
+		if claims, err = s.authenticateUserClaims(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if claims.ServiceAccountID == 0 && claims.UserID != in.GetUserId() {
+		callerIsAdmin, err := s.auth.IsAdmin(ctx, claims.UserID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to check admin status")
+		}
+		if !callerIsAdmin {
+			return nil, status.Error(codes.PermissionDenied, "only admins may check other users")
+		}
+	}
+
 	isAdmin, err := s.auth.IsAdmin(ctx, in.GetUserId())
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
@@ -175,26 +548,100 @@ func (s *serverAPI) CreateVerification(
 		return nil, status.Error(codes.InvalidArgument, "email is required")
 	}
 
-	verificationCode := verification.GenerateRandomString(s.verificationCodeLen)
-	// save verification data
-	result, err := s.verification.StoreVerification(ctx, in.GetEmail(), verificationCode, time.Now().UTC().Add(time.Hour*time.Duration(s.verificationExpiresAfterHours)))
+	vType, ok := verificationType(in.GetType())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown verification type")
+	}
+
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerIP, peer.IP(ctx)); err != nil {
+		return nil, err
+	}
+
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerEmail, strings.ToLower(in.GetEmail())); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyCaptcha(ctx, captchaTriggerAlways); err != nil {
+		return nil, err
+	}
+
+	defer s.antiEnumeration.pad(ctx, time.Now())
+
+	codeFormat := s.verificationCodes.For(vType)
+	verificationCode, err := codeFormat.Generate(s.random)
 	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create verification")
+	}
+	// save verification data
+	if _, err := s.verification.StoreVerification(ctx, in.GetEmail(), vType, verificationCode, s.clock.Now().UTC().Add(codeFormat.TTL)); err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
+			if s.antiEnumeration.Enabled {
+				return &ssov1.CreateVerificationResponse{Success: true}, nil
+			}
+
 			return nil, status.Error(codes.NotFound, "unable to create verification with email provided")
 		}
 
 		return nil, status.Error(codes.Internal, "failed to create verification")
 	}
-	_ = result
 
 	// send code to email
-	if err := s.emailService.SendEmail("Verify your new account", []string{in.GetEmail()}, verificationCode, []string{}, []string{}, []string{}); err != nil {
-		return nil, status.Error(codes.Internal, "failed to send email")
+	if err := s.sendVerificationCode(ctx, in.GetEmail(), vType, verificationCode); err != nil {
+		return nil, err
 	}
 
 	return &ssov1.CreateVerificationResponse{Success: true}, nil
 }
 
+// RequestPasswordReset emails password reset code to the user, which ResetPassword takes with the new password.
+// It succeeds whether the email is registered or not, so it can't be used to find out registered emails,
+// and it's throttled and protected by captcha like CreateVerification.
+func (s *serverAPI) RequestPasswordReset(
+	ctx context.Context,
+	in *ssov1.RequestPasswordResetRequest,
+) (*ssov1.RequestPasswordResetResponse, error) {
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerIP, peer.IP(ctx)); err != nil {
+		return nil, err
+	}
+
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerEmail, strings.ToLower(in.GetEmail())); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyCaptcha(ctx, captchaTriggerAlways); err != nil {
+		return nil, err
+	}
+
+	defer s.antiEnumeration.pad(ctx, time.Now())
+
+	codeFormat := s.verificationCodes.For(models.VerificationTypePasswordReset)
+	code, err := codeFormat.Generate(s.random)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to request password reset")
+	}
+
+	_, err = s.verification.StoreVerification(ctx, in.GetEmail(), models.VerificationTypePasswordReset, code, s.clock.Now().UTC().Add(codeFormat.TTL))
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return &ssov1.RequestPasswordResetResponse{}, nil
+		}
+
+		return nil, status.Error(codes.Internal, "failed to request password reset")
+	}
+
+	// Failures, e.g. suppressed address, are logged by the email service. Reporting them would tell
+	// the email is registered.
+	if _, err := s.emailService.SendEmail(ctx, verificationEmailSubject(models.VerificationTypePasswordReset), []string{in.GetEmail()}, code, []string{}, []string{}, []string{}); err == nil {
+		s.verification.Delivered(ctx, models.VerificationTypePasswordReset)
+	}
+
+	return &ssov1.RequestPasswordResetResponse{}, nil
+}
+
 func (s *serverAPI) VerifyMail(
 	ctx context.Context,
 	in *ssov1.VerifyMailRequest,
@@ -207,7 +654,7 @@ func (s *serverAPI) VerifyMail(
 		return nil, status.Error(codes.InvalidArgument, "code is required")
 	}
 
-	result, err := s.verification.Verify(ctx, in.GetEmail(), in.GetCode(), true)
+	result, err := s.verification.Verify(ctx, in.GetEmail(), models.VerificationTypeRegistration, in.GetCode(), true)
 	if success, err := validateVerificationResult(err); !success {
 		return nil, err
 	}
@@ -215,6 +662,85 @@ func (s *serverAPI) VerifyMail(
 	return &ssov1.VerifyMailResponse{Result: result}, nil
 }
 
+func (s *serverAPI) CreatePhoneVerification(
+	ctx context.Context,
+	in *ssov1.CreatePhoneVerificationRequest,
+) (*ssov1.CreatePhoneVerificationResponse, error) {
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if !verificationService.ValidPhone(in.GetPhone()) {
+		return nil, status.Error(codes.InvalidArgument, "phone must be in E.164 format")
+	}
+
+	if s.smsSender == nil {
+		return nil, status.Error(codes.Unavailable, "phone verification is not available")
+	}
+
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerIP, peer.IP(ctx)); err != nil {
+		return nil, err
+	}
+
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerPhone, in.GetPhone()); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyCaptcha(ctx, captchaTriggerAlways); err != nil {
+		return nil, err
+	}
+
+	codeFormat := s.verificationCodes.For(models.VerificationTypePhone)
+	verificationCode, err := codeFormat.Generate(s.random)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create phone verification")
+	}
+
+	err = s.phoneVerification.StorePhoneVerification(ctx, in.GetEmail(), in.GetPhone(), verificationCode, s.clock.Now().UTC().Add(codeFormat.TTL))
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "unable to create verification with email provided")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to create phone verification")
+	}
+
+	if err := s.smsSender.SendSMS(ctx, in.GetPhone(), "Your verification code: "+verificationCode); err != nil {
+		return nil, status.Error(codes.Internal, "failed to send sms")
+	}
+	s.phoneVerification.Delivered(ctx)
+
+	return &ssov1.CreatePhoneVerificationResponse{Success: true}, nil
+}
+
+func (s *serverAPI) VerifyPhone(
+	ctx context.Context,
+	in *ssov1.VerifyPhoneRequest,
+) (*ssov1.VerifyPhoneResponse, error) {
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if in.GetPhone() == "" {
+		return nil, status.Error(codes.InvalidArgument, "phone is required")
+	}
+
+	if in.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	if s.smsSender == nil {
+		return nil, status.Error(codes.Unavailable, "phone verification is not available")
+	}
+
+	userID, err := s.phoneVerification.VerifyPhone(ctx, in.GetEmail(), in.GetPhone(), in.GetCode())
+	if success, err := validateVerificationResult(err); !success {
+		return nil, err
+	}
+
+	return &ssov1.VerifyPhoneResponse{UserId: userID}, nil
+}
+
 func (s *serverAPI) ResetPassword(
 	ctx context.Context,
 	in *ssov1.ResetPasswordRequest,
@@ -231,7 +757,7 @@ func (s *serverAPI) ResetPassword(
 		return nil, status.Error(codes.InvalidArgument, "password is required")
 	}
 
-	verificationResult, err := s.verification.Verify(ctx, in.GetEmail(), in.GetCode(), false)
+	verificationResult, err := s.verification.Verify(ctx, in.GetEmail(), models.VerificationTypePasswordReset, in.GetCode(), false)
 
 	if success, err := validateVerificationResult(err); !success {
 		return nil, err
@@ -242,6 +768,12 @@ func (s *serverAPI) ResetPassword(
 		if errors.Is(err, auth.ErrPassAreEqual) {
 			return nil, status.Error(codes.InvalidArgument, "passwords should differ")
 		}
+		if errors.Is(err, passstrength.ErrTooWeak) {
+			return nil, s.weakPasswordError(in.GetNewPassword(), in.GetEmail())
+		}
+		if errors.Is(err, auth.ErrExternalUser) {
+			return nil, status.Error(codes.FailedPrecondition, "password is managed by external user directory")
+		}
 
 		return nil, status.Error(codes.Internal, "failed to update user password")
 	}
@@ -249,7 +781,7 @@ func (s *serverAPI) ResetPassword(
 	_ = uid
 	_ = verificationResult
 
-	err = s.verification.DeleteVerification(ctx, in.GetEmail())
+	err = s.verification.DeleteVerification(ctx, in.GetEmail(), models.VerificationTypePasswordReset)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to delete verification")
 	}
@@ -257,20 +789,1091 @@ func (s *serverAPI) ResetPassword(
 	return &ssov1.ResetPasswordResponse{Success: true}, nil
 }
 
-func validateVerificationResult(err error) (bool, error) {
+// ChangePassword changes password of the signed in user, the call is authorized by the user's access token.
+func (s *serverAPI) ChangePassword(
+	ctx context.Context,
+	in *ssov1.ChangePasswordRequest,
+) (*ssov1.ChangePasswordResponse, error) {
+	if in.GetCurrentPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "current_password is required")
+	}
+
+	if in.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	claims, err := s.authenticateUserClaims(ctx)
 	if err != nil {
-		if errors.Is(err, storage.ErrVerificationNotFound) {
-			return false, status.Error(codes.NotFound, "verification not found")
-		}
-		if errors.Is(err, storage.ErrVerificationExpired) {
-			return false, status.Error(codes.Internal, "verification expired")
+		return nil, err
+	}
+
+	if err := s.auth.ChangePassword(ctx, claims.Email, in.GetCurrentPassword(), in.GetNewPassword(), claims.ID); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidCredentials):
+			return nil, status.Error(codes.InvalidArgument, "invalid current password")
+		case errors.Is(err, auth.ErrPassAreEqual):
+			return nil, status.Error(codes.InvalidArgument, "passwords should differ")
+		case errors.Is(err, passstrength.ErrTooWeak):
+			return nil, s.weakPasswordError(in.GetNewPassword(), claims.Email)
+		case errors.Is(err, auth.ErrExternalUser):
+			return nil, status.Error(codes.FailedPrecondition, "password is managed by external user directory")
+		case errors.Is(err, storage.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		default:
+			return nil, status.Error(codes.Internal, "failed to change password")
 		}
-		if errors.Is(err, verificationService.CodesDiffer) {
-			return false, status.Error(codes.PermissionDenied, "codes differ")
+	}
+
+	return &ssov1.ChangePasswordResponse{Success: true}, nil
+}
+
+// Elevate issues one-time token for a sensitive action, the signed in user confirms it by password.
+// Elevated token is accepted only once, its replay is rejected.
+func (s *serverAPI) Elevate(
+	ctx context.Context,
+	in *ssov1.ElevateRequest,
+) (*ssov1.ElevateResponse, error) {
+	if in.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Password is checked as on login, so it's throttled the same way.
+	account, ip := strings.ToLower(claims.Email), peer.IP(ctx)
+
+	if err := s.checkLoginThrottles(ctx, account, ip); err != nil {
+		return nil, err
+	}
+
+	token, err := s.auth.Elevate(ctx, claims, in.GetPassword())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			s.failLogin(ctx, account, ip)
+
+			return nil, status.Error(codes.InvalidArgument, "invalid password")
 		}
 
-		return false, status.Error(codes.Internal, "failed to verify email")
+		return nil, status.Error(codes.Internal, "failed to elevate token")
 	}
 
-	return true, nil
+	return &ssov1.ElevateResponse{Token: token}, nil
+}
+
+// Authorize issues token for another app to the user signed in by the access token the call is authorized by,
+// so the user doesn't enter credentials again while SSO session lasts.
+func (s *serverAPI) Authorize(
+	ctx context.Context,
+	in *ssov1.AuthorizeRequest,
+) (*ssov1.AuthorizeResponse, error) {
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.auth.Authorize(ctx, claims, int(in.GetAppId()), in.GetPrompt())
+	if err != nil {
+		if errors.Is(err, auth.ErrLoginRequired) {
+			return nil, errorWithReason(codes.FailedPrecondition, "login is required", reasonLoginRequired, nil)
+		}
+		if errors.Is(err, auth.ErrUserNotVerified) {
+			return nil, notVerifiedError(claims.Email)
+		}
+		if errors.Is(err, auth.ErrUserBanned) {
+			return nil, bannedError()
+		}
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to authorize")
+	}
+
+	return &ssov1.AuthorizeResponse{Token: token}, nil
+}
+
+// Logout revokes the access token the call is authorized by.
+func (s *serverAPI) Logout(
+	ctx context.Context,
+	in *ssov1.LogoutRequest,
+) (*ssov1.LogoutResponse, error) {
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.Logout(ctx, claims); err != nil {
+		return nil, status.Error(codes.Internal, "failed to logout")
+	}
+
+	return &ssov1.LogoutResponse{Success: true}, nil
+}
+
+// WhoAmI returns profile of the user of the bearer token: the account, admin role, organizations
+// and the session of the token, so frontends don't decode tokens themselves.
+func (s *serverAPI) WhoAmI(
+	ctx context.Context,
+	in *ssov1.WhoAmIRequest,
+) (*ssov1.WhoAmIResponse, error) {
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.auth.WhoAmI(ctx, claims)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid access token")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to get profile")
+	}
+
+	orgs := make([]*ssov1.Organization, 0, len(profile.Orgs))
+	for _, member := range profile.Orgs {
+		orgs = append(orgs, organizationPb(member))
+	}
+
+	session := profile.Session
+
+	return &ssov1.WhoAmIResponse{
+		UserId:        profile.User.ID,
+		Email:         profile.User.Email,
+		Verified:      profile.User.Verified,
+		Phone:         profile.User.Phone,
+		PhoneVerified: profile.User.PhoneVerified,
+		IsAdmin:       profile.IsAdmin,
+		Organizations: orgs,
+		Preferences:   notificationPreferencesPb(profile.Preferences),
+		Session: &ssov1.SessionInfo{
+			Id:           session.ID,
+			SsoSessionId: session.SSOSessionID,
+			AppId:        int32(session.AppID),
+			Elevated:     session.Elevated,
+			Ip:           session.IP,
+			Country:      session.Country,
+			City:         session.City,
+			IssuedAt:     timestamppb.New(session.IssuedAt),
+			ExpiresAt:    timestamppb.New(session.ExpiresAt),
+		},
+	}, nil
+}
+
+// UpdateNotificationPreferences replaces categories of non-essential emails the user of the bearer token
+// receives. Essential emails, e.g. verification codes, are sent regardless.
+func (s *serverAPI) UpdateNotificationPreferences(
+	ctx context.Context,
+	in *ssov1.UpdateNotificationPreferencesRequest,
+) (*ssov1.UpdateNotificationPreferencesResponse, error) {
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := models.NotificationPreferences{
+		Reminders: in.GetReminders(),
+		Security:  in.GetSecurity(),
+		Product:   in.GetProduct(),
+	}
+
+	if err := s.auth.UpdateNotificationPreferences(ctx, claims.UserID, prefs); err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid access token")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to update notification preferences")
+	}
+
+	return &ssov1.UpdateNotificationPreferencesResponse{Preferences: notificationPreferencesPb(prefs)}, nil
+}
+
+// IntrospectToken tells the app whether its user's token is active, i.e. valid, not revoked and,
+// for elevated token, not used yet. Introspection consumes elevated token, so the app calls it once per action.
+// Tokens of the app's service accounts are introspected too, they have service_account_id instead of user_id.
+func (s *serverAPI) IntrospectToken(
+	ctx context.Context,
+	in *ssov1.IntrospectTokenRequest,
+) (*ssov1.IntrospectTokenResponse, error) {
+	if in.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.authenticateApp(ctx, int(in.GetAppId())); err != nil {
+		return nil, err
+	}
+
+	claims, err := s.auth.AuthenticateToken(ctx, in.GetToken())
+	if err != nil {
+		if isRejectedToken(err) {
+			return &ssov1.IntrospectTokenResponse{Active: false}, nil
+		}
+
+		return nil, status.Error(codes.Internal, "failed to introspect token")
+	}
+
+	// Apps may introspect only tokens issued for them.
+	if claims.AppID != int(in.GetAppId()) {
+		return &ssov1.IntrospectTokenResponse{Active: false}, nil
+	}
+
+	return &ssov1.IntrospectTokenResponse{
+		Active:    true,
+		Jti:       claims.ID,
+		UserId:    claims.UserID,
+		Email:     claims.Email,
+		AppId:     int32(claims.AppID),
+		Elevated:  claims.Elevated,
+		ExpiresAt: timestamppb.New(claims.ExpiresAt),
+
+		ServiceAccountId: claims.ServiceAccountID,
+		Scopes:           claims.Scopes,
+		OrgId:            claims.OrgID,
+	}, nil
+}
+
+func (s *serverAPI) GetVerificationStatus(
+	ctx context.Context,
+	in *ssov1.GetVerificationStatusRequest,
+) (*ssov1.GetVerificationStatusResponse, error) {
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	vType, ok := verificationType(in.GetType())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown verification type")
+	}
+
+	if err := s.authenticateApp(ctx, int(in.GetAppId())); err != nil {
+		return nil, err
+	}
+
+	st, err := s.verification.Status(ctx, in.GetEmail(), vType)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get verification status")
+	}
+
+	resp := &ssov1.GetVerificationStatusResponse{
+		Pending:           st.Pending,
+		RemainingAttempts: int32(st.RemainingAttempts),
+	}
+	if st.Pending {
+		resp.ExpiresAt = timestamppb.New(st.ExpiresAt)
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetEmailStatus(
+	ctx context.Context,
+	in *ssov1.GetEmailStatusRequest,
+) (*ssov1.GetEmailStatusResponse, error) {
+	if in.GetMessageId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "message_id is required")
+	}
+
+	emails, err := s.emailTracker.EmailStatus(ctx, in.GetMessageId())
+	if err != nil {
+		if errors.Is(err, storage.ErrEmailNotFound) {
+			return nil, status.Error(codes.NotFound, "email not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to get email status")
+	}
+
+	deliveries := make([]*ssov1.EmailDelivery, 0, len(emails))
+	for _, e := range emails {
+		deliveries = append(deliveries, &ssov1.EmailDelivery{
+			Recipient: e.Recipient,
+			UserId:    e.UserID,
+			Status:    string(e.Status),
+			Reason:    e.Reason,
+			UpdatedAt: timestamppb.New(e.UpdatedAt),
+		})
+	}
+
+	return &ssov1.GetEmailStatusResponse{MessageId: in.GetMessageId(), Deliveries: deliveries}, nil
+}
+
+// GetSecurityEvents returns recent security-relevant events of the user, e.g. logins and password resets,
+// for account security pages. Users see only their own events, the call is authorized by the user's access token.
+func (s *serverAPI) GetSecurityEvents(
+	ctx context.Context,
+	in *ssov1.GetSecurityEventsRequest,
+) (*ssov1.GetSecurityEventsResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	userID, err := s.authenticateUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID != in.GetUserId() {
+		return nil, status.Error(codes.PermissionDenied, "users can get only their own security events")
+	}
+
+	limit := int(in.GetLimit())
+	if limit == 0 {
+		limit = defaultSecurityEvents
+	}
+	if limit > maxSecurityEvents {
+		limit = maxSecurityEvents
+	}
+
+	events, err := s.auditLog.SecurityEvents(ctx, userID, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get security events")
+	}
+
+	res := make([]*ssov1.SecurityEvent, 0, len(events))
+	for _, e := range events {
+		res = append(res, &ssov1.SecurityEvent{
+			Id:        e.ID,
+			Action:    string(e.Action),
+			AppId:     int32(e.AppID),
+			Ip:        e.IP,
+			Country:   e.Country,
+			City:      e.City,
+			Payload:   e.Payload,
+			CreatedAt: timestamppb.New(e.CreatedAt),
+		})
+	}
+
+	return &ssov1.GetSecurityEventsResponse{Events: res}, nil
+}
+
+// ReplayWebhook queues webhook delivery of the app again, e.g. a dead one once the app fixed its endpoint.
+func (s *serverAPI) ReplayWebhook(
+	ctx context.Context,
+	in *ssov1.ReplayWebhookRequest,
+) (*ssov1.ReplayWebhookResponse, error) {
+	if in.GetDeliveryId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "delivery_id is required")
+	}
+
+	if err := s.authenticateApp(ctx, int(in.GetAppId())); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhooks.Replay(ctx, int(in.GetAppId()), in.GetDeliveryId()); err != nil {
+		if errors.Is(err, storage.ErrWebhookDeliveryNotFound) {
+			return nil, status.Error(codes.NotFound, "webhook delivery not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to replay webhook")
+	}
+
+	return &ssov1.ReplayWebhookResponse{Success: true}, nil
+}
+
+// CreateOrganization creates organization owned by the user the call is authorized by.
+func (s *serverAPI) CreateOrganization(
+	ctx context.Context,
+	in *ssov1.CreateOrganizationRequest,
+) (*ssov1.CreateOrganizationResponse, error) {
+	if in.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, err := s.organizations.Create(ctx, claims.UserID, claims.Email, in.GetName())
+	if err != nil {
+		if errors.Is(err, organization.ErrInvalidName) {
+			return nil, status.Error(codes.InvalidArgument, "invalid organization name")
+		}
+		if errors.Is(err, storage.ErrOrganizationExists) {
+			return nil, status.Error(codes.AlreadyExists, "organization already exists")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to create organization")
+	}
+
+	return &ssov1.CreateOrganizationResponse{OrgId: orgID}, nil
+}
+
+// InviteToOrganization emails invitation code to join the organization, the caller must be its owner or admin.
+func (s *serverAPI) InviteToOrganization(
+	ctx context.Context,
+	in *ssov1.InviteToOrganizationRequest,
+) (*ssov1.InviteToOrganizationResponse, error) {
+	if in.GetOrgId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "org_id is required")
+	}
+
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.organizations.Invite(ctx, claims.UserID, claims.Email, in.GetOrgId(), in.GetEmail(), models.OrgRole(in.GetRole())); err != nil {
+		if errors.Is(err, organization.ErrInvalidRole) {
+			return nil, status.Error(codes.InvalidArgument, "role must be owner, admin or member")
+		}
+		if errors.Is(err, organization.ErrNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "not allowed to invite with the role")
+		}
+
+		return nil, sendEmailError(err)
+	}
+
+	return &ssov1.InviteToOrganizationResponse{Success: true}, nil
+}
+
+// AcceptOrganizationInvitation adds the user the call is authorized by to the organization by the code
+// of invitation sent to the user's email. Tokens issued afterwards have the organization in orgs claim.
+func (s *serverAPI) AcceptOrganizationInvitation(
+	ctx context.Context,
+	in *ssov1.AcceptOrganizationInvitationRequest,
+) (*ssov1.AcceptOrganizationInvitationResponse, error) {
+	if in.GetOrgId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "org_id is required")
+	}
+
+	if in.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.organizations.Accept(ctx, claims.UserID, claims.Email, in.GetOrgId(), in.GetCode())
+	if err != nil {
+		if errors.Is(err, organization.ErrInvalidInvitation) {
+			return nil, status.Error(codes.PermissionDenied, "invitation is invalid or expired")
+		}
+		if errors.Is(err, storage.ErrOrganizationMemberExists) {
+			return nil, status.Error(codes.AlreadyExists, "already a member of the organization")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to accept invitation")
+	}
+
+	return &ssov1.AcceptOrganizationInvitationResponse{Organization: organizationPb(member)}, nil
+}
+
+// ListOrganizations returns organizations of the user the call is authorized by with the user's roles.
+func (s *serverAPI) ListOrganizations(
+	ctx context.Context,
+	in *ssov1.ListOrganizationsRequest,
+) (*ssov1.ListOrganizationsResponse, error) {
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.organizations.Memberships(ctx, claims.UserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list organizations")
+	}
+
+	res := make([]*ssov1.Organization, 0, len(members))
+	for _, member := range members {
+		res = append(res, organizationPb(member))
+	}
+
+	return &ssov1.ListOrganizationsResponse{Organizations: res}, nil
+}
+
+// IssueServiceAccountToken exchanges API key or JWT assertion of a service account for its access token,
+// limited to the requested scopes or all scopes of the account if none are requested.
+func (s *serverAPI) IssueServiceAccountToken(
+	ctx context.Context,
+	in *ssov1.IssueServiceAccountTokenRequest,
+) (*ssov1.IssueServiceAccountTokenResponse, error) {
+	if (in.GetApiKey() == "") == (in.GetAssertion() == "") {
+		return nil, status.Error(codes.InvalidArgument, "either api_key or assertion is required")
+	}
+
+	var (
+		token serviceaccount.Token
+		err   error
+	)
+	if in.GetApiKey() != "" {
+		token, err = s.serviceAccounts.IssueTokenByAPIKey(ctx, in.GetApiKey(), in.GetScopes())
+	} else {
+		token, err = s.serviceAccounts.IssueTokenByAssertion(ctx, in.GetAssertion(), in.GetScopes())
+	}
+	if err != nil {
+		if errors.Is(err, serviceaccount.ErrInvalidCredentials) {
+			return nil, status.Error(codes.Unauthenticated, "invalid service account credentials")
+		}
+		if errors.Is(err, serviceaccount.ErrInvalidScope) {
+			return nil, status.Error(codes.InvalidArgument, "scope is not granted to the service account")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to issue token")
+	}
+
+	return &ssov1.IssueServiceAccountTokenResponse{
+		Token:     token.Token,
+		Scopes:    token.Scopes,
+		ExpiresAt: timestamppb.New(token.ExpiresAt),
+	}, nil
+}
+
+// RequestAccountDeletion emails code confirming deletion of the account of the user the call is authorized by.
+func (s *serverAPI) RequestAccountDeletion(
+	ctx context.Context,
+	in *ssov1.RequestAccountDeletionRequest,
+) (*ssov1.RequestAccountDeletionResponse, error) {
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerEmail, strings.ToLower(claims.Email)); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountDeletion.Request(ctx, claims.UserID, claims.Email); err != nil {
+		return nil, sendEmailError(err)
+	}
+
+	return &ssov1.RequestAccountDeletionResponse{Success: true}, nil
+}
+
+// ConfirmAccountDeletion schedules deletion of the account of the user the call is authorized by
+// after the grace period, if code is the one sent by RequestAccountDeletion. All tokens of the user are revoked,
+// signing in before scheduled_at cancels the deletion.
+func (s *serverAPI) ConfirmAccountDeletion(
+	ctx context.Context,
+	in *ssov1.ConfirmAccountDeletionRequest,
+) (*ssov1.ConfirmAccountDeletionResponse, error) {
+	if in.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledAt, err := s.accountDeletion.Confirm(ctx, claims.UserID, claims.Email, in.GetCode())
+	if err != nil {
+		if errors.Is(err, deletion.ErrInvalidCode) {
+			return nil, status.Error(codes.PermissionDenied, "invalid or expired code")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to schedule account deletion")
+	}
+
+	return &ssov1.ConfirmAccountDeletionResponse{ScheduledAt: timestamppb.New(scheduledAt)}, nil
+}
+
+// CheckPasswordStrength estimates strength of the password for registration and password change forms,
+// the same estimate and policy are applied when the password is set. Email, if known, makes passwords
+// containing the user's name weaker. The password is neither stored nor logged.
+func (s *serverAPI) CheckPasswordStrength(
+	ctx context.Context,
+	in *ssov1.CheckPasswordStrengthRequest,
+) (*ssov1.CheckPasswordStrengthResponse, error) {
+	var userInputs []string
+	if in.GetEmail() != "" {
+		userInputs = append(userInputs, in.GetEmail())
+	}
+
+	res, err := s.passwordPolicy.Check(in.GetPassword(), userInputs...)
+
+	return &ssov1.CheckPasswordStrengthResponse{
+		Score:        int32(res.Score),
+		GuessesLog10: res.GuessesLog10(),
+		Warning:      res.Warning,
+		Suggestions:  res.Suggestions,
+		Acceptable:   err == nil,
+	}, nil
+}
+
+// StartDeviceLogin starts login of a device with no convenient input, e.g. a TV, to the app.
+// The device shows the pairing code, or verification_uri as QR code, and polls PollDeviceLogin
+// with poll_token every interval until the user approves the login by ApproveDeviceLogin from a signed in device.
+func (s *serverAPI) StartDeviceLogin(
+	ctx context.Context,
+	in *ssov1.StartDeviceLoginRequest,
+) (*ssov1.StartDeviceLoginResponse, error) {
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerIP, peer.IP(ctx)); err != nil {
+		return nil, err
+	}
+
+	started, err := s.deviceLogins.Start(ctx, int(in.GetAppId()), clientDevice(ctx))
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to start device login")
+	}
+
+	return &ssov1.StartDeviceLoginResponse{
+		PollToken:       started.PollToken,
+		PairingCode:     started.PairingCode,
+		VerificationUri: started.VerificationURI,
+		ExpiresAt:       timestamppb.New(started.ExpiresAt),
+		Interval:        int32(started.PollInterval / time.Second),
+	}, nil
+}
+
+// ApproveDeviceLogin approves pending device login with its pairing code on behalf of the user
+// the call is authorized by. The app and device approved are returned for the user to review.
+func (s *serverAPI) ApproveDeviceLogin(
+	ctx context.Context,
+	in *ssov1.ApproveDeviceLoginRequest,
+) (*ssov1.ApproveDeviceLoginResponse, error) {
+	if in.GetPairingCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "pairing_code is required")
+	}
+
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pairing codes are short, so guessing them is throttled per user.
+	if err := s.throttle(ctx, s.rateLimits.VerificationPerEmail, strings.ToLower(claims.Email)); err != nil {
+		return nil, err
+	}
+
+	login, err := s.deviceLogins.Approve(ctx, claims.UserID, claims.Email, in.GetPairingCode())
+	if err != nil {
+		if errors.Is(err, devicelogin.ErrInvalidPairingCode) {
+			return nil, status.Error(codes.PermissionDenied, "invalid or expired pairing code")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to approve device login")
+	}
+
+	return &ssov1.ApproveDeviceLoginResponse{
+		AppId:     int32(login.AppID),
+		UserAgent: login.Device.UserAgent,
+		Ip:        login.Device.IP,
+		Country:   login.Device.Country,
+	}, nil
+}
+
+// PollDeviceLogin returns token of the device once its login is approved. Until then
+// FailedPrecondition with AUTHORIZATION_PENDING reason is returned, NotFound once the login expired.
+func (s *serverAPI) PollDeviceLogin(
+	ctx context.Context,
+	in *ssov1.PollDeviceLoginRequest,
+) (*ssov1.PollDeviceLoginResponse, error) {
+	if in.GetPollToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "poll_token is required")
+	}
+
+	token, err := s.deviceLogins.Poll(ctx, in.GetPollToken())
+	if err != nil {
+		switch {
+		case errors.Is(err, devicelogin.ErrPending):
+			return nil, errorWithReason(codes.FailedPrecondition, "device login is not approved yet", reasonAuthorizationPending, nil)
+		case errors.Is(err, devicelogin.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "device login not found or expired")
+		case errors.Is(err, auth.ErrUserNotVerified):
+			return nil, status.Error(codes.FailedPrecondition, "email is not verified")
+		case errors.Is(err, auth.ErrUserBanned):
+			return nil, bannedError()
+		}
+
+		return nil, status.Error(codes.Internal, "failed to complete device login")
+	}
+
+	return &ssov1.PollDeviceLoginResponse{Token: token}, nil
+}
+
+// GetServerInfo returns build info of the server, so clients and support can tell which version is running.
+func (s *serverAPI) GetServerInfo(
+	ctx context.Context,
+	in *ssov1.GetServerInfoRequest,
+) (*ssov1.GetServerInfoResponse, error) {
+	info := version.Get()
+
+	return &ssov1.GetServerInfoResponse{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildDate: info.BuildDate,
+		GoVersion: info.GoVersion,
+	}, nil
+}
+
+// accountExistsEmail is sent on registration with email of an existing account if anti-enumeration is enabled.
+const accountExistsEmail = "Someone tried to sign up with this email, but you already have an account. " +
+	"If it was you, sign in or reset your password. Otherwise, ignore this email."
+
+// verificationType maps verification type of the request.
+// Unspecified type means registration, as it was the only type before types were introduced.
+func verificationType(t ssov1.VerificationType) (models.VerificationType, bool) {
+	switch t {
+	case ssov1.VerificationType_VERIFICATION_TYPE_UNSPECIFIED, ssov1.VerificationType_VERIFICATION_TYPE_REGISTRATION:
+		return models.VerificationTypeRegistration, true
+	case ssov1.VerificationType_VERIFICATION_TYPE_PASSWORD_RESET:
+		return models.VerificationTypePasswordReset, true
+	case ssov1.VerificationType_VERIFICATION_TYPE_EMAIL_CHANGE:
+		return models.VerificationTypeEmailChange, true
+	default:
+		return "", false
+	}
+}
+
+func verificationEmailSubject(vType models.VerificationType) string {
+	switch vType {
+	case models.VerificationTypePasswordReset:
+		return "Reset your password"
+	case models.VerificationTypeEmailChange:
+		return "Confirm your new email"
+	default:
+		return "Verify your new account"
+	}
+}
+
+// verifyCaptcha checks captcha token passed in request metadata, if captcha is enabled. Details of errors
+// carry the header of the token and the trigger which required captcha.
+func (s *serverAPI) verifyCaptcha(ctx context.Context, trigger string) error {
+	if s.captcha == nil {
+		return nil
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(captchaTokenHeader); len(values) > 0 {
+			token = values[0]
+		}
+	}
+
+	err := s.captcha.Verify(ctx, token, peer.IP(ctx))
+	if err == nil {
+		return nil
+	}
+
+	md := map[string]string{
+		"captcha_header": captchaTokenHeader,
+		"trigger":        trigger,
+	}
+
+	switch {
+	case errors.Is(err, captcha.ErrTokenRequired):
+		return errorWithReason(codes.PermissionDenied, "captcha is required", reasonCaptchaRequired, md)
+	case errors.Is(err, captcha.ErrInvalidToken):
+		return errorWithReason(codes.PermissionDenied, "captcha is invalid", reasonCaptchaInvalid, md)
+	default:
+		return status.Error(codes.Internal, "failed to verify captcha")
+	}
+}
+
+// throttle rejects request if limiter doesn't allow one more request by the key.
+func (s *serverAPI) throttle(ctx context.Context, limiter Limiter, key string) error {
+	if limiter == nil {
+		return nil
+	}
+
+	allowed, err := limiter.Allow(ctx, key)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to check rate limit")
+	}
+
+	if !allowed {
+		return errorWithReason(codes.ResourceExhausted, "too many requests, try again later", reasonRateLimited, nil)
+	}
+
+	return nil
+}
+
+// checkLoginThrottles rejects login while the account or client IP is delayed or locked out after failed logins,
+// and requires captcha once they failed too many times.
+func (s *serverAPI) checkLoginThrottles(ctx context.Context, account string, ip string) error {
+	var captchaRequired bool
+
+	for _, check := range []struct {
+		throttle Throttle
+		key      string
+	}{
+		{s.rateLimits.LoginPerAccount, account},
+		{s.rateLimits.LoginPerIP, ip},
+		{s.rateLimits.LoginStuffing, ip},
+	} {
+		if check.throttle == nil {
+			continue
+		}
+
+		decision, err := check.throttle.Check(ctx, check.key)
+		if err != nil {
+			return status.Error(codes.Internal, "failed to check rate limit")
+		}
+
+		if !decision.Allowed() {
+			return loginThrottledError(decision)
+		}
+
+		captchaRequired = captchaRequired || decision.CaptchaRequired
+	}
+
+	if captchaRequired {
+		return s.verifyCaptcha(ctx, captchaTriggerFailedLogins)
+	}
+
+	return nil
+}
+
+// failLogin counts failed login of the account and client IP.
+func (s *serverAPI) failLogin(ctx context.Context, account string, ip string) {
+	if s.rateLimits.LoginPerAccount != nil {
+		s.rateLimits.LoginPerAccount.Fail(ctx, account)
+	}
+
+	if s.rateLimits.LoginPerIP != nil {
+		s.rateLimits.LoginPerIP.Fail(ctx, ip)
+	}
+
+	if s.rateLimits.LoginStuffing != nil {
+		s.rateLimits.LoginStuffing.Fail(ctx, ip)
+	}
+}
+
+// loginThrottledError builds ResourceExhausted error, details tell when login may be retried.
+func loginThrottledError(decision ratelimit.Decision) error {
+	retryAfter := map[string]string{"retry_after_seconds": strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds())))}
+
+	if decision.Locked {
+		return errorWithReason(codes.ResourceExhausted, "too many failed logins, try again later", reasonLoginLocked, retryAfter)
+	}
+
+	return errorWithReason(codes.ResourceExhausted, "too many requests, try again later", reasonRateLimited, retryAfter)
+}
+
+// clientDevice returns device of the client as described by request metadata.
+// Country set by the fronting proxy takes precedence over the one resolved by GeoIP.
+func clientDevice(ctx context.Context) models.Device {
+	device := models.Device{
+		ID:        metadataValue(ctx, deviceIDHeader),
+		UserAgent: metadataValue(ctx, "user-agent"),
+		IP:        peer.IP(ctx),
+		Country:   strings.ToUpper(metadataValue(ctx, countryHeader)),
+	}
+
+	if loc, ok := geoip.FromContext(ctx); ok {
+		if device.Country == "" {
+			device.Country = loc.Country
+		}
+		if device.Country == loc.Country {
+			device.City = loc.City
+		}
+	}
+
+	return device
+}
+
+// metadataValue returns the first value of the request metadata key, empty string if it's not set.
+func metadataValue(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+// authenticateUser checks access token passed as bearer token in request metadata and returns ID of its user.
+func (s *serverAPI) authenticateUser(ctx context.Context) (int64, error) {
+	claims, err := s.authenticateUserClaims(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return claims.UserID, nil
+}
+
+// authenticateUserClaims returns claims of the caller authenticated by the authorization interceptor,
+// otherwise authenticates the bearer token itself. Tokens of service accounts are rejected.
+func (s *serverAPI) authenticateUserClaims(ctx context.Context) (jwt.Claims, error) {
+	if claims, ok := jwt.FromContext(ctx); ok {
+		if claims.ServiceAccountID != 0 {
+			return jwt.Claims{}, status.Error(codes.Unauthenticated, "invalid access token")
+		}
+
+		return claims, nil
+	}
+
+	token, ok := strings.CutPrefix(metadataValue(ctx, "authorization"), "Bearer ")
+	if !ok || token == "" {
+		return jwt.Claims{}, status.Error(codes.Unauthenticated, "access token is required")
+	}
+
+	claims, err := s.auth.AuthenticateUser(ctx, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrTokenRevoked):
+			return jwt.Claims{}, status.Error(codes.Unauthenticated, "access token is revoked")
+		case errors.Is(err, auth.ErrTokenConsumed):
+			return jwt.Claims{}, status.Error(codes.Unauthenticated, "one-time access token is already used")
+		case errors.Is(err, auth.ErrInvalidToken):
+			return jwt.Claims{}, status.Error(codes.Unauthenticated, "invalid access token")
+		default:
+			return jwt.Claims{}, status.Error(codes.Internal, "failed to authenticate user")
+		}
+	}
+
+	return claims, nil
+}
+
+// isRejectedToken reports whether the token failed authentication, as opposed to failure to check it.
+func isRejectedToken(err error) bool {
+	return errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) || errors.Is(err, auth.ErrTokenConsumed)
+}
+
+// authenticateApp checks app secret passed in request metadata.
+func (s *serverAPI) authenticateApp(ctx context.Context, appID int) error {
+	if appID == 0 {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	var secret string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(appSecretHeader); len(values) > 0 {
+			secret = values[0]
+		}
+	}
+
+	if err := s.auth.AuthenticateApp(ctx, appID, secret); err != nil {
+		if errors.Is(err, auth.ErrInvalidAppSecret) {
+			return status.Error(codes.Unauthenticated, "invalid app credentials")
+		}
+
+		return status.Error(codes.Internal, "failed to authenticate app")
+	}
+
+	return nil
+}
+
+// errorWithReason builds status error with google.rpc.ErrorInfo details.
+func errorWithReason(code codes.Code, msg string, reason string, md map[string]string) error {
+	st := status.New(code, msg)
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorDomain,
+		Metadata: md,
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// weakPasswordError builds InvalidArgument error with machine-readable reason,
+// details have the score and warning CheckPasswordStrength returns for the password.
+func (s *serverAPI) weakPasswordError(password string, email string) error {
+	res, _ := s.passwordPolicy.Check(password, email)
+
+	md := map[string]string{"score": strconv.Itoa(res.Score)}
+	if res.Warning != "" {
+		md["warning"] = res.Warning
+	}
+
+	return errorWithReason(codes.InvalidArgument, "password is too weak", reasonPasswordTooWeak, md)
+}
+
+// notVerifiedError builds FailedPrecondition error with machine-readable reason,
+// details point to the RPC which resends verification code.
+func notVerifiedError(email string) error {
+	return errorWithReason(codes.FailedPrecondition, "email is not verified", reasonEmailNotVerified, map[string]string{
+		"email":         email,
+		"resend_method": ssov1.Auth_CreateVerification_FullMethodName,
+		"resend_type":   ssov1.VerificationType_VERIFICATION_TYPE_REGISTRATION.String(),
+	})
+}
+
+// bannedError doesn't tell the reason of the ban, it's for admins.
+func bannedError() error {
+	return errorWithReason(codes.PermissionDenied, "user is banned from the app", reasonUserBanned, nil)
+}
+
+// sendVerificationCode emails the code of verification of type vType to email.
+// With anti-enumeration, failures, e.g. suppressed address, are only logged by the email service: unregistered
+// emails get no email at all, so reporting them would tell the email is registered.
+func (s *serverAPI) sendVerificationCode(ctx context.Context, email string, vType models.VerificationType, code string) error {
+	if _, err := s.emailService.SendEmail(ctx, verificationEmailSubject(vType), []string{email}, code, []string{}, []string{}, []string{}); err != nil {
+		if s.antiEnumeration.Enabled {
+			return nil
+		}
+
+		return sendEmailError(err)
+	}
+	s.verification.Delivered(ctx, vType)
+
+	return nil
+}
+
+func sendEmailError(err error) error {
+	if errors.Is(err, mail.ErrRecipientsSuppressed) {
+		return status.Error(codes.FailedPrecondition, "email address is suppressed")
+	}
+
+	return status.Error(codes.Internal, "failed to send email")
+}
+
+func validateVerificationResult(err error) (bool, error) {
+	if err != nil {
+		if errors.Is(err, storage.ErrVerificationNotFound) {
+			return false, status.Error(codes.NotFound, "verification not found")
+		}
+		if errors.Is(err, storage.ErrVerificationExpired) {
+			return false, status.Error(codes.Internal, "verification expired")
+		}
+		if errors.Is(err, verificationService.CodesDiffer) {
+			return false, status.Error(codes.PermissionDenied, "codes differ")
+		}
+		if errors.Is(err, verificationService.UnknownType) {
+			return false, status.Error(codes.InvalidArgument, "unknown verification type")
+		}
+		if errors.Is(err, verificationService.TooManyAttempts) {
+			return false, status.Error(codes.ResourceExhausted, "too many attempts, request a new code")
+		}
+
+		return false, status.Error(codes.Internal, "failed to verify")
+	}
+
+	return true, nil
+}
+
+func organizationPb(member models.OrganizationMember) *ssov1.Organization {
+	return &ssov1.Organization{
+		OrgId:    member.OrgID,
+		Name:     member.OrgName,
+		Role:     string(member.Role),
+		JoinedAt: timestamppb.New(member.JoinedAt),
+	}
+}
+
+func notificationPreferencesPb(prefs models.NotificationPreferences) *ssov1.NotificationPreferences {
+	return &ssov1.NotificationPreferences{
+		Reminders: prefs.Reminders,
+		Security:  prefs.Security,
+		Product:   prefs.Product,
+	}
 }
@@ -0,0 +1,243 @@
+package authv2grpc
+
+import (
+	"context"
+	"errors"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/services/auth"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	ssov2 "github.com/VanGoghDev/protos/gen/go/sso/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Tokens issued by v1 Login, enriched by v2
+type Auth interface {
+	AuthenticateToken(ctx context.Context, token string) (jwt.Claims, error)
+	IssueRefreshToken(ctx context.Context, claims jwt.Claims, fingerprint string) (auth.RefreshToken, error)
+	Refresh(ctx context.Context, refreshToken string, fingerprint string) (token string, refresh auth.RefreshToken, err error)
+	PendingRegistration() bool
+}
+
+// Verification code formats, may change while the server is running
+type VerificationCodes interface {
+	For(vType models.VerificationType) verification.CodeFormat
+}
+
+// Fingerprint returns fingerprint of the client refresh tokens are bound to, empty if it can't be bound.
+// Its errors are gRPC status errors.
+type Fingerprint func(ctx context.Context) (string, error)
+
+// serverAPI serves sso.v2.Auth by adapting v1 RPCs, so validation, throttling, captcha and anti-enumeration
+// are the same for both versions, v2 only enriches their responses.
+type serverAPI struct {
+	ssov2.UnimplementedAuthServer
+	v1                ssov1.AuthServer
+	auth              Auth
+	verificationCodes VerificationCodes
+	fingerprint       Fingerprint
+	clock             clock.Clock
+}
+
+// tokenType is token_type of access tokens, clients send them in authorization metadata as "Bearer <token>".
+const tokenType = "Bearer"
+
+// Reasons of v1 errors which are responses of v2.
+const (
+	errorDomain              = "sso"
+	reasonSignInNotConfirmed = "SIGN_IN_NOT_CONFIRMED"
+	reasonCaptchaRequired    = "CAPTCHA_REQUIRED"
+)
+
+// Register registers sso.v2.Auth on the server next to sso.Auth, whose server v1 is adapted.
+// Nil fingerprint doesn't bind refresh tokens to clients.
+func Register(
+	gRPCServer *grpc.Server,
+	v1 ssov1.AuthServer,
+	auth Auth,
+	verificationCodes VerificationCodes,
+	fingerprint Fingerprint,
+	clock clock.Clock,
+) {
+	ssov2.RegisterAuthServer(gRPCServer, &serverAPI{
+		v1:                v1,
+		auth:              auth,
+		verificationCodes: verificationCodes,
+		fingerprint:       fingerprint,
+		clock:             clock,
+	})
+}
+
+// Login responds with access and refresh tokens. Sign-in from new device that must be confirmed is not an error
+// in v2: the response carries the challenge, the client repeats Login with the code in its header.
+func (s *serverAPI) Login(
+	ctx context.Context,
+	in *ssov2.LoginRequest,
+) (*ssov2.LoginResponse, error) {
+	// Invalid proof of the client fails before credentials are checked.
+	fingerprint, err := s.clientFingerprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.v1.Login(ctx, &ssov1.LoginRequest{
+		Email:    in.GetEmail(),
+		Password: in.GetPassword(),
+		AppId:    in.GetAppId(),
+	})
+	if err != nil {
+		if md, ok := errorReason(err, reasonSignInNotConfirmed); ok {
+			return &ssov2.LoginResponse{
+				Challenge: &ssov2.Challenge{
+					Type:       ssov2.ChallengeType_CHALLENGE_TYPE_EMAIL_CODE,
+					CodeHeader: md["code_header"],
+				},
+			}, nil
+		}
+		// Login requires captcha only once the account or client IP failed too many times.
+		if md, ok := errorReason(err, reasonCaptchaRequired); ok {
+			return &ssov2.LoginResponse{
+				Challenge: &ssov2.Challenge{
+					Type:       ssov2.ChallengeType_CHALLENGE_TYPE_CAPTCHA,
+					CodeHeader: md["captcha_header"],
+				},
+			}, nil
+		}
+
+		return nil, err
+	}
+
+	claims, err := s.auth.AuthenticateToken(ctx, resp.GetToken())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to login")
+	}
+
+	refresh, err := s.auth.IssueRefreshToken(ctx, claims, fingerprint)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to login")
+	}
+
+	return &ssov2.LoginResponse{
+		AccessToken:           resp.GetToken(),
+		TokenType:             tokenType,
+		ExpiresAt:             timestamppb.New(claims.ExpiresAt),
+		RefreshToken:          refresh.Token,
+		RefreshTokenExpiresAt: refreshExpiresAt(refresh),
+	}, nil
+}
+
+// Register responds with the state of verification of the email. With anti-enumeration enabled, registered emails
+// get the same state as new ones, the owner is emailed instead of the code.
+func (s *serverAPI) Register(
+	ctx context.Context,
+	in *ssov2.RegisterRequest,
+) (*ssov2.RegisterResponse, error) {
+	resp, err := s.v1.Register(ctx, &ssov1.RegisterRequest{
+		Email:    in.GetEmail(),
+		Password: in.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state := ssov2.VerificationState_VERIFICATION_STATE_CODE_SENT
+	if s.auth.PendingRegistration() {
+		state = ssov2.VerificationState_VERIFICATION_STATE_PENDING_REGISTRATION
+	}
+
+	codeTTL := s.verificationCodes.For(models.VerificationTypeRegistration).TTL
+
+	return &ssov2.RegisterResponse{
+		UserId: resp.GetUserId(),
+		Verification: &ssov2.Verification{
+			State:         state,
+			CodeExpiresAt: timestamppb.New(s.clock.Now().Add(codeTTL)),
+		},
+	}, nil
+}
+
+// Refresh exchanges refresh token for new access and refresh tokens, the exchanged one is rejected from then on.
+// Token bound to a client is rejected if another client exchanges it.
+func (s *serverAPI) Refresh(
+	ctx context.Context,
+	in *ssov2.RefreshRequest,
+) (*ssov2.RefreshResponse, error) {
+	if in.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	fingerprint, err := s.clientFingerprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, refresh, err := s.auth.Refresh(ctx, in.GetRefreshToken(), fingerprint)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+		}
+		if errors.Is(err, auth.ErrUserNotVerified) {
+			return nil, status.Error(codes.FailedPrecondition, "email is not verified")
+		}
+		if errors.Is(err, auth.ErrUserBanned) {
+			return nil, status.Error(codes.PermissionDenied, "user is banned from the app")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to refresh token")
+	}
+
+	claims, err := s.auth.AuthenticateToken(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to refresh token")
+	}
+
+	return &ssov2.RefreshResponse{
+		AccessToken:           token,
+		TokenType:             tokenType,
+		ExpiresAt:             timestamppb.New(claims.ExpiresAt),
+		RefreshToken:          refresh.Token,
+		RefreshTokenExpiresAt: refreshExpiresAt(refresh),
+	}, nil
+}
+
+// clientFingerprint returns fingerprint of the client, empty if refresh tokens aren't bound.
+func (s *serverAPI) clientFingerprint(ctx context.Context) (string, error) {
+	if s.fingerprint == nil {
+		return "", nil
+	}
+
+	return s.fingerprint(ctx)
+}
+
+// refreshExpiresAt is nil if refresh tokens are disabled.
+func refreshExpiresAt(refresh auth.RefreshToken) *timestamppb.Timestamp {
+	if refresh.Token == "" {
+		return nil
+	}
+
+	return timestamppb.New(refresh.ExpiresAt)
+}
+
+// errorReason returns metadata of google.rpc.ErrorInfo of v1 error if it has the reason.
+func errorReason(err error, reason string) (map[string]string, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == errorDomain && info.GetReason() == reason {
+			return info.GetMetadata(), true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,84 @@
+package bounceshttp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"grpc-service-ref/internal/domain/models"
+)
+
+// maxBodySize limits webhook payloads, providers batch events but never come close to it.
+const maxBodySize = 1 << 20
+
+// DeliveryEventReporter handles delivery events reported by email providers.
+type DeliveryEventReporter interface {
+	ReportDeliveryEvent(ctx context.Context, event models.DeliveryEvent) error
+}
+
+// Register registers bounce and complaint webhooks of email providers:
+//
+//	POST /webhooks/email/ses       - Amazon SES notifications delivered by SNS
+//	POST /webhooks/email/sendgrid  - SendGrid event webhook
+//
+// SNS messages are always verified by their signature, so ses webhook is always registered.
+// SendGrid webhook is registered only if the verification key is configured.
+// snsTopicARN restricts accepted SNS messages to the given topic if not empty.
+func Register(
+	mux *http.ServeMux,
+	log *slog.Logger,
+	reporter DeliveryEventReporter,
+	snsTopicARN string,
+	sendGridPublicKey string,
+) error {
+	const op = "bounceshttp.Register"
+
+	mux.Handle("/webhooks/email/ses", &sesHandler{
+		log:      log,
+		reporter: reporter,
+		topicARN: snsTopicARN,
+		certs:    newCertCache(),
+	})
+
+	if sendGridPublicKey == "" {
+		log.Warn("sendgrid webhook verification key is not set, sendgrid webhook is disabled")
+
+		return nil
+	}
+
+	key, err := parseECDSAPublicKey(sendGridPublicKey)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	mux.Handle("/webhooks/email/sendgrid", &sendGridHandler{
+		log:       log,
+		reporter:  reporter,
+		publicKey: key,
+	})
+
+	return nil
+}
+
+func parseECDSAPublicKey(s string) (*ecdsa.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not ECDSA key")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,123 @@
+package bounceshttp
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+)
+
+const (
+	sendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	sendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+type sendGridEvent struct {
+	Email  string `json:"email"`
+	Event  string `json:"event"`
+	SMTPID string `json:"smtp-id"`
+	Reason string `json:"reason"`
+	// Type is "bounce" for hard bounces and "blocked" for soft ones.
+	Type string `json:"type"`
+}
+
+type sendGridHandler struct {
+	log       *slog.Logger
+	reporter  DeliveryEventReporter
+	publicKey *ecdsa.PublicKey
+}
+
+func (h *sendGridHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const op = "bounceshttp.SendGrid"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verify(r.Header.Get(sendGridTimestampHeader), r.Header.Get(sendGridSignatureHeader), body) {
+		log.Warn("invalid sendgrid webhook signature")
+
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	var events []sendGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	for _, e := range events {
+		event, ok := e.deliveryEvent()
+		if !ok {
+			continue
+		}
+
+		if err := h.reporter.ReportDeliveryEvent(r.Context(), event); err != nil {
+			log.Error("failed to report delivery event", sl.Err(err))
+
+			// SendGrid retries the whole batch on non 2xx response.
+			http.Error(w, "failed to process event", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks ECDSA signature of timestamp+payload.
+func (h *sendGridHandler) verify(timestamp string, signature string, body []byte) bool {
+	if timestamp == "" || signature == "" {
+		return false
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(timestamp))
+	hash.Write(body)
+
+	return ecdsa.VerifyASN1(h.publicKey, hash.Sum(nil), sig)
+}
+
+func (e sendGridEvent) deliveryEvent() (models.DeliveryEvent, bool) {
+	event := models.DeliveryEvent{
+		MessageID: e.SMTPID,
+		Recipient: e.Email,
+		Reason:    e.Reason,
+	}
+
+	switch e.Event {
+	case "delivered":
+		event.Status = models.EmailStatusDelivered
+	case "bounce":
+		event.Status = models.EmailStatusBounced
+		event.Permanent = e.Type != "blocked"
+	case "spamreport":
+		event.Status = models.EmailStatusComplained
+	default:
+		return models.DeliveryEvent{}, false
+	}
+
+	return event, true
+}
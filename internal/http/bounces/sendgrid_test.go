@@ -0,0 +1,144 @@
+package bounceshttp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signSendGrid(t *testing.T, key *ecdsa.PrivateKey, timestamp string, body string) string {
+	t.Helper()
+
+	hash := sha256.Sum256([]byte(timestamp + body))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestSendGridHandler(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	const (
+		timestamp = "1700000000"
+		body      = `[
+			{"email": "a@example.com", "event": "bounce", "type": "bounce", "smtp-id": "<id@example.com>", "reason": "550"},
+			{"email": "b@example.com", "event": "bounce", "type": "blocked", "smtp-id": "<id@example.com>"},
+			{"email": "c@example.com", "event": "spamreport", "smtp-id": "<id@example.com>"},
+			{"email": "d@example.com", "event": "delivered", "smtp-id": "<id@example.com>"},
+			{"email": "e@example.com", "event": "open", "smtp-id": "<id@example.com>"}
+		]`
+	)
+
+	tests := []struct {
+		name       string
+		timestamp  string
+		signature  string
+		body       string
+		wantStatus int
+		wantEvents []models.DeliveryEvent
+	}{
+		{
+			name:       "valid",
+			timestamp:  timestamp,
+			signature:  signSendGrid(t, key, timestamp, body),
+			body:       body,
+			wantStatus: http.StatusNoContent,
+			wantEvents: []models.DeliveryEvent{
+				{MessageID: "<id@example.com>", Recipient: "a@example.com", Status: models.EmailStatusBounced, Permanent: true, Reason: "550"},
+				{MessageID: "<id@example.com>", Recipient: "b@example.com", Status: models.EmailStatusBounced},
+				{MessageID: "<id@example.com>", Recipient: "c@example.com", Status: models.EmailStatusComplained},
+				{MessageID: "<id@example.com>", Recipient: "d@example.com", Status: models.EmailStatusDelivered},
+			},
+		},
+		{
+			name:       "tampered body",
+			timestamp:  timestamp,
+			signature:  signSendGrid(t, key, timestamp, body),
+			body:       strings.Replace(body, "a@example.com", "victim@example.com", 1),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "replayed with another timestamp",
+			timestamp:  "1700000001",
+			signature:  signSendGrid(t, key, timestamp, body),
+			body:       body,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "missing signature",
+			timestamp:  timestamp,
+			body:       body,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "malformed signature",
+			timestamp:  timestamp,
+			signature:  "not base64!",
+			body:       body,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "invalid payload",
+			timestamp:  timestamp,
+			signature:  signSendGrid(t, key, timestamp, "{}"),
+			body:       "{}",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := &recordingReporter{}
+			h := &sendGridHandler{log: log, reporter: reporter, publicKey: &key.PublicKey}
+
+			r := httptest.NewRequest(http.MethodPost, "/webhooks/email/sendgrid", strings.NewReader(tt.body))
+			r.Header.Set(sendGridTimestampHeader, tt.timestamp)
+			if tt.signature != "" {
+				r.Header.Set(sendGridSignatureHeader, tt.signature)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantEvents, reporter.events)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	require.NoError(t, Register(mux, log, &recordingReporter{}, "", base64.StdEncoding.EncodeToString(der)))
+	_, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, "/webhooks/email/sendgrid", nil))
+	assert.Equal(t, "/webhooks/email/sendgrid", pattern)
+
+	mux = http.NewServeMux()
+	require.NoError(t, Register(mux, log, &recordingReporter{}, "", ""))
+	_, pattern = mux.Handler(httptest.NewRequest(http.MethodPost, "/webhooks/email/sendgrid", nil))
+	assert.Empty(t, pattern, "sendgrid webhook is disabled without key")
+
+	assert.Error(t, Register(http.NewServeMux(), log, &recordingReporter{}, "", "not a key"))
+}
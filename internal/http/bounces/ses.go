@@ -0,0 +1,168 @@
+package bounceshttp
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+)
+
+const (
+	snsTypeNotification             = "Notification"
+	snsTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+)
+
+type sesNotification struct {
+	// NotificationType is set for SES notifications, EventType for SES event publishing.
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Mail             struct {
+		MessageID     string `json:"messageId"`
+		CommonHeaders struct {
+			MessageID string `json:"messageId"`
+		} `json:"commonHeaders"`
+	} `json:"mail"`
+	Bounce struct {
+		BounceType        string `json:"bounceType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+	Delivery struct {
+		Recipients []string `json:"recipients"`
+	} `json:"delivery"`
+}
+
+type sesHandler struct {
+	log      *slog.Logger
+	reporter DeliveryEventReporter
+	topicARN string
+	certs    *certCache
+}
+
+func (h *sesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const op = "bounceshttp.SES"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var msg snsMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if h.topicARN != "" && msg.TopicArn != h.topicARN {
+		log.Warn("sns message from unexpected topic", slog.String("topic_arn", msg.TopicArn))
+
+		http.Error(w, "unexpected topic", http.StatusForbidden)
+		return
+	}
+
+	if err := h.certs.verify(r.Context(), msg); err != nil {
+		log.Warn("invalid sns message signature", sl.Err(err))
+
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	switch msg.Type {
+	case snsTypeSubscriptionConfirmation:
+		if err := confirmSubscription(r.Context(), msg.SubscribeURL); err != nil {
+			log.Error("failed to confirm sns subscription", sl.Err(err))
+
+			http.Error(w, "failed to confirm subscription", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info("sns subscription confirmed", slog.String("topic_arn", msg.TopicArn))
+	case snsTypeNotification:
+		var n sesNotification
+		if err := json.Unmarshal([]byte(msg.Message), &n); err != nil {
+			http.Error(w, "invalid notification", http.StatusBadRequest)
+			return
+		}
+
+		for _, event := range n.deliveryEvents() {
+			if err := h.reporter.ReportDeliveryEvent(r.Context(), event); err != nil {
+				log.Error("failed to report delivery event", sl.Err(err))
+
+				// SNS retries delivery on non 2xx response.
+				http.Error(w, "failed to process notification", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (n sesNotification) deliveryEvents() []models.DeliveryEvent {
+	// Prefer Message-Id header set by us over the SES internal id.
+	msgID := n.Mail.CommonHeaders.MessageID
+	if msgID == "" {
+		msgID = n.Mail.MessageID
+	}
+
+	kind := n.NotificationType
+	if kind == "" {
+		kind = n.EventType
+	}
+
+	var events []models.DeliveryEvent
+
+	switch kind {
+	case "Bounce":
+		for _, r := range n.Bounce.BouncedRecipients {
+			events = append(events, models.DeliveryEvent{
+				MessageID: msgID,
+				Recipient: r.EmailAddress,
+				Status:    models.EmailStatusBounced,
+				Permanent: strings.EqualFold(n.Bounce.BounceType, "Permanent"),
+				Reason:    r.DiagnosticCode,
+			})
+		}
+	case "Complaint":
+		for _, r := range n.Complaint.ComplainedRecipients {
+			events = append(events, models.DeliveryEvent{
+				MessageID: msgID,
+				Recipient: r.EmailAddress,
+				Status:    models.EmailStatusComplained,
+				Reason:    n.Complaint.ComplaintFeedbackType,
+			})
+		}
+	case "Delivery":
+		for _, r := range n.Delivery.Recipients {
+			events = append(events, models.DeliveryEvent{
+				MessageID: msgID,
+				Recipient: r,
+				Status:    models.EmailStatusDelivered,
+			})
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,156 @@
+package bounceshttp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReporter records reported events, it fails with err if set.
+type recordingReporter struct {
+	events []models.DeliveryEvent
+	err    error
+}
+
+func (r *recordingReporter) ReportDeliveryEvent(_ context.Context, event models.DeliveryEvent) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, event)
+
+	return nil
+}
+
+func TestSESHandler(t *testing.T) {
+	signer := newTestSigner(t)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	bounce := `{
+		"notificationType": "Bounce",
+		"mail": {"messageId": "ses-id", "commonHeaders": {"messageId": "<our-id@example.com>"}},
+		"bounce": {"bounceType": "Permanent", "bouncedRecipients": [{"emailAddress": "user@example.com", "diagnosticCode": "550 no such user"}]}
+	}`
+
+	post := func(t *testing.T, h http.Handler, m snsMessage) *httptest.ResponseRecorder {
+		t.Helper()
+
+		body, err := json.Marshal(m)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhooks/email/ses", strings.NewReader(string(body))))
+
+		return w
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		reporter := &recordingReporter{}
+		h := &sesHandler{log: log, reporter: reporter, topicARN: testTopicARN, certs: signer.certs}
+
+		w := post(t, h, signer.sign(t, testNotification(bounce)))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, []models.DeliveryEvent{{
+			MessageID: "<our-id@example.com>",
+			Recipient: "user@example.com",
+			Status:    models.EmailStatusBounced,
+			Permanent: true,
+			Reason:    "550 no such user",
+		}}, reporter.events)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		reporter := &recordingReporter{}
+		h := &sesHandler{log: log, reporter: reporter, topicARN: testTopicARN, certs: signer.certs}
+
+		m := signer.sign(t, testNotification(bounce))
+		m.Message = strings.Replace(m.Message, "user@example.com", "victim@example.com", 1)
+		w := post(t, h, m)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, reporter.events)
+	})
+
+	t.Run("unexpected topic", func(t *testing.T) {
+		reporter := &recordingReporter{}
+		h := &sesHandler{log: log, reporter: reporter, topicARN: "arn:aws:sns:us-east-1:123456789012:other", certs: signer.certs}
+
+		w := post(t, h, signer.sign(t, testNotification(bounce)))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, reporter.events)
+	})
+
+	t.Run("reporter failure is retried", func(t *testing.T) {
+		h := &sesHandler{log: log, reporter: &recordingReporter{err: assert.AnError}, certs: signer.certs}
+
+		w := post(t, h, signer.sign(t, testNotification(bounce)))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		h := &sesHandler{log: log, reporter: &recordingReporter{}, certs: signer.certs}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhooks/email/ses", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestSESNotificationDeliveryEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification string
+		want         []models.DeliveryEvent
+	}{
+		{
+			name: "transient bounce by event publishing",
+			notification: `{"eventType": "Bounce", "mail": {"messageId": "ses-id"},
+				"bounce": {"bounceType": "Transient", "bouncedRecipients": [{"emailAddress": "a@example.com"}, {"emailAddress": "b@example.com"}]}}`,
+			want: []models.DeliveryEvent{
+				{MessageID: "ses-id", Recipient: "a@example.com", Status: models.EmailStatusBounced},
+				{MessageID: "ses-id", Recipient: "b@example.com", Status: models.EmailStatusBounced},
+			},
+		},
+		{
+			name: "complaint",
+			notification: `{"notificationType": "Complaint", "mail": {"messageId": "ses-id"},
+				"complaint": {"complaintFeedbackType": "abuse", "complainedRecipients": [{"emailAddress": "a@example.com"}]}}`,
+			want: []models.DeliveryEvent{
+				{MessageID: "ses-id", Recipient: "a@example.com", Status: models.EmailStatusComplained, Reason: "abuse"},
+			},
+		},
+		{
+			name:         "delivery",
+			notification: `{"notificationType": "Delivery", "mail": {"messageId": "ses-id"}, "delivery": {"recipients": ["a@example.com"]}}`,
+			want: []models.DeliveryEvent{
+				{MessageID: "ses-id", Recipient: "a@example.com", Status: models.EmailStatusDelivered},
+			},
+		},
+		{
+			name:         "unknown",
+			notification: `{"notificationType": "Open", "mail": {"messageId": "ses-id"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n sesNotification
+			require.NoError(t, json.Unmarshal([]byte(tt.notification), &n))
+
+			assert.Equal(t, tt.want, n.deliveryEvents())
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package bounceshttp
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snsHostRe matches hosts SNS signing certificates and subscribe URLs are served from.
+var snsHostRe = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+var snsClient = &http.Client{Timeout: 10 * time.Second}
+
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+// stringToSign builds the canonical string SNS signs, fields order matters.
+func (m snsMessage) stringToSign() string {
+	var sb strings.Builder
+
+	add := func(key, value string) {
+		sb.WriteString(key)
+		sb.WriteByte('\n')
+		sb.WriteString(value)
+		sb.WriteByte('\n')
+	}
+
+	add("Message", m.Message)
+	add("MessageId", m.MessageID)
+
+	if m.Type == snsTypeNotification {
+		if m.Subject != "" {
+			add("Subject", m.Subject)
+		}
+	} else {
+		add("SubscribeURL", m.SubscribeURL)
+	}
+
+	add("Timestamp", m.Timestamp)
+
+	if m.Type != snsTypeNotification {
+		add("Token", m.Token)
+	}
+
+	add("TopicArn", m.TopicArn)
+	add("Type", m.Type)
+
+	return sb.String()
+}
+
+// certCache caches SNS signing certificates by URL.
+type certCache struct {
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+func newCertCache() *certCache {
+	return &certCache{certs: make(map[string]*x509.Certificate)}
+}
+
+// verify checks signature of the SNS message.
+func (c *certCache) verify(ctx context.Context, m snsMessage) error {
+	var algo x509.SignatureAlgorithm
+
+	switch m.SignatureVersion {
+	case "1":
+		algo = x509.SHA1WithRSA
+	case "2":
+		algo = x509.SHA256WithRSA
+	default:
+		return fmt.Errorf("unsupported signature version %q", m.SignatureVersion)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	cert, err := c.cert(ctx, m.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	return cert.CheckSignature(algo, []byte(m.stringToSign()), sig)
+}
+
+func (c *certCache) cert(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	if err := validateSNSURL(certURL); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	cert, ok := c.certs[certURL]
+	c.mu.Unlock()
+
+	if ok {
+		return cert, nil
+	}
+
+	body, err := get(ctx, certURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, errors.New("invalid signing certificate")
+	}
+
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing certificate: %w", err)
+	}
+
+	c.mu.Lock()
+	c.certs[certURL] = cert
+	c.mu.Unlock()
+
+	return cert, nil
+}
+
+func confirmSubscription(ctx context.Context, subscribeURL string) error {
+	if err := validateSNSURL(subscribeURL); err != nil {
+		return err
+	}
+
+	_, err := get(ctx, subscribeURL)
+
+	return err
+}
+
+func validateSNSURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	if u.Scheme != "https" || !snsHostRe.MatchString(u.Hostname()) {
+		return fmt.Errorf("url %q is not an SNS url", rawURL)
+	}
+
+	return nil
+}
+
+func get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := snsClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+}
@@ -0,0 +1,159 @@
+package bounceshttp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCertURL  = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem"
+	testTopicARN = "arn:aws:sns:us-east-1:123456789012:ses-bounces"
+)
+
+// testSigner signs SNS messages by a self-signed certificate cached under testCertURL,
+// so certificates are never fetched.
+type testSigner struct {
+	key   *rsa.PrivateKey
+	certs *certCache
+}
+
+func newTestSigner(t *testing.T) testSigner {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	certs := newCertCache()
+	certs.certs[testCertURL] = cert
+
+	return testSigner{key: key, certs: certs}
+}
+
+func (s testSigner) sign(t *testing.T, m snsMessage) snsMessage {
+	t.Helper()
+
+	m.SignatureVersion = "2"
+	m.SigningCertURL = testCertURL
+
+	hash := sha256.Sum256([]byte(m.stringToSign()))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, hash[:])
+	require.NoError(t, err)
+	m.Signature = base64.StdEncoding.EncodeToString(sig)
+
+	return m
+}
+
+func testNotification(notification string) snsMessage {
+	return snsMessage{
+		Type:      snsTypeNotification,
+		MessageID: "sns-message",
+		TopicArn:  testTopicARN,
+		Message:   notification,
+		Timestamp: "2024-01-01T12:00:00.000Z",
+	}
+}
+
+func TestCertCacheVerify(t *testing.T) {
+	signer := newTestSigner(t)
+	ctx := context.Background()
+	signed := signer.sign(t, testNotification(`{"notificationType":"Bounce"}`))
+
+	tests := []struct {
+		name    string
+		msg     func() snsMessage
+		wantErr bool
+	}{
+		{"valid", func() snsMessage { return signed }, false},
+		{"tampered message", func() snsMessage {
+			m := signed
+			m.Message = `{"notificationType":"Delivery"}`
+			return m
+		}, true},
+		{"tampered topic", func() snsMessage {
+			m := signed
+			m.TopicArn = "arn:aws:sns:us-east-1:123456789012:other"
+			return m
+		}, true},
+		{"malformed signature", func() snsMessage {
+			m := signed
+			m.Signature = "not base64!"
+			return m
+		}, true},
+		{"unsupported signature version", func() snsMessage {
+			m := signed
+			m.SignatureVersion = "3"
+			return m
+		}, true},
+		{"certificate not from SNS", func() snsMessage {
+			m := signed
+			m.SigningCertURL = "https://example.com/cert.pem"
+			return m
+		}, true},
+		{"certificate over http", func() snsMessage {
+			m := signed
+			m.SigningCertURL = strings.Replace(testCertURL, "https://", "http://", 1)
+			return m
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := signer.certs.verify(ctx, tt.msg())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateSNSURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://sns.us-east-1.amazonaws.com/cert.pem", true},
+		{"https://sns.cn-north-1.amazonaws.com.cn/cert.pem", true},
+		{"http://sns.us-east-1.amazonaws.com/cert.pem", false},
+		{"https://sns.us-east-1.amazonaws.com.evil.com/cert.pem", false},
+		{"https://evil.com/sns.us-east-1.amazonaws.com", false},
+		{"https://s3.amazonaws.com/cert.pem", false},
+		{"://", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateSNSURL(tt.url)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
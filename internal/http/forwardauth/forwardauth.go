@@ -0,0 +1,143 @@
+package forwardauthhttp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sessionhttp "grpc-service-ref/internal/http/session"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/services/auth"
+)
+
+// Identity headers set on successful response, proxies copy them to the upstream request.
+const (
+	headerUserID    = "X-Auth-User-Id"
+	headerUserEmail = "X-Auth-User-Email"
+	headerAppID     = "X-Auth-App-Id"
+)
+
+// TokenAuthenticator verifies access tokens and checks they are not revoked.
+type TokenAuthenticator interface {
+	AuthenticateUser(ctx context.Context, token string) (jwt.Claims, error)
+}
+
+type handler struct {
+	log           *slog.Logger
+	authenticator TokenAuthenticator
+	cookieName    string
+	// csrf requires CSRF token of the session in unsafe requests authenticated by the cookie, see sessionhttp.
+	csrf bool
+}
+
+// Register registers forward auth endpoint for nginx auth_request and Traefik ForwardAuth:
+//
+//	/auth/verify -  200 with identity headers if the request has a valid access token, 401 otherwise
+//
+// Token is taken from bearer Authorization header, then from cookieName cookie.
+// Optional app_id query parameter admits only tokens issued for the app, e.g. /auth/verify?app_id=2.
+// With csrf set, requests authenticated by the cookie with unsafe original method must carry
+// sessionhttp.CSRFHeader, or they are rejected with 403.
+func Register(mux *http.ServeMux, log *slog.Logger, authenticator TokenAuthenticator, cookieName string, csrf bool) {
+	mux.Handle("/auth/verify", &handler{
+		log:           log,
+		authenticator: authenticator,
+		cookieName:    cookieName,
+		csrf:          csrf,
+	})
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const op = "forwardauthhttp.Verify"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	// Any method is accepted, since proxies may keep the method of the original request.
+	wantAppID := 0
+	if s := r.URL.Query().Get("app_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil || id <= 0 {
+			http.Error(w, "invalid app_id", http.StatusBadRequest)
+			return
+		}
+		wantAppID = id
+	}
+
+	// Elevated tokens are for a single sensitive action, they are not sessions of upstream apps.
+	// They are rejected before authentication, which would consume them.
+	token, fromCookie := h.token(r)
+	if token == "" || jwt.Elevated(token) {
+		unauthorized(w)
+		return
+	}
+
+	// Browsers send the cookie with requests of other sites, only the frontend reads the CSRF cookie.
+	if fromCookie && h.csrf && !safeMethod(originalMethod(r)) && !sessionhttp.ValidCSRF(r, h.cookieName) {
+		http.Error(w, "invalid CSRF token", http.StatusForbidden)
+		return
+	}
+
+	claims, err := h.authenticator.AuthenticateUser(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) || errors.Is(err, auth.ErrTokenConsumed) {
+			unauthorized(w)
+			return
+		}
+
+		log.Error("failed to authenticate user", sl.Err(err))
+
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	if claims.Elevated || (wantAppID != 0 && claims.AppID != wantAppID) {
+		unauthorized(w)
+		return
+	}
+
+	w.Header().Set(headerUserID, strconv.FormatInt(claims.UserID, 10))
+	w.Header().Set(headerUserEmail, claims.Email)
+	w.Header().Set(headerAppID, strconv.Itoa(claims.AppID))
+	w.WriteHeader(http.StatusOK)
+}
+
+// token returns bearer token of the request, or value of the cookie if there is none, true then.
+func (h *handler) token(r *http.Request) (string, bool) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token, false
+	}
+
+	if cookie, err := r.Cookie(h.cookieName); err == nil {
+		return cookie.Value, true
+	}
+
+	return "", false
+}
+
+// originalMethod returns method of the request the proxy checks. Traefik sends it in X-Forwarded-Method,
+// nginx in X-Original-Method set by proxy_set_header, other proxies keep the method.
+func originalMethod(r *http.Request) string {
+	if method := r.Header.Get("X-Forwarded-Method"); method != "" {
+		return method
+	}
+	if method := r.Header.Get("X-Original-Method"); method != "" {
+		return method
+	}
+
+	return r.Method
+}
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
@@ -0,0 +1,157 @@
+package headershttp
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+)
+
+const (
+	// defaultCSP forbids loading anything, responses of the HTTP server are not pages.
+	defaultCSP = "default-src 'none'; frame-ancestors 'none'"
+
+	allowedMethods = "GET, POST, DELETE, OPTIONS"
+	allowedHeaders = "Authorization, Content-Type, X-CSRF-Token"
+	// preflightMaxAge is how long browsers cache preflight responses.
+	preflightMaxAge = 10 * time.Minute
+)
+
+// AppProvider returns apps with allowed origins of their frontends.
+type AppProvider interface {
+	AppsWithOrigins(ctx context.Context) ([]models.App, error)
+}
+
+type handler struct {
+	log  *slog.Logger
+	next http.Handler
+	apps AppProvider
+	// ttl is how long origins of apps are cached.
+	ttl time.Duration
+
+	// Lock is held while loading, so concurrent requests don't load origins again.
+	mu sync.Mutex
+	// origins are content security policies of apps keyed by their allowed origins.
+	origins   map[string]string
+	expiresAt time.Time
+}
+
+// Wrap adds security headers to responses of next and answers CORS requests of origins allowed by apps,
+// see models.App.AllowedOrigins. Origins are cached for ttl, so changes of apps apply within it.
+//
+// Requests of allowed origins get CORS headers with credentials allowed and the app's content security
+// policy, if it has one. Preflight requests of other origins are rejected with 403, their other requests
+// are served without CORS headers, so browsers don't let the frontend read responses.
+//
+// It wraps the public HTTP server, where frontends get session cookies from /auth/session and call
+// /auth/verify to check them.
+// There is no HTTP/JSON gateway for gRPC yet, once there is, it's to be served behind Wrap as well.
+func Wrap(log *slog.Logger, next http.Handler, apps AppProvider, ttl time.Duration) http.Handler {
+	return &handler{
+		log:  log,
+		next: next,
+		apps: apps,
+		ttl:  ttl,
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const op = "headershttp.ServeHTTP"
+
+	header := w.Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("X-Frame-Options", "DENY")
+	header.Set("Referrer-Policy", "no-referrer")
+	header.Set("Cache-Control", "no-store")
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		header.Set("Content-Security-Policy", defaultCSP)
+		h.next.ServeHTTP(w, r)
+
+		return
+	}
+
+	// Responses differ by origin, so caches must not share them.
+	header.Add("Vary", "Origin")
+
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+	csp, allowed, err := h.allowed(r.Context(), strings.ToLower(origin))
+	if err != nil {
+		h.log.Error("failed to load allowed origins", slog.String("op", op), sl.Err(err))
+
+		http.Error(w, "internal error", http.StatusInternalServerError)
+
+		return
+	}
+
+	if !allowed {
+		header.Set("Content-Security-Policy", defaultCSP)
+
+		if preflight {
+			http.Error(w, "origin is not allowed", http.StatusForbidden)
+
+			return
+		}
+
+		h.next.ServeHTTP(w, r)
+
+		return
+	}
+
+	if csp == "" {
+		csp = defaultCSP
+	}
+	header.Set("Content-Security-Policy", csp)
+	header.Set("Access-Control-Allow-Origin", origin)
+	header.Set("Access-Control-Allow-Credentials", "true")
+
+	if preflight {
+		header.Set("Access-Control-Allow-Methods", allowedMethods)
+		header.Set("Access-Control-Allow-Headers", allowedHeaders)
+		header.Set("Access-Control-Max-Age", strconv.Itoa(int(preflightMaxAge.Seconds())))
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	h.next.ServeHTTP(w, r)
+}
+
+// allowed returns content security policy of the app allowing the origin, false if no app allows it.
+// Origins of all apps are loaded from storage once cache expires.
+func (h *handler) allowed(ctx context.Context, origin string) (string, bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	if h.origins == nil || !now.Before(h.expiresAt) {
+		apps, err := h.apps.AppsWithOrigins(ctx)
+		if err != nil {
+			return "", false, err
+		}
+
+		origins := make(map[string]string)
+		for _, app := range apps {
+			for _, o := range app.AllowedOrigins {
+				// Origin shared by apps gets policy of the first one.
+				if _, ok := origins[o]; !ok {
+					origins[o] = app.ContentSecurityPolicy
+				}
+			}
+		}
+
+		h.origins, h.expiresAt = origins, now.Add(h.ttl)
+	}
+
+	csp, ok := h.origins[origin]
+
+	return csp, ok, nil
+}
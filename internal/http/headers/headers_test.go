@@ -0,0 +1,119 @@
+package headershttp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticApps struct {
+	apps  []models.App
+	err   error
+	calls int
+}
+
+func (s *staticApps) AppsWithOrigins(context.Context) ([]models.App, error) {
+	s.calls++
+
+	return s.apps, s.err
+}
+
+const appCSP = "default-src 'self'"
+
+func newTestHandler(apps AppProvider) http.Handler {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	return Wrap(slog.New(slog.NewTextHandler(io.Discard, nil)), next, apps, time.Minute)
+}
+
+func TestWrap(t *testing.T) {
+	apps := &staticApps{apps: []models.App{
+		{ID: 1, AllowedOrigins: []string{"https://app.example.com"}, ContentSecurityPolicy: appCSP},
+		{ID: 2, AllowedOrigins: []string{"https://other.example.com", "https://app.example.com"}},
+	}}
+	h := newTestHandler(apps)
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		preflight  bool
+		wantStatus int
+		wantCORS   bool
+		wantCSP    string
+		wantNext   bool
+	}{
+		{name: "no origin", method: http.MethodGet, wantStatus: http.StatusOK, wantCSP: defaultCSP, wantNext: true},
+		{name: "allowed origin", method: http.MethodGet, origin: "https://app.example.com", wantStatus: http.StatusOK, wantCORS: true, wantCSP: appCSP, wantNext: true},
+		{name: "origins are case-insensitive", method: http.MethodGet, origin: "https://APP.example.com", wantStatus: http.StatusOK, wantCORS: true, wantCSP: appCSP, wantNext: true},
+		{name: "app without policy", method: http.MethodGet, origin: "https://other.example.com", wantStatus: http.StatusOK, wantCORS: true, wantCSP: defaultCSP, wantNext: true},
+		{name: "other origin", method: http.MethodGet, origin: "https://evil.example.com", wantStatus: http.StatusOK, wantCSP: defaultCSP, wantNext: true},
+		{name: "allowed preflight", method: http.MethodOptions, origin: "https://app.example.com", preflight: true, wantStatus: http.StatusNoContent, wantCORS: true, wantCSP: appCSP},
+		{name: "other preflight", method: http.MethodOptions, origin: "https://evil.example.com", preflight: true, wantStatus: http.StatusForbidden, wantCSP: defaultCSP},
+		{name: "options without preflight", method: http.MethodOptions, origin: "https://app.example.com", wantStatus: http.StatusOK, wantCORS: true, wantCSP: appCSP, wantNext: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/auth/verify", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			header := w.Header()
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+			assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+			assert.Equal(t, "no-store", header.Get("Cache-Control"))
+			assert.Equal(t, tt.wantCSP, header.Get("Content-Security-Policy"))
+			assert.Equal(t, tt.wantNext, w.Body.String() == "ok")
+
+			if tt.origin != "" {
+				assert.Equal(t, "Origin", header.Get("Vary"))
+			}
+
+			if !tt.wantCORS {
+				assert.Empty(t, header.Get("Access-Control-Allow-Origin"))
+
+				return
+			}
+
+			assert.Equal(t, tt.origin, header.Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "true", header.Get("Access-Control-Allow-Credentials"))
+			if tt.preflight {
+				assert.Equal(t, allowedMethods, header.Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, allowedHeaders, header.Get("Access-Control-Allow-Headers"))
+				assert.Equal(t, "600", header.Get("Access-Control-Max-Age"))
+			}
+		})
+	}
+
+	assert.Equal(t, 1, apps.calls, "origins are cached")
+}
+
+func TestWrapFailure(t *testing.T) {
+	h := newTestHandler(&staticApps{err: assert.AnError})
+
+	r := httptest.NewRequest(http.MethodGet, "/auth/verify", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,33 @@
+package jwkshttp
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+)
+
+// cacheControl lets relying services cache the key set, they fetch it again on unknown kid anyway.
+const cacheControl = "public, max-age=300"
+
+// Register registers key set of relying services verifying tokens locally, e.g. by pkg/tokenverify:
+//
+//	GET /.well-known/jwks.json - JWKS with public key of the signing key
+func Register(mux *http.ServeMux, log *slog.Logger, key *jwt.SigningKey) {
+	keys := jwt.JWKS{Keys: []jwt.JWK{key.JWK()}}
+
+	mux.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", cacheControl)
+		if err := json.NewEncoder(w).Encode(keys); err != nil {
+			log.Error("failed to write key set", slog.String("op", "jwkshttp.JWKS"), sl.Err(err))
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package opshttp
+
+import (
+	"expvar"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+)
+
+// RegisterDebug registers runtime diagnostics endpoints, available from localhost only:
+//
+//	/debug/pprof/ - pprof profiles, e.g. go tool pprof http://localhost:8083/debug/pprof/profile?seconds=30
+//	/debug/vars   - expvar variables, including memstats
+func RegisterDebug(mux *http.ServeMux, log *slog.Logger) {
+	mux.Handle("/debug/pprof/", localOnly(log, http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", localOnly(log, http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", localOnly(log, http.HandlerFunc(pprof.Profile)))
+	mux.Handle("/debug/pprof/symbol", localOnly(log, http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", localOnly(log, http.HandlerFunc(pprof.Trace)))
+	mux.Handle("/debug/vars", localOnly(log, expvar.Handler()))
+}
+
+// localOnly rejects requests not coming from loopback address.
+// Profiles expose internals of the process, so they are not served remotely even if ops port is reachable.
+func localOnly(log *slog.Logger, next http.Handler) http.Handler {
+	const op = "opshttp.localOnly"
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+			log.Warn("remote debug request rejected", slog.String("op", op), slog.String("remote_addr", r.RemoteAddr), slog.String("path", r.URL.Path))
+			http.Error(w, "debug endpoints are available from localhost only", http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,153 @@
+package opshttp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/version"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// checkTimeout limits a single readiness check, probes have their own timeouts too.
+const checkTimeout = 2 * time.Second
+
+// Pinger is a dependency readiness depends on.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Probes serves liveness, readiness and version endpoints.
+type Probes struct {
+	log *slog.Logger
+	// checks are dependencies keyed by name, e.g. storage.
+	checks       map[string]Pinger
+	logLevel     *slog.LevelVar
+	shuttingDown atomic.Bool
+}
+
+type logLevel struct {
+	Level string `json:"level"`
+}
+
+type readiness struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Register registers ops endpoints:
+//
+//	GET /healthz  - liveness, ok while the process is running
+//	GET /readyz   - readiness, ok if all checks pass and the app is not shutting down
+//	GET /version  - build info
+//	GET /metrics  - Prometheus metrics
+//	GET /loglevel - current log level
+//	PUT /loglevel - change log level until restart or config reload, body is {"level": "debug"}
+func Register(mux *http.ServeMux, log *slog.Logger, checks map[string]Pinger, level *slog.LevelVar) *Probes {
+	p := &Probes{
+		log:      log,
+		checks:   checks,
+		logLevel: level,
+	}
+
+	mux.HandleFunc("/healthz", p.healthz)
+	mux.HandleFunc("/readyz", p.readyz)
+	mux.HandleFunc("/version", p.version)
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/loglevel", p.level)
+
+	return p
+}
+
+// Shutdown makes readiness probe fail, so the instance is taken out of rotation while draining.
+func (p *Probes) Shutdown() {
+	p.shuttingDown.Store(true)
+}
+
+func (p *Probes) healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, readiness{Status: "ok"})
+}
+
+func (p *Probes) readyz(w http.ResponseWriter, r *http.Request) {
+	const op = "opshttp.readyz"
+
+	if p.shuttingDown.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, readiness{Status: "shutting down"})
+
+		return
+	}
+
+	res := readiness{
+		Status: "ok",
+		Checks: make(map[string]string, len(p.checks)),
+	}
+	code := http.StatusOK
+
+	for name, check := range p.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := check.Ping(ctx)
+		cancel()
+
+		if err != nil {
+			p.log.Warn("readiness check failed", slog.String("op", op), slog.String("check", name), sl.Err(err))
+
+			res.Checks[name] = "failed"
+			res.Status = "not ready"
+			code = http.StatusServiceUnavailable
+
+			continue
+		}
+
+		res.Checks[name] = "ok"
+	}
+
+	writeJSON(w, code, res)
+}
+
+func (p *Probes) version(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, version.Get())
+}
+
+func (p *Probes) level(w http.ResponseWriter, r *http.Request) {
+	const op = "opshttp.level"
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req logLevel
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<10)).Decode(&req); err != nil {
+			http.Error(w, "body must be {\"level\": \"<level>\"}", http.StatusBadRequest)
+
+			return
+		}
+
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(req.Level)); err != nil {
+			http.Error(w, "level must be debug, info, warn or error", http.StatusBadRequest)
+
+			return
+		}
+
+		p.logLevel.Set(l)
+		p.log.Warn("log level changed", slog.String("op", op), slog.String("level", l.String()))
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, logLevel{Level: p.logLevel.Level().String()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
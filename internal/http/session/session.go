@@ -0,0 +1,201 @@
+package sessionhttp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/services/auth"
+)
+
+// CSRFHeader carries value of the CSRF cookie in requests authenticated by the session cookie.
+const CSRFHeader = "X-CSRF-Token"
+
+// csrfCookieSuffix names the CSRF cookie after the session one, e.g. sso_token_csrf.
+const csrfCookieSuffix = "_csrf"
+
+// Authenticator verifies access tokens and ends their sessions.
+type Authenticator interface {
+	AuthenticateUser(ctx context.Context, token string) (jwt.Claims, error)
+	Logout(ctx context.Context, claims jwt.Claims) error
+}
+
+// Cookies configures cookies of sessions.
+type Cookies struct {
+	// Name is the session cookie, the CSRF cookie is named after it, see CSRFCookie.
+	Name string
+	// Domain is empty for cookies of the host only.
+	Domain   string
+	SameSite http.SameSite
+	// Insecure drops Secure attribute, so browsers send cookies over plain HTTP.
+	Insecure bool
+}
+
+// CSRFCookie returns name of the CSRF cookie of the session cookie.
+func CSRFCookie(name string) string {
+	return name + csrfCookieSuffix
+}
+
+type handler struct {
+	log           *slog.Logger
+	authenticator Authenticator
+	random        random.Randomizer
+	cookies       Cookies
+}
+
+// Register registers cookie sessions of browser frontends:
+//
+//	POST   /auth/session - sets session cookie of the bearer access token issued by Login, 200 with CSRF token
+//	DELETE /auth/session - logs out the session of the cookie and clears cookies, 204
+//
+// Session cookie is HttpOnly and carries the access token, so it expires with the token and sessions are
+// looked up in the session store by every request, e.g. /auth/verify. CSRF cookie is readable by scripts of
+// the frontend, which send its value in CSRFHeader with unsafe requests authenticated by the cookie (double
+// submit), other sites can't read it.
+func Register(mux *http.ServeMux, log *slog.Logger, authenticator Authenticator, r random.Randomizer, cookies Cookies) {
+	mux.Handle("/auth/session", &handler{
+		log:           log,
+		authenticator: authenticator,
+		random:        r,
+		cookies:       cookies,
+	})
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.login(w, r)
+	case http.MethodDelete:
+		h.logout(w, r)
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *handler) login(w http.ResponseWriter, r *http.Request) {
+	const op = "sessionhttp.Login"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	// Elevated tokens are for a single sensitive action, they are rejected before authentication consumes them.
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" || jwt.Elevated(token) {
+		unauthorized(w)
+		return
+	}
+
+	claims, err := h.authenticator.AuthenticateUser(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) || errors.Is(err, auth.ErrTokenConsumed) {
+			unauthorized(w)
+			return
+		}
+
+		log.Error("failed to authenticate user", sl.Err(err))
+
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, err := random.Secret(h.random)
+	if err != nil {
+		log.Error("failed to generate CSRF token", sl.Err(err))
+
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, h.cookie(h.cookies.Name, token, claims.ExpiresAt, true))
+	http.SetCookie(w, h.cookie(CSRFCookie(h.cookies.Name), csrfToken, claims.ExpiresAt, false))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"csrf_token": csrfToken}); err != nil {
+		log.Error("failed to write response", sl.Err(err))
+	}
+}
+
+func (h *handler) logout(w http.ResponseWriter, r *http.Request) {
+	const op = "sessionhttp.Logout"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	cookie, err := r.Cookie(h.cookies.Name)
+	if err != nil {
+		unauthorized(w)
+		return
+	}
+
+	if !ValidCSRF(r, h.cookies.Name) {
+		http.Error(w, "invalid CSRF token", http.StatusForbidden)
+		return
+	}
+
+	// Cookies of sessions that already ended are cleared too.
+	claims, err := h.authenticator.AuthenticateUser(r.Context(), cookie.Value)
+	switch {
+	case err == nil:
+		if err := h.authenticator.Logout(r.Context(), claims); err != nil {
+			log.Error("failed to log out", sl.Err(err))
+
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+	case errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) || errors.Is(err, auth.ErrTokenConsumed):
+	default:
+		log.Error("failed to authenticate user", sl.Err(err))
+
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, h.cookie(h.cookies.Name, "", time.Time{}, true))
+	http.SetCookie(w, h.cookie(CSRFCookie(h.cookies.Name), "", time.Time{}, false))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cookie returns cookie expiring at expiresAt, zero expiresAt deletes it.
+func (h *handler) cookie(name string, value string, expiresAt time.Time, httpOnly bool) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   h.cookies.Domain,
+		Expires:  expiresAt,
+		Secure:   !h.cookies.Insecure,
+		HttpOnly: httpOnly,
+		SameSite: h.cookies.SameSite,
+	}
+	if expiresAt.IsZero() {
+		cookie.MaxAge = -1
+	}
+
+	return cookie
+}
+
+// ValidCSRF reports whether CSRFHeader of the request matches the CSRF cookie of the session cookie.
+func ValidCSRF(r *http.Request, cookieName string) bool {
+	cookie, err := r.Cookie(CSRFCookie(cookieName))
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(CSRFHeader)), []byte(cookie.Value)) == 1
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
@@ -0,0 +1,165 @@
+package sessionhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/services/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCookie = "sso_token"
+
+var testExpiresAt = time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+
+// staticAuth is Authenticator with tokens known upfront, it records logged out sessions.
+type staticAuth struct {
+	tokens    map[string]jwt.Claims
+	loggedOut []string
+}
+
+func (a *staticAuth) AuthenticateUser(_ context.Context, token string) (jwt.Claims, error) {
+	if token == "failing" {
+		return jwt.Claims{}, assert.AnError
+	}
+
+	claims, ok := a.tokens[token]
+	if !ok {
+		return jwt.Claims{}, auth.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (a *staticAuth) Logout(_ context.Context, claims jwt.Claims) error {
+	a.loggedOut = append(a.loggedOut, claims.ID)
+
+	return nil
+}
+
+func newTestMux(a *staticAuth) *http.ServeMux {
+	mux := http.NewServeMux()
+	Register(mux, slog.New(slog.NewTextHandler(io.Discard, nil)), a, random.New(bytes.NewReader(bytes.Repeat([]byte{1}, 1024))), Cookies{
+		Name:     testCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return mux
+}
+
+func TestLogin(t *testing.T) {
+	a := &staticAuth{tokens: map[string]jwt.Claims{"token": {ID: "session", UserID: 1, ExpiresAt: testExpiresAt}}}
+	mux := newTestMux(a)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{"valid", "Bearer token", http.StatusOK},
+		{"no token", "", http.StatusUnauthorized},
+		{"invalid token", "Bearer unknown", http.StatusUnauthorized},
+		{"authentication failure", "Bearer failing", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/auth/session", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				assert.Empty(t, w.Result().Cookies())
+				return
+			}
+
+			var body struct {
+				CSRFToken string `json:"csrf_token"`
+			}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+
+			cookies := w.Result().Cookies()
+			require.Len(t, cookies, 2)
+
+			session, csrf := cookies[0], cookies[1]
+			assert.Equal(t, testCookie, session.Name)
+			assert.Equal(t, "token", session.Value)
+			assert.True(t, session.HttpOnly)
+			assert.True(t, session.Secure)
+			assert.Equal(t, http.SameSiteLaxMode, session.SameSite)
+			assert.Equal(t, testExpiresAt, session.Expires)
+
+			assert.Equal(t, testCookie+"_csrf", csrf.Name)
+			assert.Equal(t, body.CSRFToken, csrf.Value)
+			assert.False(t, csrf.HttpOnly, "frontend reads the CSRF token")
+			assert.True(t, csrf.Secure)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name          string
+		session       string
+		csrfCookie    string
+		csrfHeader    string
+		wantStatus    int
+		wantLoggedOut []string
+	}{
+		{name: "valid", session: "token", csrfCookie: "csrf", csrfHeader: "csrf", wantStatus: http.StatusNoContent, wantLoggedOut: []string{"session"}},
+		{name: "ended session", session: "unknown", csrfCookie: "csrf", csrfHeader: "csrf", wantStatus: http.StatusNoContent},
+		{name: "no session", csrfCookie: "csrf", csrfHeader: "csrf", wantStatus: http.StatusUnauthorized},
+		{name: "no CSRF token", session: "token", csrfCookie: "csrf", wantStatus: http.StatusForbidden},
+		{name: "wrong CSRF token", session: "token", csrfCookie: "csrf", csrfHeader: "other", wantStatus: http.StatusForbidden},
+		{name: "no CSRF cookie", session: "token", csrfHeader: "csrf", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &staticAuth{tokens: map[string]jwt.Claims{"token": {ID: "session", UserID: 1}}}
+
+			r := httptest.NewRequest(http.MethodDelete, "/auth/session", nil)
+			if tt.session != "" {
+				r.AddCookie(&http.Cookie{Name: testCookie, Value: tt.session})
+			}
+			if tt.csrfCookie != "" {
+				r.AddCookie(&http.Cookie{Name: testCookie + "_csrf", Value: tt.csrfCookie})
+			}
+			if tt.csrfHeader != "" {
+				r.Header.Set(CSRFHeader, tt.csrfHeader)
+			}
+			w := httptest.NewRecorder()
+			newTestMux(a).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantLoggedOut, a.loggedOut)
+			if tt.wantStatus == http.StatusNoContent {
+				for _, cookie := range w.Result().Cookies() {
+					assert.Equal(t, -1, cookie.MaxAge, "cookie %s is cleared", cookie.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	w := httptest.NewRecorder()
+	newTestMux(&staticAuth{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/session", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,182 @@
+package tokenreviewhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/services/auth"
+)
+
+// maxBodySize limits TokenReview requests, they carry a single token.
+const maxBodySize = 64 << 10
+
+// Groups of authenticated users, admins are in both.
+const (
+	GroupUsers  = "sso:users"
+	GroupAdmins = "sso:admins"
+)
+
+// TokenAuthenticator verifies access tokens and checks they are not revoked.
+type TokenAuthenticator interface {
+	AuthenticateUser(ctx context.Context, token string) (jwt.Claims, error)
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+// tokenReview is authentication.k8s.io TokenReview, only fields used by the webhook are declared.
+type tokenReview struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Spec       tokenReviewSpec `json:"spec"`
+}
+
+// tokenReviewResponse is TokenReview with status, spec is not sent back, so the token isn't echoed.
+type tokenReviewResponse struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Status     tokenReviewStatus `json:"status"`
+}
+
+type tokenReviewSpec struct {
+	Token     string   `json:"token"`
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+type tokenReviewStatus struct {
+	Authenticated bool      `json:"authenticated"`
+	User          *userInfo `json:"user,omitempty"`
+	Audiences     []string  `json:"audiences,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+type userInfo struct {
+	Username string   `json:"username"`
+	UID      string   `json:"uid"`
+	Groups   []string `json:"groups"`
+}
+
+type handler struct {
+	log           *slog.Logger
+	authenticator TokenAuthenticator
+	appID         int
+}
+
+// Register registers Kubernetes authentication webhook:
+//
+//	POST /k8s/tokenreview - TokenReview of authentication.k8s.io/v1 or v1beta1
+//
+// Users are authenticated by access tokens issued for appID, tokens of other apps are rejected.
+// If the API server requests audiences, the token must have one of them in its aud claim.
+// Username is email of the user, groups are GroupUsers and GroupAdmins for admins.
+func Register(mux *http.ServeMux, log *slog.Logger, authenticator TokenAuthenticator, appID int) {
+	mux.Handle("/k8s/tokenreview", &handler{
+		log:           log,
+		authenticator: authenticator,
+		appID:         appID,
+	})
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const op = "tokenreviewhttp.TokenReview"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var review tokenReview
+	if err := json.Unmarshal(body, &review); err != nil || review.Kind != "TokenReview" {
+		http.Error(w, "body must be TokenReview", http.StatusBadRequest)
+		return
+	}
+
+	resp := tokenReviewResponse{
+		APIVersion: review.APIVersion,
+		Kind:       review.Kind,
+		Status:     h.review(r.Context(), log, review.Spec),
+	}
+
+	// Status is not authenticated on internal errors too, so the API server denies the request.
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to write response", sl.Err(err))
+	}
+}
+
+func (h *handler) review(ctx context.Context, log *slog.Logger, spec tokenReviewSpec) tokenReviewStatus {
+	if spec.Token == "" {
+		return tokenReviewStatus{Error: "token is required"}
+	}
+
+	// Elevated tokens are for a single sensitive action, they are not cluster credentials.
+	// They are rejected before authentication, which would consume them.
+	if jwt.Elevated(spec.Token) {
+		return tokenReviewStatus{Error: "invalid token"}
+	}
+
+	claims, err := h.authenticator.AuthenticateUser(ctx, spec.Token)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) || errors.Is(err, auth.ErrTokenConsumed) {
+			return tokenReviewStatus{Error: "invalid token"}
+		}
+
+		log.Error("failed to authenticate user", sl.Err(err))
+
+		return tokenReviewStatus{Error: "failed to authenticate user"}
+	}
+
+	if claims.AppID != h.appID || claims.Elevated {
+		return tokenReviewStatus{Error: "invalid token"}
+	}
+
+	// Requested audiences are answered with those the token is issued for, the API server rejects
+	// the token unless one of them is its own.
+	var audiences []string
+	for _, audience := range spec.Audiences {
+		if slices.Contains(claims.Audience, audience) {
+			audiences = append(audiences, audience)
+		}
+	}
+	if len(spec.Audiences) > 0 && len(audiences) == 0 {
+		return tokenReviewStatus{Error: "token is not issued for the requested audiences"}
+	}
+
+	isAdmin, err := h.authenticator.IsAdmin(ctx, claims.UserID)
+	if err != nil {
+		log.Error("failed to check if user is admin", sl.Err(err))
+
+		return tokenReviewStatus{Error: "failed to authenticate user"}
+	}
+
+	groups := []string{GroupUsers}
+	if isAdmin {
+		groups = append(groups, GroupAdmins)
+	}
+
+	return tokenReviewStatus{
+		Authenticated: true,
+		User: &userInfo{
+			Username: claims.Email,
+			UID:      strconv.FormatInt(claims.UserID, 10),
+			Groups:   groups,
+		},
+		Audiences: audiences,
+	}
+}
@@ -0,0 +1,52 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells current time. Services take it instead of calling time.Now, so tests can control time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is Clock of the system time.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is Clock which stands still until it's set or advanced, e.g. to expire codes in tests.
+// It's safe for concurrent use.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Set sets current time.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = now
+}
+
+// Advance moves current time by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+}
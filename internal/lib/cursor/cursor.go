@@ -0,0 +1,78 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// Page tokens of list RPCs are keyset cursors: token holds the sort key of the last returned item
+// and the next page starts right after it, so pages don't shift when items are inserted and deep pages
+// are as cheap as the first one. Tokens are opaque to clients, but not secret: a forged token only moves
+// the start of the page, filters of the list still apply.
+
+// version is changed when format of tokens changes, tokens of other versions are rejected.
+const version = 1
+
+var ErrInvalidToken = errors.New("invalid page token")
+
+type token struct {
+	Version int             `json:"v"`
+	List    string          `json:"l"`
+	Key     json.RawMessage `json:"k"`
+}
+
+// Encode returns token of the position after key in the list, key is a JSON-serializable sort key.
+func Encode(list string, key any) (string, error) {
+	k, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(token{Version: version, List: list, Key: k})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Decode decodes key of the token issued by Encode for the same list into key.
+func Decode(list string, s string, key any) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	var t token
+	if err := json.Unmarshal(b, &t); err != nil {
+		return ErrInvalidToken
+	}
+
+	if t.Version != version || t.List != list {
+		return ErrInvalidToken
+	}
+
+	if err := json.Unmarshal(t.Key, key); err != nil {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// Page trims items fetched with limit+1 to limit, and returns token of the next page,
+// which is empty if there are no more items.
+func Page[T any](list string, items []T, limit int, key func(T) any) ([]T, string, error) {
+	if len(items) <= limit {
+		return items, "", nil
+	}
+
+	items = items[:limit]
+
+	next, err := Encode(list, key(items[limit-1]))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return items, next, nil
+}
@@ -0,0 +1,73 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	At time.Time `json:"t"`
+	ID string    `json:"id"`
+}
+
+func TestEncodeDecode(t *testing.T) {
+	want := testKey{At: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), ID: "session-1"}
+
+	s, err := Encode("sessions", want)
+	require.NoError(t, err)
+
+	var got testKey
+	require.NoError(t, Decode("sessions", s, &got))
+	assert.Equal(t, want, got)
+}
+
+func TestDecodeTampered(t *testing.T) {
+	valid, err := Encode("users", int64(42))
+	require.NoError(t, err)
+
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "not a token!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"v":1,"l":"users","k":42}`))},
+		{"not JSON", raw("users:42")},
+		{"another list", raw(`{"v":1,"l":"apps","k":42}`)},
+		{"another version", raw(`{"v":2,"l":"users","k":42}`)},
+		{"no version", raw(`{"l":"users","k":42}`)},
+		{"key of another type", raw(`{"v":1,"l":"users","k":"42"}`)},
+		{"no key", raw(`{"v":1,"l":"users"}`)},
+		{"truncated", valid[:len(valid)-2]},
+		{"empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key int64
+			assert.ErrorIs(t, Decode("users", tt.token, &key), ErrInvalidToken)
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	id := func(i int) any { return i }
+
+	items, next, err := Page("users", []int{1, 2, 3}, 2, id)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+
+	var after int
+	require.NoError(t, Decode("users", next, &after))
+	assert.Equal(t, 2, after, "next page starts after the last returned item")
+
+	items, next, err = Page("users", []int{1, 2}, 2, id)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+	assert.Empty(t, next, "last page has no next page")
+}
@@ -0,0 +1,92 @@
+package dkim
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	msgauth "github.com/emersion/go-msgauth/dkim"
+)
+
+// headerKeys are headers covered by the signature, see RFC 6376 section 5.4.1.
+var headerKeys = []string{
+	"From", "Reply-To", "Subject", "Date", "To", "Cc",
+	"Message-Id", "Mime-Version", "Content-Type",
+}
+
+// Signer signs outgoing emails with DKIM signature.
+type Signer struct {
+	options *msgauth.SignOptions
+}
+
+// New creates Signer with PEM encoded RSA or Ed25519 private key read from keyPath.
+func New(domain string, selector string, keyPath string) (*Signer, error) {
+	const op = "dkim.New"
+
+	if domain == "" || selector == "" {
+		return nil, fmt.Errorf("%s: domain and selector are required", op)
+	}
+
+	raw, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := parsePrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Signer{
+		options: &msgauth.SignOptions{
+			Domain:                 domain,
+			Selector:               selector,
+			Signer:                 key,
+			HeaderCanonicalization: msgauth.CanonicalizationRelaxed,
+			BodyCanonicalization:   msgauth.CanonicalizationRelaxed,
+			HeaderKeys:             headerKeys,
+		},
+	}, nil
+}
+
+// Sign returns message with DKIM-Signature header prepended.
+func (s *Signer) Sign(msg []byte) ([]byte, error) {
+	const op = "dkim.Sign"
+
+	var signed bytes.Buffer
+	if err := msgauth.Sign(&signed, bytes.NewReader(msg), s.options); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return signed.Bytes(), nil
+}
+
+func parsePrivateKey(raw []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
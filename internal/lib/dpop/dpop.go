@@ -0,0 +1,107 @@
+package dpop
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Method is htm claim of proofs, RPCs are HTTP/2 POST requests.
+const Method = "POST"
+
+// MaxAge is how far iat of proofs may be from now, it covers clock skew of clients.
+const MaxAge = 5 * time.Minute
+
+var ErrInvalidProof = errors.New("invalid dpop proof")
+
+// jwk is public key of the proof, only P-256 EC keys are supported.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	HTM string `json:"htm"`
+	HTU string `json:"htu"`
+}
+
+// Verify verifies DPoP-style proof of possession of the key, see RFC 9449: ES256 JWT of typ dpop+jwt signed
+// by the key in its jwk header, with htm POST and htu the full method of the RPC. It returns thumbprint of the key,
+// see RFC 7638. jti isn't tracked, so a proof may be replayed within MaxAge, callers bind tokens to the key,
+// not authenticate requests by it.
+func Verify(proof string, fullMethod string, now time.Time) (string, error) {
+	const op = "dpop.Verify"
+
+	var key jwk
+	parsed, err := jwt.ParseWithClaims(proof, &claims{}, func(token *jwt.Token) (interface{}, error) {
+		if typ, _ := token.Header["typ"].(string); typ != "dpop+jwt" {
+			return nil, errors.New("typ must be dpop+jwt")
+		}
+
+		raw, err := json.Marshal(token.Header["jwk"])
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return nil, err
+		}
+
+		return key.publicKey()
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}), jwt.WithTimeFunc(func() time.Time { return now }))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w: %w", op, ErrInvalidProof, err)
+	}
+
+	c, ok := parsed.Claims.(*claims)
+	if !ok || c.HTM != Method || c.HTU != fullMethod {
+		return "", fmt.Errorf("%s: %w: htm or htu mismatch", op, ErrInvalidProof)
+	}
+
+	if c.IssuedAt == nil || c.IssuedAt.Sub(now).Abs() > MaxAge {
+		return "", fmt.Errorf("%s: %w: iat is missing or out of range", op, ErrInvalidProof)
+	}
+
+	return key.thumbprint(), nil
+}
+
+func (k jwk) publicKey() (*ecdsa.PublicKey, error) {
+	if k.Kty != "EC" || k.Crv != "P-256" {
+		return nil, errors.New("jwk must be P-256 EC key")
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !key.Curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("jwk is not on the curve")
+	}
+
+	return key, nil
+}
+
+// thumbprint is base64url sha256 of the required members of the key in lexicographic order.
+func (k jwk) thumbprint() string {
+	canonical := fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, k.Crv, k.Kty, k.X, k.Y)
+	sum := sha256.Sum256([]byte(canonical))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,131 @@
+package dpop
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMethod = "/auth.AuthV2/Refresh"
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
+
+func publicJWK(key *ecdsa.PrivateKey) map[string]any {
+	return map[string]any{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+// sign returns proof signed by the key, header and claims are changed by edit.
+func sign(t *testing.T, key *ecdsa.PrivateKey, edit func(header map[string]any, claims jwt.MapClaims)) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"jti": "proof-1",
+		"htm": Method,
+		"htu": testMethod,
+		"iat": testNow.Unix(),
+	})
+	token.Header["typ"] = "dpop+jwt"
+	token.Header["jwk"] = publicJWK(key)
+
+	if edit != nil {
+		edit(token.Header, token.Claims.(jwt.MapClaims))
+	}
+
+	proof, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return proof
+}
+
+func TestVerify(t *testing.T) {
+	key := newKey(t)
+
+	// Thumbprint is computed from JSON of the required members, which encoding/json sorts.
+	canonical, err := json.Marshal(publicJWK(key))
+	require.NoError(t, err)
+	sum := sha256.Sum256(canonical)
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	got, err := Verify(sign(t, key, nil), testMethod, testNow)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = Verify(sign(t, key, func(_ map[string]any, c jwt.MapClaims) { c["iat"] = testNow.Add(-time.Minute).Unix() }), testMethod, testNow)
+	require.NoError(t, err)
+	assert.Equal(t, want, got, "thumbprint doesn't depend on the proof")
+
+	other, err := Verify(sign(t, newKey(t), nil), testMethod, testNow)
+	require.NoError(t, err)
+	assert.NotEqual(t, want, other)
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	key := newKey(t)
+	other := newKey(t)
+
+	tests := []struct {
+		name  string
+		proof string
+	}{
+		{"not a JWT", "not a proof"},
+		{"another method", sign(t, key, func(_ map[string]any, c jwt.MapClaims) { c["htu"] = "/auth.AuthV2/Login" })},
+		{"not POST", sign(t, key, func(_ map[string]any, c jwt.MapClaims) { c["htm"] = "GET" })},
+		{"no iat", sign(t, key, func(_ map[string]any, c jwt.MapClaims) { delete(c, "iat") })},
+		{"old iat", sign(t, key, func(_ map[string]any, c jwt.MapClaims) { c["iat"] = testNow.Add(-MaxAge - time.Second).Unix() })},
+		{"future iat", sign(t, key, func(_ map[string]any, c jwt.MapClaims) { c["iat"] = testNow.Add(MaxAge + time.Second).Unix() })},
+		{"expired", sign(t, key, func(_ map[string]any, c jwt.MapClaims) { c["exp"] = testNow.Add(-time.Second).Unix() })},
+		{"another typ", sign(t, key, func(h map[string]any, _ jwt.MapClaims) { h["typ"] = "JWT" })},
+		{"no jwk", sign(t, key, func(h map[string]any, _ jwt.MapClaims) { delete(h, "jwk") })},
+		{"signed by another key", sign(t, key, func(h map[string]any, _ jwt.MapClaims) { h["jwk"] = publicJWK(other) })},
+		{"RSA jwk", sign(t, key, func(h map[string]any, _ jwt.MapClaims) {
+			h["jwk"] = map[string]any{"kty": "RSA", "n": "AQAB", "e": "AQAB"}
+		})},
+		{"jwk not on the curve", sign(t, key, func(h map[string]any, _ jwt.MapClaims) {
+			jwk := publicJWK(key)
+			jwk["y"] = jwk["x"]
+			h["jwk"] = jwk
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(tt.proof, testMethod, testNow)
+			assert.ErrorIs(t, err, ErrInvalidProof)
+		})
+	}
+
+	t.Run("another algorithm", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"htm": Method, "htu": testMethod, "iat": testNow.Unix()})
+		token.Header["typ"] = "dpop+jwt"
+		token.Header["jwk"] = publicJWK(key)
+
+		proof, err := token.SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = Verify(proof, testMethod, testNow)
+		assert.ErrorIs(t, err, ErrInvalidProof)
+	})
+}
@@ -0,0 +1,153 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// KeySize is size of the master key, it's an AES-256 key.
+const KeySize = 32
+
+// version is the first byte of ciphertexts, so the format can change without breaking stored values.
+const version byte = 1
+
+var (
+	ErrInvalidKey        = errors.New("master key must be base64-encoded 32 bytes")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// Cipher encrypts fields with envelope encryption: every value is encrypted by its own random data key,
+// which is stored with the value encrypted by the master key. Values are looked up by blind index,
+// a keyed hash, since ciphertexts of the same value differ.
+type Cipher struct {
+	master cipher.AEAD
+	// indexKey is derived from the master key, so a single secret is configured.
+	indexKey []byte
+}
+
+// New returns Cipher with base64-encoded master key, usually taken from a secret manager.
+func New(key string) (*Cipher, error) {
+	const op = "fieldcrypt.New"
+
+	masterKey, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(masterKey) != KeySize {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidKey)
+	}
+
+	master, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mac := hmac.New(sha256.New, masterKey)
+	mac.Write([]byte("sso blind index"))
+
+	return &Cipher{
+		master:   master,
+		indexKey: mac.Sum(nil),
+	}, nil
+}
+
+// Encrypt returns ciphertext of the value: version, data key sealed by master key, value sealed by data key.
+func (c *Cipher) Encrypt(value string) ([]byte, error) {
+	const op = "fieldcrypt.Encrypt"
+
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out := []byte{version}
+
+	if out, err = seal(c.master, out, dataKey); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if out, err = seal(data, out, []byte(value)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+// Decrypt returns value of ciphertext made by Encrypt.
+func (c *Cipher) Decrypt(ciphertext []byte) (string, error) {
+	const op = "fieldcrypt.Decrypt"
+
+	if len(ciphertext) == 0 || ciphertext[0] != version {
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidCiphertext)
+	}
+
+	dataKey, rest, err := open(c.master, ciphertext[1:], KeySize)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	value, _, err := open(data, rest, len(rest)-data.NonceSize()-data.Overhead())
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return string(value), nil
+}
+
+// Index returns blind index of the value, equal values get equal indexes.
+func (c *Cipher) Index(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+
+	return "bi:" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal appends nonce and sealed plaintext to out.
+func seal(aead cipher.AEAD, out []byte, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, plaintext, nil), nil
+}
+
+// open opens nonce and sealed plaintext of the given size at the start of in, and returns the rest of in.
+func open(aead cipher.AEAD, in []byte, size int) (plaintext []byte, rest []byte, err error) {
+	n := aead.NonceSize() + size + aead.Overhead()
+	if size < 0 || len(in) < n {
+		return nil, nil, ErrInvalidCiphertext
+	}
+
+	plaintext, err = aead.Open(nil, in[:aead.NonceSize()], in[aead.NonceSize():n], nil)
+	if err != nil {
+		return nil, nil, ErrInvalidCiphertext
+	}
+
+	return plaintext, in[n:], nil
+}
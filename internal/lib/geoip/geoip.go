@@ -0,0 +1,101 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+
+	"grpc-service-ref/internal/domain/models"
+)
+
+// DB resolves locations of IP addresses by a MaxMind DB file, e.g. GeoLite2 City or Country.
+// The file is loaded to memory once, so lookups are safe for concurrent use.
+type DB struct {
+	reader *reader
+}
+
+// Open loads the MaxMind DB file.
+func Open(path string) (*DB, error) {
+	const op = "geoip.Open"
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reader, err := newReader(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &DB{reader: reader}, nil
+}
+
+// Lookup returns location of the IP address, false if the address is invalid or not in the database.
+// Country databases have no city and coordinates. Nil DB resolves no addresses, so GeoIP may be disabled.
+func (db *DB) Lookup(ip string) (models.Location, bool) {
+	if db == nil {
+		return models.Location{}, false
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return models.Location{}, false
+	}
+
+	record, err := db.reader.lookup(addr)
+	if err != nil {
+		return models.Location{}, false
+	}
+
+	m, ok := record.(map[string]any)
+	if !ok {
+		return models.Location{}, false
+	}
+
+	var loc models.Location
+
+	loc.Country, _ = path(m, "country", "iso_code").(string)
+	loc.City, _ = path(m, "city", "names", "en").(string)
+
+	lat, latOK := path(m, "location", "latitude").(float64)
+	lon, lonOK := path(m, "location", "longitude").(float64)
+	if latOK && lonOK {
+		loc.Latitude, loc.Longitude, loc.HasCoordinates = lat, lon, true
+	}
+
+	if loc == (models.Location{}) {
+		return models.Location{}, false
+	}
+
+	return loc, true
+}
+
+// path returns value nested in maps by the keys, nil if there is none.
+func path(m map[string]any, keys ...string) any {
+	var v any = m
+	for _, key := range keys {
+		nested, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = nested[key]
+	}
+
+	return v
+}
+
+type ctxKey struct{}
+
+// NewContext returns context carrying location of the client.
+func NewContext(ctx context.Context, loc models.Location) context.Context {
+	return context.WithValue(ctx, ctxKey{}, loc)
+}
+
+// FromContext returns location of the client, false if it wasn't resolved.
+func FromContext(ctx context.Context) (models.Location, bool) {
+	loc, ok := ctx.Value(ctxKey{}).(models.Location)
+
+	return loc, ok
+}
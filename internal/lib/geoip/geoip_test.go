@@ -0,0 +1,249 @@
+package geoip
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDB is a MaxMind DB written by tests: networks are mapped to records, the search tree has 24-bit records.
+type testDB struct {
+	ipVersion int
+	networks  map[string]map[string]any
+}
+
+// bytes returns the DB file. Networks must not overlap.
+func (db testDB) bytes(t *testing.T) []byte {
+	t.Helper()
+
+	var data bytes.Buffer
+	offsets := make(map[string]uint)
+
+	// Networks are written in order, so files are the same on every run.
+	prefixes := make([]string, 0, len(db.networks))
+	for prefix := range db.networks {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, prefix := range prefixes {
+		offsets[prefix] = uint(data.Len())
+		encode(&data, db.networks[prefix])
+	}
+
+	const empty = -1
+	nodes := [][2]int{{empty, empty}}
+	leaves := make(map[[2]int]string)
+
+	for _, prefix := range prefixes {
+		p := netip.MustParsePrefix(prefix)
+		bits, n := p.Addr().AsSlice(), p.Bits()
+		if db.ipVersion == 6 && p.Addr().Is4() {
+			// IPv4 networks of IPv6 trees are under ::/96.
+			bits, n = append(make([]byte, 12), bits...), n+96
+		}
+
+		node := 0
+		for i := 0; i < n; i++ {
+			bit := int(bits[i/8]>>(7-i%8)) & 1
+			if i == n-1 {
+				leaves[[2]int{node, bit}] = prefix
+
+				break
+			}
+
+			if nodes[node][bit] == empty {
+				nodes = append(nodes, [2]int{empty, empty})
+				nodes[node][bit] = len(nodes) - 1
+			}
+			node = nodes[node][bit]
+		}
+	}
+
+	nodeCount := uint(len(nodes))
+
+	var buf bytes.Buffer
+	for i, node := range nodes {
+		for bit, next := range node {
+			record := nodeCount
+			if prefix, ok := leaves[[2]int{i, bit}]; ok {
+				record = nodeCount + dataSectionSeparatorSize + offsets[prefix]
+			} else if next != empty {
+				record = uint(next)
+			}
+
+			buf.Write([]byte{byte(record >> 16), byte(record >> 8), byte(record)})
+		}
+	}
+
+	buf.Write(make([]byte, dataSectionSeparatorSize))
+	buf.Write(data.Bytes())
+	buf.Write(metadataMarker)
+	encode(&buf, map[string]any{
+		"node_count":  uint32(nodeCount),
+		"record_size": uint16(24),
+		"ip_version":  uint16(db.ipVersion),
+	})
+
+	return buf.Bytes()
+}
+
+// encode writes the value as a field of the data section, only types used by tests are supported.
+func encode(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf.WriteByte(typeMap<<5 | byte(len(v)))
+		for _, key := range keys {
+			encode(buf, key)
+			encode(buf, v[key])
+		}
+	case string:
+		buf.WriteByte(typeString<<5 | byte(len(v)))
+		buf.WriteString(v)
+	case float64:
+		buf.WriteByte(typeDouble<<5 | 8)
+		_ = binary.Write(buf, binary.BigEndian, math.Float64bits(v))
+	case uint16:
+		buf.WriteByte(typeUint16<<5 | 2)
+		_ = binary.Write(buf, binary.BigEndian, v)
+	case uint32:
+		buf.WriteByte(typeUint32<<5 | 4)
+		_ = binary.Write(buf, binary.BigEndian, v)
+	default:
+		panic("unsupported type")
+	}
+}
+
+var testNetworks = map[string]map[string]any{
+	"203.0.113.0/24": {
+		"country":  map[string]any{"iso_code": "DE"},
+		"city":     map[string]any{"names": map[string]any{"en": "Berlin"}},
+		"location": map[string]any{"latitude": 52.52, "longitude": 13.405},
+	},
+	"198.51.100.0/24": {
+		"country": map[string]any{"iso_code": "FR"},
+	},
+	"192.0.2.0/24": {
+		"registered_country": map[string]any{"iso_code": "US"},
+	},
+}
+
+func openTestDB(t *testing.T, db testDB) *DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	require.NoError(t, os.WriteFile(path, db.bytes(t), 0o600))
+
+	geo, err := Open(path)
+	require.NoError(t, err)
+
+	return geo
+}
+
+func TestLookup(t *testing.T) {
+	berlin := models.Location{Country: "DE", City: "Berlin", Latitude: 52.52, Longitude: 13.405, HasCoordinates: true}
+
+	tests := []struct {
+		name   string
+		ip     string
+		want   models.Location
+		wantOK bool
+	}{
+		{"city", "203.0.113.7", berlin, true},
+		{"IPv4-mapped IPv6", "::ffff:203.0.113.7", berlin, true},
+		{"country", "198.51.100.1", models.Location{Country: "FR"}, true},
+		{"record without location", "192.0.2.1", models.Location{}, false},
+		{"not in database", "10.0.0.1", models.Location{}, false},
+		{"invalid", "not an ip", models.Location{}, false},
+	}
+
+	for _, ipVersion := range []int{4, 6} {
+		geo := openTestDB(t, testDB{ipVersion: ipVersion, networks: testNetworks})
+
+		t.Run(fmt.Sprintf("IPv%d tree", ipVersion), func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					got, ok := geo.Lookup(tt.ip)
+					assert.Equal(t, tt.wantOK, ok)
+					assert.Equal(t, tt.want, got)
+				})
+			}
+		})
+	}
+}
+
+func TestLookupIPv6(t *testing.T) {
+	networks := map[string]map[string]any{
+		"2001:db8::/32": {"country": map[string]any{"iso_code": "NL"}},
+	}
+
+	geo := openTestDB(t, testDB{ipVersion: 6, networks: networks})
+	got, ok := geo.Lookup("2001:db8::1")
+	assert.True(t, ok)
+	assert.Equal(t, "NL", got.Country)
+
+	_, ok = openTestDB(t, testDB{ipVersion: 4, networks: testNetworks}).Lookup("2001:db8::1")
+	assert.False(t, ok, "IPv6 addresses aren't in IPv4 trees")
+}
+
+func TestLookupDisabled(t *testing.T) {
+	var geo *DB
+
+	_, ok := geo.Lookup("203.0.113.7")
+	assert.False(t, ok)
+}
+
+func TestOpenInvalid(t *testing.T) {
+	valid := testDB{ipVersion: 4, networks: testNetworks}.bytes(t)
+
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{"empty", nil},
+		{"no metadata", []byte("not a database")},
+		{"truncated tree", valid[len(valid)/2:]},
+		{"unsupported record size", bytes.Replace(valid, []byte("record_size\xA2\x00\x18"), []byte("record_size\xA2\x00\x10"), 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.mmdb")
+			require.NoError(t, os.WriteFile(path, tt.file, 0o600))
+
+			_, err := Open(path)
+			assert.ErrorIs(t, err, errInvalidDB)
+		})
+	}
+
+	_, err := Open(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	loc := models.Location{Country: "DE"}
+	got, ok := FromContext(NewContext(context.Background(), loc))
+	assert.True(t, ok)
+	assert.Equal(t, loc, got)
+}
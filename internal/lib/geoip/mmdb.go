@@ -0,0 +1,377 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+)
+
+// MaxMind DB format, see https://maxmind.github.io/MaxMind-DB/.
+
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// metadataMaxSize bounds the tail of the file searched for metadata.
+const metadataMaxSize = 128 * 1024
+
+// dataSectionSeparatorSize is size of zeros between the search tree and the data section.
+const dataSectionSeparatorSize = 16
+
+// maxDepth bounds nesting of decoded data, so malformed files can't exhaust the stack.
+const maxDepth = 32
+
+var errInvalidDB = errors.New("invalid MaxMind DB")
+
+// Types of the data section fields.
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// reader looks up data of IP addresses in a MaxMind DB file loaded to memory.
+type reader struct {
+	buf        []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	// ipv4Start is the node IPv4 addresses are looked up from in IPv6 trees, i.e. the node of ::/96.
+	ipv4Start uint
+}
+
+func newReader(buf []byte) (*reader, error) {
+	start := 0
+	if len(buf) > metadataMaxSize {
+		start = len(buf) - metadataMaxSize
+	}
+
+	i := bytes.LastIndex(buf[start:], metadataMarker)
+	if i == -1 {
+		return nil, fmt.Errorf("%w: metadata not found", errInvalidDB)
+	}
+
+	metaStart := start + i + len(metadataMarker)
+	meta, _, err := decoder{buf: buf[metaStart:]}.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: metadata: %w", errInvalidDB, err)
+	}
+
+	metadata, ok := meta.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", errInvalidDB)
+	}
+
+	r := &reader{
+		buf:        buf,
+		nodeCount:  uintField(metadata, "node_count"),
+		recordSize: uintField(metadata, "record_size"),
+		ipVersion:  uintField(metadata, "ip_version"),
+	}
+
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", errInvalidDB, r.recordSize)
+	}
+	if r.ipVersion != 4 && r.ipVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported IP version %d", errInvalidDB, r.ipVersion)
+	}
+
+	treeSize := r.nodeCount * r.recordSize / 4
+	dataStart := treeSize + dataSectionSeparatorSize
+	if dataStart > uint(start+i) {
+		return nil, fmt.Errorf("%w: search tree exceeds the file", errInvalidDB)
+	}
+	r.data = buf[dataStart : start+i]
+
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for j := 0; j < 96 && node < r.nodeCount; j++ {
+			node = r.record(node, 0)
+		}
+		r.ipv4Start = node
+	}
+
+	return r, nil
+}
+
+// lookup returns data of the network containing the address, nil if there is none.
+func (r *reader) lookup(addr netip.Addr) (any, error) {
+	addr = addr.Unmap()
+
+	node, bits := uint(0), addr.AsSlice()
+	if addr.Is4() {
+		if r.ipVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else if r.ipVersion == 4 {
+		return nil, nil
+	}
+
+	for i := 0; i < len(bits)*8 && node < r.nodeCount; i++ {
+		bit := uint(bits[i/8]>>(7-i%8)) & 1
+		node = r.record(node, bit)
+	}
+
+	if node <= r.nodeCount {
+		// node_count itself means no data, nodes below it mean the tree is deeper than the address.
+		return nil, nil
+	}
+
+	offset := node - r.nodeCount - dataSectionSeparatorSize
+	if offset >= uint(len(r.data)) {
+		return nil, fmt.Errorf("%w: data pointer out of range", errInvalidDB)
+	}
+
+	value, _, err := decoder{buf: r.data}.decode(offset, 0)
+
+	return value, err
+}
+
+// record returns left (bit 0) or right (bit 1) record of the node.
+func (r *reader) record(node uint, bit uint) uint {
+	nodeSize := r.recordSize / 4
+	b := r.buf[node*nodeSize : (node+1)*nodeSize]
+
+	switch r.recordSize {
+	case 24:
+		b = b[bit*3:]
+
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+// decoder decodes fields of the data section into maps, slices, strings, numbers and booleans.
+type decoder struct {
+	buf []byte
+}
+
+// decode returns value of the field at offset and offset of the next field.
+func (d decoder) decode(offset uint, depth int) (any, uint, error) {
+	if depth > maxDepth {
+		return nil, 0, fmt.Errorf("%w: data is nested too deep", errInvalidDB)
+	}
+
+	typ, size, offset, err := d.controlByte(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == typePointer {
+		pointer, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		value, _, err := d.decode(pointer, depth+1)
+
+		return value, next, err
+	}
+
+	switch typ {
+	case typeMap:
+		m := make(map[string]any, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("%w: map key is not a string", errInvalidDB)
+			}
+
+			m[k], offset, err = d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+
+		return m, offset, nil
+	case typeArray:
+		a := make([]any, 0, min(size, 1024))
+		for i := uint(0); i < size; i++ {
+			var (
+				value any
+				err   error
+			)
+			value, offset, err = d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			a = append(a, value)
+		}
+
+		return a, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	b, err := d.bytes(offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	next := offset + size
+
+	switch typ {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return bytes.Clone(b), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("%w: double of size %d", errInvalidDB, size)
+		}
+
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("%w: float of size %d", errInvalidDB, size)
+		}
+
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("%w: unsigned integer of size %d", errInvalidDB, size)
+		}
+
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+
+		return v, next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("%w: int32 of size %d", errInvalidDB, size)
+		}
+
+		var v uint32
+		for _, c := range b {
+			v = v<<8 | uint32(c)
+		}
+
+		return int64(int32(v)), next, nil
+	case typeUint128:
+		// No fields of city and country databases are uint128, they are kept as bytes.
+		return bytes.Clone(b), next, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: unexpected data type %d", errInvalidDB, typ)
+	}
+}
+
+// controlByte returns type and size of the field at offset and offset of its payload.
+func (d decoder) controlByte(offset uint) (int, uint, uint, error) {
+	b, err := d.bytes(offset, 1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	offset++
+
+	ctrl := b[0]
+	typ := int(ctrl >> 5)
+
+	if typ == typeExtended {
+		b, err := d.bytes(offset, 1)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		offset++
+
+		typ = 7 + int(b[0])
+		if typ < typeInt32 {
+			return 0, 0, 0, fmt.Errorf("%w: invalid extended type %d", errInvalidDB, typ)
+		}
+	}
+
+	size := uint(ctrl & 0x1F)
+	if typ == typePointer {
+		// Pointers keep size bits to decode the pointer itself.
+		return typ, size, offset, nil
+	}
+
+	if size < 29 {
+		return typ, size, offset, nil
+	}
+
+	n := size - 28
+	b, err = d.bytes(offset, n)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	offset += n
+
+	switch size {
+	case 29:
+		size = 29 + uint(b[0])
+	case 30:
+		size = 285 + (uint(b[0])<<8 | uint(b[1]))
+	default:
+		size = 65821 + (uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]))
+	}
+
+	return typ, size, offset, nil
+}
+
+// pointer returns offset in the data section the pointer points to and offset of the field after the pointer.
+func (d decoder) pointer(size uint, offset uint) (uint, uint, error) {
+	ss, vvv := (size>>3)&0x3, size&0x7
+
+	b, err := d.bytes(offset, ss+1)
+	if err != nil {
+		return 0, 0, err
+	}
+	next := offset + ss + 1
+
+	var pointer uint
+	switch ss {
+	case 0:
+		pointer = vvv<<8 | uint(b[0])
+	case 1:
+		pointer = (vvv<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 2:
+		pointer = (vvv<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		pointer = uint(binary.BigEndian.Uint32(b))
+	}
+
+	return pointer, next, nil
+}
+
+func (d decoder) bytes(offset uint, n uint) ([]byte, error) {
+	if offset+n > uint(len(d.buf)) || offset+n < offset {
+		return nil, fmt.Errorf("%w: data out of range", errInvalidDB)
+	}
+
+	return d.buf[offset : offset+n], nil
+}
+
+func uintField(m map[string]any, key string) uint {
+	v, _ := m[key].(uint64)
+
+	return uint(v)
+}
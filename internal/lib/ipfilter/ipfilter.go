@@ -0,0 +1,211 @@
+package ipfilter
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+)
+
+// Rules allow or deny client IPs by CIDR ranges.
+// Deny ranges take precedence, empty Allow allows all IPs which are not denied.
+type Rules struct {
+	Allow []netip.Prefix
+	Deny  []netip.Prefix
+}
+
+// ParsePrefix parses CIDR range, single IP is parsed as a range of one address.
+func ParsePrefix(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return prefix.Masked(), nil
+}
+
+// ParseRules parses allowed and denied CIDR ranges.
+func ParseRules(allow []string, deny []string) (Rules, error) {
+	var (
+		rules Rules
+		err   error
+	)
+
+	if rules.Allow, err = parsePrefixes(allow); err != nil {
+		return Rules{}, err
+	}
+
+	if rules.Deny, err = parsePrefixes(deny); err != nil {
+		return Rules{}, err
+	}
+
+	return rules, nil
+}
+
+func parsePrefixes(ss []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(ss))
+	for _, s := range ss {
+		prefix, err := ParsePrefix(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
+
+// Allowed reports whether the rules allow the IP.
+// Unknown IP matches no range, so it's allowed only if there are no allowed ranges.
+func (r Rules) Allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	if contains(r.Deny, ip) {
+		return false
+	}
+
+	return len(r.Allow) == 0 || contains(r.Allow, ip)
+}
+
+func contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	if !ip.IsValid() {
+		return false
+	}
+
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type AppRulesProvider interface {
+	// AppIPRules returns rules of all apps.
+	AppIPRules(ctx context.Context) ([]models.IPRule, error)
+}
+
+// Filter checks client IPs against global rules, rules of the called method and rules of the app the request is for.
+// Client must be allowed by all of them.
+type Filter struct {
+	appRulesProvider AppRulesProvider
+	// appRulesTTL is how long rules of apps are cached.
+	appRulesTTL time.Duration
+
+	mu      sync.RWMutex
+	global  Rules
+	methods map[string]Rules
+
+	// apps are cached rules of apps keyed by app ID, all of them are reloaded once expired.
+	appsMu        sync.Mutex
+	apps          map[int]Rules
+	appsExpiresAt time.Time
+}
+
+// New returns a new Filter.
+// Keys of methods are full method names, e.g. "/auth.Auth/Login", or service prefixes ending with "/", e.g. "/auth.Admin/".
+func New(appRulesProvider AppRulesProvider, appRulesTTL time.Duration, global Rules, methods map[string]Rules) *Filter {
+	return &Filter{
+		appRulesProvider: appRulesProvider,
+		appRulesTTL:      appRulesTTL,
+		global:           global,
+		methods:          methods,
+	}
+}
+
+// SetRules changes global and method rules of the running filter.
+func (f *Filter) SetRules(global Rules, methods map[string]Rules) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.global = global
+	f.methods = methods
+}
+
+// Allowed reports whether the client IP may call the method for the app, appID 0 means request is not for an app.
+func (f *Filter) Allowed(ctx context.Context, ip string, method string, appID int) (bool, error) {
+	const op = "ipfilter.Allowed"
+
+	// Invalid address is a zero Addr, which matches no range.
+	addr, _ := netip.ParseAddr(ip)
+
+	f.mu.RLock()
+	global, methods := f.global, f.methods
+	f.mu.RUnlock()
+
+	if !global.Allowed(addr) {
+		return false, nil
+	}
+
+	for key, rules := range methods {
+		if (key == method || strings.HasSuffix(key, "/") && strings.HasPrefix(method, key)) && !rules.Allowed(addr) {
+			return false, nil
+		}
+	}
+
+	if appID == 0 {
+		return true, nil
+	}
+
+	rules, err := f.appRules(ctx, appID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules.Allowed(addr), nil
+}
+
+// appRules returns cached rules of the app, rules of all apps are loaded from storage once cache expires.
+// Apps without rules allow all IPs.
+func (f *Filter) appRules(ctx context.Context, appID int) (Rules, error) {
+	// Lock is held while loading, so concurrent requests don't load rules again.
+	f.appsMu.Lock()
+	defer f.appsMu.Unlock()
+
+	now := time.Now()
+	if f.apps != nil && now.Before(f.appsExpiresAt) {
+		return f.apps[appID], nil
+	}
+
+	stored, err := f.appRulesProvider.AppIPRules(ctx)
+	if err != nil {
+		return Rules{}, err
+	}
+
+	apps := make(map[int]Rules)
+	for _, rule := range stored {
+		prefix, err := ParsePrefix(rule.CIDR)
+		if err != nil {
+			return Rules{}, fmt.Errorf("rule %d of app %d: %w", rule.ID, rule.AppID, err)
+		}
+
+		rules := apps[rule.AppID]
+		switch rule.Action {
+		case models.IPRuleAllow:
+			rules.Allow = append(rules.Allow, prefix)
+		case models.IPRuleDeny:
+			rules.Deny = append(rules.Deny, prefix)
+		}
+		apps[rule.AppID] = rules
+	}
+
+	f.apps = apps
+	f.appsExpiresAt = now.Add(f.appRulesTTL)
+
+	return apps[appID], nil
+}
@@ -0,0 +1,160 @@
+package ipfilter
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticRules struct {
+	rules []models.IPRule
+	calls int
+}
+
+func (s *staticRules) AppIPRules(context.Context) ([]models.IPRule, error) {
+	s.calls++
+
+	return s.rules, nil
+}
+
+func mustRules(t *testing.T, allow []string, deny []string) Rules {
+	t.Helper()
+
+	rules, err := ParseRules(allow, deny)
+	require.NoError(t, err)
+
+	return rules
+}
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "10.0.0.1", want: "10.0.0.1/32"},
+		{in: "2001:db8::1", want: "2001:db8::1/128"},
+		{in: "10.1.2.3/8", want: "10.0.0.0/8"},
+		{in: "2001:db8::1/32", want: "2001:db8::/32"},
+		{in: "10.0.0.0/33", wantErr: true},
+		{in: "example.com", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePrefix(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestRulesAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		ip    string
+		want  bool
+	}{
+		{"no rules", Rules{}, "203.0.113.1", true},
+		{"allowed", mustRules(t, []string{"10.0.0.0/8"}, nil), "10.1.2.3", true},
+		{"not allowed", mustRules(t, []string{"10.0.0.0/8"}, nil), "203.0.113.1", false},
+		{"denied", mustRules(t, nil, []string{"203.0.113.0/24"}), "203.0.113.1", false},
+		{"not denied", mustRules(t, nil, []string{"203.0.113.0/24"}), "198.51.100.1", true},
+		{"deny wins over allow", mustRules(t, []string{"10.0.0.0/8"}, []string{"10.0.0.1"}), "10.0.0.1", false},
+		{"deny wins over broader allow", mustRules(t, []string{"10.0.0.1"}, []string{"10.0.0.0/8"}), "10.0.0.1", false},
+		{"IPv4-mapped IPv6", mustRules(t, []string{"10.0.0.0/8"}, nil), "::ffff:10.0.0.1", true},
+		{"IPv6", mustRules(t, []string{"2001:db8::/32"}, nil), "2001:db8::1", true},
+		{"unknown with allow", mustRules(t, []string{"10.0.0.0/8"}, nil), "", false},
+		{"unknown without allow", mustRules(t, nil, []string{"10.0.0.0/8"}), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, _ := netip.ParseAddr(tt.ip)
+			assert.Equal(t, tt.want, tt.rules.Allowed(addr))
+		})
+	}
+}
+
+func TestFilterAllowed(t *testing.T) {
+	ctx := context.Background()
+	apps := &staticRules{rules: []models.IPRule{
+		{ID: 1, AppID: 1, CIDR: "10.0.0.0/8", Action: models.IPRuleAllow},
+		{ID: 2, AppID: 1, CIDR: "10.9.0.0/16", Action: models.IPRuleDeny},
+	}}
+
+	f := New(apps, time.Hour,
+		mustRules(t, nil, []string{"192.0.2.0/24"}),
+		map[string]Rules{
+			"/auth.Admin/":     mustRules(t, []string{"10.0.0.0/16"}, nil),
+			"/auth.Auth/Login": mustRules(t, nil, []string{"10.1.0.0/16"}),
+		},
+	)
+
+	tests := []struct {
+		name   string
+		ip     string
+		method string
+		appID  int
+		want   bool
+	}{
+		{"no rules of method or app", "203.0.113.1", "/auth.Auth/Register", 0, true},
+		{"globally denied", "192.0.2.1", "/auth.Auth/Register", 0, false},
+		{"global deny wins over app allow", "192.0.2.1", "/auth.Auth/Login", 1, false},
+		{"service prefix allowed", "10.0.1.1", "/auth.Admin/ListUsers", 0, true},
+		{"service prefix not allowed", "10.1.1.1", "/auth.Admin/ListUsers", 0, false},
+		{"method denied", "10.1.1.1", "/auth.Auth/Login", 0, false},
+		{"method rules don't apply to other methods", "10.1.1.1", "/auth.Auth/Register", 0, true},
+		{"app allowed", "10.2.1.1", "/auth.Auth/Login", 1, true},
+		{"app not allowed", "203.0.113.1", "/auth.Auth/Login", 1, false},
+		{"app deny wins over app allow", "10.9.1.1", "/auth.Auth/Login", 1, false},
+		{"app without rules", "203.0.113.1", "/auth.Auth/Login", 2, true},
+		{"invalid IP with allow rules", "not an ip", "/auth.Auth/Login", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.Allowed(ctx, tt.ip, tt.method, tt.appID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, 1, apps.calls, "app rules are cached")
+}
+
+func TestFilterSetRules(t *testing.T) {
+	ctx := context.Background()
+	f := New(&staticRules{}, time.Hour, Rules{}, nil)
+
+	allowed, err := f.Allowed(ctx, "192.0.2.1", "/auth.Auth/Login", 0)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	f.SetRules(mustRules(t, nil, []string{"192.0.2.0/24"}), nil)
+
+	allowed, err = f.Allowed(ctx, "192.0.2.1", "/auth.Auth/Login", 0)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestFilterInvalidAppRule(t *testing.T) {
+	f := New(&staticRules{rules: []models.IPRule{{ID: 1, AppID: 1, CIDR: "bad", Action: models.IPRuleAllow}}}, time.Hour, Rules{}, nil)
+
+	_, err := f.Allowed(context.Background(), "10.0.0.1", "/auth.Auth/Login", 1)
+	assert.Error(t, err)
+}
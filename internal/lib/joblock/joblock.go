@@ -0,0 +1,64 @@
+package joblock
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"grpc-service-ref/internal/lib/random"
+)
+
+// Store keeps leases of jobs shared by replicas.
+type Store interface {
+	AcquireJobLock(ctx context.Context, name string, owner string, now time.Time, until time.Time) (bool, error)
+}
+
+// Locks leases background jobs to the replica, so with several replicas sharing storage
+// every job runs on one of them at a time.
+type Locks struct {
+	store Store
+	owner string
+}
+
+// New returns Locks of the replica identified by owner, see Owner.
+func New(store Store, owner string) *Locks {
+	return &Locks{
+		store: store,
+		owner: owner,
+	}
+}
+
+// Acquire takes or renews lease of the job for ttl and reports whether the replica holds it.
+// Lease held by another replica is taken over once it expires, e.g. after that replica is stopped.
+func (l *Locks) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	const op = "joblock.Acquire"
+
+	now := time.Now().UTC()
+
+	acquired, err := l.store.AcquireJobLock(ctx, name, l.owner, now, now.Add(ttl))
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return acquired, nil
+}
+
+// Owner returns ID of the replica: its hostname, e.g. name of the pod, with a random suffix,
+// so replicas sharing a hostname aren't mistaken for one.
+func Owner() (string, error) {
+	const op = "joblock.Owner"
+
+	host, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	suffix, err := random.Crypto.Bytes(4)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return host + "-" + hex.EncodeToString(suffix), nil
+}
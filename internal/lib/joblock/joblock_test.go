@@ -0,0 +1,91 @@
+package joblock
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lease struct {
+	owner string
+	until time.Time
+}
+
+// memStore is Store keeping leases in memory, with the rules of storage implementations.
+type memStore struct {
+	leases map[string]lease
+	err    error
+}
+
+func (s *memStore) AcquireJobLock(_ context.Context, name string, owner string, now time.Time, until time.Time) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+
+	if l, ok := s.leases[name]; ok && l.owner != owner && now.Before(l.until) {
+		return false, nil
+	}
+
+	s.leases[name] = lease{owner: owner, until: until}
+
+	return true, nil
+}
+
+func TestAcquire(t *testing.T) {
+	ctx := context.Background()
+	store := &memStore{leases: make(map[string]lease)}
+	replica1, replica2 := New(store, "replica-1"), New(store, "replica-2")
+
+	before := time.Now().UTC()
+	acquired, err := replica1.Acquire(ctx, "cleanup", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	l := store.leases["cleanup"]
+	assert.Equal(t, "replica-1", l.owner)
+	assert.WithinRange(t, l.until, before.Add(time.Minute), time.Now().UTC().Add(time.Minute))
+	assert.Equal(t, time.UTC, l.until.Location())
+
+	acquired, err = replica2.Acquire(ctx, "cleanup", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired, "lease is held by another replica")
+
+	acquired, err = replica2.Acquire(ctx, "other", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "jobs are leased separately")
+
+	acquired, err = replica1.Acquire(ctx, "cleanup", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "lease is renewed by its owner")
+
+	// Expired lease is taken over, e.g. after the owner is stopped.
+	_, err = replica1.Acquire(ctx, "cleanup", -time.Second)
+	require.NoError(t, err)
+
+	acquired, err = replica2.Acquire(ctx, "cleanup", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestAcquireFailure(t *testing.T) {
+	_, err := New(&memStore{err: assert.AnError}, "replica-1").Acquire(context.Background(), "cleanup", time.Minute)
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestOwner(t *testing.T) {
+	host, err := os.Hostname()
+	require.NoError(t, err)
+
+	owner1, err := Owner()
+	require.NoError(t, err)
+	assert.Regexp(t, "^"+regexp.QuoteMeta(host)+"-[0-9a-f]{8}$", owner1)
+
+	owner2, err := Owner()
+	require.NoError(t, err)
+	assert.NotEqual(t, owner1, owner2, "replicas sharing a hostname have different owners")
+}
@@ -0,0 +1,17 @@
+package jwt
+
+import "context"
+
+type ctxKey struct{}
+
+// NewContext returns context carrying claims of the authenticated caller.
+func NewContext(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, ctxKey{}, claims)
+}
+
+// FromContext returns claims of the caller, false if the caller wasn't authenticated before the handler.
+func FromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(ctxKey{}).(Claims)
+
+	return claims, ok
+}
@@ -1,27 +1,283 @@
 package jwt
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/random"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Issuance is iss and aud claims put to tokens, so relying parties of one deployment, e.g. production,
+// don't accept tokens issued by another one, e.g. staging, even if apps share secrets. Empty claims are omitted.
+type Issuance struct {
+	Issuer   string
+	Audience string
+	// Key, if set, signs tokens of all apps by ES256 instead of HS256 with the app secret.
+	Key *SigningKey
+}
+
+// ForApp returns claims of tokens of the app, its own issuer and audience override i.
+func (i Issuance) ForApp(app models.App) Issuance {
+	if app.Issuer != "" {
+		i.Issuer = app.Issuer
+	}
+	if app.Audience != "" {
+		i.Audience = app.Audience
+	}
+
+	return i
+}
+
+// Accepts reports whether claims of the token are expected ones, claims that aren't configured match any token.
+func (i Issuance) Accepts(claims Claims) bool {
+	if i.Issuer != "" && claims.Issuer != i.Issuer {
+		return false
+	}
+	if i.Audience != "" && !slices.Contains(claims.Audience, i.Audience) {
+		return false
+	}
+
+	return true
+}
+
+// newToken returns token signed by the method of i.
+func (i Issuance) newToken() *jwt.Token {
+	if i.Key == nil {
+		return jwt.New(jwt.SigningMethodHS256)
+	}
+
+	token := jwt.New(jwt.SigningMethodES256)
+	token.Header["kid"] = i.Key.ID
+
+	return token
+}
+
+// sign signs the token by Key, or by the app secret if there is none.
+func (i Issuance) sign(token *jwt.Token, appSecret string) (string, error) {
+	if i.Key == nil {
+		return token.SignedString([]byte(appSecret))
+	}
+
+	return token.SignedString(i.Key.private)
+}
+
+func (i Issuance) put(claims jwt.MapClaims) {
+	if i.Issuer != "" {
+		claims["iss"] = i.Issuer
+	}
+	if i.Audience != "" {
+		claims["aud"] = i.Audience
+	}
+}
+
 // NewToken creates new JWT token for given user and app.
-func NewToken(user models.User, app models.App, duration time.Duration) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+// Token is identified by ID of the session, which is recorded to check the token is not revoked.
+// Organizations of the user are put to orgs claim as roles by organization ID.
+func NewToken(
+	user models.User,
+	app models.App,
+	session models.Session,
+	orgs []models.OrganizationMember,
+	issuance Issuance,
+) (string, error) {
+	token := issuance.newToken()
 
 	claims := token.Claims.(jwt.MapClaims)
+	issuance.put(claims)
+	claims["jti"] = session.ID
 	claims["uid"] = user.ID
 	claims["email"] = user.Email
-	claims["exp"] = time.Now().Add(duration).Unix()
+	claims["iat"] = session.IssuedAt.Unix()
+	claims["exp"] = session.ExpiresAt.Unix()
 	claims["app_id"] = app.ID
+	if session.Elevated {
+		claims["elv"] = true
+	}
+	if len(orgs) > 0 {
+		roles := make(map[string]string, len(orgs))
+		for _, org := range orgs {
+			roles[strconv.FormatInt(org.OrgID, 10)] = string(org.Role)
+		}
+		claims["orgs"] = roles
+	}
+
+	tokenString, err := issuance.sign(token, app.Secret)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+// NewServiceAccountToken creates new JWT token for service account of the app, limited to scopes.
+// It has sa_id claim instead of uid, so it's never taken for a token of a user.
+func NewServiceAccountToken(
+	account models.ServiceAccount,
+	app models.App,
+	session models.Session,
+	scopes []string,
+	issuance Issuance,
+) (string, error) {
+	token := issuance.newToken()
+
+	claims := token.Claims.(jwt.MapClaims)
+	issuance.put(claims)
+	claims["jti"] = session.ID
+	claims["sa_id"] = account.ID
+	claims["iat"] = session.IssuedAt.Unix()
+	claims["exp"] = session.ExpiresAt.Unix()
+	claims["app_id"] = app.ID
+	claims["scope"] = strings.Join(scopes, " ")
+	if account.OrgID != 0 {
+		claims["org_id"] = account.OrgID
+	}
 
-	tokenString, err := token.SignedString([]byte(app.Secret))
+	tokenString, err := issuance.sign(token, app.Secret)
 	if err != nil {
 		return "", err
 	}
 
 	return tokenString, nil
 }
+
+// NewID returns random token ID for jti claim.
+func NewID(r random.Randomizer) (string, error) {
+	b, err := r.Bytes(16)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are claims of the token issued by NewToken.
+type Claims struct {
+	// ID is jti claim, tokens issued before it was introduced don't have it.
+	ID        string
+	UserID    int64
+	Email     string
+	AppID     int
+	ExpiresAt time.Time
+	// Elevated is set for one-time tokens issued for sensitive actions.
+	Elevated bool
+	// Orgs are roles of the user by organization ID at the time the token was issued.
+	Orgs map[int64]models.OrgRole
+	// ServiceAccountID is set instead of UserID for tokens of service accounts.
+	ServiceAccountID int64
+	// Scopes are set for tokens of service accounts.
+	Scopes []string
+	// OrgID is organization the service account acts for, 0 if none.
+	OrgID int64
+	// Issuer and Audience are empty for tokens issued before they were configured.
+	Issuer   string
+	Audience []string
+}
+
+// ParseToken verifies token issued by NewToken and returns its claims, expiration is checked against now.
+// appSecret returns secret of the app the token claims to be issued for, it's called even if the token is
+// signed by key. If key is set, only tokens signed by it are valid, tokens signed by app secrets are not.
+func ParseToken(tokenString string, now time.Time, key *SigningKey, appSecret func(appID int) (string, error)) (Claims, error) {
+	var claims Claims
+
+	method := jwt.SigningMethodHS256.Alg()
+	if key != nil {
+		method = jwt.SigningMethodES256.Alg()
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		mapClaims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		appID, _ := mapClaims["app_id"].(float64)
+		uid, _ := mapClaims["uid"].(float64)
+		email, _ := mapClaims["email"].(string)
+		jti, _ := mapClaims["jti"].(string)
+		elevated, _ := mapClaims["elv"].(bool)
+		saID, _ := mapClaims["sa_id"].(float64)
+		scope, _ := mapClaims["scope"].(string)
+		orgID, _ := mapClaims["org_id"].(float64)
+		claims = Claims{
+			ID: jti, UserID: int64(uid), Email: email, AppID: int(appID), Elevated: elevated, Orgs: orgRoles(mapClaims),
+			ServiceAccountID: int64(saID), Scopes: strings.Fields(scope), OrgID: int64(orgID),
+		}
+
+		secret, err := appSecret(claims.AppID)
+		if err != nil {
+			return nil, err
+		}
+
+		if key != nil {
+			if kid, _ := token.Header["kid"].(string); kid != key.ID {
+				return nil, ErrInvalidToken
+			}
+
+			return &key.private.PublicKey, nil
+		}
+
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{method}), jwt.WithTimeFunc(func() time.Time { return now }))
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	// Expiration is validated by Parse if it's set, tokens without it are never issued.
+	exp, err := token.Claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return Claims{}, ErrInvalidToken
+	}
+	claims.ExpiresAt = exp.Time
+
+	// Malformed iss and aud are taken for missing ones.
+	claims.Issuer, _ = token.Claims.GetIssuer()
+	claims.Audience, _ = token.Claims.GetAudience()
+
+	return claims, nil
+}
+
+// Elevated reports whether the token claims to be elevated, the signature is not verified.
+// It lets endpoints that don't accept elevated tokens reject them before ParseToken and the session check
+// consume them, a forged claim only gets the token rejected.
+func Elevated(tokenString string) bool {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return false
+	}
+
+	elevated, _ := token.Claims.(jwt.MapClaims)["elv"].(bool)
+
+	return elevated
+}
+
+// orgRoles returns roles of orgs claim, nil if there is none.
+func orgRoles(claims jwt.MapClaims) map[int64]models.OrgRole {
+	orgs, _ := claims["orgs"].(map[string]any)
+	if len(orgs) == 0 {
+		return nil
+	}
+
+	roles := make(map[int64]models.OrgRole, len(orgs))
+	for id, role := range orgs {
+		orgID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if role, ok := role.(string); ok {
+			roles[orgID] = models.OrgRole(role)
+		}
+	}
+
+	return roles
+}
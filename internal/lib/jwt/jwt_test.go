@@ -0,0 +1,164 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/random"
+)
+
+var (
+	benchUser = models.User{ID: 1, Email: "user@example.com"}
+	benchApp  = models.App{ID: 1, Name: "test", Secret: "test-secret"}
+
+	benchIssuance = Issuance{Issuer: "https://sso.example.com", Audience: "api.example.com"}
+)
+
+func benchSession() models.Session {
+	now := time.Now()
+
+	return models.Session{
+		ID:        "0123456789abcdef0123456789abcdef",
+		UserID:    benchUser.ID,
+		AppID:     benchApp.ID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+}
+
+func BenchmarkNewToken(b *testing.B) {
+	session := benchSession()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewToken(benchUser, benchApp, session, nil, benchIssuance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseToken(b *testing.B) {
+	token, err := NewToken(benchUser, benchApp, benchSession(), nil, benchIssuance)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	appSecret := func(int) (string, error) { return benchApp.Secret, nil }
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseToken(token, time.Now(), nil, appSecret); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewID(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewID(random.Crypto); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestElevated(t *testing.T) {
+	token, err := NewToken(benchUser, benchApp, benchSession(), nil, benchIssuance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	elevatedSession := benchSession()
+	elevatedSession.Elevated = true
+	elevated, err := NewToken(benchUser, benchApp, elevatedSession, nil, benchIssuance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"access token", token, false},
+		{"elevated token", elevated, true},
+		{"not a JWT", "not a token", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Elevated(tt.token); got != tt.want {
+				t.Errorf("Elevated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTokenSigningKey(t *testing.T) {
+	newKey := func() *SigningKey {
+		private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := NewSigningKey(private)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return key
+	}
+	key, other := newKey(), newKey()
+
+	issue := func(key *SigningKey) string {
+		issuance := benchIssuance
+		issuance.Key = key
+
+		token, err := NewToken(benchUser, benchApp, benchSession(), nil, issuance)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return token
+	}
+	appSecret := func(int) (string, error) { return benchApp.Secret, nil }
+
+	tests := []struct {
+		name    string
+		token   string
+		key     *SigningKey
+		wantErr bool
+	}{
+		{name: "signed by the key", token: issue(key), key: key},
+		{name: "signed by app secret", token: issue(nil)},
+		{name: "signed by app secret while key is set", token: issue(nil), key: key, wantErr: true},
+		{name: "signed by another key", token: issue(other), key: key, wantErr: true},
+		{name: "signed by the key while it's not set", token: issue(key), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseToken(tt.token, time.Now(), tt.key, appSecret)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("ParseToken() error = %v, want ErrInvalidToken", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseToken() error = %v", err)
+			}
+			if claims.UserID != benchUser.ID {
+				t.Errorf("UserID = %d, want %d", claims.UserID, benchUser.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SigningKey is P-256 key pair tokens are signed with by ES256 instead of HS256 with secrets of apps,
+// so relying services verify tokens by the public key published as JWKS and can't mint them.
+type SigningKey struct {
+	// ID is kid header of tokens, the JWK thumbprint of the public key.
+	ID      string
+	private *ecdsa.PrivateKey
+}
+
+// JWK is public key of SigningKey in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadSigningKey reads PEM encoded P-256 private key, SEC 1 ("EC PRIVATE KEY") or PKCS #8 ("PRIVATE KEY"),
+// e.g. generated by openssl ecparam -name prime256v1 -genkey -noout.
+func LoadSigningKey(path string) (*SigningKey, error) {
+	const op = "jwt.LoadSigningKey"
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := parseECPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return NewSigningKey(key)
+}
+
+// NewSigningKey returns SigningKey of the P-256 private key.
+func NewSigningKey(key *ecdsa.PrivateKey) (*SigningKey, error) {
+	if key.Curve != elliptic.P256() {
+		return nil, errors.New("signing key must be P-256")
+	}
+
+	k := &SigningKey{private: key}
+	k.ID = k.thumbprint()
+
+	return k, nil
+}
+
+// JWK returns public key of k.
+func (k *SigningKey) JWK() JWK {
+	return JWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(k.private.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(k.private.Y.FillBytes(make([]byte, 32))),
+		Kid: k.ID,
+		Use: "sig",
+		Alg: "ES256",
+	}
+}
+
+// thumbprint is RFC 7638 thumbprint: base64url sha256 of the required members of the key in lexicographic order.
+func (k *SigningKey) thumbprint() string {
+	jwk := k.JWK()
+	canonical := fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, jwk.Crv, jwk.Kty, jwk.X, jwk.Y)
+	sum := sha256.Sum256([]byte(canonical))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func parseECPrivateKey(raw []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("private key is not ECDSA")
+		}
+
+		return ecKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
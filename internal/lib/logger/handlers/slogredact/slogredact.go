@@ -0,0 +1,169 @@
+package slogredact
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+)
+
+// Mode is how personal data is written to logs.
+type Mode string
+
+const (
+	// ModeHash replaces personal data with its hash, so records of the same user can still be correlated.
+	// Hashes of known emails can be matched by anyone having the list, it's pseudonymization, not anonymization.
+	ModeHash Mode = "hash"
+	// ModeRedact replaces personal data with a placeholder.
+	ModeRedact Mode = "redact"
+)
+
+const redacted = "[redacted]"
+
+// kind is a kind of sensitive data an attribute holds.
+type kind int
+
+const (
+	kindNone kind = iota
+	// kindPersonal is data identifying a person, e.g. email or IP.
+	kindPersonal
+	// kindSecret is never logged, e.g. verification codes or request payloads with passwords.
+	kindSecret
+)
+
+// keys are kinds of attributes by lower-case key, keys of grouped attributes are matched without group.
+var keys = map[string]kind{
+	"email":       kindPersonal,
+	"username":    kindPersonal,
+	"subject":     kindPersonal,
+	"recipient":   kindPersonal,
+	"to":          kindPersonal,
+	"cc":          kindPersonal,
+	"bcc":         kindPersonal,
+	"phone":       kindPersonal,
+	"ip":          kindPersonal,
+	"remote_addr": kindPersonal,
+	"address":     kindPersonal,
+	// key of throttles is an email or IP.
+	"key": kindPersonal,
+
+	"code":     kindSecret,
+	"password": kindSecret,
+	"token":    kindSecret,
+	"secret":   kindSecret,
+	"body":     kindSecret,
+	"content":  kindSecret,
+}
+
+// Handler hashes or redacts personal data and secrets in attributes before passing records to the next handler.
+// Only attributes are processed, data formatted into messages or errors is logged as is.
+type Handler struct {
+	next slog.Handler
+	mode Mode
+}
+
+func New(next slog.Handler, mode Mode) *Handler {
+	return &Handler{
+		next: next,
+		mode: mode,
+	}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	clean := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+
+	r.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(h.attr(a))
+
+		return true
+	})
+
+	return h.next.Handle(ctx, clean)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		clean = append(clean, h.attr(a))
+	}
+
+	return &Handler{next: h.next.WithAttrs(clean), mode: h.mode}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), mode: h.mode}
+}
+
+func (h *Handler) attr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+
+		clean := make([]any, 0, len(group))
+		for _, ga := range group {
+			clean = append(clean, h.attr(ga))
+		}
+
+		return slog.Group(a.Key, clean...)
+	}
+
+	key := strings.ToLower(a.Key)
+	// Keys like "grpc.request.content" or "peer.address" are matched by their last part.
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		key = key[i+1:]
+	}
+
+	switch keys[key] {
+	case kindSecret:
+		return slog.String(a.Key, redacted)
+	case kindPersonal:
+		// Numbers under these keys are counters, e.g. number of deleted emails.
+		if a.Value.Kind() != slog.KindString && a.Value.Kind() != slog.KindAny {
+			return a
+		}
+
+		return slog.String(a.Key, h.personal(key, a.Value))
+	default:
+		return a
+	}
+}
+
+func (h *Handler) personal(key string, v slog.Value) string {
+	s := v.String()
+	if v.Kind() == slog.KindAny {
+		s = fmt.Sprint(v.Any())
+	}
+
+	// Port differs per connection, so it's dropped to hash the same client IP the same way.
+	if key == "address" || key == "remote_addr" {
+		if host, _, err := net.SplitHostPort(s); err == nil {
+			s = host
+		}
+	}
+
+	if s == "" {
+		return ""
+	}
+
+	if h.mode == ModeRedact {
+		return redacted
+	}
+
+	return Hash(s)
+}
+
+// Hash returns short hash of the value logged instead of it in ModeHash.
+// Values are lower-cased first, so emails differing in case get the same hash.
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(s)))
+
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,25 @@
+package messageid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// New generates RFC 5322 Message-ID for the given sender address,
+// e.g. <1695991234.4f1c2a9b0e7d@gmail.com>.
+func New(from string) (string, error) {
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i != -1 && i < len(from)-1 {
+		domain = from[i+1:]
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().Unix(), hex.EncodeToString(b), domain), nil
+}
@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "sso"
+
+// RPC metrics, recorded by gRPC interceptor.
+var (
+	RPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "Number of handled RPCs by method and status code.",
+	}, []string{"method", "code"})
+
+	RPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Duration of handled RPCs by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+// Login results.
+const (
+	LoginSuccess            = "success"
+	LoginInvalidCredentials = "invalid_credentials"
+	LoginNotVerified        = "not_verified"
+	LoginNotConfirmed       = "not_confirmed"
+	LoginBanned             = "banned"
+)
+
+// Email send results.
+const (
+	EmailSent   = "sent"
+	EmailFailed = "failed"
+)
+
+// Webhook delivery attempt results.
+const (
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+	WebhookDead      = "dead"
+)
+
+// Throttle rejection reasons.
+const (
+	ThrottleDelayed = "delayed"
+	ThrottleLocked  = "locked"
+)
+
+// External user directory authentication results.
+const (
+	DirectoryAuthenticated      = "authenticated"
+	DirectoryInvalidCredentials = "invalid_credentials"
+	DirectoryFailed             = "failed"
+)
+
+// Scheduled job run results.
+const (
+	JobSucceeded = "success"
+	JobFailed    = "failure"
+	// JobSkipped is a run left to another replica holding the job's lock.
+	JobSkipped = "skipped"
+)
+
+// Scheduled job metrics, recorded by scheduler.
+var (
+	JobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Number of scheduled job runs by job and result.",
+	}, []string{"job", "result"})
+
+	JobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Duration of scheduled job runs by job.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"job"})
+
+	JobLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful run by job.",
+	}, []string{"job"})
+)
+
+// Domain metrics, recorded by services.
+var (
+	Registrations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Number of registrations by mode: immediate or pending.",
+	}, []string{"mode"})
+
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Number of login attempts by result.",
+	}, []string{"result"})
+
+	Throttled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "throttled_total",
+		Help:      "Number of attempts rejected after repeated failures, by throttle and reason.",
+	}, []string{"throttle", "reason"})
+
+	StuffingDetections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "credential_stuffing_detections_total",
+		Help:      "Number of credential stuffing detections by scope, global or ip.",
+	}, []string{"scope"})
+
+	Emails = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "emails_total",
+		Help:      "Number of emails by send result.",
+	}, []string{"result"})
+
+	EmailQueue = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "email_queue_length",
+		Help:      "Number of emails waiting for a free sending worker.",
+	})
+
+	AuditQueue = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "audit_queue_length",
+		Help:      "Number of audit events buffered for saving.",
+	})
+
+	AuditEventsDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_events_dropped_total",
+		Help:      "Number of audit events lost because the buffer was full or saving failed.",
+	})
+
+	Verifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "verifications_total",
+		Help:      "Number of verification events by type, app and event: created, delivered, verified, expired or failed.",
+	}, []string{"type", "app", "event"})
+
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Number of webhook delivery attempts by event and result.",
+	}, []string{"event", "result"})
+
+	DirectoryAuthentications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "directory_authentications_total",
+		Help:      "Number of credentials checks by external user directories, by directory and result.",
+	}, []string{"directory", "result"})
+)
+
+// Cache metrics, recorded by in-process caches of storage reads.
+var (
+	CacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_hits_total",
+		Help:      "Number of cache lookups served from cache, by cache.",
+	}, []string{"cache"})
+
+	CacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_misses_total",
+		Help:      "Number of cache lookups which went to storage, by cache.",
+	}, []string{"cache"})
+
+	CacheInvalidations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_invalidations_total",
+		Help:      "Number of cache invalidations after writes, by cache.",
+	}, []string{"cache"})
+)
+
+// RegisterDBStats exports connection pool statistics of database db, read from stats on every scrape.
+func RegisterDBStats(db string, stats func() sql.DBStats) {
+	labels := prometheus.Labels{"db": db}
+
+	gauge := func(name, help string, value func(sql.DBStats) float64) {
+		promauto.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Name:        name,
+			Help:        help,
+			ConstLabels: labels,
+		}, func() float64 { return value(stats()) })
+	}
+	counter := func(name, help string, value func(sql.DBStats) float64) {
+		promauto.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        name,
+			Help:        help,
+			ConstLabels: labels,
+		}, func() float64 { return value(stats()) })
+	}
+
+	gauge("db_max_open_connections", "Maximum number of open connections, 0 is unlimited.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	gauge("db_open_connections", "Number of open connections, in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	gauge("db_in_use_connections", "Number of connections in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	gauge("db_idle_connections", "Number of idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	counter("db_wait_count_total", "Number of waits for a free connection.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	counter("db_wait_duration_seconds_total", "Time waited for a free connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+}
@@ -0,0 +1,47 @@
+package passhash
+
+import (
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// calibrationPassword is hashed to measure hashing time, time doesn't depend on the password.
+const calibrationPassword = "calibration password"
+
+// Calibrate returns the highest bcrypt cost, not lower than minCost, hashing a password within target time
+// on this host, and the time it took. Every next cost doubles the time, so costs are tried one by one
+// and calibration takes up to about twice the target.
+func Calibrate(minCost int, target time.Duration) (int, time.Duration, error) {
+	cost := minCost
+
+	took, err := measure(cost)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for cost < bcrypt.MaxCost && took*2 <= target {
+		next, err := measure(cost + 1)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if next > target {
+			break
+		}
+
+		cost, took = cost+1, next
+	}
+
+	return cost, took, nil
+}
+
+func measure(cost int) (time.Duration, error) {
+	start := time.Now()
+
+	if _, err := bcrypt.GenerateFromPassword([]byte(calibrationPassword), cost); err != nil {
+		return 0, err
+	}
+
+	return time.Since(start), nil
+}
@@ -0,0 +1,121 @@
+package passstrength
+
+import (
+	"unicode"
+)
+
+const (
+	suggestionUseWords      = "Use a few words, avoid common phrases"
+	suggestionNoNeedSymbols = "No need for symbols, digits, or uppercase letters"
+	suggestionAddWord       = "Add another word or two. Uncommon words are better."
+)
+
+// feedback returns warning and suggestions explaining the score by the longest pattern of the password.
+func feedback(score int, sequence []*match) (string, []string) {
+	if len(sequence) == 0 {
+		return "", []string{suggestionUseWords, suggestionNoNeedSymbols}
+	}
+
+	if score > 2 {
+		return "", nil
+	}
+
+	longest := sequence[0]
+	for _, m := range sequence[1:] {
+		if len(m.token) > len(longest.token) {
+			longest = m
+		}
+	}
+
+	warning, suggestions := matchFeedback(longest, len(sequence) == 1)
+
+	return warning, append([]string{suggestionAddWord}, suggestions...)
+}
+
+func matchFeedback(m *match, soleMatch bool) (string, []string) {
+	switch m.pattern {
+	case patternDictionary:
+		return dictionaryFeedback(m, soleMatch)
+	case patternSpatial:
+		if m.turns == 1 {
+			return "Straight rows of keys are easy to guess", []string{"Use a longer keyboard pattern with more turns"}
+		}
+
+		return "Short keyboard patterns are easy to guess", []string{"Use a longer keyboard pattern with more turns"}
+	case patternRepeat:
+		if m.baseLength == 1 {
+			return `Repeats like "aaa" are easy to guess`, []string{"Avoid repeated words and characters"}
+		}
+
+		return `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`, []string{"Avoid repeated words and characters"}
+	case patternSequence:
+		return "Sequences like abc or 6543 are easy to guess", []string{"Avoid sequences"}
+	case patternYear:
+		return "Recent years are easy to guess", []string{"Avoid recent years", "Avoid years that are associated with you"}
+	case patternDate:
+		return "Dates are often easy to guess", []string{"Avoid dates and years that are associated with you"}
+	default:
+		return "", nil
+	}
+}
+
+func dictionaryFeedback(m *match, soleMatch bool) (string, []string) {
+	var warning string
+	switch m.dictionary {
+	case "passwords":
+		switch {
+		case soleMatch && m.l33t == nil && !m.reversed && m.rank <= 10:
+			warning = "This is a top-10 common password"
+		case soleMatch && m.l33t == nil && !m.reversed && m.rank <= 100:
+			warning = "This is a top-100 common password"
+		case soleMatch && m.l33t == nil && !m.reversed:
+			warning = "This is a very common password"
+		default:
+			warning = "This is similar to a commonly used password"
+		}
+	case "english":
+		if soleMatch {
+			warning = "A word by itself is easy to guess"
+		}
+	case "names":
+		if soleMatch {
+			warning = "Names and surnames by themselves are easy to guess"
+		} else {
+			warning = "Common names and surnames are easy to guess"
+		}
+	case dictionaryUserInputs:
+		warning = "Avoid your name or email address in the password"
+	}
+
+	var suggestions []string
+
+	runes := []rune(m.token)
+	switch {
+	case unicode.IsUpper(runes[0]) && uppercaseVariations(m.token) == 2 && !isAllUpper(runes):
+		suggestions = append(suggestions, "Capitalization doesn't help very much")
+	case isAllUpper(runes):
+		suggestions = append(suggestions, "All-uppercase is almost as easy to guess as all-lowercase")
+	}
+	if m.reversed && len(runes) >= 4 {
+		suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+	}
+	if m.l33t != nil {
+		suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+	}
+
+	return warning, suggestions
+}
+
+func isAllUpper(runes []rune) bool {
+	hasUpper := false
+	for _, r := range runes {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		}
+	}
+
+	return hasUpper
+}
@@ -0,0 +1,621 @@
+package passstrength
+
+import (
+	"bufio"
+	"embed"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Ranked word lists, most common first: leaked passwords, English words and common names.
+//
+//go:embed dictionaries/*.txt
+var dictionaryFiles embed.FS
+
+var dictionaries = map[string]map[string]int{
+	"passwords": loadDictionary("dictionaries/passwords.txt"),
+	"english":   loadDictionary("dictionaries/english.txt"),
+	"names":     loadDictionary("dictionaries/names.txt"),
+}
+
+const (
+	patternDictionary = "dictionary"
+	patternSpatial    = "spatial"
+	patternRepeat     = "repeat"
+	patternSequence   = "sequence"
+	patternYear       = "year"
+	patternDate       = "date"
+	patternBruteforce = "bruteforce"
+
+	dictionaryUserInputs = "user_inputs"
+)
+
+// maxWordLength bounds lengths of dictionary words looked up.
+const maxWordLength = 32
+
+// match is a pattern found in the password at runes i..j inclusive.
+type match struct {
+	pattern string
+	i, j    int
+	token   string
+
+	// dictionary matches
+	dictionary string
+	rank       int
+	reversed   bool
+	// l33t maps substituted characters of the token to the letters they stand for.
+	l33t map[rune]rune
+
+	// spatial matches
+	turns   int
+	shifted int
+
+	// repeat matches
+	baseGuesses float64
+	repeats     int
+	baseLength  int
+
+	// sequence matches
+	ascending bool
+
+	// year and date matches
+	year      int
+	separator bool
+
+	// guesses is computed once by the search.
+	guesses float64
+}
+
+func loadDictionary(name string) map[string]int {
+	f, err := dictionaryFiles.Open(name)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	ranked := make(map[string]int)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if _, ok := ranked[word]; word != "" && !ok {
+			ranked[word] = len(ranked) + 1
+		}
+	}
+
+	return ranked
+}
+
+// omnimatch returns all patterns found in the password, overlapping ones included,
+// the search picks the most guessable sequence of them.
+func omnimatch(pw []rune, userInputs map[string]int) []*match {
+	dicts := make(map[string]map[string]int, len(dictionaries)+1)
+	for name, ranked := range dictionaries {
+		dicts[name] = ranked
+	}
+	dicts[dictionaryUserInputs] = userInputs
+
+	var matches []*match
+	matches = append(matches, dictionaryMatches(pw, dicts)...)
+	matches = append(matches, reverseDictionaryMatches(pw, dicts)...)
+	matches = append(matches, l33tMatches(pw, dicts)...)
+	matches = append(matches, spatialMatches(pw)...)
+	matches = append(matches, repeatMatches(pw)...)
+	matches = append(matches, sequenceMatches(pw)...)
+	matches = append(matches, yearMatches(pw)...)
+	matches = append(matches, dateMatches(pw)...)
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].i != matches[b].i {
+			return matches[a].i < matches[b].i
+		}
+
+		return matches[a].j < matches[b].j
+	})
+
+	return matches
+}
+
+func lower(pw []rune) []rune {
+	res := make([]rune, len(pw))
+	for i, r := range pw {
+		res[i] = unicode.ToLower(r)
+	}
+
+	return res
+}
+
+func dictionaryMatches(pw []rune, dicts map[string]map[string]int) []*match {
+	lowered := lower(pw)
+
+	var matches []*match
+	for i := range lowered {
+		for j := i; j < len(lowered) && j-i < maxWordLength; j++ {
+			word := string(lowered[i : j+1])
+
+			for name, ranked := range dicts {
+				rank, ok := ranked[word]
+				if !ok {
+					continue
+				}
+
+				matches = append(matches, &match{
+					pattern:    patternDictionary,
+					i:          i,
+					j:          j,
+					token:      string(pw[i : j+1]),
+					dictionary: name,
+					rank:       rank,
+				})
+			}
+		}
+	}
+
+	return matches
+}
+
+func reverseDictionaryMatches(pw []rune, dicts map[string]map[string]int) []*match {
+	reversed := make([]rune, len(pw))
+	for i, r := range pw {
+		reversed[len(pw)-1-i] = r
+	}
+
+	matches := dictionaryMatches(reversed, dicts)
+	for _, m := range matches {
+		m.i, m.j = len(pw)-1-m.j, len(pw)-1-m.i
+		m.token = string(pw[m.i : m.j+1])
+		m.reversed = true
+	}
+
+	return matches
+}
+
+// l33tTable maps characters to letters they commonly substitute.
+var l33tTable = map[rune][]rune{
+	'4': {'a'},
+	'@': {'a'},
+	'8': {'b'},
+	'(': {'c'},
+	'{': {'c'},
+	'[': {'c'},
+	'<': {'c'},
+	'3': {'e'},
+	'6': {'g'},
+	'9': {'g'},
+	'1': {'i', 'l'},
+	'!': {'i'},
+	'|': {'i', 'l'},
+	'0': {'o'},
+	'$': {'s'},
+	'5': {'s'},
+	'+': {'t'},
+	'7': {'t'},
+	'%': {'x'},
+	'2': {'z'},
+}
+
+// maxL33tVariants bounds variants of the password looked up when substituted characters are ambiguous.
+const maxL33tVariants = 16
+
+// l33tMatches finds words with characters substituted, e.g. p@ssw0rd.
+func l33tMatches(pw []rune, dicts map[string]map[string]int) []*match {
+	variants := [][]rune{lower(pw)}
+	substituted := false
+
+	for pos, r := range pw {
+		subs, ok := l33tTable[r]
+		if !ok {
+			continue
+		}
+		substituted = true
+
+		var next [][]rune
+		for _, variant := range variants {
+			for k, sub := range subs {
+				if k > 0 && len(next) >= maxL33tVariants {
+					break
+				}
+
+				v := append([]rune(nil), variant...)
+				v[pos] = sub
+				next = append(next, v)
+			}
+		}
+		variants = next
+	}
+
+	if !substituted {
+		return nil
+	}
+
+	type word struct {
+		i, j       int
+		dictionary string
+		rank       int
+	}
+	seen := make(map[word]bool)
+
+	var matches []*match
+	for _, variant := range variants {
+		for _, m := range dictionaryMatches(variant, dicts) {
+			if m.j == m.i {
+				continue
+			}
+
+			l33t := make(map[rune]rune)
+			for pos := m.i; pos <= m.j; pos++ {
+				if _, ok := l33tTable[pw[pos]]; ok && variant[pos] != unicode.ToLower(pw[pos]) {
+					l33t[pw[pos]] = variant[pos]
+				}
+			}
+			if len(l33t) == 0 {
+				continue
+			}
+
+			w := word{m.i, m.j, m.dictionary, m.rank}
+			if seen[w] {
+				continue
+			}
+			seen[w] = true
+
+			m.token = string(pw[m.i : m.j+1])
+			m.l33t = l33t
+			matches = append(matches, m)
+		}
+	}
+
+	return matches
+}
+
+// keyboardRows are rows of US QWERTY keyboard, each key is unshifted and shifted character.
+var keyboardRows = [][]string{
+	{"`~", "1!", "2@", "3#", "4$", "5%", "6^", "7&", "8*", "9(", "0)", "-_", "=+"},
+	{"qQ", "wW", "eE", "rR", "tT", "yY", "uU", "iI", "oO", "pP", "[{", "]}", "\\|"},
+	{"aA", "sS", "dD", "fF", "gG", "hH", "jJ", "kK", "lL", ";:", "'\""},
+	{"zZ", "xX", "cC", "vV", "bB", "nN", "mM", ",<", ".>", "/?"},
+}
+
+type keyPosition struct {
+	row, col int
+}
+
+type key struct {
+	pos     keyPosition
+	shifted bool
+}
+
+// keyboard maps characters to their keys.
+var keyboard = func() map[rune]key {
+	res := make(map[rune]key)
+
+	for row, keys := range keyboardRows {
+		for col, chars := range keys {
+			for k, r := range []rune(chars) {
+				res[r] = key{pos: keyPosition{row, col}, shifted: k == 1}
+			}
+		}
+	}
+
+	return res
+}()
+
+// keyDirections are offsets of adjacent keys on a keyboard with rows slanted to the right:
+// left, upper left, upper right, right, lower right, lower left.
+var keyDirections = []keyPosition{{0, -1}, {-1, 0}, {-1, 1}, {0, 1}, {1, 0}, {1, -1}}
+
+// keyboardStartingPositions and keyboardAverageDegree describe the keyboard graph for guesses of keyboard walks.
+var keyboardStartingPositions, keyboardAverageDegree = func() (float64, float64) {
+	var keys, neighbors int
+	for row, rowKeys := range keyboardRows {
+		for col := range rowKeys {
+			keys++
+
+			for _, d := range keyDirections {
+				r, c := row+d.row, col+d.col
+				if r >= 0 && r < len(keyboardRows) && c >= 0 && c < len(keyboardRows[r]) {
+					neighbors++
+				}
+			}
+		}
+	}
+
+	return float64(keys), float64(neighbors) / float64(keys)
+}()
+
+// keyDirection returns the direction from key of a to key of b, -1 if they aren't adjacent.
+func keyDirection(a, b rune) int {
+	ka, ok := keyboard[a]
+	if !ok {
+		return -1
+	}
+
+	kb, ok := keyboard[b]
+	if !ok {
+		return -1
+	}
+
+	for dir, d := range keyDirections {
+		if ka.pos.row+d.row == kb.pos.row && ka.pos.col+d.col == kb.pos.col {
+			return dir
+		}
+	}
+
+	return -1
+}
+
+// spatialMatches finds keyboard walks of at least 3 keys, e.g. qwerty or zxcvfr.
+func spatialMatches(pw []rune) []*match {
+	var matches []*match
+
+	i := 0
+	for i < len(pw)-2 {
+		j := i
+		turns := 0
+		lastDir := -1
+		shifted := 0
+		if k, ok := keyboard[pw[i]]; ok && k.shifted {
+			shifted++
+		}
+
+		for j+1 < len(pw) {
+			dir := keyDirection(pw[j], pw[j+1])
+			if dir < 0 {
+				break
+			}
+
+			if dir != lastDir {
+				turns++
+				lastDir = dir
+			}
+			if keyboard[pw[j+1]].shifted {
+				shifted++
+			}
+			j++
+		}
+
+		if j-i+1 >= 3 {
+			matches = append(matches, &match{
+				pattern: patternSpatial,
+				i:       i,
+				j:       j,
+				token:   string(pw[i : j+1]),
+				turns:   turns,
+				shifted: shifted,
+			})
+			i = j
+
+			continue
+		}
+
+		i++
+	}
+
+	return matches
+}
+
+// repeatMatches finds repeated characters or groups of them, e.g. aaa or abcabc.
+func repeatMatches(pw []rune) []*match {
+	var matches []*match
+
+	i := 0
+	for i < len(pw)-1 {
+		bestLength, bestRepeats := 0, 0
+		for baseLength := 1; baseLength <= (len(pw)-i)/2; baseLength++ {
+			base := string(pw[i : i+baseLength])
+
+			repeats := 1
+			for next := i + baseLength; next+baseLength <= len(pw) && string(pw[next:next+baseLength]) == base; next += baseLength {
+				repeats++
+			}
+
+			if repeats >= 2 && baseLength*repeats > bestLength*bestRepeats {
+				bestLength, bestRepeats = baseLength, repeats
+			}
+		}
+
+		if bestRepeats == 0 {
+			i++
+
+			continue
+		}
+
+		base := pw[i : i+bestLength]
+		baseGuesses, _ := mostGuessableSequence(base, omnimatch(base, nil))
+		j := i + bestLength*bestRepeats - 1
+
+		matches = append(matches, &match{
+			pattern:     patternRepeat,
+			i:           i,
+			j:           j,
+			token:       string(pw[i : j+1]),
+			baseGuesses: baseGuesses,
+			repeats:     bestRepeats,
+			baseLength:  bestLength,
+		})
+		i = j + 1
+	}
+
+	return matches
+}
+
+// maxSequenceDelta bounds difference of neighbouring characters of sequences, e.g. 2468 or acegi.
+const maxSequenceDelta = 5
+
+// sequenceMatches finds sequences of at least 3 characters with constant difference, e.g. abcd or 9753.
+func sequenceMatches(pw []rune) []*match {
+	var matches []*match
+
+	add := func(i, j, delta int) {
+		if j-i+1 < 3 || delta == 0 || delta > maxSequenceDelta || delta < -maxSequenceDelta {
+			return
+		}
+
+		matches = append(matches, &match{
+			pattern:   patternSequence,
+			i:         i,
+			j:         j,
+			token:     string(pw[i : j+1]),
+			ascending: delta > 0,
+		})
+	}
+
+	if len(pw) < 3 {
+		return nil
+	}
+
+	i, lastDelta := 0, 0
+	for k := 1; k < len(pw); k++ {
+		delta := int(pw[k]) - int(pw[k-1])
+		if k == 1 {
+			lastDelta = delta
+
+			continue
+		}
+
+		if delta == lastDelta {
+			continue
+		}
+
+		add(i, k-1, lastDelta)
+		i, lastDelta = k-1, delta
+	}
+	add(i, len(pw)-1, lastDelta)
+
+	return matches
+}
+
+// minYearSpace is the least number of years guessed for years and dates, recent years are guessed first.
+const minYearSpace = 20
+
+var yearPattern = regexp.MustCompile(`^(19|20)\d\d$`)
+
+// yearMatches finds years like 1987 or 2024.
+func yearMatches(pw []rune) []*match {
+	var matches []*match
+
+	for i := 0; i+4 <= len(pw); i++ {
+		token := string(pw[i : i+4])
+		if !yearPattern.MatchString(token) {
+			continue
+		}
+
+		year, _ := strconv.Atoi(token)
+		matches = append(matches, &match{
+			pattern: patternYear,
+			i:       i,
+			j:       i + 3,
+			token:   token,
+			year:    year,
+		})
+	}
+
+	return matches
+}
+
+// dateSplits are positions splitting dates without separators into day, month and year by length.
+var dateSplits = map[int][][2]int{
+	4: {{1, 2}, {2, 3}},
+	5: {{1, 3}, {2, 3}},
+	6: {{1, 2}, {2, 4}, {4, 5}},
+	7: {{1, 3}, {2, 3}, {4, 5}, {4, 6}},
+	8: {{2, 4}, {4, 6}},
+}
+
+var datePattern = regexp.MustCompile(`^(\d{1,4})([\s/\\_.-])(\d{1,2})([\s/\\_.-])(\d{1,4})$`)
+
+// dateMatches finds dates with or without separators, e.g. 13.05.1991, 1991-05-13 or 130591.
+func dateMatches(pw []rune) []*match {
+	var matches []*match
+
+	for i := range pw {
+		for j := i + 3; j < len(pw) && j-i < 10; j++ {
+			token := string(pw[i : j+1])
+
+			if splits, ok := dateSplits[j-i+1]; ok && isDigits(token) {
+				best, found := 0, false
+				for _, split := range splits {
+					a, _ := strconv.Atoi(token[:split[0]])
+					b, _ := strconv.Atoi(token[split[0]:split[1]])
+					c, _ := strconv.Atoi(token[split[1]:])
+
+					if year, ok := dateYear(a, b, c); ok && (!found || yearDistance(year) < yearDistance(best)) {
+						best, found = year, true
+					}
+				}
+
+				if found {
+					matches = append(matches, &match{pattern: patternDate, i: i, j: j, token: token, year: best})
+				}
+
+				continue
+			}
+
+			parts := datePattern.FindStringSubmatch(token)
+			if parts == nil || parts[2] != parts[4] {
+				continue
+			}
+
+			a, _ := strconv.Atoi(parts[1])
+			b, _ := strconv.Atoi(parts[3])
+			c, _ := strconv.Atoi(parts[5])
+
+			if year, ok := dateYear(a, b, c); ok {
+				matches = append(matches, &match{pattern: patternDate, i: i, j: j, token: token, year: year, separator: true})
+			}
+		}
+	}
+
+	return matches
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dateYear returns the year of date of three numbers with the year first or last, and the day and month
+// in either order, two-digit years are of the 20th or 21st century.
+func dateYear(a, b, c int) (int, bool) {
+	candidates := [][3]int{{c, a, b}, {a, b, c}}
+
+	for _, candidate := range candidates {
+		year, first, second := candidate[0], candidate[1], candidate[2]
+
+		switch {
+		case year > 99 && (year < 1000 || year > 2050):
+			continue
+		case year <= 99 && year > 50:
+			year += 1900
+		case year <= 99:
+			year += 2000
+		}
+
+		if validDayMonth(first, second) || validDayMonth(second, first) {
+			return year, true
+		}
+	}
+
+	return 0, false
+}
+
+func validDayMonth(day, month int) bool {
+	return day >= 1 && day <= 31 && month >= 1 && month <= 12
+}
+
+func yearDistance(year int) int {
+	d := time.Now().Year() - year
+	if d < 0 {
+		d = -d
+	}
+
+	return d
+}
@@ -0,0 +1,121 @@
+package passstrength
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// maxLength bounds runes estimated, matching is quadratic in length. Longer passwords are estimated
+// by their prefix, extra characters only add strength.
+const maxLength = 100
+
+// MaxScore is the score of very unguessable passwords.
+const MaxScore = 4
+
+// ErrTooWeak is returned by Policy.Check for passwords scored below the minimal score.
+var ErrTooWeak = errors.New("password is too weak")
+
+// Result is strength of a password with feedback for the user choosing it.
+type Result struct {
+	// Score is 0 (too guessable) to MaxScore (very unguessable).
+	Score int
+	// Guesses is estimated number of guesses an attacker needs to find the password.
+	Guesses float64
+	// Warning explains what makes the password guessable, empty for strong passwords.
+	Warning string
+	// Suggestions help to choose a stronger password.
+	Suggestions []string
+}
+
+// Estimate estimates strength of the password the way zxcvbn does: the password is split into
+// the most guessable sequence of patterns (common passwords, words, names, keyboard walks, repeats,
+// sequences, years and dates) with characters between them guessed by brute force.
+// userInputs, e.g. email of the user, are guessed first, so passwords made of them are weak.
+func Estimate(password string, userInputs ...string) Result {
+	pw := []rune(password)
+	if len(pw) > maxLength {
+		pw = pw[:maxLength]
+	}
+
+	guesses, sequence := mostGuessableSequence(pw, omnimatch(pw, rankedUserInputs(userInputs)))
+	score := scoreOf(guesses)
+	warning, suggestions := feedback(score, sequence)
+
+	return Result{
+		Score:       score,
+		Guesses:     guesses,
+		Warning:     warning,
+		Suggestions: suggestions,
+	}
+}
+
+// GuessesLog10 returns order of magnitude of guesses, convenient to show and compare.
+func (r Result) GuessesLog10() float64 {
+	return math.Log10(r.Guesses)
+}
+
+// Policy is the password policy enforced on registration and password changes.
+type Policy struct {
+	// MinScore is the lowest accepted score, 0 accepts any password.
+	MinScore int
+}
+
+// Check estimates strength of the password and returns ErrTooWeak with the estimate
+// if the password is scored below MinScore.
+func (p Policy) Check(password string, userInputs ...string) (Result, error) {
+	res := Estimate(password, userInputs...)
+	if res.Score < p.MinScore {
+		return res, ErrTooWeak
+	}
+
+	return res, nil
+}
+
+// scoreOf maps guesses to score by thresholds of zxcvbn: online attacks with throttling,
+// online attacks without throttling, offline attacks on slow hashes and on fast hashes.
+func scoreOf(guesses float64) int {
+	const delta = 5
+
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	default:
+		return MaxScore
+	}
+}
+
+// rankedUserInputs returns dictionary of user inputs, emails are split into words too,
+// so names in the address are guessed.
+func rankedUserInputs(userInputs []string) map[string]int {
+	ranked := make(map[string]int)
+
+	add := func(word string) {
+		if word == "" {
+			return
+		}
+		if _, ok := ranked[word]; !ok {
+			ranked[word] = len(ranked) + 1
+		}
+	}
+
+	for _, input := range userInputs {
+		input = strings.ToLower(input)
+		add(input)
+
+		for _, word := range strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(word)
+		}
+	}
+
+	return ranked
+}
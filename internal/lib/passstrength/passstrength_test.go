@@ -0,0 +1,27 @@
+package passstrength
+
+import (
+	"testing"
+)
+
+// CheckPasswordStrength is called on keystrokes of registration forms, so estimation must stay cheap
+// for long passwords too.
+
+func BenchmarkEstimate(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Estimate("Tr0ub4dour&3correcthorse1991", "john.smith@example.com")
+	}
+}
+
+func BenchmarkEstimateMaxLength(b *testing.B) {
+	password := make([]rune, maxLength)
+	for i := range password {
+		password[i] = rune('a' + i%26)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Estimate(string(password))
+	}
+}
@@ -0,0 +1,309 @@
+package passstrength
+
+import (
+	"math"
+	"unicode"
+)
+
+const (
+	// bruteforceCardinality is guesses per character of parts of the password matching no pattern.
+	bruteforceCardinality = 10
+	// minGuessesBeforeGrowingSequence penalizes splitting the password into more patterns,
+	// so a long bruteforce part isn't split into short matches like single digits.
+	minGuessesBeforeGrowingSequence = 10000
+	minSubmatchGuessesSingleChar    = 10
+	minSubmatchGuessesMultiChar     = 50
+)
+
+// mostGuessableSequence returns guesses of the password and the sequence of non-overlapping matches
+// covering it which needs the least guesses, gaps between matches are filled with bruteforce matches.
+// The sequence of l matches needs l! * product of their guesses, as the attacker doesn't know the order
+// of patterns, plus guesses of all shorter sequences.
+func mostGuessableSequence(pw []rune, matches []*match) (float64, []*match) {
+	n := len(pw)
+	if n == 0 {
+		return 1, nil
+	}
+
+	byEnd := make([][]*match, n)
+	for _, m := range matches {
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+
+	// Indexed by end of sequence and its length: last match, product of guesses and overall guesses.
+	optimalM := make([]map[int]*match, n)
+	optimalPi := make([]map[int]float64, n)
+	optimalG := make([]map[int]float64, n)
+	for k := range optimalM {
+		optimalM[k] = make(map[int]*match)
+		optimalPi[k] = make(map[int]float64)
+		optimalG[k] = make(map[int]float64)
+	}
+
+	update := func(m *match, l int) {
+		k := m.j
+
+		pi := estimateGuesses(m, n)
+		if l > 1 {
+			pi *= optimalPi[m.i-1][l-1]
+		}
+
+		g := factorial(l)*pi + math.Pow(minGuessesBeforeGrowingSequence, float64(l-1))
+
+		for competingL, competingG := range optimalG[k] {
+			if competingL <= l && competingG <= g {
+				return
+			}
+		}
+
+		optimalM[k][l] = m
+		optimalPi[k][l] = pi
+		optimalG[k][l] = g
+	}
+
+	bruteforceUpdate := func(k int) {
+		update(bruteforceMatch(pw, 0, k), 1)
+
+		for i := 1; i <= k; i++ {
+			m := bruteforceMatch(pw, i, k)
+			for l, last := range optimalM[i-1] {
+				// Adjacent bruteforce matches are never better than a single longer one.
+				if last.pattern == patternBruteforce {
+					continue
+				}
+				update(m, l+1)
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for _, m := range byEnd[k] {
+			if m.i > 0 {
+				for l := range optimalM[m.i-1] {
+					update(m, l+1)
+				}
+			} else {
+				update(m, 1)
+			}
+		}
+		bruteforceUpdate(k)
+	}
+
+	bestL, bestG := 0, math.Inf(1)
+	for l, g := range optimalG[n-1] {
+		if g < bestG || (g == bestG && l < bestL) {
+			bestL, bestG = l, g
+		}
+	}
+
+	sequence := make([]*match, bestL)
+	for k, l := n-1, bestL; l > 0; l-- {
+		m := optimalM[k][l]
+		sequence[l-1] = m
+		k = m.i - 1
+	}
+
+	return bestG, sequence
+}
+
+func bruteforceMatch(pw []rune, i, j int) *match {
+	return &match{
+		pattern: patternBruteforce,
+		i:       i,
+		j:       j,
+		token:   string(pw[i : j+1]),
+	}
+}
+
+// estimateGuesses returns guesses of the match, matches are guessed at least a few times,
+// unless they are the whole password.
+func estimateGuesses(m *match, passwordLength int) float64 {
+	if m.guesses != 0 {
+		return m.guesses
+	}
+
+	length := m.j - m.i + 1
+
+	minGuesses := 1.0
+	if length < passwordLength {
+		minGuesses = minSubmatchGuessesMultiChar
+		if length == 1 {
+			minGuesses = minSubmatchGuessesSingleChar
+		}
+	}
+
+	var guesses float64
+	switch m.pattern {
+	case patternDictionary:
+		guesses = dictionaryGuesses(m)
+	case patternSpatial:
+		guesses = spatialGuesses(m, length)
+	case patternRepeat:
+		guesses = m.baseGuesses * float64(m.repeats)
+	case patternSequence:
+		guesses = sequenceGuesses(m, length)
+	case patternYear:
+		guesses = math.Max(float64(yearDistance(m.year)), minYearSpace)
+	case patternDate:
+		guesses = math.Max(float64(yearDistance(m.year)), minYearSpace) * 365
+		if m.separator {
+			guesses *= 4
+		}
+	default:
+		guesses = bruteforceGuesses(length)
+	}
+
+	m.guesses = math.Max(guesses, minGuesses)
+
+	return m.guesses
+}
+
+func bruteforceGuesses(length int) float64 {
+	guesses := math.Pow(bruteforceCardinality, float64(length))
+	if math.IsInf(guesses, 0) {
+		guesses = math.MaxFloat64
+	}
+
+	minGuesses := float64(minSubmatchGuessesMultiChar + 1)
+	if length == 1 {
+		minGuesses = minSubmatchGuessesSingleChar + 1
+	}
+
+	return math.Max(guesses, minGuesses)
+}
+
+func dictionaryGuesses(m *match) float64 {
+	guesses := float64(m.rank) * uppercaseVariations(m.token) * l33tVariations(m)
+	if m.reversed {
+		guesses *= 2
+	}
+
+	return guesses
+}
+
+// uppercaseVariations returns how many capitalizations of the word are guessed before the one of the token,
+// capitalized first or last letter and all caps are guessed first.
+func uppercaseVariations(token string) float64 {
+	runes := []rune(token)
+
+	var upper, lower int
+	for _, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 1
+	}
+
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(runes[0]) || unicode.IsUpper(runes[len(runes)-1]))) {
+		return 2
+	}
+
+	return partialCombinations(upper, lower)
+}
+
+// l33tVariations returns how many substitutions of the word's letters are guessed before the ones of the token.
+func l33tVariations(m *match) float64 {
+	if len(m.l33t) == 0 {
+		return 1
+	}
+
+	variations := 1.0
+	for substituted, letter := range m.l33t {
+		var subs, unsubs int
+		for _, r := range m.token {
+			switch unicode.ToLower(r) {
+			case substituted:
+				subs++
+			case letter:
+				unsubs++
+			}
+		}
+
+		if subs == 0 || unsubs == 0 {
+			variations *= 2
+		} else {
+			variations *= partialCombinations(subs, unsubs)
+		}
+	}
+
+	return variations
+}
+
+func spatialGuesses(m *match, length int) float64 {
+	var guesses float64
+	for i := 2; i <= length; i++ {
+		for j := 1; j <= m.turns && j <= i-1; j++ {
+			guesses += binomial(i-1, j-1) * keyboardStartingPositions * math.Pow(keyboardAverageDegree, float64(j))
+		}
+	}
+
+	if m.shifted > 0 {
+		unshifted := length - m.shifted
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			guesses *= partialCombinations(m.shifted, unshifted)
+		}
+	}
+
+	return guesses
+}
+
+func sequenceGuesses(m *match, length int) float64 {
+	first := []rune(m.token)[0]
+
+	var base float64
+	switch {
+	case first == 'a' || first == 'A' || first == 'z' || first == 'Z' || first == '0' || first == '1' || first == '9':
+		// Obvious starts are guessed first.
+		base = 4
+	case first >= '0' && first <= '9':
+		base = 10
+	default:
+		base = 26
+	}
+
+	if !m.ascending {
+		base *= 2
+	}
+
+	return base * float64(length)
+}
+
+// partialCombinations returns number of ways to pick 1 to min(a, b) of a+b items.
+func partialCombinations(a, b int) float64 {
+	var res float64
+	for i := 1; i <= a && i <= b; i++ {
+		res += binomial(a+b, i)
+	}
+
+	return res
+}
+
+func binomial(n, k int) float64 {
+	if k > n {
+		return 0
+	}
+
+	res := 1.0
+	for i := 1; i <= k; i++ {
+		res = res * float64(n-k+i) / float64(i)
+	}
+
+	return res
+}
+
+func factorial(n int) float64 {
+	res := 1.0
+	for i := 2; i <= n; i++ {
+		res *= float64(i)
+	}
+
+	return res
+}
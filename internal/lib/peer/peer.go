@@ -0,0 +1,64 @@
+package peer
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// IP returns IP address of the gRPC client, empty string if it's unknown.
+func IP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// ClientCertName returns common name of the verified client certificate, empty string if the client has none,
+// e.g. the connection isn't mTLS.
+func ClientCertName(ctx context.Context) string {
+	cert := clientCert(ctx)
+	if cert == nil {
+		return ""
+	}
+
+	return cert.Subject.CommonName
+}
+
+// ClientCertFingerprint returns hex sha256 of the verified client certificate, empty string if the client has none.
+func ClientCertFingerprint(ctx context.Context) string {
+	cert := clientCert(ctx)
+	if cert == nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(cert.Raw)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func clientCert(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return tlsInfo.State.VerifiedChains[0][0]
+}
@@ -0,0 +1,69 @@
+package random
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// Randomizer is the source of verification codes, token IDs and secrets.
+// It's injected, so tests can make them deterministic.
+type Randomizer interface {
+	// Bytes returns n random bytes.
+	Bytes(n int) ([]byte, error)
+	// String returns n characters picked at random from alphabet.
+	String(n int, alphabet string) (string, error)
+}
+
+// Crypto is Randomizer reading crypto/rand, the only source used by the service.
+var Crypto Randomizer = New(rand.Reader)
+
+// Source is Randomizer reading random bytes from a reader.
+type Source struct {
+	r io.Reader
+}
+
+// New returns Source reading r. Readers other than crypto/rand.Reader are for tests only,
+// e.g. math/rand seeded with a constant gives the same codes on every run.
+func New(r io.Reader) *Source {
+	return &Source{r: r}
+}
+
+func (s *Source) Bytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(s.r, b); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	return b, nil
+}
+
+// String picks characters uniformly, without modulo bias.
+func (s *Source) String(n int, alphabet string) (string, error) {
+	max := big.NewInt(int64(len(alphabet)))
+
+	sb := strings.Builder{}
+	sb.Grow(n)
+	for i := 0; i < n; i++ {
+		idx, err := rand.Int(s.r, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random string: %w", err)
+		}
+		sb.WriteByte(alphabet[idx.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// Secret returns random URL-safe string of 32 characters, e.g. for app secrets.
+func Secret(r Randomizer) (string, error) {
+	b, err := r.Bytes(24)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
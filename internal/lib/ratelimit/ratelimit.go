@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Store counts hits of keys within fixed windows.
+// Single store is shared by all limiters, keys are prefixed by limiter name.
+type Store interface {
+	// Hit registers a hit of the key and returns number of hits in the current window.
+	Hit(ctx context.Context, key string, window time.Duration) (int, error)
+}
+
+// Limiter allows at most limit hits of a key per window.
+type Limiter struct {
+	store Store
+	name  string
+
+	mu     sync.RWMutex
+	limit  int
+	window time.Duration
+}
+
+// New returns a new Limiter, limit <= 0 disables limiting.
+func New(store Store, name string, limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		store:  store,
+		name:   name,
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Allow registers a hit of the key and reports whether it is within the limit.
+func (l *Limiter) Allow(ctx context.Context, key string) (bool, error) {
+	const op = "ratelimit.Allow"
+
+	l.mu.RLock()
+	limit, window := l.limit, l.window
+	l.mu.RUnlock()
+
+	if limit <= 0 || key == "" {
+		return true, nil
+	}
+
+	hits, err := l.store.Hit(ctx, l.name+":"+key, window)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits <= limit, nil
+}
+
+// SetLimit changes limit of the running limiter.
+// Hits already counted in the store are kept, new window applies to windows started after the change.
+func (l *Limiter) SetLimit(limit int, window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+	l.window = window
+}
+
+// MemoryStore is an in-process Store.
+type MemoryStore struct {
+	mu      sync.Mutex
+	windows map[string]window
+	// lastSweep is when expired windows were removed last time.
+	lastSweep time.Time
+}
+
+type window struct {
+	hits      int
+	expiresAt time.Time
+}
+
+// sweepInterval is how often expired windows are removed from MemoryStore.
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		windows:   make(map[string]window),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Hit(_ context.Context, key string, d time.Duration) (int, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) > sweepInterval {
+		for k, w := range s.windows {
+			if !now.Before(w.expiresAt) {
+				delete(s.windows, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.expiresAt) {
+		w = window{expiresAt: now.Add(d)}
+	}
+	w.hits++
+	s.windows[key] = w
+
+	return w.hits, nil
+}
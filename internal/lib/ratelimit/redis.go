@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/tracing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisPrefix namespaces keys of the service in a Redis shared with others.
+const redisPrefix = "sso:ratelimit:"
+
+// hitScript counts hits of the key within the sliding window ending now. Hits are members of a sorted set
+// scored by time of Redis, so clocks of replicas don't matter. ARGV: window in microseconds, unique member.
+var hitScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local window = tonumber(ARGV[1])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+redis.call('ZADD', KEYS[1], now, ARGV[2])
+redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+return redis.call('ZCARD', KEYS[1])
+`)
+
+// failureScript counts failure of the key, restarting the counter if the previous failure happened before resetBefore.
+// ARGV: time of the failure and resetBefore in Unix milliseconds.
+var failureScript = redis.NewScript(`
+local at = tonumber(ARGV[1])
+local resetBefore = tonumber(ARGV[2])
+local last = tonumber(redis.call('HGET', KEYS[1], 'last') or '0')
+local count = 1
+if last >= resetBefore then
+	count = tonumber(redis.call('HGET', KEYS[1], 'count') or '0') + 1
+end
+redis.call('HSET', KEYS[1], 'count', count, 'last', at)
+redis.call('PEXPIRE', KEYS[1], math.max(at - resetBefore, 1))
+return count
+`)
+
+// RedisStore is a Store and FailureStore in Redis, so limits and throttles are shared by replicas.
+//
+// Unlike MemoryStore, windows slide: Hit counts hits within the window ending now, not since the first hit.
+// Keys expire with their window, so Redis needs no cleanup.
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Hit(ctx context.Context, key string, window time.Duration) (int, error) {
+	const op = "ratelimit.RedisStore.Hit"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	// Member only needs to be unique among hits of the same microsecond.
+	member := strconv.FormatInt(time.Now().UnixNano(), 36) + ":" + strconv.FormatUint(rand.Uint64(), 36)
+
+	hits, err := hitScript.Run(ctx, s.client, []string{redisPrefix + "hits:" + key}, window.Microseconds(), member).Int()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, nil
+}
+
+func (s *RedisStore) LoginFailures(ctx context.Context, key string) (models.LoginFailures, error) {
+	const op = "ratelimit.RedisStore.LoginFailures"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	values, err := s.client.HMGet(ctx, redisPrefix+"failures:"+key, "count", "last").Result()
+	if err != nil {
+		return models.LoginFailures{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	failures := models.LoginFailures{Key: key}
+
+	count, _ := values[0].(string)
+	last, _ := values[1].(string)
+	if count == "" || last == "" {
+		return failures, nil
+	}
+
+	if failures.Count, err = strconv.Atoi(count); err != nil {
+		return models.LoginFailures{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	lastMilli, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return models.LoginFailures{}, fmt.Errorf("%s: %w", op, err)
+	}
+	failures.LastFailureAt = time.UnixMilli(lastMilli).UTC()
+
+	return failures, nil
+}
+
+func (s *RedisStore) RecordLoginFailure(ctx context.Context, key string, at time.Time, resetBefore time.Time) (models.LoginFailures, error) {
+	const op = "ratelimit.RedisStore.RecordLoginFailure"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	count, err := failureScript.Run(ctx, s.client, []string{redisPrefix + "failures:" + key}, at.UnixMilli(), resetBefore.UnixMilli()).Int()
+	if err != nil {
+		return models.LoginFailures{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.LoginFailures{
+		Key:           key,
+		Count:         count,
+		LastFailureAt: time.UnixMilli(at.UnixMilli()).UTC(),
+	}, nil
+}
+
+func (s *RedisStore) ResetLoginFailures(ctx context.Context, key string) error {
+	const op = "ratelimit.RedisStore.ResetLoginFailures"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := s.client.Del(ctx, redisPrefix+"failures:"+key).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
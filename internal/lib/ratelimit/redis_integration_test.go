@@ -0,0 +1,97 @@
+//go:build integration
+
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedisClient connects to Redis at SSO_TEST_REDIS_ADDR, the test is skipped if it's not set.
+// Unlike fakeRedis, the server runs the scripts, e.g. docker run --rm -p 6379:6379 redis:7.
+func newRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+
+	addr := os.Getenv("SSO_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("SSO_TEST_REDIS_ADDR is not set")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { _ = client.Close() })
+
+	require.NoError(t, client.Ping(context.Background()).Err())
+
+	return client
+}
+
+// uniqueKey returns key of the test unused by previous runs, keys expire with their windows.
+func uniqueKey(t *testing.T) string {
+	return t.Name() + ":" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+func TestRedisStoreHitIntegration(t *testing.T) {
+	ctx := context.Background()
+	limiter := New(NewRedisStore(newRedisClient(t)), "login", 2, time.Second)
+	key := uniqueKey(t)
+
+	for i := 0; i < 2; i++ {
+		allowed, err := limiter.Allow(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, err := limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = limiter.Allow(ctx, key+":other")
+	require.NoError(t, err)
+	assert.True(t, allowed, "hits are counted per key")
+
+	time.Sleep(1100 * time.Millisecond)
+
+	allowed, err = limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, allowed, "hits leave the window")
+}
+
+func TestRedisStoreFailuresIntegration(t *testing.T) {
+	ctx := context.Background()
+	store := NewRedisStore(newRedisClient(t))
+	key := uniqueKey(t)
+	at := time.Now().UTC().Truncate(time.Millisecond)
+
+	failures, err := store.LoginFailures(ctx, key)
+	require.NoError(t, err)
+	assert.Zero(t, failures.Count)
+
+	for i := 1; i <= 2; i++ {
+		failures, err = store.RecordLoginFailure(ctx, key, at, at.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, i, failures.Count)
+	}
+
+	failures, err = store.LoginFailures(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 2, failures.Count)
+	assert.Equal(t, at, failures.LastFailureAt)
+
+	later := at.Add(2 * time.Hour)
+	failures, err = store.RecordLoginFailure(ctx, key, later, later.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures.Count, "counter restarts after the reset period")
+
+	require.NoError(t, store.ResetLoginFailures(ctx, key))
+
+	failures, err = store.LoginFailures(ctx, key)
+	require.NoError(t, err)
+	assert.Zero(t, failures.Count)
+}
@@ -0,0 +1,186 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis answers commands sent by RedisStore without a server, scripts are emulated by their SHA.
+// It checks what the store sends and how replies are parsed, not the Lua of the scripts.
+type fakeRedis struct {
+	hits     map[string][]time.Time
+	failures map[string][2]int64
+	keys     []string
+	err      error
+}
+
+func newFakeRedis(t *testing.T) (*fakeRedis, *redis.Client) {
+	t.Helper()
+
+	f := &fakeRedis{hits: make(map[string][]time.Time), failures: make(map[string][2]int64)}
+
+	client := redis.NewClient(&redis.Options{Addr: "fake:6379"})
+	client.AddHook(f)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return f, client
+}
+
+func (f *fakeRedis) DialHook(redis.DialHook) redis.DialHook {
+	return func(context.Context, string, string) (net.Conn, error) {
+		return nil, fmt.Errorf("fake redis doesn't dial")
+	}
+}
+
+func (f *fakeRedis) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (f *fakeRedis) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(_ context.Context, cmd redis.Cmder) error {
+		if f.err != nil {
+			cmd.SetErr(f.err)
+
+			return f.err
+		}
+
+		args := cmd.Args()
+		switch cmd.Name() {
+		case "evalsha":
+			key := args[3].(string)
+			f.keys = append(f.keys, key)
+
+			switch args[1] {
+			case hitScript.Hash():
+				window := time.Duration(args[4].(int64)) * time.Microsecond
+				now := time.Now()
+
+				hits := []time.Time{now}
+				for _, at := range f.hits[key] {
+					if now.Sub(at) < window {
+						hits = append(hits, at)
+					}
+				}
+				f.hits[key] = hits
+				cmd.(*redis.Cmd).SetVal(int64(len(hits)))
+			case failureScript.Hash():
+				at, resetBefore := args[4].(int64), args[5].(int64)
+
+				count := int64(1)
+				if last := f.failures[key]; last[1] >= resetBefore {
+					count = last[0] + 1
+				}
+				f.failures[key] = [2]int64{count, at}
+				cmd.(*redis.Cmd).SetVal(count)
+			}
+		case "hmget":
+			key := args[1].(string)
+			f.keys = append(f.keys, key)
+
+			values := []interface{}{nil, nil}
+			if failures, ok := f.failures[key]; ok {
+				values = []interface{}{strconv.FormatInt(failures[0], 10), strconv.FormatInt(failures[1], 10)}
+			}
+			cmd.(*redis.SliceCmd).SetVal(values)
+		case "del":
+			key := args[1].(string)
+			f.keys = append(f.keys, key)
+
+			delete(f.failures, key)
+			cmd.(*redis.IntCmd).SetVal(1)
+		default:
+			err := fmt.Errorf("unexpected command %s", cmd.Name())
+			cmd.SetErr(err)
+
+			return err
+		}
+
+		return nil
+	}
+}
+
+func TestRedisStoreHit(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeRedis(t)
+	limiter := New(NewRedisStore(client), "login", 2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		allowed, err := limiter.Allow(ctx, "203.0.113.1")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, err := limiter.Allow(ctx, "203.0.113.1")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = limiter.Allow(ctx, "203.0.113.2")
+	require.NoError(t, err)
+	assert.True(t, allowed, "hits are counted per key")
+
+	assert.Equal(t, "sso:ratelimit:hits:login:203.0.113.1", f.keys[0])
+}
+
+func TestRedisStoreFailures(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeRedis(t)
+	store := NewRedisStore(client)
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	failures, err := store.LoginFailures(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, models.LoginFailures{Key: "user@example.com"}, failures)
+
+	for i := 1; i <= 2; i++ {
+		failures, err = store.RecordLoginFailure(ctx, "user@example.com", at, at.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, models.LoginFailures{Key: "user@example.com", Count: i, LastFailureAt: at}, failures)
+	}
+
+	failures, err = store.LoginFailures(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, models.LoginFailures{Key: "user@example.com", Count: 2, LastFailureAt: at}, failures)
+
+	later := at.Add(2 * time.Hour)
+	failures, err = store.RecordLoginFailure(ctx, "user@example.com", later, later.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures.Count, "counter restarts after the reset period")
+
+	require.NoError(t, store.ResetLoginFailures(ctx, "user@example.com"))
+
+	failures, err = store.LoginFailures(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, failures.Count)
+
+	for _, key := range f.keys {
+		assert.Equal(t, "sso:ratelimit:failures:user@example.com", key)
+	}
+}
+
+func TestRedisStoreFailure(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeRedis(t)
+	f.err = assert.AnError
+	store := NewRedisStore(client)
+
+	_, err := store.Hit(ctx, "login:203.0.113.1", time.Minute)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	_, err = store.LoginFailures(ctx, "user@example.com")
+	assert.ErrorIs(t, err, assert.AnError)
+
+	_, err = store.RecordLoginFailure(ctx, "user@example.com", time.Now(), time.Now())
+	assert.ErrorIs(t, err, assert.AnError)
+
+	assert.ErrorIs(t, store.ResetLoginFailures(ctx, "user@example.com"), assert.AnError)
+}
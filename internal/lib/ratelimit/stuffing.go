@@ -0,0 +1,189 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"grpc-service-ref/internal/events"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+)
+
+// Scopes of credential stuffing detections.
+const (
+	StuffingScopeGlobal = "global"
+	StuffingScopeIP     = "ip"
+)
+
+// StuffingPolicy configures StuffingMonitor, 0 thresholds disable the scope.
+type StuffingPolicy struct {
+	// Window is the window failures are counted in, see Store.
+	Window time.Duration
+	// GlobalThreshold failures of all clients per window put every login under defense.
+	GlobalThreshold int
+	// IPThreshold failures of a client IP per window put its logins under defense.
+	IPThreshold int
+	// Cooldown is how long defense lasts since the last window exceeding the threshold.
+	Cooldown time.Duration
+	// Captcha defends by requiring captcha of the logins, otherwise by rejecting logins of IPs under defense.
+	Captcha bool
+}
+
+// EventPublisher publishes domain events, see events.Bus.
+type EventPublisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
+// StuffingMonitor detects credential stuffing by failed login rates of all clients and of client IPs,
+// which stay below per-account throttles when attackers try each leaked credential once.
+//
+// Once a rate exceeds its threshold, logins are defended for Cooldown: with captcha enabled, logins of the IP,
+// or all logins on global detection, must pass captcha. Without captcha, logins of the IP are rejected until
+// the cooldown ends, and global detection halves IPThreshold instead. Detections are published as
+// events.CredentialStuffingDetected.
+//
+// Failures are counted in the shared Store, defense state is per process: each replica enters defense
+// once it sees a failure over the threshold.
+type StuffingMonitor struct {
+	log       *slog.Logger
+	store     Store
+	publisher EventPublisher
+	policy    StuffingPolicy
+
+	mu          sync.Mutex
+	globalUntil time.Time
+	ipUntil     map[string]time.Time
+}
+
+func NewStuffingMonitor(log *slog.Logger, store Store, publisher EventPublisher, policy StuffingPolicy) *StuffingMonitor {
+	return &StuffingMonitor{
+		log:       log,
+		store:     store,
+		publisher: publisher,
+		policy:    policy,
+		ipUntil:   make(map[string]time.Time),
+	}
+}
+
+// Check returns decision on the next login attempt of the client IP.
+func (m *StuffingMonitor) Check(_ context.Context, ip string) (Decision, error) {
+	now := time.Now().UTC()
+
+	m.mu.Lock()
+	ipUntil, global := m.ipUntil[ip], now.Before(m.globalUntil)
+	m.mu.Unlock()
+
+	switch {
+	case ip != "" && now.Before(ipUntil):
+		if m.policy.Captcha {
+			return Decision{CaptchaRequired: true}, nil
+		}
+
+		metrics.Throttled.WithLabelValues("login_stuffing", metrics.ThrottleLocked).Inc()
+
+		return Decision{RetryAfter: ipUntil.Sub(now), Locked: true}, nil
+	case global && m.policy.Captcha:
+		return Decision{CaptchaRequired: true}, nil
+	default:
+		return Decision{}, nil
+	}
+}
+
+// Fail counts failed login of the client IP to the global and IP rates.
+//
+// Failing to count is logged, but doesn't change the result of the attempt.
+func (m *StuffingMonitor) Fail(ctx context.Context, ip string) {
+	const op = "ratelimit.StuffingMonitor.Fail"
+
+	log := m.log.With(slog.String("op", op))
+
+	now := time.Now().UTC()
+
+	if m.policy.GlobalThreshold > 0 {
+		failures, err := m.store.Hit(ctx, "login_stuffing:global", m.policy.Window)
+		if err != nil {
+			log.Error("failed to count global failure", sl.Err(err))
+		} else if failures > m.policy.GlobalThreshold {
+			m.detect(ctx, StuffingScopeGlobal, "", failures, now)
+		}
+	}
+
+	if m.policy.IPThreshold <= 0 || ip == "" {
+		return
+	}
+
+	failures, err := m.store.Hit(ctx, "login_stuffing:ip:"+ip, m.policy.Window)
+	if err != nil {
+		log.Error("failed to count ip failure", sl.Err(err))
+
+		return
+	}
+
+	threshold := m.policy.IPThreshold
+
+	// Without captcha global defense can't challenge every login, so it tightens the per-IP rate instead.
+	m.mu.Lock()
+	if !m.policy.Captcha && now.Before(m.globalUntil) {
+		threshold = max(threshold/2, 1)
+	}
+	m.mu.Unlock()
+
+	if failures > threshold {
+		m.detect(ctx, StuffingScopeIP, ip, failures, now)
+	}
+}
+
+// Reset does nothing: successful login of one account doesn't make the rates of its client less suspicious.
+func (m *StuffingMonitor) Reset(context.Context, string) {}
+
+// detect starts or extends defense of the scope, new defense is logged, counted and published.
+func (m *StuffingMonitor) detect(ctx context.Context, scope string, ip string, failures int, now time.Time) {
+	const op = "ratelimit.StuffingMonitor.detect"
+
+	until := now.Add(m.policy.Cooldown)
+
+	m.mu.Lock()
+	var started bool
+	if scope == StuffingScopeGlobal {
+		started = !now.Before(m.globalUntil)
+		m.globalUntil = until
+	} else {
+		started = !now.Before(m.ipUntil[ip])
+		m.ipUntil[ip] = until
+
+		// Defense of other IPs may have ended since, forget them so the map doesn't grow with every attacker.
+		if started {
+			for key, ipUntil := range m.ipUntil {
+				if !now.Before(ipUntil) {
+					delete(m.ipUntil, key)
+				}
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	if !started {
+		return
+	}
+
+	m.log.Warn("credential stuffing detected",
+		slog.String("op", op),
+		slog.String("scope", scope),
+		slog.String("ip", ip),
+		slog.Int("failures", failures),
+		slog.Duration("window", m.policy.Window),
+		slog.Time("until", until),
+	)
+
+	metrics.StuffingDetections.WithLabelValues(scope).Inc()
+
+	m.publisher.Publish(ctx, events.CredentialStuffingDetected{
+		Scope:    scope,
+		IP:       ip,
+		Failures: failures,
+		Window:   m.policy.Window,
+		Until:    until,
+	})
+}
@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+)
+
+// FailureStore counts failures of keys.
+// Single store is shared by all throttles, keys are prefixed by throttle name.
+type FailureStore interface {
+	// LoginFailures returns failures of the key, zero count if there are none.
+	LoginFailures(ctx context.Context, key string) (models.LoginFailures, error)
+	// RecordLoginFailure counts failure of the key, counter is restarted if the previous failure happened before resetBefore.
+	RecordLoginFailure(ctx context.Context, key string, at time.Time, resetBefore time.Time) (models.LoginFailures, error)
+	ResetLoginFailures(ctx context.Context, key string) error
+}
+
+// Policy escalates throttling as failures of a key accumulate, 0 thresholds disable the step.
+type Policy struct {
+	// DelayAfter failures, next attempt is allowed only once BaseDelay passes since the last failure.
+	// The delay doubles with every further failure up to MaxDelay.
+	DelayAfter int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// CaptchaAfter failures, attempts must pass captcha.
+	CaptchaAfter int
+	// LockoutAfter failures, attempts are rejected for LockoutDuration since the last failure.
+	LockoutAfter    int
+	LockoutDuration time.Duration
+}
+
+// Decision is what throttle requires of the next attempt.
+type Decision struct {
+	// RetryAfter is how long the attempt must wait, 0 means it's allowed now.
+	RetryAfter time.Duration
+	// Locked reports that the wait is a lockout, not a delay.
+	Locked          bool
+	CaptchaRequired bool
+}
+
+// Allowed reports whether the attempt may proceed now.
+func (d Decision) Allowed() bool {
+	return d.RetryAfter <= 0
+}
+
+// Throttle slows down and locks out keys with repeated failures, e.g. failed logins per account.
+// Failures are forgotten once window passes since the last one.
+type Throttle struct {
+	log    *slog.Logger
+	store  FailureStore
+	name   string
+	policy Policy
+	window time.Duration
+}
+
+func NewThrottle(log *slog.Logger, store FailureStore, name string, policy Policy, window time.Duration) *Throttle {
+	return &Throttle{
+		log:    log,
+		store:  store,
+		name:   name,
+		policy: policy,
+		window: window,
+	}
+}
+
+// Check returns decision on the next attempt of the key.
+func (t *Throttle) Check(ctx context.Context, key string) (Decision, error) {
+	const op = "ratelimit.Throttle.Check"
+
+	if key == "" {
+		return Decision{}, nil
+	}
+
+	failures, err := t.store.LoginFailures(ctx, t.name+":"+key)
+	if err != nil {
+		return Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	decision := t.decide(failures, time.Now().UTC())
+
+	switch {
+	case decision.Locked:
+		metrics.Throttled.WithLabelValues(t.name, metrics.ThrottleLocked).Inc()
+	case !decision.Allowed():
+		metrics.Throttled.WithLabelValues(t.name, metrics.ThrottleDelayed).Inc()
+	}
+
+	return decision, nil
+}
+
+// Fail counts failed attempt of the key.
+//
+// Failing to count is logged, but doesn't change the result of the attempt.
+func (t *Throttle) Fail(ctx context.Context, key string) {
+	const op = "ratelimit.Throttle.Fail"
+
+	if key == "" {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	failures, err := t.store.RecordLoginFailure(ctx, t.name+":"+key, now, now.Add(-t.window))
+	if err != nil {
+		t.log.Error("failed to record failure", slog.String("op", op), slog.String("throttle", t.name), sl.Err(err))
+
+		return
+	}
+
+	if t.policy.LockoutAfter > 0 && failures.Count == t.policy.LockoutAfter {
+		t.log.Warn("key is locked out",
+			slog.String("op", op),
+			slog.String("throttle", t.name),
+			slog.String("key", key),
+			slog.Duration("duration", t.policy.LockoutDuration),
+		)
+	}
+}
+
+// Reset forgets failures of the key, e.g. after successful attempt.
+//
+// Failing to reset is logged, the failures expire with window anyway.
+func (t *Throttle) Reset(ctx context.Context, key string) {
+	const op = "ratelimit.Throttle.Reset"
+
+	if key == "" {
+		return
+	}
+
+	if err := t.store.ResetLoginFailures(ctx, t.name+":"+key); err != nil {
+		t.log.Error("failed to reset failures", slog.String("op", op), slog.String("throttle", t.name), sl.Err(err))
+	}
+}
+
+func (t *Throttle) decide(failures models.LoginFailures, now time.Time) Decision {
+	p := t.policy
+
+	if failures.Count == 0 || now.Sub(failures.LastFailureAt) >= t.window {
+		return Decision{}
+	}
+
+	var decision Decision
+
+	if p.CaptchaAfter > 0 && failures.Count >= p.CaptchaAfter {
+		decision.CaptchaRequired = true
+	}
+
+	if p.LockoutAfter > 0 && failures.Count >= p.LockoutAfter {
+		if wait := failures.LastFailureAt.Add(p.LockoutDuration).Sub(now); wait > 0 {
+			decision.RetryAfter = wait
+			decision.Locked = true
+
+			return decision
+		}
+	}
+
+	if p.DelayAfter > 0 && failures.Count >= p.DelayAfter {
+		if wait := failures.LastFailureAt.Add(p.delay(failures.Count)).Sub(now); wait > 0 {
+			decision.RetryAfter = wait
+		}
+	}
+
+	return decision
+}
+
+// delay returns delay after the given number of failures: base delay doubled per failure over DelayAfter, capped by max.
+func (p Policy) delay(failures int) time.Duration {
+	delay := p.BaseDelay
+	for i := p.DelayAfter; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
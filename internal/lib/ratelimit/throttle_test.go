@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memFailures is a FailureStore kept in memory.
+type memFailures map[string]models.LoginFailures
+
+func (m memFailures) LoginFailures(_ context.Context, key string) (models.LoginFailures, error) {
+	return m[key], nil
+}
+
+func (m memFailures) RecordLoginFailure(_ context.Context, key string, at time.Time, resetBefore time.Time) (models.LoginFailures, error) {
+	failures := m[key]
+	if failures.LastFailureAt.Before(resetBefore) {
+		failures.Count = 0
+	}
+
+	failures.Key = key
+	failures.Count++
+	failures.LastFailureAt = at
+	m[key] = failures
+
+	return failures, nil
+}
+
+func (m memFailures) ResetLoginFailures(_ context.Context, key string) error {
+	delete(m, key)
+
+	return nil
+}
+
+var testPolicy = Policy{
+	DelayAfter:      3,
+	BaseDelay:       time.Second,
+	MaxDelay:        8 * time.Second,
+	CaptchaAfter:    5,
+	LockoutAfter:    10,
+	LockoutDuration: 15 * time.Minute,
+}
+
+func TestThrottleDecide(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	throttle := NewThrottle(slog.New(slog.NewTextHandler(io.Discard, nil)), memFailures{}, "login", testPolicy, time.Hour)
+
+	tests := []struct {
+		name     string
+		count    int
+		since    time.Duration
+		policy   *Policy
+		want     Decision
+		wantOpen bool
+	}{
+		{name: "no failures", count: 0, wantOpen: true},
+		{name: "below delay", count: 2, wantOpen: true},
+		{name: "first delay", count: 3, want: Decision{RetryAfter: time.Second}},
+		{name: "delay doubles", count: 4, want: Decision{RetryAfter: 2 * time.Second}},
+		{name: "delay passed", count: 4, since: 2 * time.Second, wantOpen: true},
+		{name: "captcha", count: 5, want: Decision{RetryAfter: 4 * time.Second, CaptchaRequired: true}},
+		{name: "delay capped", count: 9, want: Decision{RetryAfter: 8 * time.Second, CaptchaRequired: true}},
+		{name: "locked", count: 10, since: time.Minute, want: Decision{RetryAfter: 14 * time.Minute, Locked: true, CaptchaRequired: true}},
+		{name: "lockout passed", count: 10, since: 15 * time.Minute, want: Decision{CaptchaRequired: true}},
+		{name: "window passed", count: 10, since: time.Hour, wantOpen: true},
+		{name: "disabled steps", count: 100, policy: &Policy{}, wantOpen: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := *throttle
+			if tt.policy != nil {
+				th.policy = *tt.policy
+			}
+
+			got := th.decide(models.LoginFailures{Count: tt.count, LastFailureAt: now.Add(-tt.since)}, now)
+			if tt.wantOpen {
+				assert.Equal(t, Decision{}, got)
+				assert.True(t, got.Allowed())
+
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want.RetryAfter == 0, got.Allowed())
+		})
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	ctx := context.Background()
+	store := memFailures{}
+	throttle := NewThrottle(slog.New(slog.NewTextHandler(io.Discard, nil)), store, "login", testPolicy, time.Hour)
+
+	for i := 0; i < testPolicy.DelayAfter-1; i++ {
+		throttle.Fail(ctx, "user@example.com")
+	}
+
+	decision, err := throttle.Check(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed())
+
+	throttle.Fail(ctx, "user@example.com")
+
+	decision, err = throttle.Check(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed(), "attempt right after failure is delayed")
+
+	decision, err = throttle.Check(ctx, "other@example.com")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed(), "failures are counted per key")
+
+	assert.Contains(t, store, "login:user@example.com", "keys are prefixed by throttle name")
+
+	throttle.Reset(ctx, "user@example.com")
+
+	decision, err = throttle.Check(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed())
+
+	throttle.Fail(ctx, "")
+	decision, err = throttle.Check(ctx, "")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed(), "empty keys aren't throttled")
+	assert.NotContains(t, store, "login:")
+}
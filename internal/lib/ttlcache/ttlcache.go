@@ -0,0 +1,116 @@
+package ttlcache
+
+import (
+	"sync"
+	"time"
+
+	"grpc-service-ref/internal/lib/metrics"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache is an in-process cache with entries expiring after TTL.
+// Hits, misses and invalidations are counted by metrics labeled with name of the cache.
+type Cache[K comparable, V any] struct {
+	name string
+	ttl  time.Duration
+	// maxEntries bounds memory, expired entries are swept once it's reached.
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[K]entry[V]
+	// generation is incremented on every invalidation, so values loaded before it aren't cached.
+	generation uint64
+}
+
+// New returns Cache, it doesn't cache anything if ttl is zero.
+func New[K comparable, V any](name string, ttl time.Duration, maxEntries int) *Cache[K, V] {
+	return &Cache[K, V]{
+		name:       name,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[K]entry[V]),
+	}
+}
+
+// Load returns cached value of the key or loads and caches it. Errors are not cached.
+// Value is not cached if the cache is invalidated while it's loaded, since it may be stale.
+func (c *Cache[K, V]) Load(key K, load func() (V, error)) (V, error) {
+	if c.ttl <= 0 {
+		return load()
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && time.Now().Before(e.expiresAt) {
+		c.mu.Unlock()
+		metrics.CacheHits.WithLabelValues(c.name).Inc()
+
+		return e.value, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	metrics.CacheMisses.WithLabelValues(c.name).Inc()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation == generation {
+		c.set(key, value)
+	}
+
+	return value, nil
+}
+
+func (c *Cache[K, V]) set(key K, value V) {
+	now := time.Now()
+
+	if len(c.entries) >= c.maxEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	// Still full of live entries: drop a random one, map iteration order is random.
+	if len(c.entries) >= c.maxEntries {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// Delete invalidates the key.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	c.generation++
+
+	metrics.CacheInvalidations.WithLabelValues(c.name).Inc()
+}
+
+// Purge invalidates all keys.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[K]entry[V])
+	c.generation++
+
+	metrics.CacheInvalidations.WithLabelValues(c.name).Inc()
+}
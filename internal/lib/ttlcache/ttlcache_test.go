@@ -0,0 +1,120 @@
+package ttlcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loader counts loads of cached values.
+type loader struct {
+	value string
+	err   error
+	calls int
+}
+
+func (l *loader) load() (string, error) {
+	l.calls++
+
+	return l.value, l.err
+}
+
+func TestLoad(t *testing.T) {
+	c := New[int, string]("test", time.Minute, 10)
+	l := &loader{value: "a"}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.Load(1, l.load)
+		require.NoError(t, err)
+		assert.Equal(t, "a", got)
+	}
+	assert.Equal(t, 1, l.calls, "value is cached")
+
+	_, err := c.Load(2, l.load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, l.calls, "keys are cached separately")
+}
+
+func TestLoadError(t *testing.T) {
+	c := New[int, string]("test", time.Minute, 10)
+	l := &loader{err: assert.AnError}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Load(1, l.load)
+		assert.ErrorIs(t, err, assert.AnError)
+	}
+	assert.Equal(t, 2, l.calls, "errors are not cached")
+}
+
+func TestLoadDisabled(t *testing.T) {
+	c := New[int, string]("test", 0, 10)
+	l := &loader{value: "a"}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Load(1, l.load)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, l.calls)
+}
+
+func TestLoadExpired(t *testing.T) {
+	c := New[int, string]("test", time.Millisecond, 10)
+	l := &loader{value: "a"}
+
+	_, err := c.Load(1, l.load)
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = c.Load(1, l.load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, l.calls)
+}
+
+func TestInvalidate(t *testing.T) {
+	c := New[int, string]("test", time.Minute, 10)
+	l := &loader{value: "a"}
+
+	_, _ = c.Load(1, l.load)
+	_, _ = c.Load(2, l.load)
+
+	c.Delete(1)
+	_, _ = c.Load(1, l.load)
+	_, _ = c.Load(2, l.load)
+	assert.Equal(t, 3, l.calls, "only the deleted key is reloaded")
+
+	c.Purge()
+	_, _ = c.Load(1, l.load)
+	_, _ = c.Load(2, l.load)
+	assert.Equal(t, 5, l.calls, "all keys are reloaded")
+}
+
+func TestInvalidateWhileLoading(t *testing.T) {
+	c := New[int, string]("test", time.Minute, 10)
+
+	got, err := c.Load(1, func() (string, error) {
+		c.Delete(1)
+
+		return "stale", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "stale", got)
+
+	l := &loader{value: "fresh"}
+	got, err = c.Load(1, l.load)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", got, "value loaded before invalidation isn't cached")
+}
+
+func TestMaxEntries(t *testing.T) {
+	c := New[int, string]("test", time.Minute, 2)
+	l := &loader{value: "a"}
+
+	for key := 0; key < 5; key++ {
+		_, err := c.Load(key, l.load)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(c.entries), 2)
+	}
+}
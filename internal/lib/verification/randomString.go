@@ -1,19 +1,95 @@
 package verification
 
 import (
-	"math/rand"
-	"strings"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/random"
+)
+
+const (
+	CharsetLetters      = "letters"
+	CharsetDigits       = "digits"
+	CharsetAlphanumeric = "alphanumeric"
 )
 
-const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+const (
+	letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitBytes  = "0123456789"
+)
+
+// CodeFormat describes verification codes of some kind: how they look and how long they live,
+// e.g. 6 digits valid for 15 minutes for codes typed from a phone.
+type CodeFormat struct {
+	Len     int
+	Charset string
+	TTL     time.Duration
+}
+
+// Generate generates a code in the format.
+func (f CodeFormat) Generate(r random.Randomizer) (string, error) {
+	return GenerateRandomString(r, f.Len, f.Charset)
+}
+
+// CodeFormats holds code formats of verification types.
+type CodeFormats struct {
+	Default CodeFormat
+	ByType  map[models.VerificationType]CodeFormat
+}
+
+// For returns code format of the verification type, falling back to the default one.
+func (f CodeFormats) For(vType models.VerificationType) CodeFormat {
+	if format, ok := f.ByType[vType]; ok {
+		return format
+	}
+
+	return f.Default
+}
 
-// GenerateRandomString generate a string of random characters of given length
-func GenerateRandomString(n int) string {
-	sb := strings.Builder{}
-	sb.Grow(n)
-	for i := 0; i < n; i++ {
-		idx := rand.Int63() % int64(len(letterBytes))
-		sb.WriteByte(letterBytes[idx])
+// GenerateRandomString generate a string of random characters of given length.
+// Characters are taken from the given charset with r.
+func GenerateRandomString(r random.Randomizer, n int, charset string) (string, error) {
+	alphabet, err := charsetBytes(charset)
+	if err != nil {
+		return "", err
 	}
-	return sb.String()
+
+	return r.String(n, alphabet)
+}
+
+func charsetBytes(charset string) (string, error) {
+	switch charset {
+	case CharsetLetters, "":
+		return letterBytes, nil
+	case CharsetDigits:
+		return digitBytes, nil
+	case CharsetAlphanumeric:
+		return letterBytes + digitBytes, nil
+	default:
+		return "", fmt.Errorf("unknown charset %q", charset)
+	}
+}
+
+// ReloadableCodeFormats holds CodeFormats which can be replaced while the service is running.
+type ReloadableCodeFormats struct {
+	formats atomic.Pointer[CodeFormats]
+}
+
+func NewReloadableCodeFormats(formats CodeFormats) *ReloadableCodeFormats {
+	r := &ReloadableCodeFormats{}
+	r.Set(formats)
+
+	return r
+}
+
+// Set replaces code formats, codes already sent keep their TTL.
+func (r *ReloadableCodeFormats) Set(formats CodeFormats) {
+	r.formats.Store(&formats)
+}
+
+// For returns code format of the verification type, see CodeFormats.For.
+func (r *ReloadableCodeFormats) For(vType models.VerificationType) CodeFormat {
+	return r.formats.Load().For(vType)
 }
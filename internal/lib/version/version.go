@@ -0,0 +1,47 @@
+package version
+
+import "runtime/debug"
+
+// Set at build time with -ldflags, e.g.
+//
+//	-X grpc-service-ref/internal/lib/version.Version=v1.2.3
+//	-X grpc-service-ref/internal/lib/version.Commit=0a1b2c3
+//	-X grpc-service-ref/internal/lib/version.BuildDate=2024-01-02T15:04:05Z
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns info of the running build.
+// Commit and build date fall back to the VCS revision and commit time recorded by go build.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = s.Value
+			}
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,40 @@
+package secrets
+
+import (
+	"context"
+	"strconv"
+
+	"grpc-service-ref/internal/domain/models"
+)
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+// Apps overrides secrets of apps stored in db with the ones stored in Vault,
+// so JWT signing keys don't have to be kept in db.
+type Apps struct {
+	apps AppProvider
+	// secrets are keyed by app ID.
+	secrets map[string]string
+}
+
+func NewApps(apps AppProvider, secrets map[string]string) *Apps {
+	return &Apps{
+		apps:    apps,
+		secrets: secrets,
+	}
+}
+
+func (a *Apps) App(ctx context.Context, appID int) (models.App, error) {
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		return models.App{}, err
+	}
+
+	if secret, ok := a.secrets[strconv.Itoa(appID)]; ok {
+		app.Secret = secret
+	}
+
+	return app, nil
+}
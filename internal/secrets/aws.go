@@ -0,0 +1,61 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSSecretsManager returns secrets of AWS Secrets Manager referenced as
+// aws-sm://<secret-id>[#<json-key>], secret ID is a name or ARN.
+type AWSSecretsManager struct {
+	client *secretsmanager.Client
+}
+
+// NewAWSSecretsManager uses default AWS credentials chain, empty region falls back to AWS_REGION.
+func NewAWSSecretsManager(ctx context.Context, region string) (*AWSSecretsManager, error) {
+	const op = "secrets.NewAWSSecretsManager"
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &AWSSecretsManager{client: secretsmanager.NewFromConfig(cfg)}, nil
+}
+
+func (m *AWSSecretsManager) Secret(ctx context.Context, ref string) (string, error) {
+	const op = "AWSSecretsManager.Secret"
+
+	id, key := splitKey(ref)
+
+	out, err := m.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(id),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var secret string
+	switch {
+	case out.SecretString != nil:
+		secret = *out.SecretString
+	default:
+		secret = string(out.SecretBinary)
+	}
+
+	val, err := jsonKey(secret, key)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s: %w", op, id, err)
+	}
+
+	return val, nil
+}
@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	gcpTokenURL  = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	gcpAccessURL = "https://secretmanager.googleapis.com/v1/projects/%s/secrets/%s/versions/%s:access"
+)
+
+// GCPSecretManager returns secrets of GCP Secret Manager referenced as
+// gcp-sm://<project>/<secret>[/<version>][#<json-key>], version defaults to latest.
+//
+// Access token is taken from the metadata server, so the service account attached
+// to the instance (or workload identity on GKE and Cloud Run) must be allowed to access secrets.
+type GCPSecretManager struct {
+	client *http.Client
+}
+
+func NewGCPSecretManager(timeout time.Duration) *GCPSecretManager {
+	return &GCPSecretManager{client: &http.Client{Timeout: timeout}}
+}
+
+func (m *GCPSecretManager) Secret(ctx context.Context, ref string) (string, error) {
+	const op = "GCPSecretManager.Secret"
+
+	name, key := splitKey(ref)
+
+	parts := strings.Split(name, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", fmt.Errorf("%s: reference must be gcp-sm://<project>/<secret>[/<version>]", op)
+	}
+
+	version := "latest"
+	if len(parts) == 3 {
+		version = parts[2]
+	}
+
+	token, err := m.token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(gcpAccessURL, parts[0], parts[1], version), nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var resp struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := m.do(req, &resp); err != nil {
+		return "", fmt.Errorf("%s: %s: %w", op, name, err)
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	val, err := jsonKey(string(secret), key)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s: %w", op, name, err)
+	}
+
+	return val, nil
+}
+
+func (m *GCPSecretManager) token(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := m.do(req, &resp); err != nil {
+		return "", fmt.Errorf("failed to get access token from metadata server: %w", err)
+	}
+
+	return resp.AccessToken, nil
+}
+
+func (m *GCPSecretManager) do(req *http.Request, out any) error {
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrSecretNotFound
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
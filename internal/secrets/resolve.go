@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"grpc-service-ref/internal/config"
+)
+
+// Schemes of secret URIs.
+const (
+	SchemeVault = "vault"
+	SchemeAWS   = "aws-sm"
+	SchemeGCP   = "gcp-sm"
+)
+
+// SecretProvider returns secrets referenced by URIs of some scheme.
+type SecretProvider interface {
+	// Secret returns value of the secret, ref is the URI without scheme, e.g. "sso/email-password".
+	Secret(ctx context.Context, ref string) (string, error)
+}
+
+// Resolver resolves secret URIs, e.g. aws-sm://sso/email-password, with providers registered by scheme.
+type Resolver struct {
+	providers map[string]SecretProvider
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{providers: make(map[string]SecretProvider)}
+}
+
+// Register sets provider of the scheme.
+func (r *Resolver) Register(scheme string, provider SecretProvider) {
+	r.providers[scheme] = provider
+}
+
+// Resolve returns value of the secret URI.
+// Values which are not secret URIs are returned as is with ok set to false.
+func (r *Resolver) Resolve(ctx context.Context, value string) (res string, ok bool, err error) {
+	const op = "secrets.Resolve"
+
+	scheme, ref, found := strings.Cut(value, "://")
+	if !found || !knownScheme(scheme) {
+		return value, false, nil
+	}
+
+	provider, registered := r.providers[scheme]
+	if !registered {
+		return "", true, fmt.Errorf("%s: %s secrets are not configured", op, scheme)
+	}
+
+	res, err = provider.Secret(ctx, ref)
+	if err != nil {
+		return "", true, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, true, nil
+}
+
+// ResolveConfig replaces secret fields of the config referencing secret URIs with their values.
+func ResolveConfig(ctx context.Context, cfg *config.Config, r *Resolver) error {
+	const op = "secrets.ResolveConfig"
+
+	fields := map[string]*string{
+		"storage_path":         &cfg.StoragePath,
+		"emailSender.password": &cfg.EmailService.Password,
+		"smsSender.auth_token": &cfg.SMSService.AuthToken,
+		"captcha.secret":       &cfg.Captcha.Secret,
+		"encryption.key":       &cfg.Encryption.Key,
+		"redis.password":       &cfg.Redis.Password,
+	}
+
+	for name, field := range fields {
+		val, _, err := r.Resolve(ctx, *field)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", op, name, err)
+		}
+
+		*field = val
+	}
+
+	return nil
+}
+
+func knownScheme(scheme string) bool {
+	switch scheme {
+	case SchemeVault, SchemeAWS, SchemeGCP:
+		return true
+	default:
+		return false
+	}
+}
+
+// splitKey splits "<name>#<key>" reference, key is empty if secret is used as a whole.
+func splitKey(ref string) (name string, key string) {
+	name, key, _ = strings.Cut(ref, "#")
+
+	return name, key
+}
+
+// jsonKey returns a key of the JSON object secret, or the secret itself if key is empty.
+func jsonKey(secret string, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal([]byte(secret), &values); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+
+	val, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("key %q: %w", key, ErrSecretNotFound)
+	}
+
+	return fmt.Sprint(val), nil
+}
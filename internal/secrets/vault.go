@@ -0,0 +1,293 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"grpc-service-ref/internal/lib/logger/sl"
+)
+
+const (
+	AuthMethodToken   = "token"
+	AuthMethodAppRole = "approle"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// retryInterval is how long Run waits before retrying failed token renewal.
+const retryInterval = 30 * time.Second
+
+// Vault reads secrets from KV v2 secrets engine of HashiCorp Vault.
+type Vault struct {
+	log        *slog.Logger
+	client     *http.Client
+	address    string
+	mount      string
+	authMethod string
+	roleID     string
+	secretID   string
+
+	mu        sync.RWMutex
+	token     string
+	ttl       time.Duration
+	renewable bool
+}
+
+type authResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+type lookupResponse struct {
+	Data struct {
+		TTL       int  `json:"ttl"`
+		Renewable bool `json:"renewable"`
+	} `json:"data"`
+}
+
+type kvResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+// New logs in to Vault with the given auth method.
+// Token is used for token auth method, roleID and secretID for approle.
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	address string,
+	mount string,
+	authMethod string,
+	token string,
+	roleID string,
+	secretID string,
+	timeout time.Duration,
+) (*Vault, error) {
+	const op = "secrets.New"
+
+	v := &Vault{
+		log:        log,
+		client:     &http.Client{Timeout: timeout},
+		address:    strings.TrimRight(address, "/"),
+		mount:      strings.Trim(mount, "/"),
+		authMethod: authMethod,
+		roleID:     roleID,
+		secretID:   secretID,
+		token:      token,
+	}
+
+	switch authMethod {
+	case AuthMethodToken:
+		if token == "" {
+			return nil, fmt.Errorf("%s: vault token is required", op)
+		}
+
+		var resp lookupResponse
+		if err := v.do(ctx, http.MethodGet, "/v1/auth/token/lookup-self", nil, &resp); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		v.ttl = time.Duration(resp.Data.TTL) * time.Second
+		v.renewable = resp.Data.Renewable
+	case AuthMethodAppRole:
+		if err := v.login(ctx); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown vault auth method %q", op, authMethod)
+	}
+
+	return v, nil
+}
+
+// Secrets returns all keys of the KV secret.
+func (v *Vault) Secrets(ctx context.Context, path string) (map[string]string, error) {
+	const op = "Vault.Secrets"
+
+	var resp kvResponse
+	if err := v.do(ctx, http.MethodGet, "/v1/"+v.mount+"/data/"+strings.Trim(path, "/"), nil, &resp); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res := make(map[string]string, len(resp.Data.Data))
+	for k, val := range resp.Data.Data {
+		res[k] = fmt.Sprint(val)
+	}
+
+	return res, nil
+}
+
+// Secret returns a key of the KV secret, ref is "<path>#<key>".
+func (v *Vault) Secret(ctx context.Context, ref string) (string, error) {
+	const op = "Vault.Secret"
+
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok {
+		return "", fmt.Errorf("%s: vault reference must be vault://<path>#<key>", op)
+	}
+
+	values, err := v.Secrets(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	val, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("%s: %s#%s: %w", op, path, key, ErrSecretNotFound)
+	}
+
+	return val, nil
+}
+
+// Run keeps Vault token alive until ctx is done.
+// Token is renewed when 2/3 of its TTL passed, approle logs in again once token can't be renewed.
+func (v *Vault) Run(ctx context.Context) {
+	const op = "Vault.Run"
+
+	log := v.log.With(slog.String("op", op))
+
+	for {
+		v.mu.RLock()
+		ttl := v.ttl
+		v.mu.RUnlock()
+
+		if ttl == 0 {
+			log.Info("vault token does not expire, renewal stopped")
+
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ttl * 2 / 3):
+		}
+
+		for {
+			err := v.refresh(ctx)
+			if err == nil {
+				break
+			}
+
+			log.Error("failed to refresh vault token", sl.Err(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}
+}
+
+func (v *Vault) refresh(ctx context.Context) error {
+	v.mu.RLock()
+	renewable := v.renewable
+	v.mu.RUnlock()
+
+	if !renewable {
+		if v.authMethod != AuthMethodAppRole {
+			return errors.New("vault token is not renewable")
+		}
+
+		return v.login(ctx)
+	}
+
+	var resp authResponse
+	if err := v.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", struct{}{}, &resp); err != nil {
+		if v.authMethod == AuthMethodAppRole {
+			return v.login(ctx)
+		}
+
+		return err
+	}
+
+	v.setToken(resp)
+
+	return nil
+}
+
+func (v *Vault) login(ctx context.Context) error {
+	body := map[string]string{
+		"role_id":   v.roleID,
+		"secret_id": v.secretID,
+	}
+
+	var resp authResponse
+	if err := v.do(ctx, http.MethodPost, "/v1/auth/approle/login", body, &resp); err != nil {
+		return fmt.Errorf("approle login: %w", err)
+	}
+
+	v.setToken(resp)
+
+	return nil
+}
+
+func (v *Vault) setToken(resp authResponse) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if resp.Auth.ClientToken != "" {
+		v.token = resp.Auth.ClientToken
+	}
+	v.ttl = time.Duration(resp.Auth.LeaseDuration) * time.Second
+	v.renewable = resp.Auth.Renewable
+}
+
+func (v *Vault) do(ctx context.Context, method string, path string, body any, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, v.address+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	v.mu.RLock()
+	token := v.token
+	v.mu.RUnlock()
+
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrSecretNotFound
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<10)).Decode(&vaultErr)
+
+		return fmt.Errorf("vault responded %d: %s", resp.StatusCode, strings.Join(vaultErr.Errors, "; "))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
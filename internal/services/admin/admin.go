@@ -0,0 +1,631 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/lib/random"
+)
+
+var (
+	ErrInvalidOrigin = errors.New("invalid origin")
+	ErrInvalidCSP    = errors.New("invalid content security policy")
+	// ErrInvalidTokenClaim is returned for issuer or audience with whitespace or control characters.
+	ErrInvalidTokenClaim = errors.New("invalid token claim")
+	// ErrInvalidBanExpiry is returned for bans expiring before they are made.
+	ErrInvalidBanExpiry = errors.New("ban expires in the past")
+)
+
+type UserManager interface {
+	User(ctx context.Context, email string) (models.User, error)
+	Users(ctx context.Context, afterID int64, limit int) ([]models.User, error)
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	SetAdmin(ctx context.Context, userID int64, isAdmin bool) error
+	SetUserVerified(ctx context.Context, userID int64, verified bool) error
+}
+
+type AppManager interface {
+	App(ctx context.Context, appID int) (models.App, error)
+	Apps(ctx context.Context, afterID int, limit int) ([]models.App, error)
+	SaveApp(ctx context.Context, name string, secret string) (int, error)
+	UpdateAppSecret(ctx context.Context, appID int, secret string) error
+	UpdateAppWebSettings(ctx context.Context, appID int, origins []string, csp string) error
+	UpdateAppTokenClaims(ctx context.Context, appID int, issuer string, audience string) error
+}
+
+type SessionProvider interface {
+	Sessions(ctx context.Context, filter models.SessionFilter, limit int) ([]models.Session, error)
+	RevokeUserSessions(ctx context.Context, userID int64, at time.Time) (int, error)
+	RevokeUserAppSessions(ctx context.Context, userID int64, appID int, at time.Time) (int, error)
+}
+
+// BanManager stores bans of users from apps.
+type BanManager interface {
+	SaveAppBan(ctx context.Context, ban models.AppBan) error
+	AppBans(ctx context.Context, userID int64, at time.Time) ([]models.AppBan, error)
+	DeleteAppBan(ctx context.Context, userID int64, appID int) error
+}
+
+// AppCache is invalidated when apps change, so cached secrets are not used after rotation.
+type AppCache interface {
+	InvalidateApp(appID int)
+}
+
+// UserCache is invalidated when users change, so login and admin checks see the change right away.
+type UserCache interface {
+	InvalidateUser(email string)
+	InvalidateAdmin(userID int64)
+}
+
+// Auditor records security-relevant actions to the audit log.
+type Auditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// Admin manages users and apps on behalf of operators calling AdminService.
+// Changes are audited with the admin authenticated by the access token as the actor, and with common name
+// of the operator's client certificate, if mTLS is enabled.
+type Admin struct {
+	log       *slog.Logger
+	users     UserManager
+	apps      AppManager
+	sessions  SessionProvider
+	bans      BanManager
+	appCache  AppCache
+	userCache UserCache
+	auditor   Auditor
+	clock     clock.Clock
+	random    random.Randomizer
+}
+
+func New(
+	log *slog.Logger,
+	users UserManager,
+	apps AppManager,
+	sessions SessionProvider,
+	bans BanManager,
+	appCache AppCache,
+	userCache UserCache,
+	auditor Auditor,
+	clock clock.Clock,
+	random random.Randomizer,
+) *Admin {
+	return &Admin{
+		log:       log,
+		users:     users,
+		apps:      apps,
+		sessions:  sessions,
+		bans:      bans,
+		appCache:  appCache,
+		userCache: userCache,
+		auditor:   auditor,
+		clock:     clock,
+		random:    random,
+	}
+}
+
+// User returns user by email and whether the user is admin.
+func (a *Admin) User(ctx context.Context, email string) (models.User, bool, error) {
+	const op = "Admin.User"
+
+	user, err := a.users.User(ctx, email)
+	if err != nil {
+		return models.User{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	isAdmin, err := a.users.IsAdmin(ctx, user.ID)
+	if err != nil {
+		return models.User{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, isAdmin, nil
+}
+
+// Users returns page of users ordered by ID, starting after afterID.
+func (a *Admin) Users(ctx context.Context, afterID int64, limit int) ([]models.User, error) {
+	const op = "Admin.Users"
+
+	users, err := a.users.Users(ctx, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// Sessions returns page of sessions of the user matching the filter, newest first.
+func (a *Admin) Sessions(ctx context.Context, filter models.SessionFilter, limit int) ([]models.Session, error) {
+	const op = "Admin.Sessions"
+
+	sessions, err := a.sessions.Sessions(ctx, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSessions revokes tokens of the user in the app, or in all apps if appID is 0, and returns how many
+// were revoked, e.g. after the account is compromised. The user signs in again to get new tokens.
+func (a *Admin) RevokeSessions(ctx context.Context, userID int64, appID int) (int, error) {
+	const op = "Admin.RevokeSessions"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", appID),
+	)
+
+	if _, err := a.users.UserByID(ctx, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now().UTC()
+
+	var (
+		revoked int
+		err     error
+	)
+	if appID == 0 {
+		revoked, err = a.sessions.RevokeUserSessions(ctx, userID, now)
+	} else {
+		if _, err := a.apps.App(ctx, appID); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		revoked, err = a.sessions.RevokeUserAppSessions(ctx, userID, appID, now)
+	}
+	if err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("sessions revoked", slog.Int("revoked", revoked))
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionSessionsRevoked,
+		Subject: strconv.FormatInt(userID, 10),
+		AppID:   appID,
+		Payload: map[string]string{"revoked": strconv.Itoa(revoked)},
+	})
+
+	return revoked, nil
+}
+
+// SetAdmin grants or revokes admin role of the user.
+func (a *Admin) SetAdmin(ctx context.Context, userID int64, isAdmin bool) error {
+	const op = "Admin.SetAdmin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := a.users.SetAdmin(ctx, userID, isAdmin); err != nil {
+		log.Error("failed to set admin role", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.userCache.InvalidateAdmin(userID)
+
+	log.Info("admin role changed", slog.Bool("is_admin", isAdmin))
+
+	change := "granted"
+	if !isAdmin {
+		change = "revoked"
+	}
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionRoleChanged,
+		Subject: strconv.FormatInt(userID, 10),
+		Payload: map[string]string{"role": "admin", "change": change},
+	})
+
+	return nil
+}
+
+// SetUserVerified marks email of the user verified or not, e.g. when verification email can't be delivered.
+// The change is audited with the reason.
+func (a *Admin) SetUserVerified(ctx context.Context, userID int64, verified bool, reason string) error {
+	const op = "Admin.SetUserVerified"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	user, err := a.users.UserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.users.SetUserVerified(ctx, userID, verified); err != nil {
+		log.Error("failed to set user verified", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.userCache.InvalidateUser(user.Email)
+
+	log.Info("user verification changed", slog.Bool("verified", verified))
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionVerificationChanged,
+		Subject: strconv.FormatInt(userID, 10),
+		Payload: map[string]string{
+			"verified":          strconv.FormatBool(verified),
+			"previous_verified": strconv.FormatBool(user.Verified),
+			"reason":            reason,
+		},
+	})
+
+	return nil
+}
+
+// BanUser bans the user from the app until expiresAt, zero expiresAt means until the ban is lifted by UnbanUser.
+// Existing ban is replaced. Tokens of the user in the app are revoked, so the ban takes effect right away,
+// other apps remain usable.
+func (a *Admin) BanUser(
+	ctx context.Context,
+	userID int64,
+	appID int,
+	reason string,
+	expiresAt time.Time,
+) (models.AppBan, error) {
+	const op = "Admin.BanUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", appID),
+	)
+
+	now := a.clock.Now().UTC()
+	if !expiresAt.IsZero() && !expiresAt.After(now) {
+		return models.AppBan{}, fmt.Errorf("%s: %w", op, ErrInvalidBanExpiry)
+	}
+
+	if _, err := a.users.UserByID(ctx, userID); err != nil {
+		return models.AppBan{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.apps.App(ctx, appID); err != nil {
+		return models.AppBan{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ban := models.AppBan{
+		UserID:    userID,
+		AppID:     appID,
+		Reason:    reason,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := a.bans.SaveAppBan(ctx, ban); err != nil {
+		log.Error("failed to save ban", sl.Err(err))
+
+		return models.AppBan{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	revoked, err := a.sessions.RevokeUserAppSessions(ctx, userID, appID, now)
+	if err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+
+		return models.AppBan{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user banned", slog.Int("revoked", revoked))
+
+	payload := map[string]string{"reason": reason, "revoked": strconv.Itoa(revoked)}
+	if !expiresAt.IsZero() {
+		payload["expires_at"] = expiresAt.UTC().Format(time.RFC3339)
+	}
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionUserBanned,
+		Subject: strconv.FormatInt(userID, 10),
+		AppID:   appID,
+		Payload: payload,
+	})
+
+	return ban, nil
+}
+
+// UnbanUser lifts ban of the user from the app.
+func (a *Admin) UnbanUser(ctx context.Context, userID int64, appID int) error {
+	const op = "Admin.UnbanUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", appID),
+	)
+
+	if err := a.bans.DeleteAppBan(ctx, userID, appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user unbanned")
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionUserUnbanned,
+		Subject: strconv.FormatInt(userID, 10),
+		AppID:   appID,
+		Payload: map[string]string{},
+	})
+
+	return nil
+}
+
+// Bans returns bans of the user which are in effect, ordered by app ID.
+func (a *Admin) Bans(ctx context.Context, userID int64) ([]models.AppBan, error) {
+	const op = "Admin.Bans"
+
+	bans, err := a.bans.AppBans(ctx, userID, a.clock.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bans, nil
+}
+
+// App returns app by ID.
+func (a *Admin) App(ctx context.Context, appID int) (models.App, error) {
+	const op = "Admin.App"
+
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// Apps returns page of apps ordered by ID, starting after afterID.
+func (a *Admin) Apps(ctx context.Context, afterID int, limit int) ([]models.App, error) {
+	const op = "Admin.Apps"
+
+	apps, err := a.apps.Apps(ctx, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+// CreateApp creates app with random secret and returns it, the secret is shown only once.
+func (a *Admin) CreateApp(ctx context.Context, name string) (models.App, error) {
+	const op = "Admin.CreateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+	)
+
+	secret, err := random.Secret(a.random)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := a.apps.SaveApp(ctx, name, secret)
+	if err != nil {
+		log.Error("failed to save app", sl.Err(err))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app created", slog.Int("app_id", id))
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionAppChanged,
+		Subject: name,
+		AppID:   id,
+		Payload: map[string]string{"change": "created"},
+	})
+
+	return models.App{ID: id, Name: name, Secret: secret}, nil
+}
+
+// RotateAppSecret replaces secret of the app with a random one and returns the app with it,
+// the secret is shown only once. Tokens signed by the old secret are rejected right away.
+func (a *Admin) RotateAppSecret(ctx context.Context, appID int) (models.App, error) {
+	const op = "Admin.RotateAppSecret"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	secret, err := random.Secret(a.random)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.apps.UpdateAppSecret(ctx, appID, secret); err != nil {
+		log.Error("failed to update app secret", sl.Err(err))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.appCache.InvalidateApp(appID)
+
+	log.Info("app secret rotated")
+
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionAppChanged,
+		Subject: app.Name,
+		AppID:   appID,
+		Payload: map[string]string{"change": "secret_rotated"},
+	})
+
+	app.Secret = secret
+
+	return app, nil
+}
+
+// SetAppWebSettings replaces origins of the app's frontends allowed by CORS and their content security policy.
+// Origins are scheme and host with optional port, e.g. https://app.example.com, wildcards are not allowed,
+// so every frontend is authorized explicitly.
+func (a *Admin) SetAppWebSettings(ctx context.Context, appID int, origins []string, csp string) (models.App, error) {
+	const op = "Admin.SetAppWebSettings"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	normalized := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		origin, err := normalizeOrigin(origin)
+		if err != nil {
+			return models.App{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !slices.Contains(normalized, origin) {
+			normalized = append(normalized, origin)
+		}
+	}
+
+	// Policy is sent as a header value, so line breaks would inject headers.
+	if strings.ContainsFunc(csp, unicode.IsControl) {
+		return models.App{}, fmt.Errorf("%s: %w", op, ErrInvalidCSP)
+	}
+	csp = strings.TrimSpace(csp)
+
+	if err := a.apps.UpdateAppWebSettings(ctx, appID, normalized, csp); err != nil {
+		log.Error("failed to update app web settings", sl.Err(err))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.appCache.InvalidateApp(appID)
+
+	log.Info("app web settings updated", slog.Int("origins", len(normalized)))
+
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionAppChanged,
+		Subject: app.Name,
+		AppID:   appID,
+		Payload: map[string]string{
+			"change":                  "web_settings_updated",
+			"allowed_origins":         strings.Join(normalized, " "),
+			"content_security_policy": csp,
+		},
+	})
+
+	return app, nil
+}
+
+// SetAppTokenClaims replaces iss and aud claims of the app's tokens, empty ones fall back to the configured claims.
+// Tokens issued before the change are rejected by introspection once the app's cache expires.
+func (a *Admin) SetAppTokenClaims(ctx context.Context, appID int, issuer string, audience string) (models.App, error) {
+	const op = "Admin.SetAppTokenClaims"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	invalid := func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }
+	if strings.ContainsFunc(issuer, invalid) || strings.ContainsFunc(audience, invalid) {
+		return models.App{}, fmt.Errorf("%s: %w", op, ErrInvalidTokenClaim)
+	}
+
+	if err := a.apps.UpdateAppTokenClaims(ctx, appID, issuer, audience); err != nil {
+		log.Error("failed to update app token claims", sl.Err(err))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.appCache.InvalidateApp(appID)
+
+	log.Info("app token claims updated")
+
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.audit(ctx, models.AuditEvent{
+		Action:  models.AuditActionAppChanged,
+		Subject: app.Name,
+		AppID:   appID,
+		Payload: map[string]string{
+			"change":   "token_claims_updated",
+			"issuer":   issuer,
+			"audience": audience,
+		},
+	})
+
+	return app, nil
+}
+
+// normalizeOrigin returns origin as browsers send it in Origin header: lowercase scheme and host,
+// without default port.
+func normalizeOrigin(origin string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil {
+		return "", ErrInvalidOrigin
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil ||
+		(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || strings.Contains(u.Host, "*") {
+		return "", ErrInvalidOrigin
+	}
+
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		port = ""
+	}
+
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		// IPv6 literal.
+		host = "[" + host + "]"
+	}
+
+	return scheme + "://" + host, nil
+}
+
+// audit records the event with source and actor of the change: the admin authenticated by the access token
+// and the operator's client certificate, if mTLS is enabled.
+func (a *Admin) audit(ctx context.Context, event models.AuditEvent) {
+	if claims, ok := jwt.FromContext(ctx); ok {
+		event.ActorID = claims.UserID
+	}
+
+	event.Payload["source"] = "admin_api"
+	if operator := peer.ClientCertName(ctx); operator != "" {
+		event.Payload["operator"] = operator
+	}
+
+	a.auditor.Record(ctx, event)
+}
@@ -0,0 +1,226 @@
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log/slog"
+	mathrand "math/rand"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/services/admin/mocks"
+	"grpc-service-ref/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+var (
+	testNow    = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testLog    = slog.New(slog.NewTextHandler(io.Discard, nil))
+	testRandom = random.New(mathrand.New(mathrand.NewSource(1)))
+)
+
+// testAdminID is the admin calling AdminService in operatorContext.
+const testAdminID = 7
+
+// operatorContext returns context of the RPC authenticated by the admin's access token and the operator's
+// client certificate.
+func operatorContext(ctx context.Context) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "operator"}}
+	ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+
+	return jwt.NewContext(ctx, jwt.Claims{UserID: testAdminID, Email: "admin@example.com"})
+}
+
+func TestSetUserVerified(t *testing.T) {
+	ctx := context.Background()
+	adminCtx := operatorContext(ctx)
+	user := models.User{ID: 1, Email: "user@example.com"}
+	verified := models.User{ID: 1, Email: "user@example.com", Verified: true}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		userID   int64
+		verified bool
+		reason   string
+		setup    func(users *mocks.UserManager, userCache *mocks.UserCache, auditor *mocks.Auditor)
+		wantErr  error
+	}{
+		{
+			name:     "verified",
+			ctx:      adminCtx,
+			userID:   user.ID,
+			verified: true,
+			reason:   "mail bounced",
+			setup: func(users *mocks.UserManager, userCache *mocks.UserCache, auditor *mocks.Auditor) {
+				users.EXPECT().UserByID(adminCtx, user.ID).Return(user, nil).Once()
+				users.EXPECT().SetUserVerified(adminCtx, user.ID, true).Return(nil).Once()
+				userCache.EXPECT().InvalidateUser(user.Email).Once()
+				auditor.EXPECT().Record(adminCtx, models.AuditEvent{
+					Action:  models.AuditActionVerificationChanged,
+					ActorID: testAdminID,
+					Subject: "1",
+					Payload: map[string]string{
+						"verified":          "true",
+						"previous_verified": "false",
+						"reason":            "mail bounced",
+						"source":            "admin_api",
+						"operator":          "operator",
+					},
+				}).Once()
+			},
+		},
+		{
+			name:   "unverified",
+			ctx:    ctx,
+			userID: user.ID,
+			setup: func(users *mocks.UserManager, userCache *mocks.UserCache, auditor *mocks.Auditor) {
+				users.EXPECT().UserByID(ctx, user.ID).Return(verified, nil).Once()
+				users.EXPECT().SetUserVerified(ctx, user.ID, false).Return(nil).Once()
+				userCache.EXPECT().InvalidateUser(user.Email).Once()
+				auditor.EXPECT().Record(ctx, models.AuditEvent{
+					Action:  models.AuditActionVerificationChanged,
+					Subject: "1",
+					Payload: map[string]string{
+						"verified":          "false",
+						"previous_verified": "true",
+						"reason":            "",
+						"source":            "admin_api",
+					},
+				}).Once()
+			},
+		},
+		{
+			name:     "user not found",
+			ctx:      ctx,
+			userID:   2,
+			verified: true,
+			setup: func(users *mocks.UserManager, _ *mocks.UserCache, _ *mocks.Auditor) {
+				users.EXPECT().UserByID(ctx, int64(2)).Return(models.User{}, storage.ErrUserNotFound).Once()
+			},
+			wantErr: storage.ErrUserNotFound,
+		},
+		{
+			name:     "storage failure",
+			ctx:      ctx,
+			userID:   user.ID,
+			verified: true,
+			setup: func(users *mocks.UserManager, _ *mocks.UserCache, _ *mocks.Auditor) {
+				users.EXPECT().UserByID(ctx, user.ID).Return(user, nil).Once()
+				users.EXPECT().SetUserVerified(ctx, user.ID, true).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, userCache, auditor := mocks.NewUserManager(t), mocks.NewUserCache(t), mocks.NewAuditor(t)
+			tt.setup(users, userCache, auditor)
+
+			a := New(testLog, users, mocks.NewAppManager(t), mocks.NewSessionProvider(t), mocks.NewBanManager(t),
+				mocks.NewAppCache(t), userCache, auditor, clock.NewFake(testNow), testRandom)
+
+			assert.ErrorIs(t, a.SetUserVerified(tt.ctx, tt.userID, tt.verified, tt.reason), tt.wantErr)
+		})
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	ctx := context.Background()
+	user := models.User{ID: 1, Email: "user@example.com"}
+
+	tests := []struct {
+		name        string
+		userID      int64
+		appID       int
+		setup       func(users *mocks.UserManager, apps *mocks.AppManager, sessions *mocks.SessionProvider, auditor *mocks.Auditor)
+		wantRevoked int
+		wantErr     error
+	}{
+		{
+			name:   "all apps",
+			userID: user.ID,
+			setup: func(users *mocks.UserManager, _ *mocks.AppManager, sessions *mocks.SessionProvider, auditor *mocks.Auditor) {
+				users.EXPECT().UserByID(ctx, user.ID).Return(user, nil).Once()
+				sessions.EXPECT().RevokeUserSessions(ctx, user.ID, testNow).Return(3, nil).Once()
+				auditor.EXPECT().Record(ctx, models.AuditEvent{
+					Action:  models.AuditActionSessionsRevoked,
+					Subject: "1",
+					Payload: map[string]string{"revoked": "3", "source": "admin_api"},
+				}).Once()
+			},
+			wantRevoked: 3,
+		},
+		{
+			name:   "app",
+			userID: user.ID,
+			appID:  2,
+			setup: func(users *mocks.UserManager, apps *mocks.AppManager, sessions *mocks.SessionProvider, auditor *mocks.Auditor) {
+				users.EXPECT().UserByID(ctx, user.ID).Return(user, nil).Once()
+				apps.EXPECT().App(ctx, 2).Return(models.App{ID: 2}, nil).Once()
+				sessions.EXPECT().RevokeUserAppSessions(ctx, user.ID, 2, testNow).Return(1, nil).Once()
+				auditor.EXPECT().Record(ctx, models.AuditEvent{
+					Action:  models.AuditActionSessionsRevoked,
+					Subject: "1",
+					AppID:   2,
+					Payload: map[string]string{"revoked": "1", "source": "admin_api"},
+				}).Once()
+			},
+			wantRevoked: 1,
+		},
+		{
+			name:   "user not found",
+			userID: 2,
+			setup: func(users *mocks.UserManager, _ *mocks.AppManager, _ *mocks.SessionProvider, _ *mocks.Auditor) {
+				users.EXPECT().UserByID(ctx, int64(2)).Return(models.User{}, storage.ErrUserNotFound).Once()
+			},
+			wantErr: storage.ErrUserNotFound,
+		},
+		{
+			name:   "app not found",
+			userID: user.ID,
+			appID:  9,
+			setup: func(users *mocks.UserManager, apps *mocks.AppManager, _ *mocks.SessionProvider, _ *mocks.Auditor) {
+				users.EXPECT().UserByID(ctx, user.ID).Return(user, nil).Once()
+				apps.EXPECT().App(ctx, 9).Return(models.App{}, storage.ErrAppNotFound).Once()
+			},
+			wantErr: storage.ErrAppNotFound,
+		},
+		{
+			name:   "storage failure",
+			userID: user.ID,
+			setup: func(users *mocks.UserManager, _ *mocks.AppManager, sessions *mocks.SessionProvider, _ *mocks.Auditor) {
+				users.EXPECT().UserByID(ctx, user.ID).Return(user, nil).Once()
+				sessions.EXPECT().RevokeUserSessions(ctx, user.ID, testNow).Return(0, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, apps, sessions, auditor := mocks.NewUserManager(t), mocks.NewAppManager(t), mocks.NewSessionProvider(t), mocks.NewAuditor(t)
+			tt.setup(users, apps, sessions, auditor)
+
+			a := New(testLog, users, apps, sessions, mocks.NewBanManager(t),
+				mocks.NewAppCache(t), mocks.NewUserCache(t), auditor, clock.NewFake(testNow), testRandom)
+
+			revoked, err := a.RevokeSessions(ctx, tt.userID, tt.appID)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantRevoked, revoked)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// AppCache is an autogenerated mock type for the AppCache type
+type AppCache struct {
+	mock.Mock
+}
+
+type AppCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AppCache) EXPECT() *AppCache_Expecter {
+	return &AppCache_Expecter{mock: &_m.Mock}
+}
+
+// InvalidateApp provides a mock function with given fields: appID
+func (_m *AppCache) InvalidateApp(appID int) {
+	_m.Called(appID)
+}
+
+// AppCache_InvalidateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateApp'
+type AppCache_InvalidateApp_Call struct {
+	*mock.Call
+}
+
+// InvalidateApp is a helper method to define mock.On call
+//   - appID int
+func (_e *AppCache_Expecter) InvalidateApp(appID interface{}) *AppCache_InvalidateApp_Call {
+	return &AppCache_InvalidateApp_Call{Call: _e.mock.On("InvalidateApp", appID)}
+}
+
+func (_c *AppCache_InvalidateApp_Call) Run(run func(appID int)) *AppCache_InvalidateApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *AppCache_InvalidateApp_Call) Return() *AppCache_InvalidateApp_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *AppCache_InvalidateApp_Call) RunAndReturn(run func(int)) *AppCache_InvalidateApp_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewAppCache creates a new instance of AppCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAppCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AppCache {
+	mock := &AppCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
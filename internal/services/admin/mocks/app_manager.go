@@ -0,0 +1,358 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AppManager is an autogenerated mock type for the AppManager type
+type AppManager struct {
+	mock.Mock
+}
+
+type AppManager_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AppManager) EXPECT() *AppManager_Expecter {
+	return &AppManager_Expecter{mock: &_m.Mock}
+}
+
+// App provides a mock function with given fields: ctx, appID
+func (_m *AppManager) App(ctx context.Context, appID int) (models.App, error) {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for App")
+	}
+
+	var r0 models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.App, error)); ok {
+		return rf(ctx, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.App); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		r0 = ret.Get(0).(models.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AppManager_App_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'App'
+type AppManager_App_Call struct {
+	*mock.Call
+}
+
+// App is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *AppManager_Expecter) App(ctx interface{}, appID interface{}) *AppManager_App_Call {
+	return &AppManager_App_Call{Call: _e.mock.On("App", ctx, appID)}
+}
+
+func (_c *AppManager_App_Call) Run(run func(ctx context.Context, appID int)) *AppManager_App_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *AppManager_App_Call) Return(_a0 models.App, _a1 error) *AppManager_App_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AppManager_App_Call) RunAndReturn(run func(context.Context, int) (models.App, error)) *AppManager_App_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Apps provides a mock function with given fields: ctx, afterID, limit
+func (_m *AppManager) Apps(ctx context.Context, afterID int, limit int) ([]models.App, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Apps")
+	}
+
+	var r0 []models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.App, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.App); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.App)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AppManager_Apps_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Apps'
+type AppManager_Apps_Call struct {
+	*mock.Call
+}
+
+// Apps is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int
+//   - limit int
+func (_e *AppManager_Expecter) Apps(ctx interface{}, afterID interface{}, limit interface{}) *AppManager_Apps_Call {
+	return &AppManager_Apps_Call{Call: _e.mock.On("Apps", ctx, afterID, limit)}
+}
+
+func (_c *AppManager_Apps_Call) Run(run func(ctx context.Context, afterID int, limit int)) *AppManager_Apps_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *AppManager_Apps_Call) Return(_a0 []models.App, _a1 error) *AppManager_Apps_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AppManager_Apps_Call) RunAndReturn(run func(context.Context, int, int) ([]models.App, error)) *AppManager_Apps_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveApp provides a mock function with given fields: ctx, name, secret
+func (_m *AppManager) SaveApp(ctx context.Context, name string, secret string) (int, error) {
+	ret := _m.Called(ctx, name, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveApp")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int, error)); ok {
+		return rf(ctx, name, secret)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int); ok {
+		r0 = rf(ctx, name, secret)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, secret)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AppManager_SaveApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveApp'
+type AppManager_SaveApp_Call struct {
+	*mock.Call
+}
+
+// SaveApp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - secret string
+func (_e *AppManager_Expecter) SaveApp(ctx interface{}, name interface{}, secret interface{}) *AppManager_SaveApp_Call {
+	return &AppManager_SaveApp_Call{Call: _e.mock.On("SaveApp", ctx, name, secret)}
+}
+
+func (_c *AppManager_SaveApp_Call) Run(run func(ctx context.Context, name string, secret string)) *AppManager_SaveApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *AppManager_SaveApp_Call) Return(_a0 int, _a1 error) *AppManager_SaveApp_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AppManager_SaveApp_Call) RunAndReturn(run func(context.Context, string, string) (int, error)) *AppManager_SaveApp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAppSecret provides a mock function with given fields: ctx, appID, secret
+func (_m *AppManager) UpdateAppSecret(ctx context.Context, appID int, secret string) error {
+	ret := _m.Called(ctx, appID, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAppSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, appID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AppManager_UpdateAppSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAppSecret'
+type AppManager_UpdateAppSecret_Call struct {
+	*mock.Call
+}
+
+// UpdateAppSecret is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - secret string
+func (_e *AppManager_Expecter) UpdateAppSecret(ctx interface{}, appID interface{}, secret interface{}) *AppManager_UpdateAppSecret_Call {
+	return &AppManager_UpdateAppSecret_Call{Call: _e.mock.On("UpdateAppSecret", ctx, appID, secret)}
+}
+
+func (_c *AppManager_UpdateAppSecret_Call) Run(run func(ctx context.Context, appID int, secret string)) *AppManager_UpdateAppSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *AppManager_UpdateAppSecret_Call) Return(_a0 error) *AppManager_UpdateAppSecret_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AppManager_UpdateAppSecret_Call) RunAndReturn(run func(context.Context, int, string) error) *AppManager_UpdateAppSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAppTokenClaims provides a mock function with given fields: ctx, appID, issuer, audience
+func (_m *AppManager) UpdateAppTokenClaims(ctx context.Context, appID int, issuer string, audience string) error {
+	ret := _m.Called(ctx, appID, issuer, audience)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAppTokenClaims")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = rf(ctx, appID, issuer, audience)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AppManager_UpdateAppTokenClaims_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAppTokenClaims'
+type AppManager_UpdateAppTokenClaims_Call struct {
+	*mock.Call
+}
+
+// UpdateAppTokenClaims is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - issuer string
+//   - audience string
+func (_e *AppManager_Expecter) UpdateAppTokenClaims(ctx interface{}, appID interface{}, issuer interface{}, audience interface{}) *AppManager_UpdateAppTokenClaims_Call {
+	return &AppManager_UpdateAppTokenClaims_Call{Call: _e.mock.On("UpdateAppTokenClaims", ctx, appID, issuer, audience)}
+}
+
+func (_c *AppManager_UpdateAppTokenClaims_Call) Run(run func(ctx context.Context, appID int, issuer string, audience string)) *AppManager_UpdateAppTokenClaims_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *AppManager_UpdateAppTokenClaims_Call) Return(_a0 error) *AppManager_UpdateAppTokenClaims_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AppManager_UpdateAppTokenClaims_Call) RunAndReturn(run func(context.Context, int, string, string) error) *AppManager_UpdateAppTokenClaims_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAppWebSettings provides a mock function with given fields: ctx, appID, origins, csp
+func (_m *AppManager) UpdateAppWebSettings(ctx context.Context, appID int, origins []string, csp string) error {
+	ret := _m.Called(ctx, appID, origins, csp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAppWebSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string, string) error); ok {
+		r0 = rf(ctx, appID, origins, csp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AppManager_UpdateAppWebSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAppWebSettings'
+type AppManager_UpdateAppWebSettings_Call struct {
+	*mock.Call
+}
+
+// UpdateAppWebSettings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - origins []string
+//   - csp string
+func (_e *AppManager_Expecter) UpdateAppWebSettings(ctx interface{}, appID interface{}, origins interface{}, csp interface{}) *AppManager_UpdateAppWebSettings_Call {
+	return &AppManager_UpdateAppWebSettings_Call{Call: _e.mock.On("UpdateAppWebSettings", ctx, appID, origins, csp)}
+}
+
+func (_c *AppManager_UpdateAppWebSettings_Call) Run(run func(ctx context.Context, appID int, origins []string, csp string)) *AppManager_UpdateAppWebSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].([]string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *AppManager_UpdateAppWebSettings_Call) Return(_a0 error) *AppManager_UpdateAppWebSettings_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AppManager_UpdateAppWebSettings_Call) RunAndReturn(run func(context.Context, int, []string, string) error) *AppManager_UpdateAppWebSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAppManager creates a new instance of AppManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAppManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AppManager {
+	mock := &AppManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,194 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// BanManager is an autogenerated mock type for the BanManager type
+type BanManager struct {
+	mock.Mock
+}
+
+type BanManager_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BanManager) EXPECT() *BanManager_Expecter {
+	return &BanManager_Expecter{mock: &_m.Mock}
+}
+
+// AppBans provides a mock function with given fields: ctx, userID, at
+func (_m *BanManager) AppBans(ctx context.Context, userID int64, at time.Time) ([]models.AppBan, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppBans")
+	}
+
+	var r0 []models.AppBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) ([]models.AppBan, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) []models.AppBan); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AppBan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BanManager_AppBans_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AppBans'
+type BanManager_AppBans_Call struct {
+	*mock.Call
+}
+
+// AppBans is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - at time.Time
+func (_e *BanManager_Expecter) AppBans(ctx interface{}, userID interface{}, at interface{}) *BanManager_AppBans_Call {
+	return &BanManager_AppBans_Call{Call: _e.mock.On("AppBans", ctx, userID, at)}
+}
+
+func (_c *BanManager_AppBans_Call) Run(run func(ctx context.Context, userID int64, at time.Time)) *BanManager_AppBans_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *BanManager_AppBans_Call) Return(_a0 []models.AppBan, _a1 error) *BanManager_AppBans_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BanManager_AppBans_Call) RunAndReturn(run func(context.Context, int64, time.Time) ([]models.AppBan, error)) *BanManager_AppBans_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAppBan provides a mock function with given fields: ctx, userID, appID
+func (_m *BanManager) DeleteAppBan(ctx context.Context, userID int64, appID int) error {
+	ret := _m.Called(ctx, userID, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAppBan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) error); ok {
+		r0 = rf(ctx, userID, appID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BanManager_DeleteAppBan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAppBan'
+type BanManager_DeleteAppBan_Call struct {
+	*mock.Call
+}
+
+// DeleteAppBan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - appID int
+func (_e *BanManager_Expecter) DeleteAppBan(ctx interface{}, userID interface{}, appID interface{}) *BanManager_DeleteAppBan_Call {
+	return &BanManager_DeleteAppBan_Call{Call: _e.mock.On("DeleteAppBan", ctx, userID, appID)}
+}
+
+func (_c *BanManager_DeleteAppBan_Call) Run(run func(ctx context.Context, userID int64, appID int)) *BanManager_DeleteAppBan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *BanManager_DeleteAppBan_Call) Return(_a0 error) *BanManager_DeleteAppBan_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BanManager_DeleteAppBan_Call) RunAndReturn(run func(context.Context, int64, int) error) *BanManager_DeleteAppBan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveAppBan provides a mock function with given fields: ctx, ban
+func (_m *BanManager) SaveAppBan(ctx context.Context, ban models.AppBan) error {
+	ret := _m.Called(ctx, ban)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAppBan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AppBan) error); ok {
+		r0 = rf(ctx, ban)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BanManager_SaveAppBan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAppBan'
+type BanManager_SaveAppBan_Call struct {
+	*mock.Call
+}
+
+// SaveAppBan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ban models.AppBan
+func (_e *BanManager_Expecter) SaveAppBan(ctx interface{}, ban interface{}) *BanManager_SaveAppBan_Call {
+	return &BanManager_SaveAppBan_Call{Call: _e.mock.On("SaveAppBan", ctx, ban)}
+}
+
+func (_c *BanManager_SaveAppBan_Call) Run(run func(ctx context.Context, ban models.AppBan)) *BanManager_SaveAppBan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AppBan))
+	})
+	return _c
+}
+
+func (_c *BanManager_SaveAppBan_Call) Return(_a0 error) *BanManager_SaveAppBan_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BanManager_SaveAppBan_Call) RunAndReturn(run func(context.Context, models.AppBan) error) *BanManager_SaveAppBan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBanManager creates a new instance of BanManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBanManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BanManager {
+	mock := &BanManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,216 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionProvider is an autogenerated mock type for the SessionProvider type
+type SessionProvider struct {
+	mock.Mock
+}
+
+type SessionProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SessionProvider) EXPECT() *SessionProvider_Expecter {
+	return &SessionProvider_Expecter{mock: &_m.Mock}
+}
+
+// RevokeUserAppSessions provides a mock function with given fields: ctx, userID, appID, at
+func (_m *SessionProvider) RevokeUserAppSessions(ctx context.Context, userID int64, appID int, at time.Time) (int, error) {
+	ret := _m.Called(ctx, userID, appID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserAppSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, time.Time) (int, error)); ok {
+		return rf(ctx, userID, appID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, time.Time) int); ok {
+		r0 = rf(ctx, userID, appID, at)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, time.Time) error); ok {
+		r1 = rf(ctx, userID, appID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionProvider_RevokeUserAppSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserAppSessions'
+type SessionProvider_RevokeUserAppSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserAppSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - appID int
+//   - at time.Time
+func (_e *SessionProvider_Expecter) RevokeUserAppSessions(ctx interface{}, userID interface{}, appID interface{}, at interface{}) *SessionProvider_RevokeUserAppSessions_Call {
+	return &SessionProvider_RevokeUserAppSessions_Call{Call: _e.mock.On("RevokeUserAppSessions", ctx, userID, appID, at)}
+}
+
+func (_c *SessionProvider_RevokeUserAppSessions_Call) Run(run func(ctx context.Context, userID int64, appID int, at time.Time)) *SessionProvider_RevokeUserAppSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionProvider_RevokeUserAppSessions_Call) Return(_a0 int, _a1 error) *SessionProvider_RevokeUserAppSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionProvider_RevokeUserAppSessions_Call) RunAndReturn(run func(context.Context, int64, int, time.Time) (int, error)) *SessionProvider_RevokeUserAppSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserSessions provides a mock function with given fields: ctx, userID, at
+func (_m *SessionProvider) RevokeUserSessions(ctx context.Context, userID int64, at time.Time) (int, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (int, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) int); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionProvider_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type SessionProvider_RevokeUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - at time.Time
+func (_e *SessionProvider_Expecter) RevokeUserSessions(ctx interface{}, userID interface{}, at interface{}) *SessionProvider_RevokeUserSessions_Call {
+	return &SessionProvider_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, userID, at)}
+}
+
+func (_c *SessionProvider_RevokeUserSessions_Call) Run(run func(ctx context.Context, userID int64, at time.Time)) *SessionProvider_RevokeUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionProvider_RevokeUserSessions_Call) Return(_a0 int, _a1 error) *SessionProvider_RevokeUserSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionProvider_RevokeUserSessions_Call) RunAndReturn(run func(context.Context, int64, time.Time) (int, error)) *SessionProvider_RevokeUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Sessions provides a mock function with given fields: ctx, filter, limit
+func (_m *SessionProvider) Sessions(ctx context.Context, filter models.SessionFilter, limit int) ([]models.Session, error) {
+	ret := _m.Called(ctx, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sessions")
+	}
+
+	var r0 []models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.SessionFilter, int) ([]models.Session, error)); ok {
+		return rf(ctx, filter, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.SessionFilter, int) []models.Session); ok {
+		r0 = rf(ctx, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.SessionFilter, int) error); ok {
+		r1 = rf(ctx, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionProvider_Sessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Sessions'
+type SessionProvider_Sessions_Call struct {
+	*mock.Call
+}
+
+// Sessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.SessionFilter
+//   - limit int
+func (_e *SessionProvider_Expecter) Sessions(ctx interface{}, filter interface{}, limit interface{}) *SessionProvider_Sessions_Call {
+	return &SessionProvider_Sessions_Call{Call: _e.mock.On("Sessions", ctx, filter, limit)}
+}
+
+func (_c *SessionProvider_Sessions_Call) Run(run func(ctx context.Context, filter models.SessionFilter, limit int)) *SessionProvider_Sessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.SessionFilter), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *SessionProvider_Sessions_Call) Return(_a0 []models.Session, _a1 error) *SessionProvider_Sessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionProvider_Sessions_Call) RunAndReturn(run func(context.Context, models.SessionFilter, int) ([]models.Session, error)) *SessionProvider_Sessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSessionProvider creates a new instance of SessionProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionProvider {
+	mock := &SessionProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
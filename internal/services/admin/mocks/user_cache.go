@@ -0,0 +1,98 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// UserCache is an autogenerated mock type for the UserCache type
+type UserCache struct {
+	mock.Mock
+}
+
+type UserCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserCache) EXPECT() *UserCache_Expecter {
+	return &UserCache_Expecter{mock: &_m.Mock}
+}
+
+// InvalidateAdmin provides a mock function with given fields: userID
+func (_m *UserCache) InvalidateAdmin(userID int64) {
+	_m.Called(userID)
+}
+
+// UserCache_InvalidateAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateAdmin'
+type UserCache_InvalidateAdmin_Call struct {
+	*mock.Call
+}
+
+// InvalidateAdmin is a helper method to define mock.On call
+//   - userID int64
+func (_e *UserCache_Expecter) InvalidateAdmin(userID interface{}) *UserCache_InvalidateAdmin_Call {
+	return &UserCache_InvalidateAdmin_Call{Call: _e.mock.On("InvalidateAdmin", userID)}
+}
+
+func (_c *UserCache_InvalidateAdmin_Call) Run(run func(userID int64)) *UserCache_InvalidateAdmin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *UserCache_InvalidateAdmin_Call) Return() *UserCache_InvalidateAdmin_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *UserCache_InvalidateAdmin_Call) RunAndReturn(run func(int64)) *UserCache_InvalidateAdmin_Call {
+	_c.Run(run)
+	return _c
+}
+
+// InvalidateUser provides a mock function with given fields: email
+func (_m *UserCache) InvalidateUser(email string) {
+	_m.Called(email)
+}
+
+// UserCache_InvalidateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateUser'
+type UserCache_InvalidateUser_Call struct {
+	*mock.Call
+}
+
+// InvalidateUser is a helper method to define mock.On call
+//   - email string
+func (_e *UserCache_Expecter) InvalidateUser(email interface{}) *UserCache_InvalidateUser_Call {
+	return &UserCache_InvalidateUser_Call{Call: _e.mock.On("InvalidateUser", email)}
+}
+
+func (_c *UserCache_InvalidateUser_Call) Run(run func(email string)) *UserCache_InvalidateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *UserCache_InvalidateUser_Call) Return() *UserCache_InvalidateUser_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *UserCache_InvalidateUser_Call) RunAndReturn(run func(string)) *UserCache_InvalidateUser_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewUserCache creates a new instance of UserCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserCache {
+	mock := &UserCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
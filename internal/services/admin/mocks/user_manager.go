@@ -0,0 +1,364 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserManager is an autogenerated mock type for the UserManager type
+type UserManager struct {
+	mock.Mock
+}
+
+type UserManager_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserManager) EXPECT() *UserManager_Expecter {
+	return &UserManager_Expecter{mock: &_m.Mock}
+}
+
+// IsAdmin provides a mock function with given fields: ctx, userID
+func (_m *UserManager) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAdmin")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (bool, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserManager_IsAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsAdmin'
+type UserManager_IsAdmin_Call struct {
+	*mock.Call
+}
+
+// IsAdmin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *UserManager_Expecter) IsAdmin(ctx interface{}, userID interface{}) *UserManager_IsAdmin_Call {
+	return &UserManager_IsAdmin_Call{Call: _e.mock.On("IsAdmin", ctx, userID)}
+}
+
+func (_c *UserManager_IsAdmin_Call) Run(run func(ctx context.Context, userID int64)) *UserManager_IsAdmin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *UserManager_IsAdmin_Call) Return(_a0 bool, _a1 error) *UserManager_IsAdmin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserManager_IsAdmin_Call) RunAndReturn(run func(context.Context, int64) (bool, error)) *UserManager_IsAdmin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAdmin provides a mock function with given fields: ctx, userID, isAdmin
+func (_m *UserManager) SetAdmin(ctx context.Context, userID int64, isAdmin bool) error {
+	ret := _m.Called(ctx, userID, isAdmin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAdmin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) error); ok {
+		r0 = rf(ctx, userID, isAdmin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserManager_SetAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAdmin'
+type UserManager_SetAdmin_Call struct {
+	*mock.Call
+}
+
+// SetAdmin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - isAdmin bool
+func (_e *UserManager_Expecter) SetAdmin(ctx interface{}, userID interface{}, isAdmin interface{}) *UserManager_SetAdmin_Call {
+	return &UserManager_SetAdmin_Call{Call: _e.mock.On("SetAdmin", ctx, userID, isAdmin)}
+}
+
+func (_c *UserManager_SetAdmin_Call) Run(run func(ctx context.Context, userID int64, isAdmin bool)) *UserManager_SetAdmin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *UserManager_SetAdmin_Call) Return(_a0 error) *UserManager_SetAdmin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserManager_SetAdmin_Call) RunAndReturn(run func(context.Context, int64, bool) error) *UserManager_SetAdmin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserVerified provides a mock function with given fields: ctx, userID, verified
+func (_m *UserManager) SetUserVerified(ctx context.Context, userID int64, verified bool) error {
+	ret := _m.Called(ctx, userID, verified)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) error); ok {
+		r0 = rf(ctx, userID, verified)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserManager_SetUserVerified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserVerified'
+type UserManager_SetUserVerified_Call struct {
+	*mock.Call
+}
+
+// SetUserVerified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - verified bool
+func (_e *UserManager_Expecter) SetUserVerified(ctx interface{}, userID interface{}, verified interface{}) *UserManager_SetUserVerified_Call {
+	return &UserManager_SetUserVerified_Call{Call: _e.mock.On("SetUserVerified", ctx, userID, verified)}
+}
+
+func (_c *UserManager_SetUserVerified_Call) Run(run func(ctx context.Context, userID int64, verified bool)) *UserManager_SetUserVerified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *UserManager_SetUserVerified_Call) Return(_a0 error) *UserManager_SetUserVerified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserManager_SetUserVerified_Call) RunAndReturn(run func(context.Context, int64, bool) error) *UserManager_SetUserVerified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// User provides a mock function with given fields: ctx, email
+func (_m *UserManager) User(ctx context.Context, email string) (models.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for User")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserManager_User_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'User'
+type UserManager_User_Call struct {
+	*mock.Call
+}
+
+// User is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *UserManager_Expecter) User(ctx interface{}, email interface{}) *UserManager_User_Call {
+	return &UserManager_User_Call{Call: _e.mock.On("User", ctx, email)}
+}
+
+func (_c *UserManager_User_Call) Run(run func(ctx context.Context, email string)) *UserManager_User_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserManager_User_Call) Return(_a0 models.User, _a1 error) *UserManager_User_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserManager_User_Call) RunAndReturn(run func(context.Context, string) (models.User, error)) *UserManager_User_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UserByID provides a mock function with given fields: ctx, id
+func (_m *UserManager) UserByID(ctx context.Context, id int64) (models.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserByID")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserManager_UserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UserByID'
+type UserManager_UserByID_Call struct {
+	*mock.Call
+}
+
+// UserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *UserManager_Expecter) UserByID(ctx interface{}, id interface{}) *UserManager_UserByID_Call {
+	return &UserManager_UserByID_Call{Call: _e.mock.On("UserByID", ctx, id)}
+}
+
+func (_c *UserManager_UserByID_Call) Run(run func(ctx context.Context, id int64)) *UserManager_UserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *UserManager_UserByID_Call) Return(_a0 models.User, _a1 error) *UserManager_UserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserManager_UserByID_Call) RunAndReturn(run func(context.Context, int64) (models.User, error)) *UserManager_UserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Users provides a mock function with given fields: ctx, afterID, limit
+func (_m *UserManager) Users(ctx context.Context, afterID int64, limit int) ([]models.User, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Users")
+	}
+
+	var r0 []models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]models.User, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []models.User); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserManager_Users_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Users'
+type UserManager_Users_Call struct {
+	*mock.Call
+}
+
+// Users is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *UserManager_Expecter) Users(ctx interface{}, afterID interface{}, limit interface{}) *UserManager_Users_Call {
+	return &UserManager_Users_Call{Call: _e.mock.On("Users", ctx, afterID, limit)}
+}
+
+func (_c *UserManager_Users_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *UserManager_Users_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *UserManager_Users_Call) Return(_a0 []models.User, _a1 error) *UserManager_Users_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserManager_Users_Call) RunAndReturn(run func(context.Context, int64, int) ([]models.User, error)) *UserManager_Users_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserManager creates a new instance of UserManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserManager {
+	mock := &UserManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
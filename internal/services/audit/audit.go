@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/geoip"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/peer"
+)
+
+type EventSaver interface {
+	SaveAuditEvent(ctx context.Context, event models.AuditEvent) (int64, error)
+}
+
+type EventProvider interface {
+	AuditEvents(ctx context.Context, filter models.AuditFilter, limit int) ([]models.AuditEvent, error)
+}
+
+// Audit keeps the append-only log of security-relevant actions.
+type Audit struct {
+	log           *slog.Logger
+	eventSaver    EventSaver
+	eventProvider EventProvider
+}
+
+func New(
+	log *slog.Logger,
+	eventSaver EventSaver,
+	eventProvider EventProvider,
+) *Audit {
+	return &Audit{
+		log:           log,
+		eventSaver:    eventSaver,
+		eventProvider: eventProvider,
+	}
+}
+
+// Record appends event to the audit log.
+// IP defaults to IP of the gRPC client and CreatedAt to the current time.
+//
+// Failing to save the event is logged, but doesn't fail the audited action.
+func (a *Audit) Record(ctx context.Context, event models.AuditEvent) {
+	const op = "Audit.Record"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("action", string(event.Action)),
+	)
+
+	if event.IP == "" {
+		event.IP = peer.IP(ctx)
+	}
+
+	// Location in the context is of the client, events of other IPs aren't located.
+	if loc, ok := geoip.FromContext(ctx); ok && event.Country == "" && event.IP == peer.IP(ctx) {
+		event.Country, event.City = loc.Country, loc.City
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	if _, err := a.eventSaver.SaveAuditEvent(ctx, event); err != nil {
+		log.Error("failed to save audit event", sl.Err(err), slog.Int64("actor_id", event.ActorID), slog.String("subject", event.Subject))
+	}
+}
+
+// securityActions are actions users see in their own security events.
+var securityActions = []models.AuditAction{
+	models.AuditActionLoginSucceeded,
+	models.AuditActionLoginFailed,
+	models.AuditActionNewSignIn,
+	models.AuditActionSignInChallenged,
+	models.AuditActionSignInBlocked,
+	models.AuditActionPasswordReset,
+	models.AuditActionPasswordChanged,
+	models.AuditActionTokenElevated,
+}
+
+// SecurityEvents returns recent security-relevant events of the user, newest first.
+func (a *Audit) SecurityEvents(ctx context.Context, userID int64, limit int) ([]models.AuditEvent, error) {
+	const op = "Audit.SecurityEvents"
+
+	events, err := a.eventProvider.AuditEvents(ctx, models.AuditFilter{Actions: securityActions, ActorID: userID}, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// Events returns page of audit events matching the filter, newest first.
+func (a *Audit) Events(ctx context.Context, filter models.AuditFilter, limit int) ([]models.AuditEvent, error) {
+	const op = "Audit.Events"
+
+	events, err := a.eventProvider.AuditEvents(ctx, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+)
+
+// Overflow policies of Buffer, applied to events recorded while the buffer is full.
+const (
+	// OverflowBlock waits for free space until the context of the audited action is done.
+	OverflowBlock = "block"
+	// OverflowDrop drops the event right away.
+	OverflowDrop = "drop"
+)
+
+// ErrBufferFull is returned for events dropped by overflow policy.
+var ErrBufferFull = errors.New("audit buffer is full")
+
+type BatchSaver interface {
+	SaveAuditEvent(ctx context.Context, event models.AuditEvent) (int64, error)
+	SaveAuditEvents(ctx context.Context, events []models.AuditEvent) error
+}
+
+// Buffer is an EventSaver which saves events in background by batches, so audited actions,
+// e.g. Login, don't wait for inserts. Events are saved within flush interval, IDs of saved events are not known.
+type Buffer struct {
+	log           *slog.Logger
+	saver         BatchSaver
+	batchSize     int
+	flushInterval time.Duration
+	overflow      string
+
+	events chan models.AuditEvent
+	// mu guards stopped, it's held for reading while event is buffered, so events isn't closed meanwhile.
+	mu      sync.RWMutex
+	stopped bool
+	done    chan struct{}
+}
+
+func NewBuffer(
+	log *slog.Logger,
+	saver BatchSaver,
+	size int,
+	batchSize int,
+	flushInterval time.Duration,
+	overflow string,
+) *Buffer {
+	return &Buffer{
+		log:           log,
+		saver:         saver,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		overflow:      overflow,
+		events:        make(chan models.AuditEvent, size),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start starts the background flusher.
+func (b *Buffer) Start() {
+	go func() {
+		defer close(b.done)
+		b.run()
+	}()
+}
+
+// Stop saves buffered events and stops the flusher, events recorded later are saved synchronously.
+func (b *Buffer) Stop() {
+	b.mu.Lock()
+	if !b.stopped {
+		b.stopped = true
+		close(b.events)
+	}
+	b.mu.Unlock()
+
+	<-b.done
+}
+
+// SaveAuditEvent buffers the event, 0 is returned as its ID.
+func (b *Buffer) SaveAuditEvent(ctx context.Context, event models.AuditEvent) (int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.stopped {
+		return b.saver.SaveAuditEvent(ctx, event)
+	}
+
+	select {
+	case b.events <- event:
+		metrics.AuditQueue.Inc()
+
+		return 0, nil
+	default:
+	}
+
+	if b.overflow == OverflowBlock {
+		select {
+		case b.events <- event:
+			metrics.AuditQueue.Inc()
+
+			return 0, nil
+		case <-ctx.Done():
+		}
+	}
+
+	metrics.AuditEventsDropped.Inc()
+
+	return 0, ErrBufferFull
+}
+
+// run saves events once batch is full or flush interval passes, until events is closed.
+func (b *Buffer) run() {
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.AuditEvent, 0, b.batchSize)
+
+	for {
+		select {
+		case event, ok := <-b.events:
+			if !ok {
+				b.flush(batch)
+
+				return
+			}
+
+			metrics.AuditQueue.Dec()
+
+			if batch = append(batch, event); len(batch) < b.batchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+
+		b.flush(batch)
+		batch = batch[:0]
+	}
+}
+
+func (b *Buffer) flush(batch []models.AuditEvent) {
+	const op = "Buffer.flush"
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := b.saver.SaveAuditEvents(context.Background(), batch); err != nil {
+		b.log.Error("failed to save audit events",
+			slog.String("op", op),
+			slog.Int("count", len(batch)),
+			sl.Err(err),
+		)
+		metrics.AuditEventsDropped.Add(float64(len(batch)))
+	}
+}
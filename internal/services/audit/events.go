@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/events"
+)
+
+// Subscribe records domain events published to the bus to the audit log.
+func (a *Audit) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e events.RegistrationPending) {
+		a.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionRegistered,
+			Subject: e.Email,
+			Payload: map[string]string{"mode": "pending"},
+		})
+	})
+	events.Subscribe(bus, func(ctx context.Context, e events.UserRegistered) {
+		// Activated registration was recorded once it was pending, so registrations aren't counted twice.
+		if e.Activated {
+			return
+		}
+
+		a.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionRegistered,
+			ActorID: e.UserID,
+			Subject: e.Email,
+			Payload: map[string]string{"mode": "immediate"},
+		})
+	})
+	events.Subscribe(bus, func(ctx context.Context, e events.EmailVerified) {
+		a.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionEmailVerified,
+			ActorID: e.UserID,
+			Subject: e.Email,
+		})
+	})
+	events.Subscribe(bus, func(ctx context.Context, e events.PasswordReset) {
+		a.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionPasswordReset,
+			ActorID: e.UserID,
+			Subject: e.Email,
+			Payload: map[string]string{"sessions_revoked": strconv.Itoa(e.SessionsRevoked)},
+		})
+	})
+	events.Subscribe(bus, func(ctx context.Context, e events.PasswordChanged) {
+		a.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionPasswordChanged,
+			ActorID: e.UserID,
+			Subject: e.Email,
+			Payload: map[string]string{"sessions_revoked": strconv.Itoa(e.SessionsRevoked)},
+		})
+	})
+	events.Subscribe(bus, func(ctx context.Context, e events.LoggedOut) {
+		a.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionLoggedOut,
+			ActorID: e.UserID,
+			Subject: e.Email,
+			AppID:   e.AppID,
+			Payload: map[string]string{"sso_session": e.SSOSessionID},
+		})
+	})
+	events.Subscribe(bus, func(ctx context.Context, e events.CredentialStuffingDetected) {
+		a.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionCredentialStuffingDetected,
+			Subject: e.IP,
+			Payload: map[string]string{
+				"scope":    e.Scope,
+				"failures": strconv.Itoa(e.Failures),
+				"window":   e.Window.String(),
+				"until":    e.Until.Format(time.RFC3339),
+			},
+		})
+	})
+}
@@ -0,0 +1,141 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BatchSaver is an autogenerated mock type for the BatchSaver type
+type BatchSaver struct {
+	mock.Mock
+}
+
+type BatchSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BatchSaver) EXPECT() *BatchSaver_Expecter {
+	return &BatchSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveAuditEvent provides a mock function with given fields: ctx, event
+func (_m *BatchSaver) SaveAuditEvent(ctx context.Context, event models.AuditEvent) (int64, error) {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAuditEvent")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AuditEvent) (int64, error)); ok {
+		return rf(ctx, event)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.AuditEvent) int64); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.AuditEvent) error); ok {
+		r1 = rf(ctx, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BatchSaver_SaveAuditEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAuditEvent'
+type BatchSaver_SaveAuditEvent_Call struct {
+	*mock.Call
+}
+
+// SaveAuditEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event models.AuditEvent
+func (_e *BatchSaver_Expecter) SaveAuditEvent(ctx interface{}, event interface{}) *BatchSaver_SaveAuditEvent_Call {
+	return &BatchSaver_SaveAuditEvent_Call{Call: _e.mock.On("SaveAuditEvent", ctx, event)}
+}
+
+func (_c *BatchSaver_SaveAuditEvent_Call) Run(run func(ctx context.Context, event models.AuditEvent)) *BatchSaver_SaveAuditEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AuditEvent))
+	})
+	return _c
+}
+
+func (_c *BatchSaver_SaveAuditEvent_Call) Return(_a0 int64, _a1 error) *BatchSaver_SaveAuditEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BatchSaver_SaveAuditEvent_Call) RunAndReturn(run func(context.Context, models.AuditEvent) (int64, error)) *BatchSaver_SaveAuditEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveAuditEvents provides a mock function with given fields: ctx, events
+func (_m *BatchSaver) SaveAuditEvents(ctx context.Context, events []models.AuditEvent) error {
+	ret := _m.Called(ctx, events)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAuditEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.AuditEvent) error); ok {
+		r0 = rf(ctx, events)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BatchSaver_SaveAuditEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAuditEvents'
+type BatchSaver_SaveAuditEvents_Call struct {
+	*mock.Call
+}
+
+// SaveAuditEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - events []models.AuditEvent
+func (_e *BatchSaver_Expecter) SaveAuditEvents(ctx interface{}, events interface{}) *BatchSaver_SaveAuditEvents_Call {
+	return &BatchSaver_SaveAuditEvents_Call{Call: _e.mock.On("SaveAuditEvents", ctx, events)}
+}
+
+func (_c *BatchSaver_SaveAuditEvents_Call) Run(run func(ctx context.Context, events []models.AuditEvent)) *BatchSaver_SaveAuditEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.AuditEvent))
+	})
+	return _c
+}
+
+func (_c *BatchSaver_SaveAuditEvents_Call) Return(_a0 error) *BatchSaver_SaveAuditEvents_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BatchSaver_SaveAuditEvents_Call) RunAndReturn(run func(context.Context, []models.AuditEvent) error) *BatchSaver_SaveAuditEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBatchSaver creates a new instance of BatchSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBatchSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BatchSaver {
+	mock := &BatchSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventSaver is an autogenerated mock type for the EventSaver type
+type EventSaver struct {
+	mock.Mock
+}
+
+type EventSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EventSaver) EXPECT() *EventSaver_Expecter {
+	return &EventSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveAuditEvent provides a mock function with given fields: ctx, event
+func (_m *EventSaver) SaveAuditEvent(ctx context.Context, event models.AuditEvent) (int64, error) {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAuditEvent")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AuditEvent) (int64, error)); ok {
+		return rf(ctx, event)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.AuditEvent) int64); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.AuditEvent) error); ok {
+		r1 = rf(ctx, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EventSaver_SaveAuditEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAuditEvent'
+type EventSaver_SaveAuditEvent_Call struct {
+	*mock.Call
+}
+
+// SaveAuditEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event models.AuditEvent
+func (_e *EventSaver_Expecter) SaveAuditEvent(ctx interface{}, event interface{}) *EventSaver_SaveAuditEvent_Call {
+	return &EventSaver_SaveAuditEvent_Call{Call: _e.mock.On("SaveAuditEvent", ctx, event)}
+}
+
+func (_c *EventSaver_SaveAuditEvent_Call) Run(run func(ctx context.Context, event models.AuditEvent)) *EventSaver_SaveAuditEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AuditEvent))
+	})
+	return _c
+}
+
+func (_c *EventSaver_SaveAuditEvent_Call) Return(_a0 int64, _a1 error) *EventSaver_SaveAuditEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *EventSaver_SaveAuditEvent_Call) RunAndReturn(run func(context.Context, models.AuditEvent) (int64, error)) *EventSaver_SaveAuditEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEventSaver creates a new instance of EventSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventSaver {
+	mock := &EventSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,33 +2,96 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/events"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/geoip"
 	"grpc-service-ref/internal/lib/jwt"
 	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+	"grpc-service-ref/internal/lib/passstrength"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/services/directory"
 	"grpc-service-ref/internal/storage"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Auth struct {
-	log         *slog.Logger
-	usrSaver    UserSaver
-	usrProvider UserProvider
-	appProvider AppProvider
-	tokenTTL    time.Duration
+	log          *slog.Logger
+	usrSaver     UserSaver
+	usrProvider  UserProvider
+	appProvider  AppProvider
+	pendingSaver PendingRegistrationSaver
+	auditor      Auditor
+	events       EventPublisher
+	notifier     Notifier
+	// signIn is nil if new device detection is disabled.
+	signIn        SignInChecker
+	sessions      SessionStore
+	refreshTokens RefreshTokenStore
+	orgs          MembershipProvider
+	// deletions are canceled by login.
+	deletions   DeletionCanceler
+	preferences NotificationPreferencesStore
+	bans        BanProvider
+	// directory is nil if no users are authenticated by external user directories.
+	directory Directory
+	clock     clock.Clock
+	random    random.Randomizer
+	tokenTTL  time.Duration
+	// elevatedTokenTTL is lifetime of one-time tokens issued by Elevate.
+	elevatedTokenTTL time.Duration
+	// ssoSessionTTL is how long after Login tokens for other apps are issued by Authorize, 0 disables Authorize.
+	ssoSessionTTL time.Duration
+	// refreshTokenTTL is lifetime of refresh tokens issued along access tokens, 0 disables refresh tokens.
+	refreshTokenTTL time.Duration
+	// passwordCost is bcrypt cost of new password hashes, existing hashes keep their cost.
+	passwordCost int
+	// passwordPolicy rejects weak passwords on registration and password changes, existing passwords are kept.
+	passwordPolicy passstrength.Policy
+	// revokeSessions revokes tokens of the user on password reset, and other than the current one on password change,
+	// so whoever took over the account loses it.
+	revokeSessions bool
+	// requireVerified rejects login of unverified users for all apps,
+	// otherwise it's up to the app setting.
+	requireVerified bool
+	// pendingRegistrationTTL enables pending-registration mode if not zero:
+	// user is created only after email is verified.
+	pendingRegistrationTTL time.Duration
+	// issuance is iss and aud claims of tokens of apps that don't override them.
+	issuance jwt.Issuance
 }
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrPassAreEqual       = errors.New("codes are equal")
+	ErrUserNotVerified    = errors.New("user email is not verified")
+	ErrInvalidAppSecret   = errors.New("invalid app secret")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenRevoked       = errors.New("token is revoked")
+	ErrTokenConsumed      = errors.New("one-time token is already used")
+	// ErrLoginRequired is returned by Authorize if the user must log in with credentials.
+	ErrLoginRequired = errors.New("login is required")
+	// ErrExternalUser is returned for password changes and registration of users managed
+	// by an external user directory.
+	ErrExternalUser = errors.New("user is managed by external user directory")
+	// ErrInvalidRefreshToken is returned by Refresh for unknown, expired, revoked and reused refresh tokens.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrUserBanned is returned when tokens for an app are requested by a user banned from it.
+	ErrUserBanned = errors.New("user is banned from the app")
 )
 
-//go:generate go run github.com/vektra/mockery/v2@v2.28.2 --name=URLSaver
+// PromptLogin makes Authorize require login with credentials even within SSO session.
+const PromptLogin = "login"
+
 type UserSaver interface {
 	SaveUser(
 		ctx context.Context,
@@ -48,38 +111,180 @@ type UserSaver interface {
 
 type UserProvider interface {
 	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
 	IsAdmin(ctx context.Context, userID int64) (bool, error)
 }
 
+type PendingRegistrationSaver interface {
+	SavePendingRegistration(
+		ctx context.Context,
+		email string,
+		passHash []byte,
+		expiresAt time.Time,
+	) error
+}
+
 type AppProvider interface {
 	App(ctx context.Context, appID int) (models.App, error)
 }
 
-func New(
-	log *slog.Logger,
-	userSaver UserSaver,
-	userProvider UserProvider,
-	appProvider AppProvider,
-	tokenTTL time.Duration,
-) *Auth {
+// Auditor records security-relevant actions to the audit log.
+type Auditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// EventPublisher publishes domain events, which are audited and delivered to webhooks, see events.Bus.
+type EventPublisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
+// Notifier emails users about changes of their accounts.
+type Notifier interface {
+	PasswordChanged(ctx context.Context, email string, reset bool, signedOut bool)
+}
+
+// SignInChecker checks device the user signs in from, sign-in from new device may require confirmation code.
+type SignInChecker interface {
+	Check(ctx context.Context, user models.User, appID int, device models.Device, code string) error
+}
+
+// SessionStore records issued tokens by their jti, so they can be revoked and one-time tokens can't be replayed.
+type SessionStore interface {
+	SaveSession(ctx context.Context, session models.Session) error
+	Session(ctx context.Context, id string) (models.Session, error)
+	RevokeSession(ctx context.Context, id string, at time.Time) error
+	RevokeSSOSession(ctx context.Context, ssoSessionID string, at time.Time) ([]models.Session, error)
+	ConsumeSession(ctx context.Context, id string, at time.Time) error
+	RevokeOtherUserSessions(ctx context.Context, userID int64, keepID string, at time.Time) (int, error)
+}
+
+// RefreshTokenStore records issued refresh tokens by their hash, so each of them is exchanged once.
+type RefreshTokenStore interface {
+	SaveRefreshToken(ctx context.Context, token models.RefreshToken) error
+	RefreshToken(ctx context.Context, id string) (models.RefreshToken, error)
+	UseRefreshToken(ctx context.Context, id string, at time.Time) error
+}
+
+// MembershipProvider returns organizations of the user, which are put to tokens.
+type MembershipProvider interface {
+	Memberships(ctx context.Context, userID int64) ([]models.OrganizationMember, error)
+}
+
+// DeletionCanceler cancels scheduled deletion of the account of the user who signs in.
+type DeletionCanceler interface {
+	CancelAccountDeletion(ctx context.Context, userID int64) error
+}
+
+// NotificationPreferencesStore stores categories of non-essential emails users receive.
+type NotificationPreferencesStore interface {
+	SaveNotificationPreferences(ctx context.Context, userID int64, prefs models.NotificationPreferences, at time.Time) error
+	NotificationPreferences(ctx context.Context, email string) (models.NotificationPreferences, error)
+}
+
+// BanProvider returns bans of users from apps, see models.AppBan.
+type BanProvider interface {
+	AppBan(ctx context.Context, userID int64, appID int) (models.AppBan, error)
+}
+
+// Directory authenticates users managed by external user directories, see directory.Router.
+type Directory interface {
+	// Authenticate returns the local user once the directory accepts credentials, ok is false
+	// if no directory manages the user, so credentials are checked locally.
+	Authenticate(ctx context.Context, appID int, email string, password string) (user models.User, ok bool, err error)
+	// Manages reports whether users with the email must be authenticated by a directory.
+	Manages(email string) bool
+}
+
+// Deps are stores and services Auth works with.
+type Deps struct {
+	UserSaver    UserSaver
+	UserProvider UserProvider
+	AppProvider  AppProvider
+	PendingSaver PendingRegistrationSaver
+	Auditor      Auditor
+	Events       EventPublisher
+	Notifier     Notifier
+	// SignIn is nil if new device detection is disabled.
+	SignIn        SignInChecker
+	Sessions      SessionStore
+	RefreshTokens RefreshTokenStore
+	Orgs          MembershipProvider
+	Deletions     DeletionCanceler
+	Preferences   NotificationPreferencesStore
+	Bans          BanProvider
+	// Directory is nil if no users are authenticated by external user directories.
+	Directory Directory
+	Clock     clock.Clock
+	Random    random.Randomizer
+}
+
+// Config are lifetimes of tokens and policies of Auth, see fields of Auth.
+type Config struct {
+	TokenTTL               time.Duration
+	ElevatedTokenTTL       time.Duration
+	SSOSessionTTL          time.Duration
+	RefreshTokenTTL        time.Duration
+	PasswordCost           int
+	PasswordPolicy         passstrength.Policy
+	RevokeSessions         bool
+	RequireVerified        bool
+	PendingRegistrationTTL time.Duration
+	Issuance               jwt.Issuance
+}
+
+func New(log *slog.Logger, deps Deps, cfg Config) *Auth {
 	return &Auth{
-		usrSaver:    userSaver,
-		usrProvider: userProvider,
-		log:         log,
-		appProvider: appProvider,
-		tokenTTL:    tokenTTL,
+		log:                    log,
+		usrSaver:               deps.UserSaver,
+		usrProvider:            deps.UserProvider,
+		appProvider:            deps.AppProvider,
+		pendingSaver:           deps.PendingSaver,
+		auditor:                deps.Auditor,
+		events:                 deps.Events,
+		notifier:               deps.Notifier,
+		signIn:                 deps.SignIn,
+		sessions:               deps.Sessions,
+		refreshTokens:          deps.RefreshTokens,
+		orgs:                   deps.Orgs,
+		deletions:              deps.Deletions,
+		preferences:            deps.Preferences,
+		bans:                   deps.Bans,
+		directory:              deps.Directory,
+		clock:                  deps.Clock,
+		random:                 deps.Random,
+		tokenTTL:               cfg.TokenTTL,
+		elevatedTokenTTL:       cfg.ElevatedTokenTTL,
+		ssoSessionTTL:          cfg.SSOSessionTTL,
+		refreshTokenTTL:        cfg.RefreshTokenTTL,
+		passwordCost:           cfg.PasswordCost,
+		passwordPolicy:         cfg.PasswordPolicy,
+		revokeSessions:         cfg.RevokeSessions,
+		requireVerified:        cfg.RequireVerified,
+		pendingRegistrationTTL: cfg.PendingRegistrationTTL,
+		issuance:               cfg.Issuance,
 	}
 }
 
+// PendingRegistration reports whether users are created only once their email is verified,
+// RegisterNewUser returns 0 for them.
+func (a *Auth) PendingRegistration() bool {
+	return a.pendingRegistrationTTL != 0
+}
+
 // Login checks if user with given credentials exists in the system and returns access token.
 //
 // If user exists, but password is incorrect, returns error.
 // If user doesn't exist, returns error.
+// If user email is not verified and verification is required globally or by the app, returns ErrUserNotVerified.
+// If user is banned from the app, returns ErrUserBanned.
+// Sign-in from new device may need confirmation by signInCode, see SignInChecker.
 func (a *Auth) Login(
 	ctx context.Context,
 	email string,
 	password string,
 	appID int,
+	device models.Device,
+	signInCode string,
 ) (string, error) {
 	const op = "Auth.Login"
 
@@ -90,44 +295,87 @@ func (a *Auth) Login(
 
 	log.Info("attempting to login user")
 
-	user, err := a.usrProvider.User(ctx, email)
+	user, err := a.authenticate(ctx, email, password, appID)
 	if err != nil {
-		if errors.Is(err, storage.ErrUserNotFound) {
-			a.log.Warn("user not found", sl.Err(err))
+		if errors.Is(err, ErrInvalidCredentials) {
+			log.Info("invalid credentials")
+			metrics.Logins.WithLabelValues(metrics.LoginInvalidCredentials).Inc()
+			a.auditLoginFailed(ctx, user.ID, email, appID, metrics.LoginInvalidCredentials)
 
 			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 		}
 
-		a.log.Error("failed to get user", sl.Err(err))
+		log.Error("failed to authenticate user", sl.Err(err))
 
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
-		a.log.Info("invalid credentials", sl.Err(err))
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !user.Verified && (a.requireVerified || app.RequireVerified) {
+		log.Info("user email is not verified")
+		metrics.Logins.WithLabelValues(metrics.LoginNotVerified).Inc()
+		a.auditLoginFailed(ctx, user.ID, email, appID, metrics.LoginNotVerified)
 
-		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		return "", fmt.Errorf("%s: %w", op, ErrUserNotVerified)
 	}
 
-	app, err := a.appProvider.App(ctx, appID)
-	if err != nil {
+	if err := a.checkBan(ctx, user.ID, appID); err != nil {
+		if errors.Is(err, ErrUserBanned) {
+			log.Info("user is banned from the app")
+			metrics.Logins.WithLabelValues(metrics.LoginBanned).Inc()
+			a.auditLoginFailed(ctx, user.ID, email, appID, metrics.LoginBanned)
+		} else {
+			log.Error("failed to check ban", sl.Err(err))
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if a.signIn != nil {
+		if err := a.signIn.Check(ctx, user, appID, device, signInCode); err != nil {
+			log.Info("sign-in is not confirmed", sl.Err(err))
+			metrics.Logins.WithLabelValues(metrics.LoginNotConfirmed).Inc()
+
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := a.cancelAccountDeletion(ctx, user, appID); err != nil {
+		log.Error("failed to cancel account deletion", sl.Err(err))
+
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Info("user logged in successfully")
 
-	token, err := jwt.NewToken(user, app, a.tokenTTL)
+	token, err := a.issueToken(ctx, user, app, a.tokenTTL, false, "")
 	if err != nil {
 		a.log.Error("failed to generate token", sl.Err(err))
 
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
+	a.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionLoginSucceeded,
+		ActorID: user.ID,
+		Subject: email,
+		AppID:   appID,
+	})
+
 	return token, nil
 }
 
 // RegisterNewUser registers new user in the system and returns user ID.
 // If user with given username already exists, returns error.
+// If password is weaker than the policy requires, returns passstrength.ErrTooWeak.
+//
+// In pending-registration mode user is not created until email is verified,
+// so returned user ID is 0.
 func (a *Auth) RegisterNewUser(ctx context.Context, email string, pass string) (int64, error) {
 	const op = "Auth.RegisterNewUser"
 
@@ -138,13 +386,40 @@ func (a *Auth) RegisterNewUser(ctx context.Context, email string, pass string) (
 
 	log.Info("registering user")
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	if a.directory != nil && a.directory.Manages(email) {
+		log.Info("email is managed by external user directory")
+
+		return 0, fmt.Errorf("%s: %w", op, ErrExternalUser)
+	}
+
+	if _, err := a.passwordPolicy.Check(pass, email); err != nil {
+		log.Info("password is too weak")
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), a.passwordCost)
 	if err != nil {
 		log.Error("failed to generate password hash", sl.Err(err))
 
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if a.pendingRegistrationTTL != 0 {
+		expiresAt := a.clock.Now().UTC().Add(a.pendingRegistrationTTL)
+		if err := a.pendingSaver.SavePendingRegistration(ctx, email, passHash, expiresAt); err != nil {
+			log.Error("failed to save pending registration", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("registration is pending until email is verified")
+		metrics.Registrations.WithLabelValues("pending").Inc()
+		a.events.Publish(ctx, events.RegistrationPending{Email: email})
+
+		return 0, nil
+	}
+
 	id, err := a.usrSaver.SaveUser(ctx, email, passHash)
 	if err != nil {
 		log.Error("failed to save user", sl.Err(err))
@@ -152,6 +427,9 @@ func (a *Auth) RegisterNewUser(ctx context.Context, email string, pass string) (
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	metrics.Registrations.WithLabelValues("immediate").Inc()
+	a.events.Publish(ctx, events.UserRegistered{UserID: id, Email: email})
+
 	return id, nil
 }
 
@@ -192,7 +470,17 @@ func (a *Auth) UpdateUser(ctx context.Context, email string, pass string) (int64
 		return 0, fmt.Errorf("%s:%w", op, err)
 	}
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	if usr.Directory != "" {
+		return 0, fmt.Errorf("%s: %w", op, ErrExternalUser)
+	}
+
+	if _, err := a.passwordPolicy.Check(pass, email); err != nil {
+		log.Info("password is too weak")
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), a.passwordCost)
 
 	if equals := bcrypt.CompareHashAndPassword(usr.PassHash, []byte(pass)); equals == nil {
 		a.log.Info("password does not differ")
@@ -213,5 +501,630 @@ func (a *Auth) UpdateUser(ctx context.Context, email string, pass string) (int64
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	revoked, signedOut := a.revokeOtherSessions(ctx, log, usr.ID, "")
+
+	a.events.Publish(ctx, events.PasswordReset{UserID: usr.ID, Email: email, SessionsRevoked: revoked})
+	a.notifier.PasswordChanged(ctx, email, true, signedOut)
+
 	return id, nil
 }
+
+// ChangePassword changes password of the user who knows the current one.
+// If current password is wrong, returns ErrInvalidCredentials,
+// if the new one is weaker than the policy requires, returns passstrength.ErrTooWeak.
+// Tokens of the user other than the one of sessionID are revoked if it's enabled.
+func (a *Auth) ChangePassword(ctx context.Context, email string, currentPass string, newPass string, sessionID string) error {
+	const op = "Auth.ChangePassword"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	usr, err := a.usrProvider.User(ctx, email)
+	if err != nil {
+		log.Error("failed to fetch user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if usr.Directory != "" {
+		return fmt.Errorf("%s: %w", op, ErrExternalUser)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(usr.PassHash, []byte(currentPass)); err != nil {
+		log.Info("invalid current password")
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	if currentPass == newPass {
+		return fmt.Errorf("%s: %w", op, ErrPassAreEqual)
+	}
+
+	if _, err := a.passwordPolicy.Check(newPass, email); err != nil {
+		log.Info("password is too weak")
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(newPass), a.passwordCost)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.usrSaver.UpdateUser(ctx, usr, passHash); err != nil {
+		log.Error("failed to save user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password changed")
+
+	revoked, signedOut := a.revokeOtherSessions(ctx, log, usr.ID, sessionID)
+
+	a.events.Publish(ctx, events.PasswordChanged{UserID: usr.ID, Email: email, SessionsRevoked: revoked})
+	a.notifier.PasswordChanged(ctx, email, false, signedOut)
+
+	return nil
+}
+
+// revokeOtherSessions revokes tokens of the user except the one of keepID, all of them if it's empty,
+// and returns how many were revoked and whether the user is signed out elsewhere. Failure doesn't undo
+// the password change, which is already saved, so it's only logged.
+func (a *Auth) revokeOtherSessions(ctx context.Context, log *slog.Logger, userID int64, keepID string) (int, bool) {
+	if !a.revokeSessions {
+		return 0, false
+	}
+
+	revoked, err := a.sessions.RevokeOtherUserSessions(ctx, userID, keepID, a.clock.Now().UTC())
+	if err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+
+		return 0, false
+	}
+
+	log.Info("sessions revoked", slog.Int("revoked", revoked))
+
+	return revoked, true
+}
+
+// AuthenticateApp checks that the caller knows secret of the app.
+// Used by RPCs available to trusted apps only.
+func (a *Auth) AuthenticateApp(ctx context.Context, appID int, secret string) error {
+	const op = "Auth.AuthenticateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found")
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidAppSecret)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(app.Secret), []byte(secret)) != 1 {
+		log.Warn("invalid app secret")
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidAppSecret)
+	}
+
+	return nil
+}
+
+// AuthenticateUser verifies access token issued by Login and returns its claims.
+// Tokens of service accounts are rejected with ErrInvalidToken, see AuthenticateToken.
+func (a *Auth) AuthenticateUser(ctx context.Context, token string) (jwt.Claims, error) {
+	const op = "Auth.AuthenticateUser"
+
+	claims, err := a.AuthenticateToken(ctx, token)
+	if err != nil {
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if claims.ServiceAccountID != 0 {
+		a.log.Info("token of service account is rejected", slog.String("op", op), slog.String("jti", claims.ID))
+
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// AuthenticateToken verifies access token of a user or a service account and returns its claims.
+// Token is checked with secret of the app it was issued for.
+//
+// Revoked tokens are rejected with ErrTokenRevoked. Elevated token is consumed by the first call,
+// further calls with it return ErrTokenConsumed.
+func (a *Auth) AuthenticateToken(ctx context.Context, token string) (jwt.Claims, error) {
+	const op = "Auth.AuthenticateToken"
+
+	log := a.log.With(slog.String("op", op))
+
+	var (
+		// appErr is a failure to get the app, reported as internal error unless the app doesn't exist.
+		appErr error
+		app    models.App
+	)
+
+	claims, err := jwt.ParseToken(token, a.clock.Now(), a.issuance.Key, func(appID int) (string, error) {
+		var err error
+		app, err = a.appProvider.App(ctx, appID)
+		if err != nil && !errors.Is(err, storage.ErrAppNotFound) {
+			appErr = err
+		}
+
+		return app.Secret, err
+	})
+	if appErr != nil {
+		log.Error("failed to get app", sl.Err(appErr))
+
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, appErr)
+	}
+	if err != nil {
+		log.Info("invalid token", sl.Err(err))
+
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	// Tokens of another deployment, e.g. staging, are rejected even if the app shares the secret.
+	if !a.issuance.ForApp(app).Accepts(claims) {
+		log.Info("token of unexpected issuer or audience", slog.String("jti", claims.ID), slog.String("iss", claims.Issuer))
+
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if err := a.checkSession(ctx, claims); err != nil {
+		log.Info("token is rejected", slog.String("jti", claims.ID), sl.Err(err))
+
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claims, nil
+}
+
+// checkSession checks that session of the token is not revoked and consumes session of elevated token.
+func (a *Auth) checkSession(ctx context.Context, claims jwt.Claims) error {
+	// Tokens issued before jti was introduced can't be checked, they are rejected.
+	if claims.ID == "" {
+		return ErrInvalidToken
+	}
+
+	session, err := a.sessions.Session(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	if session.UserID != claims.UserID || session.ServiceAccountID != claims.ServiceAccountID ||
+		session.AppID != claims.AppID || session.Elevated != claims.Elevated {
+		return ErrInvalidToken
+	}
+
+	if !session.RevokedAt.IsZero() {
+		return ErrTokenRevoked
+	}
+
+	if !session.Elevated {
+		return nil
+	}
+
+	if err := a.sessions.ConsumeSession(ctx, session.ID, a.clock.Now().UTC()); err != nil {
+		if errors.Is(err, storage.ErrSessionConsumed) {
+			return ErrTokenConsumed
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Elevate issues one-time token for a sensitive action once the user signed in by the access token
+// confirms their password. If password is wrong, returns ErrInvalidCredentials.
+func (a *Auth) Elevate(ctx context.Context, claims jwt.Claims, password string) (string, error) {
+	const op = "Auth.Elevate"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", claims.UserID),
+	)
+
+	user, err := a.authenticate(ctx, claims.Email, password, claims.AppID)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			log.Info("invalid password")
+
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+
+		log.Error("failed to authenticate user", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.appProvider.App(ctx, claims.AppID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := a.issueToken(ctx, user, app, a.elevatedTokenTTL, true, "")
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("elevated token issued")
+
+	a.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionTokenElevated,
+		ActorID: user.ID,
+		Subject: user.Email,
+		AppID:   app.ID,
+	})
+
+	return token, nil
+}
+
+// Authorize issues token for the app to the user signed in to another app by the access token,
+// without credentials, while SSO session started by Login lasts.
+// ErrLoginRequired is returned if SSO session is over or revoked, SSO sessions are disabled,
+// or prompt is PromptLogin, so the app asks the user to log in anyway.
+func (a *Auth) Authorize(ctx context.Context, claims jwt.Claims, appID int, prompt string) (string, error) {
+	const op = "Auth.Authorize"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", claims.UserID),
+		slog.Int("app_id", appID),
+	)
+
+	if prompt == PromptLogin || a.ssoSessionTTL == 0 || claims.Elevated {
+		return "", fmt.Errorf("%s: %w", op, ErrLoginRequired)
+	}
+
+	ssoSession, err := a.ssoSession(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrLoginRequired)
+		}
+
+		log.Error("failed to get sso session", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+	ssoExpiresAt := ssoSession.IssuedAt.Add(a.ssoSessionTTL)
+	if !ssoSession.RevokedAt.IsZero() || !now.Before(ssoExpiresAt) {
+		log.Info("sso session is over")
+
+		return "", fmt.Errorf("%s: %w", op, ErrLoginRequired)
+	}
+
+	user, err := a.usrProvider.User(ctx, claims.Email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrLoginRequired)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !user.Verified && (a.requireVerified || app.RequireVerified) {
+		log.Info("user email is not verified")
+
+		return "", fmt.Errorf("%s: %w", op, ErrUserNotVerified)
+	}
+
+	if err := a.checkBan(ctx, user.ID, appID); err != nil {
+		if errors.Is(err, ErrUserBanned) {
+			log.Info("user is banned from the app")
+			a.auditLoginFailed(ctx, user.ID, user.Email, appID, metrics.LoginBanned)
+		} else {
+			log.Error("failed to check ban", sl.Err(err))
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Tokens don't outlive SSO session they are issued within.
+	ttl := a.tokenTTL
+	if left := ssoExpiresAt.Sub(now); left < ttl {
+		ttl = left
+	}
+
+	token, err := a.issueToken(ctx, user, app, ttl, false, ssoSession.ID)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user authorized by sso session")
+
+	metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
+	a.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionLoginSucceeded,
+		ActorID: user.ID,
+		Subject: user.Email,
+		AppID:   app.ID,
+		Payload: map[string]string{"sso_session": ssoSession.ID},
+	})
+
+	return token, nil
+}
+
+// LoginApprovedDevice issues token for the app to the device whose login the user approved from another device
+// the user is signed in on, see devicelogin. The token starts a new SSO session, so logout on one device
+// doesn't end the other.
+func (a *Auth) LoginApprovedDevice(ctx context.Context, userID int64, appID int) (string, error) {
+	const op = "Auth.LoginApprovedDevice"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	user, err := a.usrProvider.UserByID(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !user.Verified && (a.requireVerified || app.RequireVerified) {
+		log.Info("user email is not verified")
+		metrics.Logins.WithLabelValues(metrics.LoginNotVerified).Inc()
+
+		return "", fmt.Errorf("%s: %w", op, ErrUserNotVerified)
+	}
+
+	if err := a.checkBan(ctx, user.ID, appID); err != nil {
+		if errors.Is(err, ErrUserBanned) {
+			log.Info("user is banned from the app")
+			metrics.Logins.WithLabelValues(metrics.LoginBanned).Inc()
+			a.auditLoginFailed(ctx, user.ID, user.Email, appID, metrics.LoginBanned)
+		} else {
+			log.Error("failed to check ban", sl.Err(err))
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.cancelAccountDeletion(ctx, user, appID); err != nil {
+		log.Error("failed to cancel account deletion", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := a.issueToken(ctx, user, app, a.tokenTTL, false, "")
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("device logged in by approval")
+
+	metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
+	a.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionLoginSucceeded,
+		ActorID: user.ID,
+		Subject: user.Email,
+		AppID:   app.ID,
+		Payload: map[string]string{"method": "device_approval"},
+	})
+
+	return token, nil
+}
+
+// ssoSession returns SSO session the session was issued within.
+func (a *Auth) ssoSession(ctx context.Context, sessionID string) (models.Session, error) {
+	session, err := a.sessions.Session(ctx, sessionID)
+	if err != nil {
+		return models.Session{}, err
+	}
+
+	if session.SSOSessionID == "" || session.SSOSessionID == session.ID {
+		return session, nil
+	}
+
+	return a.sessions.Session(ctx, session.SSOSessionID)
+}
+
+// Logout ends SSO session of the token, so the token and tokens issued within the session for other apps
+// are rejected by AuthenticateUser until they expire. Apps are notified by user.logged_out event
+// to terminate their local sessions of the user.
+func (a *Auth) Logout(ctx context.Context, claims jwt.Claims) error {
+	const op = "Auth.Logout"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", claims.UserID),
+	)
+
+	session, err := a.sessions.Session(ctx, claims.ID)
+	if err != nil {
+		log.Error("failed to get session", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ssoSessionID := session.SSOSessionID
+	if ssoSessionID == "" {
+		ssoSessionID = session.ID
+	}
+
+	revoked, err := a.sessions.RevokeSSOSession(ctx, ssoSessionID, a.clock.Now().UTC())
+	if err != nil {
+		log.Error("failed to revoke sso session", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged out", slog.Int("sessions", len(revoked)))
+
+	a.events.Publish(ctx, events.LoggedOut{
+		UserID:       claims.UserID,
+		Email:        claims.Email,
+		AppID:        claims.AppID,
+		SSOSessionID: ssoSessionID,
+		Sessions:     revoked,
+	})
+
+	return nil
+}
+
+// issueToken records new session of the user and returns its token.
+// ssoSessionID is SSO session the token is issued within, empty starts a new one.
+func (a *Auth) issueToken(
+	ctx context.Context,
+	user models.User,
+	app models.App,
+	ttl time.Duration,
+	elevated bool,
+	ssoSessionID string,
+) (string, error) {
+	id, err := jwt.NewID(a.random)
+	if err != nil {
+		return "", err
+	}
+
+	if ssoSessionID == "" {
+		ssoSessionID = id
+	}
+
+	now := a.clock.Now().UTC()
+	session := models.Session{
+		ID:           id,
+		SSOSessionID: ssoSessionID,
+		UserID:       user.ID,
+		AppID:        app.ID,
+		Elevated:     elevated,
+		IP:           peer.IP(ctx),
+		IssuedAt:     now,
+		ExpiresAt:    now.Add(ttl),
+	}
+	if loc, ok := geoip.FromContext(ctx); ok {
+		session.Country, session.City = loc.Country, loc.City
+	}
+
+	orgs, err := a.orgs.Memberships(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	// Token whose session isn't saved would be rejected anyway, so it's not issued.
+	if err := a.sessions.SaveSession(ctx, session); err != nil {
+		return "", err
+	}
+
+	return jwt.NewToken(user, app, session, orgs, a.issuance.ForApp(app))
+}
+
+// cancelAccountDeletion cancels deletion of the account scheduled by the user, login during grace period
+// means the user keeps the account.
+func (a *Auth) cancelAccountDeletion(ctx context.Context, user models.User, appID int) error {
+	if err := a.deletions.CancelAccountDeletion(ctx, user.ID); err != nil {
+		if errors.Is(err, storage.ErrAccountDeletionNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	a.log.Info("account deletion canceled by login", slog.Int64("user_id", user.ID))
+
+	a.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionAccountDeletionCanceled,
+		ActorID: user.ID,
+		Subject: user.Email,
+		AppID:   appID,
+	})
+
+	return nil
+}
+
+// checkBan returns ErrUserBanned if the user is banned from the app, expired bans are ignored.
+func (a *Auth) checkBan(ctx context.Context, userID int64, appID int) error {
+	ban, err := a.bans.AppBan(ctx, userID, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppBanNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if ban.Active(a.clock.Now()) {
+		return ErrUserBanned
+	}
+
+	return nil
+}
+
+// authenticate checks credentials of the user of the app, by the external user directory managing the user
+// or by the local password hash. ErrInvalidCredentials comes with ID of the user, if it exists, for the audit log.
+func (a *Auth) authenticate(ctx context.Context, email string, password string, appID int) (models.User, error) {
+	if a.directory != nil {
+		user, ok, err := a.directory.Authenticate(ctx, appID, email, password)
+		if errors.Is(err, directory.ErrInvalidCredentials) {
+			return models.User{}, ErrInvalidCredentials
+		}
+		if ok || err != nil {
+			return user, err
+		}
+	}
+
+	user, err := a.usrProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return models.User{}, ErrInvalidCredentials
+		}
+
+		return models.User{}, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
+		return models.User{ID: user.ID}, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// auditLoginFailed records failed login, userID is 0 if there is no user with the email.
+func (a *Auth) auditLoginFailed(ctx context.Context, userID int64, email string, appID int, reason string) {
+	a.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionLoginFailed,
+		ActorID: userID,
+		Subject: email,
+		AppID:   appID,
+		Payload: map[string]string{"reason": reason},
+	})
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password hashing dominates latency of Register and Login, benchmarks use the cost Auth hashes with.
+
+func BenchmarkHashPassword(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := bcrypt.GenerateFromPassword([]byte("correct horse battery staple"), bcrypt.DefaultCost); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkComparePassword(b *testing.B) {
+	passHash, err := bcrypt.GenerateFromPassword([]byte("correct horse battery staple"), bcrypt.DefaultCost)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := bcrypt.CompareHashAndPassword(passHash, []byte("correct horse battery staple")); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
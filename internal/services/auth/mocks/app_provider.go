@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AppProvider is an autogenerated mock type for the AppProvider type
+type AppProvider struct {
+	mock.Mock
+}
+
+type AppProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AppProvider) EXPECT() *AppProvider_Expecter {
+	return &AppProvider_Expecter{mock: &_m.Mock}
+}
+
+// App provides a mock function with given fields: ctx, appID
+func (_m *AppProvider) App(ctx context.Context, appID int) (models.App, error) {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for App")
+	}
+
+	var r0 models.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.App, error)); ok {
+		return rf(ctx, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.App); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		r0 = ret.Get(0).(models.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AppProvider_App_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'App'
+type AppProvider_App_Call struct {
+	*mock.Call
+}
+
+// App is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *AppProvider_Expecter) App(ctx interface{}, appID interface{}) *AppProvider_App_Call {
+	return &AppProvider_App_Call{Call: _e.mock.On("App", ctx, appID)}
+}
+
+func (_c *AppProvider_App_Call) Run(run func(ctx context.Context, appID int)) *AppProvider_App_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *AppProvider_App_Call) Return(_a0 models.App, _a1 error) *AppProvider_App_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AppProvider_App_Call) RunAndReturn(run func(context.Context, int) (models.App, error)) *AppProvider_App_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAppProvider creates a new instance of AppProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAppProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AppProvider {
+	mock := &AppProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BanProvider is an autogenerated mock type for the BanProvider type
+type BanProvider struct {
+	mock.Mock
+}
+
+type BanProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BanProvider) EXPECT() *BanProvider_Expecter {
+	return &BanProvider_Expecter{mock: &_m.Mock}
+}
+
+// AppBan provides a mock function with given fields: ctx, userID, appID
+func (_m *BanProvider) AppBan(ctx context.Context, userID int64, appID int) (models.AppBan, error) {
+	ret := _m.Called(ctx, userID, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppBan")
+	}
+
+	var r0 models.AppBan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) (models.AppBan, error)); ok {
+		return rf(ctx, userID, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) models.AppBan); ok {
+		r0 = rf(ctx, userID, appID)
+	} else {
+		r0 = ret.Get(0).(models.AppBan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, userID, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BanProvider_AppBan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AppBan'
+type BanProvider_AppBan_Call struct {
+	*mock.Call
+}
+
+// AppBan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - appID int
+func (_e *BanProvider_Expecter) AppBan(ctx interface{}, userID interface{}, appID interface{}) *BanProvider_AppBan_Call {
+	return &BanProvider_AppBan_Call{Call: _e.mock.On("AppBan", ctx, userID, appID)}
+}
+
+func (_c *BanProvider_AppBan_Call) Run(run func(ctx context.Context, userID int64, appID int)) *BanProvider_AppBan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *BanProvider_AppBan_Call) Return(_a0 models.AppBan, _a1 error) *BanProvider_AppBan_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BanProvider_AppBan_Call) RunAndReturn(run func(context.Context, int64, int) (models.AppBan, error)) *BanProvider_AppBan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBanProvider creates a new instance of BanProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBanProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BanProvider {
+	mock := &BanProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
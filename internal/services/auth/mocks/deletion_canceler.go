@@ -0,0 +1,83 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeletionCanceler is an autogenerated mock type for the DeletionCanceler type
+type DeletionCanceler struct {
+	mock.Mock
+}
+
+type DeletionCanceler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeletionCanceler) EXPECT() *DeletionCanceler_Expecter {
+	return &DeletionCanceler_Expecter{mock: &_m.Mock}
+}
+
+// CancelAccountDeletion provides a mock function with given fields: ctx, userID
+func (_m *DeletionCanceler) CancelAccountDeletion(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelAccountDeletion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletionCanceler_CancelAccountDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelAccountDeletion'
+type DeletionCanceler_CancelAccountDeletion_Call struct {
+	*mock.Call
+}
+
+// CancelAccountDeletion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *DeletionCanceler_Expecter) CancelAccountDeletion(ctx interface{}, userID interface{}) *DeletionCanceler_CancelAccountDeletion_Call {
+	return &DeletionCanceler_CancelAccountDeletion_Call{Call: _e.mock.On("CancelAccountDeletion", ctx, userID)}
+}
+
+func (_c *DeletionCanceler_CancelAccountDeletion_Call) Run(run func(ctx context.Context, userID int64)) *DeletionCanceler_CancelAccountDeletion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *DeletionCanceler_CancelAccountDeletion_Call) Return(_a0 error) *DeletionCanceler_CancelAccountDeletion_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DeletionCanceler_CancelAccountDeletion_Call) RunAndReturn(run func(context.Context, int64) error) *DeletionCanceler_CancelAccountDeletion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeletionCanceler creates a new instance of DeletionCanceler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeletionCanceler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeletionCanceler {
+	mock := &DeletionCanceler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Directory is an autogenerated mock type for the Directory type
+type Directory struct {
+	mock.Mock
+}
+
+type Directory_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Directory) EXPECT() *Directory_Expecter {
+	return &Directory_Expecter{mock: &_m.Mock}
+}
+
+// Authenticate provides a mock function with given fields: ctx, appID, email, password
+func (_m *Directory) Authenticate(ctx context.Context, appID int, email string, password string) (models.User, bool, error) {
+	ret := _m.Called(ctx, appID, email, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 models.User
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (models.User, bool, error)); ok {
+		return rf(ctx, appID, email, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) models.User); ok {
+		r0 = rf(ctx, appID, email, password)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) bool); ok {
+		r1 = rf(ctx, appID, email, password)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, string, string) error); ok {
+		r2 = rf(ctx, appID, email, password)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Directory_Authenticate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Authenticate'
+type Directory_Authenticate_Call struct {
+	*mock.Call
+}
+
+// Authenticate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+//   - email string
+//   - password string
+func (_e *Directory_Expecter) Authenticate(ctx interface{}, appID interface{}, email interface{}, password interface{}) *Directory_Authenticate_Call {
+	return &Directory_Authenticate_Call{Call: _e.mock.On("Authenticate", ctx, appID, email, password)}
+}
+
+func (_c *Directory_Authenticate_Call) Run(run func(ctx context.Context, appID int, email string, password string)) *Directory_Authenticate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Directory_Authenticate_Call) Return(user models.User, ok bool, err error) *Directory_Authenticate_Call {
+	_c.Call.Return(user, ok, err)
+	return _c
+}
+
+func (_c *Directory_Authenticate_Call) RunAndReturn(run func(context.Context, int, string, string) (models.User, bool, error)) *Directory_Authenticate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Manages provides a mock function with given fields: email
+func (_m *Directory) Manages(email string) bool {
+	ret := _m.Called(email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Manages")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Directory_Manages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Manages'
+type Directory_Manages_Call struct {
+	*mock.Call
+}
+
+// Manages is a helper method to define mock.On call
+//   - email string
+func (_e *Directory_Expecter) Manages(email interface{}) *Directory_Manages_Call {
+	return &Directory_Manages_Call{Call: _e.mock.On("Manages", email)}
+}
+
+func (_c *Directory_Manages_Call) Run(run func(email string)) *Directory_Manages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Directory_Manages_Call) Return(_a0 bool) *Directory_Manages_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Directory_Manages_Call) RunAndReturn(run func(string) bool) *Directory_Manages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDirectory creates a new instance of Directory. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDirectory(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Directory {
+	mock := &Directory{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,71 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	events "grpc-service-ref/internal/events"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventPublisher is an autogenerated mock type for the EventPublisher type
+type EventPublisher struct {
+	mock.Mock
+}
+
+type EventPublisher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EventPublisher) EXPECT() *EventPublisher_Expecter {
+	return &EventPublisher_Expecter{mock: &_m.Mock}
+}
+
+// Publish provides a mock function with given fields: ctx, event
+func (_m *EventPublisher) Publish(ctx context.Context, event events.Event) {
+	_m.Called(ctx, event)
+}
+
+// EventPublisher_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type EventPublisher_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event events.Event
+func (_e *EventPublisher_Expecter) Publish(ctx interface{}, event interface{}) *EventPublisher_Publish_Call {
+	return &EventPublisher_Publish_Call{Call: _e.mock.On("Publish", ctx, event)}
+}
+
+func (_c *EventPublisher_Publish_Call) Run(run func(ctx context.Context, event events.Event)) *EventPublisher_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(events.Event))
+	})
+	return _c
+}
+
+func (_c *EventPublisher_Publish_Call) Return() *EventPublisher_Publish_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *EventPublisher_Publish_Call) RunAndReturn(run func(context.Context, events.Event)) *EventPublisher_Publish_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewEventPublisher creates a new instance of EventPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventPublisher {
+	mock := &EventPublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
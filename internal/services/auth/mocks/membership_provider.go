@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MembershipProvider is an autogenerated mock type for the MembershipProvider type
+type MembershipProvider struct {
+	mock.Mock
+}
+
+type MembershipProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MembershipProvider) EXPECT() *MembershipProvider_Expecter {
+	return &MembershipProvider_Expecter{mock: &_m.Mock}
+}
+
+// Memberships provides a mock function with given fields: ctx, userID
+func (_m *MembershipProvider) Memberships(ctx context.Context, userID int64) ([]models.OrganizationMember, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Memberships")
+	}
+
+	var r0 []models.OrganizationMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.OrganizationMember, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.OrganizationMember); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OrganizationMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MembershipProvider_Memberships_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Memberships'
+type MembershipProvider_Memberships_Call struct {
+	*mock.Call
+}
+
+// Memberships is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *MembershipProvider_Expecter) Memberships(ctx interface{}, userID interface{}) *MembershipProvider_Memberships_Call {
+	return &MembershipProvider_Memberships_Call{Call: _e.mock.On("Memberships", ctx, userID)}
+}
+
+func (_c *MembershipProvider_Memberships_Call) Run(run func(ctx context.Context, userID int64)) *MembershipProvider_Memberships_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MembershipProvider_Memberships_Call) Return(_a0 []models.OrganizationMember, _a1 error) *MembershipProvider_Memberships_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MembershipProvider_Memberships_Call) RunAndReturn(run func(context.Context, int64) ([]models.OrganizationMember, error)) *MembershipProvider_Memberships_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMembershipProvider creates a new instance of MembershipProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMembershipProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MembershipProvider {
+	mock := &MembershipProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,145 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// NotificationPreferencesStore is an autogenerated mock type for the NotificationPreferencesStore type
+type NotificationPreferencesStore struct {
+	mock.Mock
+}
+
+type NotificationPreferencesStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *NotificationPreferencesStore) EXPECT() *NotificationPreferencesStore_Expecter {
+	return &NotificationPreferencesStore_Expecter{mock: &_m.Mock}
+}
+
+// NotificationPreferences provides a mock function with given fields: ctx, email
+func (_m *NotificationPreferencesStore) NotificationPreferences(ctx context.Context, email string) (models.NotificationPreferences, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotificationPreferences")
+	}
+
+	var r0 models.NotificationPreferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.NotificationPreferences, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.NotificationPreferences); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(models.NotificationPreferences)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotificationPreferencesStore_NotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotificationPreferences'
+type NotificationPreferencesStore_NotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// NotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *NotificationPreferencesStore_Expecter) NotificationPreferences(ctx interface{}, email interface{}) *NotificationPreferencesStore_NotificationPreferences_Call {
+	return &NotificationPreferencesStore_NotificationPreferences_Call{Call: _e.mock.On("NotificationPreferences", ctx, email)}
+}
+
+func (_c *NotificationPreferencesStore_NotificationPreferences_Call) Run(run func(ctx context.Context, email string)) *NotificationPreferencesStore_NotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *NotificationPreferencesStore_NotificationPreferences_Call) Return(_a0 models.NotificationPreferences, _a1 error) *NotificationPreferencesStore_NotificationPreferences_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *NotificationPreferencesStore_NotificationPreferences_Call) RunAndReturn(run func(context.Context, string) (models.NotificationPreferences, error)) *NotificationPreferencesStore_NotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveNotificationPreferences provides a mock function with given fields: ctx, userID, prefs, at
+func (_m *NotificationPreferencesStore) SaveNotificationPreferences(ctx context.Context, userID int64, prefs models.NotificationPreferences, at time.Time) error {
+	ret := _m.Called(ctx, userID, prefs, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveNotificationPreferences")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.NotificationPreferences, time.Time) error); ok {
+		r0 = rf(ctx, userID, prefs, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NotificationPreferencesStore_SaveNotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveNotificationPreferences'
+type NotificationPreferencesStore_SaveNotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// SaveNotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - prefs models.NotificationPreferences
+//   - at time.Time
+func (_e *NotificationPreferencesStore_Expecter) SaveNotificationPreferences(ctx interface{}, userID interface{}, prefs interface{}, at interface{}) *NotificationPreferencesStore_SaveNotificationPreferences_Call {
+	return &NotificationPreferencesStore_SaveNotificationPreferences_Call{Call: _e.mock.On("SaveNotificationPreferences", ctx, userID, prefs, at)}
+}
+
+func (_c *NotificationPreferencesStore_SaveNotificationPreferences_Call) Run(run func(ctx context.Context, userID int64, prefs models.NotificationPreferences, at time.Time)) *NotificationPreferencesStore_SaveNotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(models.NotificationPreferences), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *NotificationPreferencesStore_SaveNotificationPreferences_Call) Return(_a0 error) *NotificationPreferencesStore_SaveNotificationPreferences_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *NotificationPreferencesStore_SaveNotificationPreferences_Call) RunAndReturn(run func(context.Context, int64, models.NotificationPreferences, time.Time) error) *NotificationPreferencesStore_SaveNotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewNotificationPreferencesStore creates a new instance of NotificationPreferencesStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotificationPreferencesStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NotificationPreferencesStore {
+	mock := &NotificationPreferencesStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,72 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Notifier is an autogenerated mock type for the Notifier type
+type Notifier struct {
+	mock.Mock
+}
+
+type Notifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Notifier) EXPECT() *Notifier_Expecter {
+	return &Notifier_Expecter{mock: &_m.Mock}
+}
+
+// PasswordChanged provides a mock function with given fields: ctx, email, reset, signedOut
+func (_m *Notifier) PasswordChanged(ctx context.Context, email string, reset bool, signedOut bool) {
+	_m.Called(ctx, email, reset, signedOut)
+}
+
+// Notifier_PasswordChanged_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PasswordChanged'
+type Notifier_PasswordChanged_Call struct {
+	*mock.Call
+}
+
+// PasswordChanged is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - reset bool
+//   - signedOut bool
+func (_e *Notifier_Expecter) PasswordChanged(ctx interface{}, email interface{}, reset interface{}, signedOut interface{}) *Notifier_PasswordChanged_Call {
+	return &Notifier_PasswordChanged_Call{Call: _e.mock.On("PasswordChanged", ctx, email, reset, signedOut)}
+}
+
+func (_c *Notifier_PasswordChanged_Call) Run(run func(ctx context.Context, email string, reset bool, signedOut bool)) *Notifier_PasswordChanged_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(bool), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *Notifier_PasswordChanged_Call) Return() *Notifier_PasswordChanged_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Notifier_PasswordChanged_Call) RunAndReturn(run func(context.Context, string, bool, bool)) *Notifier_PasswordChanged_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewNotifier creates a new instance of Notifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Notifier {
+	mock := &Notifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
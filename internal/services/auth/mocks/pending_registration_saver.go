@@ -0,0 +1,86 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PendingRegistrationSaver is an autogenerated mock type for the PendingRegistrationSaver type
+type PendingRegistrationSaver struct {
+	mock.Mock
+}
+
+type PendingRegistrationSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PendingRegistrationSaver) EXPECT() *PendingRegistrationSaver_Expecter {
+	return &PendingRegistrationSaver_Expecter{mock: &_m.Mock}
+}
+
+// SavePendingRegistration provides a mock function with given fields: ctx, email, passHash, expiresAt
+func (_m *PendingRegistrationSaver) SavePendingRegistration(ctx context.Context, email string, passHash []byte, expiresAt time.Time) error {
+	ret := _m.Called(ctx, email, passHash, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavePendingRegistration")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, time.Time) error); ok {
+		r0 = rf(ctx, email, passHash, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PendingRegistrationSaver_SavePendingRegistration_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SavePendingRegistration'
+type PendingRegistrationSaver_SavePendingRegistration_Call struct {
+	*mock.Call
+}
+
+// SavePendingRegistration is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - passHash []byte
+//   - expiresAt time.Time
+func (_e *PendingRegistrationSaver_Expecter) SavePendingRegistration(ctx interface{}, email interface{}, passHash interface{}, expiresAt interface{}) *PendingRegistrationSaver_SavePendingRegistration_Call {
+	return &PendingRegistrationSaver_SavePendingRegistration_Call{Call: _e.mock.On("SavePendingRegistration", ctx, email, passHash, expiresAt)}
+}
+
+func (_c *PendingRegistrationSaver_SavePendingRegistration_Call) Run(run func(ctx context.Context, email string, passHash []byte, expiresAt time.Time)) *PendingRegistrationSaver_SavePendingRegistration_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]byte), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PendingRegistrationSaver_SavePendingRegistration_Call) Return(_a0 error) *PendingRegistrationSaver_SavePendingRegistration_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PendingRegistrationSaver_SavePendingRegistration_Call) RunAndReturn(run func(context.Context, string, []byte, time.Time) error) *PendingRegistrationSaver_SavePendingRegistration_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPendingRegistrationSaver creates a new instance of PendingRegistrationSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPendingRegistrationSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PendingRegistrationSaver {
+	mock := &PendingRegistrationSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,191 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RefreshTokenStore is an autogenerated mock type for the RefreshTokenStore type
+type RefreshTokenStore struct {
+	mock.Mock
+}
+
+type RefreshTokenStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RefreshTokenStore) EXPECT() *RefreshTokenStore_Expecter {
+	return &RefreshTokenStore_Expecter{mock: &_m.Mock}
+}
+
+// RefreshToken provides a mock function with given fields: ctx, id
+func (_m *RefreshTokenStore) RefreshToken(ctx context.Context, id string) (models.RefreshToken, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 models.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.RefreshToken, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.RefreshToken); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.RefreshToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshTokenStore_RefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshToken'
+type RefreshTokenStore_RefreshToken_Call struct {
+	*mock.Call
+}
+
+// RefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *RefreshTokenStore_Expecter) RefreshToken(ctx interface{}, id interface{}) *RefreshTokenStore_RefreshToken_Call {
+	return &RefreshTokenStore_RefreshToken_Call{Call: _e.mock.On("RefreshToken", ctx, id)}
+}
+
+func (_c *RefreshTokenStore_RefreshToken_Call) Run(run func(ctx context.Context, id string)) *RefreshTokenStore_RefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenStore_RefreshToken_Call) Return(_a0 models.RefreshToken, _a1 error) *RefreshTokenStore_RefreshToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RefreshTokenStore_RefreshToken_Call) RunAndReturn(run func(context.Context, string) (models.RefreshToken, error)) *RefreshTokenStore_RefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveRefreshToken provides a mock function with given fields: ctx, token
+func (_m *RefreshTokenStore) SaveRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.RefreshToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenStore_SaveRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveRefreshToken'
+type RefreshTokenStore_SaveRefreshToken_Call struct {
+	*mock.Call
+}
+
+// SaveRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token models.RefreshToken
+func (_e *RefreshTokenStore_Expecter) SaveRefreshToken(ctx interface{}, token interface{}) *RefreshTokenStore_SaveRefreshToken_Call {
+	return &RefreshTokenStore_SaveRefreshToken_Call{Call: _e.mock.On("SaveRefreshToken", ctx, token)}
+}
+
+func (_c *RefreshTokenStore_SaveRefreshToken_Call) Run(run func(ctx context.Context, token models.RefreshToken)) *RefreshTokenStore_SaveRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.RefreshToken))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenStore_SaveRefreshToken_Call) Return(_a0 error) *RefreshTokenStore_SaveRefreshToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RefreshTokenStore_SaveRefreshToken_Call) RunAndReturn(run func(context.Context, models.RefreshToken) error) *RefreshTokenStore_SaveRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRefreshToken provides a mock function with given fields: ctx, id, at
+func (_m *RefreshTokenStore) UseRefreshToken(ctx context.Context, id string, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenStore_UseRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRefreshToken'
+type RefreshTokenStore_UseRefreshToken_Call struct {
+	*mock.Call
+}
+
+// UseRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - at time.Time
+func (_e *RefreshTokenStore_Expecter) UseRefreshToken(ctx interface{}, id interface{}, at interface{}) *RefreshTokenStore_UseRefreshToken_Call {
+	return &RefreshTokenStore_UseRefreshToken_Call{Call: _e.mock.On("UseRefreshToken", ctx, id, at)}
+}
+
+func (_c *RefreshTokenStore_UseRefreshToken_Call) Run(run func(ctx context.Context, id string, at time.Time)) *RefreshTokenStore_UseRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenStore_UseRefreshToken_Call) Return(_a0 error) *RefreshTokenStore_UseRefreshToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RefreshTokenStore_UseRefreshToken_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *RefreshTokenStore_UseRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRefreshTokenStore creates a new instance of RefreshTokenStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRefreshTokenStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RefreshTokenStore {
+	mock := &RefreshTokenStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
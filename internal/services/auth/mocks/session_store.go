@@ -0,0 +1,358 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionStore is an autogenerated mock type for the SessionStore type
+type SessionStore struct {
+	mock.Mock
+}
+
+type SessionStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SessionStore) EXPECT() *SessionStore_Expecter {
+	return &SessionStore_Expecter{mock: &_m.Mock}
+}
+
+// ConsumeSession provides a mock function with given fields: ctx, id, at
+func (_m *SessionStore) ConsumeSession(ctx context.Context, id string, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionStore_ConsumeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeSession'
+type SessionStore_ConsumeSession_Call struct {
+	*mock.Call
+}
+
+// ConsumeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - at time.Time
+func (_e *SessionStore_Expecter) ConsumeSession(ctx interface{}, id interface{}, at interface{}) *SessionStore_ConsumeSession_Call {
+	return &SessionStore_ConsumeSession_Call{Call: _e.mock.On("ConsumeSession", ctx, id, at)}
+}
+
+func (_c *SessionStore_ConsumeSession_Call) Run(run func(ctx context.Context, id string, at time.Time)) *SessionStore_ConsumeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionStore_ConsumeSession_Call) Return(_a0 error) *SessionStore_ConsumeSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionStore_ConsumeSession_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *SessionStore_ConsumeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeOtherUserSessions provides a mock function with given fields: ctx, userID, keepID, at
+func (_m *SessionStore) RevokeOtherUserSessions(ctx context.Context, userID int64, keepID string, at time.Time) (int, error) {
+	ret := _m.Called(ctx, userID, keepID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeOtherUserSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Time) (int, error)); ok {
+		return rf(ctx, userID, keepID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Time) int); ok {
+		r0 = rf(ctx, userID, keepID, at)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, keepID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionStore_RevokeOtherUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeOtherUserSessions'
+type SessionStore_RevokeOtherUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeOtherUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - keepID string
+//   - at time.Time
+func (_e *SessionStore_Expecter) RevokeOtherUserSessions(ctx interface{}, userID interface{}, keepID interface{}, at interface{}) *SessionStore_RevokeOtherUserSessions_Call {
+	return &SessionStore_RevokeOtherUserSessions_Call{Call: _e.mock.On("RevokeOtherUserSessions", ctx, userID, keepID, at)}
+}
+
+func (_c *SessionStore_RevokeOtherUserSessions_Call) Run(run func(ctx context.Context, userID int64, keepID string, at time.Time)) *SessionStore_RevokeOtherUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionStore_RevokeOtherUserSessions_Call) Return(_a0 int, _a1 error) *SessionStore_RevokeOtherUserSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionStore_RevokeOtherUserSessions_Call) RunAndReturn(run func(context.Context, int64, string, time.Time) (int, error)) *SessionStore_RevokeOtherUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSSOSession provides a mock function with given fields: ctx, ssoSessionID, at
+func (_m *SessionStore) RevokeSSOSession(ctx context.Context, ssoSessionID string, at time.Time) ([]models.Session, error) {
+	ret := _m.Called(ctx, ssoSessionID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSSOSession")
+	}
+
+	var r0 []models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) ([]models.Session, error)); ok {
+		return rf(ctx, ssoSessionID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []models.Session); ok {
+		r0 = rf(ctx, ssoSessionID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, ssoSessionID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionStore_RevokeSSOSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSSOSession'
+type SessionStore_RevokeSSOSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSSOSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ssoSessionID string
+//   - at time.Time
+func (_e *SessionStore_Expecter) RevokeSSOSession(ctx interface{}, ssoSessionID interface{}, at interface{}) *SessionStore_RevokeSSOSession_Call {
+	return &SessionStore_RevokeSSOSession_Call{Call: _e.mock.On("RevokeSSOSession", ctx, ssoSessionID, at)}
+}
+
+func (_c *SessionStore_RevokeSSOSession_Call) Run(run func(ctx context.Context, ssoSessionID string, at time.Time)) *SessionStore_RevokeSSOSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionStore_RevokeSSOSession_Call) Return(_a0 []models.Session, _a1 error) *SessionStore_RevokeSSOSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionStore_RevokeSSOSession_Call) RunAndReturn(run func(context.Context, string, time.Time) ([]models.Session, error)) *SessionStore_RevokeSSOSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function with given fields: ctx, id, at
+func (_m *SessionStore) RevokeSession(ctx context.Context, id string, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionStore_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type SessionStore_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - at time.Time
+func (_e *SessionStore_Expecter) RevokeSession(ctx interface{}, id interface{}, at interface{}) *SessionStore_RevokeSession_Call {
+	return &SessionStore_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, id, at)}
+}
+
+func (_c *SessionStore_RevokeSession_Call) Run(run func(ctx context.Context, id string, at time.Time)) *SessionStore_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionStore_RevokeSession_Call) Return(_a0 error) *SessionStore_RevokeSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionStore_RevokeSession_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *SessionStore_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveSession provides a mock function with given fields: ctx, session
+func (_m *SessionStore) SaveSession(ctx context.Context, session models.Session) error {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Session) error); ok {
+		r0 = rf(ctx, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionStore_SaveSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSession'
+type SessionStore_SaveSession_Call struct {
+	*mock.Call
+}
+
+// SaveSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session models.Session
+func (_e *SessionStore_Expecter) SaveSession(ctx interface{}, session interface{}) *SessionStore_SaveSession_Call {
+	return &SessionStore_SaveSession_Call{Call: _e.mock.On("SaveSession", ctx, session)}
+}
+
+func (_c *SessionStore_SaveSession_Call) Run(run func(ctx context.Context, session models.Session)) *SessionStore_SaveSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Session))
+	})
+	return _c
+}
+
+func (_c *SessionStore_SaveSession_Call) Return(_a0 error) *SessionStore_SaveSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionStore_SaveSession_Call) RunAndReturn(run func(context.Context, models.Session) error) *SessionStore_SaveSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Session provides a mock function with given fields: ctx, id
+func (_m *SessionStore) Session(ctx context.Context, id string) (models.Session, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Session")
+	}
+
+	var r0 models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.Session, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.Session); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionStore_Session_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Session'
+type SessionStore_Session_Call struct {
+	*mock.Call
+}
+
+// Session is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *SessionStore_Expecter) Session(ctx interface{}, id interface{}) *SessionStore_Session_Call {
+	return &SessionStore_Session_Call{Call: _e.mock.On("Session", ctx, id)}
+}
+
+func (_c *SessionStore_Session_Call) Run(run func(ctx context.Context, id string)) *SessionStore_Session_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *SessionStore_Session_Call) Return(_a0 models.Session, _a1 error) *SessionStore_Session_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionStore_Session_Call) RunAndReturn(run func(context.Context, string) (models.Session, error)) *SessionStore_Session_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSessionStore creates a new instance of SessionStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionStore {
+	mock := &SessionStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
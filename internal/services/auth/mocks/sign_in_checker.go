@@ -0,0 +1,87 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SignInChecker is an autogenerated mock type for the SignInChecker type
+type SignInChecker struct {
+	mock.Mock
+}
+
+type SignInChecker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SignInChecker) EXPECT() *SignInChecker_Expecter {
+	return &SignInChecker_Expecter{mock: &_m.Mock}
+}
+
+// Check provides a mock function with given fields: ctx, user, appID, device, code
+func (_m *SignInChecker) Check(ctx context.Context, user models.User, appID int, device models.Device, code string) error {
+	ret := _m.Called(ctx, user, appID, device, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.User, int, models.Device, string) error); ok {
+		r0 = rf(ctx, user, appID, device, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SignInChecker_Check_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Check'
+type SignInChecker_Check_Call struct {
+	*mock.Call
+}
+
+// Check is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user models.User
+//   - appID int
+//   - device models.Device
+//   - code string
+func (_e *SignInChecker_Expecter) Check(ctx interface{}, user interface{}, appID interface{}, device interface{}, code interface{}) *SignInChecker_Check_Call {
+	return &SignInChecker_Check_Call{Call: _e.mock.On("Check", ctx, user, appID, device, code)}
+}
+
+func (_c *SignInChecker_Check_Call) Run(run func(ctx context.Context, user models.User, appID int, device models.Device, code string)) *SignInChecker_Check_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.User), args[2].(int), args[3].(models.Device), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *SignInChecker_Check_Call) Return(_a0 error) *SignInChecker_Check_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SignInChecker_Check_Call) RunAndReturn(run func(context.Context, models.User, int, models.Device, string) error) *SignInChecker_Check_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSignInChecker creates a new instance of SignInChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSignInChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SignInChecker {
+	mock := &SignInChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,210 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserSaver is an autogenerated mock type for the UserSaver type
+type UserSaver struct {
+	mock.Mock
+}
+
+type UserSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserSaver) EXPECT() *UserSaver_Expecter {
+	return &UserSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveUser provides a mock function with given fields: ctx, email, passHash
+func (_m *UserSaver) SaveUser(ctx context.Context, email string, passHash []byte) (int64, error) {
+	ret := _m.Called(ctx, email, passHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) (int64, error)); ok {
+		return rf(ctx, email, passHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) int64); ok {
+		r0 = rf(ctx, email, passHash)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte) error); ok {
+		r1 = rf(ctx, email, passHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserSaver_SaveUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUser'
+type UserSaver_SaveUser_Call struct {
+	*mock.Call
+}
+
+// SaveUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - passHash []byte
+func (_e *UserSaver_Expecter) SaveUser(ctx interface{}, email interface{}, passHash interface{}) *UserSaver_SaveUser_Call {
+	return &UserSaver_SaveUser_Call{Call: _e.mock.On("SaveUser", ctx, email, passHash)}
+}
+
+func (_c *UserSaver_SaveUser_Call) Run(run func(ctx context.Context, email string, passHash []byte)) *UserSaver_SaveUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]byte))
+	})
+	return _c
+}
+
+func (_c *UserSaver_SaveUser_Call) Return(uid int64, err error) *UserSaver_SaveUser_Call {
+	_c.Call.Return(uid, err)
+	return _c
+}
+
+func (_c *UserSaver_SaveUser_Call) RunAndReturn(run func(context.Context, string, []byte) (int64, error)) *UserSaver_SaveUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUser provides a mock function with given fields: ctx, user, passHash
+func (_m *UserSaver) UpdateUser(ctx context.Context, user models.User, passHash []byte) (int64, error) {
+	ret := _m.Called(ctx, user, passHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.User, []byte) (int64, error)); ok {
+		return rf(ctx, user, passHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.User, []byte) int64); ok {
+		r0 = rf(ctx, user, passHash)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.User, []byte) error); ok {
+		r1 = rf(ctx, user, passHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserSaver_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
+type UserSaver_UpdateUser_Call struct {
+	*mock.Call
+}
+
+// UpdateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user models.User
+//   - passHash []byte
+func (_e *UserSaver_Expecter) UpdateUser(ctx interface{}, user interface{}, passHash interface{}) *UserSaver_UpdateUser_Call {
+	return &UserSaver_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, user, passHash)}
+}
+
+func (_c *UserSaver_UpdateUser_Call) Run(run func(ctx context.Context, user models.User, passHash []byte)) *UserSaver_UpdateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.User), args[2].([]byte))
+	})
+	return _c
+}
+
+func (_c *UserSaver_UpdateUser_Call) Return(uid int64, err error) *UserSaver_UpdateUser_Call {
+	_c.Call.Return(uid, err)
+	return _c
+}
+
+func (_c *UserSaver_UpdateUser_Call) RunAndReturn(run func(context.Context, models.User, []byte) (int64, error)) *UserSaver_UpdateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyUser provides a mock function with given fields: ctx, email
+func (_m *UserSaver) VerifyUser(ctx context.Context, email string) (int64, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserSaver_VerifyUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyUser'
+type UserSaver_VerifyUser_Call struct {
+	*mock.Call
+}
+
+// VerifyUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *UserSaver_Expecter) VerifyUser(ctx interface{}, email interface{}) *UserSaver_VerifyUser_Call {
+	return &UserSaver_VerifyUser_Call{Call: _e.mock.On("VerifyUser", ctx, email)}
+}
+
+func (_c *UserSaver_VerifyUser_Call) Run(run func(ctx context.Context, email string)) *UserSaver_VerifyUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserSaver_VerifyUser_Call) Return(_a0 int64, _a1 error) *UserSaver_VerifyUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserSaver_VerifyUser_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *UserSaver_VerifyUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserSaver creates a new instance of UserSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserSaver {
+	mock := &UserSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/storage"
+)
+
+// UpdateNotificationPreferences replaces categories of non-essential emails the user receives.
+// Changes are audited, so security notifications silenced by whoever took over the account are noticed.
+func (a *Auth) UpdateNotificationPreferences(
+	ctx context.Context,
+	userID int64,
+	prefs models.NotificationPreferences,
+) error {
+	const op = "Auth.UpdateNotificationPreferences"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	// The user may be deleted after the token was issued.
+	if _, err := a.usrProvider.UserByID(ctx, userID); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.preferences.SaveNotificationPreferences(ctx, userID, prefs, a.clock.Now().UTC()); err != nil {
+		log.Error("failed to save notification preferences", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("notification preferences updated")
+
+	a.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionNotificationPreferencesChanged,
+		ActorID: userID,
+		Payload: map[string]string{
+			string(models.EmailCategoryReminders): strconv.FormatBool(prefs.Reminders),
+			string(models.EmailCategorySecurity):  strconv.FormatBool(prefs.Security),
+			string(models.EmailCategoryProduct):   strconv.FormatBool(prefs.Product),
+		},
+	})
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/storage"
+)
+
+// Profile is the user of a token as of now, with the session the token belongs to.
+type Profile struct {
+	User    models.User
+	IsAdmin bool
+	// Orgs are current memberships of the user, they may differ from roles put to the token when it was issued.
+	Orgs        []models.OrganizationMember
+	Session     models.Session
+	Preferences models.NotificationPreferences
+}
+
+// WhoAmI returns profile of the user the authenticated token is issued to.
+func (a *Auth) WhoAmI(ctx context.Context, claims jwt.Claims) (Profile, error) {
+	const op = "Auth.WhoAmI"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", claims.UserID),
+	)
+
+	user, err := a.usrProvider.UserByID(ctx, claims.UserID)
+	if err != nil {
+		// The user may be deleted after the token was issued.
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return Profile{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	isAdmin, err := a.usrProvider.IsAdmin(ctx, user.ID)
+	if err != nil {
+		log.Error("failed to check admin", sl.Err(err))
+
+		return Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	orgs, err := a.orgs.Memberships(ctx, user.ID)
+	if err != nil {
+		log.Error("failed to get memberships", sl.Err(err))
+
+		return Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	session, err := a.sessions.Session(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return Profile{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get session", sl.Err(err))
+
+		return Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	prefs, err := a.preferences.NotificationPreferences(ctx, user.Email)
+	if err != nil {
+		log.Error("failed to get notification preferences", sl.Err(err))
+
+		return Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return Profile{
+		User:        user,
+		IsAdmin:     isAdmin,
+		Orgs:        orgs,
+		Session:     session,
+		Preferences: prefs,
+	}, nil
+}
@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/storage"
+)
+
+// RefreshToken is a refresh token shown to the client once, only its hash is stored.
+type RefreshToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// IssueRefreshToken issues refresh token within SSO session of the access token, so logout and revocation
+// of sessions of the user end it too. Token is empty if refresh tokens are disabled.
+// Non-empty fingerprint of the client binds the token to it, see Refresh.
+func (a *Auth) IssueRefreshToken(ctx context.Context, claims jwt.Claims, fingerprint string) (RefreshToken, error) {
+	const op = "Auth.IssueRefreshToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", claims.UserID),
+	)
+
+	if a.refreshTokenTTL == 0 {
+		return RefreshToken{}, nil
+	}
+
+	// One-time tokens and tokens of service accounts are not refreshed.
+	if claims.UserID == 0 || claims.Elevated {
+		return RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	ssoSession, err := a.ssoSession(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get sso session", sl.Err(err))
+
+		return RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	refresh, err := a.saveRefreshToken(ctx, ssoSession.ID, claims.UserID, claims.AppID, fingerprint)
+	if err != nil {
+		log.Error("failed to save refresh token", sl.Err(err))
+
+		return RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return refresh, nil
+}
+
+// Refresh exchanges refresh token for a new access token and a new refresh token, the exchanged one
+// is rejected from then on. Exchange of an already exchanged token means it leaked: SSO session
+// of the token is revoked, so neither the thief nor the user can refresh it anymore.
+//
+// Token bound to a client is exchanged only by the client with the same fingerprint, new token is bound to it too.
+// Exchange by another client is rejected without using the token, so the client it's bound to keeps it.
+func (a *Auth) Refresh(ctx context.Context, refreshToken string, fingerprint string) (string, RefreshToken, error) {
+	const op = "Auth.Refresh"
+
+	log := a.log.With(slog.String("op", op))
+
+	if a.refreshTokenTTL == 0 {
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	id := hashRefreshToken(refreshToken)
+
+	stored, err := a.refreshTokens.RefreshToken(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+			return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+
+		log.Error("failed to get refresh token", sl.Err(err))
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", stored.UserID), slog.String("sso_session", stored.SessionID))
+
+	now := a.clock.Now().UTC()
+	if !now.Before(stored.ExpiresAt) {
+		log.Info("refresh token is expired")
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored.Fingerprint), []byte(fingerprint)) != 1 {
+		log.Warn("refresh token is used by another client")
+
+		a.auditor.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionRefreshTokenMismatch,
+			ActorID: stored.UserID,
+			AppID:   stored.AppID,
+			Payload: map[string]string{"sso_session": stored.SessionID},
+		})
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	ssoSession, err := a.sessions.Session(ctx, stored.SessionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+
+		log.Error("failed to get sso session", sl.Err(err))
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !ssoSession.RevokedAt.IsZero() {
+		log.Info("sso session of refresh token is revoked")
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	if err := a.refreshTokens.UseRefreshToken(ctx, id, now); err != nil {
+		if errors.Is(err, storage.ErrRefreshTokenUsed) {
+			a.revokeReusedRefreshToken(ctx, log, stored)
+
+			return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+
+		log.Error("failed to use refresh token", sl.Err(err))
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.appProvider.App(ctx, stored.AppID)
+	if err != nil {
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !user.Verified && (a.requireVerified || app.RequireVerified) {
+		log.Info("user email is not verified")
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, ErrUserNotVerified)
+	}
+
+	if err := a.checkBan(ctx, user.ID, app.ID); err != nil {
+		if errors.Is(err, ErrUserBanned) {
+			log.Info("user is banned from the app")
+		} else {
+			log.Error("failed to check ban", sl.Err(err))
+		}
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := a.issueToken(ctx, user, app, a.tokenTTL, false, stored.SessionID)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	refresh, err := a.saveRefreshToken(ctx, stored.SessionID, user.ID, app.ID, stored.Fingerprint)
+	if err != nil {
+		log.Error("failed to save refresh token", sl.Err(err))
+
+		return "", RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token refreshed")
+
+	return token, refresh, nil
+}
+
+// revokeReusedRefreshToken revokes SSO session of the refresh token exchanged twice. Failure is logged only,
+// the exchange is rejected anyway.
+func (a *Auth) revokeReusedRefreshToken(ctx context.Context, log *slog.Logger, stored models.RefreshToken) {
+	log.Warn("refresh token is reused, revoking sso session")
+
+	if _, err := a.sessions.RevokeSSOSession(ctx, stored.SessionID, a.clock.Now().UTC()); err != nil {
+		log.Error("failed to revoke sso session", sl.Err(err))
+	}
+
+	a.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionRefreshTokenReused,
+		ActorID: stored.UserID,
+		AppID:   stored.AppID,
+		Payload: map[string]string{"sso_session": stored.SessionID},
+	})
+}
+
+func (a *Auth) saveRefreshToken(ctx context.Context, ssoSessionID string, userID int64, appID int, fingerprint string) (RefreshToken, error) {
+	token, err := random.Secret(a.random)
+	if err != nil {
+		return RefreshToken{}, err
+	}
+
+	now := a.clock.Now().UTC()
+	stored := models.RefreshToken{
+		ID:          hashRefreshToken(token),
+		SessionID:   ssoSessionID,
+		UserID:      userID,
+		AppID:       appID,
+		Fingerprint: fingerprint,
+		IssuedAt:    now,
+		ExpiresAt:   now.Add(a.refreshTokenTTL),
+	}
+
+	if err := a.refreshTokens.SaveRefreshToken(ctx, stored); err != nil {
+		return RefreshToken{}, err
+	}
+
+	return RefreshToken{Token: token, ExpiresAt: stored.ExpiresAt}, nil
+}
+
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
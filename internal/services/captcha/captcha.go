@@ -0,0 +1,134 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/lib/logger/sl"
+)
+
+const (
+	ProviderReCaptcha = "recaptcha"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+// siteverify endpoints, all providers share the same request and response format.
+var verifyURLs = map[string]string{
+	ProviderReCaptcha: "https://www.google.com/recaptcha/api/siteverify",
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+var (
+	ErrTokenRequired = errors.New("captcha token is required")
+	ErrInvalidToken  = errors.New("captcha token is invalid")
+)
+
+// Captcha verifies captcha tokens solved by clients with the provider siteverify API.
+type Captcha struct {
+	log       *slog.Logger
+	client    *http.Client
+	verifyURL string
+	secret    string
+	// minScore is a minimal score of reCAPTCHA v3 tokens, ignored if provider doesn't return score.
+	minScore float64
+}
+
+type verifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func New(
+	log *slog.Logger,
+	provider string,
+	secret string,
+	minScore float64,
+	timeout time.Duration,
+) (*Captcha, error) {
+	const op = "captcha.New"
+
+	verifyURL, ok := verifyURLs[provider]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown captcha provider %q", op, provider)
+	}
+
+	if secret == "" {
+		return nil, fmt.Errorf("%s: captcha secret is required", op)
+	}
+
+	return &Captcha{
+		log:       log,
+		client:    &http.Client{Timeout: timeout},
+		verifyURL: verifyURL,
+		secret:    secret,
+		minScore:  minScore,
+	}, nil
+}
+
+// Verify checks captcha token solved by the client with the given IP.
+func (c *Captcha) Verify(ctx context.Context, token string, remoteIP string) error {
+	const op = "Captcha.Verify"
+
+	log := c.log.With(
+		slog.String("op", op),
+	)
+
+	if token == "" {
+		return fmt.Errorf("%s: %w", op, ErrTokenRequired)
+	}
+
+	form := url.Values{
+		"secret":   {c.secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Error("failed to verify captcha", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %d", op, resp.StatusCode)
+	}
+
+	var res verifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !res.Success {
+		log.Info("captcha rejected", slog.Any("error_codes", res.ErrorCodes))
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if res.Score != nil && *res.Score < c.minScore {
+		log.Info("captcha score is too low", slog.Float64("score", *res.Score))
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	return nil
+}
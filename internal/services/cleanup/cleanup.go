@@ -0,0 +1,155 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+type ExpiredVerificationsDeleter interface {
+	DeleteExpiredVerifications(ctx context.Context, before time.Time) (int64, error)
+	DeleteExpiredPhoneVerifications(ctx context.Context, before time.Time) (int64, error)
+}
+
+type ExpiredPendingRegistrationsDeleter interface {
+	DeleteExpiredPendingRegistrations(ctx context.Context, before time.Time) (int64, error)
+}
+
+type StaleLoginFailuresDeleter interface {
+	DeleteStaleLoginFailures(ctx context.Context, before time.Time) (int64, error)
+}
+
+type ExpiredSessionsDeleter interface {
+	DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error)
+	DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error)
+}
+
+type ExpiredDeviceLoginsDeleter interface {
+	DeleteExpiredDeviceLogins(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Cleanup deletes records which are of no use once expired, its methods are run as scheduled jobs.
+type Cleanup struct {
+	log                  *slog.Logger
+	verifications        ExpiredVerificationsDeleter
+	pendingRegistrations ExpiredPendingRegistrationsDeleter
+	loginFailures        StaleLoginFailuresDeleter
+	sessions             ExpiredSessionsDeleter
+	deviceLogins         ExpiredDeviceLoginsDeleter
+	// loginFailuresWindow is how long failed logins are remembered by throttles.
+	loginFailuresWindow time.Duration
+}
+
+func New(
+	log *slog.Logger,
+	verifications ExpiredVerificationsDeleter,
+	pendingRegistrations ExpiredPendingRegistrationsDeleter,
+	loginFailures StaleLoginFailuresDeleter,
+	sessions ExpiredSessionsDeleter,
+	deviceLogins ExpiredDeviceLoginsDeleter,
+	loginFailuresWindow time.Duration,
+) *Cleanup {
+	return &Cleanup{
+		log:                  log,
+		verifications:        verifications,
+		pendingRegistrations: pendingRegistrations,
+		loginFailures:        loginFailures,
+		sessions:             sessions,
+		deviceLogins:         deviceLogins,
+		loginFailuresWindow:  loginFailuresWindow,
+	}
+}
+
+// Verifications deletes expired email and phone verifications.
+func (c *Cleanup) Verifications(ctx context.Context) error {
+	const op = "Cleanup.Verifications"
+
+	now := time.Now().UTC()
+
+	emails, err := c.verifications.DeleteExpiredVerifications(ctx, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	phones, err := c.verifications.DeleteExpiredPhoneVerifications(ctx, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("expired verifications deleted",
+		slog.String("op", op),
+		slog.Int64("email", emails),
+		slog.Int64("phone", phones),
+	)
+
+	return nil
+}
+
+// PendingRegistrations deletes pending registrations which were not verified in time.
+func (c *Cleanup) PendingRegistrations(ctx context.Context) error {
+	const op = "Cleanup.PendingRegistrations"
+
+	n, err := c.pendingRegistrations.DeleteExpiredPendingRegistrations(ctx, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("expired pending registrations deleted", slog.String("op", op), slog.Int64("count", n))
+
+	return nil
+}
+
+// LoginFailures deletes failed login counters which throttles don't remember anymore.
+func (c *Cleanup) LoginFailures(ctx context.Context) error {
+	const op = "Cleanup.LoginFailures"
+
+	n, err := c.loginFailures.DeleteStaleLoginFailures(ctx, time.Now().UTC().Add(-c.loginFailuresWindow))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("stale login failures deleted", slog.String("op", op), slog.Int64("count", n))
+
+	return nil
+}
+
+// Sessions deletes sessions of expired tokens, expired tokens are rejected without checking their sessions.
+// Expired refresh tokens are deleted first, so SSO sessions kept for them are deleted along.
+func (c *Cleanup) Sessions(ctx context.Context) error {
+	const op = "Cleanup.Sessions"
+
+	now := time.Now().UTC()
+
+	refreshTokens, err := c.sessions.DeleteExpiredRefreshTokens(ctx, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := c.sessions.DeleteExpiredSessions(ctx, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("expired sessions deleted",
+		slog.String("op", op),
+		slog.Int64("count", n),
+		slog.Int64("refresh_tokens", refreshTokens),
+	)
+
+	return nil
+}
+
+// DeviceLogins deletes device logins which were not approved or completed in time.
+func (c *Cleanup) DeviceLogins(ctx context.Context) error {
+	const op = "Cleanup.DeviceLogins"
+
+	n, err := c.deviceLogins.DeleteExpiredDeviceLogins(ctx, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("expired device logins deleted", slog.String("op", op), slog.Int64("count", n))
+
+	return nil
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExpiredDeviceLoginsDeleter is an autogenerated mock type for the ExpiredDeviceLoginsDeleter type
+type ExpiredDeviceLoginsDeleter struct {
+	mock.Mock
+}
+
+type ExpiredDeviceLoginsDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ExpiredDeviceLoginsDeleter) EXPECT() *ExpiredDeviceLoginsDeleter_Expecter {
+	return &ExpiredDeviceLoginsDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteExpiredDeviceLogins provides a mock function with given fields: ctx, before
+func (_m *ExpiredDeviceLoginsDeleter) DeleteExpiredDeviceLogins(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredDeviceLogins")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredDeviceLogins'
+type ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredDeviceLogins is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *ExpiredDeviceLoginsDeleter_Expecter) DeleteExpiredDeviceLogins(ctx interface{}, before interface{}) *ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call {
+	return &ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call{Call: _e.mock.On("DeleteExpiredDeviceLogins", ctx, before)}
+}
+
+func (_c *ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call) Run(run func(ctx context.Context, before time.Time)) *ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call) Return(_a0 int64, _a1 error) *ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *ExpiredDeviceLoginsDeleter_DeleteExpiredDeviceLogins_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExpiredDeviceLoginsDeleter creates a new instance of ExpiredDeviceLoginsDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExpiredDeviceLoginsDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExpiredDeviceLoginsDeleter {
+	mock := &ExpiredDeviceLoginsDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
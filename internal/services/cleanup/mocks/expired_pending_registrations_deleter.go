@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExpiredPendingRegistrationsDeleter is an autogenerated mock type for the ExpiredPendingRegistrationsDeleter type
+type ExpiredPendingRegistrationsDeleter struct {
+	mock.Mock
+}
+
+type ExpiredPendingRegistrationsDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ExpiredPendingRegistrationsDeleter) EXPECT() *ExpiredPendingRegistrationsDeleter_Expecter {
+	return &ExpiredPendingRegistrationsDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteExpiredPendingRegistrations provides a mock function with given fields: ctx, before
+func (_m *ExpiredPendingRegistrationsDeleter) DeleteExpiredPendingRegistrations(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredPendingRegistrations")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredPendingRegistrations'
+type ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredPendingRegistrations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *ExpiredPendingRegistrationsDeleter_Expecter) DeleteExpiredPendingRegistrations(ctx interface{}, before interface{}) *ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call {
+	return &ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call{Call: _e.mock.On("DeleteExpiredPendingRegistrations", ctx, before)}
+}
+
+func (_c *ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call) Run(run func(ctx context.Context, before time.Time)) *ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call) Return(_a0 int64, _a1 error) *ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *ExpiredPendingRegistrationsDeleter_DeleteExpiredPendingRegistrations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExpiredPendingRegistrationsDeleter creates a new instance of ExpiredPendingRegistrationsDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExpiredPendingRegistrationsDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExpiredPendingRegistrationsDeleter {
+	mock := &ExpiredPendingRegistrationsDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
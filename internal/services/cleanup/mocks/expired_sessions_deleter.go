@@ -0,0 +1,151 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExpiredSessionsDeleter is an autogenerated mock type for the ExpiredSessionsDeleter type
+type ExpiredSessionsDeleter struct {
+	mock.Mock
+}
+
+type ExpiredSessionsDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ExpiredSessionsDeleter) EXPECT() *ExpiredSessionsDeleter_Expecter {
+	return &ExpiredSessionsDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteExpiredRefreshTokens provides a mock function with given fields: ctx, before
+func (_m *ExpiredSessionsDeleter) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredRefreshTokens")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredRefreshTokens'
+type ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredRefreshTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *ExpiredSessionsDeleter_Expecter) DeleteExpiredRefreshTokens(ctx interface{}, before interface{}) *ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call {
+	return &ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call{Call: _e.mock.On("DeleteExpiredRefreshTokens", ctx, before)}
+}
+
+func (_c *ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call) Run(run func(ctx context.Context, before time.Time)) *ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call) Return(_a0 int64, _a1 error) *ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *ExpiredSessionsDeleter_DeleteExpiredRefreshTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpiredSessions provides a mock function with given fields: ctx, before
+func (_m *ExpiredSessionsDeleter) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredSessions")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpiredSessionsDeleter_DeleteExpiredSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredSessions'
+type ExpiredSessionsDeleter_DeleteExpiredSessions_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *ExpiredSessionsDeleter_Expecter) DeleteExpiredSessions(ctx interface{}, before interface{}) *ExpiredSessionsDeleter_DeleteExpiredSessions_Call {
+	return &ExpiredSessionsDeleter_DeleteExpiredSessions_Call{Call: _e.mock.On("DeleteExpiredSessions", ctx, before)}
+}
+
+func (_c *ExpiredSessionsDeleter_DeleteExpiredSessions_Call) Run(run func(ctx context.Context, before time.Time)) *ExpiredSessionsDeleter_DeleteExpiredSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ExpiredSessionsDeleter_DeleteExpiredSessions_Call) Return(_a0 int64, _a1 error) *ExpiredSessionsDeleter_DeleteExpiredSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExpiredSessionsDeleter_DeleteExpiredSessions_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *ExpiredSessionsDeleter_DeleteExpiredSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExpiredSessionsDeleter creates a new instance of ExpiredSessionsDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExpiredSessionsDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExpiredSessionsDeleter {
+	mock := &ExpiredSessionsDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
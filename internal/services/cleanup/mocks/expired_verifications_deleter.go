@@ -0,0 +1,151 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExpiredVerificationsDeleter is an autogenerated mock type for the ExpiredVerificationsDeleter type
+type ExpiredVerificationsDeleter struct {
+	mock.Mock
+}
+
+type ExpiredVerificationsDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ExpiredVerificationsDeleter) EXPECT() *ExpiredVerificationsDeleter_Expecter {
+	return &ExpiredVerificationsDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteExpiredPhoneVerifications provides a mock function with given fields: ctx, before
+func (_m *ExpiredVerificationsDeleter) DeleteExpiredPhoneVerifications(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredPhoneVerifications")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredPhoneVerifications'
+type ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredPhoneVerifications is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *ExpiredVerificationsDeleter_Expecter) DeleteExpiredPhoneVerifications(ctx interface{}, before interface{}) *ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call {
+	return &ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call{Call: _e.mock.On("DeleteExpiredPhoneVerifications", ctx, before)}
+}
+
+func (_c *ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call) Run(run func(ctx context.Context, before time.Time)) *ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call) Return(_a0 int64, _a1 error) *ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *ExpiredVerificationsDeleter_DeleteExpiredPhoneVerifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpiredVerifications provides a mock function with given fields: ctx, before
+func (_m *ExpiredVerificationsDeleter) DeleteExpiredVerifications(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredVerifications")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredVerifications'
+type ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredVerifications is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *ExpiredVerificationsDeleter_Expecter) DeleteExpiredVerifications(ctx interface{}, before interface{}) *ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call {
+	return &ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call{Call: _e.mock.On("DeleteExpiredVerifications", ctx, before)}
+}
+
+func (_c *ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call) Run(run func(ctx context.Context, before time.Time)) *ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call) Return(_a0 int64, _a1 error) *ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *ExpiredVerificationsDeleter_DeleteExpiredVerifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExpiredVerificationsDeleter creates a new instance of ExpiredVerificationsDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExpiredVerificationsDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExpiredVerificationsDeleter {
+	mock := &ExpiredVerificationsDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StaleLoginFailuresDeleter is an autogenerated mock type for the StaleLoginFailuresDeleter type
+type StaleLoginFailuresDeleter struct {
+	mock.Mock
+}
+
+type StaleLoginFailuresDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *StaleLoginFailuresDeleter) EXPECT() *StaleLoginFailuresDeleter_Expecter {
+	return &StaleLoginFailuresDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteStaleLoginFailures provides a mock function with given fields: ctx, before
+func (_m *StaleLoginFailuresDeleter) DeleteStaleLoginFailures(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStaleLoginFailures")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStaleLoginFailures'
+type StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call struct {
+	*mock.Call
+}
+
+// DeleteStaleLoginFailures is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *StaleLoginFailuresDeleter_Expecter) DeleteStaleLoginFailures(ctx interface{}, before interface{}) *StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call {
+	return &StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call{Call: _e.mock.On("DeleteStaleLoginFailures", ctx, before)}
+}
+
+func (_c *StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call) Run(run func(ctx context.Context, before time.Time)) *StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call) Return(_a0 int64, _a1 error) *StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *StaleLoginFailuresDeleter_DeleteStaleLoginFailures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStaleLoginFailuresDeleter creates a new instance of StaleLoginFailuresDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStaleLoginFailuresDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StaleLoginFailuresDeleter {
+	mock := &StaleLoginFailuresDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,271 @@
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/lib/verification"
+	verificationService "grpc-service-ref/internal/services/verification"
+	"grpc-service-ref/internal/storage"
+)
+
+// purgeBatchSize is how many accounts are deleted by a single run of Purge.
+const purgeBatchSize = 100
+
+// ErrInvalidCode is returned for wrong, expired or exhausted confirmation code.
+var ErrInvalidCode = errors.New("invalid account deletion confirmation code")
+
+type Store interface {
+	SaveAccountDeletion(ctx context.Context, deletion models.AccountDeletion) error
+	DueAccountDeletions(ctx context.Context, before time.Time, limit int) ([]models.AccountDeletion, error)
+}
+
+// UserDeleter deletes users, it must invalidate cached users, see cache.Users.
+type UserDeleter interface {
+	DeleteScheduledUser(ctx context.Context, userID int64, at time.Time) (email string, err error)
+}
+
+// SessionRevoker revokes tokens of the user once deletion is scheduled, so the user signs in to keep using apps,
+// which cancels the deletion.
+type SessionRevoker interface {
+	RevokeUserSessions(ctx context.Context, userID int64, at time.Time) (int, error)
+}
+
+type Verifier interface {
+	StoreVerification(
+		ctx context.Context,
+		email string,
+		vType models.VerificationType,
+		code string,
+		expiresAt time.Time,
+	) (models.VerificationData, error)
+	Verify(
+		ctx context.Context,
+		email string,
+		vType models.VerificationType,
+		code string,
+		deleteVerificationAfterAtempt bool,
+	) (string, error)
+	Delivered(ctx context.Context, vType models.VerificationType)
+}
+
+type EmailSender interface {
+	SendEmail(
+		ctx context.Context,
+		subject string,
+		to []string,
+		content string,
+		cc []string,
+		bcc []string,
+		atachFiles []string,
+	) (messageID string, err error)
+}
+
+type Notifier interface {
+	AccountDeletionScheduled(ctx context.Context, email string, at time.Time)
+}
+
+type CodeFormats interface {
+	For(vType models.VerificationType) verification.CodeFormat
+}
+
+// EventPublisher notifies apps of user events by webhooks.
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.WebhookEvent)
+}
+
+// Auditor records security-relevant actions to the audit log.
+type Auditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// Deletion deletes accounts on their users' request. Deletion confirmed by code sent to the email
+// is scheduled after the grace period, signing in meanwhile cancels it, see auth.Login.
+type Deletion struct {
+	log         *slog.Logger
+	store       Store
+	users       UserDeleter
+	sessions    SessionRevoker
+	verifier    Verifier
+	mailer      EmailSender
+	notifier    Notifier
+	events      EventPublisher
+	auditor     Auditor
+	codeFormats CodeFormats
+	clock       clock.Clock
+	random      random.Randomizer
+	gracePeriod time.Duration
+}
+
+func New(
+	log *slog.Logger,
+	store Store,
+	users UserDeleter,
+	sessions SessionRevoker,
+	verifier Verifier,
+	mailer EmailSender,
+	notifier Notifier,
+	events EventPublisher,
+	auditor Auditor,
+	codeFormats CodeFormats,
+	clock clock.Clock,
+	random random.Randomizer,
+	gracePeriod time.Duration,
+) *Deletion {
+	return &Deletion{
+		log:         log,
+		store:       store,
+		users:       users,
+		sessions:    sessions,
+		verifier:    verifier,
+		mailer:      mailer,
+		notifier:    notifier,
+		events:      events,
+		auditor:     auditor,
+		codeFormats: codeFormats,
+		clock:       clock,
+		random:      random,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// Request emails code confirming deletion of the user's account.
+func (d *Deletion) Request(ctx context.Context, userID int64, email string) error {
+	const op = "Deletion.Request"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	codeFormat := d.codeFormats.For(models.VerificationTypeAccountDeletion)
+
+	code, err := codeFormat.Generate(d.random)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	expiresAt := d.clock.Now().UTC().Add(codeFormat.TTL)
+	if _, err := d.verifier.StoreVerification(ctx, email, models.VerificationTypeAccountDeletion, code, expiresAt); err != nil {
+		log.Error("failed to store verification", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := d.mailer.SendEmail(ctx, "Confirm deletion of your account", []string{email}, code, []string{}, []string{}, []string{}); err != nil {
+		log.Error("failed to send confirmation code", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	d.verifier.Delivered(ctx, models.VerificationTypeAccountDeletion)
+
+	log.Info("account deletion confirmation code sent")
+
+	return nil
+}
+
+// Confirm schedules deletion of the user's account after the grace period if code is the one sent by Request,
+// and returns when the account is deleted. Tokens of the user are revoked.
+func (d *Deletion) Confirm(ctx context.Context, userID int64, email string, code string) (time.Time, error) {
+	const op = "Deletion.Confirm"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	_, err := d.verifier.Verify(ctx, email, models.VerificationTypeAccountDeletion, code, true)
+	switch {
+	case err == nil:
+	case errors.Is(err, verificationService.CodesDiffer),
+		errors.Is(err, verificationService.TooManyAttempts),
+		errors.Is(err, storage.ErrVerificationExpired),
+		errors.Is(err, storage.ErrVerificationNotFound):
+		return time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidCode)
+	default:
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := d.clock.Now().UTC()
+	deletion := models.AccountDeletion{
+		UserID:      userID,
+		RequestedAt: now,
+		ScheduledAt: now.Add(d.gracePeriod),
+	}
+
+	if err := d.store.SaveAccountDeletion(ctx, deletion); err != nil {
+		log.Error("failed to save account deletion", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := d.sessions.RevokeUserSessions(ctx, userID, now); err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("account deletion scheduled", slog.Time("scheduled_at", deletion.ScheduledAt))
+
+	d.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionAccountDeletionRequested,
+		ActorID: userID,
+		Subject: email,
+		Payload: map[string]string{"scheduled_at": deletion.ScheduledAt.Format(time.RFC3339)},
+	})
+
+	d.notifier.AccountDeletionScheduled(ctx, email, deletion.ScheduledAt)
+
+	return deletion.ScheduledAt, nil
+}
+
+// Purge deletes accounts whose deletion is due and notifies apps by user.deleted webhook,
+// it's run as a scheduled job.
+func (d *Deletion) Purge(ctx context.Context) error {
+	const op = "Deletion.Purge"
+
+	log := d.log.With(slog.String("op", op))
+
+	now := d.clock.Now().UTC()
+
+	due, err := d.store.DueAccountDeletions(ctx, now, purgeBatchSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var deleted int
+	for _, deletion := range due {
+		if _, err := d.users.DeleteScheduledUser(ctx, deletion.UserID, now); err != nil {
+			// Deletion canceled by login meanwhile is not an error.
+			if !errors.Is(err, storage.ErrAccountDeletionNotFound) {
+				log.Error("failed to delete user", slog.Int64("user_id", deletion.UserID), sl.Err(err))
+			}
+
+			continue
+		}
+		deleted++
+
+		d.auditor.Record(ctx, models.AuditEvent{
+			Action:  models.AuditActionAccountDeleted,
+			ActorID: deletion.UserID,
+			Payload: map[string]string{"requested_at": deletion.RequestedAt.Format(time.RFC3339)},
+		})
+
+		d.events.Publish(ctx, models.WebhookEvent{
+			Type: models.WebhookEventUserDeleted,
+			Data: map[string]any{"user_id": deletion.UserID},
+		})
+	}
+
+	log.Info("scheduled accounts deleted", slog.Int("count", deleted))
+
+	return nil
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	verification "grpc-service-ref/internal/lib/verification"
+)
+
+// CodeFormats is an autogenerated mock type for the CodeFormats type
+type CodeFormats struct {
+	mock.Mock
+}
+
+type CodeFormats_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *CodeFormats) EXPECT() *CodeFormats_Expecter {
+	return &CodeFormats_Expecter{mock: &_m.Mock}
+}
+
+// For provides a mock function with given fields: vType
+func (_m *CodeFormats) For(vType models.VerificationType) verification.CodeFormat {
+	ret := _m.Called(vType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for For")
+	}
+
+	var r0 verification.CodeFormat
+	if rf, ok := ret.Get(0).(func(models.VerificationType) verification.CodeFormat); ok {
+		r0 = rf(vType)
+	} else {
+		r0 = ret.Get(0).(verification.CodeFormat)
+	}
+
+	return r0
+}
+
+// CodeFormats_For_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'For'
+type CodeFormats_For_Call struct {
+	*mock.Call
+}
+
+// For is a helper method to define mock.On call
+//   - vType models.VerificationType
+func (_e *CodeFormats_Expecter) For(vType interface{}) *CodeFormats_For_Call {
+	return &CodeFormats_For_Call{Call: _e.mock.On("For", vType)}
+}
+
+func (_c *CodeFormats_For_Call) Run(run func(vType models.VerificationType)) *CodeFormats_For_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(models.VerificationType))
+	})
+	return _c
+}
+
+func (_c *CodeFormats_For_Call) Return(_a0 verification.CodeFormat) *CodeFormats_For_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *CodeFormats_For_Call) RunAndReturn(run func(models.VerificationType) verification.CodeFormat) *CodeFormats_For_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewCodeFormats creates a new instance of CodeFormats. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCodeFormats(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CodeFormats {
+	mock := &CodeFormats{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
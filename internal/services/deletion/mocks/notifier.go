@@ -0,0 +1,73 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Notifier is an autogenerated mock type for the Notifier type
+type Notifier struct {
+	mock.Mock
+}
+
+type Notifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Notifier) EXPECT() *Notifier_Expecter {
+	return &Notifier_Expecter{mock: &_m.Mock}
+}
+
+// AccountDeletionScheduled provides a mock function with given fields: ctx, email, at
+func (_m *Notifier) AccountDeletionScheduled(ctx context.Context, email string, at time.Time) {
+	_m.Called(ctx, email, at)
+}
+
+// Notifier_AccountDeletionScheduled_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AccountDeletionScheduled'
+type Notifier_AccountDeletionScheduled_Call struct {
+	*mock.Call
+}
+
+// AccountDeletionScheduled is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - at time.Time
+func (_e *Notifier_Expecter) AccountDeletionScheduled(ctx interface{}, email interface{}, at interface{}) *Notifier_AccountDeletionScheduled_Call {
+	return &Notifier_AccountDeletionScheduled_Call{Call: _e.mock.On("AccountDeletionScheduled", ctx, email, at)}
+}
+
+func (_c *Notifier_AccountDeletionScheduled_Call) Run(run func(ctx context.Context, email string, at time.Time)) *Notifier_AccountDeletionScheduled_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Notifier_AccountDeletionScheduled_Call) Return() *Notifier_AccountDeletionScheduled_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Notifier_AccountDeletionScheduled_Call) RunAndReturn(run func(context.Context, string, time.Time)) *Notifier_AccountDeletionScheduled_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewNotifier creates a new instance of Notifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Notifier {
+	mock := &Notifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionRevoker is an autogenerated mock type for the SessionRevoker type
+type SessionRevoker struct {
+	mock.Mock
+}
+
+type SessionRevoker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SessionRevoker) EXPECT() *SessionRevoker_Expecter {
+	return &SessionRevoker_Expecter{mock: &_m.Mock}
+}
+
+// RevokeUserSessions provides a mock function with given fields: ctx, userID, at
+func (_m *SessionRevoker) RevokeUserSessions(ctx context.Context, userID int64, at time.Time) (int, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (int, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) int); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionRevoker_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type SessionRevoker_RevokeUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - at time.Time
+func (_e *SessionRevoker_Expecter) RevokeUserSessions(ctx interface{}, userID interface{}, at interface{}) *SessionRevoker_RevokeUserSessions_Call {
+	return &SessionRevoker_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, userID, at)}
+}
+
+func (_c *SessionRevoker_RevokeUserSessions_Call) Run(run func(ctx context.Context, userID int64, at time.Time)) *SessionRevoker_RevokeUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionRevoker_RevokeUserSessions_Call) Return(_a0 int, _a1 error) *SessionRevoker_RevokeUserSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionRevoker_RevokeUserSessions_Call) RunAndReturn(run func(context.Context, int64, time.Time) (int, error)) *SessionRevoker_RevokeUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSessionRevoker creates a new instance of SessionRevoker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionRevoker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionRevoker {
+	mock := &SessionRevoker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
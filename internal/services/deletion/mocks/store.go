@@ -0,0 +1,147 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+
+	time "time"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+type Store_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Store) EXPECT() *Store_Expecter {
+	return &Store_Expecter{mock: &_m.Mock}
+}
+
+// DueAccountDeletions provides a mock function with given fields: ctx, before, limit
+func (_m *Store) DueAccountDeletions(ctx context.Context, before time.Time, limit int) ([]models.AccountDeletion, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DueAccountDeletions")
+	}
+
+	var r0 []models.AccountDeletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.AccountDeletion, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.AccountDeletion); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AccountDeletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_DueAccountDeletions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DueAccountDeletions'
+type Store_DueAccountDeletions_Call struct {
+	*mock.Call
+}
+
+// DueAccountDeletions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - limit int
+func (_e *Store_Expecter) DueAccountDeletions(ctx interface{}, before interface{}, limit interface{}) *Store_DueAccountDeletions_Call {
+	return &Store_DueAccountDeletions_Call{Call: _e.mock.On("DueAccountDeletions", ctx, before, limit)}
+}
+
+func (_c *Store_DueAccountDeletions_Call) Run(run func(ctx context.Context, before time.Time, limit int)) *Store_DueAccountDeletions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Store_DueAccountDeletions_Call) Return(_a0 []models.AccountDeletion, _a1 error) *Store_DueAccountDeletions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_DueAccountDeletions_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]models.AccountDeletion, error)) *Store_DueAccountDeletions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveAccountDeletion provides a mock function with given fields: ctx, _a1
+func (_m *Store) SaveAccountDeletion(ctx context.Context, _a1 models.AccountDeletion) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAccountDeletion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AccountDeletion) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_SaveAccountDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAccountDeletion'
+type Store_SaveAccountDeletion_Call struct {
+	*mock.Call
+}
+
+// SaveAccountDeletion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 models.AccountDeletion
+func (_e *Store_Expecter) SaveAccountDeletion(ctx interface{}, _a1 interface{}) *Store_SaveAccountDeletion_Call {
+	return &Store_SaveAccountDeletion_Call{Call: _e.mock.On("SaveAccountDeletion", ctx, _a1)}
+}
+
+func (_c *Store_SaveAccountDeletion_Call) Run(run func(ctx context.Context, _a1 models.AccountDeletion)) *Store_SaveAccountDeletion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.AccountDeletion))
+	})
+	return _c
+}
+
+func (_c *Store_SaveAccountDeletion_Call) Return(_a0 error) *Store_SaveAccountDeletion_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_SaveAccountDeletion_Call) RunAndReturn(run func(context.Context, models.AccountDeletion) error) *Store_SaveAccountDeletion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// UserDeleter is an autogenerated mock type for the UserDeleter type
+type UserDeleter struct {
+	mock.Mock
+}
+
+type UserDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserDeleter) EXPECT() *UserDeleter_Expecter {
+	return &UserDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteScheduledUser provides a mock function with given fields: ctx, userID, at
+func (_m *UserDeleter) DeleteScheduledUser(ctx context.Context, userID int64, at time.Time) (string, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteScheduledUser")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (string, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) string); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserDeleter_DeleteScheduledUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteScheduledUser'
+type UserDeleter_DeleteScheduledUser_Call struct {
+	*mock.Call
+}
+
+// DeleteScheduledUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - at time.Time
+func (_e *UserDeleter_Expecter) DeleteScheduledUser(ctx interface{}, userID interface{}, at interface{}) *UserDeleter_DeleteScheduledUser_Call {
+	return &UserDeleter_DeleteScheduledUser_Call{Call: _e.mock.On("DeleteScheduledUser", ctx, userID, at)}
+}
+
+func (_c *UserDeleter_DeleteScheduledUser_Call) Run(run func(ctx context.Context, userID int64, at time.Time)) *UserDeleter_DeleteScheduledUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *UserDeleter_DeleteScheduledUser_Call) Return(email string, err error) *UserDeleter_DeleteScheduledUser_Call {
+	_c.Call.Return(email, err)
+	return _c
+}
+
+func (_c *UserDeleter_DeleteScheduledUser_Call) RunAndReturn(run func(context.Context, int64, time.Time) (string, error)) *UserDeleter_DeleteScheduledUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserDeleter creates a new instance of UserDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserDeleter {
+	mock := &UserDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,194 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+
+	time "time"
+)
+
+// Verifier is an autogenerated mock type for the Verifier type
+type Verifier struct {
+	mock.Mock
+}
+
+type Verifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Verifier) EXPECT() *Verifier_Expecter {
+	return &Verifier_Expecter{mock: &_m.Mock}
+}
+
+// Delivered provides a mock function with given fields: ctx, vType
+func (_m *Verifier) Delivered(ctx context.Context, vType models.VerificationType) {
+	_m.Called(ctx, vType)
+}
+
+// Verifier_Delivered_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delivered'
+type Verifier_Delivered_Call struct {
+	*mock.Call
+}
+
+// Delivered is a helper method to define mock.On call
+//   - ctx context.Context
+//   - vType models.VerificationType
+func (_e *Verifier_Expecter) Delivered(ctx interface{}, vType interface{}) *Verifier_Delivered_Call {
+	return &Verifier_Delivered_Call{Call: _e.mock.On("Delivered", ctx, vType)}
+}
+
+func (_c *Verifier_Delivered_Call) Run(run func(ctx context.Context, vType models.VerificationType)) *Verifier_Delivered_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.VerificationType))
+	})
+	return _c
+}
+
+func (_c *Verifier_Delivered_Call) Return() *Verifier_Delivered_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Verifier_Delivered_Call) RunAndReturn(run func(context.Context, models.VerificationType)) *Verifier_Delivered_Call {
+	_c.Run(run)
+	return _c
+}
+
+// StoreVerification provides a mock function with given fields: ctx, email, vType, code, expiresAt
+func (_m *Verifier) StoreVerification(ctx context.Context, email string, vType models.VerificationType, code string, expiresAt time.Time) (models.VerificationData, error) {
+	ret := _m.Called(ctx, email, vType, code, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreVerification")
+	}
+
+	var r0 models.VerificationData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, time.Time) (models.VerificationData, error)); ok {
+		return rf(ctx, email, vType, code, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, time.Time) models.VerificationData); ok {
+		r0 = rf(ctx, email, vType, code, expiresAt)
+	} else {
+		r0 = ret.Get(0).(models.VerificationData)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.VerificationType, string, time.Time) error); ok {
+		r1 = rf(ctx, email, vType, code, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verifier_StoreVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreVerification'
+type Verifier_StoreVerification_Call struct {
+	*mock.Call
+}
+
+// StoreVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+//   - code string
+//   - expiresAt time.Time
+func (_e *Verifier_Expecter) StoreVerification(ctx interface{}, email interface{}, vType interface{}, code interface{}, expiresAt interface{}) *Verifier_StoreVerification_Call {
+	return &Verifier_StoreVerification_Call{Call: _e.mock.On("StoreVerification", ctx, email, vType, code, expiresAt)}
+}
+
+func (_c *Verifier_StoreVerification_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType, code string, expiresAt time.Time)) *Verifier_StoreVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType), args[3].(string), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Verifier_StoreVerification_Call) Return(_a0 models.VerificationData, _a1 error) *Verifier_StoreVerification_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Verifier_StoreVerification_Call) RunAndReturn(run func(context.Context, string, models.VerificationType, string, time.Time) (models.VerificationData, error)) *Verifier_StoreVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Verify provides a mock function with given fields: ctx, email, vType, code, deleteVerificationAfterAtempt
+func (_m *Verifier) Verify(ctx context.Context, email string, vType models.VerificationType, code string, deleteVerificationAfterAtempt bool) (string, error) {
+	ret := _m.Called(ctx, email, vType, code, deleteVerificationAfterAtempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, bool) (string, error)); ok {
+		return rf(ctx, email, vType, code, deleteVerificationAfterAtempt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, bool) string); ok {
+		r0 = rf(ctx, email, vType, code, deleteVerificationAfterAtempt)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.VerificationType, string, bool) error); ok {
+		r1 = rf(ctx, email, vType, code, deleteVerificationAfterAtempt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verifier_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type Verifier_Verify_Call struct {
+	*mock.Call
+}
+
+// Verify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+//   - code string
+//   - deleteVerificationAfterAtempt bool
+func (_e *Verifier_Expecter) Verify(ctx interface{}, email interface{}, vType interface{}, code interface{}, deleteVerificationAfterAtempt interface{}) *Verifier_Verify_Call {
+	return &Verifier_Verify_Call{Call: _e.mock.On("Verify", ctx, email, vType, code, deleteVerificationAfterAtempt)}
+}
+
+func (_c *Verifier_Verify_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType, code string, deleteVerificationAfterAtempt bool)) *Verifier_Verify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType), args[3].(string), args[4].(bool))
+	})
+	return _c
+}
+
+func (_c *Verifier_Verify_Call) Return(_a0 string, _a1 error) *Verifier_Verify_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Verifier_Verify_Call) RunAndReturn(run func(context.Context, string, models.VerificationType, string, bool) (string, error)) *Verifier_Verify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewVerifier creates a new instance of Verifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Verifier {
+	mock := &Verifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,284 @@
+package devicelogin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/storage"
+)
+
+const (
+	// pairingCodeAlphabet has no vowels, so codes don't spell words, and no characters easily confused.
+	pairingCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	pairingCodeLen      = 8
+	// saveAttempts bounds retries of pairing codes colliding with pending ones.
+	saveAttempts = 3
+)
+
+var (
+	ErrInvalidPairingCode = errors.New("pairing code is invalid or expired")
+	// ErrNotFound is returned by Poll for unknown, expired or already completed login.
+	ErrNotFound = errors.New("device login not found or expired")
+	// ErrPending is returned by Poll until the login is approved.
+	ErrPending = errors.New("device login is not approved yet")
+)
+
+type Store interface {
+	SaveDeviceLogin(ctx context.Context, login models.DeviceLogin) error
+	DeviceLogin(ctx context.Context, id string) (models.DeviceLogin, error)
+	ApproveDeviceLogin(ctx context.Context, pairingCode string, userID int64, at time.Time) (models.DeviceLogin, error)
+	ConsumeDeviceLogin(ctx context.Context, id string, at time.Time) (models.DeviceLogin, error)
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+// TokenIssuer issues token to the device once its login is approved, see auth.LoginApprovedDevice.
+type TokenIssuer interface {
+	LoginApprovedDevice(ctx context.Context, userID int64, appID int) (string, error)
+}
+
+// Auditor records security-relevant actions to the audit log.
+type Auditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// Started is device login waiting for approval.
+type Started struct {
+	// PollToken is the secret the device polls by, only its hash is stored.
+	PollToken string
+	// PairingCode is shown by the device for the user to enter or scan, e.g. BCDF-GHJK.
+	PairingCode string
+	// VerificationURI is the page approving the login with the pairing code, to encode as QR code,
+	// empty if not configured.
+	VerificationURI string
+	ExpiresAt       time.Time
+	// PollInterval is how often the device should poll.
+	PollInterval time.Duration
+}
+
+// DeviceLogins logs in devices with no convenient input, e.g. TVs and kiosks, by approval from another device
+// the user is signed in on: the device shows pairing code, the user approves it and the device polls for its token.
+type DeviceLogins struct {
+	log          *slog.Logger
+	store        Store
+	apps         AppProvider
+	tokens       TokenIssuer
+	auditor      Auditor
+	clock        clock.Clock
+	random       random.Randomizer
+	ttl          time.Duration
+	pollInterval time.Duration
+	// verificationURI is base URI of the approval page, the pairing code is added as code query parameter.
+	verificationURI string
+}
+
+func New(
+	log *slog.Logger,
+	store Store,
+	apps AppProvider,
+	tokens TokenIssuer,
+	auditor Auditor,
+	clock clock.Clock,
+	random random.Randomizer,
+	ttl time.Duration,
+	pollInterval time.Duration,
+	verificationURI string,
+) *DeviceLogins {
+	return &DeviceLogins{
+		log:             log,
+		store:           store,
+		apps:            apps,
+		tokens:          tokens,
+		auditor:         auditor,
+		clock:           clock,
+		random:          random,
+		ttl:             ttl,
+		pollInterval:    pollInterval,
+		verificationURI: verificationURI,
+	}
+}
+
+// Start starts login of the device to the app, which waits for approval until it expires.
+func (d *DeviceLogins) Start(ctx context.Context, appID int, device models.Device) (Started, error) {
+	const op = "DeviceLogins.Start"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	if _, err := d.apps.App(ctx, appID); err != nil {
+		return Started{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pollToken, err := random.Secret(d.random)
+	if err != nil {
+		return Started{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := d.clock.Now().UTC()
+	login := models.DeviceLogin{
+		ID:        hashPollToken(pollToken),
+		AppID:     appID,
+		Device:    device,
+		CreatedAt: now,
+		ExpiresAt: now.Add(d.ttl),
+	}
+
+	for attempt := 1; ; attempt++ {
+		login.PairingCode, err = d.random.String(pairingCodeLen, pairingCodeAlphabet)
+		if err != nil {
+			return Started{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = d.store.SaveDeviceLogin(ctx, login)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, storage.ErrDeviceLoginExists) || attempt == saveAttempts {
+			log.Error("failed to save device login", sl.Err(err))
+
+			return Started{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	log.Info("device login started")
+
+	code := formatPairingCode(login.PairingCode)
+
+	return Started{
+		PollToken:       pollToken,
+		PairingCode:     code,
+		VerificationURI: d.uriWithCode(code),
+		ExpiresAt:       login.ExpiresAt,
+		PollInterval:    d.pollInterval,
+	}, nil
+}
+
+// Approve approves pending device login with the pairing code on behalf of the signed in user
+// and returns it, so the user sees which app and device were approved.
+func (d *DeviceLogins) Approve(ctx context.Context, userID int64, email string, pairingCode string) (models.DeviceLogin, error) {
+	const op = "DeviceLogins.Approve"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	login, err := d.store.ApproveDeviceLogin(ctx, normalizePairingCode(pairingCode), userID, d.clock.Now().UTC())
+	if err != nil {
+		if errors.Is(err, storage.ErrDeviceLoginNotFound) {
+			log.Info("pairing code is invalid or expired")
+
+			return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, ErrInvalidPairingCode)
+		}
+
+		log.Error("failed to approve device login", sl.Err(err))
+
+		return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("device login approved", slog.Int("app_id", login.AppID))
+
+	d.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionDeviceLoginApproved,
+		ActorID: userID,
+		Subject: email,
+		AppID:   login.AppID,
+		Payload: map[string]string{
+			"user_agent": login.Device.UserAgent,
+			"ip":         login.Device.IP,
+			"country":    login.Device.Country,
+		},
+	})
+
+	return login, nil
+}
+
+// Poll returns token of the device once its login is approved, only the first poll after approval gets it.
+// ErrPending is returned until then, ErrNotFound once the login expired.
+func (d *DeviceLogins) Poll(ctx context.Context, pollToken string) (string, error) {
+	const op = "DeviceLogins.Poll"
+
+	id := hashPollToken(pollToken)
+	now := d.clock.Now().UTC()
+
+	login, err := d.store.ConsumeDeviceLogin(ctx, id, now)
+	if err != nil {
+		if !errors.Is(err, storage.ErrDeviceLoginNotFound) {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		pending, err := d.store.DeviceLogin(ctx, id)
+		switch {
+		case errors.Is(err, storage.ErrDeviceLoginNotFound):
+			return "", fmt.Errorf("%s: %w", op, ErrNotFound)
+		case err != nil:
+			return "", fmt.Errorf("%s: %w", op, err)
+		case !now.Before(pending.ExpiresAt):
+			return "", fmt.Errorf("%s: %w", op, ErrNotFound)
+		default:
+			// Approved between the two reads is picked up by the next poll.
+			return "", fmt.Errorf("%s: %w", op, ErrPending)
+		}
+	}
+
+	token, err := d.tokens.LoginApprovedDevice(ctx, login.UserID, login.AppID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+func (d *DeviceLogins) uriWithCode(code string) string {
+	if d.verificationURI == "" {
+		return ""
+	}
+
+	u, err := url.Parse(d.verificationURI)
+	if err != nil {
+		return ""
+	}
+
+	q := u.Query()
+	q.Set("code", code)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func hashPollToken(pollToken string) string {
+	hash := sha256.Sum256([]byte(pollToken))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// formatPairingCode splits the code in halves for reading, e.g. BCDF-GHJK.
+func formatPairingCode(code string) string {
+	return code[:len(code)/2] + "-" + code[len(code)/2:]
+}
+
+// normalizePairingCode accepts codes as typed by users: in any case, with or without separators.
+func normalizePairingCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToUpper(code))
+}
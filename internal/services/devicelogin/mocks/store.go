@@ -0,0 +1,261 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+
+	time "time"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+type Store_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Store) EXPECT() *Store_Expecter {
+	return &Store_Expecter{mock: &_m.Mock}
+}
+
+// ApproveDeviceLogin provides a mock function with given fields: ctx, pairingCode, userID, at
+func (_m *Store) ApproveDeviceLogin(ctx context.Context, pairingCode string, userID int64, at time.Time) (models.DeviceLogin, error) {
+	ret := _m.Called(ctx, pairingCode, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveDeviceLogin")
+	}
+
+	var r0 models.DeviceLogin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, time.Time) (models.DeviceLogin, error)); ok {
+		return rf(ctx, pairingCode, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, time.Time) models.DeviceLogin); ok {
+		r0 = rf(ctx, pairingCode, userID, at)
+	} else {
+		r0 = ret.Get(0).(models.DeviceLogin)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, time.Time) error); ok {
+		r1 = rf(ctx, pairingCode, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_ApproveDeviceLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApproveDeviceLogin'
+type Store_ApproveDeviceLogin_Call struct {
+	*mock.Call
+}
+
+// ApproveDeviceLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - pairingCode string
+//   - userID int64
+//   - at time.Time
+func (_e *Store_Expecter) ApproveDeviceLogin(ctx interface{}, pairingCode interface{}, userID interface{}, at interface{}) *Store_ApproveDeviceLogin_Call {
+	return &Store_ApproveDeviceLogin_Call{Call: _e.mock.On("ApproveDeviceLogin", ctx, pairingCode, userID, at)}
+}
+
+func (_c *Store_ApproveDeviceLogin_Call) Run(run func(ctx context.Context, pairingCode string, userID int64, at time.Time)) *Store_ApproveDeviceLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Store_ApproveDeviceLogin_Call) Return(_a0 models.DeviceLogin, _a1 error) *Store_ApproveDeviceLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_ApproveDeviceLogin_Call) RunAndReturn(run func(context.Context, string, int64, time.Time) (models.DeviceLogin, error)) *Store_ApproveDeviceLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConsumeDeviceLogin provides a mock function with given fields: ctx, id, at
+func (_m *Store) ConsumeDeviceLogin(ctx context.Context, id string, at time.Time) (models.DeviceLogin, error) {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeDeviceLogin")
+	}
+
+	var r0 models.DeviceLogin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (models.DeviceLogin, error)); ok {
+		return rf(ctx, id, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) models.DeviceLogin); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Get(0).(models.DeviceLogin)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, id, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_ConsumeDeviceLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeDeviceLogin'
+type Store_ConsumeDeviceLogin_Call struct {
+	*mock.Call
+}
+
+// ConsumeDeviceLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - at time.Time
+func (_e *Store_Expecter) ConsumeDeviceLogin(ctx interface{}, id interface{}, at interface{}) *Store_ConsumeDeviceLogin_Call {
+	return &Store_ConsumeDeviceLogin_Call{Call: _e.mock.On("ConsumeDeviceLogin", ctx, id, at)}
+}
+
+func (_c *Store_ConsumeDeviceLogin_Call) Run(run func(ctx context.Context, id string, at time.Time)) *Store_ConsumeDeviceLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Store_ConsumeDeviceLogin_Call) Return(_a0 models.DeviceLogin, _a1 error) *Store_ConsumeDeviceLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_ConsumeDeviceLogin_Call) RunAndReturn(run func(context.Context, string, time.Time) (models.DeviceLogin, error)) *Store_ConsumeDeviceLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeviceLogin provides a mock function with given fields: ctx, id
+func (_m *Store) DeviceLogin(ctx context.Context, id string) (models.DeviceLogin, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeviceLogin")
+	}
+
+	var r0 models.DeviceLogin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.DeviceLogin, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.DeviceLogin); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.DeviceLogin)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_DeviceLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeviceLogin'
+type Store_DeviceLogin_Call struct {
+	*mock.Call
+}
+
+// DeviceLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Store_Expecter) DeviceLogin(ctx interface{}, id interface{}) *Store_DeviceLogin_Call {
+	return &Store_DeviceLogin_Call{Call: _e.mock.On("DeviceLogin", ctx, id)}
+}
+
+func (_c *Store_DeviceLogin_Call) Run(run func(ctx context.Context, id string)) *Store_DeviceLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Store_DeviceLogin_Call) Return(_a0 models.DeviceLogin, _a1 error) *Store_DeviceLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_DeviceLogin_Call) RunAndReturn(run func(context.Context, string) (models.DeviceLogin, error)) *Store_DeviceLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveDeviceLogin provides a mock function with given fields: ctx, login
+func (_m *Store) SaveDeviceLogin(ctx context.Context, login models.DeviceLogin) error {
+	ret := _m.Called(ctx, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDeviceLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DeviceLogin) error); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_SaveDeviceLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveDeviceLogin'
+type Store_SaveDeviceLogin_Call struct {
+	*mock.Call
+}
+
+// SaveDeviceLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - login models.DeviceLogin
+func (_e *Store_Expecter) SaveDeviceLogin(ctx interface{}, login interface{}) *Store_SaveDeviceLogin_Call {
+	return &Store_SaveDeviceLogin_Call{Call: _e.mock.On("SaveDeviceLogin", ctx, login)}
+}
+
+func (_c *Store_SaveDeviceLogin_Call) Run(run func(ctx context.Context, login models.DeviceLogin)) *Store_SaveDeviceLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.DeviceLogin))
+	})
+	return _c
+}
+
+func (_c *Store_SaveDeviceLogin_Call) Return(_a0 error) *Store_SaveDeviceLogin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_SaveDeviceLogin_Call) RunAndReturn(run func(context.Context, models.DeviceLogin) error) *Store_SaveDeviceLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenIssuer is an autogenerated mock type for the TokenIssuer type
+type TokenIssuer struct {
+	mock.Mock
+}
+
+type TokenIssuer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TokenIssuer) EXPECT() *TokenIssuer_Expecter {
+	return &TokenIssuer_Expecter{mock: &_m.Mock}
+}
+
+// LoginApprovedDevice provides a mock function with given fields: ctx, userID, appID
+func (_m *TokenIssuer) LoginApprovedDevice(ctx context.Context, userID int64, appID int) (string, error) {
+	ret := _m.Called(ctx, userID, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginApprovedDevice")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) (string, error)); ok {
+		return rf(ctx, userID, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) string); ok {
+		r0 = rf(ctx, userID, appID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, userID, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TokenIssuer_LoginApprovedDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoginApprovedDevice'
+type TokenIssuer_LoginApprovedDevice_Call struct {
+	*mock.Call
+}
+
+// LoginApprovedDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - appID int
+func (_e *TokenIssuer_Expecter) LoginApprovedDevice(ctx interface{}, userID interface{}, appID interface{}) *TokenIssuer_LoginApprovedDevice_Call {
+	return &TokenIssuer_LoginApprovedDevice_Call{Call: _e.mock.On("LoginApprovedDevice", ctx, userID, appID)}
+}
+
+func (_c *TokenIssuer_LoginApprovedDevice_Call) Run(run func(ctx context.Context, userID int64, appID int)) *TokenIssuer_LoginApprovedDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *TokenIssuer_LoginApprovedDevice_Call) Return(_a0 string, _a1 error) *TokenIssuer_LoginApprovedDevice_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TokenIssuer_LoginApprovedDevice_Call) RunAndReturn(run func(context.Context, int64, int) (string, error)) *TokenIssuer_LoginApprovedDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTokenIssuer creates a new instance of TokenIssuer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenIssuer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenIssuer {
+	mock := &TokenIssuer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
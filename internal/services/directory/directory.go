@@ -0,0 +1,224 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+	"grpc-service-ref/internal/storage"
+)
+
+var (
+	// ErrInvalidCredentials is returned by clients if the directory rejects the credentials.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUnknownDirectory is returned for users of directories which are not configured anymore.
+	ErrUnknownDirectory = errors.New("user directory is not configured")
+)
+
+// Identity is the user as the directory knows them.
+type Identity struct {
+	Email    string
+	Verified bool
+}
+
+// Client checks credentials against an external user directory, e.g. by HTTP, see httpdirectory.
+type Client interface {
+	Authenticate(ctx context.Context, email string, password string) (Identity, error)
+}
+
+// Directory is an external user directory and users it manages: users of the apps and users with emails
+// in the domains.
+type Directory struct {
+	// Name identifies the directory, users it authenticated keep it, see models.User.Directory.
+	Name    string
+	Client  Client
+	AppIDs  []int
+	Domains []string
+}
+
+type UserStore interface {
+	User(ctx context.Context, email string) (models.User, error)
+	SaveExternalUser(ctx context.Context, email string, directory string, verified bool) (int64, error)
+	VerifyUser(ctx context.Context, email string) (int64, error)
+}
+
+// Router delegates authentication of users to external user directories, while SSO still issues tokens
+// and manages sessions. Users are provisioned locally on their first login, so they have IDs, sessions,
+// organizations and roles like local users, but no passwords.
+//
+// Users are routed to directories by their app or email domain. Users which were registered locally before
+// keep logging in with their local passwords, users provisioned by a directory always log in by it.
+type Router struct {
+	log         *slog.Logger
+	users       UserStore
+	directories []Directory
+}
+
+func New(log *slog.Logger, users UserStore, directories []Directory) *Router {
+	return &Router{
+		log:         log,
+		users:       users,
+		directories: directories,
+	}
+}
+
+// Authenticate checks credentials of the user of the app by the directory managing them and returns the local
+// user, which is provisioned on the first login. ok is false if no directory manages the user, so credentials
+// must be checked locally. ErrInvalidCredentials is returned if the directory rejects them.
+func (r *Router) Authenticate(
+	ctx context.Context,
+	appID int,
+	email string,
+	password string,
+) (user models.User, ok bool, err error) {
+	const op = "Router.Authenticate"
+
+	log := r.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	user, err = r.users.User(ctx, email)
+	found := err == nil
+	if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+		return models.User{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var dir Directory
+	switch {
+	case found && user.Directory == "":
+		return models.User{}, false, nil
+	case found:
+		if dir, ok = r.byName(user.Directory); !ok {
+			log.Error("user directory is not configured", slog.String("directory", user.Directory))
+
+			return models.User{}, true, fmt.Errorf("%s: %w", op, ErrUnknownDirectory)
+		}
+	default:
+		if dir, ok = r.route(appID, email); !ok {
+			return models.User{}, false, nil
+		}
+	}
+
+	log = log.With(slog.String("directory", dir.Name))
+
+	identity, err := dir.Client.Authenticate(ctx, email, password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			metrics.DirectoryAuthentications.WithLabelValues(dir.Name, metrics.DirectoryInvalidCredentials).Inc()
+
+			return models.User{}, true, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+
+		log.Error("failed to authenticate user by directory", sl.Err(err))
+		metrics.DirectoryAuthentications.WithLabelValues(dir.Name, metrics.DirectoryFailed).Inc()
+
+		return models.User{}, true, fmt.Errorf("%s: %w", op, err)
+	}
+
+	metrics.DirectoryAuthentications.WithLabelValues(dir.Name, metrics.DirectoryAuthenticated).Inc()
+
+	if !strings.EqualFold(identity.Email, email) {
+		log.Error("directory authenticated another user")
+
+		return models.User{}, true, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	if !found {
+		user, err = r.provision(ctx, log, dir, email, identity)
+		if err != nil {
+			return models.User{}, true, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return user, true, nil
+	}
+
+	// Directory is the source of truth of verification, it's never revoked locally though.
+	if identity.Verified && !user.Verified {
+		if _, err := r.users.VerifyUser(ctx, email); err != nil {
+			return models.User{}, true, fmt.Errorf("%s: %w", op, err)
+		}
+		user.Verified = true
+	}
+
+	return user, true, nil
+}
+
+// Manages reports whether the email is in a domain of a directory, such users can't register locally.
+func (r *Router) Manages(email string) bool {
+	_, ok := r.route(0, email)
+
+	return ok
+}
+
+func (r *Router) provision(
+	ctx context.Context,
+	log *slog.Logger,
+	dir Directory,
+	email string,
+	identity Identity,
+) (models.User, error) {
+	id, err := r.users.SaveExternalUser(ctx, email, dir.Name, identity.Verified)
+	if err == nil {
+		log.Info("user provisioned from directory", slog.Int64("user_id", id))
+	} else if !errors.Is(err, storage.ErrUserExists) {
+		log.Error("failed to provision user", sl.Err(err))
+
+		return models.User{}, err
+	}
+
+	// Concurrent login of the same user may have provisioned it already.
+	user, err := r.users.User(ctx, email)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if user.Directory != dir.Name {
+		// Registered locally meanwhile, the directory doesn't manage the user.
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// route returns directory of the app, or of the email domain if the app has none.
+func (r *Router) route(appID int, email string) (Directory, bool) {
+	if appID != 0 {
+		for _, dir := range r.directories {
+			if slices.Contains(dir.AppIDs, appID) {
+				return dir, true
+			}
+		}
+	}
+
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return Directory{}, false
+	}
+
+	for _, dir := range r.directories {
+		for _, d := range dir.Domains {
+			if strings.EqualFold(d, domain) {
+				return dir, true
+			}
+		}
+	}
+
+	return Directory{}, false
+}
+
+func (r *Router) byName(name string) (Directory, bool) {
+	for _, dir := range r.directories {
+		if dir.Name == name {
+			return dir, true
+		}
+	}
+
+	return Directory{}, false
+}
@@ -0,0 +1,84 @@
+package httpdirectory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"grpc-service-ref/internal/services/directory"
+)
+
+// maxResponseSize bounds responses read from the directory.
+const maxResponseSize = 64 << 10
+
+// Client checks credentials by POST of JSON {"email", "password"} to the directory endpoint,
+// with the token as a bearer token if it's set.
+//
+// The directory responds 200 with {"email", "verified"} of the authenticated user, 401, 403 or 404
+// if credentials are invalid or the user is unknown. Other responses are failures.
+type Client struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+type authenticateRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type authenticateResponse struct {
+	Email    string `json:"email"`
+	Verified bool   `json:"verified"`
+}
+
+func New(url string, token string, timeout time.Duration) *Client {
+	return &Client{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+		token:  token,
+	}
+}
+
+func (c *Client) Authenticate(ctx context.Context, email string, password string) (directory.Identity, error) {
+	const op = "httpdirectory.Authenticate"
+
+	body, err := json.Marshal(authenticateRequest{Email: email, Password: password})
+	if err != nil {
+		return directory.Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return directory.Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return directory.Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return directory.Identity{}, fmt.Errorf("%s: %w", op, directory.ErrInvalidCredentials)
+	default:
+		return directory.Identity{}, fmt.Errorf("%s: unexpected status %d", op, resp.StatusCode)
+	}
+
+	var res authenticateResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&res); err != nil {
+		return directory.Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return directory.Identity{Email: res.Email, Verified: res.Verified}, nil
+}
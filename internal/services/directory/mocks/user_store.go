@@ -0,0 +1,211 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+)
+
+// UserStore is an autogenerated mock type for the UserStore type
+type UserStore struct {
+	mock.Mock
+}
+
+type UserStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserStore) EXPECT() *UserStore_Expecter {
+	return &UserStore_Expecter{mock: &_m.Mock}
+}
+
+// SaveExternalUser provides a mock function with given fields: ctx, email, _a2, verified
+func (_m *UserStore) SaveExternalUser(ctx context.Context, email string, _a2 string, verified bool) (int64, error) {
+	ret := _m.Called(ctx, email, _a2, verified)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveExternalUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) (int64, error)); ok {
+		return rf(ctx, email, _a2, verified)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) int64); ok {
+		r0 = rf(ctx, email, _a2, verified)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
+		r1 = rf(ctx, email, _a2, verified)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserStore_SaveExternalUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveExternalUser'
+type UserStore_SaveExternalUser_Call struct {
+	*mock.Call
+}
+
+// SaveExternalUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - _a2 string
+//   - verified bool
+func (_e *UserStore_Expecter) SaveExternalUser(ctx interface{}, email interface{}, _a2 interface{}, verified interface{}) *UserStore_SaveExternalUser_Call {
+	return &UserStore_SaveExternalUser_Call{Call: _e.mock.On("SaveExternalUser", ctx, email, _a2, verified)}
+}
+
+func (_c *UserStore_SaveExternalUser_Call) Run(run func(ctx context.Context, email string, _a2 string, verified bool)) *UserStore_SaveExternalUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *UserStore_SaveExternalUser_Call) Return(_a0 int64, _a1 error) *UserStore_SaveExternalUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserStore_SaveExternalUser_Call) RunAndReturn(run func(context.Context, string, string, bool) (int64, error)) *UserStore_SaveExternalUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// User provides a mock function with given fields: ctx, email
+func (_m *UserStore) User(ctx context.Context, email string) (models.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for User")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserStore_User_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'User'
+type UserStore_User_Call struct {
+	*mock.Call
+}
+
+// User is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *UserStore_Expecter) User(ctx interface{}, email interface{}) *UserStore_User_Call {
+	return &UserStore_User_Call{Call: _e.mock.On("User", ctx, email)}
+}
+
+func (_c *UserStore_User_Call) Run(run func(ctx context.Context, email string)) *UserStore_User_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserStore_User_Call) Return(_a0 models.User, _a1 error) *UserStore_User_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserStore_User_Call) RunAndReturn(run func(context.Context, string) (models.User, error)) *UserStore_User_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyUser provides a mock function with given fields: ctx, email
+func (_m *UserStore) VerifyUser(ctx context.Context, email string) (int64, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserStore_VerifyUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyUser'
+type UserStore_VerifyUser_Call struct {
+	*mock.Call
+}
+
+// VerifyUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *UserStore_Expecter) VerifyUser(ctx interface{}, email interface{}) *UserStore_VerifyUser_Call {
+	return &UserStore_VerifyUser_Call{Call: _e.mock.On("VerifyUser", ctx, email)}
+}
+
+func (_c *UserStore_VerifyUser_Call) Run(run func(ctx context.Context, email string)) *UserStore_VerifyUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserStore_VerifyUser_Call) Return(_a0 int64, _a1 error) *UserStore_VerifyUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserStore_VerifyUser_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *UserStore_VerifyUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserStore creates a new instance of UserStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserStore {
+	mock := &UserStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,76 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"grpc-service-ref/internal/lib/messageid"
+)
+
+// ConsoleSender is an EmailSender for local development.
+// It does not send anything, the email is written to the log and to out instead,
+// so flows can be tested without real SMTP credentials.
+type ConsoleSender struct {
+	log *slog.Logger
+	out io.Writer
+}
+
+func New(
+	log *slog.Logger,
+	out io.Writer,
+) *ConsoleSender {
+	return &ConsoleSender{
+		log: log,
+		out: out,
+	}
+}
+
+func (sender *ConsoleSender) SendEmail(
+	ctx context.Context,
+	subject string,
+	to []string,
+	content string,
+	cc []string,
+	bcc []string,
+	atachFiles []string,
+) (string, error) {
+	const op = "Console.SendEmail"
+
+	log := sender.log.With(
+		slog.String("op", op),
+	)
+
+	msgID, err := messageid.New("console@localhost")
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("printing email to console instead of sending",
+		slog.String("message_id", msgID),
+		slog.Any("to", to),
+		slog.Any("cc", cc),
+		slog.Any("bcc", bcc),
+		slog.String("subject", subject),
+		slog.Int("attachments", len(atachFiles)),
+	)
+
+	line := strings.Repeat("=", 60)
+
+	_, err = fmt.Fprintf(sender.out,
+		"\n%s\nTo:      %s\nSubject: %s\n%s\n\n    %s\n\n%s\n\n",
+		line,
+		strings.Join(to, ", "),
+		subject,
+		line,
+		content,
+		line,
+	)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return msgID, nil
+}
@@ -1,13 +1,18 @@
 package gmail
 
 import (
-	"flag"
+	"context"
+	"crypto/tls"
 	"fmt"
+	"grpc-service-ref/internal/lib/dkim"
 	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/messageid"
+	"grpc-service-ref/internal/lib/tracing"
+	"grpc-service-ref/internal/services/mail"
 	"log/slog"
+	"net"
 	"net/smtp"
 	"net/textproto"
-	"os"
 
 	"github.com/jordan-wright/email"
 )
@@ -22,100 +27,257 @@ type GmailSender struct {
 	name              string
 	fromEmailAddress  string
 	fromEmailPassword string
+	// dkimSigner signs outgoing emails, nil if signing is disabled.
+	dkimSigner *dkim.Signer
 }
 
 func New(
 	log *slog.Logger,
 	name string,
 	email string,
-	password string) *GmailSender {
+	password string,
+	dkimSigner *dkim.Signer) *GmailSender {
 	return &GmailSender{
 		log:               log,
 		name:              name,
 		fromEmailAddress:  email,
 		fromEmailPassword: password,
+		dkimSigner:        dkimSigner,
 	}
 }
 
+// SendEmail sends email via gmail SMTP and returns Message-Id of the sent email.
+// Message-Id is returned even if sending failed, so the failure can be tracked.
 func (sender *GmailSender) SendEmail(
+	ctx context.Context,
 	subject string,
 	to []string,
 	content string,
 	cc []string,
 	bcc []string,
 	atachFiles []string,
-) error {
+) (string, error) {
 	const op = "Gmail.SendEmail"
 
+	_, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	log := sender.log.With(
 		slog.String("op", op),
 	)
 
 	log.Info("attempting to send email")
 
+	msgID, err := messageid.New(sender.fromEmailAddress)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	e := sender.message(msgID, mail.Message{
+		Subject:     subject,
+		To:          to,
+		Content:     content,
+		Cc:          cc,
+		Bcc:         bcc,
+		AttachFiles: atachFiles,
+	})
+
+	smtpAuth := smtp.PlainAuth("", sender.fromEmailAddress, sender.fromEmailPassword, smtpAuthAddress)
+
+	if sender.dkimSigner == nil {
+		if err := e.Send(smtpServerAddress, smtpAuth); err != nil {
+			tracing.Fail(span, err)
+
+			return msgID, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return msgID, nil
+	}
+
+	if err := sender.sendSigned(e, smtpAuth); err != nil {
+		tracing.Fail(span, err)
+
+		return msgID, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return msgID, nil
+}
+
+// SendEmails sends emails over a single SMTP connection, Message-Id is returned for every email.
+// If the connection can't be established, all emails fail.
+func (sender *GmailSender) SendEmails(ctx context.Context, messages []mail.Message) []mail.SendResult {
+	const op = "Gmail.SendEmails"
+
+	_, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := sender.log.With(
+		slog.String("op", op),
+		slog.Int("count", len(messages)),
+	)
+
+	log.Info("attempting to send emails")
+
+	results := make([]mail.SendResult, len(messages))
+
+	client, err := sender.dial()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		for i := range results {
+			results[i].Err = fmt.Errorf("%s: %w", op, err)
+		}
+
+		return results
+	}
+	defer client.Close()
+
+	for i, msg := range messages {
+		msgID, err := messageid.New(sender.fromEmailAddress)
+		if err != nil {
+			results[i].Err = fmt.Errorf("%s: %w", op, err)
+
+			continue
+		}
+
+		results[i].MessageID = msgID
+
+		if err := sender.sendOver(client, sender.message(msgID, msg)); err != nil {
+			results[i].Err = fmt.Errorf("%s: %w", op, err)
+
+			// Transaction of the failed email is aborted, so the next one can be sent.
+			if err := client.Reset(); err != nil {
+				tracing.Fail(span, err)
+
+				for j := i + 1; j < len(results); j++ {
+					results[j].Err = fmt.Errorf("%s: %w", op, err)
+				}
+
+				return results
+			}
+		}
+	}
+
+	if err := client.Quit(); err != nil {
+		log.Warn("failed to close SMTP connection", sl.Err(err))
+	}
+
+	return results
+}
+
+// message builds email with the given Message-Id.
+func (sender *GmailSender) message(msgID string, msg mail.Message) *email.Email {
 	e := &email.Email{
-		To:      to,
+		To:      msg.To,
 		From:    fmt.Sprintf("%s <%s>", sender.name, sender.fromEmailAddress),
-		Subject: subject,
-		//Text:    []byte(body),
-		HTML:    []byte(content),
-		Headers: textproto.MIMEHeader{},
-		Cc:      cc,
-		Bcc:     bcc,
-	}
-
-	if len(atachFiles) > 0 {
-		for _, f := range atachFiles {
-			_, err := e.AttachFile(f)
-			if err != nil {
-				sender.log.Error("failed to attach file to email", sl.Err(err))
-			}
+		Subject: msg.Subject,
+		HTML:    []byte(msg.Content),
+		Headers: textproto.MIMEHeader{"Message-Id": {msgID}},
+		Cc:      msg.Cc,
+		Bcc:     msg.Bcc,
+	}
+
+	for _, f := range msg.AttachFiles {
+		if _, err := e.AttachFile(f); err != nil {
+			sender.log.Error("failed to attach file to email", sl.Err(err))
 		}
 	}
 
+	return e
+}
+
+// dial opens authenticated SMTP connection.
+func (sender *GmailSender) dial() (*smtp.Client, error) {
+	client, err := smtp.Dial(smtpServerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.StartTLS(&tls.Config{ServerName: smtpAuthAddress}); err != nil {
+		client.Close()
+
+		return nil, err
+	}
+
 	smtpAuth := smtp.PlainAuth("", sender.fromEmailAddress, sender.fromEmailPassword, smtpAuthAddress)
-	return e.Send(smtpServerAddress, smtpAuth)
+	if err := client.Auth(smtpAuth); err != nil {
+		client.Close()
 
+		return nil, err
+	}
+
+	return client, nil
 }
 
-// fetchConfigPath fetches config path from command line flag or environment variable.
-// Priority: flag > env > default.
-// Default value is empty string.
-func fetchSenderName() string {
-	var res string
+// sendOver sends email over open SMTP connection, it's signed if DKIM is enabled.
+func (sender *GmailSender) sendOver(client *smtp.Client, e *email.Email) error {
+	raw, err := e.Bytes()
+	if err != nil {
+		return err
+	}
 
-	flag.StringVar(&res, "senderName", "", "sender name")
-	flag.Parse()
+	if sender.dkimSigner != nil {
+		if raw, err = sender.dkimSigner.Sign(raw); err != nil {
+			return err
+		}
+	}
 
-	if res == "" {
-		res = os.Getenv("EMAIL_NAME")
+	if err := client.Mail(sender.fromEmailAddress); err != nil {
+		return err
 	}
 
-	return res
-}
+	for _, recipient := range recipients(e) {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
 
-func fetchSenderEmail() string {
-	var res string
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
 
-	flag.StringVar(&res, "senderEmail", "", "sender email")
-	flag.Parse()
+	if _, err := w.Write(raw); err != nil {
+		w.Close()
 
-	if res == "" {
-		res = os.Getenv("SENDER_EMAIL")
+		return err
 	}
 
-	return res
+	return w.Close()
 }
 
-func fetchSenderPassword() string {
-	var res string
+// Ping checks that SMTP server is reachable.
+func (sender *GmailSender) Ping(ctx context.Context) error {
+	const op = "Gmail.Ping"
 
-	flag.StringVar(&res, "senderPassword", "", "sender password")
-	flag.Parse()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", smtpServerAddress)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return conn.Close()
+}
+
+// sendSigned sends email with DKIM signature.
+// email.Send can't be used here, since the signature must be computed over the final message bytes.
+func (sender *GmailSender) sendSigned(e *email.Email, smtpAuth smtp.Auth) error {
+	raw, err := e.Bytes()
+	if err != nil {
+		return err
+	}
 
-	if res == "" {
-		res = os.Getenv("SENDER_PASSWORD")
+	signed, err := sender.dkimSigner.Sign(raw)
+	if err != nil {
+		return err
 	}
 
-	return res
+	return smtp.SendMail(smtpServerAddress, smtpAuth, sender.fromEmailAddress, recipients(e), signed)
+}
+
+// recipients returns addresses of all recipients of the email including Bcc ones.
+func recipients(e *email.Email) []string {
+	res := make([]string, 0, len(e.To)+len(e.Cc)+len(e.Bcc))
+
+	return append(append(append(res, e.To...), e.Cc...), e.Bcc...)
 }
@@ -0,0 +1,392 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+	"grpc-service-ref/internal/lib/tracing"
+	"grpc-service-ref/internal/storage"
+)
+
+// Sender is an email provider (gmail, console, ...).
+// It should return message ID even if sending failed, if one was assigned.
+type Sender interface {
+	SendEmail(
+		ctx context.Context,
+		subject string,
+		to []string,
+		content string,
+		cc []string,
+		bcc []string,
+		atachFiles []string,
+	) (messageID string, err error)
+}
+
+type EmailSaver interface {
+	SaveEmail(ctx context.Context, email models.Email) (int64, error)
+	UpdateEmailStatus(
+		ctx context.Context,
+		messageID string,
+		recipient string,
+		status models.EmailStatus,
+		reason string,
+	) error
+}
+
+type SuppressionSaver interface {
+	SaveSuppression(ctx context.Context, suppression models.Suppression) error
+}
+
+type SuppressionProvider interface {
+	Suppression(ctx context.Context, email string) (models.Suppression, error)
+	Suppressions(ctx context.Context, limit int, offset int) ([]models.Suppression, error)
+}
+
+type SuppressionDeleter interface {
+	DeleteSuppression(ctx context.Context, email string) error
+}
+
+type PreferencesProvider interface {
+	NotificationPreferences(ctx context.Context, email string) (models.NotificationPreferences, error)
+}
+
+var (
+	// ErrRecipientsSuppressed is returned when every recipient of the email is suppressed.
+	ErrRecipientsSuppressed = errors.New("all recipients are suppressed")
+	// ErrRecipientsOptedOut is returned when every recipient of the notification opted out of its category.
+	ErrRecipientsOptedOut = errors.New("all recipients opted out")
+)
+
+type EmailProvider interface {
+	Emails(ctx context.Context, messageID string) ([]models.Email, error)
+}
+
+// Mail wraps Sender and records delivery status of every sent email.
+type Mail struct {
+	log                 *slog.Logger
+	sender              Sender
+	emailSaver          EmailSaver
+	emailProvider       EmailProvider
+	suppressionSaver    SuppressionSaver
+	suppressionProvider SuppressionProvider
+	suppressionDeleter  SuppressionDeleter
+	preferences         PreferencesProvider
+}
+
+func New(
+	log *slog.Logger,
+	sender Sender,
+	emailSaver EmailSaver,
+	emailProvider EmailProvider,
+	suppressionSaver SuppressionSaver,
+	suppressionProvider SuppressionProvider,
+	suppressionDeleter SuppressionDeleter,
+	preferences PreferencesProvider,
+) *Mail {
+	return &Mail{
+		log:                 log,
+		sender:              sender,
+		emailSaver:          emailSaver,
+		emailProvider:       emailProvider,
+		suppressionSaver:    suppressionSaver,
+		suppressionProvider: suppressionProvider,
+		suppressionDeleter:  suppressionDeleter,
+		preferences:         preferences,
+	}
+}
+
+// SendEmail sends email via underlying Sender and saves a delivery record for every recipient.
+//
+// Suppressed recipients are skipped, if there is no one left in to, ErrRecipientsSuppressed is returned.
+// Failing to save the record doesn't fail the send.
+func (m *Mail) SendEmail(
+	ctx context.Context,
+	subject string,
+	to []string,
+	content string,
+	cc []string,
+	bcc []string,
+	atachFiles []string,
+) (string, error) {
+	const op = "Mail.SendEmail"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := m.log.With(
+		slog.String("op", op),
+	)
+
+	var err error
+	if to, err = m.filterSuppressed(ctx, log, to); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(to) == 0 {
+		log.Warn("email is not sent, all recipients are suppressed")
+
+		return "", fmt.Errorf("%s: %w", op, ErrRecipientsSuppressed)
+	}
+
+	if cc, err = m.filterSuppressed(ctx, log, cc); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if bcc, err = m.filterSuppressed(ctx, log, bcc); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	msgID, sendErr := m.sender.SendEmail(ctx, subject, to, content, cc, bcc, atachFiles)
+
+	status, reason := models.EmailStatusSent, ""
+	if sendErr != nil {
+		status, reason = models.EmailStatusFailed, sendErr.Error()
+	}
+
+	if msgID != "" {
+		m.saveDeliveries(ctx, log, msgID, subject, to, status, reason)
+	}
+
+	if sendErr != nil {
+		log.Error("failed to send email", sl.Err(sendErr))
+		tracing.Fail(span, sendErr)
+		metrics.Emails.WithLabelValues(metrics.EmailFailed).Inc()
+
+		return "", fmt.Errorf("%s: %w", op, sendErr)
+	}
+
+	log.Info("email sent", slog.String("message_id", msgID))
+	metrics.Emails.WithLabelValues(metrics.EmailSent).Inc()
+
+	return msgID, nil
+}
+
+// SendNotification sends non-essential email of the category to recipients who haven't opted out of it,
+// see models.NotificationPreferences. If no one is left, ErrRecipientsOptedOut is returned.
+// Nil PreferencesProvider disables preferences, notifications are sent to everyone.
+func (m *Mail) SendNotification(
+	ctx context.Context,
+	category models.EmailCategory,
+	subject string,
+	to []string,
+	content string,
+) (string, error) {
+	const op = "Mail.SendNotification"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.String("category", string(category)),
+	)
+
+	if m.preferences != nil {
+		allowed := make([]string, 0, len(to))
+		for _, address := range to {
+			prefs, err := m.preferences.NotificationPreferences(ctx, address)
+			if err != nil {
+				log.Error("failed to get notification preferences", sl.Err(err))
+
+				return "", fmt.Errorf("%s: %w", op, err)
+			}
+
+			if !prefs.Allows(category) {
+				log.Info("skipping opted out recipient", slog.String("recipient", address))
+
+				continue
+			}
+
+			allowed = append(allowed, address)
+		}
+
+		if len(allowed) == 0 {
+			return "", fmt.Errorf("%s: %w", op, ErrRecipientsOptedOut)
+		}
+
+		to = allowed
+	}
+
+	msgID, err := m.SendEmail(ctx, subject, to, content, []string{}, []string{}, []string{})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return msgID, nil
+}
+
+// filterSuppressed returns addresses which are not in the suppression list.
+func (m *Mail) filterSuppressed(ctx context.Context, log *slog.Logger, addresses []string) ([]string, error) {
+	res := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		_, err := m.suppressionProvider.Suppression(ctx, address)
+		if err == nil {
+			log.Info("skipping suppressed recipient", slog.String("recipient", address))
+
+			continue
+		}
+
+		if !errors.Is(err, storage.ErrSuppressionNotFound) {
+			log.Error("failed to check suppression list", sl.Err(err))
+
+			return nil, err
+		}
+
+		res = append(res, address)
+	}
+
+	return res, nil
+}
+
+func (m *Mail) saveDeliveries(
+	ctx context.Context,
+	log *slog.Logger,
+	msgID string,
+	subject string,
+	to []string,
+	status models.EmailStatus,
+	reason string,
+) {
+	now := time.Now().UTC()
+
+	for _, recipient := range to {
+		_, err := m.emailSaver.SaveEmail(ctx, models.Email{
+			MessageID: msgID,
+			Recipient: recipient,
+			Subject:   subject,
+			Status:    status,
+			Reason:    reason,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			log.Error("failed to save email delivery record", sl.Err(err))
+		}
+	}
+}
+
+// EmailStatus returns delivery records of the message with given provider message ID.
+func (m *Mail) EmailStatus(ctx context.Context, messageID string) ([]models.Email, error) {
+	const op = "Mail.EmailStatus"
+
+	emails, err := m.emailProvider.Emails(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return emails, nil
+}
+
+// ReportDeliveryEvent updates delivery status of the email reported by provider.
+// Recipient is suppressed on hard bounce or complaint, so no more emails are sent to it.
+func (m *Mail) ReportDeliveryEvent(ctx context.Context, event models.DeliveryEvent) error {
+	const op = "Mail.ReportDeliveryEvent"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.String("message_id", event.MessageID),
+		slog.String("recipient", event.Recipient),
+		slog.String("status", string(event.Status)),
+	)
+
+	log.Info("received delivery event")
+
+	if event.MessageID != "" {
+		err := m.emailSaver.UpdateEmailStatus(ctx, event.MessageID, event.Recipient, event.Status, event.Reason)
+		if err != nil {
+			if !errors.Is(err, storage.ErrEmailNotFound) {
+				log.Error("failed to update email status", sl.Err(err))
+
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			log.Warn("email not found", sl.Err(err))
+		}
+	}
+
+	var source models.SuppressionSource
+	switch {
+	case event.Status == models.EmailStatusComplained:
+		source = models.SuppressionSourceComplaint
+	case event.Status == models.EmailStatusBounced && event.Permanent:
+		source = models.SuppressionSourceBounce
+	default:
+		return nil
+	}
+
+	err := m.suppressionSaver.SaveSuppression(ctx, models.Suppression{
+		Email:     event.Recipient,
+		Source:    source,
+		Reason:    event.Reason,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Error("failed to suppress recipient", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("recipient suppressed", slog.String("source", string(source)))
+
+	return nil
+}
+
+// Suppress manually adds email to the suppression list.
+func (m *Mail) Suppress(ctx context.Context, email string, reason string) error {
+	const op = "Mail.Suppress"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	log.Info("suppressing email")
+
+	err := m.suppressionSaver.SaveSuppression(ctx, models.Suppression{
+		Email:     email,
+		Source:    models.SuppressionSourceManual,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Error("failed to suppress email", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Unsuppress removes email from the suppression list regardless of how it was added.
+func (m *Mail) Unsuppress(ctx context.Context, email string) error {
+	const op = "Mail.Unsuppress"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	log.Info("removing email from suppression list")
+
+	if err := m.suppressionDeleter.DeleteSuppression(ctx, email); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Suppressions returns page of the suppression list.
+func (m *Mail) Suppressions(ctx context.Context, limit int, offset int) ([]models.Suppression, error) {
+	const op = "Mail.Suppressions"
+
+	suppressions, err := m.suppressionProvider.Suppressions(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return suppressions, nil
+}
@@ -0,0 +1,86 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	mail "grpc-service-ref/internal/services/mail"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BatchSender is an autogenerated mock type for the BatchSender type
+type BatchSender struct {
+	mock.Mock
+}
+
+type BatchSender_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BatchSender) EXPECT() *BatchSender_Expecter {
+	return &BatchSender_Expecter{mock: &_m.Mock}
+}
+
+// SendEmails provides a mock function with given fields: ctx, messages
+func (_m *BatchSender) SendEmails(ctx context.Context, messages []mail.Message) []mail.SendResult {
+	ret := _m.Called(ctx, messages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendEmails")
+	}
+
+	var r0 []mail.SendResult
+	if rf, ok := ret.Get(0).(func(context.Context, []mail.Message) []mail.SendResult); ok {
+		r0 = rf(ctx, messages)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]mail.SendResult)
+		}
+	}
+
+	return r0
+}
+
+// BatchSender_SendEmails_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendEmails'
+type BatchSender_SendEmails_Call struct {
+	*mock.Call
+}
+
+// SendEmails is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messages []mail.Message
+func (_e *BatchSender_Expecter) SendEmails(ctx interface{}, messages interface{}) *BatchSender_SendEmails_Call {
+	return &BatchSender_SendEmails_Call{Call: _e.mock.On("SendEmails", ctx, messages)}
+}
+
+func (_c *BatchSender_SendEmails_Call) Run(run func(ctx context.Context, messages []mail.Message)) *BatchSender_SendEmails_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]mail.Message))
+	})
+	return _c
+}
+
+func (_c *BatchSender_SendEmails_Call) Return(_a0 []mail.SendResult) *BatchSender_SendEmails_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BatchSender_SendEmails_Call) RunAndReturn(run func(context.Context, []mail.Message) []mail.SendResult) *BatchSender_SendEmails_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBatchSender creates a new instance of BatchSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBatchSender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BatchSender {
+	mock := &BatchSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
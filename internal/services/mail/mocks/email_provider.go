@@ -0,0 +1,97 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+)
+
+// EmailProvider is an autogenerated mock type for the EmailProvider type
+type EmailProvider struct {
+	mock.Mock
+}
+
+type EmailProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EmailProvider) EXPECT() *EmailProvider_Expecter {
+	return &EmailProvider_Expecter{mock: &_m.Mock}
+}
+
+// Emails provides a mock function with given fields: ctx, messageID
+func (_m *EmailProvider) Emails(ctx context.Context, messageID string) ([]models.Email, error) {
+	ret := _m.Called(ctx, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Emails")
+	}
+
+	var r0 []models.Email
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Email, error)); ok {
+		return rf(ctx, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Email); ok {
+		r0 = rf(ctx, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Email)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EmailProvider_Emails_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Emails'
+type EmailProvider_Emails_Call struct {
+	*mock.Call
+}
+
+// Emails is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageID string
+func (_e *EmailProvider_Expecter) Emails(ctx interface{}, messageID interface{}) *EmailProvider_Emails_Call {
+	return &EmailProvider_Emails_Call{Call: _e.mock.On("Emails", ctx, messageID)}
+}
+
+func (_c *EmailProvider_Emails_Call) Run(run func(ctx context.Context, messageID string)) *EmailProvider_Emails_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *EmailProvider_Emails_Call) Return(_a0 []models.Email, _a1 error) *EmailProvider_Emails_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *EmailProvider_Emails_Call) RunAndReturn(run func(context.Context, string) ([]models.Email, error)) *EmailProvider_Emails_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEmailProvider creates a new instance of EmailProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailProvider {
+	mock := &EmailProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,145 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+)
+
+// EmailSaver is an autogenerated mock type for the EmailSaver type
+type EmailSaver struct {
+	mock.Mock
+}
+
+type EmailSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EmailSaver) EXPECT() *EmailSaver_Expecter {
+	return &EmailSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveEmail provides a mock function with given fields: ctx, email
+func (_m *EmailSaver) SaveEmail(ctx context.Context, email models.Email) (int64, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEmail")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Email) (int64, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Email) int64); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Email) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EmailSaver_SaveEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveEmail'
+type EmailSaver_SaveEmail_Call struct {
+	*mock.Call
+}
+
+// SaveEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email models.Email
+func (_e *EmailSaver_Expecter) SaveEmail(ctx interface{}, email interface{}) *EmailSaver_SaveEmail_Call {
+	return &EmailSaver_SaveEmail_Call{Call: _e.mock.On("SaveEmail", ctx, email)}
+}
+
+func (_c *EmailSaver_SaveEmail_Call) Run(run func(ctx context.Context, email models.Email)) *EmailSaver_SaveEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Email))
+	})
+	return _c
+}
+
+func (_c *EmailSaver_SaveEmail_Call) Return(_a0 int64, _a1 error) *EmailSaver_SaveEmail_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *EmailSaver_SaveEmail_Call) RunAndReturn(run func(context.Context, models.Email) (int64, error)) *EmailSaver_SaveEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateEmailStatus provides a mock function with given fields: ctx, messageID, recipient, status, reason
+func (_m *EmailSaver) UpdateEmailStatus(ctx context.Context, messageID string, recipient string, status models.EmailStatus, reason string) error {
+	ret := _m.Called(ctx, messageID, recipient, status, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmailStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.EmailStatus, string) error); ok {
+		r0 = rf(ctx, messageID, recipient, status, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EmailSaver_UpdateEmailStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateEmailStatus'
+type EmailSaver_UpdateEmailStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateEmailStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageID string
+//   - recipient string
+//   - status models.EmailStatus
+//   - reason string
+func (_e *EmailSaver_Expecter) UpdateEmailStatus(ctx interface{}, messageID interface{}, recipient interface{}, status interface{}, reason interface{}) *EmailSaver_UpdateEmailStatus_Call {
+	return &EmailSaver_UpdateEmailStatus_Call{Call: _e.mock.On("UpdateEmailStatus", ctx, messageID, recipient, status, reason)}
+}
+
+func (_c *EmailSaver_UpdateEmailStatus_Call) Run(run func(ctx context.Context, messageID string, recipient string, status models.EmailStatus, reason string)) *EmailSaver_UpdateEmailStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(models.EmailStatus), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *EmailSaver_UpdateEmailStatus_Call) Return(_a0 error) *EmailSaver_UpdateEmailStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *EmailSaver_UpdateEmailStatus_Call) RunAndReturn(run func(context.Context, string, string, models.EmailStatus, string) error) *EmailSaver_UpdateEmailStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEmailSaver creates a new instance of EmailSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailSaver {
+	mock := &EmailSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
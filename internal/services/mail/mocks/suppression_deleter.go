@@ -0,0 +1,83 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SuppressionDeleter is an autogenerated mock type for the SuppressionDeleter type
+type SuppressionDeleter struct {
+	mock.Mock
+}
+
+type SuppressionDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SuppressionDeleter) EXPECT() *SuppressionDeleter_Expecter {
+	return &SuppressionDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteSuppression provides a mock function with given fields: ctx, email
+func (_m *SuppressionDeleter) DeleteSuppression(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSuppression")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SuppressionDeleter_DeleteSuppression_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSuppression'
+type SuppressionDeleter_DeleteSuppression_Call struct {
+	*mock.Call
+}
+
+// DeleteSuppression is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *SuppressionDeleter_Expecter) DeleteSuppression(ctx interface{}, email interface{}) *SuppressionDeleter_DeleteSuppression_Call {
+	return &SuppressionDeleter_DeleteSuppression_Call{Call: _e.mock.On("DeleteSuppression", ctx, email)}
+}
+
+func (_c *SuppressionDeleter_DeleteSuppression_Call) Run(run func(ctx context.Context, email string)) *SuppressionDeleter_DeleteSuppression_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *SuppressionDeleter_DeleteSuppression_Call) Return(_a0 error) *SuppressionDeleter_DeleteSuppression_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SuppressionDeleter_DeleteSuppression_Call) RunAndReturn(run func(context.Context, string) error) *SuppressionDeleter_DeleteSuppression_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSuppressionDeleter creates a new instance of SuppressionDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSuppressionDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SuppressionDeleter {
+	mock := &SuppressionDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
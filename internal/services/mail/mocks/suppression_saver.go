@@ -0,0 +1,85 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+)
+
+// SuppressionSaver is an autogenerated mock type for the SuppressionSaver type
+type SuppressionSaver struct {
+	mock.Mock
+}
+
+type SuppressionSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SuppressionSaver) EXPECT() *SuppressionSaver_Expecter {
+	return &SuppressionSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveSuppression provides a mock function with given fields: ctx, suppression
+func (_m *SuppressionSaver) SaveSuppression(ctx context.Context, suppression models.Suppression) error {
+	ret := _m.Called(ctx, suppression)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSuppression")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Suppression) error); ok {
+		r0 = rf(ctx, suppression)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SuppressionSaver_SaveSuppression_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSuppression'
+type SuppressionSaver_SaveSuppression_Call struct {
+	*mock.Call
+}
+
+// SaveSuppression is a helper method to define mock.On call
+//   - ctx context.Context
+//   - suppression models.Suppression
+func (_e *SuppressionSaver_Expecter) SaveSuppression(ctx interface{}, suppression interface{}) *SuppressionSaver_SaveSuppression_Call {
+	return &SuppressionSaver_SaveSuppression_Call{Call: _e.mock.On("SaveSuppression", ctx, suppression)}
+}
+
+func (_c *SuppressionSaver_SaveSuppression_Call) Run(run func(ctx context.Context, suppression models.Suppression)) *SuppressionSaver_SaveSuppression_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Suppression))
+	})
+	return _c
+}
+
+func (_c *SuppressionSaver_SaveSuppression_Call) Return(_a0 error) *SuppressionSaver_SaveSuppression_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SuppressionSaver_SaveSuppression_Call) RunAndReturn(run func(context.Context, models.Suppression) error) *SuppressionSaver_SaveSuppression_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSuppressionSaver creates a new instance of SuppressionSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSuppressionSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SuppressionSaver {
+	mock := &SuppressionSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
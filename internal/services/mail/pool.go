@@ -0,0 +1,230 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"grpc-service-ref/internal/lib/metrics"
+)
+
+// Message is an email queued for sending.
+type Message struct {
+	Subject     string
+	To          []string
+	Content     string
+	Cc          []string
+	Bcc         []string
+	AttachFiles []string
+}
+
+// SendResult is the result of sending a Message, MessageID may be set even if sending failed.
+type SendResult struct {
+	MessageID string
+	Err       error
+}
+
+// BatchSender is a Sender which sends several emails at once more cheaply than one by one,
+// e.g. over a single SMTP connection. Results are in order of messages.
+type BatchSender interface {
+	SendEmails(ctx context.Context, messages []Message) []SendResult
+}
+
+// ErrPoolStopped is returned by SendEmail after Pool is stopped.
+var ErrPoolStopped = errors.New("email pool is stopped")
+
+type job struct {
+	ctx    context.Context
+	msg    Message
+	result chan SendResult
+}
+
+// Pool sends emails via underlying Sender by a fixed number of workers,
+// so bursts of emails don't open unbounded number of provider connections.
+// Callers wait for their email to be sent, they are blocked while the queue is full.
+type Pool struct {
+	log    *slog.Logger
+	sender Sender
+	// batcher is sender if it supports batching, nil otherwise.
+	batcher   BatchSender
+	workers   int
+	batchSize int
+
+	queue chan job
+	// mu guards stopped, it's held for reading while job is queued, so queue isn't closed meanwhile.
+	mu      sync.RWMutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewPool returns Pool of workers sending up to batchSize emails at once if sender supports batching.
+func NewPool(log *slog.Logger, sender Sender, workers int, batchSize int) *Pool {
+	batcher, _ := sender.(BatchSender)
+
+	return &Pool{
+		log:       log,
+		sender:    sender,
+		batcher:   batcher,
+		workers:   workers,
+		batchSize: batchSize,
+		queue:     make(chan job, workers*batchSize),
+	}
+}
+
+// Start starts workers.
+func (p *Pool) Start() {
+	p.wg.Add(p.workers)
+	for i := 0; i < p.workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			p.work()
+		}()
+	}
+}
+
+// Stop stops accepting emails and waits until queued ones are sent.
+func (p *Pool) Stop() {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// SendEmail queues email and waits until it's sent.
+func (p *Pool) SendEmail(
+	ctx context.Context,
+	subject string,
+	to []string,
+	content string,
+	cc []string,
+	bcc []string,
+	atachFiles []string,
+) (string, error) {
+	const op = "Pool.SendEmail"
+
+	j := job{
+		ctx: ctx,
+		msg: Message{
+			Subject:     subject,
+			To:          to,
+			Content:     content,
+			Cc:          cc,
+			Bcc:         bcc,
+			AttachFiles: atachFiles,
+		},
+		result: make(chan SendResult, 1),
+	}
+
+	if err := p.enqueue(ctx, j); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	select {
+	case res := <-j.result:
+		return res.MessageID, res.Err
+	case <-ctx.Done():
+		return "", fmt.Errorf("%s: %w", op, ctx.Err())
+	}
+}
+
+func (p *Pool) enqueue(ctx context.Context, j job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.stopped {
+		return ErrPoolStopped
+	}
+
+	select {
+	case p.queue <- j:
+		metrics.EmailQueue.Inc()
+
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work sends queued emails until queue is closed, taking up to batchSize of them at once.
+func (p *Pool) work() {
+	batch := make([]job, 0, p.batchSize)
+
+	for j := range p.queue {
+		batch = append(batch[:0], j)
+
+	fill:
+		for len(batch) < p.batchSize {
+			select {
+			case next, ok := <-p.queue:
+				if !ok {
+					break fill
+				}
+
+				batch = append(batch, next)
+			default:
+				break fill
+			}
+		}
+
+		metrics.EmailQueue.Sub(float64(len(batch)))
+
+		p.send(batch)
+	}
+}
+
+// send sends the batch, emails of callers which stopped waiting are skipped.
+func (p *Pool) send(batch []job) {
+	pending := batch[:0]
+	for _, j := range batch {
+		if err := j.ctx.Err(); err != nil {
+			j.result <- SendResult{Err: err}
+
+			continue
+		}
+
+		pending = append(pending, j)
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	if p.batcher == nil || len(pending) == 1 {
+		for _, j := range pending {
+			msgID, err := p.sender.SendEmail(j.ctx, j.msg.Subject, j.msg.To, j.msg.Content, j.msg.Cc, j.msg.Bcc, j.msg.AttachFiles)
+			j.result <- SendResult{MessageID: msgID, Err: err}
+		}
+
+		return
+	}
+
+	messages := make([]Message, len(pending))
+	for i, j := range pending {
+		messages[i] = j.msg
+	}
+
+	// Batch is sent on behalf of several callers, so it's not canceled if the first one stops waiting.
+	results := p.batcher.SendEmails(context.WithoutCancel(pending[0].ctx), messages)
+	if len(results) != len(pending) {
+		p.log.Error("batch sender returned wrong number of results",
+			slog.Int("messages", len(pending)),
+			slog.Int("results", len(results)),
+		)
+
+		for _, j := range pending {
+			j.result <- SendResult{Err: errors.New("batch sender returned wrong number of results")}
+		}
+
+		return
+	}
+
+	for i, j := range pending {
+		j.result <- results[i]
+	}
+}
@@ -0,0 +1,190 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSender sends emails one by one, it records how many are sent at once.
+type countingSender struct {
+	delay    time.Duration
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+	sent     atomic.Int32
+}
+
+func (s *countingSender) SendEmail(context.Context, string, []string, string, []string, []string, []string) (string, error) {
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+
+	for {
+		max := s.maxSeen.Load()
+		if n <= max || s.maxSeen.CompareAndSwap(max, n) {
+			break
+		}
+	}
+
+	time.Sleep(s.delay)
+
+	return fmt.Sprintf("msg-%d", s.sent.Add(1)), nil
+}
+
+// recordingBatchSender records sizes of batches, results are the subjects of messages.
+type recordingBatchSender struct {
+	countingSender
+	mu      sync.Mutex
+	batches []int
+	// short drops the last result of batches.
+	short bool
+}
+
+func (s *recordingBatchSender) SendEmails(_ context.Context, messages []Message) []SendResult {
+	s.mu.Lock()
+	s.batches = append(s.batches, len(messages))
+	s.mu.Unlock()
+
+	results := make([]SendResult, len(messages))
+	for i, msg := range messages {
+		results[i] = SendResult{MessageID: msg.Subject}
+	}
+
+	if s.short {
+		results = results[:len(results)-1]
+	}
+
+	return results
+}
+
+func newTestPool(sender Sender, workers int, batchSize int) *Pool {
+	return NewPool(slog.New(slog.NewTextHandler(io.Discard, nil)), sender, workers, batchSize)
+}
+
+// sendAll sends emails with subjects "0", "1"... concurrently and returns message IDs and errors in order of subjects.
+func sendAll(ctx context.Context, p *Pool, n int) ([]string, []error) {
+	ids := make([]string, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			ids[i], errs[i] = p.SendEmail(ctx, fmt.Sprint(i), []string{"user@example.com"}, "content", nil, nil, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	return ids, errs
+}
+
+// waitQueued waits until n emails are queued, so workers started afterwards see them at once.
+func waitQueued(t *testing.T, p *Pool, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return len(p.queue) == n }, time.Second, time.Millisecond)
+}
+
+func TestPoolBoundsWorkers(t *testing.T) {
+	sender := &countingSender{delay: 5 * time.Millisecond}
+	p := newTestPool(sender, 2, 1)
+	p.Start()
+	defer p.Stop()
+
+	_, errs := sendAll(context.Background(), p, 10)
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(10), sender.sent.Load())
+	assert.LessOrEqual(t, sender.maxSeen.Load(), int32(2), "no more emails are sent at once than there are workers")
+}
+
+func TestPoolBatches(t *testing.T) {
+	sender := &recordingBatchSender{}
+	p := newTestPool(sender, 1, 3)
+
+	var (
+		ids  []string
+		errs []error
+		done = make(chan struct{})
+	)
+	go func() {
+		ids, errs = sendAll(context.Background(), p, 3)
+		close(done)
+	}()
+
+	waitQueued(t, p, 3)
+	p.Start()
+	<-done
+	p.Stop()
+
+	assert.Equal(t, []int{3}, sender.batches)
+	for i := range ids {
+		require.NoError(t, errs[i])
+		assert.Equal(t, fmt.Sprint(i), ids[i], "results are returned to their callers")
+	}
+	assert.Zero(t, sender.sent.Load(), "batch isn't sent one by one")
+}
+
+func TestPoolBatchWithWrongResults(t *testing.T) {
+	sender := &recordingBatchSender{short: true}
+	p := newTestPool(sender, 1, 2)
+
+	var (
+		errs []error
+		done = make(chan struct{})
+	)
+	go func() {
+		_, errs = sendAll(context.Background(), p, 2)
+		close(done)
+	}()
+
+	waitQueued(t, p, 2)
+	p.Start()
+	<-done
+	p.Stop()
+
+	for _, err := range errs {
+		assert.Error(t, err)
+	}
+}
+
+func TestPoolSkipsCanceled(t *testing.T) {
+	sender := &countingSender{}
+	p := newTestPool(sender, 1, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := p.SendEmail(ctx, "subject", []string{"user@example.com"}, "content", nil, nil, nil)
+		errs <- err
+	}()
+
+	waitQueued(t, p, 1)
+	cancel()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+
+	p.Start()
+	p.Stop()
+	assert.Zero(t, sender.sent.Load(), "email of the caller which stopped waiting isn't sent")
+}
+
+func TestPoolStop(t *testing.T) {
+	sender := &countingSender{}
+	p := newTestPool(sender, 1, 1)
+	p.Start()
+	p.Stop()
+	p.Stop()
+
+	_, err := p.SendEmail(context.Background(), "subject", []string{"user@example.com"}, "content", nil, nil, nil)
+	assert.ErrorIs(t, err, ErrPoolStopped)
+}
@@ -0,0 +1,284 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/lib/ttlcache"
+	"grpc-service-ref/internal/storage"
+)
+
+// maxMessageLen bounds messages shown to clients.
+const maxMessageLen = 500
+
+var (
+	ErrInvalidMessage = errors.New("invalid maintenance message")
+	ErrInvalidEndsAt  = errors.New("maintenance must end in the future")
+)
+
+type Store interface {
+	SaveMaintenance(ctx context.Context, m models.Maintenance) error
+	DeleteMaintenance(ctx context.Context, appID int) error
+	Maintenances(ctx context.Context, at time.Time) ([]models.Maintenance, error)
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+// Auditor records security-relevant actions to the audit log.
+type Auditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// Settings is maintenance of all apps turned on by config.
+type Settings struct {
+	Enabled bool
+	Message string
+}
+
+// Notice tells clients rejected during maintenance what is going on and when to retry.
+type Notice struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+// Maintenance turns maintenance windows of all apps or of one app on and off. During a window the public
+// server rejects RPCs with Unavailable, see Check.
+//
+// Windows are turned on by config or at runtime by operators. Runtime windows are kept in storage,
+// so all replicas see them, and are cached, so replicas pick them up within the cache TTL.
+type Maintenance struct {
+	log     *slog.Logger
+	store   Store
+	apps    AppProvider
+	auditor Auditor
+	clock   clock.Clock
+	// retryAfter is the retry delay told to clients if the window has no end.
+	retryAfter time.Duration
+	cache      *ttlcache.Cache[struct{}, []models.Maintenance]
+
+	mu       sync.RWMutex
+	settings Settings
+}
+
+// New returns Maintenance caching runtime windows for ttl, zero ttl disables caching.
+func New(
+	log *slog.Logger,
+	store Store,
+	apps AppProvider,
+	auditor Auditor,
+	clock clock.Clock,
+	ttl time.Duration,
+	retryAfter time.Duration,
+	settings Settings,
+) *Maintenance {
+	return &Maintenance{
+		log:        log,
+		store:      store,
+		apps:       apps,
+		auditor:    auditor,
+		clock:      clock,
+		retryAfter: retryAfter,
+		cache:      ttlcache.New[struct{}, []models.Maintenance]("maintenance", ttl, 1),
+		settings:   settings,
+	}
+}
+
+// SetSettings changes maintenance turned on by config of the running service.
+func (m *Maintenance) SetSettings(settings Settings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.settings = settings
+}
+
+// Check reports whether the app is under maintenance, appID 0 means request is not for an app,
+// so only maintenance of all apps applies to it.
+func (m *Maintenance) Check(ctx context.Context, appID int) (Notice, bool, error) {
+	const op = "Maintenance.Check"
+
+	m.mu.RLock()
+	settings := m.settings
+	m.mu.RUnlock()
+
+	if settings.Enabled {
+		return Notice{Message: settings.Message, RetryAfter: m.retryAfter}, true, nil
+	}
+
+	windows, err := m.windows(ctx)
+	if err != nil {
+		return Notice{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := m.clock.Now()
+	for _, w := range windows {
+		// Cached windows may have ended since they were loaded.
+		if (w.AppID != 0 && w.AppID != appID) || (!w.EndsAt.IsZero() && !now.Before(w.EndsAt)) {
+			continue
+		}
+
+		retryAfter := m.retryAfter
+		if !w.EndsAt.IsZero() {
+			retryAfter = w.EndsAt.Sub(now)
+		}
+
+		return Notice{Message: w.Message, RetryAfter: retryAfter}, true, nil
+	}
+
+	return Notice{}, false, nil
+}
+
+// Windows returns runtime maintenance windows which are not over, ordered by app ID.
+// Maintenance turned on by config is not among them.
+func (m *Maintenance) Windows(ctx context.Context) ([]models.Maintenance, error) {
+	const op = "Maintenance.Windows"
+
+	windows, err := m.store.Maintenances(ctx, m.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return windows, nil
+}
+
+// Enable starts maintenance window of the app, or of all apps if appID is 0, replacing the running one.
+// Zero endsAt means the window lasts until it's disabled.
+func (m *Maintenance) Enable(ctx context.Context, appID int, message string, endsAt time.Time) (models.Maintenance, error) {
+	const op = "Maintenance.Enable"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	message = strings.TrimSpace(message)
+	if len(message) > maxMessageLen || strings.ContainsFunc(message, unicode.IsControl) {
+		return models.Maintenance{}, fmt.Errorf("%s: %w", op, ErrInvalidMessage)
+	}
+
+	now := m.clock.Now().UTC()
+	if !endsAt.IsZero() && !endsAt.After(now) {
+		return models.Maintenance{}, fmt.Errorf("%s: %w", op, ErrInvalidEndsAt)
+	}
+
+	subject, err := m.subject(ctx, appID)
+	if err != nil {
+		return models.Maintenance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	window := models.Maintenance{
+		AppID:     appID,
+		Message:   message,
+		StartedAt: now,
+		EndsAt:    endsAt.UTC(),
+	}
+
+	if err := m.store.SaveMaintenance(ctx, window); err != nil {
+		log.Error("failed to save maintenance", sl.Err(err))
+
+		return models.Maintenance{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.cache.Purge()
+
+	log.Warn("maintenance enabled", slog.Time("ends_at", window.EndsAt))
+
+	payload := map[string]string{
+		"enabled": "true",
+		"message": message,
+	}
+	if !window.EndsAt.IsZero() {
+		payload["ends_at"] = window.EndsAt.Format(time.RFC3339)
+	}
+
+	m.audit(ctx, appID, subject, payload)
+
+	return window, nil
+}
+
+// Disable ends maintenance window of the app, or of all apps if appID is 0.
+// It's a no-op if there is no window, maintenance turned on by config is turned off by config only.
+func (m *Maintenance) Disable(ctx context.Context, appID int) error {
+	const op = "Maintenance.Disable"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	subject, err := m.subject(ctx, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := m.store.DeleteMaintenance(ctx, appID); err != nil {
+		if errors.Is(err, storage.ErrMaintenanceNotFound) {
+			return nil
+		}
+
+		log.Error("failed to delete maintenance", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.cache.Purge()
+
+	log.Warn("maintenance disabled")
+
+	m.audit(ctx, appID, subject, map[string]string{"enabled": "false"})
+
+	return nil
+}
+
+// windows returns cached runtime windows, all of them are reloaded once expired.
+func (m *Maintenance) windows(ctx context.Context) ([]models.Maintenance, error) {
+	return m.cache.Load(struct{}{}, func() ([]models.Maintenance, error) {
+		return m.store.Maintenances(ctx, m.clock.Now())
+	})
+}
+
+// subject returns name of the app audit events are about, it fails with storage.ErrAppNotFound for unknown apps.
+func (m *Maintenance) subject(ctx context.Context, appID int) (string, error) {
+	if appID == 0 {
+		return "all apps", nil
+	}
+
+	app, err := m.apps.App(ctx, appID)
+	if err != nil {
+		return "", err
+	}
+
+	return app.Name, nil
+}
+
+func (m *Maintenance) audit(ctx context.Context, appID int, subject string, payload map[string]string) {
+	payload["source"] = "admin_api"
+	if operator := peer.ClientCertName(ctx); operator != "" {
+		payload["operator"] = operator
+	}
+
+	event := models.AuditEvent{
+		Action:  models.AuditActionMaintenanceChanged,
+		Subject: subject,
+		AppID:   appID,
+		Payload: payload,
+	}
+	// Admin authenticated by the access token.
+	if claims, ok := jwt.FromContext(ctx); ok {
+		event.ActorID = claims.UserID
+	}
+
+	m.auditor.Record(ctx, event)
+}
@@ -0,0 +1,280 @@
+package maintenance
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/services/maintenance/mocks"
+	"grpc-service-ref/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testRetryAfter = 5 * time.Minute
+
+var (
+	testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+)
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	windows := []models.Maintenance{
+		{AppID: 2, Message: "app 2 upgrade", StartedAt: testNow, EndsAt: testNow.Add(time.Hour)},
+		{AppID: 3, Message: "app 3 upgrade", StartedAt: testNow},
+		{AppID: 4, Message: "ended", StartedAt: testNow.Add(-time.Hour), EndsAt: testNow},
+	}
+
+	tests := []struct {
+		name   string
+		appID  int
+		want   Notice
+		wantOn bool
+	}{
+		{"not for an app", 0, Notice{}, false},
+		{"app without window", 1, Notice{}, false},
+		{"window with end", 2, Notice{Message: "app 2 upgrade", RetryAfter: time.Hour}, true},
+		{"window without end", 3, Notice{Message: "app 3 upgrade", RetryAfter: testRetryAfter}, true},
+		{"ended window", 4, Notice{}, false},
+	}
+
+	store := mocks.NewStore(t)
+	store.EXPECT().Maintenances(ctx, testNow).Return(windows, nil).Once()
+
+	m := New(testLog, store, mocks.NewAppProvider(t), mocks.NewAuditor(t), clock.NewFake(testNow), time.Minute, testRetryAfter, Settings{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, on, err := m.Check(ctx, tt.appID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOn, on)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckAllApps(t *testing.T) {
+	ctx := context.Background()
+
+	store := mocks.NewStore(t)
+	store.EXPECT().Maintenances(ctx, testNow).
+		Return([]models.Maintenance{{Message: "upgrade", StartedAt: testNow, EndsAt: testNow.Add(time.Hour)}}, nil).Once()
+
+	clk := clock.NewFake(testNow)
+	m := New(testLog, store, mocks.NewAppProvider(t), mocks.NewAuditor(t), clk, time.Minute, testRetryAfter, Settings{})
+
+	for _, appID := range []int{0, 1} {
+		got, on, err := m.Check(ctx, appID)
+		require.NoError(t, err)
+		assert.True(t, on)
+		assert.Equal(t, Notice{Message: "upgrade", RetryAfter: time.Hour}, got)
+	}
+
+	clk.Advance(time.Hour)
+
+	_, on, err := m.Check(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, on, "cached window has ended")
+}
+
+func TestCheckSettings(t *testing.T) {
+	ctx := context.Background()
+
+	store := mocks.NewStore(t)
+	m := New(testLog, store, mocks.NewAppProvider(t), mocks.NewAuditor(t), clock.NewFake(testNow), time.Minute, testRetryAfter,
+		Settings{Enabled: true, Message: "upgrade"})
+
+	got, on, err := m.Check(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, on)
+	assert.Equal(t, Notice{Message: "upgrade", RetryAfter: testRetryAfter}, got)
+
+	m.SetSettings(Settings{})
+	store.EXPECT().Maintenances(ctx, testNow).Return(nil, nil).Once()
+
+	_, on, err = m.Check(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, on)
+}
+
+func TestCheckFailure(t *testing.T) {
+	ctx := context.Background()
+
+	store := mocks.NewStore(t)
+	store.EXPECT().Maintenances(ctx, testNow).Return(nil, assert.AnError).Twice()
+
+	m := New(testLog, store, mocks.NewAppProvider(t), mocks.NewAuditor(t), clock.NewFake(testNow), time.Minute, testRetryAfter, Settings{})
+
+	for i := 0; i < 2; i++ {
+		_, _, err := m.Check(ctx, 1)
+		assert.ErrorIs(t, err, assert.AnError, "errors are not cached")
+	}
+}
+
+func TestEnable(t *testing.T) {
+	ctx := jwt.NewContext(context.Background(), jwt.Claims{UserID: 7})
+	endsAt := testNow.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		appID   int
+		message string
+		endsAt  time.Time
+		setup   func(apps *mocks.AppProvider, store *mocks.Store, auditor *mocks.Auditor)
+		want    models.Maintenance
+		wantErr error
+	}{
+		{
+			name:    "app",
+			appID:   1,
+			message: " upgrade ",
+			endsAt:  endsAt,
+			setup: func(apps *mocks.AppProvider, store *mocks.Store, auditor *mocks.Auditor) {
+				apps.EXPECT().App(ctx, 1).Return(models.App{ID: 1, Name: "test"}, nil).Once()
+				store.EXPECT().SaveMaintenance(ctx, models.Maintenance{AppID: 1, Message: "upgrade", StartedAt: testNow, EndsAt: endsAt}).
+					Return(nil).Once()
+				auditor.EXPECT().Record(ctx, models.AuditEvent{
+					Action:  models.AuditActionMaintenanceChanged,
+					ActorID: 7,
+					Subject: "test",
+					AppID:   1,
+					Payload: map[string]string{
+						"enabled": "true",
+						"message": "upgrade",
+						"ends_at": "2024-01-01T13:00:00Z",
+						"source":  "admin_api",
+					},
+				}).Once()
+			},
+			want: models.Maintenance{AppID: 1, Message: "upgrade", StartedAt: testNow, EndsAt: endsAt},
+		},
+		{
+			name:    "all apps",
+			message: "upgrade",
+			setup: func(_ *mocks.AppProvider, store *mocks.Store, auditor *mocks.Auditor) {
+				store.EXPECT().SaveMaintenance(ctx, models.Maintenance{Message: "upgrade", StartedAt: testNow}).Return(nil).Once()
+				auditor.EXPECT().Record(ctx, mock.MatchedBy(func(event models.AuditEvent) bool {
+					_, hasEnd := event.Payload["ends_at"]
+
+					return event.Subject == "all apps" && !hasEnd
+				})).Once()
+			},
+			want: models.Maintenance{Message: "upgrade", StartedAt: testNow},
+		},
+		{
+			name:    "app not found",
+			appID:   9,
+			message: "upgrade",
+			setup: func(apps *mocks.AppProvider, _ *mocks.Store, _ *mocks.Auditor) {
+				apps.EXPECT().App(ctx, 9).Return(models.App{}, storage.ErrAppNotFound).Once()
+			},
+			wantErr: storage.ErrAppNotFound,
+		},
+		{name: "message too long", message: strings.Repeat("a", maxMessageLen+1), wantErr: ErrInvalidMessage},
+		{name: "control characters", message: "up\ngrade", wantErr: ErrInvalidMessage},
+		{name: "ends now", message: "upgrade", endsAt: testNow, wantErr: ErrInvalidEndsAt},
+		{name: "ended", message: "upgrade", endsAt: testNow.Add(-time.Minute), wantErr: ErrInvalidEndsAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apps, store, auditor := mocks.NewAppProvider(t), mocks.NewStore(t), mocks.NewAuditor(t)
+			if tt.setup != nil {
+				tt.setup(apps, store, auditor)
+			}
+
+			m := New(testLog, store, apps, auditor, clock.NewFake(testNow), time.Minute, testRetryAfter, Settings{})
+
+			got, err := m.Enable(ctx, tt.appID, tt.message, tt.endsAt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnablePurgesCache(t *testing.T) {
+	ctx := context.Background()
+	window := models.Maintenance{Message: "upgrade", StartedAt: testNow}
+
+	store, auditor := mocks.NewStore(t), mocks.NewAuditor(t)
+	m := New(testLog, store, mocks.NewAppProvider(t), auditor, clock.NewFake(testNow), time.Minute, testRetryAfter, Settings{})
+
+	store.EXPECT().Maintenances(ctx, testNow).Return(nil, nil).Once()
+	_, on, err := m.Check(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, on)
+
+	store.EXPECT().SaveMaintenance(ctx, window).Return(nil).Once()
+	auditor.EXPECT().Record(ctx, mock.Anything).Once()
+	_, err = m.Enable(ctx, 0, "upgrade", time.Time{})
+	require.NoError(t, err)
+
+	store.EXPECT().Maintenances(ctx, testNow).Return([]models.Maintenance{window}, nil).Once()
+	_, on, err = m.Check(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, on)
+}
+
+func TestDisable(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		appID   int
+		setup   func(apps *mocks.AppProvider, store *mocks.Store, auditor *mocks.Auditor)
+		wantErr error
+	}{
+		{
+			name:  "disabled",
+			appID: 1,
+			setup: func(apps *mocks.AppProvider, store *mocks.Store, auditor *mocks.Auditor) {
+				apps.EXPECT().App(ctx, 1).Return(models.App{ID: 1, Name: "test"}, nil).Once()
+				store.EXPECT().DeleteMaintenance(ctx, 1).Return(nil).Once()
+				auditor.EXPECT().Record(ctx, models.AuditEvent{
+					Action:  models.AuditActionMaintenanceChanged,
+					Subject: "test",
+					AppID:   1,
+					Payload: map[string]string{"enabled": "false", "source": "admin_api"},
+				}).Once()
+			},
+		},
+		{
+			name: "no window",
+			setup: func(_ *mocks.AppProvider, store *mocks.Store, _ *mocks.Auditor) {
+				store.EXPECT().DeleteMaintenance(ctx, 0).Return(storage.ErrMaintenanceNotFound).Once()
+			},
+		},
+		{
+			name: "storage failure",
+			setup: func(_ *mocks.AppProvider, store *mocks.Store, _ *mocks.Auditor) {
+				store.EXPECT().DeleteMaintenance(ctx, 0).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apps, store, auditor := mocks.NewAppProvider(t), mocks.NewStore(t), mocks.NewAuditor(t)
+			tt.setup(apps, store, auditor)
+
+			m := New(testLog, store, apps, auditor, clock.NewFake(testNow), time.Minute, testRetryAfter, Settings{})
+
+			assert.ErrorIs(t, m.Disable(ctx, tt.appID), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "grpc-service-ref/internal/domain/models"
+
+	time "time"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+type Store_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Store) EXPECT() *Store_Expecter {
+	return &Store_Expecter{mock: &_m.Mock}
+}
+
+// DeleteMaintenance provides a mock function with given fields: ctx, appID
+func (_m *Store) DeleteMaintenance(ctx context.Context, appID int) error {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMaintenance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_DeleteMaintenance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMaintenance'
+type Store_DeleteMaintenance_Call struct {
+	*mock.Call
+}
+
+// DeleteMaintenance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *Store_Expecter) DeleteMaintenance(ctx interface{}, appID interface{}) *Store_DeleteMaintenance_Call {
+	return &Store_DeleteMaintenance_Call{Call: _e.mock.On("DeleteMaintenance", ctx, appID)}
+}
+
+func (_c *Store_DeleteMaintenance_Call) Run(run func(ctx context.Context, appID int)) *Store_DeleteMaintenance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Store_DeleteMaintenance_Call) Return(_a0 error) *Store_DeleteMaintenance_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_DeleteMaintenance_Call) RunAndReturn(run func(context.Context, int) error) *Store_DeleteMaintenance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Maintenances provides a mock function with given fields: ctx, at
+func (_m *Store) Maintenances(ctx context.Context, at time.Time) ([]models.Maintenance, error) {
+	ret := _m.Called(ctx, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Maintenances")
+	}
+
+	var r0 []models.Maintenance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]models.Maintenance, error)); ok {
+		return rf(ctx, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []models.Maintenance); ok {
+		r0 = rf(ctx, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Maintenance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_Maintenances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Maintenances'
+type Store_Maintenances_Call struct {
+	*mock.Call
+}
+
+// Maintenances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - at time.Time
+func (_e *Store_Expecter) Maintenances(ctx interface{}, at interface{}) *Store_Maintenances_Call {
+	return &Store_Maintenances_Call{Call: _e.mock.On("Maintenances", ctx, at)}
+}
+
+func (_c *Store_Maintenances_Call) Run(run func(ctx context.Context, at time.Time)) *Store_Maintenances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Store_Maintenances_Call) Return(_a0 []models.Maintenance, _a1 error) *Store_Maintenances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_Maintenances_Call) RunAndReturn(run func(context.Context, time.Time) ([]models.Maintenance, error)) *Store_Maintenances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveMaintenance provides a mock function with given fields: ctx, m
+func (_m *Store) SaveMaintenance(ctx context.Context, m models.Maintenance) error {
+	ret := _m.Called(ctx, m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMaintenance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Maintenance) error); ok {
+		r0 = rf(ctx, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_SaveMaintenance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveMaintenance'
+type Store_SaveMaintenance_Call struct {
+	*mock.Call
+}
+
+// SaveMaintenance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - m models.Maintenance
+func (_e *Store_Expecter) SaveMaintenance(ctx interface{}, m interface{}) *Store_SaveMaintenance_Call {
+	return &Store_SaveMaintenance_Call{Call: _e.mock.On("SaveMaintenance", ctx, m)}
+}
+
+func (_c *Store_SaveMaintenance_Call) Run(run func(ctx context.Context, m models.Maintenance)) *Store_SaveMaintenance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Maintenance))
+	})
+	return _c
+}
+
+func (_c *Store_SaveMaintenance_Call) Return(_a0 error) *Store_SaveMaintenance_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_SaveMaintenance_Call) RunAndReturn(run func(context.Context, models.Maintenance) error) *Store_SaveMaintenance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
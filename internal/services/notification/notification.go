@@ -0,0 +1,323 @@
+package notification
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/services/mail"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// templates are plain text email bodies, keyed by file name.
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// Types of emails, they are names of their templates without extension.
+const (
+	EmailPasswordChanged          = "password_changed"
+	EmailNewSignIn                = "new_sign_in"
+	EmailSignInBlocked            = "sign_in_blocked"
+	EmailAccountDeletionScheduled = "account_deletion_scheduled"
+	EmailOrganizationInvitation   = "organization_invitation"
+)
+
+// subjects are subjects of emails which don't depend on data, see invitationSubject.
+var subjects = map[string]string{
+	EmailPasswordChanged:          "Your password was changed",
+	EmailNewSignIn:                "New sign-in to your account",
+	EmailSignInBlocked:            "Suspicious sign-in to your account was blocked",
+	EmailAccountDeletionScheduled: "Your account will be deleted",
+}
+
+// categories are categories of emails users may opt out of, emails without category are essential.
+var categories = map[string]models.EmailCategory{
+	EmailPasswordChanged: models.EmailCategorySecurity,
+	EmailNewSignIn:       models.EmailCategorySecurity,
+	EmailSignInBlocked:   models.EmailCategorySecurity,
+}
+
+var (
+	ErrUnknownEmail  = errors.New("unknown email type")
+	ErrInvalidSample = errors.New("invalid sample data")
+)
+
+// Data of templates, fields are keys of sample data of Preview.
+type (
+	passwordChangedData struct {
+		Reset     bool
+		SignedOut bool
+		Time      time.Time
+		IP        string
+	}
+
+	deviceData struct {
+		Time      time.Time
+		UserAgent string
+		IP        string
+		Country   string
+		City      string
+	}
+
+	deletionScheduledData struct {
+		ScheduledAt time.Time
+		IP          string
+	}
+
+	invitationData struct {
+		Organization string
+		Inviter      string
+		Role         models.OrgRole
+		Code         string
+		ExpiresAt    time.Time
+	}
+)
+
+type EmailSender interface {
+	SendEmail(
+		ctx context.Context,
+		subject string,
+		to []string,
+		content string,
+		cc []string,
+		bcc []string,
+		atachFiles []string,
+	) (messageID string, err error)
+	SendNotification(
+		ctx context.Context,
+		category models.EmailCategory,
+		subject string,
+		to []string,
+		content string,
+	) (messageID string, err error)
+}
+
+// Notifier emails users about security-relevant changes of their accounts.
+//
+// Failing to send is logged, but doesn't fail the change the user is notified about.
+// Notifications of a category, see categories, are not sent to users who opted out of it.
+type Notifier struct {
+	log    *slog.Logger
+	mailer EmailSender
+}
+
+func New(log *slog.Logger, mailer EmailSender) *Notifier {
+	return &Notifier{
+		log:    log,
+		mailer: mailer,
+	}
+}
+
+// PasswordChanged notifies the user that password was changed, or reset by code if reset is true.
+// signedOut tells the user that other devices were signed out.
+func (n *Notifier) PasswordChanged(ctx context.Context, email string, reset bool, signedOut bool) {
+	const op = "Notifier.PasswordChanged"
+
+	n.send(ctx, op, email, subjects[EmailPasswordChanged], EmailPasswordChanged, passwordChangedData{
+		Reset:     reset,
+		SignedOut: signedOut,
+		Time:      time.Now().UTC(),
+		IP:        peer.IP(ctx),
+	})
+}
+
+// NewSignIn notifies the user of sign-in from a new device or location.
+func (n *Notifier) NewSignIn(ctx context.Context, email string, device models.KnownDevice) {
+	const op = "Notifier.NewSignIn"
+
+	n.send(ctx, op, email, subjects[EmailNewSignIn], EmailNewSignIn, deviceData{
+		Time:      device.LastSeenAt,
+		UserAgent: device.UserAgent,
+		IP:        device.IP,
+		Country:   device.Country,
+		City:      device.City,
+	})
+}
+
+// SignInBlocked notifies the user of sign-in blocked as suspicious, the password was right,
+// so the user should change it unless it was the user.
+func (n *Notifier) SignInBlocked(ctx context.Context, email string, device models.KnownDevice) {
+	const op = "Notifier.SignInBlocked"
+
+	n.send(ctx, op, email, subjects[EmailSignInBlocked], EmailSignInBlocked, deviceData{
+		Time:      device.LastSeenAt,
+		UserAgent: device.UserAgent,
+		IP:        device.IP,
+		Country:   device.Country,
+		City:      device.City,
+	})
+}
+
+// AccountDeletionScheduled notifies the user that the account is deleted at the time unless the user signs in before.
+func (n *Notifier) AccountDeletionScheduled(ctx context.Context, email string, at time.Time) {
+	const op = "Notifier.AccountDeletionScheduled"
+
+	n.send(ctx, op, email, subjects[EmailAccountDeletionScheduled], EmailAccountDeletionScheduled, deletionScheduledData{
+		ScheduledAt: at,
+		IP:          peer.IP(ctx),
+	})
+}
+
+// OrganizationInvitation emails the code of invitation to the organization.
+// Unlike notifications, invitation is useless if it's not delivered, so failing to send is returned.
+func (n *Notifier) OrganizationInvitation(
+	ctx context.Context,
+	email string,
+	invitation models.OrganizationInvitation,
+	orgName string,
+	inviter string,
+) error {
+	const op = "Notifier.OrganizationInvitation"
+
+	content, err := render(EmailOrganizationInvitation, invitationData{
+		Organization: orgName,
+		Inviter:      inviter,
+		Role:         invitation.Role,
+		Code:         invitation.Code,
+		ExpiresAt:    invitation.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := n.mailer.SendEmail(ctx, invitationSubject(orgName), []string{email}, content, []string{}, []string{}, []string{}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Preview renders subject and body of the email of the type without sending it. Sample data overrides
+// example values of fields of the template, keyed by field name, e.g. "IP"; times are RFC 3339.
+func (n *Notifier) Preview(ctx context.Context, emailType string, sample map[string]string) (subject string, body string, err error) {
+	const op = "Notifier.Preview"
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	var data any
+	switch emailType {
+	case EmailPasswordChanged:
+		data = &passwordChangedData{SignedOut: true, Time: now, IP: "203.0.113.7"}
+	case EmailNewSignIn, EmailSignInBlocked:
+		data = &deviceData{Time: now, UserAgent: "Mozilla/5.0 (Macintosh)", IP: "203.0.113.7", Country: "NL", City: "Amsterdam"}
+	case EmailAccountDeletionScheduled:
+		data = &deletionScheduledData{ScheduledAt: now.Add(30 * 24 * time.Hour), IP: "203.0.113.7"}
+	case EmailOrganizationInvitation:
+		data = &invitationData{
+			Organization: "Example",
+			Inviter:      "owner@example.com",
+			Role:         models.OrgRoleMember,
+			Code:         "123456",
+			ExpiresAt:    now.Add(7 * 24 * time.Hour),
+		}
+	default:
+		return "", "", fmt.Errorf("%s: %w", op, ErrUnknownEmail)
+	}
+
+	if err := applySample(data, sample); err != nil {
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	body, err = render(emailType, data)
+	if err != nil {
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	subject = subjects[emailType]
+	if d, ok := data.(*invitationData); ok {
+		subject = invitationSubject(d.Organization)
+	}
+
+	return subject, body, nil
+}
+
+func (n *Notifier) send(ctx context.Context, op string, email string, subject string, emailType string, data any) {
+	log := n.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	content, err := render(emailType, data)
+	if err != nil {
+		log.Error("failed to render notification", sl.Err(err))
+
+		return
+	}
+
+	if category, ok := categories[emailType]; ok {
+		_, err = n.mailer.SendNotification(ctx, category, subject, []string{email}, content)
+	} else {
+		_, err = n.mailer.SendEmail(ctx, subject, []string{email}, content, []string{}, []string{}, []string{})
+	}
+	if err != nil {
+		if errors.Is(err, mail.ErrRecipientsOptedOut) {
+			log.Info("notification is not sent, user opted out")
+
+			return
+		}
+
+		log.Error("failed to send notification", sl.Err(err))
+
+		return
+	}
+
+	log.Info("notification sent")
+}
+
+// render renders body of the email of the type.
+func render(emailType string, data any) (string, error) {
+	var content strings.Builder
+	if err := templates.ExecuteTemplate(&content, emailType+".tmpl", data); err != nil {
+		return "", err
+	}
+
+	return content.String(), nil
+}
+
+func invitationSubject(orgName string) string {
+	return "You are invited to join " + orgName
+}
+
+// applySample sets fields of data, a pointer to template data, to sample values keyed by field name.
+func applySample(data any, sample map[string]string) error {
+	v := reflect.ValueOf(data).Elem()
+
+	for name, value := range sample {
+		field := v.FieldByName(name)
+		if !field.IsValid() {
+			return fmt.Errorf("%w: unknown field %s", ErrInvalidSample, name)
+		}
+
+		switch field.Interface().(type) {
+		case time.Time:
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return fmt.Errorf("%w: %s is not RFC 3339 time", ErrInvalidSample, name)
+			}
+
+			field.Set(reflect.ValueOf(t.UTC()))
+		case bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%w: %s is not bool", ErrInvalidSample, name)
+			}
+
+			field.SetBool(b)
+		default:
+			field.SetString(value)
+		}
+	}
+
+	return nil
+}
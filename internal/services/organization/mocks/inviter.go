@@ -0,0 +1,87 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Inviter is an autogenerated mock type for the Inviter type
+type Inviter struct {
+	mock.Mock
+}
+
+type Inviter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Inviter) EXPECT() *Inviter_Expecter {
+	return &Inviter_Expecter{mock: &_m.Mock}
+}
+
+// OrganizationInvitation provides a mock function with given fields: ctx, email, invitation, orgName, inviter
+func (_m *Inviter) OrganizationInvitation(ctx context.Context, email string, invitation models.OrganizationInvitation, orgName string, inviter string) error {
+	ret := _m.Called(ctx, email, invitation, orgName, inviter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrganizationInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.OrganizationInvitation, string, string) error); ok {
+		r0 = rf(ctx, email, invitation, orgName, inviter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Inviter_OrganizationInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OrganizationInvitation'
+type Inviter_OrganizationInvitation_Call struct {
+	*mock.Call
+}
+
+// OrganizationInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - invitation models.OrganizationInvitation
+//   - orgName string
+//   - inviter string
+func (_e *Inviter_Expecter) OrganizationInvitation(ctx interface{}, email interface{}, invitation interface{}, orgName interface{}, inviter interface{}) *Inviter_OrganizationInvitation_Call {
+	return &Inviter_OrganizationInvitation_Call{Call: _e.mock.On("OrganizationInvitation", ctx, email, invitation, orgName, inviter)}
+}
+
+func (_c *Inviter_OrganizationInvitation_Call) Run(run func(ctx context.Context, email string, invitation models.OrganizationInvitation, orgName string, inviter string)) *Inviter_OrganizationInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.OrganizationInvitation), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *Inviter_OrganizationInvitation_Call) Return(_a0 error) *Inviter_OrganizationInvitation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Inviter_OrganizationInvitation_Call) RunAndReturn(run func(context.Context, string, models.OrganizationInvitation, string, string) error) *Inviter_OrganizationInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewInviter creates a new instance of Inviter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInviter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Inviter {
+	mock := &Inviter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
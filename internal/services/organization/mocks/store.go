@@ -0,0 +1,435 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+type Store_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Store) EXPECT() *Store_Expecter {
+	return &Store_Expecter{mock: &_m.Mock}
+}
+
+// AcceptOrganizationInvitation provides a mock function with given fields: ctx, invitationID, userID, at
+func (_m *Store) AcceptOrganizationInvitation(ctx context.Context, invitationID int64, userID int64, at time.Time) error {
+	ret := _m.Called(ctx, invitationID, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptOrganizationInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, time.Time) error); ok {
+		r0 = rf(ctx, invitationID, userID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_AcceptOrganizationInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AcceptOrganizationInvitation'
+type Store_AcceptOrganizationInvitation_Call struct {
+	*mock.Call
+}
+
+// AcceptOrganizationInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - invitationID int64
+//   - userID int64
+//   - at time.Time
+func (_e *Store_Expecter) AcceptOrganizationInvitation(ctx interface{}, invitationID interface{}, userID interface{}, at interface{}) *Store_AcceptOrganizationInvitation_Call {
+	return &Store_AcceptOrganizationInvitation_Call{Call: _e.mock.On("AcceptOrganizationInvitation", ctx, invitationID, userID, at)}
+}
+
+func (_c *Store_AcceptOrganizationInvitation_Call) Run(run func(ctx context.Context, invitationID int64, userID int64, at time.Time)) *Store_AcceptOrganizationInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Store_AcceptOrganizationInvitation_Call) Return(_a0 error) *Store_AcceptOrganizationInvitation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_AcceptOrganizationInvitation_Call) RunAndReturn(run func(context.Context, int64, int64, time.Time) error) *Store_AcceptOrganizationInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Memberships provides a mock function with given fields: ctx, userID
+func (_m *Store) Memberships(ctx context.Context, userID int64) ([]models.OrganizationMember, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Memberships")
+	}
+
+	var r0 []models.OrganizationMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.OrganizationMember, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.OrganizationMember); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OrganizationMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_Memberships_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Memberships'
+type Store_Memberships_Call struct {
+	*mock.Call
+}
+
+// Memberships is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *Store_Expecter) Memberships(ctx interface{}, userID interface{}) *Store_Memberships_Call {
+	return &Store_Memberships_Call{Call: _e.mock.On("Memberships", ctx, userID)}
+}
+
+func (_c *Store_Memberships_Call) Run(run func(ctx context.Context, userID int64)) *Store_Memberships_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Store_Memberships_Call) Return(_a0 []models.OrganizationMember, _a1 error) *Store_Memberships_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_Memberships_Call) RunAndReturn(run func(context.Context, int64) ([]models.OrganizationMember, error)) *Store_Memberships_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Organization provides a mock function with given fields: ctx, id
+func (_m *Store) Organization(ctx context.Context, id int64) (models.Organization, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Organization")
+	}
+
+	var r0 models.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Organization, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Organization); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Organization)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_Organization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Organization'
+type Store_Organization_Call struct {
+	*mock.Call
+}
+
+// Organization is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Store_Expecter) Organization(ctx interface{}, id interface{}) *Store_Organization_Call {
+	return &Store_Organization_Call{Call: _e.mock.On("Organization", ctx, id)}
+}
+
+func (_c *Store_Organization_Call) Run(run func(ctx context.Context, id int64)) *Store_Organization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Store_Organization_Call) Return(_a0 models.Organization, _a1 error) *Store_Organization_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_Organization_Call) RunAndReturn(run func(context.Context, int64) (models.Organization, error)) *Store_Organization_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OrganizationInvitation provides a mock function with given fields: ctx, orgID, email
+func (_m *Store) OrganizationInvitation(ctx context.Context, orgID int64, email string) (models.OrganizationInvitation, error) {
+	ret := _m.Called(ctx, orgID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrganizationInvitation")
+	}
+
+	var r0 models.OrganizationInvitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (models.OrganizationInvitation, error)); ok {
+		return rf(ctx, orgID, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) models.OrganizationInvitation); ok {
+		r0 = rf(ctx, orgID, email)
+	} else {
+		r0 = ret.Get(0).(models.OrganizationInvitation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, orgID, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_OrganizationInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OrganizationInvitation'
+type Store_OrganizationInvitation_Call struct {
+	*mock.Call
+}
+
+// OrganizationInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orgID int64
+//   - email string
+func (_e *Store_Expecter) OrganizationInvitation(ctx interface{}, orgID interface{}, email interface{}) *Store_OrganizationInvitation_Call {
+	return &Store_OrganizationInvitation_Call{Call: _e.mock.On("OrganizationInvitation", ctx, orgID, email)}
+}
+
+func (_c *Store_OrganizationInvitation_Call) Run(run func(ctx context.Context, orgID int64, email string)) *Store_OrganizationInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Store_OrganizationInvitation_Call) Return(_a0 models.OrganizationInvitation, _a1 error) *Store_OrganizationInvitation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_OrganizationInvitation_Call) RunAndReturn(run func(context.Context, int64, string) (models.OrganizationInvitation, error)) *Store_OrganizationInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OrganizationMember provides a mock function with given fields: ctx, orgID, userID
+func (_m *Store) OrganizationMember(ctx context.Context, orgID int64, userID int64) (models.OrganizationMember, error) {
+	ret := _m.Called(ctx, orgID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrganizationMember")
+	}
+
+	var r0 models.OrganizationMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (models.OrganizationMember, error)); ok {
+		return rf(ctx, orgID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) models.OrganizationMember); ok {
+		r0 = rf(ctx, orgID, userID)
+	} else {
+		r0 = ret.Get(0).(models.OrganizationMember)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, orgID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_OrganizationMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OrganizationMember'
+type Store_OrganizationMember_Call struct {
+	*mock.Call
+}
+
+// OrganizationMember is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orgID int64
+//   - userID int64
+func (_e *Store_Expecter) OrganizationMember(ctx interface{}, orgID interface{}, userID interface{}) *Store_OrganizationMember_Call {
+	return &Store_OrganizationMember_Call{Call: _e.mock.On("OrganizationMember", ctx, orgID, userID)}
+}
+
+func (_c *Store_OrganizationMember_Call) Run(run func(ctx context.Context, orgID int64, userID int64)) *Store_OrganizationMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Store_OrganizationMember_Call) Return(_a0 models.OrganizationMember, _a1 error) *Store_OrganizationMember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_OrganizationMember_Call) RunAndReturn(run func(context.Context, int64, int64) (models.OrganizationMember, error)) *Store_OrganizationMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveOrganization provides a mock function with given fields: ctx, org, ownerID
+func (_m *Store) SaveOrganization(ctx context.Context, org models.Organization, ownerID int64) (int64, error) {
+	ret := _m.Called(ctx, org, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOrganization")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Organization, int64) (int64, error)); ok {
+		return rf(ctx, org, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Organization, int64) int64); ok {
+		r0 = rf(ctx, org, ownerID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Organization, int64) error); ok {
+		r1 = rf(ctx, org, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_SaveOrganization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOrganization'
+type Store_SaveOrganization_Call struct {
+	*mock.Call
+}
+
+// SaveOrganization is a helper method to define mock.On call
+//   - ctx context.Context
+//   - org models.Organization
+//   - ownerID int64
+func (_e *Store_Expecter) SaveOrganization(ctx interface{}, org interface{}, ownerID interface{}) *Store_SaveOrganization_Call {
+	return &Store_SaveOrganization_Call{Call: _e.mock.On("SaveOrganization", ctx, org, ownerID)}
+}
+
+func (_c *Store_SaveOrganization_Call) Run(run func(ctx context.Context, org models.Organization, ownerID int64)) *Store_SaveOrganization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Organization), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Store_SaveOrganization_Call) Return(_a0 int64, _a1 error) *Store_SaveOrganization_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_SaveOrganization_Call) RunAndReturn(run func(context.Context, models.Organization, int64) (int64, error)) *Store_SaveOrganization_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveOrganizationInvitation provides a mock function with given fields: ctx, invitation
+func (_m *Store) SaveOrganizationInvitation(ctx context.Context, invitation models.OrganizationInvitation) (int64, error) {
+	ret := _m.Called(ctx, invitation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOrganizationInvitation")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.OrganizationInvitation) (int64, error)); ok {
+		return rf(ctx, invitation)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.OrganizationInvitation) int64); ok {
+		r0 = rf(ctx, invitation)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.OrganizationInvitation) error); ok {
+		r1 = rf(ctx, invitation)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_SaveOrganizationInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOrganizationInvitation'
+type Store_SaveOrganizationInvitation_Call struct {
+	*mock.Call
+}
+
+// SaveOrganizationInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - invitation models.OrganizationInvitation
+func (_e *Store_Expecter) SaveOrganizationInvitation(ctx interface{}, invitation interface{}) *Store_SaveOrganizationInvitation_Call {
+	return &Store_SaveOrganizationInvitation_Call{Call: _e.mock.On("SaveOrganizationInvitation", ctx, invitation)}
+}
+
+func (_c *Store_SaveOrganizationInvitation_Call) Run(run func(ctx context.Context, invitation models.OrganizationInvitation)) *Store_SaveOrganizationInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.OrganizationInvitation))
+	})
+	return _c
+}
+
+func (_c *Store_SaveOrganizationInvitation_Call) Return(_a0 int64, _a1 error) *Store_SaveOrganizationInvitation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_SaveOrganizationInvitation_Call) RunAndReturn(run func(context.Context, models.OrganizationInvitation) (int64, error)) *Store_SaveOrganizationInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,274 @@
+package organization
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/storage"
+)
+
+// Invitation codes are typed by users, so ambiguous characters like 0 and O are left out.
+const (
+	invitationCodeLen      = 10
+	invitationCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	maxNameLen             = 100
+)
+
+var (
+	ErrInvalidName = errors.New("invalid organization name")
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrNotAllowed is returned if the user is not a member or the role doesn't allow the action.
+	ErrNotAllowed = errors.New("not allowed")
+	// ErrInvalidInvitation is returned for wrong code, expired or accepted invitation.
+	ErrInvalidInvitation = errors.New("invitation is invalid or expired")
+)
+
+type Store interface {
+	SaveOrganization(ctx context.Context, org models.Organization, ownerID int64) (int64, error)
+	Organization(ctx context.Context, id int64) (models.Organization, error)
+	OrganizationMember(ctx context.Context, orgID int64, userID int64) (models.OrganizationMember, error)
+	Memberships(ctx context.Context, userID int64) ([]models.OrganizationMember, error)
+	SaveOrganizationInvitation(ctx context.Context, invitation models.OrganizationInvitation) (int64, error)
+	OrganizationInvitation(ctx context.Context, orgID int64, email string) (models.OrganizationInvitation, error)
+	AcceptOrganizationInvitation(ctx context.Context, invitationID int64, userID int64, at time.Time) error
+}
+
+// Inviter emails invitation codes.
+type Inviter interface {
+	OrganizationInvitation(ctx context.Context, email string, invitation models.OrganizationInvitation, orgName string, inviter string) error
+}
+
+// Auditor records security-relevant actions to the audit log.
+type Auditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// Organizations manages organizations users belong to and invitations to them.
+// Users act on their own behalf, i.e. by their access tokens, so userID and email come from the token.
+type Organizations struct {
+	log           *slog.Logger
+	store         Store
+	inviter       Inviter
+	auditor       Auditor
+	clock         clock.Clock
+	random        random.Randomizer
+	invitationTTL time.Duration
+}
+
+func New(
+	log *slog.Logger,
+	store Store,
+	inviter Inviter,
+	auditor Auditor,
+	clock clock.Clock,
+	random random.Randomizer,
+	invitationTTL time.Duration,
+) *Organizations {
+	return &Organizations{
+		log:           log,
+		store:         store,
+		inviter:       inviter,
+		auditor:       auditor,
+		clock:         clock,
+		random:        random,
+		invitationTTL: invitationTTL,
+	}
+}
+
+// Create creates organization owned by the user and returns its ID.
+// If organization with the name exists, returns storage.ErrOrganizationExists.
+func (o *Organizations) Create(ctx context.Context, userID int64, email string, name string) (int64, error) {
+	const op = "Organizations.Create"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLen {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidName)
+	}
+
+	orgID, err := o.store.SaveOrganization(ctx, models.Organization{Name: name, CreatedAt: o.clock.Now().UTC()}, userID)
+	if err != nil {
+		if !errors.Is(err, storage.ErrOrganizationExists) {
+			log.Error("failed to save organization", sl.Err(err))
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("organization created", slog.Int64("org_id", orgID))
+
+	o.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionOrganizationCreated,
+		ActorID: userID,
+		Subject: email,
+		Payload: map[string]string{"org_id": strconv.FormatInt(orgID, 10), "name": name},
+	})
+
+	return orgID, nil
+}
+
+// Invite emails invitation to join the organization with the role. Owners invite anyone,
+// admins invite admins and members, others get ErrNotAllowed.
+func (o *Organizations) Invite(
+	ctx context.Context,
+	userID int64,
+	email string,
+	orgID int64,
+	inviteeEmail string,
+	role models.OrgRole,
+) error {
+	const op = "Organizations.Invite"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int64("org_id", orgID),
+	)
+
+	if !role.Valid() {
+		return fmt.Errorf("%s: %w", op, ErrInvalidRole)
+	}
+
+	member, err := o.store.OrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrOrganizationMemberNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrNotAllowed)
+		}
+
+		log.Error("failed to get member", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !member.Role.CanInvite(role) {
+		log.Info("role doesn't allow to invite", slog.String("role", string(member.Role)), slog.String("invitee_role", string(role)))
+
+		return fmt.Errorf("%s: %w", op, ErrNotAllowed)
+	}
+
+	code, err := o.random.String(invitationCodeLen, invitationCodeAlphabet)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := o.clock.Now().UTC()
+	invitation := models.OrganizationInvitation{
+		OrgID:     orgID,
+		Email:     inviteeEmail,
+		Role:      role,
+		Code:      code,
+		InvitedBy: userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(o.invitationTTL),
+	}
+
+	invitation.ID, err = o.store.SaveOrganizationInvitation(ctx, invitation)
+	if err != nil {
+		log.Error("failed to save invitation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.inviter.OrganizationInvitation(ctx, inviteeEmail, invitation, member.OrgName, email); err != nil {
+		log.Error("failed to send invitation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user invited", slog.String("role", string(role)))
+
+	o.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionOrganizationInvited,
+		ActorID: userID,
+		Subject: inviteeEmail,
+		Payload: map[string]string{"org_id": strconv.FormatInt(orgID, 10), "role": string(role)},
+	})
+
+	return nil
+}
+
+// Accept adds the user to the organization by the code of invitation sent to the user's email.
+// If the user is a member already, returns storage.ErrOrganizationMemberExists.
+func (o *Organizations) Accept(ctx context.Context, userID int64, email string, orgID int64, code string) (models.OrganizationMember, error) {
+	const op = "Organizations.Accept"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int64("org_id", orgID),
+	)
+
+	invitation, err := o.store.OrganizationInvitation(ctx, orgID, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrOrganizationInvitationNotFound) {
+			return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, ErrInvalidInvitation)
+		}
+
+		log.Error("failed to get invitation", sl.Err(err))
+
+		return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := o.clock.Now().UTC()
+
+	if subtle.ConstantTimeCompare([]byte(invitation.Code), []byte(strings.ToUpper(code))) != 1 || !now.Before(invitation.ExpiresAt) {
+		log.Info("invalid invitation code")
+
+		return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, ErrInvalidInvitation)
+	}
+
+	if err := o.store.AcceptOrganizationInvitation(ctx, invitation.ID, userID, now); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrOrganizationInvitationNotFound):
+			return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, ErrInvalidInvitation)
+		case errors.Is(err, storage.ErrOrganizationMemberExists):
+			return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to accept invitation", sl.Err(err))
+
+		return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user joined organization", slog.String("role", string(invitation.Role)))
+
+	o.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionOrganizationJoined,
+		ActorID: userID,
+		Subject: email,
+		Payload: map[string]string{"org_id": strconv.FormatInt(orgID, 10), "role": string(invitation.Role)},
+	})
+
+	member, err := o.store.OrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return member, nil
+}
+
+// Memberships returns organizations the user is a member of.
+func (o *Organizations) Memberships(ctx context.Context, userID int64) ([]models.OrganizationMember, error) {
+	const op = "Organizations.Memberships"
+
+	members, err := o.store.Memberships(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
@@ -0,0 +1,159 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailSender is an autogenerated mock type for the EmailSender type
+type EmailSender struct {
+	mock.Mock
+}
+
+type EmailSender_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EmailSender) EXPECT() *EmailSender_Expecter {
+	return &EmailSender_Expecter{mock: &_m.Mock}
+}
+
+// SendEmail provides a mock function with given fields: ctx, subject, to, content, cc, bcc, atachFiles
+func (_m *EmailSender) SendEmail(ctx context.Context, subject string, to []string, content string, cc []string, bcc []string, atachFiles []string) (string, error) {
+	ret := _m.Called(ctx, subject, to, content, cc, bcc, atachFiles)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendEmail")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, string, []string, []string, []string) (string, error)); ok {
+		return rf(ctx, subject, to, content, cc, bcc, atachFiles)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, string, []string, []string, []string) string); ok {
+		r0 = rf(ctx, subject, to, content, cc, bcc, atachFiles)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, string, []string, []string, []string) error); ok {
+		r1 = rf(ctx, subject, to, content, cc, bcc, atachFiles)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EmailSender_SendEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendEmail'
+type EmailSender_SendEmail_Call struct {
+	*mock.Call
+}
+
+// SendEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subject string
+//   - to []string
+//   - content string
+//   - cc []string
+//   - bcc []string
+//   - atachFiles []string
+func (_e *EmailSender_Expecter) SendEmail(ctx interface{}, subject interface{}, to interface{}, content interface{}, cc interface{}, bcc interface{}, atachFiles interface{}) *EmailSender_SendEmail_Call {
+	return &EmailSender_SendEmail_Call{Call: _e.mock.On("SendEmail", ctx, subject, to, content, cc, bcc, atachFiles)}
+}
+
+func (_c *EmailSender_SendEmail_Call) Run(run func(ctx context.Context, subject string, to []string, content string, cc []string, bcc []string, atachFiles []string)) *EmailSender_SendEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].(string), args[4].([]string), args[5].([]string), args[6].([]string))
+	})
+	return _c
+}
+
+func (_c *EmailSender_SendEmail_Call) Return(messageID string, err error) *EmailSender_SendEmail_Call {
+	_c.Call.Return(messageID, err)
+	return _c
+}
+
+func (_c *EmailSender_SendEmail_Call) RunAndReturn(run func(context.Context, string, []string, string, []string, []string, []string) (string, error)) *EmailSender_SendEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendNotification provides a mock function with given fields: ctx, category, subject, to, content
+func (_m *EmailSender) SendNotification(ctx context.Context, category models.EmailCategory, subject string, to []string, content string) (string, error) {
+	ret := _m.Called(ctx, category, subject, to, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendNotification")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.EmailCategory, string, []string, string) (string, error)); ok {
+		return rf(ctx, category, subject, to, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.EmailCategory, string, []string, string) string); ok {
+		r0 = rf(ctx, category, subject, to, content)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.EmailCategory, string, []string, string) error); ok {
+		r1 = rf(ctx, category, subject, to, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EmailSender_SendNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendNotification'
+type EmailSender_SendNotification_Call struct {
+	*mock.Call
+}
+
+// SendNotification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - category models.EmailCategory
+//   - subject string
+//   - to []string
+//   - content string
+func (_e *EmailSender_Expecter) SendNotification(ctx interface{}, category interface{}, subject interface{}, to interface{}, content interface{}) *EmailSender_SendNotification_Call {
+	return &EmailSender_SendNotification_Call{Call: _e.mock.On("SendNotification", ctx, category, subject, to, content)}
+}
+
+func (_c *EmailSender_SendNotification_Call) Run(run func(ctx context.Context, category models.EmailCategory, subject string, to []string, content string)) *EmailSender_SendNotification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.EmailCategory), args[2].(string), args[3].([]string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *EmailSender_SendNotification_Call) Return(messageID string, err error) *EmailSender_SendNotification_Call {
+	_c.Call.Return(messageID, err)
+	return _c
+}
+
+func (_c *EmailSender_SendNotification_Call) RunAndReturn(run func(context.Context, models.EmailCategory, string, []string, string) (string, error)) *EmailSender_SendNotification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEmailSender creates a new instance of EmailSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailSender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailSender {
+	mock := &EmailSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PreferencesProvider is an autogenerated mock type for the PreferencesProvider type
+type PreferencesProvider struct {
+	mock.Mock
+}
+
+type PreferencesProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PreferencesProvider) EXPECT() *PreferencesProvider_Expecter {
+	return &PreferencesProvider_Expecter{mock: &_m.Mock}
+}
+
+// NotificationPreferences provides a mock function with given fields: ctx, email
+func (_m *PreferencesProvider) NotificationPreferences(ctx context.Context, email string) (models.NotificationPreferences, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotificationPreferences")
+	}
+
+	var r0 models.NotificationPreferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.NotificationPreferences, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.NotificationPreferences); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(models.NotificationPreferences)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PreferencesProvider_NotificationPreferences_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotificationPreferences'
+type PreferencesProvider_NotificationPreferences_Call struct {
+	*mock.Call
+}
+
+// NotificationPreferences is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *PreferencesProvider_Expecter) NotificationPreferences(ctx interface{}, email interface{}) *PreferencesProvider_NotificationPreferences_Call {
+	return &PreferencesProvider_NotificationPreferences_Call{Call: _e.mock.On("NotificationPreferences", ctx, email)}
+}
+
+func (_c *PreferencesProvider_NotificationPreferences_Call) Run(run func(ctx context.Context, email string)) *PreferencesProvider_NotificationPreferences_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *PreferencesProvider_NotificationPreferences_Call) Return(_a0 models.NotificationPreferences, _a1 error) *PreferencesProvider_NotificationPreferences_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PreferencesProvider_NotificationPreferences_Call) RunAndReturn(run func(context.Context, string) (models.NotificationPreferences, error)) *PreferencesProvider_NotificationPreferences_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPreferencesProvider creates a new instance of PreferencesProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPreferencesProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PreferencesProvider {
+	mock := &PreferencesProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+type Store_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Store) EXPECT() *Store_Expecter {
+	return &Store_Expecter{mock: &_m.Mock}
+}
+
+// SaveVerificationReminder provides a mock function with given fields: ctx, userID, at
+func (_m *Store) SaveVerificationReminder(ctx context.Context, userID int64, at time.Time) error {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveVerificationReminder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_SaveVerificationReminder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveVerificationReminder'
+type Store_SaveVerificationReminder_Call struct {
+	*mock.Call
+}
+
+// SaveVerificationReminder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - at time.Time
+func (_e *Store_Expecter) SaveVerificationReminder(ctx interface{}, userID interface{}, at interface{}) *Store_SaveVerificationReminder_Call {
+	return &Store_SaveVerificationReminder_Call{Call: _e.mock.On("SaveVerificationReminder", ctx, userID, at)}
+}
+
+func (_c *Store_SaveVerificationReminder_Call) Run(run func(ctx context.Context, userID int64, at time.Time)) *Store_SaveVerificationReminder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Store_SaveVerificationReminder_Call) Return(_a0 error) *Store_SaveVerificationReminder_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_SaveVerificationReminder_Call) RunAndReturn(run func(context.Context, int64, time.Time) error) *Store_SaveVerificationReminder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UsersToRemind provides a mock function with given fields: ctx, registeredBefore, remindedBefore, maxReminders, limit
+func (_m *Store) UsersToRemind(ctx context.Context, registeredBefore time.Time, remindedBefore time.Time, maxReminders int, limit int) ([]models.User, error) {
+	ret := _m.Called(ctx, registeredBefore, remindedBefore, maxReminders, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UsersToRemind")
+	}
+
+	var r0 []models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int, int) ([]models.User, error)); ok {
+		return rf(ctx, registeredBefore, remindedBefore, maxReminders, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int, int) []models.User); ok {
+		r0 = rf(ctx, registeredBefore, remindedBefore, maxReminders, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int, int) error); ok {
+		r1 = rf(ctx, registeredBefore, remindedBefore, maxReminders, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_UsersToRemind_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UsersToRemind'
+type Store_UsersToRemind_Call struct {
+	*mock.Call
+}
+
+// UsersToRemind is a helper method to define mock.On call
+//   - ctx context.Context
+//   - registeredBefore time.Time
+//   - remindedBefore time.Time
+//   - maxReminders int
+//   - limit int
+func (_e *Store_Expecter) UsersToRemind(ctx interface{}, registeredBefore interface{}, remindedBefore interface{}, maxReminders interface{}, limit interface{}) *Store_UsersToRemind_Call {
+	return &Store_UsersToRemind_Call{Call: _e.mock.On("UsersToRemind", ctx, registeredBefore, remindedBefore, maxReminders, limit)}
+}
+
+func (_c *Store_UsersToRemind_Call) Run(run func(ctx context.Context, registeredBefore time.Time, remindedBefore time.Time, maxReminders int, limit int)) *Store_UsersToRemind_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *Store_UsersToRemind_Call) Return(_a0 []models.User, _a1 error) *Store_UsersToRemind_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_UsersToRemind_Call) RunAndReturn(run func(context.Context, time.Time, time.Time, int, int) ([]models.User, error)) *Store_UsersToRemind_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
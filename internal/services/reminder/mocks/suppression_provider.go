@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SuppressionProvider is an autogenerated mock type for the SuppressionProvider type
+type SuppressionProvider struct {
+	mock.Mock
+}
+
+type SuppressionProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SuppressionProvider) EXPECT() *SuppressionProvider_Expecter {
+	return &SuppressionProvider_Expecter{mock: &_m.Mock}
+}
+
+// Suppression provides a mock function with given fields: ctx, email
+func (_m *SuppressionProvider) Suppression(ctx context.Context, email string) (models.Suppression, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Suppression")
+	}
+
+	var r0 models.Suppression
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.Suppression, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.Suppression); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(models.Suppression)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuppressionProvider_Suppression_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Suppression'
+type SuppressionProvider_Suppression_Call struct {
+	*mock.Call
+}
+
+// Suppression is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *SuppressionProvider_Expecter) Suppression(ctx interface{}, email interface{}) *SuppressionProvider_Suppression_Call {
+	return &SuppressionProvider_Suppression_Call{Call: _e.mock.On("Suppression", ctx, email)}
+}
+
+func (_c *SuppressionProvider_Suppression_Call) Run(run func(ctx context.Context, email string)) *SuppressionProvider_Suppression_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *SuppressionProvider_Suppression_Call) Return(_a0 models.Suppression, _a1 error) *SuppressionProvider_Suppression_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SuppressionProvider_Suppression_Call) RunAndReturn(run func(context.Context, string) (models.Suppression, error)) *SuppressionProvider_Suppression_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSuppressionProvider creates a new instance of SuppressionProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSuppressionProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SuppressionProvider {
+	mock := &SuppressionProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserProvider is an autogenerated mock type for the UserProvider type
+type UserProvider struct {
+	mock.Mock
+}
+
+type UserProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserProvider) EXPECT() *UserProvider_Expecter {
+	return &UserProvider_Expecter{mock: &_m.Mock}
+}
+
+// UserByID provides a mock function with given fields: ctx, id
+func (_m *UserProvider) UserByID(ctx context.Context, id int64) (models.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserByID")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserProvider_UserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UserByID'
+type UserProvider_UserByID_Call struct {
+	*mock.Call
+}
+
+// UserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *UserProvider_Expecter) UserByID(ctx interface{}, id interface{}) *UserProvider_UserByID_Call {
+	return &UserProvider_UserByID_Call{Call: _e.mock.On("UserByID", ctx, id)}
+}
+
+func (_c *UserProvider_UserByID_Call) Run(run func(ctx context.Context, id int64)) *UserProvider_UserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *UserProvider_UserByID_Call) Return(_a0 models.User, _a1 error) *UserProvider_UserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserProvider_UserByID_Call) RunAndReturn(run func(context.Context, int64) (models.User, error)) *UserProvider_UserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserProvider creates a new instance of UserProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserProvider {
+	mock := &UserProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,314 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/storage"
+)
+
+// batchSize is how many users are reminded by a single run of Remind.
+const batchSize = 100
+
+var (
+	ErrAlreadyVerified = errors.New("user email is already verified")
+	// ErrCooldown is returned by Resend while verification emails to the user are rate limited.
+	ErrCooldown = errors.New("verification was sent recently")
+	// ErrSuppressed is returned by Resend for emails in the suppression list, which bounce or complained.
+	ErrSuppressed = errors.New("email is suppressed")
+)
+
+type Store interface {
+	UsersToRemind(
+		ctx context.Context,
+		registeredBefore time.Time,
+		remindedBefore time.Time,
+		maxReminders int,
+		limit int,
+	) ([]models.User, error)
+	SaveVerificationReminder(ctx context.Context, userID int64, at time.Time) error
+}
+
+type UserProvider interface {
+	UserByID(ctx context.Context, id int64) (models.User, error)
+}
+
+type SuppressionProvider interface {
+	Suppression(ctx context.Context, email string) (models.Suppression, error)
+}
+
+type PreferencesProvider interface {
+	NotificationPreferences(ctx context.Context, email string) (models.NotificationPreferences, error)
+}
+
+type Verifier interface {
+	StoreVerification(
+		ctx context.Context,
+		email string,
+		vType models.VerificationType,
+		code string,
+		expiresAt time.Time,
+	) (models.VerificationData, error)
+	Delivered(ctx context.Context, vType models.VerificationType)
+}
+
+type EmailSender interface {
+	SendEmail(
+		ctx context.Context,
+		subject string,
+		to []string,
+		content string,
+		cc []string,
+		bcc []string,
+		atachFiles []string,
+	) (messageID string, err error)
+	SendNotification(
+		ctx context.Context,
+		category models.EmailCategory,
+		subject string,
+		to []string,
+		content string,
+	) (messageID string, err error)
+}
+
+type CodeFormats interface {
+	For(vType models.VerificationType) verification.CodeFormat
+}
+
+// Limiter rate limits verification emails per email, it's shared with CreateVerification.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// Auditor records security-relevant actions to the audit log.
+type Auditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// Reminders remind users who didn't verify their email after registration to do so,
+// every reminder has a fresh registration verification code.
+type Reminders struct {
+	log          *slog.Logger
+	store        Store
+	users        UserProvider
+	suppressions SuppressionProvider
+	preferences  PreferencesProvider
+	verifier     Verifier
+	mailer       EmailSender
+	auditor      Auditor
+	codeFormats  CodeFormats
+	cooldown     Limiter
+	clock        clock.Clock
+	random       random.Randomizer
+	// after is how long after registration the first reminder is sent.
+	after time.Duration
+	// interval is the least time between reminders to the same user.
+	interval     time.Duration
+	maxReminders int
+}
+
+func New(
+	log *slog.Logger,
+	store Store,
+	users UserProvider,
+	suppressions SuppressionProvider,
+	preferences PreferencesProvider,
+	verifier Verifier,
+	mailer EmailSender,
+	auditor Auditor,
+	codeFormats CodeFormats,
+	cooldown Limiter,
+	clock clock.Clock,
+	random random.Randomizer,
+	after time.Duration,
+	interval time.Duration,
+	maxReminders int,
+) *Reminders {
+	return &Reminders{
+		log:          log,
+		store:        store,
+		users:        users,
+		suppressions: suppressions,
+		preferences:  preferences,
+		verifier:     verifier,
+		mailer:       mailer,
+		auditor:      auditor,
+		codeFormats:  codeFormats,
+		cooldown:     cooldown,
+		clock:        clock,
+		random:       random,
+		after:        after,
+		interval:     interval,
+		maxReminders: maxReminders,
+	}
+}
+
+// Remind emails reminders to unverified users due for one, it's run as a scheduled job.
+// Users in the suppression list or opted out of reminders are not emailed, but the reminder is counted,
+// so they aren't selected again on every run.
+func (r *Reminders) Remind(ctx context.Context) error {
+	const op = "Reminders.Remind"
+
+	log := r.log.With(slog.String("op", op))
+
+	if r.maxReminders == 0 {
+		return nil
+	}
+
+	now := r.clock.Now().UTC()
+
+	users, err := r.store.UsersToRemind(ctx, now.Add(-r.after), now.Add(-r.interval), r.maxReminders, batchSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var sent int
+	for _, user := range users {
+		ok, err := r.remind(ctx, user.Email, models.EmailCategoryReminders)
+		if err != nil {
+			log.Error("failed to remind user", slog.Int64("user_id", user.ID), sl.Err(err))
+
+			continue
+		}
+		if ok {
+			sent++
+		}
+
+		if err := r.store.SaveVerificationReminder(ctx, user.ID, now); err != nil {
+			log.Error("failed to save reminder", slog.Int64("user_id", user.ID), sl.Err(err))
+		}
+	}
+
+	log.Info("verification reminders sent", slog.Int("count", sent))
+
+	return nil
+}
+
+// Resend emails fresh registration verification code to the unverified user on behalf of support staff,
+// the code the user may have is replaced. Emails to the user are rate limited the same way as
+// CreateVerification, force skips the limit. The resend is audited with common name of the operator's
+// client certificate, if mTLS is enabled. Resend is on behalf of the user, so it's sent even if
+// the user opted out of reminders.
+func (r *Reminders) Resend(ctx context.Context, userID int64, force bool) error {
+	const op = "Reminders.Resend"
+
+	log := r.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	user, err := r.users.UserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Verified {
+		return fmt.Errorf("%s: %w", op, ErrAlreadyVerified)
+	}
+
+	// Forced resend isn't counted either, so it doesn't hold back the user's own requests.
+	if !force {
+		allowed, err := r.cooldown.Allow(ctx, strings.ToLower(user.Email))
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !allowed {
+			log.Info("verification resend is rate limited")
+
+			return fmt.Errorf("%s: %w", op, ErrCooldown)
+		}
+	}
+
+	sent, err := r.remind(ctx, user.Email, "")
+	if err != nil {
+		log.Error("failed to resend verification", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !sent {
+		return fmt.Errorf("%s: %w", op, ErrSuppressed)
+	}
+
+	log.Info("verification resent", slog.Bool("force", force))
+
+	payload := map[string]string{"source": "admin_api", "force": strconv.FormatBool(force)}
+	if operator := peer.ClientCertName(ctx); operator != "" {
+		payload["operator"] = operator
+	}
+
+	event := models.AuditEvent{
+		Action:  models.AuditActionVerificationResent,
+		Subject: user.Email,
+		Payload: payload,
+	}
+	// Admin authenticated by the access token.
+	if claims, ok := jwt.FromContext(ctx); ok {
+		event.ActorID = claims.UserID
+	}
+
+	r.auditor.Record(ctx, event)
+
+	return nil
+}
+
+// remind emails fresh verification code to the user, it returns false if the email is suppressed or
+// the user opted out of the category. Empty category means the email is essential.
+func (r *Reminders) remind(ctx context.Context, email string, category models.EmailCategory) (bool, error) {
+	// Suppression and preferences are checked before the code is stored, so a code the user may have
+	// is not replaced for nothing.
+	_, err := r.suppressions.Suppression(ctx, email)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, storage.ErrSuppressionNotFound) {
+		return false, err
+	}
+
+	if category != "" {
+		prefs, err := r.preferences.NotificationPreferences(ctx, email)
+		if err != nil {
+			return false, err
+		}
+		if !prefs.Allows(category) {
+			return false, nil
+		}
+	}
+
+	codeFormat := r.codeFormats.For(models.VerificationTypeRegistration)
+
+	code, err := codeFormat.Generate(r.random)
+	if err != nil {
+		return false, err
+	}
+
+	expiresAt := r.clock.Now().UTC().Add(codeFormat.TTL)
+	if _, err := r.verifier.StoreVerification(ctx, email, models.VerificationTypeRegistration, code, expiresAt); err != nil {
+		return false, err
+	}
+
+	const subject = "Verify your email to finish registration"
+	if category != "" {
+		_, err = r.mailer.SendNotification(ctx, category, subject, []string{email}, code)
+	} else {
+		_, err = r.mailer.SendEmail(ctx, subject, []string{email}, code, []string{}, []string{}, []string{})
+	}
+	if err != nil {
+		return false, err
+	}
+
+	r.verifier.Delivered(ctx, models.VerificationTypeRegistration)
+
+	return true, nil
+}
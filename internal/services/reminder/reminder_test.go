@@ -0,0 +1,191 @@
+package reminder
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	mathrand "math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/services/reminder/mocks"
+	"grpc-service-ref/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAfter        = 3 * 24 * time.Hour
+	testInterval     = 7 * 24 * time.Hour
+	testMaxReminders = 2
+)
+
+var (
+	testNow    = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testLog    = slog.New(slog.NewTextHandler(io.Discard, nil))
+	testRandom = random.New(mathrand.New(mathrand.NewSource(1)))
+)
+
+var testFormats = verification.CodeFormats{
+	Default: verification.CodeFormat{Len: 6, Charset: verification.CharsetDigits, TTL: 24 * time.Hour},
+}
+
+// expectCode expects a fresh registration code to be stored for the email and returns where it's captured.
+func expectCode(ctx context.Context, verifier *mocks.Verifier, email string) *string {
+	code := new(string)
+	verifier.EXPECT().StoreVerification(ctx, email, models.VerificationTypeRegistration, mock.Anything, testNow.Add(24*time.Hour)).
+		RunAndReturn(func(_ context.Context, _ string, _ models.VerificationType, c string, _ time.Time) (models.VerificationData, error) {
+			*code = c
+
+			return models.VerificationData{}, nil
+		}).Once()
+	verifier.EXPECT().Delivered(ctx, models.VerificationTypeRegistration).Once()
+
+	return code
+}
+
+func TestRemind(t *testing.T) {
+	ctx := context.Background()
+	due := models.User{ID: 1, Email: "due@example.com"}
+	suppressed := models.User{ID: 2, Email: "bounced@example.com"}
+	optedOut := models.User{ID: 3, Email: "opted-out@example.com"}
+	failing := models.User{ID: 4, Email: "failing@example.com"}
+
+	store, suppressions, preferences := mocks.NewStore(t), mocks.NewSuppressionProvider(t), mocks.NewPreferencesProvider(t)
+	verifier, mailer := mocks.NewVerifier(t), mocks.NewEmailSender(t)
+
+	store.EXPECT().UsersToRemind(ctx, testNow.Add(-testAfter), testNow.Add(-testInterval), testMaxReminders, batchSize).
+		Return([]models.User{due, suppressed, optedOut, failing}, nil).Once()
+
+	suppressions.EXPECT().Suppression(ctx, due.Email).Return(models.Suppression{}, storage.ErrSuppressionNotFound)
+	preferences.EXPECT().NotificationPreferences(ctx, due.Email).Return(models.DefaultNotificationPreferences(), nil)
+	code := expectCode(ctx, verifier, due.Email)
+	mailer.EXPECT().SendNotification(ctx, models.EmailCategoryReminders, mock.Anything, []string{due.Email}, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ models.EmailCategory, _ string, _ []string, content string) (string, error) {
+			assert.Equal(t, *code, content, "reminder has the fresh code")
+
+			return "message-id", nil
+		}).Once()
+	store.EXPECT().SaveVerificationReminder(ctx, due.ID, testNow).Return(nil).Once()
+
+	suppressions.EXPECT().Suppression(ctx, suppressed.Email).Return(models.Suppression{Email: suppressed.Email}, nil)
+	store.EXPECT().SaveVerificationReminder(ctx, suppressed.ID, testNow).Return(nil).Once()
+
+	suppressions.EXPECT().Suppression(ctx, optedOut.Email).Return(models.Suppression{}, storage.ErrSuppressionNotFound)
+	preferences.EXPECT().NotificationPreferences(ctx, optedOut.Email).Return(models.NotificationPreferences{Security: true}, nil)
+	store.EXPECT().SaveVerificationReminder(ctx, optedOut.ID, testNow).Return(nil).Once()
+
+	suppressions.EXPECT().Suppression(ctx, failing.Email).Return(models.Suppression{}, assert.AnError)
+
+	r := New(testLog, store, mocks.NewUserProvider(t), suppressions, preferences, verifier, mailer, mocks.NewAuditor(t),
+		testFormats, mocks.NewLimiter(t), clock.NewFake(testNow), testRandom, testAfter, testInterval, testMaxReminders)
+
+	require.NoError(t, r.Remind(ctx))
+
+	verifier.AssertNotCalled(t, "StoreVerification", ctx, suppressed.Email, mock.Anything, mock.Anything, mock.Anything)
+	verifier.AssertNotCalled(t, "StoreVerification", ctx, optedOut.Email, mock.Anything, mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "SaveVerificationReminder", ctx, failing.ID, mock.Anything)
+}
+
+func TestRemindWithoutUsers(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		maxReminders int
+		usersErr     error
+		wantErr      error
+	}{
+		{name: "disabled", maxReminders: 0},
+		{name: "no users", maxReminders: testMaxReminders},
+		{name: "storage failure", maxReminders: testMaxReminders, usersErr: assert.AnError, wantErr: assert.AnError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewStore(t)
+			if tt.maxReminders > 0 {
+				store.EXPECT().UsersToRemind(ctx, mock.Anything, mock.Anything, tt.maxReminders, batchSize).Return(nil, tt.usersErr).Once()
+			}
+
+			r := New(testLog, store, mocks.NewUserProvider(t), mocks.NewSuppressionProvider(t), mocks.NewPreferencesProvider(t),
+				mocks.NewVerifier(t), mocks.NewEmailSender(t), mocks.NewAuditor(t), testFormats, mocks.NewLimiter(t),
+				clock.NewFake(testNow), testRandom, testAfter, testInterval, tt.maxReminders)
+
+			assert.ErrorIs(t, r.Remind(ctx), tt.wantErr)
+		})
+	}
+}
+
+func TestResend(t *testing.T) {
+	ctx := jwt.NewContext(context.Background(), jwt.Claims{UserID: 7})
+	unverified := models.User{ID: 1, Email: "User@example.com"}
+
+	tests := []struct {
+		name       string
+		user       models.User
+		userErr    error
+		force      bool
+		cooldown   bool
+		suppressed bool
+		wantErr    error
+	}{
+		{name: "resent", user: unverified},
+		{name: "forced resend skips cooldown", user: unverified, force: true, cooldown: true},
+		{name: "cooldown", user: unverified, cooldown: true, wantErr: ErrCooldown},
+		{name: "suppressed", user: unverified, force: true, suppressed: true, wantErr: ErrSuppressed},
+		{name: "already verified", user: models.User{ID: 2, Email: "verified@example.com", Verified: true}, force: true, wantErr: ErrAlreadyVerified},
+		{name: "user not found", user: models.User{ID: 3}, userErr: storage.ErrUserNotFound, force: true, wantErr: storage.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, cooldown, suppressions := mocks.NewUserProvider(t), mocks.NewLimiter(t), mocks.NewSuppressionProvider(t)
+			verifier, mailer, auditor := mocks.NewVerifier(t), mocks.NewEmailSender(t), mocks.NewAuditor(t)
+
+			// Mocks fail on unexpected calls, so the steps after the failing one are never taken.
+			users.EXPECT().UserByID(ctx, tt.user.ID).Return(tt.user, tt.userErr).Once()
+			if tt.userErr == nil && !tt.user.Verified {
+				if !tt.force {
+					cooldown.EXPECT().Allow(ctx, strings.ToLower(tt.user.Email)).Return(!tt.cooldown, nil).Once()
+				}
+				if tt.force || !tt.cooldown {
+					suppression, err := models.Suppression{}, storage.ErrSuppressionNotFound
+					if tt.suppressed {
+						suppression, err = models.Suppression{Email: tt.user.Email}, nil
+					}
+					suppressions.EXPECT().Suppression(ctx, tt.user.Email).Return(suppression, err).Once()
+				}
+			}
+			if tt.wantErr == nil {
+				code := expectCode(ctx, verifier, tt.user.Email)
+				mailer.EXPECT().SendEmail(ctx, mock.Anything, []string{tt.user.Email}, mock.Anything, []string{}, []string{}, []string{}).
+					RunAndReturn(func(_ context.Context, _ string, _ []string, content string, _ []string, _ []string, _ []string) (string, error) {
+						assert.Equal(t, *code, content)
+
+						return "message-id", nil
+					}).Once()
+				auditor.EXPECT().Record(ctx, mock.MatchedBy(func(event models.AuditEvent) bool {
+					return event.Action == models.AuditActionVerificationResent &&
+						event.ActorID == 7 &&
+						event.Subject == tt.user.Email &&
+						event.Payload["force"] == strconv.FormatBool(tt.force)
+				})).Once()
+			}
+
+			r := New(testLog, mocks.NewStore(t), users, suppressions, mocks.NewPreferencesProvider(t), verifier, mailer, auditor,
+				testFormats, cooldown, clock.NewFake(testNow), testRandom, testAfter, testInterval, testMaxReminders)
+
+			assert.ErrorIs(t, r.Resend(ctx, tt.user.ID, tt.force), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GeoIP is an autogenerated mock type for the GeoIP type
+type GeoIP struct {
+	mock.Mock
+}
+
+type GeoIP_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GeoIP) EXPECT() *GeoIP_Expecter {
+	return &GeoIP_Expecter{mock: &_m.Mock}
+}
+
+// Lookup provides a mock function with given fields: ip
+func (_m *GeoIP) Lookup(ip string) (models.Location, bool) {
+	ret := _m.Called(ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lookup")
+	}
+
+	var r0 models.Location
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (models.Location, bool)); ok {
+		return rf(ip)
+	}
+	if rf, ok := ret.Get(0).(func(string) models.Location); ok {
+		r0 = rf(ip)
+	} else {
+		r0 = ret.Get(0).(models.Location)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(ip)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// GeoIP_Lookup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lookup'
+type GeoIP_Lookup_Call struct {
+	*mock.Call
+}
+
+// Lookup is a helper method to define mock.On call
+//   - ip string
+func (_e *GeoIP_Expecter) Lookup(ip interface{}) *GeoIP_Lookup_Call {
+	return &GeoIP_Lookup_Call{Call: _e.mock.On("Lookup", ip)}
+}
+
+func (_c *GeoIP_Lookup_Call) Run(run func(ip string)) *GeoIP_Lookup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *GeoIP_Lookup_Call) Return(_a0 models.Location, _a1 bool) *GeoIP_Lookup_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GeoIP_Lookup_Call) RunAndReturn(run func(string) (models.Location, bool)) *GeoIP_Lookup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGeoIP creates a new instance of GeoIP. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeoIP(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeoIP {
+	mock := &GeoIP{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
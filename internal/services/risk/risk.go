@@ -0,0 +1,167 @@
+package risk
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+)
+
+// MaxScore is the score of logins which are certainly not made by the user.
+const MaxScore = 100
+
+// Scores of signals, summed up to MaxScore.
+const (
+	newDeviceScore        = 20
+	newCountryScore       = 40
+	impossibleTravelScore = 80
+)
+
+// Reasons of scores reported in Assessment.
+const (
+	ReasonNewDevice        = "new_device"
+	ReasonNewCountry       = "new_country"
+	ReasonImpossibleTravel = "impossible_travel"
+)
+
+// minTravelDistance is distance in km below which travel is never impossible,
+// GeoIP locations of nearby IPs are often tens of km apart.
+const minTravelDistance = 500
+
+const earthRadius = 6371
+
+// GeoIP resolves locations of IP addresses.
+type GeoIP interface {
+	// Lookup returns location of the IP, false if it's unknown.
+	Lookup(ip string) (models.Location, bool)
+}
+
+// Attempt is a login of the user who passed credentials check.
+type Attempt struct {
+	User   models.User
+	AppID  int
+	Device models.Device
+	// History is devices the user signed in from before, empty for the first login.
+	History    []models.KnownDevice
+	NewDevice  bool
+	NewCountry bool
+	Time       time.Time
+}
+
+// Assessment is risk of a login.
+type Assessment struct {
+	// Score is 0 (usual login) to MaxScore.
+	Score int
+	// Reasons are signals contributing to the score, e.g. ReasonImpossibleTravel.
+	Reasons []string
+}
+
+// Policy decides what happens to logins by their score.
+type Policy struct {
+	// ChallengeScore requires logins scored at least that to be confirmed by code emailed to the user, 0 disables it.
+	ChallengeScore int
+	// BlockScore rejects logins scored at least that, the user is notified. 0 disables it.
+	BlockScore int
+}
+
+// Challenges reports whether login with the score must be confirmed.
+func (p Policy) Challenges(score int) bool {
+	return p.ChallengeScore > 0 && score >= p.ChallengeScore
+}
+
+// Blocks reports whether login with the score is rejected.
+func (p Policy) Blocks(score int) bool {
+	return p.BlockScore > 0 && score >= p.BlockScore
+}
+
+// Heuristic scores logins by signals of account takeover: new devices and countries,
+// and travel from the location of the previous login faster than possible.
+// Travel is checked only if geo is set.
+type Heuristic struct {
+	geo GeoIP
+	// maxSpeed is the fastest possible travel in km/h.
+	maxSpeed float64
+}
+
+func NewHeuristic(geo GeoIP, maxSpeed float64) *Heuristic {
+	return &Heuristic{
+		geo:      geo,
+		maxSpeed: maxSpeed,
+	}
+}
+
+// Evaluate returns risk of the login attempt, first login of the user is never risky.
+func (h *Heuristic) Evaluate(ctx context.Context, attempt Attempt) (Assessment, error) {
+	var res Assessment
+	if len(attempt.History) == 0 {
+		return res, nil
+	}
+
+	if attempt.NewDevice {
+		res.add(newDeviceScore, ReasonNewDevice)
+	}
+	if attempt.NewCountry {
+		res.add(newCountryScore, ReasonNewCountry)
+	}
+	if h.impossibleTravel(attempt) {
+		res.add(impossibleTravelScore, ReasonImpossibleTravel)
+	}
+
+	return res, nil
+}
+
+func (a *Assessment) add(score int, reason string) {
+	a.Score = min(a.Score+score, MaxScore)
+	a.Reasons = append(a.Reasons, reason)
+}
+
+// impossibleTravel reports whether the user couldn't get from location of the last login to the current one in time.
+func (h *Heuristic) impossibleTravel(attempt Attempt) bool {
+	if h.geo == nil {
+		return false
+	}
+
+	last := attempt.History[0]
+	for _, d := range attempt.History[1:] {
+		if d.LastSeenAt.After(last.LastSeenAt) {
+			last = d
+		}
+	}
+
+	from, ok := h.geo.Lookup(last.IP)
+	if !ok || !from.HasCoordinates {
+		return false
+	}
+
+	to, ok := h.geo.Lookup(attempt.Device.IP)
+	if !ok || !to.HasCoordinates {
+		return false
+	}
+
+	distance := haversine(from, to)
+	if distance < minTravelDistance {
+		return false
+	}
+
+	hours := attempt.Time.Sub(last.LastSeenAt).Hours()
+	if hours <= 0 {
+		return true
+	}
+
+	return distance/hours > h.maxSpeed
+}
+
+// haversine returns great-circle distance between the locations in km.
+func haversine(from models.Location, to models.Location) float64 {
+	lat1, lat2 := radians(from.Latitude), radians(to.Latitude)
+	dLat, dLon := lat2-lat1, radians(to.Longitude-from.Longitude)
+
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
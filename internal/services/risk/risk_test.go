@@ -0,0 +1,158 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/services/risk/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+var (
+	berlin = models.Location{Country: "DE", City: "Berlin", Latitude: 52.52, Longitude: 13.405, HasCoordinates: true}
+	munich = models.Location{Country: "DE", City: "Munich", Latitude: 48.137, Longitude: 11.575, HasCoordinates: true}
+	tokyo  = models.Location{Country: "JP", City: "Tokyo", Latitude: 35.676, Longitude: 139.65, HasCoordinates: true}
+)
+
+// IPs resolved by the test GeoIP.
+const (
+	berlinIP    = "203.0.113.1"
+	munichIP    = "203.0.113.2"
+	tokyoIP     = "198.51.100.1"
+	noCoordsIP  = "198.51.100.2"
+	unknownIP   = "192.0.2.1"
+	maxSpeedKmh = 1000
+)
+
+func newTestGeoIP(t *testing.T) *mocks.GeoIP {
+	t.Helper()
+
+	geo := mocks.NewGeoIP(t)
+	geo.EXPECT().Lookup(berlinIP).Return(berlin, true).Maybe()
+	geo.EXPECT().Lookup(munichIP).Return(munich, true).Maybe()
+	geo.EXPECT().Lookup(tokyoIP).Return(tokyo, true).Maybe()
+	geo.EXPECT().Lookup(noCoordsIP).Return(models.Location{Country: "FR"}, true).Maybe()
+	geo.EXPECT().Lookup(unknownIP).Return(models.Location{}, false).Maybe()
+
+	return geo
+}
+
+func TestHeuristicEvaluate(t *testing.T) {
+	history := []models.KnownDevice{
+		{Key: "id:old", IP: tokyoIP, LastSeenAt: testNow.Add(-30 * 24 * time.Hour)},
+		{Key: "id:laptop", IP: berlinIP, LastSeenAt: testNow.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name        string
+		attempt     Attempt
+		withoutGeo  bool
+		wantScore   int
+		wantReasons []string
+	}{
+		{
+			name:    "first login",
+			attempt: Attempt{Device: models.Device{IP: tokyoIP}, NewDevice: true, NewCountry: true, Time: testNow},
+		},
+		{
+			name:    "usual login",
+			attempt: Attempt{Device: models.Device{IP: berlinIP}, History: history, Time: testNow},
+		},
+		{
+			name:        "new device",
+			attempt:     Attempt{Device: models.Device{IP: berlinIP}, History: history, NewDevice: true, Time: testNow},
+			wantScore:   newDeviceScore,
+			wantReasons: []string{ReasonNewDevice},
+		},
+		{
+			name:      "travel within speed limit",
+			attempt:   Attempt{Device: models.Device{IP: munichIP}, History: history, Time: testNow},
+			wantScore: 0,
+		},
+		{
+			name:        "impossible travel from the latest login",
+			attempt:     Attempt{Device: models.Device{IP: tokyoIP}, History: history, Time: testNow},
+			wantScore:   impossibleTravelScore,
+			wantReasons: []string{ReasonImpossibleTravel},
+		},
+		{
+			name:        "score is capped",
+			attempt:     Attempt{Device: models.Device{IP: tokyoIP}, History: history, NewDevice: true, NewCountry: true, Time: testNow},
+			wantScore:   MaxScore,
+			wantReasons: []string{ReasonNewDevice, ReasonNewCountry, ReasonImpossibleTravel},
+		},
+		{
+			name:    "possible travel",
+			attempt: Attempt{Device: models.Device{IP: tokyoIP}, History: history, Time: testNow.Add(24 * time.Hour)},
+		},
+		{
+			name:        "login at the same time elsewhere",
+			attempt:     Attempt{Device: models.Device{IP: tokyoIP}, History: history, Time: testNow.Add(-time.Hour)},
+			wantScore:   impossibleTravelScore,
+			wantReasons: []string{ReasonImpossibleTravel},
+		},
+		{
+			name:    "unknown location",
+			attempt: Attempt{Device: models.Device{IP: unknownIP}, History: history, Time: testNow},
+		},
+		{
+			name:    "location without coordinates",
+			attempt: Attempt{Device: models.Device{IP: noCoordsIP}, History: history, Time: testNow},
+		},
+		{
+			name:       "travel isn't checked without geo",
+			attempt:    Attempt{Device: models.Device{IP: tokyoIP}, History: history, Time: testNow},
+			withoutGeo: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var geo GeoIP
+			if !tt.withoutGeo {
+				geo = newTestGeoIP(t)
+			}
+
+			got, err := NewHeuristic(geo, maxSpeedKmh).Evaluate(context.Background(), tt.attempt)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantScore, got.Score)
+			assert.Equal(t, tt.wantReasons, got.Reasons)
+		})
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	assert.InDelta(t, 504, haversine(berlin, munich), 5)
+	assert.InDelta(t, 8920, haversine(berlin, tokyo), 50)
+	assert.Zero(t, haversine(berlin, berlin))
+}
+
+func TestPolicy(t *testing.T) {
+	p := Policy{ChallengeScore: 40, BlockScore: 80}
+
+	tests := []struct {
+		score         int
+		wantChallenge bool
+		wantBlock     bool
+	}{
+		{0, false, false},
+		{39, false, false},
+		{40, true, false},
+		{80, true, true},
+		{MaxScore, true, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantChallenge, p.Challenges(tt.score), "challenges %d", tt.score)
+		assert.Equal(t, tt.wantBlock, p.Blocks(tt.score), "blocks %d", tt.score)
+	}
+
+	assert.False(t, Policy{}.Challenges(MaxScore), "zero thresholds disable the policy")
+	assert.False(t, Policy{}.Blocks(MaxScore), "zero thresholds disable the policy")
+}
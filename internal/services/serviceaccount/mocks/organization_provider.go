@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrganizationProvider is an autogenerated mock type for the OrganizationProvider type
+type OrganizationProvider struct {
+	mock.Mock
+}
+
+type OrganizationProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OrganizationProvider) EXPECT() *OrganizationProvider_Expecter {
+	return &OrganizationProvider_Expecter{mock: &_m.Mock}
+}
+
+// Organization provides a mock function with given fields: ctx, id
+func (_m *OrganizationProvider) Organization(ctx context.Context, id int64) (models.Organization, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Organization")
+	}
+
+	var r0 models.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Organization, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Organization); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Organization)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrganizationProvider_Organization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Organization'
+type OrganizationProvider_Organization_Call struct {
+	*mock.Call
+}
+
+// Organization is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *OrganizationProvider_Expecter) Organization(ctx interface{}, id interface{}) *OrganizationProvider_Organization_Call {
+	return &OrganizationProvider_Organization_Call{Call: _e.mock.On("Organization", ctx, id)}
+}
+
+func (_c *OrganizationProvider_Organization_Call) Run(run func(ctx context.Context, id int64)) *OrganizationProvider_Organization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *OrganizationProvider_Organization_Call) Return(_a0 models.Organization, _a1 error) *OrganizationProvider_Organization_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OrganizationProvider_Organization_Call) RunAndReturn(run func(context.Context, int64) (models.Organization, error)) *OrganizationProvider_Organization_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOrganizationProvider creates a new instance of OrganizationProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOrganizationProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OrganizationProvider {
+	mock := &OrganizationProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SessionSaver is an autogenerated mock type for the SessionSaver type
+type SessionSaver struct {
+	mock.Mock
+}
+
+type SessionSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SessionSaver) EXPECT() *SessionSaver_Expecter {
+	return &SessionSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveSession provides a mock function with given fields: ctx, session
+func (_m *SessionSaver) SaveSession(ctx context.Context, session models.Session) error {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Session) error); ok {
+		r0 = rf(ctx, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionSaver_SaveSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSession'
+type SessionSaver_SaveSession_Call struct {
+	*mock.Call
+}
+
+// SaveSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session models.Session
+func (_e *SessionSaver_Expecter) SaveSession(ctx interface{}, session interface{}) *SessionSaver_SaveSession_Call {
+	return &SessionSaver_SaveSession_Call{Call: _e.mock.On("SaveSession", ctx, session)}
+}
+
+func (_c *SessionSaver_SaveSession_Call) Run(run func(ctx context.Context, session models.Session)) *SessionSaver_SaveSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Session))
+	})
+	return _c
+}
+
+func (_c *SessionSaver_SaveSession_Call) Return(_a0 error) *SessionSaver_SaveSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionSaver_SaveSession_Call) RunAndReturn(run func(context.Context, models.Session) error) *SessionSaver_SaveSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSessionSaver creates a new instance of SessionSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionSaver {
+	mock := &SessionSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
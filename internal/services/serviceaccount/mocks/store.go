@@ -0,0 +1,412 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+type Store_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Store) EXPECT() *Store_Expecter {
+	return &Store_Expecter{mock: &_m.Mock}
+}
+
+// DisableServiceAccount provides a mock function with given fields: ctx, id, at
+func (_m *Store) DisableServiceAccount(ctx context.Context, id int64, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableServiceAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_DisableServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableServiceAccount'
+type Store_DisableServiceAccount_Call struct {
+	*mock.Call
+}
+
+// DisableServiceAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - at time.Time
+func (_e *Store_Expecter) DisableServiceAccount(ctx interface{}, id interface{}, at interface{}) *Store_DisableServiceAccount_Call {
+	return &Store_DisableServiceAccount_Call{Call: _e.mock.On("DisableServiceAccount", ctx, id, at)}
+}
+
+func (_c *Store_DisableServiceAccount_Call) Run(run func(ctx context.Context, id int64, at time.Time)) *Store_DisableServiceAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Store_DisableServiceAccount_Call) Return(_a0 error) *Store_DisableServiceAccount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_DisableServiceAccount_Call) RunAndReturn(run func(context.Context, int64, time.Time) error) *Store_DisableServiceAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeServiceAccountKey provides a mock function with given fields: ctx, id, at
+func (_m *Store) RevokeServiceAccountKey(ctx context.Context, id string, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeServiceAccountKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_RevokeServiceAccountKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeServiceAccountKey'
+type Store_RevokeServiceAccountKey_Call struct {
+	*mock.Call
+}
+
+// RevokeServiceAccountKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - at time.Time
+func (_e *Store_Expecter) RevokeServiceAccountKey(ctx interface{}, id interface{}, at interface{}) *Store_RevokeServiceAccountKey_Call {
+	return &Store_RevokeServiceAccountKey_Call{Call: _e.mock.On("RevokeServiceAccountKey", ctx, id, at)}
+}
+
+func (_c *Store_RevokeServiceAccountKey_Call) Run(run func(ctx context.Context, id string, at time.Time)) *Store_RevokeServiceAccountKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Store_RevokeServiceAccountKey_Call) Return(_a0 error) *Store_RevokeServiceAccountKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_RevokeServiceAccountKey_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *Store_RevokeServiceAccountKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveServiceAccount provides a mock function with given fields: ctx, account
+func (_m *Store) SaveServiceAccount(ctx context.Context, account models.ServiceAccount) (int64, error) {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveServiceAccount")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ServiceAccount) (int64, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ServiceAccount) int64); ok {
+		r0 = rf(ctx, account)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ServiceAccount) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_SaveServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveServiceAccount'
+type Store_SaveServiceAccount_Call struct {
+	*mock.Call
+}
+
+// SaveServiceAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - account models.ServiceAccount
+func (_e *Store_Expecter) SaveServiceAccount(ctx interface{}, account interface{}) *Store_SaveServiceAccount_Call {
+	return &Store_SaveServiceAccount_Call{Call: _e.mock.On("SaveServiceAccount", ctx, account)}
+}
+
+func (_c *Store_SaveServiceAccount_Call) Run(run func(ctx context.Context, account models.ServiceAccount)) *Store_SaveServiceAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.ServiceAccount))
+	})
+	return _c
+}
+
+func (_c *Store_SaveServiceAccount_Call) Return(_a0 int64, _a1 error) *Store_SaveServiceAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_SaveServiceAccount_Call) RunAndReturn(run func(context.Context, models.ServiceAccount) (int64, error)) *Store_SaveServiceAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveServiceAccountKey provides a mock function with given fields: ctx, key
+func (_m *Store) SaveServiceAccountKey(ctx context.Context, key models.ServiceAccountKey) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveServiceAccountKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ServiceAccountKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_SaveServiceAccountKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveServiceAccountKey'
+type Store_SaveServiceAccountKey_Call struct {
+	*mock.Call
+}
+
+// SaveServiceAccountKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key models.ServiceAccountKey
+func (_e *Store_Expecter) SaveServiceAccountKey(ctx interface{}, key interface{}) *Store_SaveServiceAccountKey_Call {
+	return &Store_SaveServiceAccountKey_Call{Call: _e.mock.On("SaveServiceAccountKey", ctx, key)}
+}
+
+func (_c *Store_SaveServiceAccountKey_Call) Run(run func(ctx context.Context, key models.ServiceAccountKey)) *Store_SaveServiceAccountKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.ServiceAccountKey))
+	})
+	return _c
+}
+
+func (_c *Store_SaveServiceAccountKey_Call) Return(_a0 error) *Store_SaveServiceAccountKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_SaveServiceAccountKey_Call) RunAndReturn(run func(context.Context, models.ServiceAccountKey) error) *Store_SaveServiceAccountKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ServiceAccount provides a mock function with given fields: ctx, id
+func (_m *Store) ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ServiceAccount")
+	}
+
+	var r0 models.ServiceAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.ServiceAccount, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.ServiceAccount); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.ServiceAccount)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_ServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ServiceAccount'
+type Store_ServiceAccount_Call struct {
+	*mock.Call
+}
+
+// ServiceAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Store_Expecter) ServiceAccount(ctx interface{}, id interface{}) *Store_ServiceAccount_Call {
+	return &Store_ServiceAccount_Call{Call: _e.mock.On("ServiceAccount", ctx, id)}
+}
+
+func (_c *Store_ServiceAccount_Call) Run(run func(ctx context.Context, id int64)) *Store_ServiceAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Store_ServiceAccount_Call) Return(_a0 models.ServiceAccount, _a1 error) *Store_ServiceAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_ServiceAccount_Call) RunAndReturn(run func(context.Context, int64) (models.ServiceAccount, error)) *Store_ServiceAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ServiceAccountKey provides a mock function with given fields: ctx, id
+func (_m *Store) ServiceAccountKey(ctx context.Context, id string) (models.ServiceAccountKey, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ServiceAccountKey")
+	}
+
+	var r0 models.ServiceAccountKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.ServiceAccountKey, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.ServiceAccountKey); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.ServiceAccountKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_ServiceAccountKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ServiceAccountKey'
+type Store_ServiceAccountKey_Call struct {
+	*mock.Call
+}
+
+// ServiceAccountKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Store_Expecter) ServiceAccountKey(ctx interface{}, id interface{}) *Store_ServiceAccountKey_Call {
+	return &Store_ServiceAccountKey_Call{Call: _e.mock.On("ServiceAccountKey", ctx, id)}
+}
+
+func (_c *Store_ServiceAccountKey_Call) Run(run func(ctx context.Context, id string)) *Store_ServiceAccountKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Store_ServiceAccountKey_Call) Return(_a0 models.ServiceAccountKey, _a1 error) *Store_ServiceAccountKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_ServiceAccountKey_Call) RunAndReturn(run func(context.Context, string) (models.ServiceAccountKey, error)) *Store_ServiceAccountKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ServiceAccounts provides a mock function with given fields: ctx, appID
+func (_m *Store) ServiceAccounts(ctx context.Context, appID int) ([]models.ServiceAccount, error) {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ServiceAccounts")
+	}
+
+	var r0 []models.ServiceAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.ServiceAccount, error)); ok {
+		return rf(ctx, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ServiceAccount); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ServiceAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_ServiceAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ServiceAccounts'
+type Store_ServiceAccounts_Call struct {
+	*mock.Call
+}
+
+// ServiceAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *Store_Expecter) ServiceAccounts(ctx interface{}, appID interface{}) *Store_ServiceAccounts_Call {
+	return &Store_ServiceAccounts_Call{Call: _e.mock.On("ServiceAccounts", ctx, appID)}
+}
+
+func (_c *Store_ServiceAccounts_Call) Run(run func(ctx context.Context, appID int)) *Store_ServiceAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Store_ServiceAccounts_Call) Return(_a0 []models.ServiceAccount, _a1 error) *Store_ServiceAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Store_ServiceAccounts_Call) RunAndReturn(run func(context.Context, int) ([]models.ServiceAccount, error)) *Store_ServiceAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,610 @@
+package serviceaccount
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/geoip"
+	"grpc-service-ref/internal/lib/jwt"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/peer"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/storage"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// apiKeyPrefix makes API keys recognizable, e.g. by secret scanners.
+	apiKeyPrefix = "sa_"
+	keyIDLen     = 8
+	maxNameLen   = 100
+	maxScopeLen  = 100
+	// maxAssertionTTL limits lifetime of assertions, so an intercepted one can't be exchanged for tokens for long.
+	maxAssertionTTL = 5 * time.Minute
+)
+
+var (
+	ErrInvalidName = errors.New("invalid service account name")
+	// ErrInvalidScope is returned for malformed scope or scope the account doesn't have.
+	ErrInvalidScope     = errors.New("invalid scope")
+	ErrInvalidPublicKey = errors.New("invalid public key")
+	// ErrInvalidCredentials is returned for unknown or revoked key, invalid assertion or disabled account.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+type Store interface {
+	SaveServiceAccount(ctx context.Context, account models.ServiceAccount) (int64, error)
+	ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error)
+	ServiceAccounts(ctx context.Context, appID int) ([]models.ServiceAccount, error)
+	DisableServiceAccount(ctx context.Context, id int64, at time.Time) error
+	SaveServiceAccountKey(ctx context.Context, key models.ServiceAccountKey) error
+	ServiceAccountKey(ctx context.Context, id string) (models.ServiceAccountKey, error)
+	RevokeServiceAccountKey(ctx context.Context, id string, at time.Time) error
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+type OrganizationProvider interface {
+	Organization(ctx context.Context, id int64) (models.Organization, error)
+}
+
+// SessionSaver records issued tokens, so they are validated and revoked like tokens of users.
+type SessionSaver interface {
+	SaveSession(ctx context.Context, session models.Session) error
+}
+
+// Auditor records security-relevant actions to the audit log.
+type Auditor interface {
+	Record(ctx context.Context, event models.AuditEvent)
+}
+
+// Token is an access token issued to a service account.
+type Token struct {
+	Token     string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// ServiceAccounts manages non-human accounts of apps and issues them tokens for their API keys
+// or JWT assertions signed by their private keys.
+type ServiceAccounts struct {
+	log      *slog.Logger
+	store    Store
+	apps     AppProvider
+	orgs     OrganizationProvider
+	sessions SessionSaver
+	auditor  Auditor
+	clock    clock.Clock
+	random   random.Randomizer
+	tokenTTL time.Duration
+	audience string
+	// issuance is iss and aud claims of tokens of apps that don't override them.
+	issuance jwt.Issuance
+}
+
+func New(
+	log *slog.Logger,
+	store Store,
+	apps AppProvider,
+	orgs OrganizationProvider,
+	sessions SessionSaver,
+	auditor Auditor,
+	clock clock.Clock,
+	random random.Randomizer,
+	tokenTTL time.Duration,
+	audience string,
+	issuance jwt.Issuance,
+) *ServiceAccounts {
+	return &ServiceAccounts{
+		log:      log,
+		store:    store,
+		apps:     apps,
+		orgs:     orgs,
+		sessions: sessions,
+		auditor:  auditor,
+		clock:    clock,
+		random:   random,
+		tokenTTL: tokenTTL,
+		audience: audience,
+		issuance: issuance,
+	}
+}
+
+// Create creates service account of the app, acting for the organization unless orgID is 0.
+// Tokens of the account are limited to the scopes.
+func (s *ServiceAccounts) Create(
+	ctx context.Context,
+	appID int,
+	orgID int64,
+	name string,
+	scopes []string,
+) (models.ServiceAccount, error) {
+	const op = "ServiceAccounts.Create"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLen {
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, ErrInvalidName)
+	}
+	for _, scope := range scopes {
+		if !validScope(scope) {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, ErrInvalidScope)
+		}
+	}
+
+	if _, err := s.apps.App(ctx, appID); err != nil {
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if orgID != 0 {
+		if _, err := s.orgs.Organization(ctx, orgID); err != nil {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	account := models.ServiceAccount{
+		AppID:     appID,
+		OrgID:     orgID,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: s.clock.Now().UTC(),
+	}
+
+	id, err := s.store.SaveServiceAccount(ctx, account)
+	if err != nil {
+		if !errors.Is(err, storage.ErrServiceAccountExists) {
+			log.Error("failed to save service account", sl.Err(err))
+		}
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+	account.ID = id
+
+	log.Info("service account created", slog.Int64("service_account_id", id))
+
+	s.audit(ctx, account, map[string]string{"change": "created", "scopes": strings.Join(scopes, " ")})
+
+	return account, nil
+}
+
+// ServiceAccounts returns service accounts of the app.
+func (s *ServiceAccounts) ServiceAccounts(ctx context.Context, appID int) ([]models.ServiceAccount, error) {
+	const op = "ServiceAccounts.ServiceAccounts"
+
+	accounts, err := s.store.ServiceAccounts(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return accounts, nil
+}
+
+// Disable disables service account, its keys stop working and its tokens are revoked.
+func (s *ServiceAccounts) Disable(ctx context.Context, id int64) error {
+	const op = "ServiceAccounts.Disable"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("service_account_id", id),
+	)
+
+	account, err := s.store.ServiceAccount(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.store.DisableServiceAccount(ctx, id, s.clock.Now().UTC()); err != nil {
+		log.Error("failed to disable service account", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account disabled")
+
+	s.audit(ctx, account, map[string]string{"change": "disabled"})
+
+	return nil
+}
+
+// CreateAPIKey creates API key of the service account and returns it with its secret part,
+// the API key is shown only once.
+func (s *ServiceAccounts) CreateAPIKey(ctx context.Context, id int64) (models.ServiceAccountKey, string, error) {
+	const op = "ServiceAccounts.CreateAPIKey"
+
+	account, err := s.store.ServiceAccount(ctx, id)
+	if err != nil {
+		return models.ServiceAccountKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, err := random.Secret(s.random)
+	if err != nil {
+		return models.ServiceAccountKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+	key, err := s.saveKey(ctx, account, models.ServiceAccountKey{Kind: models.ServiceAccountKeyAPI, SecretHash: hash[:]})
+	if err != nil {
+		return models.ServiceAccountKey{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, apiKeyPrefix + key.ID + "_" + secret, nil
+}
+
+// AddPublicKey adds PEM encoded RSA, ECDSA P-256 or Ed25519 public key verifying JWT assertions of the service account.
+// Assertions name the key by its ID in kid header.
+func (s *ServiceAccounts) AddPublicKey(ctx context.Context, id int64, publicKey string) (models.ServiceAccountKey, error) {
+	const op = "ServiceAccounts.AddPublicKey"
+
+	if _, err := parsePublicKey(publicKey); err != nil {
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidPublicKey, err)
+	}
+
+	account, err := s.store.ServiceAccount(ctx, id)
+	if err != nil {
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := s.saveKey(ctx, account, models.ServiceAccountKey{Kind: models.ServiceAccountKeyPublic, PublicKey: publicKey})
+	if err != nil {
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (s *ServiceAccounts) saveKey(
+	ctx context.Context,
+	account models.ServiceAccount,
+	key models.ServiceAccountKey,
+) (models.ServiceAccountKey, error) {
+	b, err := s.random.Bytes(keyIDLen)
+	if err != nil {
+		return models.ServiceAccountKey{}, err
+	}
+
+	key.ID = hex.EncodeToString(b)
+	key.ServiceAccountID = account.ID
+	key.CreatedAt = s.clock.Now().UTC()
+
+	if err := s.store.SaveServiceAccountKey(ctx, key); err != nil {
+		s.log.Error("failed to save service account key", slog.Int64("service_account_id", account.ID), sl.Err(err))
+
+		return models.ServiceAccountKey{}, err
+	}
+
+	s.log.Info("service account key added",
+		slog.Int64("service_account_id", account.ID),
+		slog.String("key_id", key.ID),
+		slog.String("kind", string(key.Kind)),
+	)
+
+	s.audit(ctx, account, map[string]string{"change": "key_added", "key_id": key.ID, "kind": string(key.Kind)})
+
+	return key, nil
+}
+
+// RevokeKey revokes key of a service account, tokens issued for it before stay valid until they expire.
+func (s *ServiceAccounts) RevokeKey(ctx context.Context, keyID string) error {
+	const op = "ServiceAccounts.RevokeKey"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("key_id", keyID),
+	)
+
+	key, err := s.store.ServiceAccountKey(ctx, keyID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	account, err := s.store.ServiceAccount(ctx, key.ServiceAccountID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.store.RevokeServiceAccountKey(ctx, keyID, s.clock.Now().UTC()); err != nil {
+		log.Error("failed to revoke key", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("service account key revoked")
+
+	s.audit(ctx, account, map[string]string{"change": "key_revoked", "key_id": keyID})
+
+	return nil
+}
+
+// IssueTokenByAPIKey issues token of the service account the API key belongs to.
+// Token has the requested scopes, all scopes of the account if none are requested.
+func (s *ServiceAccounts) IssueTokenByAPIKey(ctx context.Context, apiKey string, scopes []string) (Token, error) {
+	const op = "ServiceAccounts.IssueTokenByAPIKey"
+
+	log := s.log.With(slog.String("op", op))
+
+	keyID, secret, ok := strings.Cut(strings.TrimPrefix(apiKey, apiKeyPrefix), "_")
+	if !ok || !strings.HasPrefix(apiKey, apiKeyPrefix) {
+		return Token{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	key, account, err := s.activeKey(ctx, keyID, models.ServiceAccountKeyAPI)
+	if err != nil {
+		log.Warn("key is rejected", slog.String("key_id", keyID), sl.Err(err))
+
+		return Token{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(hash[:], key.SecretHash) != 1 {
+		log.Warn("invalid API key secret", slog.String("key_id", keyID))
+
+		return Token{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	token, err := s.issueToken(ctx, account, key, scopes)
+	if err != nil {
+		return Token{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// IssueTokenByAssertion issues token of the service account for JWT assertion signed by its private key.
+// The assertion has kid header of the key, iss and sub of the account ID, aud of SSO and expires within 5 minutes.
+// Token has the requested scopes, all scopes of the account if none are requested.
+func (s *ServiceAccounts) IssueTokenByAssertion(ctx context.Context, assertion string, scopes []string) (Token, error) {
+	const op = "ServiceAccounts.IssueTokenByAssertion"
+
+	log := s.log.With(slog.String("op", op))
+
+	var (
+		key     models.ServiceAccountKey
+		account models.ServiceAccount
+		// keyErr is a failure to get the key, reported as is unless the key is rejected.
+		keyErr error
+	)
+
+	now := s.clock.Now()
+	parsed, err := gojwt.Parse(assertion, func(token *gojwt.Token) (any, error) {
+		keyID, _ := token.Header["kid"].(string)
+
+		key, account, keyErr = s.activeKey(ctx, keyID, models.ServiceAccountKeyPublic)
+		if keyErr != nil {
+			return nil, keyErr
+		}
+
+		return parsePublicKey(key.PublicKey)
+	},
+		gojwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		gojwt.WithAudience(s.audience),
+		gojwt.WithTimeFunc(func() time.Time { return now }),
+	)
+	if keyErr != nil && !errors.Is(keyErr, ErrInvalidCredentials) {
+		return Token{}, fmt.Errorf("%s: %w", op, keyErr)
+	}
+	if err != nil {
+		log.Warn("invalid assertion", sl.Err(err))
+
+		return Token{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	if err := s.checkAssertion(parsed, account, now); err != nil {
+		log.Warn("assertion is rejected", slog.String("key_id", key.ID), sl.Err(err))
+
+		return Token{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	token, err := s.issueToken(ctx, account, key, scopes)
+	if err != nil {
+		return Token{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// checkAssertion checks claims which Parse doesn't: the assertion is issued by the account for itself
+// and is short-lived.
+func (s *ServiceAccounts) checkAssertion(assertion *gojwt.Token, account models.ServiceAccount, now time.Time) error {
+	accountID := strconv.FormatInt(account.ID, 10)
+
+	iss, err := assertion.Claims.GetIssuer()
+	if err != nil || iss != accountID {
+		return errors.New("iss is not the service account")
+	}
+	sub, err := assertion.Claims.GetSubject()
+	if err != nil || sub != accountID {
+		return errors.New("sub is not the service account")
+	}
+
+	exp, err := assertion.Claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return errors.New("exp is missing")
+	}
+	if exp.Sub(now) > maxAssertionTTL {
+		return errors.New("exp is too far in the future")
+	}
+
+	return nil
+}
+
+// activeKey returns key of the kind and its account, ErrInvalidCredentials if either can't be used.
+func (s *ServiceAccounts) activeKey(
+	ctx context.Context,
+	keyID string,
+	kind models.ServiceAccountKeyKind,
+) (models.ServiceAccountKey, models.ServiceAccount, error) {
+	key, err := s.store.ServiceAccountKey(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, storage.ErrServiceAccountKeyNotFound) {
+			return models.ServiceAccountKey{}, models.ServiceAccount{}, ErrInvalidCredentials
+		}
+
+		return models.ServiceAccountKey{}, models.ServiceAccount{}, err
+	}
+	if key.Kind != kind || !key.RevokedAt.IsZero() {
+		return models.ServiceAccountKey{}, models.ServiceAccount{}, ErrInvalidCredentials
+	}
+
+	account, err := s.store.ServiceAccount(ctx, key.ServiceAccountID)
+	if err != nil {
+		if errors.Is(err, storage.ErrServiceAccountNotFound) {
+			return models.ServiceAccountKey{}, models.ServiceAccount{}, ErrInvalidCredentials
+		}
+
+		return models.ServiceAccountKey{}, models.ServiceAccount{}, err
+	}
+	if !account.DisabledAt.IsZero() {
+		return models.ServiceAccountKey{}, models.ServiceAccount{}, ErrInvalidCredentials
+	}
+
+	return key, account, nil
+}
+
+// issueToken records new session of the service account and returns its token.
+func (s *ServiceAccounts) issueToken(
+	ctx context.Context,
+	account models.ServiceAccount,
+	key models.ServiceAccountKey,
+	scopes []string,
+) (Token, error) {
+	if len(scopes) == 0 {
+		scopes = account.Scopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(account.Scopes, scope) {
+			return Token{}, ErrInvalidScope
+		}
+	}
+
+	app, err := s.apps.App(ctx, account.AppID)
+	if err != nil {
+		return Token{}, err
+	}
+
+	id, err := jwt.NewID(s.random)
+	if err != nil {
+		return Token{}, err
+	}
+
+	now := s.clock.Now().UTC()
+	session := models.Session{
+		ID:               id,
+		SSOSessionID:     id,
+		ServiceAccountID: account.ID,
+		AppID:            app.ID,
+		IP:               peer.IP(ctx),
+		IssuedAt:         now,
+		ExpiresAt:        now.Add(s.tokenTTL),
+	}
+	if loc, ok := geoip.FromContext(ctx); ok {
+		session.Country, session.City = loc.Country, loc.City
+	}
+
+	if err := s.sessions.SaveSession(ctx, session); err != nil {
+		return Token{}, err
+	}
+
+	token, err := jwt.NewServiceAccountToken(account, app, session, scopes, s.issuance.ForApp(app))
+	if err != nil {
+		return Token{}, err
+	}
+
+	s.log.Info("service account token issued",
+		slog.Int64("service_account_id", account.ID),
+		slog.String("key_id", key.ID),
+		slog.String("jti", id),
+	)
+
+	s.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionServiceAccountTokenIssued,
+		Subject: account.Name,
+		AppID:   account.AppID,
+		Payload: map[string]string{
+			"service_account_id": strconv.FormatInt(account.ID, 10),
+			"key_id":             key.ID,
+			"scopes":             strings.Join(scopes, " "),
+		},
+	})
+
+	return Token{Token: token, Scopes: scopes, ExpiresAt: session.ExpiresAt}, nil
+}
+
+// audit records change of the service account made by an operator calling AdminService.
+func (s *ServiceAccounts) audit(ctx context.Context, account models.ServiceAccount, payload map[string]string) {
+	payload["service_account_id"] = strconv.FormatInt(account.ID, 10)
+	payload["source"] = "admin_api"
+	if operator := peer.ClientCertName(ctx); operator != "" {
+		payload["operator"] = operator
+	}
+
+	event := models.AuditEvent{
+		Action:  models.AuditActionServiceAccountChanged,
+		Subject: account.Name,
+		AppID:   account.AppID,
+		Payload: payload,
+	}
+	// Admin authenticated by the access token.
+	if claims, ok := jwt.FromContext(ctx); ok {
+		event.ActorID = claims.UserID
+	}
+
+	s.auditor.Record(ctx, event)
+}
+
+// validScope tells whether scope is a non-empty token of printable characters, as scopes are space-separated
+// in scope claim.
+func validScope(scope string) bool {
+	if scope == "" || len(scope) > maxScopeLen {
+		return false
+	}
+
+	for _, r := range scope {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parsePublicKey parses PEM encoded public key of a supported type.
+func parsePublicKey(publicKey string) (any, error) {
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
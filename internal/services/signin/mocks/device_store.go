@@ -0,0 +1,143 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeviceStore is an autogenerated mock type for the DeviceStore type
+type DeviceStore struct {
+	mock.Mock
+}
+
+type DeviceStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeviceStore) EXPECT() *DeviceStore_Expecter {
+	return &DeviceStore_Expecter{mock: &_m.Mock}
+}
+
+// KnownDevices provides a mock function with given fields: ctx, userID
+func (_m *DeviceStore) KnownDevices(ctx context.Context, userID int64) ([]models.KnownDevice, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for KnownDevices")
+	}
+
+	var r0 []models.KnownDevice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.KnownDevice, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.KnownDevice); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.KnownDevice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeviceStore_KnownDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'KnownDevices'
+type DeviceStore_KnownDevices_Call struct {
+	*mock.Call
+}
+
+// KnownDevices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *DeviceStore_Expecter) KnownDevices(ctx interface{}, userID interface{}) *DeviceStore_KnownDevices_Call {
+	return &DeviceStore_KnownDevices_Call{Call: _e.mock.On("KnownDevices", ctx, userID)}
+}
+
+func (_c *DeviceStore_KnownDevices_Call) Run(run func(ctx context.Context, userID int64)) *DeviceStore_KnownDevices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *DeviceStore_KnownDevices_Call) Return(_a0 []models.KnownDevice, _a1 error) *DeviceStore_KnownDevices_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeviceStore_KnownDevices_Call) RunAndReturn(run func(context.Context, int64) ([]models.KnownDevice, error)) *DeviceStore_KnownDevices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveKnownDevice provides a mock function with given fields: ctx, device
+func (_m *DeviceStore) SaveKnownDevice(ctx context.Context, device models.KnownDevice) error {
+	ret := _m.Called(ctx, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveKnownDevice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.KnownDevice) error); ok {
+		r0 = rf(ctx, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeviceStore_SaveKnownDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveKnownDevice'
+type DeviceStore_SaveKnownDevice_Call struct {
+	*mock.Call
+}
+
+// SaveKnownDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - device models.KnownDevice
+func (_e *DeviceStore_Expecter) SaveKnownDevice(ctx interface{}, device interface{}) *DeviceStore_SaveKnownDevice_Call {
+	return &DeviceStore_SaveKnownDevice_Call{Call: _e.mock.On("SaveKnownDevice", ctx, device)}
+}
+
+func (_c *DeviceStore_SaveKnownDevice_Call) Run(run func(ctx context.Context, device models.KnownDevice)) *DeviceStore_SaveKnownDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.KnownDevice))
+	})
+	return _c
+}
+
+func (_c *DeviceStore_SaveKnownDevice_Call) Return(_a0 error) *DeviceStore_SaveKnownDevice_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DeviceStore_SaveKnownDevice_Call) RunAndReturn(run func(context.Context, models.KnownDevice) error) *DeviceStore_SaveKnownDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeviceStore creates a new instance of DeviceStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeviceStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeviceStore {
+	mock := &DeviceStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Notifier is an autogenerated mock type for the Notifier type
+type Notifier struct {
+	mock.Mock
+}
+
+type Notifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Notifier) EXPECT() *Notifier_Expecter {
+	return &Notifier_Expecter{mock: &_m.Mock}
+}
+
+// NewSignIn provides a mock function with given fields: ctx, email, device
+func (_m *Notifier) NewSignIn(ctx context.Context, email string, device models.KnownDevice) {
+	_m.Called(ctx, email, device)
+}
+
+// Notifier_NewSignIn_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewSignIn'
+type Notifier_NewSignIn_Call struct {
+	*mock.Call
+}
+
+// NewSignIn is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - device models.KnownDevice
+func (_e *Notifier_Expecter) NewSignIn(ctx interface{}, email interface{}, device interface{}) *Notifier_NewSignIn_Call {
+	return &Notifier_NewSignIn_Call{Call: _e.mock.On("NewSignIn", ctx, email, device)}
+}
+
+func (_c *Notifier_NewSignIn_Call) Run(run func(ctx context.Context, email string, device models.KnownDevice)) *Notifier_NewSignIn_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.KnownDevice))
+	})
+	return _c
+}
+
+func (_c *Notifier_NewSignIn_Call) Return() *Notifier_NewSignIn_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Notifier_NewSignIn_Call) RunAndReturn(run func(context.Context, string, models.KnownDevice)) *Notifier_NewSignIn_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SignInBlocked provides a mock function with given fields: ctx, email, device
+func (_m *Notifier) SignInBlocked(ctx context.Context, email string, device models.KnownDevice) {
+	_m.Called(ctx, email, device)
+}
+
+// Notifier_SignInBlocked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SignInBlocked'
+type Notifier_SignInBlocked_Call struct {
+	*mock.Call
+}
+
+// SignInBlocked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - device models.KnownDevice
+func (_e *Notifier_Expecter) SignInBlocked(ctx interface{}, email interface{}, device interface{}) *Notifier_SignInBlocked_Call {
+	return &Notifier_SignInBlocked_Call{Call: _e.mock.On("SignInBlocked", ctx, email, device)}
+}
+
+func (_c *Notifier_SignInBlocked_Call) Run(run func(ctx context.Context, email string, device models.KnownDevice)) *Notifier_SignInBlocked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.KnownDevice))
+	})
+	return _c
+}
+
+func (_c *Notifier_SignInBlocked_Call) Return() *Notifier_SignInBlocked_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Notifier_SignInBlocked_Call) RunAndReturn(run func(context.Context, string, models.KnownDevice)) *Notifier_SignInBlocked_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewNotifier creates a new instance of Notifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Notifier {
+	mock := &Notifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
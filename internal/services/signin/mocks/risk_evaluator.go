@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	risk "grpc-service-ref/internal/services/risk"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RiskEvaluator is an autogenerated mock type for the RiskEvaluator type
+type RiskEvaluator struct {
+	mock.Mock
+}
+
+type RiskEvaluator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RiskEvaluator) EXPECT() *RiskEvaluator_Expecter {
+	return &RiskEvaluator_Expecter{mock: &_m.Mock}
+}
+
+// Evaluate provides a mock function with given fields: ctx, attempt
+func (_m *RiskEvaluator) Evaluate(ctx context.Context, attempt risk.Attempt) (risk.Assessment, error) {
+	ret := _m.Called(ctx, attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Evaluate")
+	}
+
+	var r0 risk.Assessment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, risk.Attempt) (risk.Assessment, error)); ok {
+		return rf(ctx, attempt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, risk.Attempt) risk.Assessment); ok {
+		r0 = rf(ctx, attempt)
+	} else {
+		r0 = ret.Get(0).(risk.Assessment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, risk.Attempt) error); ok {
+		r1 = rf(ctx, attempt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RiskEvaluator_Evaluate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Evaluate'
+type RiskEvaluator_Evaluate_Call struct {
+	*mock.Call
+}
+
+// Evaluate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attempt risk.Attempt
+func (_e *RiskEvaluator_Expecter) Evaluate(ctx interface{}, attempt interface{}) *RiskEvaluator_Evaluate_Call {
+	return &RiskEvaluator_Evaluate_Call{Call: _e.mock.On("Evaluate", ctx, attempt)}
+}
+
+func (_c *RiskEvaluator_Evaluate_Call) Run(run func(ctx context.Context, attempt risk.Attempt)) *RiskEvaluator_Evaluate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(risk.Attempt))
+	})
+	return _c
+}
+
+func (_c *RiskEvaluator_Evaluate_Call) Return(_a0 risk.Assessment, _a1 error) *RiskEvaluator_Evaluate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RiskEvaluator_Evaluate_Call) RunAndReturn(run func(context.Context, risk.Attempt) (risk.Assessment, error)) *RiskEvaluator_Evaluate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRiskEvaluator creates a new instance of RiskEvaluator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRiskEvaluator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RiskEvaluator {
+	mock := &RiskEvaluator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,348 @@
+package signin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/services/auth"
+	"grpc-service-ref/internal/services/risk"
+	verificationService "grpc-service-ref/internal/services/verification"
+	"grpc-service-ref/internal/storage"
+)
+
+var (
+	ErrConfirmationRequired = errors.New("sign-in from new device must be confirmed")
+	ErrInvalidCode          = errors.New("invalid sign-in confirmation code")
+	ErrBlocked              = errors.New("sign-in is blocked as suspicious")
+)
+
+type DeviceStore interface {
+	KnownDevices(ctx context.Context, userID int64) ([]models.KnownDevice, error)
+	SaveKnownDevice(ctx context.Context, device models.KnownDevice) error
+}
+
+type Verifier interface {
+	StoreVerification(
+		ctx context.Context,
+		email string,
+		vType models.VerificationType,
+		code string,
+		expiresAt time.Time,
+	) (models.VerificationData, error)
+	Verify(
+		ctx context.Context,
+		email string,
+		vType models.VerificationType,
+		code string,
+		deleteVerificationAfterAtempt bool,
+	) (string, error)
+	Delivered(ctx context.Context, vType models.VerificationType)
+}
+
+type EmailSender interface {
+	SendEmail(
+		ctx context.Context,
+		subject string,
+		to []string,
+		content string,
+		cc []string,
+		bcc []string,
+		atachFiles []string,
+	) (messageID string, err error)
+}
+
+type Notifier interface {
+	NewSignIn(ctx context.Context, email string, device models.KnownDevice)
+	SignInBlocked(ctx context.Context, email string, device models.KnownDevice)
+}
+
+// RiskEvaluator scores risk of logins, see risk.Heuristic.
+type RiskEvaluator interface {
+	Evaluate(ctx context.Context, attempt risk.Attempt) (risk.Assessment, error)
+}
+
+type CodeFormats interface {
+	For(vType models.VerificationType) verification.CodeFormat
+}
+
+// SignIn detects logins from devices or countries the user didn't sign in from before.
+// Such logins are recorded to the audit log, the user is notified by email
+// and, if confirmation is required, the login must be confirmed by code sent to the email.
+// Risky logins, as scored by the risk evaluator, are confirmed the same way or blocked by the risk policy.
+type SignIn struct {
+	log         *slog.Logger
+	devices     DeviceStore
+	verifier    Verifier
+	mailer      EmailSender
+	notifier    Notifier
+	auditor     auth.Auditor
+	codeFormats CodeFormats
+	clock       clock.Clock
+	random      random.Randomizer
+	notify      bool
+	// requireConfirmation rejects sign-in from new device until it's confirmed by code.
+	requireConfirmation bool
+	// riskEvaluator is nil if risk evaluation is disabled.
+	riskEvaluator RiskEvaluator
+	riskPolicy    risk.Policy
+}
+
+func New(
+	log *slog.Logger,
+	devices DeviceStore,
+	verifier Verifier,
+	mailer EmailSender,
+	notifier Notifier,
+	auditor auth.Auditor,
+	codeFormats CodeFormats,
+	clock clock.Clock,
+	random random.Randomizer,
+	notify bool,
+	requireConfirmation bool,
+	riskEvaluator RiskEvaluator,
+	riskPolicy risk.Policy,
+) *SignIn {
+	return &SignIn{
+		log:                 log,
+		devices:             devices,
+		verifier:            verifier,
+		mailer:              mailer,
+		notifier:            notifier,
+		auditor:             auditor,
+		codeFormats:         codeFormats,
+		clock:               clock,
+		random:              random,
+		notify:              notify,
+		requireConfirmation: requireConfirmation,
+		riskEvaluator:       riskEvaluator,
+		riskPolicy:          riskPolicy,
+	}
+}
+
+// Check is called once user passed credentials check, it remembers the device the user signs in from.
+//
+// If the device or its country is new for the user and confirmation is required, code is emailed
+// and ErrConfirmationRequired is returned until the login is repeated with the code.
+// The first device of the user is trusted without confirmation.
+func (s *SignIn) Check(ctx context.Context, user models.User, appID int, device models.Device, code string) error {
+	const op = "SignIn.Check"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", user.ID),
+	)
+
+	known, err := s.devices.KnownDevices(ctx, user.ID)
+	if err != nil {
+		log.Error("failed to get known devices", sl.Err(err))
+
+		// Only notification would be missed, it's not worth failing the login.
+		if !s.requireConfirmation {
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := s.clock.Now().UTC()
+	seen := models.KnownDevice{
+		UserID:      user.ID,
+		Key:         deviceKey(device),
+		UserAgent:   device.UserAgent,
+		IP:          device.IP,
+		Country:     device.Country,
+		City:        device.City,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+
+	newDevice, newCountry := isNew(known, seen)
+	suspicious := len(known) > 0 && (newDevice || newCountry)
+
+	assessment := s.evaluateRisk(ctx, log, risk.Attempt{
+		User:       user,
+		AppID:      appID,
+		Device:     device,
+		History:    known,
+		NewDevice:  newDevice,
+		NewCountry: newCountry,
+		Time:       now,
+	})
+
+	if s.riskPolicy.Blocks(assessment.Score) {
+		log.Warn("sign-in blocked as suspicious", slog.Int("risk_score", assessment.Score))
+		s.auditRisk(ctx, models.AuditActionSignInBlocked, user, appID, device, assessment)
+		s.notifier.SignInBlocked(ctx, user.Email, seen)
+
+		return fmt.Errorf("%s: %w", op, ErrBlocked)
+	}
+
+	challenged := s.riskPolicy.Challenges(assessment.Score)
+	// Repeated login with the code is not audited again.
+	if challenged && code == "" {
+		s.auditRisk(ctx, models.AuditActionSignInChallenged, user, appID, device, assessment)
+	}
+
+	if (suspicious && s.requireConfirmation) || challenged {
+		if err := s.confirm(verificationService.WithApp(ctx, appID), log, user.Email, code); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, d := range known {
+		if d.Key == seen.Key {
+			seen.FirstSeenAt = d.FirstSeenAt
+		}
+	}
+
+	// Failing to save means the device is reported as new again next time, so it doesn't fail the login.
+	if err := s.devices.SaveKnownDevice(ctx, seen); err != nil {
+		log.Error("failed to save known device", sl.Err(err))
+	}
+
+	if !suspicious {
+		return nil
+	}
+
+	log.Info("sign-in from new device", slog.Bool("new_device", newDevice), slog.Bool("new_country", newCountry))
+
+	s.auditor.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionNewSignIn,
+		ActorID: user.ID,
+		Subject: user.Email,
+		AppID:   appID,
+		IP:      device.IP,
+		Payload: map[string]string{
+			"user_agent":  device.UserAgent,
+			"country":     device.Country,
+			"city":        device.City,
+			"new_device":  fmt.Sprint(newDevice),
+			"new_country": fmt.Sprint(newCountry),
+		},
+	})
+
+	if s.notify {
+		s.notifier.NewSignIn(ctx, user.Email, seen)
+	}
+
+	return nil
+}
+
+// evaluateRisk returns risk of the login, zero if evaluation is disabled or fails,
+// so evaluator outage doesn't lock users out.
+func (s *SignIn) evaluateRisk(ctx context.Context, log *slog.Logger, attempt risk.Attempt) risk.Assessment {
+	if s.riskEvaluator == nil {
+		return risk.Assessment{}
+	}
+
+	assessment, err := s.riskEvaluator.Evaluate(ctx, attempt)
+	if err != nil {
+		log.Error("failed to evaluate risk of sign-in", sl.Err(err))
+
+		return risk.Assessment{}
+	}
+
+	return assessment
+}
+
+func (s *SignIn) auditRisk(
+	ctx context.Context,
+	action models.AuditAction,
+	user models.User,
+	appID int,
+	device models.Device,
+	assessment risk.Assessment,
+) {
+	s.auditor.Record(ctx, models.AuditEvent{
+		Action:  action,
+		ActorID: user.ID,
+		Subject: user.Email,
+		AppID:   appID,
+		IP:      device.IP,
+		Payload: map[string]string{
+			"user_agent": device.UserAgent,
+			"country":    device.Country,
+			"city":       device.City,
+			"risk_score": strconv.Itoa(assessment.Score),
+			"reasons":    strings.Join(assessment.Reasons, ","),
+		},
+	})
+}
+
+// confirm checks code of the sign-in confirmation, or emails a new code if it's not passed.
+func (s *SignIn) confirm(ctx context.Context, log *slog.Logger, email string, code string) error {
+	if code == "" {
+		codeFormat := s.codeFormats.For(models.VerificationTypeSignIn)
+
+		newCode, err := codeFormat.Generate(s.random)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.verifier.StoreVerification(ctx, email, models.VerificationTypeSignIn, newCode, s.clock.Now().UTC().Add(codeFormat.TTL)); err != nil {
+			return err
+		}
+
+		if _, err := s.mailer.SendEmail(ctx, "Confirm sign-in from new device", []string{email}, newCode, []string{}, []string{}, []string{}); err != nil {
+			return err
+		}
+
+		s.verifier.Delivered(ctx, models.VerificationTypeSignIn)
+
+		log.Info("sign-in confirmation code sent")
+
+		return ErrConfirmationRequired
+	}
+
+	_, err := s.verifier.Verify(ctx, email, models.VerificationTypeSignIn, code, true)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, verificationService.CodesDiffer),
+		errors.Is(err, verificationService.TooManyAttempts),
+		errors.Is(err, storage.ErrVerificationExpired):
+		return ErrInvalidCode
+	default:
+		return err
+	}
+}
+
+// isNew reports whether the device and its country are not among known devices.
+// Unknown country is never new.
+func isNew(known []models.KnownDevice, device models.KnownDevice) (newDevice bool, newCountry bool) {
+	newDevice, newCountry = true, device.Country != ""
+
+	for _, d := range known {
+		if d.Key == device.Key {
+			newDevice = false
+		}
+		if d.Country == device.Country {
+			newCountry = false
+		}
+	}
+
+	return newDevice, newCountry
+}
+
+// deviceKey identifies the device by ID the client sent, or by its user agent otherwise.
+func deviceKey(device models.Device) string {
+	if device.ID != "" {
+		return "id:" + device.ID
+	}
+
+	sum := sha256.Sum256([]byte(device.UserAgent))
+
+	return "ua:" + hex.EncodeToString(sum[:16])
+}
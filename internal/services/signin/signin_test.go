@@ -0,0 +1,281 @@
+package signin
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	mathrand "math/rand"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/lib/verification"
+	authmocks "grpc-service-ref/internal/services/auth/mocks"
+	"grpc-service-ref/internal/services/risk"
+	"grpc-service-ref/internal/services/signin/mocks"
+	verificationService "grpc-service-ref/internal/services/verification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+)
+
+var testUser = models.User{ID: 42, Email: "user@example.com"}
+
+var testFormats = verification.CodeFormats{
+	Default: verification.CodeFormat{Len: 6, Charset: verification.CharsetDigits, TTL: 15 * time.Minute},
+}
+
+func knownDevice(device models.Device) models.KnownDevice {
+	return models.KnownDevice{
+		UserID:      testUser.ID,
+		Key:         deviceKey(device),
+		UserAgent:   device.UserAgent,
+		IP:          device.IP,
+		Country:     device.Country,
+		FirstSeenAt: testNow.Add(-24 * time.Hour),
+		LastSeenAt:  testNow.Add(-time.Hour),
+	}
+}
+
+func auditAction(action models.AuditAction) any {
+	return mock.MatchedBy(func(event models.AuditEvent) bool { return event.Action == action })
+}
+
+func TestDeviceKey(t *testing.T) {
+	chrome := models.Device{UserAgent: "Chrome"}
+
+	assert.Equal(t, "id:device-1", deviceKey(models.Device{ID: "device-1", UserAgent: "Chrome"}))
+	assert.Equal(t, deviceKey(chrome), deviceKey(models.Device{UserAgent: "Chrome", IP: "203.0.113.1"}), "device is the same from another IP")
+	assert.NotEqual(t, deviceKey(chrome), deviceKey(models.Device{UserAgent: "Firefox"}))
+}
+
+func TestIsNew(t *testing.T) {
+	known := []models.KnownDevice{
+		{Key: "id:laptop", Country: "DE"},
+		{Key: "id:phone", Country: "FR"},
+	}
+
+	tests := []struct {
+		name           string
+		device         models.KnownDevice
+		wantNewDevice  bool
+		wantNewCountry bool
+	}{
+		{"known", models.KnownDevice{Key: "id:laptop", Country: "DE"}, false, false},
+		{"known device in country of another device", models.KnownDevice{Key: "id:laptop", Country: "FR"}, false, false},
+		{"new device", models.KnownDevice{Key: "id:tablet", Country: "DE"}, true, false},
+		{"new country", models.KnownDevice{Key: "id:laptop", Country: "US"}, false, true},
+		{"new device and country", models.KnownDevice{Key: "id:tablet", Country: "US"}, true, true},
+		{"unknown country", models.KnownDevice{Key: "id:laptop"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newDevice, newCountry := isNew(known, tt.device)
+			assert.Equal(t, tt.wantNewDevice, newDevice)
+			assert.Equal(t, tt.wantNewCountry, newCountry)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	laptop := models.Device{ID: "laptop", UserAgent: "Chrome", IP: "203.0.113.1", Country: "DE"}
+	tablet := models.Device{ID: "tablet", UserAgent: "Safari", IP: "198.51.100.1", Country: "DE"}
+	travelling := models.Device{ID: "laptop", UserAgent: "Chrome", IP: "203.0.113.1", Country: "US"}
+
+	// Mocks fail on unexpected calls, e.g. saving the device which must be confirmed first. Failing to get known
+	// devices doesn't fail login unless it must be confirmed.
+	tests := []struct {
+		name                string
+		notify              bool
+		requireConfirmation bool
+		risk                bool
+		riskPolicy          risk.Policy
+		device              models.Device
+		code                string
+		setup               func(t *testing.T, devices *mocks.DeviceStore, verifier *mocks.Verifier, mailer *mocks.EmailSender,
+			notifier *mocks.Notifier, auditor *authmocks.Auditor, evaluator *mocks.RiskEvaluator)
+		wantErr error
+	}{
+		{
+			name:                "first device is trusted",
+			notify:              true,
+			requireConfirmation: true,
+			device:              laptop,
+			setup: func(_ *testing.T, devices *mocks.DeviceStore, _ *mocks.Verifier, _ *mocks.EmailSender,
+				_ *mocks.Notifier, _ *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return(nil, nil)
+				devices.EXPECT().SaveKnownDevice(ctx, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:                "known device",
+			notify:              true,
+			requireConfirmation: true,
+			device:              laptop,
+			setup: func(t *testing.T, devices *mocks.DeviceStore, _ *mocks.Verifier, _ *mocks.EmailSender,
+				_ *mocks.Notifier, _ *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				known := knownDevice(laptop)
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return([]models.KnownDevice{known}, nil)
+				devices.EXPECT().SaveKnownDevice(ctx, mock.Anything).
+					Run(func(_ context.Context, device models.KnownDevice) {
+						assert.Equal(t, known.FirstSeenAt, device.FirstSeenAt, "first sign-in is kept")
+						assert.Equal(t, testNow, device.LastSeenAt)
+					}).Return(nil).Once()
+			},
+		},
+		{
+			name:   "new device is audited and notified",
+			notify: true,
+			device: tablet,
+			setup: func(t *testing.T, devices *mocks.DeviceStore, _ *mocks.Verifier, _ *mocks.EmailSender,
+				notifier *mocks.Notifier, auditor *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return([]models.KnownDevice{knownDevice(laptop)}, nil)
+				devices.EXPECT().SaveKnownDevice(ctx, mock.Anything).Return(nil).Once()
+				auditor.EXPECT().Record(ctx, auditAction(models.AuditActionNewSignIn)).Once()
+				notifier.EXPECT().NewSignIn(ctx, testUser.Email, mock.Anything).
+					Run(func(_ context.Context, _ string, device models.KnownDevice) {
+						assert.Equal(t, deviceKey(tablet), device.Key)
+						assert.Equal(t, testNow, device.FirstSeenAt)
+					}).Once()
+			},
+		},
+		{
+			name:   "new country without notification",
+			device: travelling,
+			setup: func(_ *testing.T, devices *mocks.DeviceStore, _ *mocks.Verifier, _ *mocks.EmailSender,
+				_ *mocks.Notifier, auditor *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return([]models.KnownDevice{knownDevice(laptop)}, nil)
+				devices.EXPECT().SaveKnownDevice(ctx, mock.Anything).Return(nil).Once()
+				auditor.EXPECT().Record(ctx, mock.MatchedBy(func(event models.AuditEvent) bool {
+					return event.Payload["new_device"] == "false" && event.Payload["new_country"] == "true"
+				})).Once()
+			},
+		},
+		{
+			name:                "new device must be confirmed",
+			requireConfirmation: true,
+			device:              tablet,
+			setup: func(t *testing.T, devices *mocks.DeviceStore, verifier *mocks.Verifier, mailer *mocks.EmailSender,
+				_ *mocks.Notifier, _ *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return([]models.KnownDevice{knownDevice(laptop)}, nil)
+
+				var code string
+				verifier.EXPECT().StoreVerification(mock.Anything, testUser.Email, models.VerificationTypeSignIn, mock.Anything, testNow.Add(15*time.Minute)).
+					RunAndReturn(func(_ context.Context, _ string, _ models.VerificationType, c string, _ time.Time) (models.VerificationData, error) {
+						code = c
+
+						return models.VerificationData{}, nil
+					}).Once()
+				mailer.EXPECT().SendEmail(mock.Anything, mock.Anything, []string{testUser.Email}, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					RunAndReturn(func(_ context.Context, _ string, _ []string, content string, _ []string, _ []string, _ []string) (string, error) {
+						assert.Len(t, code, 6)
+						assert.Equal(t, code, content)
+
+						return "message-id", nil
+					}).Once()
+				verifier.EXPECT().Delivered(mock.Anything, models.VerificationTypeSignIn).Once()
+			},
+			wantErr: ErrConfirmationRequired,
+		},
+		{
+			name:                "new device confirmed by code",
+			requireConfirmation: true,
+			device:              tablet,
+			code:                "123456",
+			setup: func(_ *testing.T, devices *mocks.DeviceStore, verifier *mocks.Verifier, _ *mocks.EmailSender,
+				_ *mocks.Notifier, auditor *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return([]models.KnownDevice{knownDevice(laptop)}, nil)
+				verifier.EXPECT().Verify(mock.Anything, testUser.Email, models.VerificationTypeSignIn, "123456", true).Return(testUser.Email, nil).Once()
+				devices.EXPECT().SaveKnownDevice(ctx, mock.Anything).Return(nil).Once()
+				auditor.EXPECT().Record(ctx, auditAction(models.AuditActionNewSignIn)).Once()
+			},
+		},
+		{
+			name:                "new device with wrong code",
+			requireConfirmation: true,
+			device:              tablet,
+			code:                "000000",
+			setup: func(_ *testing.T, devices *mocks.DeviceStore, verifier *mocks.Verifier, _ *mocks.EmailSender,
+				_ *mocks.Notifier, _ *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return([]models.KnownDevice{knownDevice(laptop)}, nil)
+				verifier.EXPECT().Verify(mock.Anything, testUser.Email, models.VerificationTypeSignIn, "000000", true).
+					Return("", verificationService.CodesDiffer).Once()
+			},
+			wantErr: ErrInvalidCode,
+		},
+		{
+			name:       "risky sign-in is blocked",
+			risk:       true,
+			riskPolicy: risk.Policy{ChallengeScore: 50, BlockScore: 80},
+			device:     tablet,
+			setup: func(_ *testing.T, devices *mocks.DeviceStore, _ *mocks.Verifier, _ *mocks.EmailSender,
+				notifier *mocks.Notifier, auditor *authmocks.Auditor, evaluator *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return([]models.KnownDevice{knownDevice(laptop)}, nil)
+				evaluator.EXPECT().Evaluate(ctx, mock.MatchedBy(func(attempt risk.Attempt) bool {
+					return attempt.NewDevice && !attempt.NewCountry && attempt.Time.Equal(testNow)
+				})).Return(risk.Assessment{Score: 90, Reasons: []string{"impossible_travel"}}, nil)
+				auditor.EXPECT().Record(ctx, auditAction(models.AuditActionSignInBlocked)).Once()
+				notifier.EXPECT().SignInBlocked(ctx, testUser.Email, mock.Anything).Once()
+			},
+			wantErr: ErrBlocked,
+		},
+		{
+			name:       "failing risk evaluation doesn't block",
+			risk:       true,
+			riskPolicy: risk.Policy{BlockScore: 1},
+			device:     laptop,
+			setup: func(_ *testing.T, devices *mocks.DeviceStore, _ *mocks.Verifier, _ *mocks.EmailSender,
+				_ *mocks.Notifier, _ *authmocks.Auditor, evaluator *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return([]models.KnownDevice{knownDevice(laptop)}, nil)
+				evaluator.EXPECT().Evaluate(ctx, mock.Anything).Return(risk.Assessment{}, assert.AnError)
+				devices.EXPECT().SaveKnownDevice(ctx, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:   "failing to get known devices",
+			notify: true,
+			device: laptop,
+			setup: func(_ *testing.T, devices *mocks.DeviceStore, _ *mocks.Verifier, _ *mocks.EmailSender,
+				_ *mocks.Notifier, _ *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:                "failing to get known devices with confirmation",
+			requireConfirmation: true,
+			device:              laptop,
+			setup: func(_ *testing.T, devices *mocks.DeviceStore, _ *mocks.Verifier, _ *mocks.EmailSender,
+				_ *mocks.Notifier, _ *authmocks.Auditor, _ *mocks.RiskEvaluator) {
+				devices.EXPECT().KnownDevices(ctx, testUser.ID).Return(nil, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			devices, verifier, mailer := mocks.NewDeviceStore(t), mocks.NewVerifier(t), mocks.NewEmailSender(t)
+			notifier, auditor, evaluator := mocks.NewNotifier(t), authmocks.NewAuditor(t), mocks.NewRiskEvaluator(t)
+			tt.setup(t, devices, verifier, mailer, notifier, auditor, evaluator)
+
+			var riskEvaluator RiskEvaluator
+			if tt.risk {
+				riskEvaluator = evaluator
+			}
+
+			s := New(testLog, devices, verifier, mailer, notifier, auditor, testFormats, clock.NewFake(testNow),
+				random.New(mathrand.New(mathrand.NewSource(1))), tt.notify, tt.requireConfirmation, riskEvaluator, tt.riskPolicy)
+
+			assert.ErrorIs(t, s.Check(ctx, testUser, 1, tt.device, tt.code), tt.wantErr)
+		})
+	}
+}
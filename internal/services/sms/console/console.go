@@ -0,0 +1,38 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// ConsoleSender is an SMSSender for local development.
+// The message is written to the log and to out instead of being sent.
+type ConsoleSender struct {
+	log *slog.Logger
+	out io.Writer
+}
+
+func New(
+	log *slog.Logger,
+	out io.Writer,
+) *ConsoleSender {
+	return &ConsoleSender{
+		log: log,
+		out: out,
+	}
+}
+
+func (sender *ConsoleSender) SendSMS(ctx context.Context, to string, text string) error {
+	const op = "Console.SendSMS"
+
+	sender.log.With(slog.String("op", op)).
+		Info("printing sms to console instead of sending", slog.String("to", to))
+
+	if _, err := fmt.Fprintf(sender.out, "\nSMS to %s: %s\n\n", to, text); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package twilio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const apiURL = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+
+// TwilioSender sends SMS with Twilio Messages API.
+type TwilioSender struct {
+	log        *slog.Logger
+	client     *http.Client
+	accountSID string
+	authToken  string
+	from       string
+}
+
+func New(
+	log *slog.Logger,
+	accountSID string,
+	authToken string,
+	from string,
+	timeout time.Duration,
+) *TwilioSender {
+	return &TwilioSender{
+		log:        log,
+		client:     &http.Client{Timeout: timeout},
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+	}
+}
+
+func (sender *TwilioSender) SendSMS(ctx context.Context, to string, text string) error {
+	const op = "Twilio.SendSMS"
+
+	log := sender.log.With(
+		slog.String("op", op),
+	)
+
+	form := url.Values{
+		"To":   {to},
+		"From": {sender.from},
+		"Body": {text},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(apiURL, sender.accountSID), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	req.SetBasicAuth(sender.accountSID, sender.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := sender.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		log.Error("twilio rejected sms", slog.Int("status", resp.StatusCode), slog.String("body", string(body)))
+
+		return fmt.Errorf("%s: unexpected status %d", op, resp.StatusCode)
+	}
+
+	log.Info("sms sent")
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Provider is an autogenerated mock type for the Provider type
+type Provider struct {
+	mock.Mock
+}
+
+type Provider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Provider) EXPECT() *Provider_Expecter {
+	return &Provider_Expecter{mock: &_m.Mock}
+}
+
+// AuditStats provides a mock function with given fields: ctx, since, until
+func (_m *Provider) AuditStats(ctx context.Context, since time.Time, until time.Time) (models.Stats, error) {
+	ret := _m.Called(ctx, since, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuditStats")
+	}
+
+	var r0 models.Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) (models.Stats, error)); ok {
+		return rf(ctx, since, until)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) models.Stats); ok {
+		r0 = rf(ctx, since, until)
+	} else {
+		r0 = ret.Get(0).(models.Stats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, since, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Provider_AuditStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuditStats'
+type Provider_AuditStats_Call struct {
+	*mock.Call
+}
+
+// AuditStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - since time.Time
+//   - until time.Time
+func (_e *Provider_Expecter) AuditStats(ctx interface{}, since interface{}, until interface{}) *Provider_AuditStats_Call {
+	return &Provider_AuditStats_Call{Call: _e.mock.On("AuditStats", ctx, since, until)}
+}
+
+func (_c *Provider_AuditStats_Call) Run(run func(ctx context.Context, since time.Time, until time.Time)) *Provider_AuditStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Provider_AuditStats_Call) Return(_a0 models.Stats, _a1 error) *Provider_AuditStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Provider_AuditStats_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) (models.Stats, error)) *Provider_AuditStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerificationStats provides a mock function with given fields: ctx, since, until
+func (_m *Provider) VerificationStats(ctx context.Context, since time.Time, until time.Time) ([]models.VerificationFunnel, error) {
+	ret := _m.Called(ctx, since, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerificationStats")
+	}
+
+	var r0 []models.VerificationFunnel
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]models.VerificationFunnel, error)); ok {
+		return rf(ctx, since, until)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []models.VerificationFunnel); ok {
+		r0 = rf(ctx, since, until)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.VerificationFunnel)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, since, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Provider_VerificationStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerificationStats'
+type Provider_VerificationStats_Call struct {
+	*mock.Call
+}
+
+// VerificationStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - since time.Time
+//   - until time.Time
+func (_e *Provider_Expecter) VerificationStats(ctx interface{}, since interface{}, until interface{}) *Provider_VerificationStats_Call {
+	return &Provider_VerificationStats_Call{Call: _e.mock.On("VerificationStats", ctx, since, until)}
+}
+
+func (_c *Provider_VerificationStats_Call) Run(run func(ctx context.Context, since time.Time, until time.Time)) *Provider_VerificationStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Provider_VerificationStats_Call) Return(_a0 []models.VerificationFunnel, _a1 error) *Provider_VerificationStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Provider_VerificationStats_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]models.VerificationFunnel, error)) *Provider_VerificationStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewProvider creates a new instance of Provider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Provider {
+	mock := &Provider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/ttlcache"
+)
+
+// maxPeriods bounds number of cached periods, callers ask for a few fixed ones.
+const maxPeriods = 16
+
+type Provider interface {
+	AuditStats(ctx context.Context, since time.Time, until time.Time) (models.Stats, error)
+	VerificationStats(ctx context.Context, since time.Time, until time.Time) ([]models.VerificationFunnel, error)
+}
+
+// Stats reports registration, login and verification statistics to operators.
+// Aggregating scans the audit log, so stats are cached per period and lag behind by up to the cache TTL.
+// Verifications are counted per hour, so the period of their funnels starts at the hour of its start.
+type Stats struct {
+	log      *slog.Logger
+	provider Provider
+	clock    clock.Clock
+	cache    *ttlcache.Cache[time.Duration, models.Stats]
+}
+
+// New returns Stats caching stats for ttl, zero ttl disables caching.
+func New(log *slog.Logger, provider Provider, clock clock.Clock, ttl time.Duration) *Stats {
+	return &Stats{
+		log:      log,
+		provider: provider,
+		clock:    clock,
+		cache:    ttlcache.New[time.Duration, models.Stats]("stats", ttl, maxPeriods),
+	}
+}
+
+// Last returns stats over the period ending now, e.g. last 24 hours.
+func (s *Stats) Last(ctx context.Context, period time.Duration) (models.Stats, error) {
+	const op = "Stats.Last"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Duration("period", period),
+	)
+
+	stats, err := s.cache.Load(period, func() (models.Stats, error) {
+		until := s.clock.Now().UTC()
+
+		stats, err := s.provider.AuditStats(ctx, until.Add(-period), until)
+		if err != nil {
+			return models.Stats{}, err
+		}
+
+		stats.Verifications, err = s.provider.VerificationStats(ctx, until.Add(-period), until)
+		if err != nil {
+			return models.Stats{}, err
+		}
+
+		return stats, nil
+	})
+	if err != nil {
+		log.Error("failed to aggregate stats", sl.Err(err))
+
+		return models.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PendingRegistrationActivator is an autogenerated mock type for the PendingRegistrationActivator type
+type PendingRegistrationActivator struct {
+	mock.Mock
+}
+
+type PendingRegistrationActivator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PendingRegistrationActivator) EXPECT() *PendingRegistrationActivator_Expecter {
+	return &PendingRegistrationActivator_Expecter{mock: &_m.Mock}
+}
+
+// ActivatePendingRegistration provides a mock function with given fields: ctx, email
+func (_m *PendingRegistrationActivator) ActivatePendingRegistration(ctx context.Context, email string) (int64, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ActivatePendingRegistration")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PendingRegistrationActivator_ActivatePendingRegistration_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ActivatePendingRegistration'
+type PendingRegistrationActivator_ActivatePendingRegistration_Call struct {
+	*mock.Call
+}
+
+// ActivatePendingRegistration is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *PendingRegistrationActivator_Expecter) ActivatePendingRegistration(ctx interface{}, email interface{}) *PendingRegistrationActivator_ActivatePendingRegistration_Call {
+	return &PendingRegistrationActivator_ActivatePendingRegistration_Call{Call: _e.mock.On("ActivatePendingRegistration", ctx, email)}
+}
+
+func (_c *PendingRegistrationActivator_ActivatePendingRegistration_Call) Run(run func(ctx context.Context, email string)) *PendingRegistrationActivator_ActivatePendingRegistration_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *PendingRegistrationActivator_ActivatePendingRegistration_Call) Return(uid int64, err error) *PendingRegistrationActivator_ActivatePendingRegistration_Call {
+	_c.Call.Return(uid, err)
+	return _c
+}
+
+func (_c *PendingRegistrationActivator_ActivatePendingRegistration_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *PendingRegistrationActivator_ActivatePendingRegistration_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPendingRegistrationActivator creates a new instance of PendingRegistrationActivator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPendingRegistrationActivator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PendingRegistrationActivator {
+	mock := &PendingRegistrationActivator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
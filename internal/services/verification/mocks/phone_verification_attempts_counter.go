@@ -0,0 +1,93 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhoneVerificationAttemptsCounter is an autogenerated mock type for the PhoneVerificationAttemptsCounter type
+type PhoneVerificationAttemptsCounter struct {
+	mock.Mock
+}
+
+type PhoneVerificationAttemptsCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PhoneVerificationAttemptsCounter) EXPECT() *PhoneVerificationAttemptsCounter_Expecter {
+	return &PhoneVerificationAttemptsCounter_Expecter{mock: &_m.Mock}
+}
+
+// IncrementPhoneVerificationAttempts provides a mock function with given fields: ctx, email
+func (_m *PhoneVerificationAttemptsCounter) IncrementPhoneVerificationAttempts(ctx context.Context, email string) (int, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementPhoneVerificationAttempts")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementPhoneVerificationAttempts'
+type PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call struct {
+	*mock.Call
+}
+
+// IncrementPhoneVerificationAttempts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *PhoneVerificationAttemptsCounter_Expecter) IncrementPhoneVerificationAttempts(ctx interface{}, email interface{}) *PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call {
+	return &PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call{Call: _e.mock.On("IncrementPhoneVerificationAttempts", ctx, email)}
+}
+
+func (_c *PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call) Run(run func(ctx context.Context, email string)) *PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call) Return(attempts int, err error) *PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call {
+	_c.Call.Return(attempts, err)
+	return _c
+}
+
+func (_c *PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call) RunAndReturn(run func(context.Context, string) (int, error)) *PhoneVerificationAttemptsCounter_IncrementPhoneVerificationAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPhoneVerificationAttemptsCounter creates a new instance of PhoneVerificationAttemptsCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhoneVerificationAttemptsCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhoneVerificationAttemptsCounter {
+	mock := &PhoneVerificationAttemptsCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,83 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhoneVerificationDeleter is an autogenerated mock type for the PhoneVerificationDeleter type
+type PhoneVerificationDeleter struct {
+	mock.Mock
+}
+
+type PhoneVerificationDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PhoneVerificationDeleter) EXPECT() *PhoneVerificationDeleter_Expecter {
+	return &PhoneVerificationDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeletePhoneVerification provides a mock function with given fields: ctx, email
+func (_m *PhoneVerificationDeleter) DeletePhoneVerification(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePhoneVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PhoneVerificationDeleter_DeletePhoneVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePhoneVerification'
+type PhoneVerificationDeleter_DeletePhoneVerification_Call struct {
+	*mock.Call
+}
+
+// DeletePhoneVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *PhoneVerificationDeleter_Expecter) DeletePhoneVerification(ctx interface{}, email interface{}) *PhoneVerificationDeleter_DeletePhoneVerification_Call {
+	return &PhoneVerificationDeleter_DeletePhoneVerification_Call{Call: _e.mock.On("DeletePhoneVerification", ctx, email)}
+}
+
+func (_c *PhoneVerificationDeleter_DeletePhoneVerification_Call) Run(run func(ctx context.Context, email string)) *PhoneVerificationDeleter_DeletePhoneVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *PhoneVerificationDeleter_DeletePhoneVerification_Call) Return(_a0 error) *PhoneVerificationDeleter_DeletePhoneVerification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PhoneVerificationDeleter_DeletePhoneVerification_Call) RunAndReturn(run func(context.Context, string) error) *PhoneVerificationDeleter_DeletePhoneVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPhoneVerificationDeleter creates a new instance of PhoneVerificationDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhoneVerificationDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhoneVerificationDeleter {
+	mock := &PhoneVerificationDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhoneVerificationProvider is an autogenerated mock type for the PhoneVerificationProvider type
+type PhoneVerificationProvider struct {
+	mock.Mock
+}
+
+type PhoneVerificationProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PhoneVerificationProvider) EXPECT() *PhoneVerificationProvider_Expecter {
+	return &PhoneVerificationProvider_Expecter{mock: &_m.Mock}
+}
+
+// PhoneVerification provides a mock function with given fields: ctx, email
+func (_m *PhoneVerificationProvider) PhoneVerification(ctx context.Context, email string) (models.PhoneVerificationData, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PhoneVerification")
+	}
+
+	var r0 models.PhoneVerificationData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.PhoneVerificationData, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.PhoneVerificationData); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(models.PhoneVerificationData)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PhoneVerificationProvider_PhoneVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PhoneVerification'
+type PhoneVerificationProvider_PhoneVerification_Call struct {
+	*mock.Call
+}
+
+// PhoneVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *PhoneVerificationProvider_Expecter) PhoneVerification(ctx interface{}, email interface{}) *PhoneVerificationProvider_PhoneVerification_Call {
+	return &PhoneVerificationProvider_PhoneVerification_Call{Call: _e.mock.On("PhoneVerification", ctx, email)}
+}
+
+func (_c *PhoneVerificationProvider_PhoneVerification_Call) Run(run func(ctx context.Context, email string)) *PhoneVerificationProvider_PhoneVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *PhoneVerificationProvider_PhoneVerification_Call) Return(verificationData models.PhoneVerificationData, err error) *PhoneVerificationProvider_PhoneVerification_Call {
+	_c.Call.Return(verificationData, err)
+	return _c
+}
+
+func (_c *PhoneVerificationProvider_PhoneVerification_Call) RunAndReturn(run func(context.Context, string) (models.PhoneVerificationData, error)) *PhoneVerificationProvider_PhoneVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPhoneVerificationProvider creates a new instance of PhoneVerificationProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhoneVerificationProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhoneVerificationProvider {
+	mock := &PhoneVerificationProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,87 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhoneVerificationSaver is an autogenerated mock type for the PhoneVerificationSaver type
+type PhoneVerificationSaver struct {
+	mock.Mock
+}
+
+type PhoneVerificationSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PhoneVerificationSaver) EXPECT() *PhoneVerificationSaver_Expecter {
+	return &PhoneVerificationSaver_Expecter{mock: &_m.Mock}
+}
+
+// StorePhoneVerification provides a mock function with given fields: ctx, email, phone, code, expiresAt
+func (_m *PhoneVerificationSaver) StorePhoneVerification(ctx context.Context, email string, phone string, code string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, email, phone, code, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StorePhoneVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) error); ok {
+		r0 = rf(ctx, email, phone, code, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PhoneVerificationSaver_StorePhoneVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StorePhoneVerification'
+type PhoneVerificationSaver_StorePhoneVerification_Call struct {
+	*mock.Call
+}
+
+// StorePhoneVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - phone string
+//   - code string
+//   - expiresAt time.Time
+func (_e *PhoneVerificationSaver_Expecter) StorePhoneVerification(ctx interface{}, email interface{}, phone interface{}, code interface{}, expiresAt interface{}) *PhoneVerificationSaver_StorePhoneVerification_Call {
+	return &PhoneVerificationSaver_StorePhoneVerification_Call{Call: _e.mock.On("StorePhoneVerification", ctx, email, phone, code, expiresAt)}
+}
+
+func (_c *PhoneVerificationSaver_StorePhoneVerification_Call) Run(run func(ctx context.Context, email string, phone string, code string, expiresAt time.Time)) *PhoneVerificationSaver_StorePhoneVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PhoneVerificationSaver_StorePhoneVerification_Call) Return(_a0 error) *PhoneVerificationSaver_StorePhoneVerification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PhoneVerificationSaver_StorePhoneVerification_Call) RunAndReturn(run func(context.Context, string, string, string, time.Time) error) *PhoneVerificationSaver_StorePhoneVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPhoneVerificationSaver creates a new instance of PhoneVerificationSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhoneVerificationSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhoneVerificationSaver {
+	mock := &PhoneVerificationSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
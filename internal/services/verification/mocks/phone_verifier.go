@@ -0,0 +1,94 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhoneVerifier is an autogenerated mock type for the PhoneVerifier type
+type PhoneVerifier struct {
+	mock.Mock
+}
+
+type PhoneVerifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PhoneVerifier) EXPECT() *PhoneVerifier_Expecter {
+	return &PhoneVerifier_Expecter{mock: &_m.Mock}
+}
+
+// VerifyPhone provides a mock function with given fields: ctx, email, phone
+func (_m *PhoneVerifier) VerifyPhone(ctx context.Context, email string, phone string) (int64, error) {
+	ret := _m.Called(ctx, email, phone)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyPhone")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int64, error)); ok {
+		return rf(ctx, email, phone)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, email, phone)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, phone)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PhoneVerifier_VerifyPhone_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyPhone'
+type PhoneVerifier_VerifyPhone_Call struct {
+	*mock.Call
+}
+
+// VerifyPhone is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - phone string
+func (_e *PhoneVerifier_Expecter) VerifyPhone(ctx interface{}, email interface{}, phone interface{}) *PhoneVerifier_VerifyPhone_Call {
+	return &PhoneVerifier_VerifyPhone_Call{Call: _e.mock.On("VerifyPhone", ctx, email, phone)}
+}
+
+func (_c *PhoneVerifier_VerifyPhone_Call) Run(run func(ctx context.Context, email string, phone string)) *PhoneVerifier_VerifyPhone_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *PhoneVerifier_VerifyPhone_Call) Return(uid int64, err error) *PhoneVerifier_VerifyPhone_Call {
+	_c.Call.Return(uid, err)
+	return _c
+}
+
+func (_c *PhoneVerifier_VerifyPhone_Call) RunAndReturn(run func(context.Context, string, string) (int64, error)) *PhoneVerifier_VerifyPhone_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPhoneVerifier creates a new instance of PhoneVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPhoneVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PhoneVerifier {
+	mock := &PhoneVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// StatsCounter is an autogenerated mock type for the StatsCounter type
+type StatsCounter struct {
+	mock.Mock
+}
+
+type StatsCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *StatsCounter) EXPECT() *StatsCounter_Expecter {
+	return &StatsCounter_Expecter{mock: &_m.Mock}
+}
+
+// CountVerificationEvent provides a mock function with given fields: ctx, vType, appID, event, at
+func (_m *StatsCounter) CountVerificationEvent(ctx context.Context, vType models.VerificationType, appID int, event models.VerificationEvent, at time.Time) error {
+	ret := _m.Called(ctx, vType, appID, event, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountVerificationEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.VerificationType, int, models.VerificationEvent, time.Time) error); ok {
+		r0 = rf(ctx, vType, appID, event, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StatsCounter_CountVerificationEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountVerificationEvent'
+type StatsCounter_CountVerificationEvent_Call struct {
+	*mock.Call
+}
+
+// CountVerificationEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - vType models.VerificationType
+//   - appID int
+//   - event models.VerificationEvent
+//   - at time.Time
+func (_e *StatsCounter_Expecter) CountVerificationEvent(ctx interface{}, vType interface{}, appID interface{}, event interface{}, at interface{}) *StatsCounter_CountVerificationEvent_Call {
+	return &StatsCounter_CountVerificationEvent_Call{Call: _e.mock.On("CountVerificationEvent", ctx, vType, appID, event, at)}
+}
+
+func (_c *StatsCounter_CountVerificationEvent_Call) Run(run func(ctx context.Context, vType models.VerificationType, appID int, event models.VerificationEvent, at time.Time)) *StatsCounter_CountVerificationEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.VerificationType), args[2].(int), args[3].(models.VerificationEvent), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *StatsCounter_CountVerificationEvent_Call) Return(_a0 error) *StatsCounter_CountVerificationEvent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StatsCounter_CountVerificationEvent_Call) RunAndReturn(run func(context.Context, models.VerificationType, int, models.VerificationEvent, time.Time) error) *StatsCounter_CountVerificationEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStatsCounter creates a new instance of StatsCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatsCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StatsCounter {
+	mock := &StatsCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VerificationAttemptsCounter is an autogenerated mock type for the VerificationAttemptsCounter type
+type VerificationAttemptsCounter struct {
+	mock.Mock
+}
+
+type VerificationAttemptsCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *VerificationAttemptsCounter) EXPECT() *VerificationAttemptsCounter_Expecter {
+	return &VerificationAttemptsCounter_Expecter{mock: &_m.Mock}
+}
+
+// IncrementVerificationAttempts provides a mock function with given fields: ctx, email, vType
+func (_m *VerificationAttemptsCounter) IncrementVerificationAttempts(ctx context.Context, email string, vType models.VerificationType) (int, error) {
+	ret := _m.Called(ctx, email, vType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementVerificationAttempts")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType) (int, error)); ok {
+		return rf(ctx, email, vType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType) int); ok {
+		r0 = rf(ctx, email, vType)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.VerificationType) error); ok {
+		r1 = rf(ctx, email, vType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerificationAttemptsCounter_IncrementVerificationAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncrementVerificationAttempts'
+type VerificationAttemptsCounter_IncrementVerificationAttempts_Call struct {
+	*mock.Call
+}
+
+// IncrementVerificationAttempts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+func (_e *VerificationAttemptsCounter_Expecter) IncrementVerificationAttempts(ctx interface{}, email interface{}, vType interface{}) *VerificationAttemptsCounter_IncrementVerificationAttempts_Call {
+	return &VerificationAttemptsCounter_IncrementVerificationAttempts_Call{Call: _e.mock.On("IncrementVerificationAttempts", ctx, email, vType)}
+}
+
+func (_c *VerificationAttemptsCounter_IncrementVerificationAttempts_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType)) *VerificationAttemptsCounter_IncrementVerificationAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType))
+	})
+	return _c
+}
+
+func (_c *VerificationAttemptsCounter_IncrementVerificationAttempts_Call) Return(attempts int, err error) *VerificationAttemptsCounter_IncrementVerificationAttempts_Call {
+	_c.Call.Return(attempts, err)
+	return _c
+}
+
+func (_c *VerificationAttemptsCounter_IncrementVerificationAttempts_Call) RunAndReturn(run func(context.Context, string, models.VerificationType) (int, error)) *VerificationAttemptsCounter_IncrementVerificationAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewVerificationAttemptsCounter creates a new instance of VerificationAttemptsCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVerificationAttemptsCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VerificationAttemptsCounter {
+	mock := &VerificationAttemptsCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
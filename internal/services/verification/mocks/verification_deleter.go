@@ -0,0 +1,85 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VerificationDeleter is an autogenerated mock type for the VerificationDeleter type
+type VerificationDeleter struct {
+	mock.Mock
+}
+
+type VerificationDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *VerificationDeleter) EXPECT() *VerificationDeleter_Expecter {
+	return &VerificationDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteVerification provides a mock function with given fields: ctx, email, vType
+func (_m *VerificationDeleter) DeleteVerification(ctx context.Context, email string, vType models.VerificationType) error {
+	ret := _m.Called(ctx, email, vType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType) error); ok {
+		r0 = rf(ctx, email, vType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerificationDeleter_DeleteVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteVerification'
+type VerificationDeleter_DeleteVerification_Call struct {
+	*mock.Call
+}
+
+// DeleteVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+func (_e *VerificationDeleter_Expecter) DeleteVerification(ctx interface{}, email interface{}, vType interface{}) *VerificationDeleter_DeleteVerification_Call {
+	return &VerificationDeleter_DeleteVerification_Call{Call: _e.mock.On("DeleteVerification", ctx, email, vType)}
+}
+
+func (_c *VerificationDeleter_DeleteVerification_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType)) *VerificationDeleter_DeleteVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType))
+	})
+	return _c
+}
+
+func (_c *VerificationDeleter_DeleteVerification_Call) Return(_a0 error) *VerificationDeleter_DeleteVerification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *VerificationDeleter_DeleteVerification_Call) RunAndReturn(run func(context.Context, string, models.VerificationType) error) *VerificationDeleter_DeleteVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewVerificationDeleter creates a new instance of VerificationDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVerificationDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VerificationDeleter {
+	mock := &VerificationDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VerificationProvider is an autogenerated mock type for the VerificationProvider type
+type VerificationProvider struct {
+	mock.Mock
+}
+
+type VerificationProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *VerificationProvider) EXPECT() *VerificationProvider_Expecter {
+	return &VerificationProvider_Expecter{mock: &_m.Mock}
+}
+
+// Verification provides a mock function with given fields: ctx, email, vType
+func (_m *VerificationProvider) Verification(ctx context.Context, email string, vType models.VerificationType) (models.VerificationData, error) {
+	ret := _m.Called(ctx, email, vType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verification")
+	}
+
+	var r0 models.VerificationData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType) (models.VerificationData, error)); ok {
+		return rf(ctx, email, vType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType) models.VerificationData); ok {
+		r0 = rf(ctx, email, vType)
+	} else {
+		r0 = ret.Get(0).(models.VerificationData)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.VerificationType) error); ok {
+		r1 = rf(ctx, email, vType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerificationProvider_Verification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verification'
+type VerificationProvider_Verification_Call struct {
+	*mock.Call
+}
+
+// Verification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+func (_e *VerificationProvider_Expecter) Verification(ctx interface{}, email interface{}, vType interface{}) *VerificationProvider_Verification_Call {
+	return &VerificationProvider_Verification_Call{Call: _e.mock.On("Verification", ctx, email, vType)}
+}
+
+func (_c *VerificationProvider_Verification_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType)) *VerificationProvider_Verification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType))
+	})
+	return _c
+}
+
+func (_c *VerificationProvider_Verification_Call) Return(verificationData models.VerificationData, err error) *VerificationProvider_Verification_Call {
+	_c.Call.Return(verificationData, err)
+	return _c
+}
+
+func (_c *VerificationProvider_Verification_Call) RunAndReturn(run func(context.Context, string, models.VerificationType) (models.VerificationData, error)) *VerificationProvider_Verification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewVerificationProvider creates a new instance of VerificationProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVerificationProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VerificationProvider {
+	mock := &VerificationProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
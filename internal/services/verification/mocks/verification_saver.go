@@ -0,0 +1,99 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// VerificationSaver is an autogenerated mock type for the VerificationSaver type
+type VerificationSaver struct {
+	mock.Mock
+}
+
+type VerificationSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *VerificationSaver) EXPECT() *VerificationSaver_Expecter {
+	return &VerificationSaver_Expecter{mock: &_m.Mock}
+}
+
+// StoreVerification provides a mock function with given fields: ctx, email, vType, code, expiresAt
+func (_m *VerificationSaver) StoreVerification(ctx context.Context, email string, vType models.VerificationType, code string, expiresAt time.Time) (models.VerificationData, error) {
+	ret := _m.Called(ctx, email, vType, code, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreVerification")
+	}
+
+	var r0 models.VerificationData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, time.Time) (models.VerificationData, error)); ok {
+		return rf(ctx, email, vType, code, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.VerificationType, string, time.Time) models.VerificationData); ok {
+		r0 = rf(ctx, email, vType, code, expiresAt)
+	} else {
+		r0 = ret.Get(0).(models.VerificationData)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.VerificationType, string, time.Time) error); ok {
+		r1 = rf(ctx, email, vType, code, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerificationSaver_StoreVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreVerification'
+type VerificationSaver_StoreVerification_Call struct {
+	*mock.Call
+}
+
+// StoreVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - vType models.VerificationType
+//   - code string
+//   - expiresAt time.Time
+func (_e *VerificationSaver_Expecter) StoreVerification(ctx interface{}, email interface{}, vType interface{}, code interface{}, expiresAt interface{}) *VerificationSaver_StoreVerification_Call {
+	return &VerificationSaver_StoreVerification_Call{Call: _e.mock.On("StoreVerification", ctx, email, vType, code, expiresAt)}
+}
+
+func (_c *VerificationSaver_StoreVerification_Call) Run(run func(ctx context.Context, email string, vType models.VerificationType, code string, expiresAt time.Time)) *VerificationSaver_StoreVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(models.VerificationType), args[3].(string), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *VerificationSaver_StoreVerification_Call) Return(verificationData models.VerificationData, err error) *VerificationSaver_StoreVerification_Call {
+	_c.Call.Return(verificationData, err)
+	return _c
+}
+
+func (_c *VerificationSaver_StoreVerification_Call) RunAndReturn(run func(context.Context, string, models.VerificationType, string, time.Time) (models.VerificationData, error)) *VerificationSaver_StoreVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewVerificationSaver creates a new instance of VerificationSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewVerificationSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *VerificationSaver {
+	mock := &VerificationSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,227 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/storage"
+)
+
+type PhoneVerificationSaver interface {
+	StorePhoneVerification(ctx context.Context, email string, phone string, code string, expiresAt time.Time) error
+}
+
+type PhoneVerificationProvider interface {
+	PhoneVerification(ctx context.Context, email string) (verificationData models.PhoneVerificationData, err error)
+}
+
+type PhoneVerificationAttemptsCounter interface {
+	IncrementPhoneVerificationAttempts(ctx context.Context, email string) (attempts int, err error)
+}
+
+type PhoneVerificationDeleter interface {
+	DeletePhoneVerification(ctx context.Context, email string) error
+}
+
+type PhoneVerifier interface {
+	VerifyPhone(ctx context.Context, email string, phone string) (uid int64, err error)
+}
+
+// Phone verifies phone numbers of users the same way Verification verifies emails.
+type Phone struct {
+	log             *slog.Logger
+	saver           PhoneVerificationSaver
+	provider        PhoneVerificationProvider
+	deleter         PhoneVerificationDeleter
+	attemptsCounter PhoneVerificationAttemptsCounter
+	phoneVerifier   PhoneVerifier
+	stats           StatsCounter
+	clock           clock.Clock
+	maxAttempts     int
+}
+
+var InvalidPhone = errors.New("Invalid phone number")
+
+// phoneRe matches phone numbers in E.164 format.
+var phoneRe = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+func NewPhone(
+	log *slog.Logger,
+	saver PhoneVerificationSaver,
+	provider PhoneVerificationProvider,
+	deleter PhoneVerificationDeleter,
+	attemptsCounter PhoneVerificationAttemptsCounter,
+	phoneVerifier PhoneVerifier,
+	stats StatsCounter,
+	clock clock.Clock,
+	maxAttempts int,
+) *Phone {
+	return &Phone{
+		log:             log,
+		saver:           saver,
+		provider:        provider,
+		deleter:         deleter,
+		attemptsCounter: attemptsCounter,
+		phoneVerifier:   phoneVerifier,
+		stats:           stats,
+		clock:           clock,
+		maxAttempts:     maxAttempts,
+	}
+}
+
+// StorePhoneVerification saves verification of the phone number of the user with the given email.
+func (p *Phone) StorePhoneVerification(
+	ctx context.Context,
+	email string,
+	phone string,
+	code string,
+	expiresAt time.Time,
+) error {
+	const op = "Phone.StorePhoneVerification"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.String("username", email),
+	)
+
+	log.Info("storing phone verification")
+
+	if email == "" {
+		return fmt.Errorf("%s: %w", op, EmptyEmail)
+	}
+
+	if !ValidPhone(phone) {
+		return fmt.Errorf("%s: %w", op, InvalidPhone)
+	}
+
+	if code == "" {
+		return fmt.Errorf("%s: %w", op, EmptyCode)
+	}
+
+	if expiresAt.IsZero() {
+		return fmt.Errorf("%s: %w", op, EmptyExpiresAt)
+	}
+
+	if err := p.saver.StorePhoneVerification(ctx, email, phone, code, expiresAt); err != nil {
+		log.Error("failed to save phone verification", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	countEvent(ctx, log, p.stats, models.VerificationTypePhone, models.VerificationEventCreated, p.clock.Now())
+
+	return nil
+}
+
+// Delivered counts delivery of the code of phone verification, see Verification.Delivered.
+func (p *Phone) Delivered(ctx context.Context) {
+	const op = "Phone.Delivered"
+
+	log := p.log.With(slog.String("op", op))
+
+	countEvent(ctx, log, p.stats, models.VerificationTypePhone, models.VerificationEventDelivered, p.clock.Now())
+}
+
+// VerifyPhone checks the code sent to the phone and marks the phone as verified.
+// Code is accepted only for the phone it was sent to.
+func (p *Phone) VerifyPhone(
+	ctx context.Context,
+	email string,
+	phone string,
+	code string,
+) (int64, error) {
+	const op = "Phone.VerifyPhone"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.String("username", email),
+	)
+
+	if email == "" {
+		return 0, fmt.Errorf("%s: %w", op, EmptyEmail)
+	}
+
+	if code == "" {
+		return 0, fmt.Errorf("%s: %w", op, EmptyCode)
+	}
+
+	verification, err := p.provider.PhoneVerification(ctx, email)
+	if err != nil && !errors.Is(err, storage.ErrVerificationNotFound) {
+		log.Error("failed to fetch phone verification", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	found := err == nil
+
+	if !codesEqual(verification.Code, code) || verification.Phone != phone || !found {
+		return 0, p.failAttempt(ctx, log, email)
+	}
+
+	if verification.ExpiresAt.Before(p.clock.Now()) {
+		countEvent(ctx, log, p.stats, models.VerificationTypePhone, models.VerificationEventExpired, p.clock.Now())
+		p.deleter.DeletePhoneVerification(ctx, email)
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrVerificationExpired)
+	}
+
+	id, err := p.phoneVerifier.VerifyPhone(ctx, email, phone)
+	if err != nil {
+		log.Error("failed to verify phone", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := p.deleter.DeletePhoneVerification(ctx, email); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	countEvent(ctx, log, p.stats, models.VerificationTypePhone, models.VerificationEventVerified, p.clock.Now())
+
+	log.Info("phone verified", slog.Int64("user_id", id))
+
+	return id, nil
+}
+
+// failAttempt counts failed phone verification attempt, see Verification.failAttempt.
+func (p *Phone) failAttempt(ctx context.Context, log *slog.Logger, email string) error {
+	const op = "Phone.VerifyPhone"
+
+	countEvent(ctx, log, p.stats, models.VerificationTypePhone, models.VerificationEventFailed, p.clock.Now())
+
+	attempts, err := p.attemptsCounter.IncrementPhoneVerificationAttempts(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrVerificationNotFound) {
+			return fmt.Errorf("%s: %w", op, CodesDiffer)
+		}
+
+		log.Error("failed to count phone verification attempt", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if p.maxAttempts <= 0 || attempts < p.maxAttempts {
+		return fmt.Errorf("%s: %w", op, CodesDiffer)
+	}
+
+	log.Warn("phone verification attempts limit reached", slog.Int("attempts", attempts))
+
+	if err := p.deleter.DeletePhoneVerification(ctx, email); err != nil {
+		log.Error("failed to delete phone verification", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return fmt.Errorf("%s: %w", op, TooManyAttempts)
+}
+
+// ValidPhone reports whether phone is in E.164 format, e.g. +14155552671.
+func ValidPhone(phone string) bool {
+	return phoneRe.MatchString(phone)
+}
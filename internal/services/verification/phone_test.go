@@ -0,0 +1,233 @@
+package verification
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/services/verification/mocks"
+	"grpc-service-ref/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testEmail = "user@example.com"
+	testPhone = "+14155552671"
+	testCode  = "123456"
+)
+
+var (
+	testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+)
+
+func TestValidPhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		valid bool
+	}{
+		{"+14155552671", true},
+		{"+442071838750", true},
+		{"+1234567", true},
+		{"14155552671", false},
+		{"+04155552671", false},
+		{"+141555", false},
+		{"+1234567890123456", false},
+		{"+1 415 555 2671", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			assert.Equal(t, tt.valid, ValidPhone(tt.phone))
+		})
+	}
+}
+
+func TestPhoneStorePhoneVerification(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := testNow.Add(10 * time.Minute)
+
+	tests := []struct {
+		name      string
+		email     string
+		phone     string
+		code      string
+		expiresAt time.Time
+		wantErr   error
+	}{
+		{"valid", testEmail, testPhone, testCode, expiresAt, nil},
+		{"empty email", "", testPhone, testCode, expiresAt, EmptyEmail},
+		{"invalid phone", testEmail, "555-2671", testCode, expiresAt, InvalidPhone},
+		{"empty code", testEmail, testPhone, "", expiresAt, EmptyCode},
+		{"empty expires at", testEmail, testPhone, testCode, time.Time{}, EmptyExpiresAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saver := mocks.NewPhoneVerificationSaver(t)
+			if tt.wantErr == nil {
+				saver.EXPECT().StorePhoneVerification(ctx, tt.email, tt.phone, tt.code, tt.expiresAt).Return(nil).Once()
+			}
+
+			phone := NewPhone(testLog, saver, mocks.NewPhoneVerificationProvider(t), mocks.NewPhoneVerificationDeleter(t),
+				mocks.NewPhoneVerificationAttemptsCounter(t), mocks.NewPhoneVerifier(t), nil, clock.NewFake(testNow), 5)
+
+			err := phone.StorePhoneVerification(ctx, tt.email, tt.phone, tt.code, tt.expiresAt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPhoneVerifyPhone(t *testing.T) {
+	ctx := context.Background()
+	pending := models.PhoneVerificationData{
+		Email:     testEmail,
+		Phone:     testPhone,
+		Code:      testCode,
+		ExpiresAt: testNow.Add(time.Minute),
+	}
+	expired := pending
+	expired.ExpiresAt = testNow.Add(-time.Second)
+
+	// Mocks fail on unexpected calls, e.g. verifying the phone by a failed attempt.
+	tests := []struct {
+		name        string
+		phone       string
+		code        string
+		maxAttempts int
+		setup       func(provider *mocks.PhoneVerificationProvider, deleter *mocks.PhoneVerificationDeleter,
+			counter *mocks.PhoneVerificationAttemptsCounter, verifier *mocks.PhoneVerifier)
+		wantID  int64
+		wantErr error
+	}{
+		{
+			name:        "valid",
+			phone:       testPhone,
+			code:        testCode,
+			maxAttempts: 5,
+			setup: func(provider *mocks.PhoneVerificationProvider, deleter *mocks.PhoneVerificationDeleter,
+				_ *mocks.PhoneVerificationAttemptsCounter, verifier *mocks.PhoneVerifier) {
+				provider.EXPECT().PhoneVerification(ctx, testEmail).Return(pending, nil)
+				verifier.EXPECT().VerifyPhone(ctx, testEmail, testPhone).Return(42, nil).Once()
+				deleter.EXPECT().DeletePhoneVerification(ctx, testEmail).Return(nil).Once()
+			},
+			wantID: 42,
+		},
+		{
+			name:        "wrong code",
+			phone:       testPhone,
+			code:        "654321",
+			maxAttempts: 5,
+			setup: func(provider *mocks.PhoneVerificationProvider, _ *mocks.PhoneVerificationDeleter,
+				counter *mocks.PhoneVerificationAttemptsCounter, _ *mocks.PhoneVerifier) {
+				provider.EXPECT().PhoneVerification(ctx, testEmail).Return(pending, nil)
+				counter.EXPECT().IncrementPhoneVerificationAttempts(ctx, testEmail).Return(1, nil).Once()
+			},
+			wantErr: CodesDiffer,
+		},
+		{
+			name:        "code sent to another phone",
+			phone:       "+442071838750",
+			code:        testCode,
+			maxAttempts: 5,
+			setup: func(provider *mocks.PhoneVerificationProvider, _ *mocks.PhoneVerificationDeleter,
+				counter *mocks.PhoneVerificationAttemptsCounter, _ *mocks.PhoneVerifier) {
+				provider.EXPECT().PhoneVerification(ctx, testEmail).Return(pending, nil)
+				counter.EXPECT().IncrementPhoneVerificationAttempts(ctx, testEmail).Return(1, nil).Once()
+			},
+			wantErr: CodesDiffer,
+		},
+		{
+			name:        "not found",
+			phone:       testPhone,
+			code:        testCode,
+			maxAttempts: 5,
+			setup: func(provider *mocks.PhoneVerificationProvider, _ *mocks.PhoneVerificationDeleter,
+				counter *mocks.PhoneVerificationAttemptsCounter, _ *mocks.PhoneVerifier) {
+				provider.EXPECT().PhoneVerification(ctx, testEmail).Return(models.PhoneVerificationData{}, storage.ErrVerificationNotFound)
+				counter.EXPECT().IncrementPhoneVerificationAttempts(ctx, testEmail).Return(0, storage.ErrVerificationNotFound).Once()
+			},
+			wantErr: CodesDiffer,
+		},
+		{
+			name:        "too many attempts",
+			phone:       testPhone,
+			code:        "654321",
+			maxAttempts: 3,
+			setup: func(provider *mocks.PhoneVerificationProvider, deleter *mocks.PhoneVerificationDeleter,
+				counter *mocks.PhoneVerificationAttemptsCounter, _ *mocks.PhoneVerifier) {
+				provider.EXPECT().PhoneVerification(ctx, testEmail).Return(pending, nil)
+				counter.EXPECT().IncrementPhoneVerificationAttempts(ctx, testEmail).Return(3, nil).Once()
+				deleter.EXPECT().DeletePhoneVerification(ctx, testEmail).Return(nil).Once()
+			},
+			wantErr: TooManyAttempts,
+		},
+		{
+			name:  "unlimited attempts",
+			phone: testPhone,
+			code:  "654321",
+			setup: func(provider *mocks.PhoneVerificationProvider, _ *mocks.PhoneVerificationDeleter,
+				counter *mocks.PhoneVerificationAttemptsCounter, _ *mocks.PhoneVerifier) {
+				provider.EXPECT().PhoneVerification(ctx, testEmail).Return(pending, nil)
+				counter.EXPECT().IncrementPhoneVerificationAttempts(ctx, testEmail).Return(100, nil).Once()
+			},
+			wantErr: CodesDiffer,
+		},
+		{
+			name:        "expired",
+			phone:       testPhone,
+			code:        testCode,
+			maxAttempts: 5,
+			setup: func(provider *mocks.PhoneVerificationProvider, deleter *mocks.PhoneVerificationDeleter,
+				_ *mocks.PhoneVerificationAttemptsCounter, _ *mocks.PhoneVerifier) {
+				provider.EXPECT().PhoneVerification(ctx, testEmail).Return(expired, nil)
+				deleter.EXPECT().DeletePhoneVerification(ctx, testEmail).Return(nil).Once()
+			},
+			wantErr: storage.ErrVerificationExpired,
+		},
+		{
+			name:        "storage failure",
+			phone:       testPhone,
+			code:        testCode,
+			maxAttempts: 5,
+			setup: func(provider *mocks.PhoneVerificationProvider, _ *mocks.PhoneVerificationDeleter,
+				_ *mocks.PhoneVerificationAttemptsCounter, _ *mocks.PhoneVerifier) {
+				provider.EXPECT().PhoneVerification(ctx, testEmail).Return(models.PhoneVerificationData{}, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:        "empty code",
+			phone:       testPhone,
+			maxAttempts: 5,
+			wantErr:     EmptyCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, deleter := mocks.NewPhoneVerificationProvider(t), mocks.NewPhoneVerificationDeleter(t)
+			counter, verifier := mocks.NewPhoneVerificationAttemptsCounter(t), mocks.NewPhoneVerifier(t)
+			if tt.setup != nil {
+				tt.setup(provider, deleter, counter, verifier)
+			}
+
+			phone := NewPhone(testLog, mocks.NewPhoneVerificationSaver(t), provider, deleter, counter, verifier, nil,
+				clock.NewFake(testNow), tt.maxAttempts)
+
+			id, err := phone.VerifyPhone(ctx, testEmail, tt.phone, tt.code)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package verification
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+)
+
+// StatsCounter counts verification events for the stats RPC, metrics count them per replica only.
+type StatsCounter interface {
+	CountVerificationEvent(
+		ctx context.Context,
+		vType models.VerificationType,
+		appID int,
+		event models.VerificationEvent,
+		at time.Time,
+	) error
+}
+
+type appKey struct{}
+
+// WithApp returns ctx attributing verifications created and checked with it to the app in metrics and stats.
+func WithApp(ctx context.Context, appID int) context.Context {
+	return context.WithValue(ctx, appKey{}, appID)
+}
+
+// appFromContext returns app of WithApp, 0 if verification isn't related to an app.
+func appFromContext(ctx context.Context) int {
+	appID, _ := ctx.Value(appKey{}).(int)
+
+	return appID
+}
+
+// countEvent counts the event in metrics and, unless counter is nil, in stats. Failure to count is logged only,
+// stats never fail verifications.
+func countEvent(
+	ctx context.Context,
+	log *slog.Logger,
+	counter StatsCounter,
+	vType models.VerificationType,
+	event models.VerificationEvent,
+	at time.Time,
+) {
+	appID := appFromContext(ctx)
+
+	metrics.Verifications.WithLabelValues(string(vType), strconv.Itoa(appID), string(event)).Inc()
+
+	if counter == nil {
+		return
+	}
+
+	if err := counter.CountVerificationEvent(ctx, vType, appID, event, at); err != nil {
+		log.Error("failed to count verification event", slog.String("event", string(event)), sl.Err(err))
+	}
+}
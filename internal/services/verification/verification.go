@@ -2,12 +2,16 @@ package verification
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/events"
+	"grpc-service-ref/internal/lib/clock"
 	"grpc-service-ref/internal/lib/logger/sl"
 	"grpc-service-ref/internal/services/auth"
 	"grpc-service-ref/internal/storage"
@@ -17,17 +21,26 @@ type VerificationSaver interface {
 	StoreVerification(
 		ctx context.Context,
 		email string,
+		vType models.VerificationType,
 		code string,
 		expiresAt time.Time,
 	) (verificationData models.VerificationData, err error)
 }
 
 type VerificationProvider interface {
-	Verification(ctx context.Context, email string) (verificationData models.VerificationData, err error)
+	Verification(ctx context.Context, email string, vType models.VerificationType) (verificationData models.VerificationData, err error)
+}
+
+type VerificationAttemptsCounter interface {
+	IncrementVerificationAttempts(ctx context.Context, email string, vType models.VerificationType) (attempts int, err error)
 }
 
 type VerificationDeleter interface {
-	DeleteVerification(ctx context.Context, email string) error
+	DeleteVerification(ctx context.Context, email string, vType models.VerificationType) error
+}
+
+type PendingRegistrationActivator interface {
+	ActivatePendingRegistration(ctx context.Context, email string) (uid int64, err error)
 }
 
 type Verification struct {
@@ -35,15 +48,23 @@ type Verification struct {
 	verificationSaver    VerificationSaver
 	verificationProvider VerificationProvider
 	verificationDeleter  VerificationDeleter
+	attemptsCounter      VerificationAttemptsCounter
 	userSaver            auth.UserSaver
+	pendingActivator     PendingRegistrationActivator
+	events               auth.EventPublisher
+	stats                StatsCounter
+	clock                clock.Clock
+	maxAttempts          int
 }
 
 var (
 	EmptyEmail          = errors.New("Empty email")
 	EmptyCode           = errors.New("Empty code")
 	EmptyExpiresAt      = errors.New("Empty expires at")
+	UnknownType         = errors.New("Unknown verification type")
 	CodesDiffer         = errors.New("Codes are different")
 	VerificationExpired = errors.New("Verification expired")
+	TooManyAttempts     = errors.New("Too many attempts")
 )
 
 func New(
@@ -51,20 +72,33 @@ func New(
 	verificationSaver VerificationSaver,
 	verificationProvider VerificationProvider,
 	verificationDeleter VerificationDeleter,
+	attemptsCounter VerificationAttemptsCounter,
 	userSaver auth.UserSaver,
+	pendingActivator PendingRegistrationActivator,
+	events auth.EventPublisher,
+	stats StatsCounter,
+	clock clock.Clock,
+	maxAttempts int,
 ) *Verification {
 	return &Verification{
 		log:                  log,
 		verificationSaver:    verificationSaver,
 		verificationProvider: verificationProvider,
 		verificationDeleter:  verificationDeleter,
+		attemptsCounter:      attemptsCounter,
 		userSaver:            userSaver,
+		pendingActivator:     pendingActivator,
+		events:               events,
+		stats:                stats,
+		clock:                clock,
+		maxAttempts:          maxAttempts,
 	}
 }
 
 func (v *Verification) StoreVerification(
 	ctx context.Context,
 	email string,
+	vType models.VerificationType,
 	code string,
 	expiresAt time.Time,
 ) (models.VerificationData, error) {
@@ -73,6 +107,7 @@ func (v *Verification) StoreVerification(
 	log := v.log.With(
 		slog.String("op", op),
 		slog.String("username", email),
+		slog.String("type", string(vType)),
 	)
 
 	log.Info("storing verification")
@@ -94,7 +129,13 @@ func (v *Verification) StoreVerification(
 		return models.VerificationData{}, fmt.Errorf("%s: %w", op, EmptyExpiresAt)
 	}
 
-	verificationData, err := v.verificationSaver.StoreVerification(ctx, email, code, expiresAt)
+	if !validType(vType) {
+		log.Error("unknown verification type")
+
+		return models.VerificationData{}, fmt.Errorf("%s: %w", op, UnknownType)
+	}
+
+	verificationData, err := v.verificationSaver.StoreVerification(ctx, email, vType, code, expiresAt)
 
 	if err != nil {
 		log.Error("failed to save verification data", sl.Err(err))
@@ -102,12 +143,33 @@ func (v *Verification) StoreVerification(
 		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	countEvent(ctx, log, v.stats, vType, models.VerificationEventCreated, v.clock.Now())
+
 	return verificationData, nil
 }
 
+// Delivered counts delivery of the code of the verification of the given type, callers report it once
+// the code is sent.
+func (v *Verification) Delivered(ctx context.Context, vType models.VerificationType) {
+	const op = "Verification.Delivered"
+
+	log := v.log.With(
+		slog.String("op", op),
+		slog.String("type", string(vType)),
+	)
+
+	countEvent(ctx, log, v.stats, vType, models.VerificationEventDelivered, v.clock.Now())
+}
+
+// Verify checks code of the verification of the given type.
+// Code of another type is never accepted, e.g. registration code can't be used to reset password.
+//
+// Missing verification is reported as CodesDiffer and takes the same path as a wrong code,
+// so neither response nor timing reveal whether email has a pending verification.
 func (v *Verification) Verify(
 	ctx context.Context,
 	email string,
+	vType models.VerificationType,
 	code string,
 	deleteVerificationAfterAtempt bool,
 ) (string, error) {
@@ -116,6 +178,7 @@ func (v *Verification) Verify(
 	log := v.log.With(
 		slog.String("op", op),
 		slog.String("username", email),
+		slog.String("type", string(vType)),
 	)
 
 	if email == "" {
@@ -129,29 +192,36 @@ func (v *Verification) Verify(
 		return "", fmt.Errorf("%s: %w", op, EmptyCode)
 	}
 
-	verification, err := v.verificationProvider.Verification(ctx, email)
-	if err != nil {
+	verification, err := v.verificationProvider.Verification(ctx, email, vType)
+	if err != nil && !errors.Is(err, storage.ErrVerificationNotFound) {
 		log.Error("failed to fetch verification data", sl.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	if verification.Code != code {
-		return "", fmt.Errorf("%s, %w", op, CodesDiffer)
+	found := err == nil
+
+	if !codesEqual(verification.Code, code) || !found {
+		return "", v.failAttempt(ctx, log, email, vType)
 	}
 
-	if verification.ExpiresAt.Before(time.Now()) {
-		v.verificationDeleter.DeleteVerification(ctx, email)
+	if verification.ExpiresAt.Before(v.clock.Now()) {
+		countEvent(ctx, log, v.stats, vType, models.VerificationEventExpired, v.clock.Now())
+		v.verificationDeleter.DeleteVerification(ctx, email, vType)
 		return "", fmt.Errorf("%s: %w", op, storage.ErrVerificationExpired)
 	}
 
 	// обновить юзера
-	id, err := v.userSaver.VerifyUser(ctx, email)
+	id, err := v.verifyUser(ctx, log, email, vType)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	countEvent(ctx, log, v.stats, vType, models.VerificationEventVerified, v.clock.Now())
+
 	// удалить верификацию
 	if deleteVerificationAfterAtempt {
-		if err := v.verificationDeleter.DeleteVerification(ctx, email); err != nil {
+		if err := v.verificationDeleter.DeleteVerification(ctx, email, vType); err != nil {
 			return "", fmt.Errorf("%s: %w", op, err)
 		}
 	}
@@ -159,15 +229,130 @@ func (v *Verification) Verify(
 	return fmt.Sprintf("%v", id), nil
 }
 
+// Status returns status of the verification of the given type.
+// Missing or expired verification is reported as not pending.
+func (v *Verification) Status(
+	ctx context.Context,
+	email string,
+	vType models.VerificationType,
+) (models.VerificationStatus, error) {
+	const op = "Verification.Status"
+
+	if email == "" {
+		return models.VerificationStatus{}, fmt.Errorf("%s: %w", op, EmptyEmail)
+	}
+
+	if !validType(vType) {
+		return models.VerificationStatus{}, fmt.Errorf("%s: %w", op, UnknownType)
+	}
+
+	verification, err := v.verificationProvider.Verification(ctx, email, vType)
+	if err != nil {
+		if errors.Is(err, storage.ErrVerificationNotFound) {
+			return models.VerificationStatus{RemainingAttempts: v.remainingAttempts(0)}, nil
+		}
+
+		return models.VerificationStatus{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.VerificationStatus{
+		Pending:           verification.ExpiresAt.After(v.clock.Now()),
+		ExpiresAt:         verification.ExpiresAt,
+		RemainingAttempts: v.remainingAttempts(verification.Attempts),
+	}, nil
+}
+
+func (v *Verification) remainingAttempts(attempts int) int {
+	if v.maxAttempts <= 0 {
+		return -1
+	}
+
+	return max(v.maxAttempts-attempts, 0)
+}
+
+// verifyUser marks user as verified.
+// Pending registration is turned into a verified user on registration verification.
+func (v *Verification) verifyUser(
+	ctx context.Context,
+	log *slog.Logger,
+	email string,
+	vType models.VerificationType,
+) (int64, error) {
+	if vType == models.VerificationTypeRegistration {
+		id, err := v.pendingActivator.ActivatePendingRegistration(ctx, email)
+		if err == nil {
+			log.Info("pending registration activated", slog.Int64("user_id", id))
+			v.events.Publish(ctx, events.UserRegistered{UserID: id, Email: email, Activated: true})
+			v.events.Publish(ctx, events.EmailVerified{UserID: id, Email: email})
+
+			return id, nil
+		}
+
+		if !errors.Is(err, storage.ErrPendingRegistrationNotFound) {
+			log.Error("failed to activate pending registration", sl.Err(err))
+
+			return 0, err
+		}
+	}
+
+	id, err := v.userSaver.VerifyUser(ctx, email)
+	if err != nil {
+		return 0, err
+	}
+
+	if vType == models.VerificationTypeRegistration {
+		v.events.Publish(ctx, events.EmailVerified{UserID: id, Email: email})
+	}
+
+	return id, nil
+}
+
+// failAttempt counts failed verification attempt.
+// Verification is deleted once attempts limit is reached, so the code can't be brute-forced.
+func (v *Verification) failAttempt(ctx context.Context, log *slog.Logger, email string, vType models.VerificationType) error {
+	const op = "Verification.Verify"
+
+	countEvent(ctx, log, v.stats, vType, models.VerificationEventFailed, v.clock.Now())
+
+	// Counter is incremented for missing verification too, it's a no-op then,
+	// but takes as long as for an existing one.
+	attempts, err := v.attemptsCounter.IncrementVerificationAttempts(ctx, email, vType)
+	if err != nil {
+		if errors.Is(err, storage.ErrVerificationNotFound) {
+			return fmt.Errorf("%s: %w", op, CodesDiffer)
+		}
+
+		log.Error("failed to count verification attempt", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if v.maxAttempts <= 0 || attempts < v.maxAttempts {
+		return fmt.Errorf("%s: %w", op, CodesDiffer)
+	}
+
+	log.Warn("verification attempts limit reached", slog.Int("attempts", attempts))
+
+	if err := v.verificationDeleter.DeleteVerification(ctx, email, vType); err != nil {
+		log.Error("failed to delete verification", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return fmt.Errorf("%s: %w", op, TooManyAttempts)
+}
+
 func (v *Verification) DeleteVerification(
 	ctx context.Context,
 	email string,
+	vType models.VerificationType,
 ) error {
 	const op = "Verification.Delete"
 
 	log := v.log.With(
 		slog.String("op", op),
 		slog.String("username", email),
+		slog.String("type", string(vType)),
 	)
 	log.Info("Deleting verification")
 
@@ -177,9 +362,30 @@ func (v *Verification) DeleteVerification(
 		return fmt.Errorf("%s: %w", op, EmptyEmail)
 	}
 
-	if err := v.verificationDeleter.DeleteVerification(ctx, email); err != nil {
+	if err := v.verificationDeleter.DeleteVerification(ctx, email, vType); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
+
+// codesEqual compares codes in constant time.
+// Codes are hashed first, since subtle.ConstantTimeCompare returns early on different lengths.
+func codesEqual(expected string, actual string) bool {
+	e := sha256.Sum256([]byte(expected))
+	a := sha256.Sum256([]byte(actual))
+
+	return subtle.ConstantTimeCompare(e[:], a[:]) == 1
+}
+
+func validType(vType models.VerificationType) bool {
+	switch vType {
+	case models.VerificationTypeRegistration,
+		models.VerificationTypePasswordReset,
+		models.VerificationTypeEmailChange,
+		models.VerificationTypeSignIn:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/events"
+)
+
+// Subscribe publishes domain events of the bus apps are notified of to their webhooks.
+func (d *Dispatcher) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e events.UserRegistered) {
+		d.publishUser(ctx, models.WebhookEventUserRegistered, e.UserID, e.Email)
+	})
+	events.Subscribe(bus, func(ctx context.Context, e events.EmailVerified) {
+		d.publishUser(ctx, models.WebhookEventUserVerified, e.UserID, e.Email)
+	})
+	events.Subscribe(bus, func(ctx context.Context, e events.PasswordReset) {
+		d.publishUser(ctx, models.WebhookEventUserPasswordReset, e.UserID, e.Email)
+	})
+	events.Subscribe(bus, d.publishLoggedOut)
+}
+
+func (d *Dispatcher) publishUser(ctx context.Context, eventType models.WebhookEventType, userID int64, email string) {
+	d.Publish(ctx, models.WebhookEvent{
+		Type: eventType,
+		Data: map[string]any{"user_id": userID, "email": email},
+	})
+}
+
+// publishLoggedOut notifies every app which had sessions revoked, with IDs of its sessions, i.e. jti of tokens.
+func (d *Dispatcher) publishLoggedOut(ctx context.Context, e events.LoggedOut) {
+	sessionsByApp := make(map[int][]string)
+	for _, session := range e.Sessions {
+		sessionsByApp[session.AppID] = append(sessionsByApp[session.AppID], session.ID)
+	}
+
+	for appID, sessionIDs := range sessionsByApp {
+		d.Publish(ctx, models.WebhookEvent{
+			Type:  models.WebhookEventUserLoggedOut,
+			AppID: appID,
+			Data: map[string]any{
+				"user_id":        e.UserID,
+				"sso_session_id": e.SSOSessionID,
+				"session_ids":    sessionIDs,
+			},
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DeliveryProvider is an autogenerated mock type for the DeliveryProvider type
+type DeliveryProvider struct {
+	mock.Mock
+}
+
+type DeliveryProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeliveryProvider) EXPECT() *DeliveryProvider_Expecter {
+	return &DeliveryProvider_Expecter{mock: &_m.Mock}
+}
+
+// DueWebhookDeliveries provides a mock function with given fields: ctx, now, limit
+func (_m *DeliveryProvider) DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DueWebhookDeliveries")
+	}
+
+	var r0 []models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.WebhookDelivery, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.WebhookDelivery); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeliveryProvider_DueWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DueWebhookDeliveries'
+type DeliveryProvider_DueWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// DueWebhookDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - limit int
+func (_e *DeliveryProvider_Expecter) DueWebhookDeliveries(ctx interface{}, now interface{}, limit interface{}) *DeliveryProvider_DueWebhookDeliveries_Call {
+	return &DeliveryProvider_DueWebhookDeliveries_Call{Call: _e.mock.On("DueWebhookDeliveries", ctx, now, limit)}
+}
+
+func (_c *DeliveryProvider_DueWebhookDeliveries_Call) Run(run func(ctx context.Context, now time.Time, limit int)) *DeliveryProvider_DueWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DeliveryProvider_DueWebhookDeliveries_Call) Return(_a0 []models.WebhookDelivery, _a1 error) *DeliveryProvider_DueWebhookDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeliveryProvider_DueWebhookDeliveries_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]models.WebhookDelivery, error)) *DeliveryProvider_DueWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WebhookDelivery provides a mock function with given fields: ctx, id
+func (_m *DeliveryProvider) WebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WebhookDelivery")
+	}
+
+	var r0 models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.WebhookDelivery, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.WebhookDelivery); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.WebhookDelivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeliveryProvider_WebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WebhookDelivery'
+type DeliveryProvider_WebhookDelivery_Call struct {
+	*mock.Call
+}
+
+// WebhookDelivery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *DeliveryProvider_Expecter) WebhookDelivery(ctx interface{}, id interface{}) *DeliveryProvider_WebhookDelivery_Call {
+	return &DeliveryProvider_WebhookDelivery_Call{Call: _e.mock.On("WebhookDelivery", ctx, id)}
+}
+
+func (_c *DeliveryProvider_WebhookDelivery_Call) Run(run func(ctx context.Context, id int64)) *DeliveryProvider_WebhookDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *DeliveryProvider_WebhookDelivery_Call) Return(_a0 models.WebhookDelivery, _a1 error) *DeliveryProvider_WebhookDelivery_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeliveryProvider_WebhookDelivery_Call) RunAndReturn(run func(context.Context, int64) (models.WebhookDelivery, error)) *DeliveryProvider_WebhookDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeliveryProvider creates a new instance of DeliveryProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeliveryProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeliveryProvider {
+	mock := &DeliveryProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,141 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeliverySaver is an autogenerated mock type for the DeliverySaver type
+type DeliverySaver struct {
+	mock.Mock
+}
+
+type DeliverySaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeliverySaver) EXPECT() *DeliverySaver_Expecter {
+	return &DeliverySaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveWebhookDelivery provides a mock function with given fields: ctx, delivery
+func (_m *DeliverySaver) SaveWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) (int64, error) {
+	ret := _m.Called(ctx, delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWebhookDelivery")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookDelivery) (int64, error)); ok {
+		return rf(ctx, delivery)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookDelivery) int64); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.WebhookDelivery) error); ok {
+		r1 = rf(ctx, delivery)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeliverySaver_SaveWebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveWebhookDelivery'
+type DeliverySaver_SaveWebhookDelivery_Call struct {
+	*mock.Call
+}
+
+// SaveWebhookDelivery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - delivery models.WebhookDelivery
+func (_e *DeliverySaver_Expecter) SaveWebhookDelivery(ctx interface{}, delivery interface{}) *DeliverySaver_SaveWebhookDelivery_Call {
+	return &DeliverySaver_SaveWebhookDelivery_Call{Call: _e.mock.On("SaveWebhookDelivery", ctx, delivery)}
+}
+
+func (_c *DeliverySaver_SaveWebhookDelivery_Call) Run(run func(ctx context.Context, delivery models.WebhookDelivery)) *DeliverySaver_SaveWebhookDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.WebhookDelivery))
+	})
+	return _c
+}
+
+func (_c *DeliverySaver_SaveWebhookDelivery_Call) Return(_a0 int64, _a1 error) *DeliverySaver_SaveWebhookDelivery_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeliverySaver_SaveWebhookDelivery_Call) RunAndReturn(run func(context.Context, models.WebhookDelivery) (int64, error)) *DeliverySaver_SaveWebhookDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateWebhookDelivery provides a mock function with given fields: ctx, delivery
+func (_m *DeliverySaver) UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	ret := _m.Called(ctx, delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhookDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookDelivery) error); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeliverySaver_UpdateWebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWebhookDelivery'
+type DeliverySaver_UpdateWebhookDelivery_Call struct {
+	*mock.Call
+}
+
+// UpdateWebhookDelivery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - delivery models.WebhookDelivery
+func (_e *DeliverySaver_Expecter) UpdateWebhookDelivery(ctx interface{}, delivery interface{}) *DeliverySaver_UpdateWebhookDelivery_Call {
+	return &DeliverySaver_UpdateWebhookDelivery_Call{Call: _e.mock.On("UpdateWebhookDelivery", ctx, delivery)}
+}
+
+func (_c *DeliverySaver_UpdateWebhookDelivery_Call) Run(run func(ctx context.Context, delivery models.WebhookDelivery)) *DeliverySaver_UpdateWebhookDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.WebhookDelivery))
+	})
+	return _c
+}
+
+func (_c *DeliverySaver_UpdateWebhookDelivery_Call) Return(_a0 error) *DeliverySaver_UpdateWebhookDelivery_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DeliverySaver_UpdateWebhookDelivery_Call) RunAndReturn(run func(context.Context, models.WebhookDelivery) error) *DeliverySaver_UpdateWebhookDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeliverySaver creates a new instance of DeliverySaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeliverySaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeliverySaver {
+	mock := &DeliverySaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Locks is an autogenerated mock type for the Locks type
+type Locks struct {
+	mock.Mock
+}
+
+type Locks_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Locks) EXPECT() *Locks_Expecter {
+	return &Locks_Expecter{mock: &_m.Mock}
+}
+
+// Acquire provides a mock function with given fields: ctx, name, ttl
+func (_m *Locks) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, name, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Acquire")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, name, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) bool); ok {
+		r0 = rf(ctx, name, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, name, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Locks_Acquire_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Acquire'
+type Locks_Acquire_Call struct {
+	*mock.Call
+}
+
+// Acquire is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - ttl time.Duration
+func (_e *Locks_Expecter) Acquire(ctx interface{}, name interface{}, ttl interface{}) *Locks_Acquire_Call {
+	return &Locks_Acquire_Call{Call: _e.mock.On("Acquire", ctx, name, ttl)}
+}
+
+func (_c *Locks_Acquire_Call) Run(run func(ctx context.Context, name string, ttl time.Duration)) *Locks_Acquire_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Locks_Acquire_Call) Return(_a0 bool, _a1 error) *Locks_Acquire_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Locks_Acquire_Call) RunAndReturn(run func(context.Context, string, time.Duration) (bool, error)) *Locks_Acquire_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLocks creates a new instance of Locks. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocks(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Locks {
+	mock := &Locks{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
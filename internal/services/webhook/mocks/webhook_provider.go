@@ -0,0 +1,96 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	models "grpc-service-ref/internal/domain/models"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookProvider is an autogenerated mock type for the WebhookProvider type
+type WebhookProvider struct {
+	mock.Mock
+}
+
+type WebhookProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WebhookProvider) EXPECT() *WebhookProvider_Expecter {
+	return &WebhookProvider_Expecter{mock: &_m.Mock}
+}
+
+// Webhooks provides a mock function with given fields: ctx, appID
+func (_m *WebhookProvider) Webhooks(ctx context.Context, appID int) ([]models.Webhook, error) {
+	ret := _m.Called(ctx, appID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Webhooks")
+	}
+
+	var r0 []models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Webhook, error)); ok {
+		return rf(ctx, appID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Webhook); ok {
+		r0 = rf(ctx, appID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, appID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookProvider_Webhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Webhooks'
+type WebhookProvider_Webhooks_Call struct {
+	*mock.Call
+}
+
+// Webhooks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - appID int
+func (_e *WebhookProvider_Expecter) Webhooks(ctx interface{}, appID interface{}) *WebhookProvider_Webhooks_Call {
+	return &WebhookProvider_Webhooks_Call{Call: _e.mock.On("Webhooks", ctx, appID)}
+}
+
+func (_c *WebhookProvider_Webhooks_Call) Run(run func(ctx context.Context, appID int)) *WebhookProvider_Webhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *WebhookProvider_Webhooks_Call) Return(_a0 []models.Webhook, _a1 error) *WebhookProvider_Webhooks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookProvider_Webhooks_Call) RunAndReturn(run func(context.Context, int) ([]models.Webhook, error)) *WebhookProvider_Webhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWebhookProvider creates a new instance of WebhookProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookProvider {
+	mock := &WebhookProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,344 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/logger/sl"
+	"grpc-service-ref/internal/lib/metrics"
+	"grpc-service-ref/internal/lib/tracing"
+	"grpc-service-ref/internal/storage"
+)
+
+// Headers of delivery requests.
+const (
+	HeaderEvent     = "X-SSO-Event"
+	HeaderDelivery  = "X-SSO-Delivery"
+	HeaderSignature = "X-SSO-Signature"
+)
+
+// batchSize is a max number of due deliveries attempted per poll.
+const batchSize = 100
+
+// deliveryLock is name of the lease of delivery, it's held for leasePolls poll intervals,
+// so another replica takes over after a few polls once the holder stops.
+const (
+	deliveryLock = "deliver_webhooks"
+	leasePolls   = 3
+)
+
+type WebhookProvider interface {
+	Webhooks(ctx context.Context, appID int) ([]models.Webhook, error)
+}
+
+type DeliverySaver interface {
+	SaveWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) (int64, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+}
+
+type DeliveryProvider interface {
+	DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	WebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error)
+}
+
+// Locks leases delivery to one replica, so deliveries aren't sent by several replicas at once.
+type Locks interface {
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+}
+
+// Dispatcher delivers events to webhooks of apps subscribed to them.
+// Deliveries are stored first and POSTed by Run, so events survive restarts and failed deliveries are retried.
+type Dispatcher struct {
+	log              *slog.Logger
+	client           *http.Client
+	webhookProvider  WebhookProvider
+	deliverySaver    DeliverySaver
+	deliveryProvider DeliveryProvider
+	locks            Locks
+	maxAttempts      int
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	pollInterval     time.Duration
+}
+
+type payload struct {
+	Type      models.WebhookEventType `json:"type"`
+	CreatedAt time.Time               `json:"created_at"`
+	Data      map[string]any          `json:"data"`
+}
+
+func New(
+	log *slog.Logger,
+	webhookProvider WebhookProvider,
+	deliverySaver DeliverySaver,
+	deliveryProvider DeliveryProvider,
+	locks Locks,
+	maxAttempts int,
+	initialBackoff time.Duration,
+	maxBackoff time.Duration,
+	timeout time.Duration,
+	pollInterval time.Duration,
+) *Dispatcher {
+	return &Dispatcher{
+		log:              log,
+		client:           &http.Client{Timeout: timeout},
+		webhookProvider:  webhookProvider,
+		deliverySaver:    deliverySaver,
+		deliveryProvider: deliveryProvider,
+		locks:            locks,
+		maxAttempts:      maxAttempts,
+		initialBackoff:   initialBackoff,
+		maxBackoff:       maxBackoff,
+		pollInterval:     pollInterval,
+	}
+}
+
+// Publish queues delivery of the event to webhooks subscribed to it.
+//
+// Failing to queue is logged, but doesn't fail the action the event is about.
+func (d *Dispatcher) Publish(ctx context.Context, event models.WebhookEvent) {
+	const op = "Dispatcher.Publish"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.String("event", string(event.Type)),
+	)
+
+	webhooks, err := d.webhookProvider.Webhooks(ctx, event.AppID)
+	if err != nil {
+		log.Error("failed to get webhooks", sl.Err(err))
+
+		return
+	}
+
+	now := time.Now().UTC()
+
+	body, err := json.Marshal(payload{Type: event.Type, CreatedAt: now, Data: event.Data})
+	if err != nil {
+		log.Error("failed to encode payload", sl.Err(err))
+
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event.Type) {
+			continue
+		}
+
+		_, err := d.deliverySaver.SaveWebhookDelivery(ctx, models.WebhookDelivery{
+			Webhook:       webhook,
+			Event:         event.Type,
+			Payload:       body,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			log.Error("failed to queue delivery", sl.Err(err), slog.Int64("webhook_id", webhook.ID))
+		}
+	}
+}
+
+// Run delivers due deliveries until ctx is done.
+// Delivery in flight is completed, so it's not sent twice after restart.
+// With locks, only the replica holding the delivery lease delivers, others take over once it stops.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Replay queues delivery again with all attempts, e.g. once the app fixed its endpoint.
+// Deliveries to webhooks of other apps are reported as not found.
+func (d *Dispatcher) Replay(ctx context.Context, appID int, deliveryID int64) error {
+	const op = "Dispatcher.Replay"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int64("delivery_id", deliveryID),
+	)
+
+	delivery, err := d.deliveryProvider.WebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if delivery.Webhook.AppID != appID {
+		return fmt.Errorf("%s: %w", op, storage.ErrWebhookDeliveryNotFound)
+	}
+
+	now := time.Now().UTC()
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+
+	if err := d.deliverySaver.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("webhook delivery replayed")
+
+	return nil
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	const op = "Dispatcher.deliverDue"
+
+	log := d.log.With(slog.String("op", op))
+
+	if !d.leased(ctx, log) {
+		return
+	}
+
+	deliveries, err := d.deliveryProvider.DueWebhookDeliveries(ctx, time.Now().UTC(), batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error("failed to get due deliveries", sl.Err(err))
+		}
+
+		return
+	}
+
+	for _, delivery := range deliveries {
+		// Lease is renewed per delivery, so a long batch doesn't let another replica send the rest again.
+		if ctx.Err() != nil || !d.leased(ctx, log) {
+			return
+		}
+
+		d.deliver(context.WithoutCancel(ctx), log, delivery)
+	}
+}
+
+// leased renews the delivery lease and reports whether the replica holds it, it always does without locks.
+// Failing to renew is logged and delivery is left to the next poll.
+func (d *Dispatcher) leased(ctx context.Context, log *slog.Logger) bool {
+	if d.locks == nil {
+		return true
+	}
+
+	acquired, err := d.locks.Acquire(ctx, deliveryLock, leasePolls*d.pollInterval)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error("failed to acquire delivery lock", sl.Err(err))
+		}
+
+		return false
+	}
+
+	return acquired
+}
+
+// deliver attempts the delivery and saves its result.
+func (d *Dispatcher) deliver(ctx context.Context, log *slog.Logger, delivery models.WebhookDelivery) {
+	log = log.With(
+		slog.Int64("delivery_id", delivery.ID),
+		slog.Int64("webhook_id", delivery.Webhook.ID),
+	)
+
+	ctx, span := tracing.Start(ctx, "Dispatcher.deliver")
+	defer span.End()
+
+	err := d.send(ctx, delivery)
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	result := metrics.WebhookDelivered
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+	case delivery.Attempts >= d.maxAttempts:
+		result = metrics.WebhookDead
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.LastError = err.Error()
+		log.Warn("webhook delivery is dead, all attempts failed", sl.Err(err))
+	default:
+		result = metrics.WebhookFailed
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		log.Info("webhook delivery failed, will retry", sl.Err(err), slog.Time("next_attempt_at", delivery.NextAttemptAt))
+	}
+
+	if err != nil {
+		tracing.Fail(span, err)
+	}
+
+	metrics.WebhookDeliveries.WithLabelValues(string(delivery.Event), result).Inc()
+
+	if err := d.deliverySaver.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		log.Error("failed to save delivery result", sl.Err(err))
+	}
+}
+
+// send POSTs signed payload of the delivery, any 2xx response means it's delivered.
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderSignature, Signature(delivery.Webhook.Secret, time.Now().Unix(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// backoff returns delay after the given number of failed attempts: initial backoff doubled per attempt, capped by max.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.initialBackoff
+	for i := 1; i < attempts && delay < d.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.maxBackoff)
+}
+
+// Signature returns value of the signature header: "t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">".
+// Apps should check it with the webhook secret and reject stale timestamps, so requests can't be forged or replayed.
+func Signature(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
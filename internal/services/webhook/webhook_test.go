@@ -0,0 +1,256 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/services/webhook/mocks"
+	"grpc-service-ref/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret      = "webhook-secret"
+	testMaxAttempts = 3
+)
+
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"type":"user.registered"}`)
+	sig := Signature(testSecret, 1700000000, body)
+
+	assert.True(t, strings.HasPrefix(sig, "t=1700000000,v1="))
+	assert.Equal(t, sig, Signature(testSecret, 1700000000, body), "signature is deterministic")
+	assert.NotEqual(t, sig, Signature("other-secret", 1700000000, body))
+	assert.NotEqual(t, sig, Signature(testSecret, 1700000001, body))
+	assert.NotEqual(t, sig, Signature(testSecret, 1700000000, []byte(`{"type":"user.deleted"}`)))
+}
+
+func TestBackoff(t *testing.T) {
+	d := New(testLog, mocks.NewWebhookProvider(t), mocks.NewDeliverySaver(t), mocks.NewDeliveryProvider(t), nil, testMaxAttempts, time.Second, 10*time.Second, time.Second, time.Second)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			assert.Equal(t, tt.want, d.backoff(tt.attempts))
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	webhooks, saver := mocks.NewWebhookProvider(t), mocks.NewDeliverySaver(t)
+	d := New(testLog, webhooks, saver, mocks.NewDeliveryProvider(t), nil, testMaxAttempts, time.Second, 10*time.Second, time.Second, time.Second)
+
+	subscribed := models.Webhook{ID: 1, AppID: 1, URL: "https://app.example.com/hooks", Events: []models.WebhookEventType{models.WebhookEventUserRegistered}}
+	all := models.Webhook{ID: 2, AppID: 1, URL: "https://app.example.com/all"}
+	other := models.Webhook{ID: 3, AppID: 1, URL: "https://app.example.com/deleted", Events: []models.WebhookEventType{models.WebhookEventUserDeleted}}
+
+	webhooks.EXPECT().Webhooks(ctx, 1).Return([]models.Webhook{subscribed, all, other}, nil)
+
+	var saved []models.WebhookDelivery
+	saver.EXPECT().SaveWebhookDelivery(ctx, mock.Anything).
+		RunAndReturn(func(_ context.Context, delivery models.WebhookDelivery) (int64, error) {
+			saved = append(saved, delivery)
+
+			return int64(len(saved)), nil
+		}).Times(2)
+
+	d.Publish(ctx, models.WebhookEvent{
+		Type:  models.WebhookEventUserRegistered,
+		AppID: 1,
+		Data:  map[string]any{"user_id": 42},
+	})
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, subscribed, saved[0].Webhook)
+	assert.Equal(t, all, saved[1].Webhook)
+
+	for _, delivery := range saved {
+		assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, models.WebhookEventUserRegistered, delivery.Event)
+		assert.Equal(t, delivery.CreatedAt, delivery.NextAttemptAt, "delivery is due right away")
+
+		var p payload
+		require.NoError(t, json.Unmarshal(delivery.Payload, &p))
+		assert.Equal(t, models.WebhookEventUserRegistered, p.Type)
+		assert.Equal(t, float64(42), p.Data["user_id"])
+	}
+}
+
+func TestPublishWithoutWebhooks(t *testing.T) {
+	ctx := context.Background()
+	webhooks, saver := mocks.NewWebhookProvider(t), mocks.NewDeliverySaver(t)
+	webhooks.EXPECT().Webhooks(ctx, 1).Return(nil, assert.AnError)
+
+	d := New(testLog, webhooks, saver, mocks.NewDeliveryProvider(t), nil, testMaxAttempts, time.Second, 10*time.Second, time.Second, time.Second)
+
+	d.Publish(ctx, models.WebhookEvent{Type: models.WebhookEventUserRegistered, AppID: 1})
+	saver.AssertNotCalled(t, "SaveWebhookDelivery", mock.Anything, mock.Anything)
+}
+
+func TestDeliver(t *testing.T) {
+	body := []byte(`{"type":"user.registered"}`)
+
+	tests := []struct {
+		name         string
+		status       int
+		attempts     int
+		wantStatus   models.WebhookDeliveryStatus
+		wantAttempts int
+		wantRetry    bool
+	}{
+		{"delivered", http.StatusNoContent, 0, models.WebhookDeliveryDelivered, 1, false},
+		{"failed", http.StatusInternalServerError, 0, models.WebhookDeliveryPending, 1, true},
+		{"failed again", http.StatusBadGateway, 1, models.WebhookDeliveryPending, 2, true},
+		{"dead", http.StatusInternalServerError, testMaxAttempts - 1, models.WebhookDeliveryDead, testMaxAttempts, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			saver := mocks.NewDeliverySaver(t)
+			d := New(testLog, mocks.NewWebhookProvider(t), saver, mocks.NewDeliveryProvider(t), nil, testMaxAttempts, time.Second, 10*time.Second, time.Second, time.Second)
+
+			var got *http.Request
+			var gotBody []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				gotBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			delivery := models.WebhookDelivery{
+				ID:       7,
+				Webhook:  models.Webhook{ID: 1, AppID: 1, URL: srv.URL, Secret: testSecret},
+				Event:    models.WebhookEventUserRegistered,
+				Payload:  body,
+				Status:   models.WebhookDeliveryPending,
+				Attempts: tt.attempts,
+			}
+
+			var updated models.WebhookDelivery
+			saver.EXPECT().UpdateWebhookDelivery(mock.Anything, mock.Anything).
+				RunAndReturn(func(_ context.Context, delivery models.WebhookDelivery) error {
+					updated = delivery
+
+					return nil
+				}).Once()
+
+			d.deliver(ctx, d.log, delivery)
+
+			require.NotNil(t, got)
+			assert.Equal(t, body, gotBody)
+			assert.Equal(t, string(models.WebhookEventUserRegistered), got.Header.Get(HeaderEvent))
+			assert.Equal(t, "7", got.Header.Get(HeaderDelivery))
+
+			sig := got.Header.Get(HeaderSignature)
+			ts, _, ok := strings.Cut(strings.TrimPrefix(sig, "t="), ",")
+			require.True(t, ok)
+			timestamp, err := strconv.ParseInt(ts, 10, 64)
+			require.NoError(t, err)
+			assert.Equal(t, Signature(testSecret, timestamp, body), sig)
+
+			assert.Equal(t, tt.wantStatus, updated.Status)
+			assert.Equal(t, tt.wantAttempts, updated.Attempts)
+			if tt.wantStatus == models.WebhookDeliveryDelivered {
+				assert.Empty(t, updated.LastError)
+			} else {
+				assert.Contains(t, updated.LastError, strconv.Itoa(tt.status))
+			}
+			if tt.wantRetry {
+				assert.Equal(t, updated.UpdatedAt.Add(d.backoff(tt.wantAttempts)), updated.NextAttemptAt)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	dead := models.WebhookDelivery{
+		ID:        7,
+		Webhook:   models.Webhook{ID: 1, AppID: 1},
+		Status:    models.WebhookDeliveryDead,
+		Attempts:  testMaxAttempts,
+		LastError: "unexpected status 500",
+	}
+
+	tests := []struct {
+		name     string
+		appID    int
+		delivery models.WebhookDelivery
+		err      error
+		wantErr  error
+	}{
+		{name: "replayed", appID: 1, delivery: dead},
+		{name: "delivery of another app", appID: 2, delivery: dead, wantErr: storage.ErrWebhookDeliveryNotFound},
+		{name: "not found", appID: 1, err: storage.ErrWebhookDeliveryNotFound, wantErr: storage.ErrWebhookDeliveryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saver, deliveries := mocks.NewDeliverySaver(t), mocks.NewDeliveryProvider(t)
+			deliveries.EXPECT().WebhookDelivery(ctx, int64(7)).Return(tt.delivery, tt.err).Once()
+
+			var updated models.WebhookDelivery
+			if tt.wantErr == nil {
+				saver.EXPECT().UpdateWebhookDelivery(ctx, mock.Anything).
+					RunAndReturn(func(_ context.Context, delivery models.WebhookDelivery) error {
+						updated = delivery
+
+						return nil
+					}).Once()
+			}
+
+			d := New(testLog, mocks.NewWebhookProvider(t), saver, deliveries, nil, testMaxAttempts, time.Second, 10*time.Second, time.Second, time.Second)
+
+			err := d.Replay(ctx, tt.appID, 7)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, models.WebhookDeliveryPending, updated.Status)
+			assert.Zero(t, updated.Attempts)
+			assert.Equal(t, updated.UpdatedAt, updated.NextAttemptAt)
+		})
+	}
+}
+
+func TestDeliverDueWithoutLease(t *testing.T) {
+	ctx := context.Background()
+	deliveries, locks := mocks.NewDeliveryProvider(t), mocks.NewLocks(t)
+	d := New(testLog, mocks.NewWebhookProvider(t), mocks.NewDeliverySaver(t), deliveries, nil, testMaxAttempts, time.Second, 10*time.Second, time.Second, time.Second)
+	d.locks = locks
+
+	locks.EXPECT().Acquire(ctx, deliveryLock, leasePolls*d.pollInterval).Return(false, nil).Once()
+
+	d.deliverDue(ctx)
+	deliveries.AssertNotCalled(t, "DueWebhookDeliveries", mock.Anything, mock.Anything, mock.Anything)
+}
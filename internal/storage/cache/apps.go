@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/ttlcache"
+)
+
+// maxApps bounds number of cached apps.
+const maxApps = 10_000
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+// Apps caches apps with their secrets, since every login and token check reads them.
+// Apps must be invalidated when they change, otherwise changes take effect after TTL.
+type Apps struct {
+	apps          AppProvider
+	cache         *ttlcache.Cache[int, models.App]
+	invalidations Invalidations
+}
+
+// NewApps returns Apps caching apps for ttl, zero ttl disables caching.
+// Invalidations are published to other replicas by invalidations, nil keeps them local.
+func NewApps(apps AppProvider, ttl time.Duration, invalidations Invalidations) *Apps {
+	a := &Apps{
+		apps:          apps,
+		cache:         ttlcache.New[int, models.App]("apps", ttl, maxApps),
+		invalidations: invalidations,
+	}
+
+	if invalidations != nil {
+		invalidations.Subscribe(a.invalidated)
+	}
+
+	return a
+}
+
+func (a *Apps) App(ctx context.Context, appID int) (models.App, error) {
+	return a.cache.Load(appID, func() (models.App, error) {
+		return a.apps.App(ctx, appID)
+	})
+}
+
+// InvalidateApp removes the app from cache of all replicas, e.g. after its secret is rotated.
+func (a *Apps) InvalidateApp(appID int) {
+	a.cache.Delete(appID)
+
+	if a.invalidations != nil {
+		a.invalidations.Publish(invalidateApps + strconv.Itoa(appID))
+	}
+}
+
+// invalidated removes app invalidated by a replica.
+func (a *Apps) invalidated(key string) {
+	if id, ok := strings.CutPrefix(key, invalidateApps); ok {
+		if appID, err := strconv.Atoi(id); err == nil {
+			a.cache.Delete(appID)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingApps is AppProvider counting reads of apps.
+type countingApps struct {
+	apps  map[int]models.App
+	calls int
+}
+
+func (c *countingApps) App(_ context.Context, appID int) (models.App, error) {
+	c.calls++
+
+	return c.apps[appID], nil
+}
+
+func TestApps(t *testing.T) {
+	ctx := context.Background()
+	provider := &countingApps{apps: map[int]models.App{1: {ID: 1, Secret: "old"}, 2: {ID: 2}}}
+	invalidations := &localInvalidations{}
+	a := NewApps(provider, time.Minute, invalidations)
+
+	for i := 0; i < 2; i++ {
+		app, err := a.App(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "old", app.Secret)
+	}
+	_, _ = a.App(ctx, 2)
+	assert.Equal(t, 2, provider.calls)
+
+	provider.apps[1] = models.App{ID: 1, Secret: "new"}
+	a.InvalidateApp(1)
+	assert.Equal(t, []string{"apps:1"}, invalidations.published)
+
+	app, err := a.App(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "new", app.Secret, "rotated secret is seen after invalidation")
+	_, _ = a.App(ctx, 2)
+	assert.Equal(t, 3, provider.calls, "other apps stay cached")
+
+	// Keys published by other replicas.
+	invalidations.Publish("apps:2")
+	invalidations.Publish("apps:not-an-id")
+	invalidations.Publish(invalidateUsers)
+
+	_, _ = a.App(ctx, 1)
+	_, _ = a.App(ctx, 2)
+	assert.Equal(t, 4, provider.calls)
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"grpc-service-ref/internal/lib/logger/sl"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Keys of invalidations published by caches.
+const (
+	invalidateApps   = "apps:"
+	invalidateAdmins = "admins:"
+	// invalidateUsers purges users cached by other replicas, users are cached by email,
+	// which isn't published, so emails don't leave storage.
+	invalidateUsers = "users"
+)
+
+// Invalidations spreads invalidations of cached entries between replicas, so replicas don't serve entries
+// changed by others until TTL expires. Nil Invalidations keep invalidations local.
+type Invalidations interface {
+	// Publish tells replicas that the entry of the key changed.
+	Publish(key string)
+	// Subscribe makes invalidate receive keys published by replicas, including this one.
+	Subscribe(invalidate func(key string))
+}
+
+// redisChannel is the Redis channel invalidations are published to.
+const redisChannel = "sso:cache:invalidations"
+
+// RedisInvalidations are Invalidations published to a Redis channel.
+// Invalidations published while a replica is disconnected from Redis are lost, TTL still bounds staleness.
+type RedisInvalidations struct {
+	log    *slog.Logger
+	client redis.UniversalClient
+
+	mu       sync.RWMutex
+	handlers []func(key string)
+}
+
+func NewRedisInvalidations(log *slog.Logger, client redis.UniversalClient) *RedisInvalidations {
+	return &RedisInvalidations{
+		log:    log,
+		client: client,
+	}
+}
+
+// Publish publishes the key to replicas.
+//
+// Failing to publish is logged, but doesn't fail the change, other replicas see it after TTL.
+func (r *RedisInvalidations) Publish(key string) {
+	const op = "cache.RedisInvalidations.Publish"
+
+	if err := r.client.Publish(context.Background(), redisChannel, key).Err(); err != nil {
+		r.log.Error("failed to publish cache invalidation", slog.String("op", op), slog.String("key", key), sl.Err(err))
+	}
+}
+
+// Subscribe is called by caches on startup, before Run.
+func (r *RedisInvalidations) Subscribe(invalidate func(key string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers = append(r.handlers, invalidate)
+}
+
+// Run receives invalidations published by replicas until ctx is done.
+func (r *RedisInvalidations) Run(ctx context.Context) {
+	const op = "cache.RedisInvalidations.Run"
+
+	// Subscription is reconnected by the client once Redis is back.
+	sub := r.client.Subscribe(ctx, redisChannel)
+	defer func() {
+		if err := sub.Close(); err != nil {
+			r.log.Error("failed to unsubscribe from cache invalidations", slog.String("op", op), sl.Err(err))
+		}
+	}()
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			r.mu.RLock()
+			handlers := r.handlers
+			r.mu.RUnlock()
+
+			for _, invalidate := range handlers {
+				invalidate(msg.Payload)
+			}
+		}
+	}
+}
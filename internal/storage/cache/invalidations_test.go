@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// publishes is a Redis hook recording PUBLISH commands instead of sending them to a server.
+type publishes struct {
+	messages [][2]string
+	err      error
+}
+
+func (p *publishes) DialHook(redis.DialHook) redis.DialHook {
+	return func(context.Context, string, string) (net.Conn, error) {
+		return nil, fmt.Errorf("fake redis doesn't dial")
+	}
+}
+
+func (p *publishes) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (p *publishes) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(_ context.Context, cmd redis.Cmder) error {
+		if p.err != nil {
+			cmd.SetErr(p.err)
+
+			return p.err
+		}
+
+		args := cmd.Args()
+		if cmd.Name() != "publish" {
+			err := fmt.Errorf("unexpected command %s", cmd.Name())
+			cmd.SetErr(err)
+
+			return err
+		}
+
+		p.messages = append(p.messages, [2]string{args[1].(string), args[2].(string)})
+		cmd.(*redis.IntCmd).SetVal(1)
+
+		return nil
+	}
+}
+
+func newTestInvalidations(t *testing.T) (*RedisInvalidations, *publishes) {
+	t.Helper()
+
+	p := &publishes{}
+	client := redis.NewClient(&redis.Options{Addr: "fake:6379"})
+	client.AddHook(p)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewRedisInvalidations(slog.New(slog.NewTextHandler(io.Discard, nil)), client), p
+}
+
+func TestRedisInvalidationsPublish(t *testing.T) {
+	invalidations, p := newTestInvalidations(t)
+
+	invalidations.Publish(invalidateApps + "1")
+	invalidations.Publish(invalidateUsers)
+
+	assert.Equal(t, [][2]string{{redisChannel, "apps:1"}, {redisChannel, "users"}}, p.messages)
+}
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/ttlcache"
+)
+
+// maxUsers bounds number of cached users and of their cached admin roles.
+const maxUsers = 100_000
+
+// UserStorage is storage of users, all writes changing users must go through Users,
+// so cached users are invalidated.
+type UserStorage interface {
+	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+	SaveUser(ctx context.Context, email string, passHash []byte) (uid int64, err error)
+	SaveExternalUser(ctx context.Context, email string, directory string, verified bool) (uid int64, err error)
+	VerifyUser(ctx context.Context, email string) (int64, error)
+	UpdateUser(ctx context.Context, user models.User, passHash []byte) (uid int64, err error)
+	VerifyPhone(ctx context.Context, email string, phone string) (uid int64, err error)
+	DeleteScheduledUser(ctx context.Context, userID int64, at time.Time) (email string, err error)
+}
+
+// Users caches users by email for login. Password hashes are cached too,
+// so writes are invalidated right away and changed password can't be used after the change.
+// Writes on other replicas are not seen until TTL expires, unless invalidations are published to replicas.
+//
+// Admin roles are cached by user ID separately, relying services check them on every request.
+// Roles are written by AdminService, which invalidates them by InvalidateAdmin.
+type Users struct {
+	users         UserStorage
+	cache         *ttlcache.Cache[string, models.User]
+	admins        *ttlcache.Cache[int64, bool]
+	invalidations Invalidations
+}
+
+// NewUsers returns Users caching users for ttl and admin roles for adminsTTL, zero TTL disables caching.
+// Invalidations are published to other replicas by invalidations, nil keeps them local.
+func NewUsers(users UserStorage, ttl time.Duration, adminsTTL time.Duration, invalidations Invalidations) *Users {
+	u := &Users{
+		users:         users,
+		cache:         ttlcache.New[string, models.User]("users", ttl, maxUsers),
+		admins:        ttlcache.New[int64, bool]("admins", adminsTTL, maxUsers),
+		invalidations: invalidations,
+	}
+
+	if invalidations != nil {
+		invalidations.Subscribe(u.invalidated)
+	}
+
+	return u
+}
+
+func (u *Users) User(ctx context.Context, email string) (models.User, error) {
+	return u.cache.Load(email, func() (models.User, error) {
+		return u.users.User(ctx, email)
+	})
+}
+
+// UserByID is not cached, users are looked up by ID rarely, e.g. on approved device login.
+func (u *Users) UserByID(ctx context.Context, id int64) (models.User, error) {
+	return u.users.UserByID(ctx, id)
+}
+
+func (u *Users) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	return u.admins.Load(userID, func() (bool, error) {
+		return u.users.IsAdmin(ctx, userID)
+	})
+}
+
+func (u *Users) SaveUser(ctx context.Context, email string, passHash []byte) (int64, error) {
+	return u.users.SaveUser(ctx, email, passHash)
+}
+
+func (u *Users) SaveExternalUser(ctx context.Context, email string, directory string, verified bool) (int64, error) {
+	return u.users.SaveExternalUser(ctx, email, directory, verified)
+}
+
+func (u *Users) VerifyUser(ctx context.Context, email string) (int64, error) {
+	defer u.InvalidateUser(email)
+
+	return u.users.VerifyUser(ctx, email)
+}
+
+func (u *Users) UpdateUser(ctx context.Context, user models.User, passHash []byte) (int64, error) {
+	defer u.InvalidateUser(user.Email)
+
+	return u.users.UpdateUser(ctx, user, passHash)
+}
+
+func (u *Users) VerifyPhone(ctx context.Context, email string, phone string) (int64, error) {
+	defer u.InvalidateUser(email)
+
+	return u.users.VerifyPhone(ctx, email, phone)
+}
+
+// InvalidateUser removes the user from cache, e.g. after its verification is changed by AdminService.
+// Other replicas drop all cached users, since emails aren't published.
+func (u *Users) InvalidateUser(email string) {
+	u.cache.Delete(email)
+
+	if u.invalidations != nil {
+		u.invalidations.Publish(invalidateUsers)
+	}
+}
+
+// InvalidateAdmin removes admin role of the user from cache of all replicas after it's granted or revoked.
+func (u *Users) InvalidateAdmin(userID int64) {
+	u.admins.Delete(userID)
+
+	if u.invalidations != nil {
+		u.invalidations.Publish(invalidateAdmins + strconv.FormatInt(userID, 10))
+	}
+}
+
+// invalidated removes entries invalidated by a replica.
+func (u *Users) invalidated(key string) {
+	if key == invalidateUsers {
+		u.cache.Purge()
+
+		return
+	}
+
+	if id, ok := strings.CutPrefix(key, invalidateAdmins); ok {
+		if userID, err := strconv.ParseInt(id, 10, 64); err == nil {
+			u.admins.Delete(userID)
+		}
+	}
+}
+
+func (u *Users) DeleteScheduledUser(ctx context.Context, userID int64, at time.Time) (string, error) {
+	email, err := u.users.DeleteScheduledUser(ctx, userID, at)
+	if err != nil {
+		return "", err
+	}
+
+	u.InvalidateUser(email)
+	u.InvalidateAdmin(userID)
+
+	return email, nil
+}
@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingUsers is UserStorage counting reads of users and admin roles, methods it doesn't implement aren't used by tests.
+type countingUsers struct {
+	UserStorage
+
+	users        map[string]models.User
+	admins       map[int64]bool
+	userCalls    int
+	isAdminCalls int
+}
+
+func newCountingUsers() *countingUsers {
+	return &countingUsers{
+		users:  map[string]models.User{"user@example.com": {ID: 1, Email: "user@example.com"}},
+		admins: map[int64]bool{1: true},
+	}
+}
+
+func (c *countingUsers) User(_ context.Context, email string) (models.User, error) {
+	c.userCalls++
+
+	return c.users[email], nil
+}
+
+func (c *countingUsers) IsAdmin(_ context.Context, userID int64) (bool, error) {
+	c.isAdminCalls++
+
+	return c.admins[userID], nil
+}
+
+func (c *countingUsers) VerifyUser(_ context.Context, email string) (int64, error) {
+	user := c.users[email]
+	user.Verified = true
+	c.users[email] = user
+
+	return user.ID, nil
+}
+
+func (c *countingUsers) DeleteScheduledUser(_ context.Context, userID int64, _ time.Time) (string, error) {
+	for email, user := range c.users {
+		if user.ID == userID {
+			delete(c.users, email)
+			delete(c.admins, userID)
+
+			return email, nil
+		}
+	}
+
+	return "", assert.AnError
+}
+
+// localInvalidations are Invalidations delivered to subscribers of the replica right away.
+type localInvalidations struct {
+	published []string
+	handlers  []func(key string)
+}
+
+func (l *localInvalidations) Publish(key string) {
+	l.published = append(l.published, key)
+
+	for _, invalidate := range l.handlers {
+		invalidate(key)
+	}
+}
+
+func (l *localInvalidations) Subscribe(invalidate func(key string)) {
+	l.handlers = append(l.handlers, invalidate)
+}
+
+func TestUsersUser(t *testing.T) {
+	ctx := context.Background()
+	storage := newCountingUsers()
+	u := NewUsers(storage, time.Minute, time.Minute, nil)
+
+	for i := 0; i < 2; i++ {
+		user, err := u.User(ctx, "user@example.com")
+		require.NoError(t, err)
+		assert.False(t, user.Verified)
+	}
+	assert.Equal(t, 1, storage.userCalls)
+
+	_, err := u.VerifyUser(ctx, "user@example.com")
+	require.NoError(t, err)
+
+	user, err := u.User(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, user.Verified, "writes invalidate cached user")
+	assert.Equal(t, 2, storage.userCalls)
+}
+
+func TestUsersIsAdmin(t *testing.T) {
+	ctx := context.Background()
+	storage := newCountingUsers()
+	u := NewUsers(storage, time.Minute, time.Minute, nil)
+
+	for i := 0; i < 2; i++ {
+		isAdmin, err := u.IsAdmin(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, isAdmin)
+	}
+	assert.Equal(t, 1, storage.isAdminCalls)
+
+	storage.admins[1] = false
+	u.InvalidateAdmin(1)
+
+	isAdmin, err := u.IsAdmin(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, isAdmin, "role change is seen after invalidation")
+	assert.Equal(t, 2, storage.isAdminCalls)
+}
+
+func TestUsersDisabled(t *testing.T) {
+	ctx := context.Background()
+	storage := newCountingUsers()
+	u := NewUsers(storage, 0, 0, nil)
+
+	for i := 0; i < 2; i++ {
+		_, _ = u.User(ctx, "user@example.com")
+		_, _ = u.IsAdmin(ctx, 1)
+	}
+	assert.Equal(t, 2, storage.userCalls)
+	assert.Equal(t, 2, storage.isAdminCalls)
+}
+
+func TestUsersInvalidations(t *testing.T) {
+	ctx := context.Background()
+	storage := newCountingUsers()
+	invalidations := &localInvalidations{}
+	u := NewUsers(storage, time.Minute, time.Minute, invalidations)
+
+	_, _ = u.User(ctx, "user@example.com")
+	_, _ = u.IsAdmin(ctx, 1)
+	_, _ = u.IsAdmin(ctx, 2)
+
+	u.InvalidateUser("user@example.com")
+	u.InvalidateAdmin(1)
+	assert.Equal(t, []string{invalidateUsers, "admins:1"}, invalidations.published, "emails aren't published")
+
+	_, _ = u.User(ctx, "user@example.com")
+	_, _ = u.IsAdmin(ctx, 1)
+	_, _ = u.IsAdmin(ctx, 2)
+	assert.Equal(t, 2, storage.userCalls)
+	assert.Equal(t, 3, storage.isAdminCalls, "other admin roles stay cached")
+
+	// Keys published by other replicas.
+	invalidations.Publish(invalidateUsers)
+	invalidations.Publish("admins:2")
+	invalidations.Publish("admins:not-an-id")
+
+	_, _ = u.User(ctx, "user@example.com")
+	_, _ = u.IsAdmin(ctx, 1)
+	_, _ = u.IsAdmin(ctx, 2)
+	assert.Equal(t, 3, storage.userCalls)
+	assert.Equal(t, 4, storage.isAdminCalls)
+}
+
+func TestUsersDeleteScheduledUser(t *testing.T) {
+	ctx := context.Background()
+	storage := newCountingUsers()
+	invalidations := &localInvalidations{}
+	u := NewUsers(storage, time.Minute, time.Minute, invalidations)
+
+	_, _ = u.User(ctx, "user@example.com")
+	_, _ = u.IsAdmin(ctx, 1)
+
+	email, err := u.DeleteScheduledUser(ctx, 1, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", email)
+	assert.Equal(t, []string{invalidateUsers, "admins:1"}, invalidations.published)
+
+	user, _ := u.User(ctx, "user@example.com")
+	isAdmin, _ := u.IsAdmin(ctx, 1)
+	assert.Zero(t, user.ID, "deleted user isn't cached")
+	assert.False(t, isAdmin)
+
+	_, err = u.DeleteScheduledUser(ctx, 2, time.Now())
+	assert.Error(t, err)
+	assert.Len(t, invalidations.published, 2, "nothing is invalidated on failure")
+}
@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"grpc-service-ref/internal/lib/tracing"
+)
+
+// Emails and phones of users are stored in two columns if encryption is enabled:
+// the original column holds blind index used for lookups and uniqueness, *_enc column holds ciphertext.
+// Rows with NULL *_enc are in plaintext, they are encrypted by EncryptUsers.
+
+// lookup returns value of the lookup column for the email or phone: its blind index if encryption is enabled.
+func (s *Storage) lookup(value string) string {
+	if s.cipher == nil || value == "" {
+		return value
+	}
+
+	return s.cipher.Index(value)
+}
+
+// seal returns value of the lookup column and ciphertext of the email or phone, ciphertext is nil if encryption is disabled.
+func (s *Storage) seal(value string) (string, []byte, error) {
+	if s.cipher == nil || value == "" {
+		return value, nil, nil
+	}
+
+	ciphertext, err := s.cipher.Encrypt(value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return s.cipher.Index(value), ciphertext, nil
+}
+
+// unseal returns the email or phone, stored is value of the lookup column which is used as is if it's not encrypted.
+func (s *Storage) unseal(stored string, ciphertext []byte) (string, error) {
+	if ciphertext == nil {
+		return stored, nil
+	}
+
+	if s.cipher == nil {
+		return "", errors.New("field is encrypted, but encryption is not configured")
+	}
+
+	return s.cipher.Decrypt(ciphertext)
+}
+
+// EncryptUsers encrypts emails and phones of users and phone verifications stored in plaintext,
+// e.g. before encryption was enabled, and returns number of encrypted users. It's a no-op if encryption is disabled.
+func (s *Storage) EncryptUsers(ctx context.Context) (int64, error) {
+	const op = "storage.sqlite.EncryptUsers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if s.cipher == nil {
+		return 0, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	type plainUser struct {
+		id    int64
+		email string
+		phone string
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, email, COALESCE(phone, '') FROM users WHERE email_enc IS NULL")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var users []plainUser
+	for rows.Next() {
+		var u plainUser
+		if err := rows.Scan(&u.id, &u.email, &u.phone); err != nil {
+			rows.Close()
+
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, u)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, u := range users {
+		emailKey, emailEnc, err := s.seal(u.email)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		phoneKey, phoneEnc, err := s.seal(u.phone)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE users SET email = ?, email_enc = ?, phone = NULLIF(?, ''), phone_enc = ? WHERE id = ?",
+			emailKey, emailEnc, phoneKey, phoneEnc, u.id,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// Phone verifications reference users by email.
+		if _, err := tx.ExecContext(ctx, "UPDATE phone_verifications SET email = ? WHERE email = ?", emailKey, u.email); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := s.encryptPhoneVerifications(ctx, tx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int64(len(users)), nil
+}
+
+// encryptPhoneVerifications encrypts phones of pending phone verifications stored in plaintext.
+func (s *Storage) encryptPhoneVerifications(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "SELECT email, phone FROM phone_verifications WHERE phone_enc IS NULL")
+	if err != nil {
+		return err
+	}
+
+	phones := make(map[string]string)
+	for rows.Next() {
+		var email, phone string
+		if err := rows.Scan(&email, &phone); err != nil {
+			rows.Close()
+
+			return err
+		}
+
+		phones[email] = phone
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for email, phone := range phones {
+		phoneKey, phoneEnc, err := s.seal(phone)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE phone_verifications SET phone = ?, phone_enc = ? WHERE email = ?", phoneKey, phoneEnc, email)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,21 +3,33 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/tracing"
 	"grpc-service-ref/internal/storage"
 
 	"github.com/mattn/go-sqlite3"
 )
 
+// FieldCipher encrypts emails and phones of users, see fieldcrypt.Cipher.
+type FieldCipher interface {
+	Encrypt(value string) ([]byte, error)
+	Decrypt(ciphertext []byte) (string, error)
+	Index(value string) string
+}
+
 type Storage struct {
 	db *sql.DB
+	// cipher is nil if emails and phones of users are stored in plaintext.
+	cipher FieldCipher
 }
 
-func New(storagePath string) (*Storage, error) {
+func New(storagePath string, cipher FieldCipher) (*Storage, error) {
 	const op = "storage.sqlite.New"
 
 	db, err := sql.Open("sqlite3", storagePath)
@@ -25,23 +37,91 @@ func New(storagePath string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, cipher: cipher}, nil
+}
+
+// SetPool limits connection pool of db, see sql.DB for meaning of zero values.
+func (s *Storage) SetPool(maxOpenConns int, maxIdleConns int, connMaxLifetime time.Duration) {
+	s.db.SetMaxOpenConns(maxOpenConns)
+	s.db.SetMaxIdleConns(maxIdleConns)
+	s.db.SetConnMaxLifetime(connMaxLifetime)
+}
+
+// Stats returns connection pool statistics of db.
+func (s *Storage) Stats() sql.DBStats {
+	return s.db.Stats()
 }
 
 func (s *Storage) Stop() error {
 	return s.db.Close()
 }
 
+// Ping checks that db is reachable.
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.sqlite.Ping"
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 // SaveUser saves user to db.
 func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (int64, error) {
 	const op = "storage.sqlite.SaveUser"
 
-	stmt, err := s.db.Prepare("INSERT INTO users(email, pass_hash) VALUES(?, ?)")
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	emailKey, emailEnc, err := s.seal(email)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare("INSERT INTO users(email, email_enc, pass_hash, created_at) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, emailKey, emailEnc, passHash, time.Now().UTC())
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// SaveExternalUser saves user authenticated by the external user directory. The user has no password hash,
+// so it can't log in with local credentials.
+func (s *Storage) SaveExternalUser(ctx context.Context, email string, directory string, verified bool) (int64, error) {
+	const op = "storage.sqlite.SaveExternalUser"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	emailKey, emailEnc, err := s.seal(email)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO users(email, email_enc, pass_hash, is_verified, directory, created_at) VALUES(?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res, err := stmt.ExecContext(ctx, email, passHash)
+	res, err := stmt.ExecContext(ctx, emailKey, emailEnc, []byte{}, verified, directory, time.Now().UTC())
 	if err != nil {
 		var sqliteErr sqlite3.Error
 		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
@@ -62,21 +142,28 @@ func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (
 func (s *Storage) UpdateUser(ctx context.Context, user models.User, passHash []byte) (int64, error) {
 	const op = "storage.sqlite.updateuser"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	stmt, err := s.db.Prepare("UPDATE users SET email = ?, pass_hash = ?, is_verified = ? WHERE email = ?")
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res, err := stmt.ExecContext(ctx, user.Email, passHash, user.Verified, user.Email)
+	res, err := stmt.ExecContext(ctx, s.lookup(user.Email), passHash, user.Verified, s.lookup(user.Email))
 	if err != nil {
-		var sqliteErr sqlite3.Error
-		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sql.ErrNoRows {
-			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
-		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
 
+	n, err := res.RowsAffected()
+	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if n == 0 {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
 	id, err := res.LastInsertId()
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
@@ -88,21 +175,28 @@ func (s *Storage) UpdateUser(ctx context.Context, user models.User, passHash []b
 func (s *Storage) VerifyUser(ctx context.Context, email string) (int64, error) {
 	const op = "storage.sqlite.VerifyUser"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	stmt, err := s.db.Prepare("UPDATE users SET is_verified = true WHERE email = ?")
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res, err := stmt.ExecContext(ctx, email)
+	res, err := stmt.ExecContext(ctx, s.lookup(email))
 	if err != nil {
-		var sqliteErr sqlite3.Error
-		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sql.ErrNoRows {
-			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
-		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
 
+	n, err := res.RowsAffected()
+	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if n == 0 {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
 	id, err := res.LastInsertId()
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
@@ -115,15 +209,67 @@ func (s *Storage) VerifyUser(ctx context.Context, email string) (int64, error) {
 func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 	const op = "storage.sqlite.User"
 
-	stmt, err := s.db.Prepare("SELECT id, email, pass_hash FROM users WHERE email = ?")
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, email, email_enc, pass_hash, is_verified, COALESCE(phone, ''), phone_enc, is_phone_verified, directory
+		FROM users WHERE email = ?`)
 	if err != nil {
 		return models.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	row := stmt.QueryRowContext(ctx, email)
+	row := stmt.QueryRowContext(ctx, s.lookup(email))
+
+	var (
+		user               models.User
+		emailEnc, phoneEnc []byte
+	)
+
+	err = row.Scan(
+		&user.ID, &user.Email, &emailEnc, &user.PassHash, &user.Verified, &user.Phone, &phoneEnc, &user.PhoneVerified, &user.Directory,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Email, err = s.unseal(user.Email, emailEnc); err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Phone, err = s.unseal(user.Phone, phoneEnc); err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// UserByID returns user by ID.
+func (s *Storage) UserByID(ctx context.Context, id int64) (models.User, error) {
+	const op = "storage.sqlite.UserByID"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, email, email_enc, pass_hash, is_verified, COALESCE(phone, ''), phone_enc, is_phone_verified, directory
+		FROM users WHERE id = ?`)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
 
-	var user models.User
-	err = row.Scan(&user.ID, &user.Email, &user.PassHash)
+	var (
+		user               models.User
+		emailEnc, phoneEnc []byte
+	)
+
+	err = stmt.QueryRowContext(ctx, id).Scan(
+		&user.ID, &user.Email, &emailEnc, &user.PassHash, &user.Verified, &user.Phone, &phoneEnc, &user.PhoneVerified, &user.Directory,
+	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
@@ -132,9 +278,145 @@ func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 		return models.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if user.Email, err = s.unseal(user.Email, emailEnc); err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Phone, err = s.unseal(user.Phone, phoneEnc); err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return user, nil
 }
 
+// Users returns page of users ordered by ID, starting after afterID.
+func (s *Storage) Users(ctx context.Context, afterID int64, limit int) ([]models.User, error) {
+	const op = "storage.sqlite.Users"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, email, email_enc, pass_hash, is_verified, COALESCE(phone, ''), phone_enc, is_phone_verified, directory
+		FROM users WHERE id > ? ORDER BY id LIMIT ?`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var (
+			user               models.User
+			emailEnc, phoneEnc []byte
+		)
+
+		err := rows.Scan(
+			&user.ID, &user.Email, &emailEnc, &user.PassHash, &user.Verified, &user.Phone, &phoneEnc, &user.PhoneVerified, &user.Directory,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if user.Email, err = s.unseal(user.Email, emailEnc); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if user.Phone, err = s.unseal(user.Phone, phoneEnc); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// UsersToRemind returns unverified users registered before registeredBefore who were reminded
+// to verify their email less than maxReminders times, last time before remindedBefore.
+func (s *Storage) UsersToRemind(
+	ctx context.Context,
+	registeredBefore time.Time,
+	remindedBefore time.Time,
+	maxReminders int,
+	limit int,
+) ([]models.User, error) {
+	const op = "storage.sqlite.UsersToRemind"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT u.id, u.email, u.email_enc
+		FROM users u LEFT JOIN verification_reminders r ON r.user_id = u.id
+		WHERE NOT u.is_verified AND u.created_at <= ?
+			AND (r.user_id IS NULL OR (r.sent < ? AND r.last_sent_at <= ?))
+		ORDER BY u.id LIMIT ?`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, registeredBefore, maxReminders, remindedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var (
+			user     models.User
+			emailEnc []byte
+		)
+
+		if err := rows.Scan(&user.ID, &user.Email, &emailEnc); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if user.Email, err = s.unseal(user.Email, emailEnc); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// SaveVerificationReminder counts reminder to verify email sent to the user at the time.
+func (s *Storage) SaveVerificationReminder(ctx context.Context, userID int64, at time.Time) error {
+	const op = "storage.sqlite.SaveVerificationReminder"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO verification_reminders(user_id, sent, last_sent_at) VALUES(?, 1, ?)
+		ON CONFLICT(user_id) DO UPDATE SET sent = sent + 1, last_sent_at = excluded.last_sent_at`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, userID, at); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 //func (s *Storage) SavePermission(ctx context.Context, userID int64, permission models.Permission, appID string) error {
 //	const op = "storage.sqlite.SavePermission"
 //
@@ -155,15 +437,17 @@ func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 func (s *Storage) App(ctx context.Context, id int) (models.App, error) {
 	const op = "storage.sqlite.App"
 
-	stmt, err := s.db.Prepare("SELECT id, name, secret FROM apps WHERE id = ?")
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, name, secret, require_verified, allowed_origins, content_security_policy, issuer, audience
+		FROM apps WHERE id = ?`)
 	if err != nil {
 		return models.App{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	row := stmt.QueryRowContext(ctx, id)
-
-	var app models.App
-	err = row.Scan(&app.ID, &app.Name, &app.Secret)
+	app, err := scanApp(stmt.QueryRowContext(ctx, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.App{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
@@ -175,89 +459,3141 @@ func (s *Storage) App(ctx context.Context, id int) (models.App, error) {
 	return app, nil
 }
 
-func (s *Storage) IsAdmin(ctx context.Context, userID int64) (bool, error) {
-	const op = "storage.sqlite.IsAdmin"
+// Apps returns page of apps ordered by ID, starting after afterID.
+func (s *Storage) Apps(ctx context.Context, afterID int, limit int) ([]models.App, error) {
+	const op = "storage.sqlite.Apps"
 
-	stmt, err := s.db.Prepare("SELECT is_admin FROM users WHERE id = ?")
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, name, secret, require_verified, allowed_origins, content_security_policy, issuer, audience
+		FROM apps WHERE id > ? ORDER BY id LIMIT ?`)
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	row := stmt.QueryRowContext(ctx, userID)
+	apps, err := queryApps(ctx, stmt, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	var isAdmin bool
+	return apps, nil
+}
 
-	err = row.Scan(&isAdmin)
+// AppsWithOrigins returns apps with allowed origins of their frontends.
+func (s *Storage) AppsWithOrigins(ctx context.Context) ([]models.App, error) {
+	const op = "storage.sqlite.AppsWithOrigins"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, name, secret, require_verified, allowed_origins, content_security_policy, issuer, audience
+		FROM apps WHERE allowed_origins != '' ORDER BY id`)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	apps, err := queryApps(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+func queryApps(ctx context.Context, stmt *sql.Stmt, args ...any) ([]models.App, error) {
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var apps []models.App
+	for rows.Next() {
+		app, err := scanApp(rows)
+		if err != nil {
+			return nil, err
 		}
 
-		return false, fmt.Errorf("%s: %w", op, err)
+		apps = append(apps, app)
 	}
 
-	return isAdmin, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return apps, nil
 }
 
-func (s *Storage) StoreVerification(ctx context.Context, email string, code string, expiresAt time.Time) (models.VerificationData, error) {
-	const op = "storage.sqlite.StoreVerification"
+func scanApp(row scanner) (models.App, error) {
+	var (
+		app     models.App
+		origins string
+	)
+	if err := row.Scan(&app.ID, &app.Name, &app.Secret, &app.RequireVerified, &origins, &app.ContentSecurityPolicy, &app.Issuer, &app.Audience); err != nil {
+		return models.App{}, err
+	}
 
-	stmt, err := s.db.Prepare("INSERT INTO verifications(email, code, expiresAt) VALUES(?, ?, ?)")
+	app.AllowedOrigins = strings.Fields(origins)
+
+	return app, nil
+}
+
+// SaveApp saves app to db.
+func (s *Storage) SaveApp(ctx context.Context, name string, secret string) (int, error) {
+	const op = "storage.sqlite.SaveApp"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("INSERT INTO apps(name, secret) VALUES(?, ?)")
 	if err != nil {
-		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res, err := stmt.ExecContext(ctx, email, code, expiresAt)
+	res, err := stmt.ExecContext(ctx, name, secret)
 	if err != nil {
 		var sqliteErr sqlite3.Error
 		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
-			return models.VerificationData{}, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAppExists)
 		}
 
-		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res.RowsAffected()
-	// _, err := res.LastInsertId()
-	// if err != nil {
-	// 	return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
-	// }
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
 
-	return models.VerificationData{}, nil
+	return int(id), nil
 }
 
-func (s *Storage) Verification(ctx context.Context, email string) (models.VerificationData, error) {
-	const op = "storage.sqlite.Verification"
+// UpdateAppSecret replaces secret of the app.
+func (s *Storage) UpdateAppSecret(ctx context.Context, appID int, secret string) error {
+	const op = "storage.sqlite.UpdateAppSecret"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	stmt, err := s.db.Prepare("SELECT * FROM verifications WHERE email = ?")
+	stmt, err := s.db.Prepare("UPDATE apps SET secret = ? WHERE id = ?")
 	if err != nil {
-		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	row := stmt.QueryRowContext(ctx, email)
-	var verification models.VerificationData
-	err = row.Scan(&verification.Email, &verification.Code, &verification.ExpiresAt)
+	res, err := stmt.ExecContext(ctx, secret, appID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return models.VerificationData{}, fmt.Errorf("%s: %w", op, storage.ErrVerificationNotFound)
-		}
-		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	return verification, nil
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
 }
 
-func (s *Storage) DeleteVerification(ctx context.Context, email string) error {
-	const op = "storage.sqlite.DeleteVerification"
+// UpdateAppWebSettings replaces allowed origins of frontends of the app and their content security policy.
+func (s *Storage) UpdateAppWebSettings(ctx context.Context, appID int, origins []string, csp string) error {
+	const op = "storage.sqlite.UpdateAppWebSettings"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	stmt, err := s.db.Prepare("DELETE from verifications WHERE email = ?")
+	stmt, err := s.db.Prepare("UPDATE apps SET allowed_origins = ?, content_security_policy = ? WHERE id = ?")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	res, err := stmt.ExecContext(ctx, email)
+	res, err := stmt.ExecContext(ctx, strings.Join(origins, " "), csp, appID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	_ = res
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// UpdateAppTokenClaims replaces issuer and audience of tokens of the app.
+func (s *Storage) UpdateAppTokenClaims(ctx context.Context, appID int, issuer string, audience string) error {
+	const op = "storage.sqlite.UpdateAppTokenClaims"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE apps SET issuer = ?, audience = ? WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, issuer, audience, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
 	return nil
 }
+
+func (s *Storage) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	const op = "storage.sqlite.IsAdmin"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT is_admin FROM users WHERE id = ?")
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := stmt.QueryRowContext(ctx, userID)
+
+	var isAdmin bool
+
+	err = row.Scan(&isAdmin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return isAdmin, nil
+}
+
+// SetAdmin grants or revokes admin role of the user.
+func (s *Storage) SetAdmin(ctx context.Context, userID int64, isAdmin bool) error {
+	const op = "storage.sqlite.SetAdmin"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE users SET is_admin = ? WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, isAdmin, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// SetUserVerified sets whether email of the user is verified.
+func (s *Storage) SetUserVerified(ctx context.Context, userID int64, verified bool) error {
+	const op = "storage.sqlite.SetUserVerified"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE users SET is_verified = ? WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, verified, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// StoreVerification saves verification of the given type.
+// Pending verification of the same type is replaced and its attempts counter is reset.
+func (s *Storage) StoreVerification(
+	ctx context.Context,
+	email string,
+	vType models.VerificationType,
+	code string,
+	expiresAt time.Time,
+) (models.VerificationData, error) {
+	const op = "storage.sqlite.StoreVerification"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO verifications(email, type, code, expiresAt, attempts) VALUES(?, ?, ?, ?, 0)
+		ON CONFLICT(email, type) DO UPDATE SET code = excluded.code, expiresAt = excluded.expiresAt, attempts = 0`)
+	if err != nil {
+		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, email, vType, code, expiresAt)
+	if err != nil {
+		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.VerificationData{
+		Email:     email,
+		Type:      vType,
+		Code:      code,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *Storage) Verification(ctx context.Context, email string, vType models.VerificationType) (models.VerificationData, error) {
+	const op = "storage.sqlite.Verification"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT email, type, code, expiresat, attempts FROM verifications WHERE email = ? AND type = ?")
+	if err != nil {
+		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := stmt.QueryRowContext(ctx, email, vType)
+	var verification models.VerificationData
+	err = row.Scan(&verification.Email, &verification.Type, &verification.Code, &verification.ExpiresAt, &verification.Attempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.VerificationData{}, fmt.Errorf("%s: %w", op, storage.ErrVerificationNotFound)
+		}
+		return models.VerificationData{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return verification, nil
+}
+
+// IncrementVerificationAttempts increments failed attempts counter of the verification and returns its new value.
+func (s *Storage) IncrementVerificationAttempts(ctx context.Context, email string, vType models.VerificationType) (int, error) {
+	const op = "storage.sqlite.IncrementVerificationAttempts"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE verifications SET attempts = attempts + 1 WHERE email = ? AND type = ? RETURNING attempts")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var attempts int
+	err = stmt.QueryRowContext(ctx, email, vType).Scan(&attempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrVerificationNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attempts, nil
+}
+
+func (s *Storage) DeleteVerification(ctx context.Context, email string, vType models.VerificationType) error {
+	const op = "storage.sqlite.DeleteVerification"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("DELETE from verifications WHERE email = ? AND type = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, email, vType)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	_ = res
+	return nil
+}
+
+// StorePhoneVerification saves verification of the phone number of the user.
+// Pending phone verification of the user is replaced and its attempts counter is reset.
+func (s *Storage) StorePhoneVerification(
+	ctx context.Context,
+	email string,
+	phone string,
+	code string,
+	expiresAt time.Time,
+) error {
+	const op = "storage.sqlite.StorePhoneVerification"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	phoneKey, phoneEnc, err := s.seal(phone)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO phone_verifications(email, phone, phone_enc, code, expiresAt, attempts)
+		SELECT email, ?, ?, ?, ?, 0 FROM users WHERE email = ?
+		ON CONFLICT(email) DO UPDATE SET
+			phone = excluded.phone, phone_enc = excluded.phone_enc, code = excluded.code,
+			expiresAt = excluded.expiresAt, attempts = 0`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, phoneKey, phoneEnc, code, expiresAt, s.lookup(email))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (s *Storage) PhoneVerification(ctx context.Context, email string) (models.PhoneVerificationData, error) {
+	const op = "storage.sqlite.PhoneVerification"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT phone, phone_enc, code, expiresat, attempts FROM phone_verifications WHERE email = ?")
+	if err != nil {
+		return models.PhoneVerificationData{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Email is stored as blind index if encryption is enabled, so it's taken from the argument.
+	verification := models.PhoneVerificationData{Email: email}
+
+	var phoneEnc []byte
+	err = stmt.QueryRowContext(ctx, s.lookup(email)).Scan(
+		&verification.Phone,
+		&phoneEnc,
+		&verification.Code,
+		&verification.ExpiresAt,
+		&verification.Attempts,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.PhoneVerificationData{}, fmt.Errorf("%s: %w", op, storage.ErrVerificationNotFound)
+		}
+
+		return models.PhoneVerificationData{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if verification.Phone, err = s.unseal(verification.Phone, phoneEnc); err != nil {
+		return models.PhoneVerificationData{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return verification, nil
+}
+
+// IncrementPhoneVerificationAttempts increments failed attempts counter of the phone verification and returns its new value.
+func (s *Storage) IncrementPhoneVerificationAttempts(ctx context.Context, email string) (int, error) {
+	const op = "storage.sqlite.IncrementPhoneVerificationAttempts"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE phone_verifications SET attempts = attempts + 1 WHERE email = ? RETURNING attempts")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var attempts int
+	err = stmt.QueryRowContext(ctx, s.lookup(email)).Scan(&attempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrVerificationNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attempts, nil
+}
+
+func (s *Storage) DeletePhoneVerification(ctx context.Context, email string) error {
+	const op = "storage.sqlite.DeletePhoneVerification"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("DELETE FROM phone_verifications WHERE email = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, s.lookup(email)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// VerifyPhone sets verified phone number of the user and returns user ID.
+func (s *Storage) VerifyPhone(ctx context.Context, email string, phone string) (int64, error) {
+	const op = "storage.sqlite.VerifyPhone"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	phoneKey, phoneEnc, err := s.seal(phone)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare("UPDATE users SET phone = ?, phone_enc = ?, is_phone_verified = true WHERE email = ? RETURNING id")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err = stmt.QueryRowContext(ctx, phoneKey, phoneEnc, s.lookup(email)).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// SaveEmail saves email delivery record to db.
+// User ID is resolved by recipient email, so bounces can be correlated with users.
+func (s *Storage) SaveEmail(ctx context.Context, email models.Email) (int64, error) {
+	const op = "storage.sqlite.SaveEmail"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO emails(message_id, recipient, user_id, subject, status, reason, created_at, updated_at)
+		VALUES(?, ?, (SELECT id FROM users WHERE email = ?), ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx,
+		email.MessageID,
+		email.Recipient,
+		s.lookup(email.Recipient),
+		email.Subject,
+		email.Status,
+		email.Reason,
+		email.CreatedAt,
+		email.UpdatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// UpdateEmailStatus updates delivery status of the message sent to recipient.
+func (s *Storage) UpdateEmailStatus(
+	ctx context.Context,
+	messageID string,
+	recipient string,
+	status models.EmailStatus,
+	reason string,
+) error {
+	const op = "storage.sqlite.UpdateEmailStatus"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE emails SET status = ?, reason = ?, updated_at = ? WHERE message_id = ? AND recipient = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, status, reason, time.Now().UTC(), messageID, recipient)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrEmailNotFound)
+	}
+
+	return nil
+}
+
+// Emails returns delivery records of the message with given message ID.
+func (s *Storage) Emails(ctx context.Context, messageID string) ([]models.Email, error) {
+	const op = "storage.sqlite.Emails"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, message_id, recipient, COALESCE(user_id, 0), subject, status, reason, created_at, updated_at
+		FROM emails WHERE message_id = ?`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var emails []models.Email
+	for rows.Next() {
+		var email models.Email
+		err := rows.Scan(
+			&email.ID,
+			&email.MessageID,
+			&email.Recipient,
+			&email.UserID,
+			&email.Subject,
+			&email.Status,
+			&email.Reason,
+			&email.CreatedAt,
+			&email.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		emails = append(emails, email)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(emails) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrEmailNotFound)
+	}
+
+	return emails, nil
+}
+
+// SaveSuppression adds email to the suppression list.
+// If email is already suppressed, its source and reason are updated.
+func (s *Storage) SaveSuppression(ctx context.Context, suppression models.Suppression) error {
+	const op = "storage.sqlite.SaveSuppression"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO suppressions(email, source, reason, created_at) VALUES(?, ?, ?, ?)
+		ON CONFLICT(email) DO UPDATE SET source = excluded.source, reason = excluded.reason`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, suppression.Email, suppression.Source, suppression.Reason, suppression.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Suppression returns suppression of the given email.
+func (s *Storage) Suppression(ctx context.Context, email string) (models.Suppression, error) {
+	const op = "storage.sqlite.Suppression"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT email, source, reason, created_at FROM suppressions WHERE email = ?")
+	if err != nil {
+		return models.Suppression{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := stmt.QueryRowContext(ctx, email)
+
+	var suppression models.Suppression
+	err = row.Scan(&suppression.Email, &suppression.Source, &suppression.Reason, &suppression.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Suppression{}, fmt.Errorf("%s: %w", op, storage.ErrSuppressionNotFound)
+		}
+
+		return models.Suppression{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return suppression, nil
+}
+
+// Suppressions returns page of suppressed emails, newest first.
+func (s *Storage) Suppressions(ctx context.Context, limit int, offset int) ([]models.Suppression, error) {
+	const op = "storage.sqlite.Suppressions"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT email, source, reason, created_at FROM suppressions ORDER BY created_at DESC, email LIMIT ? OFFSET ?")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var suppressions []models.Suppression
+	for rows.Next() {
+		var suppression models.Suppression
+		if err := rows.Scan(&suppression.Email, &suppression.Source, &suppression.Reason, &suppression.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		suppressions = append(suppressions, suppression)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return suppressions, nil
+}
+
+// DeleteSuppression removes email from the suppression list.
+func (s *Storage) DeleteSuppression(ctx context.Context, email string) error {
+	const op = "storage.sqlite.DeleteSuppression"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("DELETE FROM suppressions WHERE email = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, email)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSuppressionNotFound)
+	}
+
+	return nil
+}
+
+// SaveNotificationPreferences replaces notification preferences of the user.
+func (s *Storage) SaveNotificationPreferences(
+	ctx context.Context,
+	userID int64,
+	prefs models.NotificationPreferences,
+	at time.Time,
+) error {
+	const op = "storage.sqlite.SaveNotificationPreferences"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO notification_preferences(user_id, reminders, security, product, updated_at) VALUES(?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			reminders = excluded.reminders,
+			security = excluded.security,
+			product = excluded.product,
+			updated_at = excluded.updated_at`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, userID, prefs.Reminders, prefs.Security, prefs.Product, at); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// NotificationPreferences returns notification preferences of the user with the email. Recipients who are not
+// users or haven't changed preferences get models.DefaultNotificationPreferences.
+func (s *Storage) NotificationPreferences(ctx context.Context, email string) (models.NotificationPreferences, error) {
+	const op = "storage.sqlite.NotificationPreferences"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT p.reminders, p.security, p.product
+		FROM notification_preferences p
+		JOIN users u ON u.id = p.user_id
+		WHERE u.email = ?`)
+	if err != nil {
+		return models.NotificationPreferences{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var prefs models.NotificationPreferences
+	err = stmt.QueryRowContext(ctx, s.lookup(email)).Scan(&prefs.Reminders, &prefs.Security, &prefs.Product)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DefaultNotificationPreferences(), nil
+		}
+
+		return models.NotificationPreferences{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prefs, nil
+}
+
+// SavePendingRegistration saves registration which becomes a user once email is verified.
+// Expired pending registration of the same email is replaced,
+// if email belongs to a user or has a pending registration, ErrUserExists is returned.
+func (s *Storage) SavePendingRegistration(
+	ctx context.Context,
+	email string,
+	passHash []byte,
+	expiresAt time.Time,
+) error {
+	const op = "storage.sqlite.SavePendingRegistration"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE email = ?)", s.lookup(email)).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if exists {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+	}
+
+	now := time.Now().UTC()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO pending_registrations(email, pass_hash, created_at, expires_at) VALUES(?, ?, ?, ?)
+		ON CONFLICT(email) DO UPDATE SET
+			pass_hash = excluded.pass_hash, created_at = excluded.created_at, expires_at = excluded.expires_at
+		WHERE pending_registrations.expires_at < ?`,
+		email, passHash, now, expiresAt, now,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ActivatePendingRegistration creates verified user from the pending registration and returns its ID.
+func (s *Storage) ActivatePendingRegistration(ctx context.Context, email string) (int64, error) {
+	const op = "storage.sqlite.ActivatePendingRegistration"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var passHash []byte
+	err = tx.QueryRowContext(ctx,
+		"SELECT pass_hash FROM pending_registrations WHERE email = ? AND expires_at >= ?",
+		email, time.Now().UTC(),
+	).Scan(&passHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrPendingRegistrationNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	emailKey, emailEnc, err := s.seal(email)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx,
+		"INSERT INTO users(email, email_enc, pass_hash, is_verified, created_at) VALUES(?, ?, ?, true, ?)",
+		emailKey, emailEnc, passHash, time.Now().UTC(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pending_registrations WHERE email = ?", email); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// SaveAuditEvent appends event to the audit log.
+func (s *Storage) SaveAuditEvent(ctx context.Context, event models.AuditEvent) (int64, error) {
+	const op = "storage.sqlite.SaveAuditEvent"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO audit_events(action, actor_id, subject, app_id, ip, country, city, payload, created_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, event.Action, event.ActorID, event.Subject, event.AppID, event.IP, event.Country, event.City, string(payload), event.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// SaveAuditEvents saves events in a single transaction, either all or none of them are saved.
+func (s *Storage) SaveAuditEvents(ctx context.Context, events []models.AuditEvent) error {
+	const op = "storage.sqlite.SaveAuditEvents"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO audit_events(action, actor_id, subject, app_id, ip, country, city, payload, created_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = stmt.ExecContext(ctx, event.Action, event.ActorID, event.Subject, event.AppID, event.IP, event.Country, event.City, string(payload), event.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AuditEvents returns page of audit events matching the filter, newest first.
+func (s *Storage) AuditEvents(ctx context.Context, filter models.AuditFilter, limit int) ([]models.AuditEvent, error) {
+	const op = "storage.sqlite.AuditEvents"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	var (
+		where []string
+		args  []any
+	)
+	if filter.Action != "" {
+		where, args = append(where, "action = ?"), append(args, filter.Action)
+	}
+	if len(filter.Actions) > 0 {
+		where = append(where, "action IN (?"+strings.Repeat(", ?", len(filter.Actions)-1)+")")
+		for _, action := range filter.Actions {
+			args = append(args, action)
+		}
+	}
+	if filter.ActorID != 0 {
+		where, args = append(where, "actor_id = ?"), append(args, filter.ActorID)
+	}
+	if filter.Subject != "" {
+		where, args = append(where, "subject = ?"), append(args, filter.Subject)
+	}
+	if filter.AppID != 0 {
+		where, args = append(where, "app_id = ?"), append(args, filter.AppID)
+	}
+	if filter.Country != "" {
+		where, args = append(where, "country = ?"), append(args, filter.Country)
+	}
+	if !filter.Since.IsZero() {
+		where, args = append(where, "created_at >= ?"), append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		where, args = append(where, "created_at < ?"), append(args, filter.Until)
+	}
+	if filter.BeforeID != 0 {
+		where, args = append(where, "id < ?"), append(args, filter.BeforeID)
+	}
+
+	query := "SELECT id, action, actor_id, subject, app_id, ip, country, city, payload, created_at FROM audit_events"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		var (
+			event   models.AuditEvent
+			payload []byte
+		)
+		err := rows.Scan(&event.ID, &event.Action, &event.ActorID, &event.Subject, &event.AppID, &event.IP, &event.Country, &event.City, &payload, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := json.Unmarshal(payload, &event.Payload); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// AuditStats aggregates registrations and logins audited in [since, until).
+// Registrations are counted by distinct emails, so repeated pending registrations count once.
+func (s *Storage) AuditStats(ctx context.Context, since time.Time, until time.Time) (models.Stats, error) {
+	const op = "storage.sqlite.AuditStats"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT
+			COUNT(DISTINCT CASE WHEN action = ? THEN subject END),
+			COUNT(DISTINCT CASE WHEN action = ? THEN actor_id END),
+			COALESCE(SUM(action = ?), 0),
+			COALESCE(SUM(action = ?), 0),
+			(SELECT COUNT(DISTINCT r.subject) FROM audit_events r
+				WHERE r.action = ? AND r.created_at >= ? AND r.created_at < ?
+				AND EXISTS (SELECT 1 FROM audit_events v
+					WHERE v.subject = r.subject AND v.action = ? AND v.created_at >= r.created_at))
+		FROM audit_events
+		WHERE action IN (?, ?, ?) AND created_at >= ? AND created_at < ?`)
+	if err != nil {
+		return models.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats := models.Stats{Since: since, Until: until}
+
+	err = stmt.QueryRowContext(ctx,
+		models.AuditActionRegistered,
+		models.AuditActionLoginSucceeded,
+		models.AuditActionLoginSucceeded,
+		models.AuditActionLoginFailed,
+		models.AuditActionRegistered, since, until, models.AuditActionEmailVerified,
+		models.AuditActionRegistered, models.AuditActionLoginSucceeded, models.AuditActionLoginFailed, since, until,
+	).Scan(&stats.Registrations, &stats.ActiveUsers, &stats.LoginsSucceeded, &stats.LoginsFailed, &stats.VerifiedRegistrations)
+	if err != nil {
+		return models.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// CountVerificationEvent counts the event of verification of the type and the app in the hour of at.
+func (s *Storage) CountVerificationEvent(
+	ctx context.Context,
+	vType models.VerificationType,
+	appID int,
+	event models.VerificationEvent,
+	at time.Time,
+) error {
+	const op = "storage.sqlite.CountVerificationEvent"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO verification_stats(hour, type, app_id, event, count) VALUES(?, ?, ?, ?, 1)
+		ON CONFLICT(hour, type, app_id, event) DO UPDATE SET count = count + 1`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, at.UTC().Truncate(time.Hour), vType, appID, event); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// VerificationStats returns funnels of verifications by type and app counted in hours within since and until.
+func (s *Storage) VerificationStats(ctx context.Context, since time.Time, until time.Time) ([]models.VerificationFunnel, error) {
+	const op = "storage.sqlite.VerificationStats"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT
+			type,
+			app_id,
+			COALESCE(SUM(CASE WHEN event = ? THEN count END), 0),
+			COALESCE(SUM(CASE WHEN event = ? THEN count END), 0),
+			COALESCE(SUM(CASE WHEN event = ? THEN count END), 0),
+			COALESCE(SUM(CASE WHEN event = ? THEN count END), 0),
+			COALESCE(SUM(CASE WHEN event = ? THEN count END), 0)
+		FROM verification_stats
+		WHERE hour >= ? AND hour < ?
+		GROUP BY type, app_id
+		ORDER BY type, app_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx,
+		models.VerificationEventCreated,
+		models.VerificationEventDelivered,
+		models.VerificationEventVerified,
+		models.VerificationEventExpired,
+		models.VerificationEventFailed,
+		since.UTC().Truncate(time.Hour), until.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var funnels []models.VerificationFunnel
+	for rows.Next() {
+		var f models.VerificationFunnel
+		if err := rows.Scan(&f.Type, &f.AppID, &f.Created, &f.Delivered, &f.Verified, &f.Expired, &f.Failed); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		funnels = append(funnels, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return funnels, nil
+}
+
+// Webhooks returns webhooks of the app, or of all apps if appID is 0.
+func (s *Storage) Webhooks(ctx context.Context, appID int) ([]models.Webhook, error) {
+	const op = "storage.sqlite.Webhooks"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT id, app_id, url, secret, events FROM webhooks WHERE ? = 0 OR app_id = ?")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, appID, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var webhooks []models.Webhook
+	for rows.Next() {
+		var (
+			webhook models.Webhook
+			events  string
+		)
+		if err := rows.Scan(&webhook.ID, &webhook.AppID, &webhook.URL, &webhook.Secret, &events); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		webhook.Events = webhookEvents(events)
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// SaveWebhookDelivery saves delivery to the webhook.
+func (s *Storage) SaveWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) (int64, error) {
+	const op = "storage.sqlite.SaveWebhookDelivery"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO webhook_deliveries(webhook_id, event, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx,
+		delivery.Webhook.ID, delivery.Event, delivery.Payload, delivery.Status, delivery.Attempts,
+		delivery.LastError, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+const webhookDeliveryColumns = `
+	d.id, d.event, d.payload, d.status, d.attempts, d.last_error, d.next_attempt_at, d.created_at, d.updated_at,
+	w.id, w.app_id, w.url, w.secret, w.events`
+
+// DueWebhookDeliveries returns pending deliveries which should be attempted by now, oldest first.
+func (s *Storage) DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	const op = "storage.sqlite.DueWebhookDeliveries"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ?
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT ?`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, models.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// WebhookDelivery returns delivery by ID.
+func (s *Storage) WebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error) {
+	const op = "storage.sqlite.WebhookDelivery"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.id = ?`)
+	if err != nil {
+		return models.WebhookDelivery{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	delivery, err := scanWebhookDelivery(stmt.QueryRowContext(ctx, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.WebhookDelivery{}, fmt.Errorf("%s: %w", op, storage.ErrWebhookDeliveryNotFound)
+		}
+
+		return models.WebhookDelivery{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return delivery, nil
+}
+
+// UpdateWebhookDelivery saves status, attempts, last error and next attempt time of the delivery.
+func (s *Storage) UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	const op = "storage.sqlite.UpdateWebhookDelivery"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx,
+		delivery.Status, delivery.Attempts, delivery.LastError, delivery.NextAttemptAt, delivery.UpdatedAt, delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrWebhookDeliveryNotFound)
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhookDelivery(row scanner) (models.WebhookDelivery, error) {
+	var (
+		delivery models.WebhookDelivery
+		events   string
+	)
+
+	err := row.Scan(
+		&delivery.ID, &delivery.Event, &delivery.Payload, &delivery.Status, &delivery.Attempts,
+		&delivery.LastError, &delivery.NextAttemptAt, &delivery.CreatedAt, &delivery.UpdatedAt,
+		&delivery.Webhook.ID, &delivery.Webhook.AppID, &delivery.Webhook.URL, &delivery.Webhook.Secret, &events,
+	)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	delivery.Webhook.Events = webhookEvents(events)
+
+	return delivery, nil
+}
+
+// webhookEvents parses comma separated event types.
+func webhookEvents(s string) []models.WebhookEventType {
+	var events []models.WebhookEventType
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			events = append(events, models.WebhookEventType(e))
+		}
+	}
+
+	return events
+}
+
+// DeleteExpiredVerifications deletes verifications expired before the given time and returns their number.
+func (s *Storage) DeleteExpiredVerifications(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredVerifications"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.deleteExpired(ctx, op, "DELETE FROM verifications WHERE expiresat < ?", before)
+}
+
+// DeleteExpiredPhoneVerifications deletes phone verifications expired before the given time and returns their number.
+func (s *Storage) DeleteExpiredPhoneVerifications(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredPhoneVerifications"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.deleteExpired(ctx, op, "DELETE FROM phone_verifications WHERE expiresat < ?", before)
+}
+
+// DeleteExpiredPendingRegistrations deletes pending registrations expired before the given time and returns their number.
+func (s *Storage) DeleteExpiredPendingRegistrations(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredPendingRegistrations"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.deleteExpired(ctx, op, "DELETE FROM pending_registrations WHERE expires_at < ?", before)
+}
+
+func (s *Storage) deleteExpired(ctx context.Context, op string, query string, before time.Time) (int64, error) {
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
+
+// LoginFailures returns failed logins counted by the key, zero count if there are none.
+func (s *Storage) LoginFailures(ctx context.Context, key string) (models.LoginFailures, error) {
+	const op = "storage.sqlite.LoginFailures"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT failures, last_failure_at FROM login_failures WHERE key = ?")
+	if err != nil {
+		return models.LoginFailures{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	failures := models.LoginFailures{Key: key}
+
+	err = stmt.QueryRowContext(ctx, key).Scan(&failures.Count, &failures.LastFailureAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return models.LoginFailures{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+// RecordLoginFailure counts failed login by the key and returns updated counter.
+// Counter is restarted if the previous failure happened before resetBefore.
+func (s *Storage) RecordLoginFailure(ctx context.Context, key string, at time.Time, resetBefore time.Time) (models.LoginFailures, error) {
+	const op = "storage.sqlite.RecordLoginFailure"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO login_failures(key, failures, last_failure_at) VALUES(?, 1, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			failures = CASE WHEN last_failure_at < ? THEN 1 ELSE failures + 1 END,
+			last_failure_at = excluded.last_failure_at
+		RETURNING failures, last_failure_at`)
+	if err != nil {
+		return models.LoginFailures{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	failures := models.LoginFailures{Key: key}
+
+	if err := stmt.QueryRowContext(ctx, key, at, resetBefore).Scan(&failures.Count, &failures.LastFailureAt); err != nil {
+		return models.LoginFailures{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+// ResetLoginFailures deletes failed logins counted by the key.
+func (s *Storage) ResetLoginFailures(ctx context.Context, key string) error {
+	const op = "storage.sqlite.ResetLoginFailures"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("DELETE FROM login_failures WHERE key = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteStaleLoginFailures deletes counters whose last failure happened before the given time and returns their number.
+func (s *Storage) DeleteStaleLoginFailures(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteStaleLoginFailures"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.deleteExpired(ctx, op, "DELETE FROM login_failures WHERE last_failure_at < ?", before)
+}
+
+// AppIPRules returns IP rules of all apps.
+func (s *Storage) AppIPRules(ctx context.Context) ([]models.IPRule, error) {
+	const op = "storage.sqlite.AppIPRules"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT id, app_id, cidr, action FROM app_ip_rules")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var rules []models.IPRule
+	for rows.Next() {
+		var rule models.IPRule
+		if err := rows.Scan(&rule.ID, &rule.AppID, &rule.CIDR, &rule.Action); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+// KnownDevices returns devices the user signed in from, most recently seen first.
+func (s *Storage) KnownDevices(ctx context.Context, userID int64) ([]models.KnownDevice, error) {
+	const op = "storage.sqlite.KnownDevices"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT user_id, key, user_agent, ip, country, city, first_seen_at, last_seen_at
+		FROM known_devices WHERE user_id = ? ORDER BY last_seen_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var devices []models.KnownDevice
+	for rows.Next() {
+		var d models.KnownDevice
+		if err := rows.Scan(&d.UserID, &d.Key, &d.UserAgent, &d.IP, &d.Country, &d.City, &d.FirstSeenAt, &d.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		devices = append(devices, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return devices, nil
+}
+
+// SaveKnownDevice saves device of the user, already known device gets its last seen attributes updated.
+func (s *Storage) SaveKnownDevice(ctx context.Context, device models.KnownDevice) error {
+	const op = "storage.sqlite.SaveKnownDevice"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO known_devices(user_id, key, user_agent, ip, country, city, first_seen_at, last_seen_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, key) DO UPDATE SET
+			user_agent = excluded.user_agent,
+			ip = excluded.ip,
+			country = excluded.country,
+			city = excluded.city,
+			last_seen_at = excluded.last_seen_at`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		device.UserID, device.Key, device.UserAgent, device.IP, device.Country, device.City, device.FirstSeenAt, device.LastSeenAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveSession saves session of the issued token.
+func (s *Storage) SaveSession(ctx context.Context, session models.Session) error {
+	const op = "storage.sqlite.SaveSession"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO sessions(id, sso_session_id, user_id, service_account_id, app_id, elevated, ip, country, city, issued_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		session.ID, session.SSOSessionID, session.UserID, session.ServiceAccountID, session.AppID, session.Elevated,
+		session.IP, session.Country, session.City, session.IssuedAt, session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Session returns session by ID, which is jti claim of its token.
+func (s *Storage) Session(ctx context.Context, id string) (models.Session, error) {
+	const op = "storage.sqlite.Session"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, sso_session_id, user_id, service_account_id, app_id, elevated, ip, country, city, issued_at, expires_at, revoked_at, consumed_at
+		FROM sessions WHERE id = ?`)
+	if err != nil {
+		return models.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		session               models.Session
+		revokedAt, consumedAt sql.NullTime
+	)
+
+	err = stmt.QueryRowContext(ctx, id).Scan(
+		&session.ID, &session.SSOSessionID, &session.UserID, &session.ServiceAccountID, &session.AppID, &session.Elevated,
+		&session.IP, &session.Country, &session.City, &session.IssuedAt, &session.ExpiresAt, &revokedAt, &consumedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Session{}, fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+		}
+
+		return models.Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	session.RevokedAt = revokedAt.Time
+	session.ConsumedAt = consumedAt.Time
+
+	return session, nil
+}
+
+// Sessions returns page of sessions of the user matching the filter, newest first.
+func (s *Storage) Sessions(ctx context.Context, filter models.SessionFilter, limit int) ([]models.Session, error) {
+	const op = "storage.sqlite.Sessions"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	where, args := []string{"user_id = ?"}, []any{filter.UserID}
+	if !filter.ActiveAt.IsZero() {
+		where = append(where, "revoked_at IS NULL", "consumed_at IS NULL", "expires_at > ?")
+		args = append(args, filter.ActiveAt)
+	}
+	if filter.Before.ID != "" {
+		where = append(where, "(issued_at < ? OR (issued_at = ? AND id < ?))")
+		args = append(args, filter.Before.IssuedAt, filter.Before.IssuedAt, filter.Before.ID)
+	}
+
+	query := `
+		SELECT id, sso_session_id, user_id, service_account_id, app_id, elevated, ip, country, city, issued_at, expires_at, revoked_at, consumed_at
+		FROM sessions WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY issued_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		var (
+			session               models.Session
+			revokedAt, consumedAt sql.NullTime
+		)
+
+		err := rows.Scan(
+			&session.ID, &session.SSOSessionID, &session.UserID, &session.ServiceAccountID, &session.AppID, &session.Elevated,
+			&session.IP, &session.Country, &session.City, &session.IssuedAt, &session.ExpiresAt, &revokedAt, &consumedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		session.RevokedAt = revokedAt.Time
+		session.ConsumedAt = consumedAt.Time
+
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession marks session as revoked, already revoked session keeps the time it was revoked first.
+func (s *Storage) RevokeSession(ctx context.Context, id string, at time.Time) error {
+	const op = "storage.sqlite.RevokeSession"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE sessions SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	}
+
+	return nil
+}
+
+// RevokeSSOSession revokes sessions issued within SSO session which are not revoked yet and returns them.
+func (s *Storage) RevokeSSOSession(ctx context.Context, ssoSessionID string, at time.Time) ([]models.Session, error) {
+	const op = "storage.sqlite.RevokeSSOSession"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		UPDATE sessions SET revoked_at = ? WHERE sso_session_id = ? AND revoked_at IS NULL
+		RETURNING id, sso_session_id, user_id, service_account_id, app_id, elevated, ip, country, city, issued_at, expires_at, revoked_at, consumed_at`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, at, ssoSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		var (
+			session               models.Session
+			revokedAt, consumedAt sql.NullTime
+		)
+
+		err := rows.Scan(
+			&session.ID, &session.SSOSessionID, &session.UserID, &session.ServiceAccountID, &session.AppID, &session.Elevated,
+			&session.IP, &session.Country, &session.City, &session.IssuedAt, &session.ExpiresAt, &revokedAt, &consumedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		session.RevokedAt = revokedAt.Time
+		session.ConsumedAt = consumedAt.Time
+
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// ConsumeSession marks session of one-time token as used.
+// It's done atomically, so only one of concurrent validations of the token succeeds, others get ErrSessionConsumed.
+func (s *Storage) ConsumeSession(ctx context.Context, id string, at time.Time) error {
+	const op = "storage.sqlite.ConsumeSession"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE sessions SET consumed_at = ? WHERE id = ? AND consumed_at IS NULL")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSessionConsumed)
+	}
+
+	return nil
+}
+
+// DeleteExpiredSessions deletes sessions whose tokens expired before the given time and returns their number.
+// SSO sessions of refresh tokens which are not expired are kept, refresh checks they are not revoked.
+func (s *Storage) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredSessions"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.deleteExpired(ctx, op, `
+		DELETE FROM sessions WHERE expires_at < ?1
+		AND id NOT IN (SELECT session_id FROM refresh_tokens WHERE expires_at >= ?1)`, before)
+}
+
+func (s *Storage) SaveRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	const op = "storage.sqlite.SaveRefreshToken"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO refresh_tokens(id, session_id, user_id, app_id, fingerprint, issued_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		token.ID, token.SessionID, token.UserID, token.AppID, token.Fingerprint, token.IssuedAt, token.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RefreshToken returns refresh token by ID, which is SHA-256 of the token.
+func (s *Storage) RefreshToken(ctx context.Context, id string) (models.RefreshToken, error) {
+	const op = "storage.sqlite.RefreshToken"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, session_id, user_id, app_id, fingerprint, issued_at, expires_at, used_at
+		FROM refresh_tokens WHERE id = ?`)
+	if err != nil {
+		return models.RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		token  models.RefreshToken
+		usedAt sql.NullTime
+	)
+
+	err = stmt.QueryRowContext(ctx, id).Scan(
+		&token.ID, &token.SessionID, &token.UserID, &token.AppID, &token.Fingerprint, &token.IssuedAt, &token.ExpiresAt, &usedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RefreshToken{}, fmt.Errorf("%s: %w", op, storage.ErrRefreshTokenNotFound)
+		}
+
+		return models.RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token.UsedAt = usedAt.Time
+
+	return token, nil
+}
+
+// UseRefreshToken marks refresh token as exchanged, so it can't be exchanged again.
+func (s *Storage) UseRefreshToken(ctx context.Context, id string, at time.Time) error {
+	const op = "storage.sqlite.UseRefreshToken"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE refresh_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrRefreshTokenUsed)
+	}
+
+	return nil
+}
+
+// DeleteExpiredRefreshTokens deletes refresh tokens expired before the given time and returns their number.
+func (s *Storage) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredRefreshTokens"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.deleteExpired(ctx, op, "DELETE FROM refresh_tokens WHERE expires_at < ?", before)
+}
+
+// SaveOrganization saves organization with the owner as its first member and returns its ID.
+func (s *Storage) SaveOrganization(ctx context.Context, org models.Organization, ownerID int64) (int64, error) {
+	const op = "storage.sqlite.SaveOrganization"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "INSERT INTO organizations(name, created_at) VALUES(?, ?)", org.Name, org.CreatedAt)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrOrganizationExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO organization_members(org_id, user_id, role, joined_at) VALUES(?, ?, ?, ?)",
+		id, ownerID, models.OrgRoleOwner, org.CreatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Organization returns organization by ID.
+func (s *Storage) Organization(ctx context.Context, id int64) (models.Organization, error) {
+	const op = "storage.sqlite.Organization"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT id, name, created_at FROM organizations WHERE id = ?")
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var org models.Organization
+	if err := stmt.QueryRowContext(ctx, id).Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Organization{}, fmt.Errorf("%s: %w", op, storage.ErrOrganizationNotFound)
+		}
+
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, nil
+}
+
+// OrganizationMember returns membership of the user in the organization.
+func (s *Storage) OrganizationMember(ctx context.Context, orgID int64, userID int64) (models.OrganizationMember, error) {
+	const op = "storage.sqlite.OrganizationMember"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT m.org_id, o.name, m.user_id, m.role, m.joined_at
+		FROM organization_members m JOIN organizations o ON o.id = m.org_id
+		WHERE m.org_id = ? AND m.user_id = ?`)
+	if err != nil {
+		return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var member models.OrganizationMember
+	err = stmt.QueryRowContext(ctx, orgID, userID).Scan(&member.OrgID, &member.OrgName, &member.UserID, &member.Role, &member.JoinedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, storage.ErrOrganizationMemberNotFound)
+		}
+
+		return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return member, nil
+}
+
+// Memberships returns organizations the user is a member of, ordered by organization ID.
+func (s *Storage) Memberships(ctx context.Context, userID int64) ([]models.OrganizationMember, error) {
+	const op = "storage.sqlite.Memberships"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT m.org_id, o.name, m.user_id, m.role, m.joined_at
+		FROM organization_members m JOIN organizations o ON o.id = m.org_id
+		WHERE m.user_id = ? ORDER BY m.org_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var members []models.OrganizationMember
+	for rows.Next() {
+		var member models.OrganizationMember
+		if err := rows.Scan(&member.OrgID, &member.OrgName, &member.UserID, &member.Role, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SaveOrganizationInvitation saves invitation and returns its ID.
+// Email is stored as lookup value, so it's not readable if personal data is encrypted.
+func (s *Storage) SaveOrganizationInvitation(ctx context.Context, invitation models.OrganizationInvitation) (int64, error) {
+	const op = "storage.sqlite.SaveOrganizationInvitation"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO organization_invitations(org_id, email, role, code, invited_by, created_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx,
+		invitation.OrgID, s.lookup(invitation.Email), invitation.Role, invitation.Code,
+		invitation.InvitedBy, invitation.CreatedAt, invitation.ExpiresAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// OrganizationInvitation returns the latest invitation of the email to the organization which is not accepted yet.
+// Email of the returned invitation is the one asked for.
+func (s *Storage) OrganizationInvitation(ctx context.Context, orgID int64, email string) (models.OrganizationInvitation, error) {
+	const op = "storage.sqlite.OrganizationInvitation"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, org_id, role, code, invited_by, created_at, expires_at
+		FROM organization_invitations
+		WHERE org_id = ? AND email = ? AND accepted_at IS NULL
+		ORDER BY id DESC LIMIT 1`)
+	if err != nil {
+		return models.OrganizationInvitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitation := models.OrganizationInvitation{Email: email}
+	err = stmt.QueryRowContext(ctx, orgID, s.lookup(email)).Scan(
+		&invitation.ID, &invitation.OrgID, &invitation.Role, &invitation.Code,
+		&invitation.InvitedBy, &invitation.CreatedAt, &invitation.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OrganizationInvitation{}, fmt.Errorf("%s: %w", op, storage.ErrOrganizationInvitationNotFound)
+		}
+
+		return models.OrganizationInvitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitation, nil
+}
+
+// AcceptOrganizationInvitation marks the invitation as accepted and adds the user to the organization
+// with the role of the invitation. Invitation is accepted only once, later calls get ErrOrganizationInvitationNotFound.
+func (s *Storage) AcceptOrganizationInvitation(ctx context.Context, invitationID int64, userID int64, at time.Time) error {
+	const op = "storage.sqlite.AcceptOrganizationInvitation"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var (
+		orgID int64
+		role  models.OrgRole
+	)
+
+	err = tx.QueryRowContext(ctx,
+		"UPDATE organization_invitations SET accepted_at = ? WHERE id = ? AND accepted_at IS NULL RETURNING org_id, role",
+		at, invitationID,
+	).Scan(&orgID, &role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrOrganizationInvitationNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO organization_members(org_id, user_id, role, joined_at) VALUES(?, ?, ?, ?)",
+		orgID, userID, role, at,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			return fmt.Errorf("%s: %w", op, storage.ErrOrganizationMemberExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveServiceAccount saves service account and returns its ID, name is unique within the app.
+func (s *Storage) SaveServiceAccount(ctx context.Context, account models.ServiceAccount) (int64, error) {
+	const op = "storage.sqlite.SaveServiceAccount"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("INSERT INTO service_accounts(app_id, org_id, name, scopes, created_at) VALUES(?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, account.AppID, account.OrgID, account.Name, strings.Join(account.Scopes, " "), account.CreatedAt)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrServiceAccountExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// ServiceAccount returns service account by ID.
+func (s *Storage) ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error) {
+	const op = "storage.sqlite.ServiceAccount"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, app_id, org_id, name, scopes, disabled_at, created_at
+		FROM service_accounts WHERE id = ?`)
+	if err != nil {
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	account, err := scanServiceAccount(stmt.QueryRowContext(ctx, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, storage.ErrServiceAccountNotFound)
+		}
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return account, nil
+}
+
+// ServiceAccounts returns service accounts of the app ordered by ID.
+func (s *Storage) ServiceAccounts(ctx context.Context, appID int) ([]models.ServiceAccount, error) {
+	const op = "storage.sqlite.ServiceAccounts"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, app_id, org_id, name, scopes, disabled_at, created_at
+		FROM service_accounts WHERE app_id = ? ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var accounts []models.ServiceAccount
+	for rows.Next() {
+		account, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return accounts, nil
+}
+
+func scanServiceAccount(row scanner) (models.ServiceAccount, error) {
+	var (
+		account    models.ServiceAccount
+		scopes     string
+		disabledAt sql.NullTime
+	)
+
+	err := row.Scan(&account.ID, &account.AppID, &account.OrgID, &account.Name, &scopes, &disabledAt, &account.CreatedAt)
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	account.Scopes = strings.Fields(scopes)
+	account.DisabledAt = disabledAt.Time
+
+	return account, nil
+}
+
+// DisableServiceAccount disables service account and revokes its sessions which are not revoked yet,
+// already disabled account keeps the time it was disabled first.
+func (s *Storage) DisableServiceAccount(ctx context.Context, id int64, at time.Time) error {
+	const op = "storage.sqlite.DisableServiceAccount"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "UPDATE service_accounts SET disabled_at = COALESCE(disabled_at, ?) WHERE id = ?", at, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrServiceAccountNotFound)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE sessions SET revoked_at = ? WHERE service_account_id = ? AND revoked_at IS NULL", at, id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveServiceAccountKey saves key of service account.
+func (s *Storage) SaveServiceAccountKey(ctx context.Context, key models.ServiceAccountKey) error {
+	const op = "storage.sqlite.SaveServiceAccountKey"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO service_account_keys(id, service_account_id, kind, secret_hash, public_key, created_at)
+		VALUES(?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, key.ID, key.ServiceAccountID, key.Kind, key.SecretHash, key.PublicKey, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ServiceAccountKey returns key of service account by ID.
+func (s *Storage) ServiceAccountKey(ctx context.Context, id string) (models.ServiceAccountKey, error) {
+	const op = "storage.sqlite.ServiceAccountKey"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT id, service_account_id, kind, secret_hash, public_key, created_at, revoked_at
+		FROM service_account_keys WHERE id = ?`)
+	if err != nil {
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		key       models.ServiceAccountKey
+		revokedAt sql.NullTime
+	)
+
+	err = stmt.QueryRowContext(ctx, id).Scan(
+		&key.ID, &key.ServiceAccountID, &key.Kind, &key.SecretHash, &key.PublicKey, &key.CreatedAt, &revokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, storage.ErrServiceAccountKeyNotFound)
+		}
+
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key.RevokedAt = revokedAt.Time
+
+	return key, nil
+}
+
+// RevokeServiceAccountKey marks key as revoked, already revoked key keeps the time it was revoked first.
+func (s *Storage) RevokeServiceAccountKey(ctx context.Context, id string, at time.Time) error {
+	const op = "storage.sqlite.RevokeServiceAccountKey"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE service_account_keys SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrServiceAccountKeyNotFound)
+	}
+
+	return nil
+}
+
+// SaveAccountDeletion schedules deletion of the user's account, deletion requested again is rescheduled.
+func (s *Storage) SaveAccountDeletion(ctx context.Context, deletion models.AccountDeletion) error {
+	const op = "storage.sqlite.SaveAccountDeletion"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO account_deletions(user_id, requested_at, scheduled_at) VALUES(?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET requested_at = excluded.requested_at, scheduled_at = excluded.scheduled_at`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, deletion.UserID, deletion.RequestedAt, deletion.ScheduledAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CancelAccountDeletion cancels scheduled deletion of the user's account.
+// If no deletion is scheduled, returns storage.ErrAccountDeletionNotFound.
+func (s *Storage) CancelAccountDeletion(ctx context.Context, userID int64) error {
+	const op = "storage.sqlite.CancelAccountDeletion"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("DELETE FROM account_deletions WHERE user_id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAccountDeletionNotFound)
+	}
+
+	return nil
+}
+
+// DueAccountDeletions returns at most limit account deletions scheduled before the time, oldest first.
+func (s *Storage) DueAccountDeletions(ctx context.Context, before time.Time, limit int) ([]models.AccountDeletion, error) {
+	const op = "storage.sqlite.DueAccountDeletions"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT user_id, requested_at, scheduled_at FROM account_deletions
+		WHERE scheduled_at <= ? ORDER BY scheduled_at LIMIT ?`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var deletions []models.AccountDeletion
+	for rows.Next() {
+		var deletion models.AccountDeletion
+		if err := rows.Scan(&deletion.UserID, &deletion.RequestedAt, &deletion.ScheduledAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		deletions = append(deletions, deletion)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deletions, nil
+}
+
+// DeleteScheduledUser deletes the user and the user's data if deletion of the account is due at the time,
+// otherwise, e.g. if the user canceled it meanwhile, returns storage.ErrAccountDeletionNotFound.
+// Audit events and email suppressions are kept. The email of the deleted user is returned.
+func (s *Storage) DeleteScheduledUser(ctx context.Context, userID int64, at time.Time) (string, error) {
+	const op = "storage.sqlite.DeleteScheduledUser"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM account_deletions WHERE user_id = ? AND scheduled_at <= ?", userID, at)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrAccountDeletionNotFound)
+	}
+
+	var (
+		stored   string
+		emailEnc []byte
+	)
+	err = tx.QueryRowContext(ctx, "SELECT email, email_enc FROM users WHERE id = ?", userID).Scan(&stored, &emailEnc)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	email, err := s.unseal(stored, emailEnc)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Foreign keys are not enforced, so rows referencing the user are deleted explicitly.
+	queries := []struct {
+		query string
+		arg   any
+	}{
+		{"DELETE FROM verifications WHERE email = ?", email},
+		{"DELETE FROM phone_verifications WHERE email = ?", stored},
+		{"DELETE FROM organization_invitations WHERE email = ?", stored},
+		// Recipients of emails are not sealed, and user ID is not resolved for emails sent before registration.
+		{"DELETE FROM emails WHERE recipient = ?", email},
+		{"DELETE FROM emails WHERE user_id = ?", userID},
+		{"DELETE FROM known_devices WHERE user_id = ?", userID},
+		{"DELETE FROM device_logins WHERE user_id = ?", userID},
+		{"DELETE FROM verification_reminders WHERE user_id = ?", userID},
+		{"DELETE FROM refresh_tokens WHERE user_id = ?", userID},
+		{"DELETE FROM notification_preferences WHERE user_id = ?", userID},
+		{"DELETE FROM app_bans WHERE user_id = ?", userID},
+		{"DELETE FROM sessions WHERE user_id = ?", userID},
+		{"DELETE FROM organization_members WHERE user_id = ?", userID},
+		{"DELETE FROM users WHERE id = ?", userID},
+	}
+	for _, q := range queries {
+		if _, err := tx.ExecContext(ctx, q.query, q.arg); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return email, nil
+}
+
+// RevokeUserSessions revokes sessions of the user which are not revoked yet and returns how many were revoked.
+func (s *Storage) RevokeUserSessions(ctx context.Context, userID int64, at time.Time) (int, error) {
+	const op = "storage.sqlite.RevokeUserSessions"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(n), nil
+}
+
+// RevokeUserAppSessions revokes sessions of the user in the app which are not revoked yet
+// and returns how many were revoked.
+func (s *Storage) RevokeUserAppSessions(ctx context.Context, userID int64, appID int, at time.Time) (int, error) {
+	const op = "storage.sqlite.RevokeUserAppSessions"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND app_id = ? AND revoked_at IS NULL")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at, userID, appID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(n), nil
+}
+
+// RevokeOtherUserSessions revokes sessions of the user which are not revoked yet, except the kept one,
+// and returns how many were revoked. Empty keepID revokes all of them.
+func (s *Storage) RevokeOtherUserSessions(ctx context.Context, userID int64, keepID string, at time.Time) (int, error) {
+	const op = "storage.sqlite.RevokeOtherUserSessions"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND id != ? AND revoked_at IS NULL")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, at, userID, keepID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(n), nil
+}
+
+const deviceLoginColumns = `id, pairing_code, app_id, device_id, user_agent, ip, country, user_id, approved_at, created_at, expires_at`
+
+// SaveDeviceLogin saves pending device login, storage.ErrDeviceLoginExists is returned if its pairing code is taken.
+func (s *Storage) SaveDeviceLogin(ctx context.Context, login models.DeviceLogin) error {
+	const op = "storage.sqlite.SaveDeviceLogin"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO device_logins(id, pairing_code, app_id, device_id, user_agent, ip, country, created_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		login.ID, login.PairingCode, login.AppID, login.Device.ID, login.Device.UserAgent, login.Device.IP, login.Device.Country,
+		login.CreatedAt, login.ExpiresAt,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, storage.ErrDeviceLoginExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeviceLogin returns device login by ID, expired ones included until they are deleted.
+func (s *Storage) DeviceLogin(ctx context.Context, id string) (models.DeviceLogin, error) {
+	const op = "storage.sqlite.DeviceLogin"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT " + deviceLoginColumns + " FROM device_logins WHERE id = ?")
+	if err != nil {
+		return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	login, err := scanDeviceLogin(stmt.QueryRowContext(ctx, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, storage.ErrDeviceLoginNotFound)
+		}
+
+		return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return login, nil
+}
+
+// ApproveDeviceLogin approves pending device login with the pairing code on behalf of the user and returns it.
+// storage.ErrDeviceLoginNotFound is returned if there is no such login pending at the time.
+func (s *Storage) ApproveDeviceLogin(ctx context.Context, pairingCode string, userID int64, at time.Time) (models.DeviceLogin, error) {
+	const op = "storage.sqlite.ApproveDeviceLogin"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		UPDATE device_logins SET user_id = ?, approved_at = ?
+		WHERE pairing_code = ? AND approved_at IS NULL AND expires_at > ?
+		RETURNING ` + deviceLoginColumns)
+	if err != nil {
+		return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	login, err := scanDeviceLogin(stmt.QueryRowContext(ctx, userID, at, pairingCode, at))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, storage.ErrDeviceLoginNotFound)
+		}
+
+		return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return login, nil
+}
+
+// ConsumeDeviceLogin deletes approved device login which is not expired at the time and returns it,
+// so a login is exchanged for a token once. storage.ErrDeviceLoginNotFound is returned if there is no such login.
+func (s *Storage) ConsumeDeviceLogin(ctx context.Context, id string, at time.Time) (models.DeviceLogin, error) {
+	const op = "storage.sqlite.ConsumeDeviceLogin"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		DELETE FROM device_logins WHERE id = ? AND approved_at IS NOT NULL AND expires_at > ?
+		RETURNING ` + deviceLoginColumns)
+	if err != nil {
+		return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	login, err := scanDeviceLogin(stmt.QueryRowContext(ctx, id, at))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, storage.ErrDeviceLoginNotFound)
+		}
+
+		return models.DeviceLogin{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return login, nil
+}
+
+func scanDeviceLogin(row scanner) (models.DeviceLogin, error) {
+	var (
+		login      models.DeviceLogin
+		approvedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&login.ID, &login.PairingCode, &login.AppID, &login.Device.ID, &login.Device.UserAgent, &login.Device.IP,
+		&login.Device.Country, &login.UserID, &approvedAt, &login.CreatedAt, &login.ExpiresAt,
+	)
+	if err != nil {
+		return models.DeviceLogin{}, err
+	}
+
+	login.ApprovedAt = approvedAt.Time
+
+	return login, nil
+}
+
+// DeleteExpiredDeviceLogins deletes device logins expired before the time and returns their number.
+func (s *Storage) DeleteExpiredDeviceLogins(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredDeviceLogins"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.deleteExpired(ctx, op, "DELETE FROM device_logins WHERE expires_at < ?", before)
+}
+
+// SaveMaintenance starts maintenance window of the app, or replaces the running one.
+func (s *Storage) SaveMaintenance(ctx context.Context, m models.Maintenance) error {
+	const op = "storage.sqlite.SaveMaintenance"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO maintenance_windows(app_id, message, started_at, ends_at) VALUES(?, ?, ?, ?)
+		ON CONFLICT(app_id) DO UPDATE SET
+			message = excluded.message, started_at = excluded.started_at, ends_at = excluded.ends_at`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	endsAt := sql.NullTime{Time: m.EndsAt, Valid: !m.EndsAt.IsZero()}
+
+	if _, err := stmt.ExecContext(ctx, m.AppID, m.Message, m.StartedAt, endsAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteMaintenance ends maintenance window of the app.
+func (s *Storage) DeleteMaintenance(ctx context.Context, appID int) error {
+	const op = "storage.sqlite.DeleteMaintenance"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("DELETE FROM maintenance_windows WHERE app_id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMaintenanceNotFound)
+	}
+
+	return nil
+}
+
+// Maintenances returns maintenance windows which are not over at the time, ordered by app ID.
+func (s *Storage) Maintenances(ctx context.Context, at time.Time) ([]models.Maintenance, error) {
+	const op = "storage.sqlite.Maintenances"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT app_id, message, started_at, ends_at FROM maintenance_windows
+		WHERE ends_at IS NULL OR ends_at > ? ORDER BY app_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, at)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var windows []models.Maintenance
+	for rows.Next() {
+		var (
+			m      models.Maintenance
+			endsAt sql.NullTime
+		)
+
+		if err := rows.Scan(&m.AppID, &m.Message, &m.StartedAt, &endsAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		m.EndsAt = endsAt.Time
+
+		windows = append(windows, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return windows, nil
+}
+
+// SaveAppBan bans the user from the app, or replaces the existing ban.
+func (s *Storage) SaveAppBan(ctx context.Context, ban models.AppBan) error {
+	const op = "storage.sqlite.SaveAppBan"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO app_bans(user_id, app_id, reason, created_at, expires_at) VALUES(?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, app_id) DO UPDATE SET
+			reason = excluded.reason, created_at = excluded.created_at, expires_at = excluded.expires_at`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	expiresAt := sql.NullTime{Time: ban.ExpiresAt, Valid: !ban.ExpiresAt.IsZero()}
+
+	if _, err := stmt.ExecContext(ctx, ban.UserID, ban.AppID, ban.Reason, ban.CreatedAt, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AppBan returns ban of the user from the app, expired ban is returned too, see models.AppBan.Active.
+func (s *Storage) AppBan(ctx context.Context, userID int64, appID int) (models.AppBan, error) {
+	const op = "storage.sqlite.AppBan"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("SELECT user_id, app_id, reason, created_at, expires_at FROM app_bans WHERE user_id = ? AND app_id = ?")
+	if err != nil {
+		return models.AppBan{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		ban       models.AppBan
+		expiresAt sql.NullTime
+	)
+	err = stmt.QueryRowContext(ctx, userID, appID).Scan(&ban.UserID, &ban.AppID, &ban.Reason, &ban.CreatedAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AppBan{}, fmt.Errorf("%s: %w", op, storage.ErrAppBanNotFound)
+		}
+
+		return models.AppBan{}, fmt.Errorf("%s: %w", op, err)
+	}
+	ban.ExpiresAt = expiresAt.Time
+
+	return ban, nil
+}
+
+// AppBans returns bans of the user which are not expired at the time, ordered by app ID.
+func (s *Storage) AppBans(ctx context.Context, userID int64, at time.Time) ([]models.AppBan, error) {
+	const op = "storage.sqlite.AppBans"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		SELECT user_id, app_id, reason, created_at, expires_at FROM app_bans
+		WHERE user_id = ? AND (expires_at IS NULL OR expires_at > ?) ORDER BY app_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID, at)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var bans []models.AppBan
+	for rows.Next() {
+		var (
+			ban       models.AppBan
+			expiresAt sql.NullTime
+		)
+
+		if err := rows.Scan(&ban.UserID, &ban.AppID, &ban.Reason, &ban.CreatedAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		ban.ExpiresAt = expiresAt.Time
+
+		bans = append(bans, ban)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bans, nil
+}
+
+// DeleteAppBan lifts ban of the user from the app.
+func (s *Storage) DeleteAppBan(ctx context.Context, userID int64, appID int) error {
+	const op = "storage.sqlite.DeleteAppBan"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare("DELETE FROM app_bans WHERE user_id = ? AND app_id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, userID, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppBanNotFound)
+	}
+
+	return nil
+}
+
+// AcquireJobLock takes or renews lease of the job by owner until the given time, it's taken only if it has
+// expired by now or is held by the owner already. It reports whether the owner holds the lease.
+func (s *Storage) AcquireJobLock(ctx context.Context, name string, owner string, now time.Time, until time.Time) (bool, error) {
+	const op = "storage.sqlite.AcquireJobLock"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	stmt, err := s.db.Prepare(`
+		INSERT INTO job_locks(name, owner, expires_at) VALUES(?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE job_locks.owner = excluded.owner OR job_locks.expires_at <= ?`)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, name, owner, until, now)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,176 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/lib/fieldcrypt"
+	"grpc-service-ref/internal/storage/storagetest"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+const migrationsPath = "../../../migrations"
+
+// newTestStorage returns storage of a fresh migrated db.
+func newTestStorage(tb testing.TB, cipher FieldCipher) *Storage {
+	tb.Helper()
+
+	storagePath := filepath.Join(tb.TempDir(), "sso.db")
+
+	m, err := migrate.New("file://"+migrationsPath, "sqlite3://"+storagePath)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		tb.Fatal(err)
+	}
+	m.Close()
+
+	s, err := New(storagePath, cipher)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { s.Stop() })
+
+	return s
+}
+
+func newTestCipher(tb testing.TB) FieldCipher {
+	tb.Helper()
+
+	cipher, err := fieldcrypt.New(base64.StdEncoding.EncodeToString(make([]byte, fieldcrypt.KeySize)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return cipher
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return newTestStorage(t, nil)
+	})
+}
+
+func TestConformanceEncrypted(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return newTestStorage(t, newTestCipher(t))
+	})
+}
+
+// newBenchStorage returns storage of a fresh migrated db with one app and benchUsers users.
+func newBenchStorage(b *testing.B, cipher FieldCipher) *Storage {
+	b.Helper()
+
+	s := newTestStorage(b, cipher)
+	ctx := context.Background()
+
+	if _, err := s.SaveApp(ctx, "bench", "bench-secret"); err != nil {
+		b.Fatal(err)
+	}
+
+	for i := 0; i < benchUsers; i++ {
+		if _, err := s.SaveUser(ctx, benchEmail(i), []byte("hash")); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return s
+}
+
+const benchUsers = 1000
+
+func benchEmail(i int) string {
+	return fmt.Sprintf("user%d@example.com", i)
+}
+
+func BenchmarkUser(b *testing.B) {
+	s := newBenchStorage(b, nil)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.User(ctx, benchEmail(i%benchUsers)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUserEncrypted(b *testing.B) {
+	s := newBenchStorage(b, newTestCipher(b))
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.User(ctx, benchEmail(i%benchUsers)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApp(b *testing.B) {
+	s := newBenchStorage(b, nil)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.App(ctx, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSaveSession(b *testing.B) {
+	s := newBenchStorage(b, nil)
+	ctx := context.Background()
+	now := time.Now()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := s.SaveSession(ctx, models.Session{
+			ID:        fmt.Sprintf("session-%d", i),
+			UserID:    1,
+			AppID:     1,
+			IssuedAt:  now,
+			ExpiresAt: now.Add(time.Hour),
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSession(b *testing.B) {
+	s := newBenchStorage(b, nil)
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 0; i < benchUsers; i++ {
+		err := s.SaveSession(ctx, models.Session{
+			ID:        fmt.Sprintf("session-%d", i),
+			UserID:    int64(i + 1),
+			AppID:     1,
+			IssuedAt:  now,
+			ExpiresAt: now.Add(time.Hour),
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Session(ctx, fmt.Sprintf("session-%d", i%benchUsers)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
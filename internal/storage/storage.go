@@ -3,9 +3,40 @@ package storage
 import "errors"
 
 var (
-	ErrUserExists           = errors.New("user already exists")
-	ErrUserNotFound         = errors.New("user not found")
-	ErrAppNotFound          = errors.New("app not found")
-	ErrVerificationNotFound = errors.New("verification not found")
-	ErrVerificationExpired  = errors.New("verification expired")
+	ErrUserExists              = errors.New("user already exists")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrAppNotFound             = errors.New("app not found")
+	ErrAppExists               = errors.New("app already exists")
+	ErrVerificationNotFound    = errors.New("verification not found")
+	ErrVerificationExpired     = errors.New("verification expired")
+	ErrEmailNotFound           = errors.New("email not found")
+	ErrSuppressionNotFound     = errors.New("suppression not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+	ErrPendingRegistrationNotFound = errors.New("pending registration not found")
+
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionConsumed = errors.New("session already consumed")
+
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenUsed     = errors.New("refresh token already used")
+
+	ErrOrganizationExists             = errors.New("organization already exists")
+	ErrOrganizationNotFound           = errors.New("organization not found")
+	ErrOrganizationMemberExists       = errors.New("user is already a member of the organization")
+	ErrOrganizationMemberNotFound     = errors.New("organization member not found")
+	ErrOrganizationInvitationNotFound = errors.New("organization invitation not found")
+
+	ErrServiceAccountExists      = errors.New("service account already exists")
+	ErrServiceAccountNotFound    = errors.New("service account not found")
+	ErrServiceAccountKeyNotFound = errors.New("service account key not found")
+
+	ErrAccountDeletionNotFound = errors.New("account deletion not found")
+
+	ErrDeviceLoginExists   = errors.New("device login already exists")
+	ErrDeviceLoginNotFound = errors.New("device login not found")
+
+	ErrMaintenanceNotFound = errors.New("maintenance not found")
+
+	ErrAppBanNotFound = errors.New("app ban not found")
 )
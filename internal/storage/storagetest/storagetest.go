@@ -0,0 +1,979 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/domain/models"
+	"grpc-service-ref/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Storage is a storage backend under test, methods are the ones services rely on for error semantics.
+type Storage interface {
+	SaveUser(ctx context.Context, email string, passHash []byte) (int64, error)
+	SaveExternalUser(ctx context.Context, email string, directory string, verified bool) (int64, error)
+	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
+	UpdateUser(ctx context.Context, user models.User, passHash []byte) (int64, error)
+	VerifyUser(ctx context.Context, email string) (int64, error)
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+	SetAdmin(ctx context.Context, userID int64, isAdmin bool) error
+	SetUserVerified(ctx context.Context, userID int64, verified bool) error
+
+	SaveApp(ctx context.Context, name string, secret string) (int, error)
+	App(ctx context.Context, id int) (models.App, error)
+	UpdateAppSecret(ctx context.Context, appID int, secret string) error
+	UpdateAppWebSettings(ctx context.Context, appID int, origins []string, csp string) error
+	UpdateAppTokenClaims(ctx context.Context, appID int, issuer string, audience string) error
+	AppsWithOrigins(ctx context.Context) ([]models.App, error)
+
+	StoreVerification(ctx context.Context, email string, vType models.VerificationType, code string, expiresAt time.Time) (models.VerificationData, error)
+	Verification(ctx context.Context, email string, vType models.VerificationType) (models.VerificationData, error)
+	IncrementVerificationAttempts(ctx context.Context, email string, vType models.VerificationType) (int, error)
+	DeleteVerification(ctx context.Context, email string, vType models.VerificationType) error
+	CountVerificationEvent(ctx context.Context, vType models.VerificationType, appID int, event models.VerificationEvent, at time.Time) error
+	VerificationStats(ctx context.Context, since time.Time, until time.Time) ([]models.VerificationFunnel, error)
+
+	SavePendingRegistration(ctx context.Context, email string, passHash []byte, expiresAt time.Time) error
+	ActivatePendingRegistration(ctx context.Context, email string) (int64, error)
+
+	SaveSuppression(ctx context.Context, suppression models.Suppression) error
+	Suppression(ctx context.Context, email string) (models.Suppression, error)
+	DeleteSuppression(ctx context.Context, email string) error
+
+	SaveEmail(ctx context.Context, email models.Email) (int64, error)
+	Emails(ctx context.Context, messageID string) ([]models.Email, error)
+
+	SaveNotificationPreferences(ctx context.Context, userID int64, prefs models.NotificationPreferences, at time.Time) error
+	NotificationPreferences(ctx context.Context, email string) (models.NotificationPreferences, error)
+
+	SaveSession(ctx context.Context, session models.Session) error
+	Session(ctx context.Context, id string) (models.Session, error)
+	RevokeSession(ctx context.Context, id string, at time.Time) error
+	RevokeSSOSession(ctx context.Context, ssoSessionID string, at time.Time) ([]models.Session, error)
+	ConsumeSession(ctx context.Context, id string, at time.Time) error
+	DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error)
+
+	SaveRefreshToken(ctx context.Context, token models.RefreshToken) error
+	RefreshToken(ctx context.Context, id string) (models.RefreshToken, error)
+	UseRefreshToken(ctx context.Context, id string, at time.Time) error
+	DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error)
+
+	SaveOrganization(ctx context.Context, org models.Organization, ownerID int64) (int64, error)
+	Organization(ctx context.Context, id int64) (models.Organization, error)
+	OrganizationMember(ctx context.Context, orgID int64, userID int64) (models.OrganizationMember, error)
+	Memberships(ctx context.Context, userID int64) ([]models.OrganizationMember, error)
+	SaveOrganizationInvitation(ctx context.Context, invitation models.OrganizationInvitation) (int64, error)
+	OrganizationInvitation(ctx context.Context, orgID int64, email string) (models.OrganizationInvitation, error)
+	AcceptOrganizationInvitation(ctx context.Context, invitationID int64, userID int64, at time.Time) error
+
+	SaveServiceAccount(ctx context.Context, account models.ServiceAccount) (int64, error)
+	ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error)
+	ServiceAccounts(ctx context.Context, appID int) ([]models.ServiceAccount, error)
+	DisableServiceAccount(ctx context.Context, id int64, at time.Time) error
+	SaveServiceAccountKey(ctx context.Context, key models.ServiceAccountKey) error
+	ServiceAccountKey(ctx context.Context, id string) (models.ServiceAccountKey, error)
+	RevokeServiceAccountKey(ctx context.Context, id string, at time.Time) error
+
+	SaveAccountDeletion(ctx context.Context, deletion models.AccountDeletion) error
+	CancelAccountDeletion(ctx context.Context, userID int64) error
+	DueAccountDeletions(ctx context.Context, before time.Time, limit int) ([]models.AccountDeletion, error)
+	DeleteScheduledUser(ctx context.Context, userID int64, at time.Time) (string, error)
+	RevokeUserSessions(ctx context.Context, userID int64, at time.Time) (int, error)
+	RevokeUserAppSessions(ctx context.Context, userID int64, appID int, at time.Time) (int, error)
+	RevokeOtherUserSessions(ctx context.Context, userID int64, keepID string, at time.Time) (int, error)
+
+	SaveKnownDevice(ctx context.Context, device models.KnownDevice) error
+	KnownDevices(ctx context.Context, userID int64) ([]models.KnownDevice, error)
+
+	SaveDeviceLogin(ctx context.Context, login models.DeviceLogin) error
+	DeviceLogin(ctx context.Context, id string) (models.DeviceLogin, error)
+	ApproveDeviceLogin(ctx context.Context, pairingCode string, userID int64, at time.Time) (models.DeviceLogin, error)
+	ConsumeDeviceLogin(ctx context.Context, id string, at time.Time) (models.DeviceLogin, error)
+
+	UsersToRemind(ctx context.Context, registeredBefore time.Time, remindedBefore time.Time, maxReminders int, limit int) ([]models.User, error)
+	SaveVerificationReminder(ctx context.Context, userID int64, at time.Time) error
+
+	SaveMaintenance(ctx context.Context, m models.Maintenance) error
+	DeleteMaintenance(ctx context.Context, appID int) error
+	Maintenances(ctx context.Context, at time.Time) ([]models.Maintenance, error)
+
+	SaveAppBan(ctx context.Context, ban models.AppBan) error
+	AppBan(ctx context.Context, userID int64, appID int) (models.AppBan, error)
+	AppBans(ctx context.Context, userID int64, at time.Time) ([]models.AppBan, error)
+	DeleteAppBan(ctx context.Context, userID int64, appID int) error
+
+	AcquireJobLock(ctx context.Context, name string, owner string, now time.Time, until time.Time) (bool, error)
+}
+
+// Run runs conformance tests of a storage backend, so every backend returns the same errors of package storage
+// for the same conditions. newStorage returns storage of a fresh migrated db, it's called by every test.
+// Backends running as servers, e.g. in containers, may share one server as long as dbs are fresh.
+func Run(t *testing.T, newStorage func(t *testing.T) Storage) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s Storage)
+	}{
+		{"Users", testUsers},
+		{"Admins", testAdmins},
+		{"Apps", testApps},
+		{"Verifications", testVerifications},
+		{"VerificationStats", testVerificationStats},
+		{"PendingRegistrations", testPendingRegistrations},
+		{"Suppressions", testSuppressions},
+		{"NotificationPreferences", testNotificationPreferences},
+		{"Sessions", testSessions},
+		{"RefreshTokens", testRefreshTokens},
+		{"Organizations", testOrganizations},
+		{"ServiceAccounts", testServiceAccounts},
+		{"AccountDeletions", testAccountDeletions},
+		{"DeviceLogins", testDeviceLogins},
+		{"VerificationReminders", testVerificationReminders},
+		{"Maintenances", testMaintenances},
+		{"AppBans", testAppBans},
+		{"JobLocks", testJobLocks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+const (
+	email   = "user@example.com"
+	unknown = "unknown@example.com"
+)
+
+func testUsers(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	id, err := s.SaveUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+
+	_, err = s.SaveUser(ctx, email, []byte("other"))
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+
+	user, err := s.User(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+	assert.Equal(t, email, user.Email)
+	assert.Equal(t, []byte("hash"), user.PassHash)
+	assert.False(t, user.Verified)
+
+	_, err = s.User(ctx, unknown)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	byID, err := s.UserByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, email, byID.Email)
+
+	_, err = s.UserByID(ctx, id+100)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	_, err = s.UpdateUser(ctx, user, []byte("new-hash"))
+	require.NoError(t, err)
+
+	_, err = s.UpdateUser(ctx, models.User{ID: id + 100, Email: unknown}, []byte("hash"))
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	_, err = s.VerifyUser(ctx, email)
+	require.NoError(t, err)
+
+	_, err = s.VerifyUser(ctx, unknown)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	user, err = s.User(ctx, email)
+	require.NoError(t, err)
+	assert.True(t, user.Verified)
+	assert.Equal(t, []byte("new-hash"), user.PassHash)
+
+	require.NoError(t, s.SetUserVerified(ctx, id, false))
+	assert.ErrorIs(t, s.SetUserVerified(ctx, id+100, true), storage.ErrUserNotFound)
+
+	user, err = s.UserByID(ctx, id)
+	require.NoError(t, err)
+	assert.False(t, user.Verified)
+	assert.Empty(t, user.Directory)
+
+	_, err = s.SaveExternalUser(ctx, email, "corp", true)
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+
+	externalID, err := s.SaveExternalUser(ctx, unknown, "corp", true)
+	require.NoError(t, err)
+
+	external, err := s.User(ctx, unknown)
+	require.NoError(t, err)
+	assert.Equal(t, externalID, external.ID)
+	assert.Equal(t, "corp", external.Directory)
+	assert.True(t, external.Verified)
+	assert.Empty(t, external.PassHash)
+}
+
+func testAdmins(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	id, err := s.SaveUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.SetAdmin(ctx, id, true))
+
+	isAdmin, err := s.IsAdmin(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	_, err = s.IsAdmin(ctx, id+100)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	assert.ErrorIs(t, s.SetAdmin(ctx, id+100, true), storage.ErrUserNotFound)
+}
+
+func testApps(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	id, err := s.SaveApp(ctx, "conformance", "secret")
+	require.NoError(t, err)
+
+	_, err = s.SaveApp(ctx, "conformance", "other")
+	assert.ErrorIs(t, err, storage.ErrAppExists)
+
+	require.NoError(t, s.UpdateAppSecret(ctx, id, "rotated"))
+
+	app, err := s.App(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "conformance", app.Name)
+	assert.Equal(t, "rotated", app.Secret)
+
+	_, err = s.App(ctx, id+100)
+	assert.ErrorIs(t, err, storage.ErrAppNotFound)
+
+	assert.ErrorIs(t, s.UpdateAppSecret(ctx, id+100, "secret"), storage.ErrAppNotFound)
+
+	withOrigins, err := s.AppsWithOrigins(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, withOrigins)
+
+	origins := []string{"https://app.example.com", "http://localhost:3000"}
+	require.NoError(t, s.UpdateAppWebSettings(ctx, id, origins, "default-src 'self'"))
+	assert.ErrorIs(t, s.UpdateAppWebSettings(ctx, id+100, origins, ""), storage.ErrAppNotFound)
+
+	app, err = s.App(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, origins, app.AllowedOrigins)
+	assert.Equal(t, "default-src 'self'", app.ContentSecurityPolicy)
+
+	withOrigins, err = s.AppsWithOrigins(ctx)
+	require.NoError(t, err)
+	require.Len(t, withOrigins, 1)
+	assert.Equal(t, id, withOrigins[0].ID)
+
+	require.NoError(t, s.UpdateAppTokenClaims(ctx, id, "https://sso.example.com", "api.example.com"))
+	assert.ErrorIs(t, s.UpdateAppTokenClaims(ctx, id+100, "", ""), storage.ErrAppNotFound)
+
+	app, err = s.App(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "https://sso.example.com", app.Issuer)
+	assert.Equal(t, "api.example.com", app.Audience)
+}
+
+func testVerifications(t *testing.T, s Storage) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	_, err := s.SaveUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+
+	_, err = s.StoreVerification(ctx, email, models.VerificationTypeRegistration, "123456", expiresAt)
+	require.NoError(t, err)
+
+	verification, err := s.Verification(ctx, email, models.VerificationTypeRegistration)
+	require.NoError(t, err)
+	assert.Equal(t, "123456", verification.Code)
+	assert.True(t, expiresAt.Equal(verification.ExpiresAt), "expires at %s, want %s", verification.ExpiresAt, expiresAt)
+
+	// Verifications of other types are independent.
+	_, err = s.Verification(ctx, email, models.VerificationTypePasswordReset)
+	assert.ErrorIs(t, err, storage.ErrVerificationNotFound)
+
+	attempts, err := s.IncrementVerificationAttempts(ctx, email, models.VerificationTypeRegistration)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+
+	_, err = s.IncrementVerificationAttempts(ctx, unknown, models.VerificationTypeRegistration)
+	assert.ErrorIs(t, err, storage.ErrVerificationNotFound)
+
+	require.NoError(t, s.DeleteVerification(ctx, email, models.VerificationTypeRegistration))
+
+	_, err = s.Verification(ctx, email, models.VerificationTypeRegistration)
+	assert.ErrorIs(t, err, storage.ErrVerificationNotFound)
+}
+
+func testVerificationStats(t *testing.T, s Storage) {
+	ctx := context.Background()
+	hour := time.Now().UTC().Truncate(time.Hour)
+
+	count := func(vType models.VerificationType, appID int, event models.VerificationEvent, at time.Time) {
+		require.NoError(t, s.CountVerificationEvent(ctx, vType, appID, event, at))
+	}
+
+	count(models.VerificationTypeRegistration, 0, models.VerificationEventCreated, hour)
+	count(models.VerificationTypeRegistration, 0, models.VerificationEventCreated, hour.Add(time.Minute))
+	count(models.VerificationTypeRegistration, 0, models.VerificationEventDelivered, hour.Add(time.Minute))
+	count(models.VerificationTypeRegistration, 0, models.VerificationEventFailed, hour.Add(2*time.Minute))
+	count(models.VerificationTypeRegistration, 0, models.VerificationEventVerified, hour.Add(3*time.Minute))
+	count(models.VerificationTypeSignIn, 1, models.VerificationEventCreated, hour)
+	count(models.VerificationTypeSignIn, 1, models.VerificationEventExpired, hour)
+	// Out of the period.
+	count(models.VerificationTypeSignIn, 1, models.VerificationEventCreated, hour.Add(-2*time.Hour))
+
+	funnels, err := s.VerificationStats(ctx, hour.Add(-time.Hour), hour.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []models.VerificationFunnel{
+		{Type: models.VerificationTypeRegistration, Created: 2, Delivered: 1, Verified: 1, Failed: 1},
+		{Type: models.VerificationTypeSignIn, AppID: 1, Created: 1, Expired: 1},
+	}, funnels)
+
+	funnels, err = s.VerificationStats(ctx, hour.Add(time.Hour), hour.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, funnels)
+}
+
+func testPendingRegistrations(t *testing.T, s Storage) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	require.NoError(t, s.SavePendingRegistration(ctx, email, []byte("hash"), expiresAt))
+
+	err := s.SavePendingRegistration(ctx, email, []byte("other"), expiresAt)
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+
+	_, err = s.ActivatePendingRegistration(ctx, unknown)
+	assert.ErrorIs(t, err, storage.ErrPendingRegistrationNotFound)
+
+	id, err := s.ActivatePendingRegistration(ctx, email)
+	require.NoError(t, err)
+
+	user, err := s.User(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+	assert.Equal(t, []byte("hash"), user.PassHash)
+
+	err = s.SavePendingRegistration(ctx, email, []byte("hash"), expiresAt)
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+}
+
+func testSuppressions(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveSuppression(ctx, models.Suppression{Email: email, Source: models.SuppressionSourceManual}))
+
+	suppression, err := s.Suppression(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, email, suppression.Email)
+
+	_, err = s.Suppression(ctx, unknown)
+	assert.ErrorIs(t, err, storage.ErrSuppressionNotFound)
+
+	require.NoError(t, s.DeleteSuppression(ctx, email))
+
+	assert.ErrorIs(t, s.DeleteSuppression(ctx, email), storage.ErrSuppressionNotFound)
+}
+
+func testNotificationPreferences(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	prefs, err := s.NotificationPreferences(ctx, unknown)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultNotificationPreferences(), prefs, "recipients who are not users get defaults")
+
+	userID, err := s.SaveUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+
+	prefs, err = s.NotificationPreferences(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultNotificationPreferences(), prefs)
+
+	optedOut := models.NotificationPreferences{Reminders: false, Security: true, Product: false}
+	require.NoError(t, s.SaveNotificationPreferences(ctx, userID, optedOut, now))
+
+	prefs, err = s.NotificationPreferences(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, optedOut, prefs)
+
+	require.NoError(t, s.SaveNotificationPreferences(ctx, userID, models.DefaultNotificationPreferences(), now))
+
+	prefs, err = s.NotificationPreferences(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultNotificationPreferences(), prefs, "preferences must be replaced")
+}
+
+func testSessions(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	session := models.Session{
+		ID: "session", SSOSessionID: "login", UserID: 1, AppID: 1, Elevated: true,
+		IP: "203.0.113.1", Country: "DE", City: "Berlin", IssuedAt: now, ExpiresAt: now.Add(time.Hour),
+	}
+	require.NoError(t, s.SaveSession(ctx, session))
+
+	saved, err := s.Session(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, session.UserID, saved.UserID)
+	assert.Equal(t, session.SSOSessionID, saved.SSOSessionID)
+	assert.Equal(t, session.IP, saved.IP)
+	assert.Equal(t, session.Country, saved.Country)
+	assert.Equal(t, session.City, saved.City)
+	assert.True(t, saved.Elevated)
+	assert.True(t, saved.RevokedAt.IsZero())
+
+	_, err = s.Session(ctx, "unknown")
+	assert.ErrorIs(t, err, storage.ErrSessionNotFound)
+
+	require.NoError(t, s.ConsumeSession(ctx, session.ID, now))
+	assert.ErrorIs(t, s.ConsumeSession(ctx, session.ID, now), storage.ErrSessionConsumed)
+
+	require.NoError(t, s.RevokeSession(ctx, session.ID, now))
+	assert.ErrorIs(t, s.RevokeSession(ctx, "unknown", now), storage.ErrSessionNotFound)
+
+	saved, err = s.Session(ctx, session.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.RevokedAt.IsZero())
+	assert.False(t, saved.ConsumedAt.IsZero())
+
+	login := models.Session{ID: "login", SSOSessionID: "login", UserID: 1, AppID: 1, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	other := models.Session{ID: "other", SSOSessionID: "login", UserID: 1, AppID: 2, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, s.SaveSession(ctx, login))
+	require.NoError(t, s.SaveSession(ctx, other))
+
+	// "session" is in the same SSO session, but it's already revoked, so it's not returned.
+	revoked, err := s.RevokeSSOSession(ctx, "login", now)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"login", "other"}, sessionIDs(revoked))
+
+	revoked, err = s.RevokeSSOSession(ctx, "login", now)
+	require.NoError(t, err)
+	assert.Empty(t, revoked)
+}
+
+func testRefreshTokens(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	userID, err := s.SaveUser(ctx, "user@example.com", []byte("hash"))
+	require.NoError(t, err)
+
+	// The session is expired, but the refresh token issued within it is not.
+	login := models.Session{ID: "login", SSOSessionID: "login", UserID: userID, AppID: 1, IssuedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	require.NoError(t, s.SaveSession(ctx, login))
+
+	token := models.RefreshToken{
+		ID: "hash", SessionID: "login", UserID: userID, AppID: 1, Fingerprint: "ua:hash", IssuedAt: now, ExpiresAt: now.Add(time.Hour),
+	}
+	require.NoError(t, s.SaveRefreshToken(ctx, token))
+
+	saved, err := s.RefreshToken(ctx, token.ID)
+	require.NoError(t, err)
+	assert.Equal(t, token.SessionID, saved.SessionID)
+	assert.Equal(t, token.UserID, saved.UserID)
+	assert.Equal(t, token.AppID, saved.AppID)
+	assert.Equal(t, token.Fingerprint, saved.Fingerprint)
+	assert.True(t, saved.UsedAt.IsZero())
+
+	_, err = s.RefreshToken(ctx, "unknown")
+	assert.ErrorIs(t, err, storage.ErrRefreshTokenNotFound)
+
+	require.NoError(t, s.UseRefreshToken(ctx, token.ID, now))
+	assert.ErrorIs(t, s.UseRefreshToken(ctx, token.ID, now), storage.ErrRefreshTokenUsed)
+
+	saved, err = s.RefreshToken(ctx, token.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.UsedAt.IsZero())
+
+	n, err := s.DeleteExpiredSessions(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = s.DeleteExpiredRefreshTokens(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = s.DeleteExpiredSessions(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func sessionIDs(sessions []models.Session) []string {
+	ids := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+
+	return ids
+}
+
+func testOrganizations(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	ownerID, err := s.SaveUser(ctx, "owner@example.com", []byte("hash"))
+	require.NoError(t, err)
+	userID, err := s.SaveUser(ctx, "user@example.com", []byte("hash"))
+	require.NoError(t, err)
+
+	orgID, err := s.SaveOrganization(ctx, models.Organization{Name: "acme", CreatedAt: now}, ownerID)
+	require.NoError(t, err)
+
+	_, err = s.SaveOrganization(ctx, models.Organization{Name: "acme", CreatedAt: now}, ownerID)
+	assert.ErrorIs(t, err, storage.ErrOrganizationExists)
+
+	org, err := s.Organization(ctx, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", org.Name)
+
+	_, err = s.Organization(ctx, orgID+1)
+	assert.ErrorIs(t, err, storage.ErrOrganizationNotFound)
+
+	owner, err := s.OrganizationMember(ctx, orgID, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrgRoleOwner, owner.Role)
+
+	_, err = s.OrganizationMember(ctx, orgID, userID)
+	assert.ErrorIs(t, err, storage.ErrOrganizationMemberNotFound)
+
+	_, err = s.OrganizationInvitation(ctx, orgID, "user@example.com")
+	assert.ErrorIs(t, err, storage.ErrOrganizationInvitationNotFound)
+
+	invitationID, err := s.SaveOrganizationInvitation(ctx, models.OrganizationInvitation{
+		OrgID:     orgID,
+		Email:     "user@example.com",
+		Role:      models.OrgRoleAdmin,
+		Code:      "CODE",
+		InvitedBy: ownerID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	invitation, err := s.OrganizationInvitation(ctx, orgID, "user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, invitationID, invitation.ID)
+	assert.Equal(t, "CODE", invitation.Code)
+	assert.Equal(t, models.OrgRoleAdmin, invitation.Role)
+
+	require.NoError(t, s.AcceptOrganizationInvitation(ctx, invitationID, userID, now))
+	assert.ErrorIs(t, s.AcceptOrganizationInvitation(ctx, invitationID, userID, now), storage.ErrOrganizationInvitationNotFound)
+
+	_, err = s.OrganizationInvitation(ctx, orgID, "user@example.com")
+	assert.ErrorIs(t, err, storage.ErrOrganizationInvitationNotFound, "accepted invitation must not be returned")
+
+	memberships, err := s.Memberships(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, "acme", memberships[0].OrgName)
+	assert.Equal(t, models.OrgRoleAdmin, memberships[0].Role)
+
+	// Accepting another invitation of a member doesn't add the member twice.
+	invitationID, err = s.SaveOrganizationInvitation(ctx, models.OrganizationInvitation{
+		OrgID: orgID, Email: "user@example.com", Role: models.OrgRoleMember, Code: "CODE", InvitedBy: ownerID,
+		CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.AcceptOrganizationInvitation(ctx, invitationID, userID, now), storage.ErrOrganizationMemberExists)
+}
+
+func testServiceAccounts(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	account := models.ServiceAccount{AppID: 1, OrgID: 2, Name: "ci", Scopes: []string{"deploy", "read"}, CreatedAt: now}
+	id, err := s.SaveServiceAccount(ctx, account)
+	require.NoError(t, err)
+
+	_, err = s.SaveServiceAccount(ctx, account)
+	assert.ErrorIs(t, err, storage.ErrServiceAccountExists)
+
+	saved, err := s.ServiceAccount(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, account.Scopes, saved.Scopes)
+	assert.Equal(t, account.OrgID, saved.OrgID)
+	assert.True(t, saved.DisabledAt.IsZero())
+
+	_, err = s.ServiceAccount(ctx, id+1)
+	assert.ErrorIs(t, err, storage.ErrServiceAccountNotFound)
+
+	accounts, err := s.ServiceAccounts(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, accounts, 1)
+
+	key := models.ServiceAccountKey{ID: "key", ServiceAccountID: id, Kind: models.ServiceAccountKeyAPI, SecretHash: []byte("hash"), CreatedAt: now}
+	require.NoError(t, s.SaveServiceAccountKey(ctx, key))
+
+	savedKey, err := s.ServiceAccountKey(ctx, key.ID)
+	require.NoError(t, err)
+	assert.Equal(t, key.SecretHash, savedKey.SecretHash)
+	assert.True(t, savedKey.RevokedAt.IsZero())
+
+	_, err = s.ServiceAccountKey(ctx, "unknown")
+	assert.ErrorIs(t, err, storage.ErrServiceAccountKeyNotFound)
+
+	require.NoError(t, s.RevokeServiceAccountKey(ctx, key.ID, now))
+	assert.ErrorIs(t, s.RevokeServiceAccountKey(ctx, "unknown", now), storage.ErrServiceAccountKeyNotFound)
+
+	savedKey, err = s.ServiceAccountKey(ctx, key.ID)
+	require.NoError(t, err)
+	assert.False(t, savedKey.RevokedAt.IsZero())
+
+	session := models.Session{ID: "session", SSOSessionID: "session", ServiceAccountID: id, AppID: 1, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, s.SaveSession(ctx, session))
+
+	require.NoError(t, s.DisableServiceAccount(ctx, id, now))
+	assert.ErrorIs(t, s.DisableServiceAccount(ctx, id+1, now), storage.ErrServiceAccountNotFound)
+
+	saved, err = s.ServiceAccount(ctx, id)
+	require.NoError(t, err)
+	assert.False(t, saved.DisabledAt.IsZero())
+
+	savedSession, err := s.Session(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, id, savedSession.ServiceAccountID)
+	assert.False(t, savedSession.RevokedAt.IsZero(), "sessions of disabled account must be revoked")
+}
+
+func testAccountDeletions(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	userID, err := s.SaveUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+
+	session := models.Session{ID: "session", SSOSessionID: "session", UserID: userID, AppID: 1, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, s.SaveSession(ctx, session))
+	other := models.Session{ID: "other", SSOSessionID: "session", UserID: userID, AppID: 2, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, s.SaveSession(ctx, other))
+
+	n, err := s.RevokeUserAppSessions(ctx, userID, 2, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	saved, err := s.Session(ctx, session.ID)
+	require.NoError(t, err)
+	assert.True(t, saved.RevokedAt.IsZero(), "sessions of other apps must not be revoked")
+
+	third := models.Session{ID: "third", SSOSessionID: "third", UserID: userID, AppID: 1, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, s.SaveSession(ctx, third))
+
+	n, err = s.RevokeOtherUserSessions(ctx, userID, session.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "revoked sessions must not be counted again")
+
+	saved, err = s.Session(ctx, session.ID)
+	require.NoError(t, err)
+	assert.True(t, saved.RevokedAt.IsZero(), "kept session must not be revoked")
+
+	n, err = s.RevokeUserSessions(ctx, userID, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	saved, err = s.Session(ctx, session.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.RevokedAt.IsZero())
+
+	// Every kind of row of the user is saved, so the purge is checked to leave none of them.
+	refresh := models.RefreshToken{ID: "refresh", SessionID: session.ID, UserID: userID, AppID: 1, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, s.SaveRefreshToken(ctx, refresh))
+	require.NoError(t, s.SaveNotificationPreferences(ctx, userID, models.NotificationPreferences{}, now))
+	_, err = s.StoreVerification(ctx, email, models.VerificationTypeRegistration, "CODE", now.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = s.SaveEmail(ctx, models.Email{MessageID: "message", Recipient: email, Subject: "subject", Status: models.EmailStatusSent, CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	require.NoError(t, s.SaveKnownDevice(ctx, models.KnownDevice{UserID: userID, Key: "device", FirstSeenAt: now, LastSeenAt: now}))
+	require.NoError(t, s.SaveVerificationReminder(ctx, userID, now))
+	require.NoError(t, s.SaveAppBan(ctx, models.AppBan{UserID: userID, AppID: 1, Reason: "abuse", CreatedAt: now}))
+	orgID, err := s.SaveOrganization(ctx, models.Organization{Name: "org", CreatedAt: now}, userID)
+	require.NoError(t, err)
+	_, err = s.SaveOrganizationInvitation(ctx, models.OrganizationInvitation{
+		OrgID:     orgID,
+		Email:     email,
+		Role:      models.OrgRoleMember,
+		Code:      "CODE",
+		InvitedBy: userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	login := models.DeviceLogin{ID: "login", PairingCode: "BCDFGHJK", AppID: 1, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, s.SaveDeviceLogin(ctx, login))
+	_, err = s.ApproveDeviceLogin(ctx, login.PairingCode, userID, now)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, s.CancelAccountDeletion(ctx, userID), storage.ErrAccountDeletionNotFound)
+
+	deletion := models.AccountDeletion{UserID: userID, RequestedAt: now, ScheduledAt: now.Add(time.Hour)}
+	require.NoError(t, s.SaveAccountDeletion(ctx, deletion))
+
+	due, err := s.DueAccountDeletions(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	_, err = s.DeleteScheduledUser(ctx, userID, now)
+	assert.ErrorIs(t, err, storage.ErrAccountDeletionNotFound, "deletion is not due yet")
+
+	require.NoError(t, s.CancelAccountDeletion(ctx, userID))
+	_, err = s.DeleteScheduledUser(ctx, userID, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, storage.ErrAccountDeletionNotFound)
+
+	require.NoError(t, s.SaveAccountDeletion(ctx, deletion))
+
+	due, err = s.DueAccountDeletions(ctx, now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, userID, due[0].UserID)
+
+	deletedEmail, err := s.DeleteScheduledUser(ctx, userID, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, email, deletedEmail)
+
+	_, err = s.User(ctx, email)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+	_, err = s.Session(ctx, session.ID)
+	assert.ErrorIs(t, err, storage.ErrSessionNotFound)
+	_, err = s.RefreshToken(ctx, refresh.ID)
+	assert.ErrorIs(t, err, storage.ErrRefreshTokenNotFound)
+	_, err = s.Verification(ctx, email, models.VerificationTypeRegistration)
+	assert.ErrorIs(t, err, storage.ErrVerificationNotFound)
+	_, err = s.Emails(ctx, "message")
+	assert.ErrorIs(t, err, storage.ErrEmailNotFound)
+	devices, err := s.KnownDevices(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+	_, err = s.AppBan(ctx, userID, 1)
+	assert.ErrorIs(t, err, storage.ErrAppBanNotFound)
+	memberships, err := s.Memberships(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, memberships)
+	_, err = s.OrganizationInvitation(ctx, orgID, email)
+	assert.ErrorIs(t, err, storage.ErrOrganizationInvitationNotFound)
+	_, err = s.DeviceLogin(ctx, login.ID)
+	assert.ErrorIs(t, err, storage.ErrDeviceLoginNotFound)
+
+	// Preferences of a new user with the same email must not be inherited from the deleted one.
+	_, err = s.SaveUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+	prefs, err := s.NotificationPreferences(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultNotificationPreferences(), prefs)
+
+	due, err = s.DueAccountDeletions(ctx, now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func testDeviceLogins(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	appID, err := s.SaveApp(ctx, "app", "secret")
+	require.NoError(t, err)
+
+	login := models.DeviceLogin{
+		ID:          "login-hash",
+		PairingCode: "BCDFGHJK",
+		AppID:       appID,
+		Device:      models.Device{UserAgent: "tv", IP: "10.0.0.1", Country: "NL"},
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(10 * time.Minute),
+	}
+	require.NoError(t, s.SaveDeviceLogin(ctx, login))
+	assert.ErrorIs(t, s.SaveDeviceLogin(ctx, models.DeviceLogin{
+		ID:          "other-hash",
+		PairingCode: login.PairingCode,
+		AppID:       appID,
+		CreatedAt:   now,
+		ExpiresAt:   login.ExpiresAt,
+	}), storage.ErrDeviceLoginExists)
+
+	saved, err := s.DeviceLogin(ctx, login.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.Approved())
+	assert.Equal(t, login.Device, saved.Device)
+
+	_, err = s.DeviceLogin(ctx, "unknown")
+	assert.ErrorIs(t, err, storage.ErrDeviceLoginNotFound)
+
+	_, err = s.ConsumeDeviceLogin(ctx, login.ID, now)
+	assert.ErrorIs(t, err, storage.ErrDeviceLoginNotFound, "pending login is not consumed")
+
+	_, err = s.ApproveDeviceLogin(ctx, login.PairingCode, 1, login.ExpiresAt)
+	assert.ErrorIs(t, err, storage.ErrDeviceLoginNotFound, "expired login is not approved")
+
+	approved, err := s.ApproveDeviceLogin(ctx, login.PairingCode, 1, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), approved.UserID)
+	assert.True(t, approved.Approved())
+
+	_, err = s.ApproveDeviceLogin(ctx, login.PairingCode, 2, now)
+	assert.ErrorIs(t, err, storage.ErrDeviceLoginNotFound, "login is approved once")
+
+	consumed, err := s.ConsumeDeviceLogin(ctx, login.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), consumed.UserID)
+	assert.Equal(t, appID, consumed.AppID)
+
+	_, err = s.ConsumeDeviceLogin(ctx, login.ID, now)
+	assert.ErrorIs(t, err, storage.ErrDeviceLoginNotFound, "login is consumed once")
+}
+
+func testVerificationReminders(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	id, err := s.SaveUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	later := now.Add(time.Minute)
+
+	users, err := s.UsersToRemind(ctx, now.Add(-time.Hour), later, 2, 10)
+	require.NoError(t, err)
+	assert.Empty(t, users, "user registered after registeredBefore is not reminded")
+
+	users, err = s.UsersToRemind(ctx, later, later, 2, 10)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, id, users[0].ID)
+	assert.Equal(t, email, users[0].Email)
+
+	require.NoError(t, s.SaveVerificationReminder(ctx, id, now))
+
+	users, err = s.UsersToRemind(ctx, later, now.Add(-time.Minute), 2, 10)
+	require.NoError(t, err)
+	assert.Empty(t, users, "user reminded after remindedBefore is not reminded")
+
+	require.NoError(t, s.SaveVerificationReminder(ctx, id, now))
+
+	users, err = s.UsersToRemind(ctx, later, later, 2, 10)
+	require.NoError(t, err)
+	assert.Empty(t, users, "user is reminded maxReminders times")
+
+	users, err = s.UsersToRemind(ctx, later, later, 3, 10)
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	_, err = s.VerifyUser(ctx, email)
+	require.NoError(t, err)
+
+	users, err = s.UsersToRemind(ctx, later, later, 3, 10)
+	require.NoError(t, err)
+	assert.Empty(t, users, "verified user is not reminded")
+}
+
+func testMaintenances(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, s.SaveMaintenance(ctx, models.Maintenance{AppID: 0, Message: "upgrade", StartedAt: now}))
+	require.NoError(t, s.SaveMaintenance(ctx, models.Maintenance{AppID: 1, StartedAt: now, EndsAt: now.Add(time.Hour)}))
+
+	windows, err := s.Maintenances(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	assert.Equal(t, "upgrade", windows[0].Message)
+	assert.True(t, windows[0].EndsAt.IsZero())
+	assert.Equal(t, 1, windows[1].AppID)
+	assert.True(t, windows[1].EndsAt.Equal(now.Add(time.Hour)))
+
+	windows, err = s.Maintenances(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, windows, 1, "window is over after it ends")
+
+	require.NoError(t, s.SaveMaintenance(ctx, models.Maintenance{AppID: 0, Message: "still upgrading", StartedAt: now}))
+
+	windows, err = s.Maintenances(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, windows, 2, "saving running window replaces it")
+	assert.Equal(t, "still upgrading", windows[0].Message)
+
+	require.NoError(t, s.DeleteMaintenance(ctx, 0))
+
+	assert.ErrorIs(t, s.DeleteMaintenance(ctx, 0), storage.ErrMaintenanceNotFound)
+}
+
+func testAppBans(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	userID, err := s.SaveUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+
+	_, err = s.AppBan(ctx, userID, 1)
+	assert.ErrorIs(t, err, storage.ErrAppBanNotFound)
+
+	require.NoError(t, s.SaveAppBan(ctx, models.AppBan{UserID: userID, AppID: 1, Reason: "abuse", CreatedAt: now}))
+	require.NoError(t, s.SaveAppBan(ctx, models.AppBan{UserID: userID, AppID: 2, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	ban, err := s.AppBan(ctx, userID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "abuse", ban.Reason)
+	assert.True(t, ban.ExpiresAt.IsZero())
+
+	bans, err := s.AppBans(ctx, userID, now)
+	require.NoError(t, err)
+	require.Len(t, bans, 2)
+	assert.Equal(t, 2, bans[1].AppID)
+	assert.True(t, bans[1].ExpiresAt.Equal(now.Add(time.Hour)))
+
+	bans, err = s.AppBans(ctx, userID, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, bans, 1, "expired ban is not listed")
+
+	ban, err = s.AppBan(ctx, userID, 2)
+	require.NoError(t, err)
+	assert.False(t, ban.Active(now.Add(2*time.Hour)), "expired ban is returned, but not active")
+
+	require.NoError(t, s.SaveAppBan(ctx, models.AppBan{UserID: userID, AppID: 1, Reason: "spam", CreatedAt: now}))
+
+	ban, err = s.AppBan(ctx, userID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "spam", ban.Reason, "saving existing ban replaces it")
+
+	require.NoError(t, s.DeleteAppBan(ctx, userID, 1))
+
+	assert.ErrorIs(t, s.DeleteAppBan(ctx, userID, 1), storage.ErrAppBanNotFound)
+}
+
+func testJobLocks(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	acquired, err := s.AcquireJobLock(ctx, "cleanup", "replica-1", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = s.AcquireJobLock(ctx, "cleanup", "replica-2", now.Add(30*time.Second), now.Add(90*time.Second))
+	require.NoError(t, err)
+	assert.False(t, acquired, "lock is held by another owner")
+
+	acquired, err = s.AcquireJobLock(ctx, "other", "replica-2", now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, acquired, "locks of other jobs are independent")
+
+	acquired, err = s.AcquireJobLock(ctx, "cleanup", "replica-1", now.Add(30*time.Second), now.Add(90*time.Second))
+	require.NoError(t, err)
+	assert.True(t, acquired, "owner renews its lock")
+
+	acquired, err = s.AcquireJobLock(ctx, "cleanup", "replica-2", now.Add(time.Minute), now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.False(t, acquired, "renewed lock is held until it expires")
+
+	acquired, err = s.AcquireJobLock(ctx, "cleanup", "replica-2", now.Add(90*time.Second), now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, acquired, "expired lock is taken over")
+}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS pending_registrations
+(
+    email      TEXT PRIMARY KEY,
+    pass_hash  BLOB      NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE apps
+    ADD COLUMN require_verified BOOLEAN NOT NULL DEFAULT FALSE;
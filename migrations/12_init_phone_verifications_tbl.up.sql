@@ -0,0 +1,11 @@
+ALTER TABLE users ADD COLUMN phone Varchar(20);
+ALTER TABLE users ADD COLUMN is_phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+create table if not exists phone_verifications (
+			email 		Varchar(100) not null primary key,
+			phone 		Varchar(20) not null,
+			code  		Varchar(10) not null,
+			expiresat 	Timestamp not null,
+			attempts 	Integer not null default 0,
+			Constraint fk_user_email Foreign Key(email) References users(email)
+				On Delete Cascade On Update Cascade
+		);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id         INTEGER PRIMARY KEY,
+    action     TEXT      NOT NULL,
+    actor_id   INTEGER   NOT NULL DEFAULT 0,
+    subject    TEXT      NOT NULL DEFAULT '',
+    app_id     INTEGER   NOT NULL DEFAULT 0,
+    ip         TEXT      NOT NULL DEFAULT '',
+    payload    TEXT      NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_subject ON audit_events (subject);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id);
+
+-- audit log is append-only
+CREATE TRIGGER IF NOT EXISTS audit_events_no_update BEFORE UPDATE ON audit_events
+BEGIN
+    SELECT RAISE(ABORT, 'audit_events is append-only');
+END;
+CREATE TRIGGER IF NOT EXISTS audit_events_no_delete BEFORE DELETE ON audit_events
+BEGIN
+    SELECT RAISE(ABORT, 'audit_events is append-only');
+END;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id     INTEGER PRIMARY KEY,
+    app_id INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    url    TEXT    NOT NULL,
+    secret TEXT    NOT NULL,
+    -- comma separated event types, empty means all events
+    events TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_app_id ON webhooks (app_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              INTEGER PRIMARY KEY,
+    webhook_id      INTEGER   NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event           TEXT      NOT NULL,
+    payload         BLOB      NOT NULL,
+    status          TEXT      NOT NULL,
+    attempts        INTEGER   NOT NULL DEFAULT 0,
+    last_error      TEXT      NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
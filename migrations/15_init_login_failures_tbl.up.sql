@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS login_failures
+(
+    -- throttled key, e.g. "account:<email>" or "ip:<address>"
+    key             TEXT PRIMARY KEY,
+    failures        INTEGER   NOT NULL,
+    last_failure_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_login_failures_last_failure_at ON login_failures (last_failure_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS app_ip_rules;
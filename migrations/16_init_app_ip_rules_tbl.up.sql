@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS app_ip_rules
+(
+    id     INTEGER PRIMARY KEY,
+    app_id INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    -- CIDR range or single IP
+    cidr   TEXT    NOT NULL,
+    action TEXT    NOT NULL CHECK (action IN ('allow', 'deny'))
+);
+CREATE INDEX IF NOT EXISTS idx_app_ip_rules_app_id ON app_ip_rules (app_id);
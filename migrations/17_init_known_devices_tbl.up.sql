@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS known_devices
+(
+    user_id       INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key           TEXT      NOT NULL,
+    user_agent    TEXT      NOT NULL DEFAULT '',
+    ip            TEXT      NOT NULL DEFAULT '',
+    country       TEXT      NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMP NOT NULL,
+    last_seen_at  TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
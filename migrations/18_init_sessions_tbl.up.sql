@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id          TEXT PRIMARY KEY,
+    user_id     INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id      INTEGER   NOT NULL,
+    elevated    BOOLEAN   NOT NULL DEFAULT FALSE,
+    issued_at   TIMESTAMP NOT NULL,
+    expires_at  TIMESTAMP NOT NULL,
+    revoked_at  TIMESTAMP,
+    consumed_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions (expires_at);
@@ -0,0 +1,3 @@
+ALTER TABLE phone_verifications DROP COLUMN phone_enc;
+ALTER TABLE users DROP COLUMN phone_enc;
+ALTER TABLE users DROP COLUMN email_enc;
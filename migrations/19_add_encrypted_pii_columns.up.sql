@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN email_enc BLOB;
+ALTER TABLE users ADD COLUMN phone_enc BLOB;
+ALTER TABLE phone_verifications ADD COLUMN phone_enc BLOB;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sessions_sso_session_id;
+ALTER TABLE sessions DROP COLUMN sso_session_id;
@@ -0,0 +1,3 @@
+ALTER TABLE sessions ADD COLUMN sso_session_id TEXT NOT NULL DEFAULT '';
+UPDATE sessions SET sso_session_id = id;
+CREATE INDEX IF NOT EXISTS idx_sessions_sso_session_id ON sessions (sso_session_id);
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS organizations
+(
+    id         INTEGER PRIMARY KEY,
+    name       TEXT      NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS organization_members
+(
+    org_id    INTEGER   NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id   INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role      TEXT      NOT NULL,
+    joined_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (org_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members (user_id);
+
+CREATE TABLE IF NOT EXISTS organization_invitations
+(
+    id          INTEGER PRIMARY KEY,
+    org_id      INTEGER   NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email       TEXT      NOT NULL,
+    role        TEXT      NOT NULL,
+    code        TEXT      NOT NULL,
+    invited_by  INTEGER   NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    expires_at  TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_org_id_email ON organization_invitations (org_id, email);
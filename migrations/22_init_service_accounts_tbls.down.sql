@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_sessions_service_account_id;
+ALTER TABLE sessions DROP COLUMN service_account_id;
+DROP TABLE IF EXISTS service_account_keys;
+DROP TABLE IF EXISTS service_accounts;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS service_accounts
+(
+    id          INTEGER PRIMARY KEY,
+    app_id      INTEGER   NOT NULL,
+    org_id      INTEGER   NOT NULL DEFAULT 0,
+    name        TEXT      NOT NULL,
+    scopes      TEXT      NOT NULL DEFAULT '',
+    disabled_at TIMESTAMP,
+    created_at  TIMESTAMP NOT NULL,
+    UNIQUE (app_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS service_account_keys
+(
+    id                 TEXT PRIMARY KEY,
+    service_account_id INTEGER   NOT NULL REFERENCES service_accounts (id) ON DELETE CASCADE,
+    kind               TEXT      NOT NULL,
+    secret_hash        BLOB,
+    public_key         TEXT      NOT NULL DEFAULT '',
+    created_at         TIMESTAMP NOT NULL,
+    revoked_at         TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_service_account_keys_service_account_id ON service_account_keys (service_account_id);
+
+ALTER TABLE sessions ADD COLUMN service_account_id INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_sessions_service_account_id ON sessions (service_account_id);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS account_deletions
+(
+    user_id      INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    requested_at TIMESTAMP NOT NULL,
+    scheduled_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_account_deletions_scheduled_at ON account_deletions (scheduled_at);
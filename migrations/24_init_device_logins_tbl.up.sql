@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS device_logins
+(
+    id           TEXT PRIMARY KEY,
+    pairing_code TEXT      NOT NULL UNIQUE,
+    app_id       INTEGER   NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    device_id    TEXT      NOT NULL DEFAULT '',
+    user_agent   TEXT      NOT NULL DEFAULT '',
+    ip           TEXT      NOT NULL DEFAULT '',
+    country      TEXT      NOT NULL DEFAULT '',
+    user_id      INTEGER   NOT NULL DEFAULT 0,
+    approved_at  TIMESTAMP,
+    created_at   TIMESTAMP NOT NULL,
+    expires_at   TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_device_logins_expires_at ON device_logins (expires_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS verification_reminders;
+ALTER TABLE users DROP COLUMN created_at;
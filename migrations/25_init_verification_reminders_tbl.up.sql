@@ -0,0 +1,9 @@
+-- created_at is unknown for users registered before, they are never reminded
+ALTER TABLE users ADD COLUMN created_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS verification_reminders
+(
+    user_id      INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    sent         INTEGER   NOT NULL,
+    last_sent_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,9 @@
+ALTER TABLE known_devices DROP COLUMN city;
+
+ALTER TABLE sessions DROP COLUMN city;
+ALTER TABLE sessions DROP COLUMN country;
+ALTER TABLE sessions DROP COLUMN ip;
+
+DROP INDEX IF EXISTS idx_audit_events_country;
+ALTER TABLE audit_events DROP COLUMN city;
+ALTER TABLE audit_events DROP COLUMN country;
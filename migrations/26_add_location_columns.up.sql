@@ -0,0 +1,9 @@
+ALTER TABLE audit_events ADD COLUMN country TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN city TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_audit_events_country ON audit_events (country);
+
+ALTER TABLE sessions ADD COLUMN ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN country TEXT NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN city TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE known_devices ADD COLUMN city TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE apps DROP COLUMN content_security_policy;
+ALTER TABLE apps DROP COLUMN allowed_origins;
@@ -0,0 +1,3 @@
+-- allowed_origins are space-separated origins of frontends of the app, e.g. https://app.example.com
+ALTER TABLE apps ADD COLUMN allowed_origins TEXT NOT NULL DEFAULT '';
+ALTER TABLE apps ADD COLUMN content_security_policy TEXT NOT NULL DEFAULT '';
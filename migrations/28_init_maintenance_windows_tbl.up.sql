@@ -0,0 +1,8 @@
+-- app_id 0 is maintenance of all apps.
+CREATE TABLE IF NOT EXISTS maintenance_windows
+(
+    app_id     INTEGER PRIMARY KEY,
+    message    TEXT      NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    ends_at    TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- directory is name of external user directory authenticating the user, empty for local users.
+ALTER TABLE users ADD COLUMN directory TEXT NOT NULL DEFAULT '';
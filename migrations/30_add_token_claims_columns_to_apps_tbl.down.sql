@@ -0,0 +1,2 @@
+ALTER TABLE apps DROP COLUMN audience;
+ALTER TABLE apps DROP COLUMN issuer;
@@ -0,0 +1,3 @@
+-- issuer and audience override iss and aud claims of tokens of the app, empty means the configured ones
+ALTER TABLE apps ADD COLUMN issuer TEXT NOT NULL DEFAULT '';
+ALTER TABLE apps ADD COLUMN audience TEXT NOT NULL DEFAULT '';
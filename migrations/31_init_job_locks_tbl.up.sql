@@ -0,0 +1,7 @@
+-- job_locks are leases of background jobs, so a job runs on one replica at a time.
+CREATE TABLE IF NOT EXISTS job_locks
+(
+    name       TEXT PRIMARY KEY,
+    owner      TEXT      NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,14 @@
+-- refresh_tokens are issued along access tokens of the v2 API, id is SHA-256 of the token.
+-- session_id is the SSO session, revoking it ends the refresh tokens issued within it.
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id         TEXT PRIMARY KEY,
+    session_id TEXT      NOT NULL,
+    user_id    INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id     INTEGER   NOT NULL,
+    issued_at  TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at    TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens (expires_at);
@@ -0,0 +1,2 @@
+-- fingerprint of the client the refresh token is bound to, empty if it's not bound.
+ALTER TABLE refresh_tokens ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '';
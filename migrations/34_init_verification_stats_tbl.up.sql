@@ -0,0 +1,11 @@
+-- verification_stats counts verification events per hour, type and app, app_id is 0 if the verification
+-- isn't related to an app. Stats of a period sum its hours.
+CREATE TABLE IF NOT EXISTS verification_stats
+(
+    hour   TIMESTAMP NOT NULL,
+    type   TEXT      NOT NULL,
+    app_id INTEGER   NOT NULL DEFAULT 0,
+    event  TEXT      NOT NULL,
+    count  INTEGER   NOT NULL DEFAULT 0,
+    PRIMARY KEY (hour, type, app_id, event)
+);
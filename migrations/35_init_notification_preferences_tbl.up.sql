@@ -0,0 +1,9 @@
+-- notification_preferences are non-essential emails users agreed to receive, users without a row receive all of them.
+CREATE TABLE IF NOT EXISTS notification_preferences
+(
+    user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    reminders  BOOLEAN   NOT NULL DEFAULT TRUE,
+    security   BOOLEAN   NOT NULL DEFAULT TRUE,
+    product    BOOLEAN   NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,10 @@
+-- app_bans keep users from signing in to a single app, other apps remain usable.
+CREATE TABLE IF NOT EXISTS app_bans
+(
+    user_id    INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id     INTEGER   NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    reason     TEXT      NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP,
+    PRIMARY KEY (user_id, app_id)
+);
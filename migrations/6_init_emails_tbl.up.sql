@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS emails
+(
+    id         INTEGER PRIMARY KEY,
+    message_id TEXT      NOT NULL,
+    recipient  TEXT      NOT NULL,
+    user_id    INTEGER,
+    subject    TEXT      NOT NULL,
+    status     TEXT      NOT NULL,
+    reason     TEXT      NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (message_id, recipient)
+);
+CREATE INDEX IF NOT EXISTS idx_emails_message_id ON emails (message_id);
+CREATE INDEX IF NOT EXISTS idx_emails_recipient ON emails (recipient);
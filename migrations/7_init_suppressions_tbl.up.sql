@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS suppressions
+(
+    email      TEXT PRIMARY KEY,
+    source     TEXT      NOT NULL,
+    reason     TEXT      NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE verifications
+    ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
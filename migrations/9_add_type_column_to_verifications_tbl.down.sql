@@ -0,0 +1,13 @@
+create table if not exists verifications_untyped (
+			email 		Varchar(100) not null,
+			code  		Varchar(10) not null,
+			expiresat 	Timestamp not null,
+			attempts 	Integer not null default 0,
+			Primary Key (email),
+			Constraint fk_user_email Foreign Key(email) References users(email)
+				On Delete Cascade On Update Cascade
+		);
+insert or ignore into verifications_untyped (email, code, expiresat, attempts)
+	select email, code, expiresat, attempts from verifications where type = 'registration';
+drop table verifications;
+alter table verifications_untyped rename to verifications;
@@ -0,0 +1,14 @@
+create table if not exists verifications_typed (
+			email 		Varchar(100) not null,
+			type 		Varchar(20) not null default 'registration',
+			code  		Varchar(10) not null,
+			expiresat 	Timestamp not null,
+			attempts 	Integer not null default 0,
+			Primary Key (email, type),
+			Constraint fk_user_email Foreign Key(email) References users(email)
+				On Delete Cascade On Update Cascade
+		);
+insert into verifications_typed (email, type, code, expiresat, attempts)
+	select email, 'registration', code, expiresat, attempts from verifications;
+drop table verifications;
+alter table verifications_typed rename to verifications;
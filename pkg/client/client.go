@@ -0,0 +1,311 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultTimeout      = 5 * time.Second
+	defaultRetries      = 3
+	defaultRetryBackoff = 100 * time.Millisecond
+)
+
+// Config configures Client, zero values mean defaults.
+type Config struct {
+	// Timeout of a single attempt of a call, 5s by default. Deadline of the call context applies too.
+	Timeout time.Duration
+	// Retries is how many times a call is retried if the service is unavailable, 3 by default, negative disables retries.
+	// Other errors are not retried, since the call may have taken effect.
+	Retries int
+	// RetryBackoff is the delay before the first retry, doubled before each next one, 100ms by default.
+	RetryBackoff time.Duration
+	// TLS enables TLS with the config, the connection is insecure if it's nil.
+	TLS *tls.Config
+	// DialOptions are added to options of the connection.
+	DialOptions []grpc.DialOption
+}
+
+// Client calls Auth service of SSO. Errors of calls wrap errors of this package where a caller can act on them,
+// e.g. ErrInvalidCredentials, otherwise they are gRPC status errors.
+type Client struct {
+	conn *grpc.ClientConn
+	auth ssov1.AuthClient
+	cfg  Config
+}
+
+// New connects to SSO at addr, e.g. "sso:44044". The connection is established lazily by the first call.
+func New(addr string, cfg Config) (*Client, error) {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.Retries == 0 {
+		cfg.Retries = defaultRetries
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.TLS != nil {
+		creds = credentials.NewTLS(cfg.TLS)
+	}
+
+	conn, err := grpc.Dial(addr, append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, cfg.DialOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{conn: conn, auth: ssov1.NewAuthClient(conn), cfg: cfg}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Register registers user and returns its ID. Verification code is emailed to the user.
+// ErrUserExists is returned if the email is taken, ErrWeakPassword if the password is too weak.
+func (c *Client) Register(ctx context.Context, email string, password string) (int64, error) {
+	var userID int64
+
+	err := c.call(ctx, func(ctx context.Context) error {
+		resp, err := c.auth.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password})
+		userID = resp.GetUserId()
+
+		return err
+	})
+
+	return userID, err
+}
+
+// Login returns access token of the user for the app.
+// ErrInvalidCredentials, ErrEmailNotVerified and ErrSignInNotConfirmed tell why login is rejected.
+func (c *Client) Login(ctx context.Context, email string, password string, appID int) (string, error) {
+	var token string
+
+	err := c.call(ctx, func(ctx context.Context) error {
+		resp, err := c.auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: int32(appID)})
+		token = resp.GetToken()
+
+		return err
+	})
+
+	return token, err
+}
+
+// Authorize returns token for the app to the user signed in to another app by the token, without credentials,
+// while SSO session started by Login lasts. ErrLoginRequired is returned once it's over,
+// or always if prompt is "login".
+func (c *Client) Authorize(ctx context.Context, token string, appID int, prompt string) (string, error) {
+	var appToken string
+
+	err := c.call(ctx, func(ctx context.Context) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		resp, err := c.auth.Authorize(ctx, &ssov1.AuthorizeRequest{AppId: int32(appID), Prompt: prompt})
+		appToken = resp.GetToken()
+
+		return err
+	})
+
+	return appToken, err
+}
+
+// ServiceAccountToken exchanges API key of a service account for its token with the scopes,
+// all scopes of the account if none are given. ErrUnauthenticated is returned for unknown or revoked key.
+func (c *Client) ServiceAccountToken(ctx context.Context, apiKey string, scopes ...string) (string, error) {
+	var token string
+
+	err := c.call(ctx, func(ctx context.Context) error {
+		resp, err := c.auth.IssueServiceAccountToken(ctx, &ssov1.IssueServiceAccountTokenRequest{ApiKey: apiKey, Scopes: scopes})
+		token = resp.GetToken()
+
+		return err
+	})
+
+	return token, err
+}
+
+// IsAdmin tells whether the user is admin, ErrNotFound is returned if there is no such user.
+func (c *Client) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	var isAdmin bool
+
+	err := c.call(ctx, func(ctx context.Context) error {
+		resp, err := c.auth.IsAdmin(ctx, &ssov1.IsAdminRequest{UserId: userID})
+		isAdmin = resp.GetIsAdmin()
+
+		return err
+	})
+
+	return isAdmin, err
+}
+
+// VerifyEmail verifies email of the registered user by the code emailed to it.
+// ErrInvalidCode is returned if the code is wrong or expired.
+func (c *Client) VerifyEmail(ctx context.Context, email string, code string) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		_, err := c.auth.VerifyMail(ctx, &ssov1.VerifyMailRequest{Email: email, Code: code})
+
+		return err
+	})
+}
+
+// ResendVerification emails a new verification code to the registered user.
+func (c *Client) ResendVerification(ctx context.Context, email string) error {
+	return c.createVerification(ctx, email, ssov1.VerificationType_VERIFICATION_TYPE_REGISTRATION)
+}
+
+// RequestPasswordReset emails password reset code to the user, the code is passed to ResetPassword.
+// It succeeds for unregistered emails too, so forms don't reveal which emails are registered.
+func (c *Client) RequestPasswordReset(ctx context.Context, email string) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		_, err := c.auth.RequestPasswordReset(ctx, &ssov1.RequestPasswordResetRequest{Email: email})
+
+		return err
+	})
+}
+
+// ResetPassword sets new password of the user by the code emailed by RequestPasswordReset.
+// ErrInvalidCode is returned if the code is wrong or expired, ErrWeakPassword if the password is too weak.
+func (c *Client) ResetPassword(ctx context.Context, email string, code string, newPassword string) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		_, err := c.auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{Email: email, Code: code, NewPassword: newPassword})
+
+		return err
+	})
+}
+
+// PasswordStrength is strength of a password estimated by SSO.
+type PasswordStrength struct {
+	// Score is 0 (too guessable) to 4 (very unguessable).
+	Score int
+	// GuessesLog10 is order of magnitude of guesses needed to find the password.
+	GuessesLog10 float64
+	Warning      string
+	Suggestions  []string
+	// Acceptable tells whether the password is accepted by Register and ResetPassword.
+	Acceptable bool
+}
+
+// CheckPasswordStrength estimates strength of the password for the user with the email, which may be empty.
+func (c *Client) CheckPasswordStrength(ctx context.Context, password string, email string) (PasswordStrength, error) {
+	var strength PasswordStrength
+
+	err := c.call(ctx, func(ctx context.Context) error {
+		resp, err := c.auth.CheckPasswordStrength(ctx, &ssov1.CheckPasswordStrengthRequest{Password: password, Email: email})
+		strength = PasswordStrength{
+			Score:        int(resp.GetScore()),
+			GuessesLog10: resp.GetGuessesLog10(),
+			Warning:      resp.GetWarning(),
+			Suggestions:  resp.GetSuggestions(),
+			Acceptable:   resp.GetAcceptable(),
+		}
+
+		return err
+	})
+
+	return strength, err
+}
+
+// Logout revokes the access token.
+func (c *Client) Logout(ctx context.Context, token string) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		_, err := c.auth.Logout(ctx, &ssov1.LogoutRequest{})
+
+		return err
+	})
+}
+
+// Profile is the user signed in with a token.
+type Profile struct {
+	UserID   int64
+	Email    string
+	Verified bool
+	IsAdmin  bool
+	// Orgs are roles of the user by organization ID.
+	Orgs map[int64]string
+	// SessionID is jti claim of the token, AppID is the app the token is issued for.
+	SessionID string
+	AppID     int
+	ExpiresAt time.Time
+}
+
+// WhoAmI returns profile of the user of the token, ErrUnauthenticated is returned for invalid or revoked token.
+func (c *Client) WhoAmI(ctx context.Context, token string) (Profile, error) {
+	var profile Profile
+
+	err := c.call(ctx, func(ctx context.Context) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		resp, err := c.auth.WhoAmI(ctx, &ssov1.WhoAmIRequest{})
+		if err != nil {
+			return err
+		}
+
+		orgs := make(map[int64]string, len(resp.GetOrganizations()))
+		for _, org := range resp.GetOrganizations() {
+			orgs[org.GetOrgId()] = org.GetRole()
+		}
+
+		profile = Profile{
+			UserID:    resp.GetUserId(),
+			Email:     resp.GetEmail(),
+			Verified:  resp.GetVerified(),
+			IsAdmin:   resp.GetIsAdmin(),
+			Orgs:      orgs,
+			SessionID: resp.GetSession().GetId(),
+			AppID:     int(resp.GetSession().GetAppId()),
+			ExpiresAt: resp.GetSession().GetExpiresAt().AsTime(),
+		}
+
+		return nil
+	})
+
+	return profile, err
+}
+
+func (c *Client) createVerification(ctx context.Context, email string, vType ssov1.VerificationType) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		_, err := c.auth.CreateVerification(ctx, &ssov1.CreateVerificationRequest{Email: email, Type: vType})
+
+		return err
+	})
+}
+
+// call makes attempts of the call with timeout each, retrying while the service is unavailable,
+// unless it's under maintenance.
+// The error of the last attempt is translated to errors of this package.
+func (c *Client) call(ctx context.Context, attempt func(ctx context.Context) error) error {
+	backoff := c.cfg.RetryBackoff
+
+	for retry := 0; ; retry++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		err := attempt(attemptCtx)
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+
+		// Maintenance windows outlast retries, so they are not retried.
+		if status.Code(err) != codes.Unavailable || retry >= c.cfg.Retries || underMaintenance(err) {
+			return translateError(err)
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return translateError(err)
+		}
+	}
+}
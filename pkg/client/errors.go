@@ -0,0 +1,125 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrUserExists         = errors.New("user already exists")
+	ErrEmailNotVerified   = errors.New("email is not verified")
+	// ErrSignInNotConfirmed is returned by Login from a new device until it's confirmed by the code emailed to the user.
+	ErrSignInNotConfirmed = errors.New("sign-in from new device is not confirmed")
+	// ErrSignInBlocked is returned by Login scored too risky by SSO, e.g. after impossible travel.
+	ErrSignInBlocked = errors.New("sign-in is blocked as suspicious")
+	// ErrLoginRequired is returned by Authorize when SSO session is over, the user must log in with credentials.
+	ErrLoginRequired   = errors.New("login is required")
+	ErrInvalidCode     = errors.New("code is invalid or expired")
+	ErrNotFound        = errors.New("not found")
+	ErrUnauthenticated = errors.New("token is invalid, expired or revoked")
+	// ErrRateLimited is returned while calls are throttled, e.g. after repeated failed logins.
+	ErrRateLimited = errors.New("too many requests")
+	// ErrWeakPassword is returned if the password is weaker than the policy of SSO requires,
+	// CheckPasswordStrength tells why.
+	ErrWeakPassword = errors.New("password is too weak")
+	// ErrMaintenance is returned while SSO is under maintenance, the status has RetryInfo with time left until
+	// the window ends, if it's known.
+	ErrMaintenance = errors.New("service is under maintenance")
+)
+
+// Reasons of errors in google.rpc.ErrorInfo details sent by SSO.
+const (
+	reasonEmailNotVerified   = "EMAIL_NOT_VERIFIED"
+	reasonSignInNotConfirmed = "SIGN_IN_NOT_CONFIRMED"
+	reasonSignInCodeInvalid  = "SIGN_IN_CODE_INVALID"
+	reasonSignInBlocked      = "SIGN_IN_BLOCKED"
+	reasonLoginRequired      = "LOGIN_REQUIRED"
+	reasonPasswordTooWeak    = "PASSWORD_TOO_WEAK"
+	reasonMaintenance        = "MAINTENANCE"
+)
+
+// translateError wraps errors of this package around gRPC status error, so both errors.Is
+// and status.Code work on the result.
+func translateError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var target error
+
+	switch reason(st) {
+	case reasonEmailNotVerified:
+		target = ErrEmailNotVerified
+	case reasonSignInNotConfirmed:
+		target = ErrSignInNotConfirmed
+	case reasonSignInCodeInvalid:
+		target = ErrInvalidCode
+	case reasonSignInBlocked:
+		target = ErrSignInBlocked
+	case reasonLoginRequired:
+		target = ErrLoginRequired
+	case reasonPasswordTooWeak:
+		target = ErrWeakPassword
+	case reasonMaintenance:
+		target = ErrMaintenance
+	}
+
+	if target == nil {
+		switch st.Code() {
+		case codes.AlreadyExists:
+			target = ErrUserExists
+		case codes.NotFound:
+			target = ErrNotFound
+			if st.Message() == "verification not found" {
+				target = ErrInvalidCode
+			}
+		case codes.Unauthenticated:
+			target = ErrUnauthenticated
+		case codes.ResourceExhausted:
+			target = ErrRateLimited
+		case codes.PermissionDenied:
+			// Captcha failures are PermissionDenied too, but they have reasons.
+			if reason(st) == "" {
+				target = ErrInvalidCode
+			}
+		case codes.InvalidArgument:
+			if st.Message() == "invalid email or password" {
+				target = ErrInvalidCredentials
+			}
+		case codes.Internal:
+			if st.Message() == "verification expired" {
+				target = ErrInvalidCode
+			}
+		}
+	}
+
+	if target == nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", target, err)
+}
+
+// reason returns reason of ErrorInfo details of the status, empty string if there are none.
+func reason(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+
+	return ""
+}
+
+// underMaintenance reports whether the error is rejection of a call during maintenance.
+func underMaintenance(err error) bool {
+	st, ok := status.FromError(err)
+
+	return ok && reason(st) == reasonMaintenance
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// refreshBefore is how long before expiration cached token is replaced, so it doesn't expire in flight.
+const refreshBefore = 30 * time.Second
+
+// TokenSource caches access token of a user, e.g. service account, and logs in again shortly before it expires.
+// It's safe for concurrent use.
+type TokenSource struct {
+	client   *Client
+	email    string
+	password string
+	appID    int
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// TokenSource returns TokenSource logging in to the app as the user.
+func (c *Client) TokenSource(email string, password string, appID int) *TokenSource {
+	return &TokenSource{
+		client:   c,
+		email:    email,
+		password: password,
+		appID:    appID,
+	}
+}
+
+// Token returns cached token, or logs in if there is none or it's about to expire.
+func (ts *TokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != "" && time.Until(ts.expiresAt) > refreshBefore {
+		return ts.token, nil
+	}
+
+	token, err := ts.client.Login(ctx, ts.email, ts.password, ts.appID)
+	if err != nil {
+		return "", err
+	}
+
+	ts.token, ts.expiresAt = token, expiresAt(token)
+
+	return token, nil
+}
+
+// Invalidate drops cached token, e.g. after a call fails with ErrUnauthenticated since the token is revoked.
+func (ts *TokenSource) Invalidate() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.token = ""
+}
+
+// expiresAt reads expiration of the token without verifying it, the client has no app secret to verify it with.
+// Zero time is returned if it can't be read, so the token isn't cached.
+func expiresAt(token string) time.Time {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return time.Time{}
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return time.Time{}
+	}
+
+	return exp.Time
+}
@@ -0,0 +1,135 @@
+package tokenverify
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey is returned for token signed by a key missing in the key set of SSO.
+var ErrUnknownKey = errors.New("token is signed by unknown key")
+
+const (
+	defaultJWKSCacheTTL = time.Hour
+	// minRefetchInterval limits fetches on unknown kid, so tokens with made-up kids don't flood SSO.
+	minRefetchInterval = 30 * time.Second
+	// maxJWKSSize limits the key set, it has a key or two.
+	maxJWKSSize = 64 << 10
+)
+
+// jwk is P-256 public key of the key set, other keys are skipped.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+}
+
+// keySet caches public keys fetched from JWKS endpoint of SSO. Keys are fetched on first use, again once
+// they are older than ttl or the token has unknown kid, e.g. after the signing key was rotated.
+type keySet struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*ecdsa.PublicKey
+	fetchedAt time.Time
+}
+
+// key returns public key of the kid. Cached keys are used while SSO is unavailable.
+func (s *keySet) key(kid string) (*ecdsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key, ok := s.keys[kid]
+	if ok && now.Sub(s.fetchedAt) < s.ttl {
+		return key, nil
+	}
+
+	if !s.fetchedAt.IsZero() && now.Sub(s.fetchedAt) < minRefetchInterval {
+		if ok {
+			return key, nil
+		}
+
+		return nil, ErrUnknownKey
+	}
+
+	keys, err := s.fetch()
+	if err != nil {
+		if ok {
+			return key, nil
+		}
+
+		return nil, err
+	}
+	s.keys, s.fetchedAt = keys, now
+
+	if key, ok = keys[kid]; !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+func (s *keySet) fetch() (map[string]*ecdsa.PublicKey, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("tokenverify: failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tokenverify: failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("tokenverify: failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*ecdsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (*ecdsa.PublicKey, error) {
+	if k.Kty != "EC" || k.Crv != "P-256" {
+		return nil, errors.New("jwk must be P-256 EC key")
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !key.Curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("jwk is not on the curve")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,149 @@
+package tokenverify
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJWKS serves public keys like /.well-known/jwks.json of SSO and counts fetches.
+type testJWKS struct {
+	mu      sync.Mutex
+	keys    map[string]*ecdsa.PrivateKey
+	fetches int
+	down    bool
+}
+
+func (s *testJWKS) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetches++
+	if s.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	for kid, key := range s.keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "EC",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+			Kid: kid,
+		})
+	}
+	_ = json.NewEncoder(w).Encode(set)
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
+
+func signES256(t *testing.T, claims jwt.MapClaims, key *ecdsa.PrivateKey, kid string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestVerifyJWKS(t *testing.T) {
+	key := newKey(t)
+	jwks := &testJWKS{keys: map[string]*ecdsa.PrivateKey{"key-1": key}}
+	srv := httptest.NewServer(jwks)
+	t.Cleanup(srv.Close)
+
+	now := testNow
+	v, err := New(Config{AppID: testAppID, JWKSURL: srv.URL, Now: func() time.Time { return now }})
+	require.NoError(t, err)
+
+	claims, err := v.Verify(signES256(t, testClaims(), key, "key-1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), claims.UserID)
+
+	_, err = v.Verify(signES256(t, testClaims(), key, "key-1"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, jwks.fetches, "keys are cached")
+
+	_, err = v.Verify(signES256(t, testClaims(), newKey(t), "key-1"))
+	assert.ErrorIs(t, err, ErrInvalidToken, "signed by another key")
+
+	_, err = v.Verify(signHS256(t, testClaims(), testSecret))
+	assert.ErrorIs(t, err, ErrInvalidToken, "HS256 isn't accepted without secret")
+
+	_, err = v.Verify(signES256(t, testClaims(), key, "key-2"))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, 1, jwks.fetches, "unknown kid doesn't refetch keys fetched just now")
+
+	// Signing key is rotated.
+	rotated := newKey(t)
+	jwks.mu.Lock()
+	jwks.keys["key-2"] = rotated
+	jwks.mu.Unlock()
+	now = now.Add(minRefetchInterval)
+
+	_, err = v.Verify(signES256(t, testClaims(), rotated, "key-2"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, jwks.fetches)
+
+	// Cached keys outlive unavailable SSO.
+	jwks.mu.Lock()
+	jwks.down = true
+	jwks.mu.Unlock()
+	now = now.Add(defaultJWKSCacheTTL)
+
+	later := testClaims()
+	later["exp"] = now.Add(time.Hour).Unix()
+	_, err = v.Verify(signES256(t, later, rotated, "key-2"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, jwks.fetches)
+}
+
+func TestVerifyJWKSAndSecret(t *testing.T) {
+	key := newKey(t)
+	srv := httptest.NewServer(&testJWKS{keys: map[string]*ecdsa.PrivateKey{"key-1": key}})
+	t.Cleanup(srv.Close)
+
+	v := newTestVerifier(t, Config{JWKSURL: srv.URL})
+
+	_, err := v.Verify(signES256(t, testClaims(), key, "key-1"))
+	require.NoError(t, err)
+
+	_, err = v.Verify(signHS256(t, testClaims(), testSecret))
+	require.NoError(t, err, "tokens signed before SSO got the key are accepted while the secret is set")
+}
+
+func TestVerifyJWKSUnavailable(t *testing.T) {
+	srv := httptest.NewServer(&testJWKS{down: true})
+	t.Cleanup(srv.Close)
+
+	v, err := New(Config{AppID: testAppID, JWKSURL: srv.URL, Now: func() time.Time { return testNow }})
+	require.NoError(t, err)
+
+	_, err = v.Verify(signES256(t, testClaims(), newKey(t), "key-1"))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,123 @@
+package tokenverify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMiddleware(t *testing.T) {
+	v := newTestVerifier(t, Config{})
+	valid := signHS256(t, testClaims(), testSecret)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{"valid", "Bearer " + valid, http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"not bearer", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"invalid", "Bearer " + signHS256(t, testClaims(), "other-secret"), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Claims
+			handler := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var ok bool
+				got, ok = ClaimsFromContext(r.Context())
+				require.True(t, ok)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/api/", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, int64(42), got.UserID)
+			} else {
+				assert.Equal(t, `Bearer error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := newTestVerifier(t, Config{}).UnaryServerInterceptor()
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want codes.Code
+	}{
+		{"valid", metadata.Pairs("authorization", "Bearer "+signHS256(t, testClaims(), testSecret)), codes.OK},
+		{"no metadata", nil, codes.Unauthenticated},
+		{"missing", metadata.MD{}, codes.Unauthenticated},
+		{"invalid", metadata.Pairs("authorization", "Bearer "+signHS256(t, testClaims(), "other-secret")), codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			called := false
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+				called = true
+				claims, ok := ClaimsFromContext(ctx)
+				require.True(t, ok)
+				assert.Equal(t, int64(42), claims.UserID)
+
+				return nil, nil
+			})
+
+			assert.Equal(t, tt.want, status.Code(err))
+			assert.Equal(t, tt.want == codes.OK, called)
+		})
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := newTestVerifier(t, Config{}).StreamServerInterceptor()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+signHS256(t, testClaims(), testSecret)))
+
+	err := interceptor(nil, testServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+		claims, ok := ClaimsFromContext(ss.Context())
+		require.True(t, ok)
+		assert.Equal(t, int64(42), claims.UserID)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = interceptor(nil, testServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(any, grpc.ServerStream) error {
+		t.Fatal("handler must not be called")
+
+		return nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,224 @@
+package tokenverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoToken      = errors.New("token is missing")
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrWrongApp is returned for valid token issued for another app.
+	ErrWrongApp = errors.New("token is issued for another app")
+	// ErrWrongAudience is returned for valid token of another issuer or audience, e.g. of a staging SSO.
+	ErrWrongAudience = errors.New("token is issued by another issuer or for another audience")
+)
+
+// Claims are claims of the access token issued by SSO.
+type Claims struct {
+	// ID is jti claim, it's empty for tokens issued by old versions of SSO.
+	ID        string
+	UserID    int64
+	Email     string
+	AppID     int
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// Elevated is set for one-time tokens issued for sensitive actions, they must be introspected by SSO
+	// to be consumed, so they are rejected by Verifier unless AllowElevated is set.
+	Elevated bool
+	// Orgs are roles of the user by organization ID, as of when the token was issued.
+	Orgs map[int64]string
+	// ServiceAccountID is set instead of UserID and Email for tokens of service accounts.
+	ServiceAccountID int64
+	// Scopes are granted to the service account's token.
+	Scopes []string
+	// OrgID is organization the service account acts for, 0 if none.
+	OrgID int64
+	// Issuer and Audience are iss and aud claims, they are empty unless SSO is configured to issue them.
+	Issuer   string
+	Audience []string
+}
+
+// Config configures Verifier.
+type Config struct {
+	// AppID is ID of the app the tokens are issued for.
+	AppID int
+	// Secret is secret of the app tokens are signed with by HS256.
+	Secret string
+	// JWKSURL is key set of SSO signing tokens by ES256, e.g. https://sso.example.com/.well-known/jwks.json,
+	// see token_signing of SSO config. Tokens signed by the secret are accepted only if Secret is set too.
+	JWKSURL string
+	// JWKSCacheTTL is how long fetched keys are used before the key set is fetched again, 1 hour by default.
+	JWKSCacheTTL time.Duration
+	// HTTPClient fetches the key set, a client with 10 seconds timeout by default.
+	HTTPClient *http.Client
+	// Issuer and Audience, if set, must be iss and aud claims of the token, see token_claims of SSO config.
+	Issuer   string
+	Audience string
+	// Leeway is allowed clock skew between SSO and the service.
+	Leeway time.Duration
+	// AllowElevated makes elevated tokens valid too.
+	AllowElevated bool
+	// AllowServiceAccounts makes tokens of service accounts valid too, the service checks their scopes.
+	AllowServiceAccounts bool
+	// Now returns current time, time.Now by default.
+	Now func() time.Time
+}
+
+// Verifier validates access tokens issued by SSO for an app locally, without calling SSO.
+//
+// SSO signs tokens by ES256 with its key if it's configured, the public key is fetched from JWKSURL.
+// Otherwise tokens are signed by HS256 with the secret of the app, the app verifies them with the same secret.
+// Local verification doesn't see revoked tokens, i.e. logged out sessions, until they expire,
+// call IntrospectToken of SSO where that matters.
+type Verifier struct {
+	cfg    Config
+	parser *jwt.Parser
+	// keys is nil if JWKSURL is not set.
+	keys *keySet
+}
+
+// New returns Verifier, secret or JWKS URL is required.
+func New(cfg Config) (*Verifier, error) {
+	if cfg.Secret == "" && cfg.JWKSURL == "" {
+		return nil, errors.New("tokenverify: secret or JWKS URL is required")
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	v := &Verifier{cfg: cfg}
+
+	var methods []string
+	if cfg.Secret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSURL != "" {
+		methods = append(methods, jwt.SigningMethodES256.Alg())
+
+		v.keys = &keySet{url: cfg.JWKSURL, client: cfg.HTTPClient, ttl: cfg.JWKSCacheTTL, now: cfg.Now}
+		if v.keys.client == nil {
+			v.keys.client = &http.Client{Timeout: 10 * time.Second}
+		}
+		if v.keys.ttl == 0 {
+			v.keys.ttl = defaultJWKSCacheTTL
+		}
+	}
+
+	v.parser = jwt.NewParser(
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithTimeFunc(cfg.Now),
+	)
+
+	return v, nil
+}
+
+// Verify checks signature, expiration, app, issuer and audience of the token and returns its claims.
+func (v *Verifier) Verify(token string) (Claims, error) {
+	if token == "" {
+		return Claims{}, ErrNoToken
+	}
+
+	mapClaims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, mapClaims, func(token *jwt.Token) (any, error) {
+		if token.Method.Alg() != jwt.SigningMethodES256.Alg() {
+			return []byte(v.cfg.Secret), nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		return v.keys.key(kid)
+	}); err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	claims := claimsOf(mapClaims)
+
+	// Expiration is validated by the parser only if it's set, SSO never issues tokens without it.
+	if claims.ExpiresAt.IsZero() {
+		return Claims{}, fmt.Errorf("%w: exp claim is missing", ErrInvalidToken)
+	}
+	if claims.AppID != v.cfg.AppID {
+		return Claims{}, ErrWrongApp
+	}
+	if v.cfg.Issuer != "" && claims.Issuer != v.cfg.Issuer {
+		return Claims{}, ErrWrongAudience
+	}
+	if v.cfg.Audience != "" && !slices.Contains(claims.Audience, v.cfg.Audience) {
+		return Claims{}, ErrWrongAudience
+	}
+	if claims.ServiceAccountID != 0 {
+		if !v.cfg.AllowServiceAccounts {
+			return Claims{}, fmt.Errorf("%w: token of service account", ErrInvalidToken)
+		}
+	} else if claims.UserID == 0 {
+		return Claims{}, fmt.Errorf("%w: uid claim is missing", ErrInvalidToken)
+	}
+	if claims.Elevated && !v.cfg.AllowElevated {
+		return Claims{}, fmt.Errorf("%w: elevated token must be introspected", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+func claimsOf(mapClaims jwt.MapClaims) Claims {
+	uid, _ := mapClaims["uid"].(float64)
+	appID, _ := mapClaims["app_id"].(float64)
+	email, _ := mapClaims["email"].(string)
+	jti, _ := mapClaims["jti"].(string)
+	elevated, _ := mapClaims["elv"].(bool)
+	saID, _ := mapClaims["sa_id"].(float64)
+	scope, _ := mapClaims["scope"].(string)
+	orgID, _ := mapClaims["org_id"].(float64)
+
+	claims := Claims{
+		ID: jti, UserID: int64(uid), Email: email, AppID: int(appID), Elevated: elevated,
+		ServiceAccountID: int64(saID), Scopes: strings.Fields(scope), OrgID: int64(orgID),
+	}
+
+	if iat, err := mapClaims.GetIssuedAt(); err == nil && iat != nil {
+		claims.IssuedAt = iat.Time
+	}
+	if orgs, ok := mapClaims["orgs"].(map[string]any); ok && len(orgs) > 0 {
+		claims.Orgs = make(map[int64]string, len(orgs))
+		for id, role := range orgs {
+			orgID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				continue
+			}
+			if role, ok := role.(string); ok {
+				claims.Orgs[orgID] = role
+			}
+		}
+	}
+	if exp, err := mapClaims.GetExpirationTime(); err == nil && exp != nil {
+		claims.ExpiresAt = exp.Time
+	}
+	claims.Issuer, _ = mapClaims.GetIssuer()
+	claims.Audience, _ = mapClaims.GetAudience()
+
+	return claims
+}
+
+type claimsKey struct{}
+
+// WithClaims returns context carrying claims.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns claims put to context by middleware, false if there are none.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+
+	return claims, ok
+}
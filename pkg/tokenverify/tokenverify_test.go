@@ -0,0 +1,259 @@
+package tokenverify
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAppID  = 1
+	testSecret = "test-secret"
+)
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// testClaims are claims of a valid user token issued at testNow.
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"jti":    "token-id",
+		"uid":    float64(42),
+		"email":  "user@example.com",
+		"app_id": float64(testAppID),
+		"iat":    testNow.Unix(),
+		"exp":    testNow.Add(time.Hour).Unix(),
+		"iss":    "https://sso.example.com",
+		"aud":    "api.example.com",
+	}
+}
+
+func signHS256(t *testing.T, claims jwt.MapClaims, secret string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return token
+}
+
+func newTestVerifier(t *testing.T, cfg Config) *Verifier {
+	t.Helper()
+
+	cfg.AppID, cfg.Secret = testAppID, testSecret
+	if cfg.Now == nil {
+		cfg.Now = func() time.Time { return testNow }
+	}
+
+	v, err := New(cfg)
+	require.NoError(t, err)
+
+	return v
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{AppID: testAppID})
+	assert.Error(t, err, "secret or JWKS URL is required")
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	with := func(changes map[string]any) jwt.MapClaims {
+		claims := testClaims()
+		for k, v := range changes {
+			if v == nil {
+				delete(claims, k)
+			} else {
+				claims[k] = v
+			}
+		}
+
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		token   func(t *testing.T) string
+		wantErr error
+		check   func(t *testing.T, claims Claims)
+	}{
+		{
+			name:  "valid",
+			token: func(t *testing.T) string { return signHS256(t, testClaims(), testSecret) },
+			check: func(t *testing.T, claims Claims) {
+				assert.Equal(t, "token-id", claims.ID)
+				assert.Equal(t, int64(42), claims.UserID)
+				assert.Equal(t, "user@example.com", claims.Email)
+				assert.Equal(t, testAppID, claims.AppID)
+				assert.Equal(t, testNow, claims.IssuedAt.UTC())
+				assert.Equal(t, testNow.Add(time.Hour), claims.ExpiresAt.UTC())
+			},
+		},
+		{
+			name:    "empty",
+			token:   func(*testing.T) string { return "" },
+			wantErr: ErrNoToken,
+		},
+		{
+			name:    "garbage",
+			token:   func(*testing.T) string { return "not.a.token" },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "wrong secret",
+			token:   func(t *testing.T) string { return signHS256(t, testClaims(), "other-secret") },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "alg none",
+			token: func(t *testing.T) string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+				require.NoError(t, err)
+
+				return token
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "alg RS256",
+			token: func(t *testing.T) string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims()).SignedString(rsaKey)
+				require.NoError(t, err)
+
+				return token
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"exp": testNow.Add(-time.Second).Unix()}), testSecret)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "missing exp",
+			token:   func(t *testing.T) string { return signHS256(t, with(map[string]any{"exp": nil}), testSecret) },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "expired within leeway",
+			cfg:  Config{Leeway: 5 * time.Second},
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"exp": testNow.Add(-3 * time.Second).Unix()}), testSecret)
+			},
+		},
+		{
+			name: "expired beyond leeway",
+			cfg:  Config{Leeway: 5 * time.Second},
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"exp": testNow.Add(-6 * time.Second).Unix()}), testSecret)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "wrong app",
+			token:   func(t *testing.T) string { return signHS256(t, with(map[string]any{"app_id": float64(2)}), testSecret) },
+			wantErr: ErrWrongApp,
+		},
+		{
+			name: "wrong issuer",
+			cfg:  Config{Issuer: "https://sso.example.com"},
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"iss": "https://staging.example.com"}), testSecret)
+			},
+			wantErr: ErrWrongAudience,
+		},
+		{
+			name:    "missing issuer",
+			cfg:     Config{Issuer: "https://sso.example.com"},
+			token:   func(t *testing.T) string { return signHS256(t, with(map[string]any{"iss": nil}), testSecret) },
+			wantErr: ErrWrongAudience,
+		},
+		{
+			name: "wrong audience",
+			cfg:  Config{Audience: "api.example.com"},
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"aud": "other.example.com"}), testSecret)
+			},
+			wantErr: ErrWrongAudience,
+		},
+		{
+			name: "one of audiences",
+			cfg:  Config{Issuer: "https://sso.example.com", Audience: "api.example.com"},
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"aud": []string{"web", "api.example.com"}}), testSecret)
+			},
+		},
+		{
+			name:    "missing uid",
+			token:   func(t *testing.T) string { return signHS256(t, with(map[string]any{"uid": nil}), testSecret) },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "elevated",
+			token:   func(t *testing.T) string { return signHS256(t, with(map[string]any{"elv": true}), testSecret) },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:  "elevated allowed",
+			cfg:   Config{AllowElevated: true},
+			token: func(t *testing.T) string { return signHS256(t, with(map[string]any{"elv": true}), testSecret) },
+			check: func(t *testing.T, claims Claims) {
+				assert.True(t, claims.Elevated)
+			},
+		},
+		{
+			name: "service account",
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"uid": nil, "email": nil, "sa_id": float64(7)}), testSecret)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "service account allowed",
+			cfg:  Config{AllowServiceAccounts: true},
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"uid": nil, "email": nil, "sa_id": float64(7), "scope": "read write", "org_id": float64(3)}), testSecret)
+			},
+			check: func(t *testing.T, claims Claims) {
+				assert.Equal(t, int64(7), claims.ServiceAccountID)
+				assert.Zero(t, claims.UserID)
+				assert.Equal(t, []string{"read", "write"}, claims.Scopes)
+				assert.Equal(t, int64(3), claims.OrgID)
+			},
+		},
+		{
+			name: "orgs",
+			token: func(t *testing.T) string {
+				return signHS256(t, with(map[string]any{"orgs": map[string]any{"3": "owner", "x": "member"}}), testSecret)
+			},
+			check: func(t *testing.T, claims Claims) {
+				assert.Equal(t, map[int64]string{3: "owner"}, claims.Orgs, "malformed org IDs are skipped")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := newTestVerifier(t, tt.cfg).Verify(tt.token(t))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.check != nil {
+				tt.check(t, claims)
+			}
+		})
+	}
+}
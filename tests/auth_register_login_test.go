@@ -59,6 +59,7 @@ func TestRegisterLogin_Login_HappyPath(t *testing.T) {
 	assert.Equal(t, respReg.GetUserId(), int64(claims["uid"].(float64)))
 	assert.Equal(t, email, claims["email"].(string))
 	assert.Equal(t, appID, int(claims["app_id"].(float64)))
+	assert.NotEmpty(t, claims["jti"])
 
 	const deltaSeconds = 1
 
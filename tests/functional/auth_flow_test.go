@@ -0,0 +1,105 @@
+package functional
+
+import (
+	"context"
+
+	"grpc-service-ref/internal/lib/jwt"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	email       = "user@example.com"
+	password    = "first-password"
+	newPassword = "second-password"
+)
+
+func (s *Suite) TestRegisterVerifyLoginReset() {
+	ctx := context.Background()
+
+	reg, err := s.Client.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password})
+	s.Require().NoError(err)
+	s.NotZero(reg.GetUserId())
+
+	_, err = s.Client.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	s.Equal(codes.FailedPrecondition, status.Code(err), "unverified user must not log in")
+
+	_, err = s.Client.VerifyMail(ctx, &ssov1.VerifyMailRequest{Email: email, Code: s.LastCode(email)})
+	s.Require().NoError(err)
+
+	login, err := s.Client.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	s.Require().NoError(err)
+
+	claims, err := jwt.ParseToken(login.GetToken(), s.Clock.Now(), nil, func(int) (string, error) { return appSecret, nil })
+	s.Require().NoError(err)
+	s.Equal(reg.GetUserId(), claims.UserID)
+	s.Equal(s.Clock.Now().Add(tokenTTL).Unix(), claims.ExpiresAt.Unix())
+
+	_, err = s.Client.CreateVerification(ctx, &ssov1.CreateVerificationRequest{
+		Email: email,
+		Type:  ssov1.VerificationType_VERIFICATION_TYPE_PASSWORD_RESET,
+	})
+	s.Require().NoError(err)
+
+	s.Notifier.EXPECT().PasswordChanged(mock.Anything, email, true, true).Once()
+
+	_, err = s.Client.ResetPassword(ctx, &ssov1.ResetPasswordRequest{Email: email, Code: s.LastCode(email), NewPassword: newPassword})
+	s.Require().NoError(err)
+
+	_, err = s.Client.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	s.Equal(codes.InvalidArgument, status.Code(err), "old password must not work after reset")
+
+	_, err = s.Client.Login(ctx, &ssov1.LoginRequest{Email: email, Password: newPassword, AppId: appID})
+	s.NoError(err)
+}
+
+func (s *Suite) TestRegister_DuplicatedEmail() {
+	ctx := context.Background()
+
+	_, err := s.Client.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password})
+	s.Require().NoError(err)
+
+	_, err = s.Client.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password})
+	s.Equal(codes.AlreadyExists, status.Code(err))
+}
+
+func (s *Suite) TestVerifyMail_WrongCode() {
+	ctx := context.Background()
+
+	_, err := s.Client.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password})
+	s.Require().NoError(err)
+
+	_, err = s.Client.VerifyMail(ctx, &ssov1.VerifyMailRequest{Email: email, Code: "not-a-code"})
+	s.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func (s *Suite) TestVerifyMail_ExpiredCode() {
+	ctx := context.Background()
+
+	_, err := s.Client.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password})
+	s.Require().NoError(err)
+
+	s.Clock.Advance(codeTTL + 1)
+
+	_, err = s.Client.VerifyMail(ctx, &ssov1.VerifyMailRequest{Email: email, Code: s.LastCode(email)})
+	s.Error(err, "expired code must be rejected")
+}
+
+func (s *Suite) TestResetPassword_SamePassword() {
+	ctx := context.Background()
+
+	_, err := s.Client.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password})
+	s.Require().NoError(err)
+
+	_, err = s.Client.CreateVerification(ctx, &ssov1.CreateVerificationRequest{
+		Email: email,
+		Type:  ssov1.VerificationType_VERIFICATION_TYPE_PASSWORD_RESET,
+	})
+	s.Require().NoError(err)
+
+	_, err = s.Client.ResetPassword(ctx, &ssov1.ResetPasswordRequest{Email: email, Code: s.LastCode(email), NewPassword: password})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
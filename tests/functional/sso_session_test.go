@@ -0,0 +1,55 @@
+package functional
+
+import (
+	"context"
+
+	"grpc-service-ref/internal/services/auth"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Suite) TestAuthorize_SSOSession() {
+	ctx := context.Background()
+
+	token := s.registerAndLogin()
+
+	otherAppID, err := s.storage.SaveApp(ctx, "other", "other-secret")
+	s.Require().NoError(err)
+
+	authorized, err := s.Client.Authorize(bearer(ctx, token), &ssov1.AuthorizeRequest{AppId: int32(otherAppID)})
+	s.Require().NoError(err)
+	s.NotEmpty(authorized.GetToken())
+
+	_, err = s.Client.Authorize(bearer(ctx, token), &ssov1.AuthorizeRequest{AppId: int32(otherAppID), Prompt: auth.PromptLogin})
+	s.Equal(codes.FailedPrecondition, status.Code(err), "prompt=login must require credentials")
+
+	// Logout from the other app ends SSO session, so tokens of every app issued within it are revoked.
+	_, err = s.Client.Logout(bearer(ctx, authorized.GetToken()), &ssov1.LogoutRequest{})
+	s.Require().NoError(err)
+
+	_, err = s.Client.Authorize(bearer(ctx, token), &ssov1.AuthorizeRequest{AppId: int32(otherAppID)})
+	s.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+// registerAndLogin registers verified user and returns its token for the app.
+func (s *Suite) registerAndLogin() string {
+	ctx := context.Background()
+
+	_, err := s.Client.Register(ctx, &ssov1.RegisterRequest{Email: email, Password: password})
+	s.Require().NoError(err)
+
+	_, err = s.Client.VerifyMail(ctx, &ssov1.VerifyMailRequest{Email: email, Code: s.LastCode(email)})
+	s.Require().NoError(err)
+
+	login, err := s.Client.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	s.Require().NoError(err)
+
+	return login.GetToken()
+}
+
+func bearer(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
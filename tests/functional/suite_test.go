@@ -0,0 +1,214 @@
+package functional
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	mathrand "math/rand"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"grpc-service-ref/internal/events"
+	authgrpc "grpc-service-ref/internal/grpc/auth"
+	grpcmocks "grpc-service-ref/internal/grpc/auth/mocks"
+	"grpc-service-ref/internal/lib/clock"
+	"grpc-service-ref/internal/lib/passstrength"
+	"grpc-service-ref/internal/lib/random"
+	"grpc-service-ref/internal/lib/verification"
+	"grpc-service-ref/internal/services/audit"
+	"grpc-service-ref/internal/services/auth"
+	authmocks "grpc-service-ref/internal/services/auth/mocks"
+	verificationService "grpc-service-ref/internal/services/verification"
+	"grpc-service-ref/internal/storage/sqlite"
+
+	ssov1 "github.com/VanGoghDev/protos/gen/go/sso"
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	migrationsPath = "../../migrations"
+
+	// appID and appSecret are of the app created by migrations.
+	appID         = 1
+	appSecret     = "test-secret"
+	tokenTTL      = time.Hour
+	ssoSessionTTL = 12 * time.Hour
+	refreshTTL    = 30 * 24 * time.Hour
+	codeTTL       = 15 * time.Minute
+)
+
+// Suite runs Auth gRPC server in process against in-memory storage. Emails are captured by a mock,
+// time and randomness are controlled by the suite, so every test starts from the same state
+// and doesn't need a running instance.
+type Suite struct {
+	suite.Suite
+
+	Client ssov1.AuthClient
+	Clock  *clock.Fake
+	// Notifier expects no notifications unless a test sets them up.
+	Notifier *authmocks.Notifier
+
+	storage *sqlite.Storage
+	// migrator holds a connection to the in-memory db, which lives while any connection is open.
+	migrator *migrate.Migrate
+	server   *grpc.Server
+	conn     *grpc.ClientConn
+
+	mu sync.Mutex
+	// codes are the last codes emailed by recipient.
+	codes map[string]string
+}
+
+func TestFunctional(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+// epoch is the time every test starts at.
+var epoch = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *Suite) SetupTest() {
+	ctx := context.Background()
+
+	// Shared cache makes connections see the same in-memory db, names of tests isolate their dbs.
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(s.T().Name(), "/", "_"))
+
+	s.migrator = s.migrate(dsn)
+
+	storage, err := sqlite.New(dsn, nil)
+	s.Require().NoError(err)
+	s.storage = storage
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s.Clock = clock.NewFake(epoch)
+	// Seeded source makes codes and token IDs the same on every run, it's used by one RPC at a time.
+	rnd := random.New(mathrand.New(mathrand.NewSource(1)))
+	s.codes = make(map[string]string)
+
+	s.Notifier = authmocks.NewNotifier(s.T())
+
+	auditService := audit.New(log, storage, storage)
+	bus := events.New(log)
+	auditService.Subscribe(bus)
+
+	authService := auth.New(log, auth.Deps{
+		UserSaver:     storage,
+		UserProvider:  storage,
+		AppProvider:   storage,
+		PendingSaver:  storage,
+		Auditor:       auditService,
+		Events:        bus,
+		Notifier:      s.Notifier,
+		Sessions:      storage,
+		RefreshTokens: storage,
+		Orgs:          storage,
+		Deletions:     storage,
+		Preferences:   storage,
+		Bans:          storage,
+		Clock:         s.Clock,
+		Random:        rnd,
+	}, auth.Config{
+		TokenTTL:         tokenTTL,
+		ElevatedTokenTTL: 5 * time.Minute,
+		SSOSessionTTL:    ssoSessionTTL,
+		RefreshTokenTTL:  refreshTTL,
+		PasswordCost:     bcrypt.MinCost,
+		RevokeSessions:   true,
+		RequireVerified:  true,
+	})
+	verifications := verificationService.New(log, storage, storage, storage, storage, storage, storage, bus, storage, s.Clock, 5)
+
+	emails := grpcmocks.NewEmailSender(s.T())
+	emails.EXPECT().
+		SendEmail(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(s.captureEmail).
+		Maybe()
+
+	codes := verification.CodeFormats{
+		Default: verification.CodeFormat{Len: 6, Charset: verification.CharsetDigits, TTL: codeTTL},
+	}
+
+	s.server = grpc.NewServer()
+	authgrpc.Register(s.server, authgrpc.Deps{
+		Auth:              authService,
+		EmailService:      emails,
+		Verification:      verifications,
+		VerificationCodes: codes,
+		PasswordPolicy:    passstrength.Policy{},
+		Clock:             s.Clock,
+		Random:            rnd,
+	})
+
+	lis := bufconn.Listen(1 << 20)
+	go s.server.Serve(lis)
+
+	s.conn, err = grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	s.Require().NoError(err)
+
+	s.Client = ssov1.NewAuthClient(s.conn)
+}
+
+func (s *Suite) TearDownTest() {
+	s.conn.Close()
+	s.server.Stop()
+	s.storage.Stop()
+	// Closes the last connection, so the in-memory db is dropped.
+	s.migrator.Close()
+}
+
+// migrate creates schema of the in-memory db.
+func (s *Suite) migrate(dsn string) *migrate.Migrate {
+	db, err := sql.Open("sqlite3", dsn)
+	s.Require().NoError(err)
+
+	driver, err := migratesqlite.WithInstance(db, &migratesqlite.Config{})
+	s.Require().NoError(err)
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+migrationsPath, "sqlite3", driver)
+	s.Require().NoError(err)
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		s.Require().NoError(err)
+	}
+
+	return m
+}
+
+// captureEmail remembers the code sent by email, verification emails have the code as content.
+func (s *Suite) captureEmail(_ context.Context, _ string, to []string, content string, _ []string, _ []string, _ []string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, recipient := range to {
+		s.codes[recipient] = content
+	}
+
+	return "<functional@sso>", nil
+}
+
+// LastCode returns the last code emailed to the address, the test fails if there is none.
+func (s *Suite) LastCode(email string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.codes[email]
+	s.Require().True(ok, "no code emailed to %s", email)
+
+	return code
+}